@@ -0,0 +1,24 @@
+// Package adminui embeds the static web UI for managing products served at
+// /admin-ui. The UI calls the public API with the user's access token.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// TokenCookie holds the access token authenticating page loads of the UI. It is
+// scoped to the UI's path and never accepted by the API itself.
+const TokenCookie = "admin_ui_token"
+
+//go:embed static
+var static embed.FS
+
+// Files returns the UI's files: index.html, login.html and the assets directory
+func Files() fs.FS {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
@@ -0,0 +1,117 @@
+// Lists, creates, updates and deletes the signed-in user's products
+const token = sessionStorage.getItem("access_token");
+const rows = document.getElementById("products");
+const more = document.getElementById("more");
+const error = document.getElementById("error");
+let cursor = "";
+
+function signOut() {
+	sessionStorage.removeItem("access_token");
+	document.cookie = "admin_ui_token=; Path=/admin-ui; Max-Age=0";
+	location.replace("/admin-ui/login");
+}
+
+async function api(method, path, body) {
+	const response = await fetch("/api/v1" + path, {
+		method,
+		headers: { "Authorization": "Bearer " + token, "Content-Type": "application/json" },
+		body: body === undefined ? undefined : JSON.stringify(body),
+	});
+	if (response.status === 401) {
+		signOut();
+	}
+	const data = await response.json();
+	if (!response.ok) {
+		throw new Error(data.detail || data.title);
+	}
+	error.hidden = true;
+	return data;
+}
+
+function showError(err) {
+	error.textContent = err.message;
+	error.hidden = false;
+}
+
+function cell(content) {
+	const td = document.createElement("td");
+	if (content instanceof Node) {
+		td.append(content);
+	} else {
+		td.textContent = content;
+	}
+	return td;
+}
+
+function render(product) {
+	const stock = document.createElement("input");
+	stock.type = "number";
+	stock.min = "0";
+	stock.value = product.stock;
+	stock.addEventListener("change", () => {
+		api("PUT", "/products/" + product.id, { stock: Number(stock.value) }).catch(showError);
+	});
+
+	const remove = document.createElement("button");
+	remove.textContent = "Delete";
+	remove.addEventListener("click", async () => {
+		if (!confirm(`Delete ${product.name}?`)) {
+			return;
+		}
+		try {
+			await api("DELETE", "/products/" + product.id);
+			row.remove();
+		} catch (err) {
+			showError(err);
+		}
+	});
+
+	const row = document.createElement("tr");
+	row.append(
+		cell(product.name),
+		cell(product.price.toFixed(2)),
+		cell(stock),
+		cell(new Date(product.updated_at).toLocaleString()),
+		cell(remove),
+	);
+	return row;
+}
+
+async function load() {
+	try {
+		const query = cursor ? "?cursor=" + encodeURIComponent(cursor) : "";
+		const page = await api("GET", "/products/cursor" + query);
+		rows.append(...page.products.map(render));
+		cursor = page.next_cursor || "";
+		more.hidden = !page.has_next;
+	} catch (err) {
+		showError(err);
+	}
+}
+
+document.getElementById("create").addEventListener("submit", async (event) => {
+	event.preventDefault();
+	const form = new FormData(event.target);
+	try {
+		const product = await api("POST", "/products/", {
+			name: form.get("name"),
+			price: Number(form.get("price")),
+			stock: Number(form.get("stock")),
+		});
+		rows.prepend(render(product));
+		event.target.reset();
+	} catch (err) {
+		showError(err);
+	}
+});
+
+document.getElementById("logout").addEventListener("click", () => {
+	api("POST", "/auth/logout").catch(() => {}).finally(signOut);
+});
+more.addEventListener("click", load);
+
+if (token) {
+	load();
+} else {
+	signOut();
+}
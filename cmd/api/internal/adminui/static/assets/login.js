@@ -0,0 +1,24 @@
+// Signs in through the API, keeping the access token for API calls in session
+// storage and in a cookie authenticating page loads of the UI
+document.getElementById("login").addEventListener("submit", async (event) => {
+	event.preventDefault();
+	const form = new FormData(event.target);
+	const error = document.getElementById("error");
+
+	const response = await fetch("/api/v1/auth/login", {
+		method: "POST",
+		headers: { "Content-Type": "application/json" },
+		body: JSON.stringify({ email: form.get("email"), password: form.get("password") }),
+	});
+	const body = await response.json();
+	if (!response.ok) {
+		error.textContent = body.detail || body.title;
+		error.hidden = false;
+		return;
+	}
+
+	sessionStorage.setItem("access_token", body.access_token);
+	const secure = location.protocol === "https:" ? "; Secure" : "";
+	document.cookie = `admin_ui_token=${body.access_token}; Path=/admin-ui; Max-Age=${body.expires_in}; SameSite=Strict${secure}`;
+	location.replace("/admin-ui/");
+});
@@ -0,0 +1,1096 @@
+package changelog
+
+import (
+	"sort"
+	"time"
+)
+
+// Change types
+const (
+	TypeAdded      = "added"
+	TypeChanged    = "changed"
+	TypeDeprecated = "deprecated"
+	TypeRemoved    = "removed"
+	TypeFixed      = "fixed"
+	TypeSecurity   = "security"
+)
+
+// Entry represents a single API-affecting change
+type Entry struct {
+	Version     string     `json:"version"`
+	Date        time.Time  `json:"date"`
+	Type        string     `json:"type"`
+	Endpoints   []string   `json:"endpoints,omitempty"`
+	Summary     string     `json:"summary"`
+	SunsetDate  *time.Time `json:"sunset_date,omitempty"`
+	Replacement string     `json:"replacement,omitempty"`
+}
+
+// Changelog represents the machine-readable changelog document
+type Changelog struct {
+	CurrentVersion string  `json:"current_version"`
+	Entries        []Entry `json:"entries"`
+}
+
+// CurrentVersion is the version of the API currently being served
+const CurrentVersion = "2.0.0"
+
+// V1SunsetDate is when /api/v1 stops being served
+var V1SunsetDate = date(2027, time.October, 17)
+
+// entries holds every API-affecting change. New entries are appended here
+// alongside the change that introduces them.
+var entries = []Entry{
+	{
+		Version: "1.0.0",
+		Date:    date(2024, time.January, 15),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/auth/register",
+			"POST /api/v1/auth/login",
+			"POST /api/v1/auth/refresh",
+			"POST /api/v1/auth/logout",
+			"POST /api/v1/auth/logout-all",
+			"GET /api/v1/auth/sessions",
+		},
+		Summary: "Initial authentication and session management endpoints",
+	},
+	{
+		Version: "1.0.0",
+		Date:    date(2024, time.January, 15),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products",
+			"GET /api/v1/products",
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v1/products/stats",
+			"GET /api/v1/products/:id",
+			"PUT /api/v1/products/:id",
+			"DELETE /api/v1/products/:id",
+		},
+		Summary: "Initial product CRUD, filtering, cursor pagination and statistics endpoints",
+	},
+	{
+		Version:   "1.1.0",
+		Date:      date(2026, time.October, 17),
+		Type:      TypeAdded,
+		Endpoints: []string{"GET /api/changelog"},
+		Summary:   "Machine-readable API changelog",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/backups",
+			"GET /api/v1/backups",
+			"POST /api/v1/backups/restore",
+		},
+		Summary: "Backup and restore of user data to S3-compatible storage",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"DELETE /api/v1/users/me",
+			"POST /api/v1/users/me/cancel-deletion",
+			"GET /api/v1/users/me/export",
+		},
+		Summary: "GDPR account deletion with grace period and personal data export",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/users/me/export-destination",
+			"PUT /api/v1/users/me/export-destination",
+			"DELETE /api/v1/users/me/export-destination",
+			"GET /api/v1/notifications",
+			"POST /api/v1/notifications/:id/read",
+		},
+		Summary: "Nightly product exports to user-owned S3 buckets with delivery notifications",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v1/products/stats",
+		},
+		Summary: "Optional pgx-based driver for product list and stats queries (DB_REPOSITORY_DRIVER=pgx); responses are unchanged",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs",
+		},
+		Summary: "Audit logging of every API call with retention policy and admin query endpoint",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/attributes",
+			"PUT /api/v1/attributes/:key",
+			"DELETE /api/v1/attributes/:key",
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+		},
+		Summary: "Custom JSONB product attributes with user-defined schemas and attr.<key> filters",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/stats/history",
+		},
+		Summary: "Archived daily statistics snapshots with monthly downsampling",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/favorites",
+			"POST /api/v1/products/:id/favorite",
+			"DELETE /api/v1/products/:id/favorite",
+		},
+		Summary: "Favorites / watchlist",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/users/me/quota",
+		},
+		Summary: "Soft product quotas: 90% usage warning notification, Warning response header, and a grace window before creation is rejected with 403",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/admin/audit-exports",
+			"GET /api/v1/admin/audit-exports/:id",
+			"GET /api/v1/admin/audit-exports/:id/download",
+		},
+		Summary: "Asynchronous CSV/JSON audit trail exports for compliance requests",
+	},
+	{
+		Version: "1.1.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"PUT /api/v1/products/:id/visibility",
+			"PUT /api/v1/users/me/slug",
+			"GET /api/v1/public/products/:id",
+			"GET /api/v1/public/users/:slug/products",
+		},
+		Summary: "Public product catalog with opt-in product visibility, user slugs and rate-limited anonymous access",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/versions",
+			"POST /api/v2/products",
+			"GET /api/v2/products",
+			"GET /api/v2/products/:id",
+			"PUT /api/v2/products/:id",
+			"DELETE /api/v2/products/:id",
+		},
+		Summary: "API v2 with integer-cent money fields; v2 shares services and authentication with v1",
+	},
+	{
+		Version:     "2.0.0",
+		Date:        date(2026, time.October, 17),
+		Type:        TypeDeprecated,
+		Endpoints:   []string{"/api/v1/*"},
+		Summary:     "API v1 is deprecated; v1 responses carry Deprecation, Sunset and Link headers",
+		SunsetDate:  &V1SunsetDate,
+		Replacement: "/api/v2",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/users/me/onboarding",
+			"POST /api/v1/users/me/verify-email",
+			"POST /api/v1/auth/verify-email",
+			"POST /api/v2/auth/verify-email",
+			"GET /api/v1/users/me/preferences",
+			"PUT /api/v1/users/me/preferences",
+		},
+		Summary: "Onboarding state machine with server-driven next-step hints, email verification and user preferences",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/:id/stock-token",
+			"DELETE /api/v1/products/:id/stock-tokens",
+			"GET /api/v1/quick/stock",
+			"POST /api/v1/quick/stock",
+		},
+		Summary: "QR-friendly signed stock tokens allowing scoped, short-lived stock adjustments without login",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Summary: "Error responses are RFC 7807 application/problem+json documents with stable machine-readable codes instead of {error, message} bodies",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeFixed,
+		Endpoints: []string{
+			"/api/v1/products/*",
+			"/api/v2/products/*",
+			"/api/v1/quick/stock",
+		},
+		Summary: "Product endpoints distinguish 404 not found, 403 forbidden, 409 conflict and 500 internal errors instead of reporting every failure as 400 or 404",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/auth/register",
+			"POST /api/v1/auth/login",
+			"POST /api/v1/products",
+			"PUT /api/v1/products/:id",
+			"POST /api/v2/auth/register",
+			"POST /api/v2/auth/login",
+			"POST /api/v2/products",
+			"PUT /api/v2/products/:id",
+		},
+		Summary: "Request validation reports every invalid field at once in the problem errors array; products may now be created with zero stock",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/admin/email-templates",
+			"GET /api/v1/admin/email-templates/:key",
+			"PUT /api/v1/admin/email-templates/:key",
+			"DELETE /api/v1/admin/email-templates/:key",
+			"POST /api/v1/admin/email-templates/:key/preview",
+		},
+		Summary: "Admin management of transactional email templates with variable validation and preview rendering",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/import",
+			"GET /api/v1/products/import-profiles",
+			"POST /api/v1/products/import-profiles",
+			"PUT /api/v1/products/import-profiles/:id",
+			"DELETE /api/v1/products/import-profiles/:id",
+		},
+		Summary: "CSV product import with saved column-to-field mapping profiles and value transforms",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/auth/register",
+			"POST /api/v1/products",
+			"PUT /api/v1/products/:id",
+		},
+		Summary: "Names and descriptions accept Unicode letters, and validation messages are localized from Accept-Language (en, pt, es, de)",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/webhooks",
+			"POST /api/v1/webhooks",
+			"PUT /api/v1/webhooks/:id",
+			"DELETE /api/v1/webhooks/:id",
+			"GET /api/v1/webhooks/:id/deliveries",
+		},
+		Summary: "Webhook subscriptions with event type selection and server-side attribute and price-change filters",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/products",
+			"PUT /api/v1/products/:id",
+			"POST /api/v2/products",
+			"PUT /api/v2/products/:id",
+		},
+		Summary: "Product names, descriptions and attribute values containing SQL keywords are no longer rejected; descriptions accept allowlisted rich-text HTML and other markup is stripped",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"GET /api/v1/products/:id",
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v2/products",
+			"GET /api/v2/products/:id",
+			"GET /api/v1/public/products/:id",
+			"GET /api/v1/public/users/:slug/products",
+		},
+		Summary: "Product descriptions support Markdown; sanitized HTML is stored as description_html and ?format=html returns it as the description",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /health",
+		},
+		Summary: "Read-only maintenance mode (READ_ONLY_MODE) serves GET requests from a replica and rejects changes with 503 READ_ONLY",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v1/products/:id",
+			"POST /api/v1/products",
+			"PUT /api/v1/products/:id",
+		},
+		Summary: "Deprecation warnings: requests that send deprecated query parameters or request fields, or receive deprecated response fields, get a Warning header per deprecation and a meta.deprecations array in JSON object responses",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/products",
+			"POST /api/v2/products",
+		},
+		Summary: "Quota-exceeded 403 responses state the products used, the limit and when the grace period ended",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/quick/stock",
+		},
+		Summary: "Stock conflicts (409) include the current stock and update time in a conflict member of the problem body",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products",
+		},
+		Summary: "The plain product list is paginated by default (page, page_size up to 100) and returns the paginated list object",
+	},
+	{
+		Version:     "2.0.0",
+		Date:        date(2026, time.October, 17),
+		Type:        TypeDeprecated,
+		Endpoints:   []string{"GET /api/v1/products?all=true"},
+		Summary:     "Unbounded product listing with ?all=true is deprecated",
+		SunsetDate:  &V1SunsetDate,
+		Replacement: "GET /api/v1/products?page=1&page_size=100",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeFixed,
+		Endpoints: []string{
+			"GET /api/v1/products/cursor",
+		},
+		Summary: "Cursor pagination follows the requested sort field and direction; cursors are opaque keyset tokens and product ID cursors are no longer accepted",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/auth/sessions",
+		},
+		Summary: "Sessions record their last activity; the session list returns active sessions with last_active_at",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs",
+			"POST /api/v1/admin/audit-exports",
+		},
+		Summary: "Audit logs and exports include the sample_rate each entry was recorded at; categories can be sampled with per-user overrides",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"GET /api/v1/products/filtered",
+			"GET /api/v2/products",
+		},
+		Summary: "include_total=false skips the total count of product lists and include_total=estimate returns a planner-statistics total_estimate; total and total_pages are omitted when not counted",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/bulk",
+			"PUT /api/v1/products/bulk",
+			"DELETE /api/v1/products/bulk",
+		},
+		Summary: "Bulk create, update and delete of up to 100 products, answering 207 Multi-Status with a per-item status and error code when some items fail.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/products/import",
+		},
+		Summary: "CSV imports return the shared bulk result (succeeded, failed and per-item status, id and error code) instead of created/failed/errors, answering 201, or 207 when some rows fail.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/stock-templates",
+			"PUT /api/v1/stock-templates/:category",
+			"DELETE /api/v1/stock-templates/:category",
+			"GET /api/v1/products/:id/stock-levels",
+			"GET /api/v1/products/low-stock",
+			"POST /api/v1/products",
+			"PUT /api/v1/products/:id",
+		},
+		Summary: "Category stock templates set the default low-stock threshold and reorder quantity of products whose category attribute matches; products can override both with low_stock_threshold and reorder_quantity.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products/stats",
+			"GET /api/v1/products/stats/history",
+		},
+		Summary: "The low_stock statistic counts products below their effective low-stock threshold (product override, category template or preference) instead of a fixed 10.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/sync/devices",
+			"POST /api/v1/sync/devices",
+			"DELETE /api/v1/sync/devices/:id",
+			"GET /api/v1/sync/devices/:id/changes",
+		},
+		Summary: "Offline sync: per-device registrations and a changes API with monotonically increasing sync tokens, tombstones and server version/updated_at for conflict detection.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products/:id",
+		},
+		Summary: "Products carry a version field that is incremented on every update.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /ready",
+		},
+		Summary: "Readiness probe reporting the circuit breaker state of the database, Redis and replica; answers 503 while a required dependency is unavailable.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"PUT /api/v1/users/me/preferences",
+			"GET /api/v1/products/:id",
+			"GET /api/v1/products/filtered",
+			"GET /api/v2/products/:id",
+		},
+		Summary: "Products can have short, human-readable codes, enabled by the product_codes preference, that are accepted in place of product IDs and by the code filter.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Summary: "Requests that exceed the server's request timeout are answered with 504 and the TIMEOUT error code instead of hanging.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /readyz",
+			"GET /ready",
+		},
+		Summary: "Readiness checks, also served at /readyz, report draining with 503 while an instance shuts down.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Summary: "Responses include a Server-Timing header with the database, cache and app time of the request.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/events",
+			"GET /api/v1/admin/events",
+		},
+		Summary: "Product changes and registrations are recorded in an append-only event log that can be queried by type, subject and time, and that webhooks are published from.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /.well-known/jwks.json",
+		},
+		Summary: "Tokens name their signing key in a kid header; signing keys can be rotated through JWT_KEYS, optionally with RS256 keys whose public keys are published as a JWK set.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Summary: "The server can terminate TLS with a certificate or Let's Encrypt certificates, serving HTTP/2, redirect HTTP to HTTPS, and bounds header reads and idle connections.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Summary: "Request bodies are limited per route, answering 413 with code PAYLOAD_TOO_LARGE, and JSON bodies nested more than 32 levels deep are rejected with 400.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/auth/login",
+			"POST /api/v2/auth/login",
+		},
+		Summary: "Logins from an address with repeated failures require a CAPTCHA token when a CAPTCHA provider is configured; without one they are answered with 401 and code CAPTCHA_REQUIRED.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/users/me/devices",
+			"PUT /api/v1/users/me/devices/:id",
+			"DELETE /api/v1/users/me/devices/:id",
+			"POST /api/v1/auth/login",
+		},
+		Summary: "Logins record the device they come from; users can name, trust and forget devices, are emailed about new ones, and with NEW_DEVICE_VERIFICATION confirm logins from untrusted devices with an emailed code.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/auth/login-history",
+		},
+		Summary: "Login history with IP geolocation, flagging logins from new countries and alerting users by email and the user.login_anomaly webhook event.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/users/me/usage",
+			"GET /api/v1/admin/users/:id/usage",
+		},
+		Summary: "Per-user API usage statistics: daily request counts and bandwidth, counted in Redis and rolled up to Postgres.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/plans",
+			"PUT /api/v1/admin/users/:id/plan",
+		},
+		Summary: "Subscription plans (free, pro, enterprise) setting each user's product quota, request rate limit and webhook limit; admins change a user's plan.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Summary: "The product quota, webhook subscription limit and authenticated rate limit now follow the user's plan; PRODUCT_QUOTA sets the free plan's quota.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/billing/checkout",
+			"GET /api/v1/billing/portal-link",
+			"GET /api/v1/billing/invoices",
+			"POST /api/v1/billing/webhook",
+		},
+		Summary: "Paid plans through Stripe Checkout: checkout and customer portal links, stored invoices, and a Stripe webhook that moves users between plans as their subscriptions change.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/orders",
+			"GET /api/v1/orders",
+			"GET /api/v1/orders/:id",
+			"POST /api/v1/orders/:id/cancel",
+		},
+		Summary: "Orders of products that take their quantities from stock atomically and put them back when cancelled.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/:id/reservations",
+			"GET /api/v1/products/:id/reservations/:reservation_id",
+			"POST /api/v1/products/:id/reservations/:reservation_id/confirm",
+			"DELETE /api/v1/products/:id/reservations/:reservation_id",
+		},
+		Summary: "Expiring stock reservations, with reserved_stock and available_stock in product responses.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/orders",
+			"POST /api/v1/quick/stock",
+		},
+		Summary: "Orders and stock adjustments can no longer take reserved units; stock conflicts report available_stock.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/:id/stock/decrement",
+		},
+		Summary: "Atomic stock decrement that fails with OUT_OF_STOCK instead of losing concurrent updates.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/orders",
+			"POST /api/v1/products/:id/reservations",
+		},
+		Summary: "Orders and reservations short of stock fail with the OUT_OF_STOCK code.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/suppliers",
+			"POST /api/v1/suppliers",
+			"GET /api/v1/suppliers/:id",
+			"PUT /api/v1/suppliers/:id",
+			"DELETE /api/v1/suppliers/:id",
+			"GET /api/v1/purchase-orders",
+			"POST /api/v1/purchase-orders",
+			"GET /api/v1/purchase-orders/:id",
+			"POST /api/v1/purchase-orders/:id/receive",
+			"POST /api/v1/purchase-orders/:id/cancel",
+		},
+		Summary: "Suppliers and purchase orders; receiving an order adds its quantities to stock and publishes stock.adjusted events.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/locations",
+			"POST /api/v1/locations",
+			"GET /api/v1/locations/:id",
+			"PUT /api/v1/locations/:id",
+			"DELETE /api/v1/locations/:id",
+			"GET /api/v1/locations/:id/stats",
+			"GET /api/v1/products/:id/locations",
+			"POST /api/v1/products/:id/locations/:location_id/stock",
+		},
+		Summary: "Locations with per-location product stock; a product's stock is the total across locations, and stock can be adjusted at a location.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v2/products",
+			"GET /api/v1/products/low-stock",
+			"POST /api/v1/quick/stock",
+		},
+		Summary: "Product lists and the low-stock list accept a location_id filter, and quick stock updates accept a location_id to adjust the stock kept at a location.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/:id/archive",
+			"POST /api/v1/products/:id/unarchive",
+		},
+		Summary: "Products can be archived and unarchived; archived products are kept but left out of lists, statistics, low-stock lists and the public catalog.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v2/products",
+		},
+		Summary: "Product lists accept status=archived to list archived products; by default they leave archived products out.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/drafts",
+			"POST /api/v1/products/:id/publish",
+			"POST /api/v1/products/:id/unpublish",
+		},
+		Summary: "Products can be saved as drafts and published once they have a price and their required attributes; drafts stay out of the public catalog.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"GET /api/v2/products",
+		},
+		Summary: "The status list filter also accepts draft and published.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/:id/related",
+		},
+		Summary: "Products can list related products ranked by shared attribute values and price proximity.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/products/:id/reviews",
+			"GET /api/v1/products/:id/reviews",
+			"PUT /api/v1/products/:id/reviews/:review_id/status",
+			"GET /api/v1/public/products/:id/reviews",
+		},
+		Summary: "Public products can be reviewed and rated from 1 to 5 once per user, owners moderate reviews, and products carry their rating_average and rating_count.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/:id/notes",
+			"POST /api/v1/products/:id/notes",
+			"PUT /api/v1/products/:id/notes/:note_id",
+			"DELETE /api/v1/products/:id/notes/:note_id",
+		},
+		Summary: "Products have an internal notes thread with email mentions that notify the mentioned users.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/saved-searches",
+			"POST /api/v1/products/saved-searches",
+			"GET /api/v1/products/saved-searches/:search_id",
+			"PUT /api/v1/products/saved-searches/:search_id",
+			"DELETE /api/v1/products/saved-searches/:search_id",
+			"PUT /api/v1/products/saved-searches/:search_id/subscription",
+			"GET /api/v1/products/saved-searches/:search_id/results",
+		},
+		Summary: "Product queries can be saved under a name, run by ID and subscribed to for notifications of new matching products.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/users/me/settings",
+			"PUT /api/v1/users/me/settings",
+		},
+		Summary: "Users can export and import all their settings, including a default page size for lists and per-type notification preferences.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/stats/timeseries",
+		},
+		Summary: "Product statistics are available as day or week time series of product counts or stock value.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products/stats",
+		},
+		Summary: "Product stats have a fixed shape and add the median price and stock value percentiles.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /debug/pprof/*name",
+		},
+		Summary: "Load-testing aids: DEBUG_FAULTS injects latency and INJECTED_FAULT errors per route, and DEBUG_PPROF serves runtime profiles to admins.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /debug/vars",
+		},
+		Summary: "Every response carries an X-Request-ID header, and problem responses return it in request_id so failures can be reported; panics are answered with a 500 problem response.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/auth/login",
+			"POST /api/v2/auth/login",
+		},
+		Summary: "Optional concurrent session limit per user: logins beyond it log out the oldest sessions, listed in revoked_sessions, or are rejected with SESSION_LIMIT_REACHED.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/auth/login",
+			"POST /api/v2/auth/login",
+			"GET /api/v1/auth/sessions",
+		},
+		Summary: "Logins accept remember_me to keep the session and refresh token for 30 days instead of 24 hours; sessions report remember_me.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"POST /api/v1/auth/register",
+			"POST /api/v1/auth/login",
+		},
+		Summary: "Emails are case-insensitive: they are trimmed and lowercased at registration and login.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"POST /api/v1/users/me/avatar",
+			"GET /api/v1/public/avatars/:id",
+		},
+		Summary: "Users can upload an avatar, served publicly as PNG; users carry its avatar_url.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/events",
+		},
+		Summary: "GET /api/v1/events streams events and notifications as server-sent events when requested with Accept: text/event-stream, resuming from Last-Event-ID.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/ws",
+		},
+		Summary: "Live inventory dashboards receive stock and price changes over a WebSocket, optionally for specific products.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"GET /api/v1/products/filtered",
+		},
+		Summary: "Product lists stream every matching product as NDJSON when requested with Accept: application/x-ndjson.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/changes",
+		},
+		Summary: "Products created, updated and deleted after a timestamp can be listed by ID for incremental offline sync.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/suggest",
+		},
+		Summary: "Product names completing a query can be suggested for search-as-you-type.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"POST /api/v1/products/batch-get",
+		},
+		Summary: "Several products can be fetched by ID in one request; IDs of products that do not exist or cannot be viewed are listed as missing.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/sort-fields",
+		},
+		Summary: "List the fields products can be sorted by.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v2/products",
+		},
+		Summary: "Product lists accept repeated sort=field:direction:nulls parameters, sort by the computed stock_value and nullable code, and reject unknown sort fields.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/filter-options",
+		},
+		Summary: "List the stock statuses and price buckets product lists can be filtered by.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeChanged,
+		Endpoints: []string{
+			"GET /api/v1/products",
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+			"GET /api/v2/products",
+		},
+		Summary: "Product lists accept stock_status=in_stock|low|out and price_bucket=budget|mid|premium filters.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /api/v1/products/:id",
+			"GET /api/v1/products",
+			"GET /api/v1/products/filtered",
+			"GET /api/v1/products/cursor",
+		},
+		Summary: "?price_format=locale adds prices formatted for the user's currency and locale, and lists describe the currency and its decimal places.",
+	},
+	{
+		Version: "2.0.0",
+		Date:    date(2026, time.October, 17),
+		Type:    TypeAdded,
+		Endpoints: []string{
+			"GET /status",
+		},
+		Summary: "Public status page data: rolling uptime, average latency and dependency health, cached for 30 seconds.",
+	},
+}
+
+// Get returns the changelog with entries ordered from newest to oldest
+func Get() Changelog {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.After(sorted[j].Date)
+	})
+
+	return Changelog{
+		CurrentVersion: CurrentVersion,
+		Entries:        sorted,
+	}
+}
+
+// Deprecations returns only the entries that announce a deprecation or sunset
+func Deprecations() []Entry {
+	result := []Entry{}
+	for _, entry := range Get().Entries {
+		if entry.Type == TypeDeprecated || entry.SunsetDate != nil {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// date builds a UTC date without a time component
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
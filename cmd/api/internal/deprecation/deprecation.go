@@ -0,0 +1,108 @@
+package deprecation
+
+import (
+	"strings"
+	"time"
+
+	"products/cmd/api/internal/changelog"
+)
+
+// Kinds of deprecated API elements
+const (
+	KindQueryParameter = "query_parameter"
+	KindRequestField   = "request_field"
+	KindResponseField  = "response_field"
+)
+
+// Deprecation describes a deprecated query parameter, request field or response field
+type Deprecation struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"`
+	Name        string     `json:"name"`
+	Endpoints   []string   `json:"-"`
+	Message     string     `json:"message"`
+	Replacement string     `json:"replacement,omitempty"`
+	SunsetDate  *time.Time `json:"sunset_date,omitempty"`
+}
+
+// v1ProductEndpoints are the v1 routes that accept or return products
+var v1ProductEndpoints = []string{
+	"POST /api/v1/products",
+	"GET /api/v1/products",
+	"GET /api/v1/products/filtered",
+	"GET /api/v1/products/cursor",
+	"GET /api/v1/products/:id",
+	"PUT /api/v1/products/:id",
+}
+
+// registry holds every deprecated API element. New entries are added here
+// alongside the changelog entry announcing the deprecation.
+var registry = []Deprecation{
+	{
+		ID:          "v1-product-price-response",
+		Kind:        KindResponseField,
+		Name:        "price",
+		Endpoints:   v1ProductEndpoints,
+		Message:     "The price field is deprecated; use price_cents from /api/v2/products",
+		Replacement: "price_cents",
+		SunsetDate:  &changelog.V1SunsetDate,
+	},
+	{
+		ID:          "v1-product-price-request",
+		Kind:        KindRequestField,
+		Name:        "price",
+		Endpoints:   []string{"POST /api/v1/products", "PUT /api/v1/products/:id"},
+		Message:     "The price field is deprecated; send price_cents to /api/v2/products",
+		Replacement: "price_cents",
+		SunsetDate:  &changelog.V1SunsetDate,
+	},
+	{
+		ID:          "v1-product-list-all-parameter",
+		Kind:        KindQueryParameter,
+		Name:        "all",
+		Endpoints:   []string{"GET /api/v1/products"},
+		Message:     "The all parameter is deprecated; page through the list with page and page_size",
+		Replacement: "page, page_size",
+		SunsetDate:  &changelog.V1SunsetDate,
+	},
+	{
+		ID:          "v1-min-price-parameter",
+		Kind:        KindQueryParameter,
+		Name:        "min_price",
+		Endpoints:   []string{"GET /api/v1/products/filtered", "GET /api/v1/products/cursor"},
+		Message:     "The min_price parameter is deprecated; use min_price_cents on GET /api/v2/products",
+		Replacement: "min_price_cents",
+		SunsetDate:  &changelog.V1SunsetDate,
+	},
+	{
+		ID:          "v1-max-price-parameter",
+		Kind:        KindQueryParameter,
+		Name:        "max_price",
+		Endpoints:   []string{"GET /api/v1/products/filtered", "GET /api/v1/products/cursor"},
+		Message:     "The max_price parameter is deprecated; use max_price_cents on GET /api/v2/products",
+		Replacement: "max_price_cents",
+		SunsetDate:  &changelog.V1SunsetDate,
+	},
+}
+
+// All returns every registered deprecation
+func All() []Deprecation {
+	return registry
+}
+
+// ForEndpoint returns the deprecations registered for a route. The route is the
+// pattern the request matched, e.g. "/api/v1/products/:id".
+func ForEndpoint(method, route string) []Deprecation {
+	endpoint := method + " " + strings.TrimSuffix(route, "/")
+
+	var result []Deprecation
+	for _, entry := range registry {
+		for _, candidate := range entry.Endpoints {
+			if candidate == endpoint {
+				result = append(result, entry)
+				break
+			}
+		}
+	}
+	return result
+}
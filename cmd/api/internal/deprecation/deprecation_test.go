@@ -0,0 +1,38 @@
+package deprecation
+
+import (
+	"testing"
+)
+
+func TestForEndpoint(t *testing.T) {
+	entries := ForEndpoint("GET", "/api/v1/products/cursor")
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 deprecations, got %d", len(entries))
+	}
+
+	if entries := ForEndpoint("POST", "/api/v1/products/"); len(entries) != 2 {
+		t.Errorf("Expected the trailing slash to be ignored, got %d deprecations", len(entries))
+	}
+	if entries := ForEndpoint("GET", "/api/v2/products/"); len(entries) != 0 {
+		t.Errorf("Expected no deprecations for v2, got %d", len(entries))
+	}
+}
+
+func TestRegistry_IsComplete(t *testing.T) {
+	seen := map[string]bool{}
+	for _, entry := range All() {
+		if entry.ID == "" || entry.Name == "" || entry.Message == "" || len(entry.Endpoints) == 0 {
+			t.Errorf("Incomplete deprecation %+v", entry)
+		}
+		if seen[entry.ID] {
+			t.Errorf("Duplicate deprecation ID %q", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		switch entry.Kind {
+		case KindQueryParameter, KindRequestField, KindResponseField:
+		default:
+			t.Errorf("Deprecation %q has unknown kind %q", entry.ID, entry.Kind)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"products/internal/redact"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccessLogConfig configures AccessLogMiddleware
+type AccessLogConfig struct {
+	// Logger writes the entries; a nil Logger disables the access log
+	Logger *slog.Logger
+
+	// Bodies adds request bodies, redacted and truncated to MaxBodyBytes, to entries
+	Bodies       bool
+	MaxBodyBytes int
+
+	// SampleRates are the fractions of requests logged per route; the first
+	// matching rate applies and other routes are always logged
+	SampleRates []RouteSampleRate
+
+	// SlowThreshold is the latency from which requests are logged regardless of
+	// sampling, like server errors; 0 disables it
+	SlowThreshold time.Duration
+}
+
+// RouteSampleRate is the fraction of the requests of matching routes logged
+type RouteSampleRate struct {
+	// Method is the HTTP method matched, or empty for every method
+	Method string
+
+	// Route is the route pattern matched, see FaultRule.Route
+	Route string
+
+	Rate float64
+}
+
+// DefaultAccessLogConfig returns the default access log configuration, without a logger
+func DefaultAccessLogConfig() AccessLogConfig {
+	return AccessLogConfig{MaxBodyBytes: 2048, SlowThreshold: time.Second}
+}
+
+// ParseAccessLogSampleRates parses semicolon-separated rates of the form
+// "[METHOD ]route=rate", e.g. "GET /api/v1/products/filtered=0.01;/api/v1/products/*=0.1"
+func ParseAccessLogSampleRates(value string) ([]RouteSampleRate, error) {
+	var rates []RouteSampleRate
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		target, rawRate, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected route=rate, got %q", entry)
+		}
+		method, route, err := parseRouteTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rawRate), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("rate for %q must be between 0 and 1", route)
+		}
+		rates = append(rates, RouteSampleRate{Method: method, Route: route, Rate: rate})
+	}
+	return rates, nil
+}
+
+// sampleRate returns the fraction of the requests for route that are logged
+func (config AccessLogConfig) sampleRate(method, route string) float64 {
+	for _, rate := range config.SampleRates {
+		if routeMatches(rate.Method, rate.Route, method, route) {
+			return rate.Rate
+		}
+	}
+	return 1
+}
+
+// AccessLogMiddleware logs one structured entry per request with its route,
+// status, latency, sizes and user. Passwords, tokens and email addresses are
+// redacted from the logged path, query and body. Server errors and slow requests
+// are always logged; other requests are sampled at the rate of their route, which
+// entries of sampled routes carry as sample_rate.
+func AccessLogMiddleware(config AccessLogConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		body := ""
+		if config.Bodies {
+			body = captureRequestBody(c)
+		}
+
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		route := c.FullPath()
+		rate := config.sampleRate(c.Request.Method, route)
+		important := status >= 500 || (config.SlowThreshold > 0 && latency >= config.SlowThreshold)
+		if !important && rate < 1 && rand.Float64() >= rate {
+			return
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.String("path", redact.Emails(c.Request.URL.Path)),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+			slog.Int64("bytes_in", max(c.Request.ContentLength, 0)),
+			slog.Int("bytes_out", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if requestID := c.GetString("request_id"); requestID != "" {
+			attrs = append(attrs, slog.String("request_id", requestID))
+		}
+		if query := c.Request.URL.RawQuery; query != "" {
+			attrs = append(attrs, slog.String("query", redact.Query(query)))
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(uuid.UUID); ok {
+				attrs = append(attrs, slog.String("user_id", id.String()))
+			}
+		}
+		if body != "" {
+			body = redact.JSON(body, true)
+			if config.MaxBodyBytes > 0 && len(body) > config.MaxBodyBytes {
+				body = body[:config.MaxBodyBytes] + "...(truncated)"
+			}
+			attrs = append(attrs, slog.String("body", body))
+		}
+		if rate < 1 {
+			attrs = append(attrs, slog.Float64("sample_rate", rate))
+		}
+
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		config.Logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
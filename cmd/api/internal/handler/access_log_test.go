@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// accessLogRouter returns a router logging to the returned buffer
+func accessLogRouter(config AccessLogConfig) (*gin.Engine, *bytes.Buffer) {
+	gin.SetMode(gin.TestMode)
+	var output bytes.Buffer
+	config.Logger = slog.New(slog.NewJSONHandler(&output, nil))
+
+	router := gin.New()
+	router.Use(AccessLogMiddleware(config))
+	return router, &output
+}
+
+// accessLogEntries decodes the logged entries
+func accessLogEntries(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	config := DefaultAccessLogConfig()
+	config.Bodies = true
+	router, output := accessLogRouter(config)
+
+	userID := uuid.New()
+	router.POST("/users/:email/login", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.String(http.StatusOK, "welcome")
+	})
+
+	body := `{"email":"jane@example.com","password":"hunter2"}`
+	request := httptest.NewRequest(http.MethodPost, "/users/jane@example.com/login?token=abc&next=%2Fhome", strings.NewReader(body))
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	entries := accessLogEntries(t, output)
+	if len(entries) != 1 {
+		t.Fatalf("Expected one entry, got %d", len(entries))
+	}
+	entry := entries[0]
+
+	want := map[string]interface{}{
+		"msg":       "request",
+		"method":    "POST",
+		"route":     "/users/:email/login",
+		"path":      "/users/j***@example.com/login",
+		"query":     "next=%2Fhome&token=%5BREDACTED%5D",
+		"status":    float64(http.StatusOK),
+		"bytes_in":  float64(len(body)),
+		"bytes_out": float64(len("welcome")),
+		"user_id":   userID.String(),
+		"body":      `{"email":"j***@example.com","password":"[REDACTED]"}`,
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("Expected %s %v, got %v", key, value, entry[key])
+		}
+	}
+	if strings.Contains(output.String(), "hunter2") || strings.Contains(output.String(), "jane@") {
+		t.Errorf("Expected secrets and emails redacted, got %s", output.String())
+	}
+}
+
+func TestAccessLogMiddleware_Sampling(t *testing.T) {
+	config := DefaultAccessLogConfig()
+	config.SampleRates = []RouteSampleRate{{Method: http.MethodGet, Route: "/products*", Rate: 0}}
+	router, output := accessLogRouter(config)
+
+	router.GET("/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/products/broken", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	router.GET("/orders", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/products", "/products/broken", "/orders"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := accessLogEntries(t, output)
+	if len(entries) != 2 {
+		t.Fatalf("Expected the server error and the unsampled route logged, got %v", entries)
+	}
+	if entries[0]["route"] != "/products/broken" || entries[0]["level"] != "ERROR" || entries[0]["sample_rate"] != float64(0) {
+		t.Errorf("Unexpected server error entry %v", entries[0])
+	}
+	if entries[1]["route"] != "/orders" || entries[1]["sample_rate"] != nil {
+		t.Errorf("Unexpected entry %v", entries[1])
+	}
+}
+
+func TestParseAccessLogSampleRates(t *testing.T) {
+	rates, err := ParseAccessLogSampleRates("GET /api/v1/products/filtered=0.01; /api/v1/*=0.5")
+	if err != nil {
+		t.Fatalf("ParseAccessLogSampleRates: %v", err)
+	}
+	want := []RouteSampleRate{
+		{Method: http.MethodGet, Route: "/api/v1/products/filtered", Rate: 0.01},
+		{Route: "/api/v1/*", Rate: 0.5},
+	}
+	if len(rates) != len(want) || rates[0] != want[0] || rates[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, rates)
+	}
+
+	for _, invalid := range []string{"/products", "=0.5", "/products=2", "/products=often"} {
+		if _, err := ParseAccessLogSampleRates(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
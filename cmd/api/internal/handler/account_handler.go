@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccountHandler handles account deletion and personal data export requests
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// Delete schedules deletion of the authenticated user's account
+func (h *AccountHandler) Delete(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	response, err := h.accountService.ScheduleDeletion(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// CancelDeletion cancels a pending deletion of the authenticated user's account
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.accountService.CancelDeletion(c.Request.Context(), userID); err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deletion cancelled"})
+}
+
+// Export returns an archive of all personal data stored for the authenticated user
+func (h *AccountHandler) Export(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	export, err := h.accountService.ExportData(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to export account data")
+		return
+	}
+
+	filename := fmt.Sprintf("account-export-%s.json", export.ExportedAt.Format("20060102T150405Z"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.JSON(http.StatusOK, export)
+}
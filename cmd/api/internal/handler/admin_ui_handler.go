@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"products/cmd/api/internal/adminui"
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminUIPath is where the embedded product management UI is served
+const AdminUIPath = "/admin-ui"
+
+// AdminUIHandler serves the embedded product management UI. The login page and
+// assets are public; every other path is a page of the single-page app, served
+// as index.html to authenticated users and redirected to the login page otherwise.
+type AdminUIHandler struct {
+	files fs.FS
+	auth  gin.HandlerFunc
+}
+
+// NewAdminUIHandler creates a new admin UI handler authenticating pages with auth,
+// which is AuthMiddleware
+func NewAdminUIHandler(auth gin.HandlerFunc) *AdminUIHandler {
+	return &AdminUIHandler{
+		files: adminui.Files(),
+		auth:  auth,
+	}
+}
+
+// Serve handles every path under AdminUIPath
+func (h *AdminUIHandler) Serve(c *gin.Context) {
+	name := strings.TrimPrefix(path.Clean(c.Param("path")), "/")
+
+	switch {
+	case name == "login":
+		h.file(c, "login.html")
+	case strings.HasPrefix(name, "assets/"):
+		h.file(c, name)
+	case h.authenticate(c):
+		h.file(c, "index.html")
+	}
+}
+
+// authenticate runs AuthMiddleware with the access token of the UI's cookie,
+// redirecting to the login page when it fails
+func (h *AdminUIHandler) authenticate(c *gin.Context) bool {
+	if c.GetHeader("Authorization") == "" {
+		if token, err := c.Cookie(adminui.TokenCookie); err == nil && token != "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	h.auth(c)
+	if c.IsAborted() {
+		c.Errors = c.Errors[:0]
+		c.Redirect(http.StatusFound, AdminUIPath+"/login")
+		return false
+	}
+	return true
+}
+
+// file writes one of the UI's files; the files change with every release, so
+// they are revalidated rather than cached
+func (h *AdminUIHandler) file(c *gin.Context, name string) {
+	data, err := fs.ReadFile(h.files, name)
+	if err != nil {
+		respondProblem(c, http.StatusNotFound, domain.CodeNotFound, "The requested resource does not exist")
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Frame-Options", "DENY")
+	c.Data(http.StatusOK, contentType, data)
+}
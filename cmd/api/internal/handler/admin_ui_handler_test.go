@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"products/cmd/api/internal/adminui"
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminUIHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// auth accepts the token "valid" like AuthMiddleware accepts a valid access token
+	auth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer valid" {
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Invalid or expired token")
+			return
+		}
+		c.Next()
+	}
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.GET(AdminUIPath+"/*path", NewAdminUIHandler(auth).Serve)
+
+	tests := []struct {
+		name        string
+		path        string
+		cookie      string
+		status      int
+		contentType string
+		location    string
+	}{
+		{"login page is public", "/admin-ui/login", "", http.StatusOK, "text/html", ""},
+		{"assets are public", "/admin-ui/assets/app.js", "", http.StatusOK, "javascript", ""},
+		{"missing asset", "/admin-ui/assets/missing.js", "", http.StatusNotFound, problemContentType, ""},
+		{"pages redirect to login", "/admin-ui/", "", http.StatusFound, "", "/admin-ui/login"},
+		{"expired cookie redirects to login", "/admin-ui/products", "expired", http.StatusFound, "", "/admin-ui/login"},
+		{"pages fall back to index.html", "/admin-ui/products/42", "valid", http.StatusOK, "text/html", ""},
+		{"paths cannot escape the UI", "/admin-ui/../../adminui.go", "valid", http.StatusOK, "text/html", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: adminui.TokenCookie, Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Header().Get("Content-Type"), tt.contentType) {
+				t.Errorf("Expected content type %q, got %q", tt.contentType, w.Header().Get("Content-Type"))
+			}
+			if w.Header().Get("Location") != tt.location {
+				t.Errorf("Expected location %q, got %q", tt.location, w.Header().Get("Location"))
+			}
+		})
+	}
+}
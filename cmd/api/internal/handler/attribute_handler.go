@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AttributeHandler handles user-defined product attribute schemas
+type AttributeHandler struct {
+	attributeService *service.AttributeService
+}
+
+// NewAttributeHandler creates a new attribute handler
+func NewAttributeHandler(attributeService *service.AttributeService) *AttributeHandler {
+	return &AttributeHandler{
+		attributeService: attributeService,
+	}
+}
+
+// List returns the authenticated user's attribute definitions
+func (h *AttributeHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	definitions, err := h.attributeService.List(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve attribute definitions")
+		return
+	}
+
+	c.JSON(http.StatusOK, definitions)
+}
+
+// Define creates or replaces an attribute definition
+func (h *AttributeHandler) Define(c *gin.Context) {
+	var req domain.AttributeDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	definition, err := h.attributeService.Define(c.Request.Context(), userID, c.Param("key"), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeValidationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// Delete removes an attribute definition
+func (h *AttributeHandler) Delete(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.attributeService.Delete(c.Request.Context(), userID, c.Param("key")); err != nil {
+		respondError(c, http.StatusNotFound, domain.CodeNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attribute definition deleted successfully"})
+}
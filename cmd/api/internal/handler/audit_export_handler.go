@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuditExportHandler handles compliance exports of audit trails for administrators
+type AuditExportHandler struct {
+	auditExportService *service.AuditExportService
+}
+
+// NewAuditExportHandler creates a new audit export handler
+func NewAuditExportHandler(auditExportService *service.AuditExportService) *AuditExportHandler {
+	return &AuditExportHandler{
+		auditExportService: auditExportService,
+	}
+}
+
+// Create queues an export of a user's audit trail
+func (h *AuditExportHandler) Create(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	var req domain.AuditExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	export, err := h.auditExportService.Request(c.Request.Context(), adminID, &req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+// Get returns the status of an audit export
+func (h *AuditExportHandler) Get(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	export, err := h.auditExportService.Get(c.Request.Context(), adminID, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, domain.CodeNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// Download returns the generated file of a completed audit export
+func (h *AuditExportHandler) Download(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	export, err := h.auditExportService.Download(c.Request.Context(), adminID, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, domain.CodeNotFound, err)
+		return
+	}
+
+	contentType := "text/csv"
+	if export.Format == domain.AuditExportFormatJSON {
+		contentType = "application/json"
+	}
+
+	// Keep the exported trail out of the audit log itself
+	c.Set(auditSkipResponseBody, true)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"audit-%s-%s.%s\"", export.UserID, export.ID, export.Format))
+	c.Data(http.StatusOK, contentType, export.Content)
+}
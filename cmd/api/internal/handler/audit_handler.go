@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles audit log queries for administrators
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List returns recorded API calls matching the query filters
+func (h *AuditHandler) List(c *gin.Context) {
+	filter := domain.AuditLogFilter{}
+	pagination := domain.Pagination{
+		Page:     1,
+		PageSize: 50,
+	}
+
+	// Parse pagination
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			pagination.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			pagination.PageSize = pageSize
+		}
+	}
+
+	// Parse filters
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := validateUUID(userIDStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "user_id: " + err.Error())
+			return
+		}
+		userIDValue := userID.String()
+		filter.UserID = &userIDValue
+	}
+
+	if route := c.Query("route"); route != "" {
+		filter.Route = &route
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		if status, err := strconv.Atoi(statusStr); err == nil {
+			filter.Status = &status
+		}
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err := time.Parse(time.RFC3339, fromStr); err == nil {
+			filter.From = &from
+		}
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err := time.Parse(time.RFC3339, toStr); err == nil {
+			filter.To = &to
+		}
+	}
+
+	response, err := h.auditService.Query(c.Request.Context(), filter, pagination)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve audit logs")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
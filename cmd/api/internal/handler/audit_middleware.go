@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// auditCaptureLimit bounds how much of a body is buffered for redaction before truncation
+const auditCaptureLimit = 64 * 1024
+
+// auditBodyOmitted replaces bodies too large to be safely redacted
+const auditBodyOmitted = "(omitted: body too large)"
+
+// auditSkipResponseBody is set by handlers whose responses must not be copied into the audit trail
+const auditSkipResponseBody = "audit_skip_response_body"
+
+// auditResponseWriter tees the response body into a bounded buffer
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditResponseWriter) capture(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > auditCaptureLimit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// captureRequestBody returns the request body, leaving it to be read again by the
+// handler. Bodies over auditCaptureLimit are replaced with auditBodyOmitted.
+func captureRequestBody(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	captured, err := io.ReadAll(io.LimitReader(c.Request.Body, auditCaptureLimit+1))
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(captured), c.Request.Body), c.Request.Body}
+
+	if len(captured) > auditCaptureLimit {
+		return auditBodyOmitted
+	}
+	return string(captured)
+}
+
+// auditedResponseBody returns the captured response body to record, empty when the
+// handler set auditSkipResponseBody
+func auditedResponseBody(c *gin.Context, writer *auditResponseWriter) string {
+	if c.GetBool(auditSkipResponseBody) {
+		return ""
+	}
+	if writer.overflow {
+		return auditBodyOmitted
+	}
+	return writer.body.String()
+}
+
+// AuditMiddleware records every API call to the audit store
+func AuditMiddleware(auditService *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestBody := captureRequestBody(c)
+
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		entry := domain.AuditLog{
+			ID:          uuid.New(),
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Path:        c.Request.URL.Path,
+			Status:      c.Writer.Status(),
+			LatencyMs:   time.Since(start).Milliseconds(),
+			ClientIP:    c.ClientIP(),
+			RequestBody: requestBody,
+			CreatedAt:   start,
+		}
+
+		entry.ResponseBody = auditedResponseBody(c, writer)
+
+		if userID, ok := c.Get("user_id"); ok {
+			if id, ok := userID.(uuid.UUID); ok {
+				entry.UserID = &id
+			}
+		}
+
+		auditService.Record(entry)
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AvatarHandler handles user avatar uploads and downloads
+type AvatarHandler struct {
+	avatarService *service.AvatarService
+}
+
+// NewAvatarHandler creates a new avatar handler
+func NewAvatarHandler(avatarService *service.AvatarService) *AvatarHandler {
+	return &AvatarHandler{
+		avatarService: avatarService,
+	}
+}
+
+// Upload replaces the authenticated user's avatar with an image sent either as
+// the multipart field "avatar" or as the raw request body
+func (h *AvatarHandler) Upload(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var data io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("avatar")
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "An image is required in the \"avatar\" field")
+			return
+		}
+		opened, err := file.Open()
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Failed to read uploaded file")
+			return
+		}
+		defer opened.Close()
+		data = opened
+	}
+
+	user, err := h.avatarService.Upload(c.Request.Context(), userID, data)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewUserResponse(user))
+}
+
+// Get serves a user's avatar; its URL changes with every upload, so it is cached
+func (h *AvatarHandler) Get(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	reader, err := h.avatarService.Open(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.DataFromReader(http.StatusOK, -1, "image/png", reader, nil)
+}
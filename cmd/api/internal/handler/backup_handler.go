@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BackupHandler handles backup-related HTTP requests
+type BackupHandler struct {
+	backupService *service.BackupService
+}
+
+// NewBackupHandler creates a new backup handler
+func NewBackupHandler(backupService *service.BackupService) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+	}
+}
+
+// Create exports a full backup of the authenticated user's data
+func (h *BackupHandler) Create(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	backup, err := h.backupService.CreateBackup(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, backup)
+}
+
+// List returns the authenticated user's stored backups
+func (h *BackupHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	backups, err := h.backupService.ListBackups(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve backups")
+		return
+	}
+
+	c.JSON(http.StatusOK, backups)
+}
+
+// Restore validates and re-imports a stored backup
+func (h *BackupHandler) Restore(c *gin.Context) {
+	var req domain.RestoreBackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	response, err := h.backupService.RestoreBackup(c.Request.Context(), userID, req.Key)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
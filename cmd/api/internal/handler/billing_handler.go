@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BillingHandler handles plan payments through Stripe
+type BillingHandler struct {
+	billingService *service.BillingService
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(billingService *service.BillingService) *BillingHandler {
+	return &BillingHandler{billingService: billingService}
+}
+
+// Checkout starts a Stripe Checkout for a paid plan and returns the URL of its
+// payment page; the body is validated by BindJSON
+func (h *BillingHandler) Checkout(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	req := requestBody[domain.CheckoutRequest](c)
+
+	link, err := h.billingService.Checkout(c.Request.Context(), userID, req.Plan)
+	if err != nil {
+		respondError(c, http.StatusBadGateway, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// PortalLink returns the URL of a Stripe customer portal session
+func (h *BillingHandler) PortalLink(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	link, err := h.billingService.PortalLink(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, http.StatusBadGateway, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// Invoices lists the current user's invoices
+func (h *BillingHandler) Invoices(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	invoices, err := h.billingService.Invoices(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+// Webhook receives Stripe events. Any response other than 2xx makes Stripe
+// deliver the event again.
+func (h *BillingHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "failed to read body")
+		return
+	}
+
+	if err := h.billingService.HandleWebhook(c.Request.Context(), payload, c.GetHeader("Stripe-Signature")); err != nil {
+		if errors.Is(err, service.ErrInvalidStripeSignature) {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
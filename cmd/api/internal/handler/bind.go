@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"products/internal/domain"
+	"products/cmd/api/internal/i18n"
+	"products/cmd/api/internal/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// requestBodyKey is the context key holding the request body validated by BindJSON
+const requestBodyKey = "request_body"
+
+// BindJSON decodes the JSON body into T, sanitizes it and validates its binding
+// tags, rejecting the request with every field error at once. Handlers behind it
+// read the result with requestBody.
+func BindJSON[T any]() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := new(T)
+		if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: "+err.Error())
+			return
+		}
+
+		validation.Sanitize(req)
+		if err := binding.Validator.ValidateStruct(req); err != nil {
+			respondValidation(c, err)
+			return
+		}
+
+		c.Set(requestBodyKey, req)
+		c.Next()
+	}
+}
+
+// requestBody returns the request body validated by BindJSON
+func requestBody[T any](c *gin.Context) *T {
+	return c.MustGet(requestBodyKey).(*T)
+}
+
+// respondValidation aborts the request with one problem entry per invalid field,
+// localized to the language negotiated from Accept-Language
+func respondValidation(c *gin.Context, err error) {
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	fieldErrors := validation.FieldErrors(err, lang)
+	if len(fieldErrors) == 0 {
+		respondProblem(c, http.StatusBadRequest, domain.CodeValidationFailed, err.Error())
+		return
+	}
+
+	c.Header("Content-Language", lang)
+	c.Error(&problemError{
+		status: http.StatusBadRequest,
+		code:   domain.CodeValidationFailed,
+		detail: i18n.T(lang, i18n.ValidationFailed),
+		errors: fieldErrors,
+	})
+	c.Abort()
+}
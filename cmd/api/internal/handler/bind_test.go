@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"products/internal/domain"
+	"products/cmd/api/internal/validation"
+	"github.com/gin-gonic/gin"
+)
+
+func serveBind(body string) *httptest.ResponseRecorder {
+	return serveBindLang(body, "")
+}
+
+func serveBindLang(body, acceptLanguage string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	validation.Register()
+
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.POST("/products", BindJSON[domain.CreateProductRequest](), func(c *gin.Context) {
+		c.JSON(http.StatusOK, requestBody[domain.CreateProductRequest](c))
+	})
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestBindJSON_ReportsAllFieldErrors(t *testing.T) {
+	recorder := serveBind(`{"name": "x", "price": 0, "stock": -1, "attributes": {"Bad Key": 1}}`)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", recorder.Code)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	if problem.Code != domain.CodeValidationFailed {
+		t.Errorf("Expected code %s, got %s", domain.CodeValidationFailed, problem.Code)
+	}
+
+	fields := map[string]bool{}
+	for _, fieldErr := range problem.Errors {
+		fields[fieldErr.Field] = true
+	}
+	for _, field := range []string{"name", "price", "stock", "attributes"} {
+		if !fields[field] {
+			t.Errorf("Expected an error for %s, got %+v", field, problem.Errors)
+		}
+	}
+}
+
+func TestBindJSON_SanitizesBeforeValidating(t *testing.T) {
+	recorder := serveBind(`{"name": "  Desk lamp\u0000 ", "price": 19.99, "stock": 0}`)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var req domain.CreateProductRequest
+	if err := json.Unmarshal(recorder.Body.Bytes(), &req); err != nil {
+		t.Fatalf("Failed to decode request: %v", err)
+	}
+	if req.Name != "Desk lamp" {
+		t.Errorf("Expected sanitized name, got %q", req.Name)
+	}
+}
+
+func TestBindJSON_RejectsMalformedBody(t *testing.T) {
+	recorder := serveBind(`{"name": `)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", recorder.Code)
+	}
+}
+
+func TestBindJSON_AcceptsUnicodeNames(t *testing.T) {
+	recorder := serveBind(`{"name": "Café Müller", "description": "Crème brûlée für zwei", "price": 9.5, "stock": 1}`)
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestBindJSON_LocalizesMessages(t *testing.T) {
+	recorder := serveBindLang(`{"name": "Desk lamp", "price": 19.99, "stock": -1}`, "de-CH,de;q=0.9,en;q=0.8")
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", recorder.Code)
+	}
+	if lang := recorder.Header().Get("Content-Language"); lang != "de" {
+		t.Errorf("Expected Content-Language de, got %q", lang)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	if len(problem.Errors) != 1 || !strings.HasPrefix(problem.Errors[0].Message, "muss zwischen") {
+		t.Errorf("Expected a German stock message, got %+v", problem.Errors)
+	}
+}
+
+func TestBindJSON_AcceptsSQLKeywordsAndMarkdown(t *testing.T) {
+	recorder := serveBind(`{"name": "Select Update Chair", "description": "Drop-in **seat**\r\n\n- oak\n- ` + "`walnut`" + `", "price": 49, "stock": 2}`)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var req domain.CreateProductRequest
+	if err := json.Unmarshal(recorder.Body.Bytes(), &req); err != nil {
+		t.Fatalf("Failed to decode request: %v", err)
+	}
+	if want := "Drop-in **seat**\n\n- oak\n- `walnut`"; req.Description != want {
+		t.Errorf("Expected description %q with line breaks kept, got %q", want, req.Description)
+	}
+}
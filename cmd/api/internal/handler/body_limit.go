@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxJSONDepth limits the nesting of arrays and objects in JSON request bodies
+const MaxJSONDepth = 32
+
+// Default request body size limits
+const (
+	DefaultBodyLimit     = 1 << 20
+	DefaultAuthBodyLimit = 16 << 10
+	DefaultBulkBodyLimit = 10 << 20
+)
+
+// BodyLimits are the request body size limits in bytes: Auth for the public
+// authentication routes, Bulk for bulk operations, imports and restores, and
+// Default for every other route
+type BodyLimits struct {
+	Default int64
+	Auth    int64
+	Bulk    int64
+}
+
+// DefaultBodyLimits returns the default request body size limits
+func DefaultBodyLimits() BodyLimits {
+	return BodyLimits{Default: DefaultBodyLimit, Auth: DefaultAuthBodyLimit, Bulk: DefaultBulkBodyLimit}
+}
+
+// errJSONTooDeep is returned by limitedBody reads once a JSON body nests too deep
+var errJSONTooDeep = fmt.Errorf("JSON nesting exceeds the maximum depth of %d", MaxJSONDepth)
+
+// BodyLimitMiddleware rejects request bodies larger than limit with 413 and, for
+// JSON bodies, nesting deeper than MaxJSONDepth with 400. Bodies are checked as
+// they are read, so handlers that report the truncated body as malformed are
+// answered with the limit's problem instead. Used again on a route, it replaces
+// the limit set for all routes, as long as the body has not been read yet.
+func BodyLimitMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if body, ok := c.Request.Body.(*limitedBody); ok {
+			body.limit = limit
+			c.Next()
+			return
+		}
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body := &limitedBody{
+			source:        c.Request.Body,
+			writer:        c.Writer,
+			limit:         limit,
+			contentLength: c.Request.ContentLength,
+			json:          isJSONContentType(c.ContentType()),
+		}
+		c.Request.Body = body
+
+		c.Next()
+
+		if c.Writer.Written() {
+			return
+		}
+		switch {
+		case body.tooLarge:
+			respondProblem(c, http.StatusRequestEntityTooLarge, domain.CodePayloadTooLarge, fmt.Sprintf("Request body exceeds the limit of %d bytes", body.limit))
+		case body.tooDeep:
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, errJSONTooDeep.Error())
+		}
+	}
+}
+
+// isJSONContentType reports whether a media type is JSON
+func isJSONContentType(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// limitedBody is a size-limited request body that records whether the limit was
+// hit and, for JSON, tracks the nesting depth of the bytes read. The limit takes
+// effect on the first read.
+type limitedBody struct {
+	source        io.ReadCloser
+	reader        io.ReadCloser
+	writer        http.ResponseWriter
+	limit         int64
+	contentLength int64
+	json          bool
+	tooLarge      bool
+	tooDeep       bool
+
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+// errBodyTooLarge is returned by limitedBody reads of bodies declared larger than the limit
+var errBodyTooLarge = errors.New("http: request body too large")
+
+// Read implements io.Reader
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.tooDeep {
+		return 0, errJSONTooDeep
+	}
+	if b.reader == nil {
+		if b.contentLength > b.limit {
+			b.tooLarge = true
+			return 0, errBodyTooLarge
+		}
+		b.reader = http.MaxBytesReader(b.writer, b.source, b.limit)
+	}
+
+	n, err := b.reader.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.tooLarge = true
+	}
+	if b.json && !b.scan(p[:n]) {
+		b.tooDeep = true
+		return 0, errJSONTooDeep
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (b *limitedBody) Close() error {
+	return b.source.Close()
+}
+
+// scan advances the JSON nesting depth over data, skipping brackets inside
+// strings, and reports whether it stays within MaxJSONDepth
+func (b *limitedBody) scan(data []byte) bool {
+	for _, char := range data {
+		if b.inString {
+			switch {
+			case b.escaped:
+				b.escaped = false
+			case char == '\\':
+				b.escaped = true
+			case char == '"':
+				b.inString = false
+			}
+			continue
+		}
+
+		switch char {
+		case '"':
+			b.inString = true
+		case '{', '[':
+			b.depth++
+			if b.depth > MaxJSONDepth {
+				return false
+			}
+		case '}', ']':
+			b.depth--
+		}
+	}
+	return true
+}
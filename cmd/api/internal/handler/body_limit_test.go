@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type payload struct {
+		Name string      `json:"name"`
+		Data interface{} `json:"data"`
+	}
+	bindJSON := func(c *gin.Context) {
+		var req payload
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: "+err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+
+	router := gin.New()
+	router.Use(ErrorMiddleware(), BodyLimitMiddleware(64))
+	router.POST("/small", bindJSON)
+	router.POST("/large", BodyLimitMiddleware(1024), bindJSON)
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		chunked  bool
+		want     int
+		wantCode string
+	}{
+		{"within limit", "/small", `{"name":"chair"}`, false, http.StatusNoContent, ""},
+		{"declared too large", "/small", `{"name":"` + strings.Repeat("a", 100) + `"}`, false, http.StatusRequestEntityTooLarge, domain.CodePayloadTooLarge},
+		{"streamed too large", "/small", `{"name":"` + strings.Repeat("a", 100) + `"}`, true, http.StatusRequestEntityTooLarge, domain.CodePayloadTooLarge},
+		{"route limit", "/large", `{"name":"` + strings.Repeat("a", 100) + `"}`, false, http.StatusNoContent, ""},
+		{"too deep", "/large", `{"data":` + strings.Repeat("[", MaxJSONDepth) + strings.Repeat("]", MaxJSONDepth) + `}`, false, http.StatusBadRequest, domain.CodeBadRequest},
+		{"brackets in strings", "/large", `{"name":"` + strings.Repeat(`[{\"`, 40) + `"}`, false, http.StatusNoContent, ""},
+		{"malformed", "/small", `{"name":`, false, http.StatusBadRequest, domain.CodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.chunked {
+				// Hide the length so that the body is only limited while reading
+				body = io.MultiReader(body)
+			}
+			request := httptest.NewRequest(http.MethodPost, tt.path, body)
+			request.Header.Set("Content-Type", "application/json")
+			if tt.chunked {
+				request.ContentLength = -1
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d %s", tt.want, recorder.Code, recorder.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(recorder.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("Expected code %s, got %s", tt.wantCode, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestBodyLimitMiddleware_JSONDepthDetail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorMiddleware(), BodyLimitMiddleware(1024))
+	router.POST("/", func(c *gin.Context) {
+		var req map[string]interface{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: "+err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":`+strings.Repeat("[", MaxJSONDepth+1)))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "maximum depth") {
+		t.Errorf("Expected 400 naming the depth limit, got %d %s", recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"products/internal/domain"
+	"products/cmd/api/internal/i18n"
+	"products/cmd/api/internal/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// decodeBulkItem decodes, sanitizes and validates one bulk item the way BindJSON
+// handles a whole body, returning the failure instead of rejecting the request
+func decodeBulkItem[T any](c *gin.Context, raw json.RawMessage) (*T, error) {
+	item := new(T)
+	if err := json.Unmarshal(raw, item); err != nil {
+		return nil, domain.NewError(domain.CodeBadRequest, "Invalid item format: "+err.Error())
+	}
+
+	if err := validateItem(c, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// validateItem sanitizes and validates the struct pointed to by item the way
+// BindJSON handles a body, with field errors in the request's language
+func validateItem(c *gin.Context, item interface{}) error {
+	validation.Sanitize(item)
+	if err := binding.Validator.ValidateStruct(item); err != nil {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		fieldErrors := validation.FieldErrors(err, lang)
+		if len(fieldErrors) == 0 {
+			return domain.NewError(domain.CodeValidationFailed, err.Error())
+		}
+		return &domain.ValidationError{Message: i18n.T(lang, i18n.ValidationFailed), Fields: fieldErrors}
+	}
+	return nil
+}
+
+// respondBulk writes a bulk result, giving every item the status its own request
+// would have had. The response is 207 Multi-Status when any item failed, and
+// successStatus otherwise.
+func respondBulk(c *gin.Context, successStatus int, result *domain.BulkResult) {
+	for i := range result.Items {
+		item := &result.Items[i]
+		if item.Error == nil {
+			item.Status = successStatus
+			continue
+		}
+
+		item.Status = http.StatusInternalServerError
+		if status, ok := codeStatus[item.Error.Code]; ok {
+			item.Status = status
+		}
+		if item.Status >= http.StatusInternalServerError {
+			log.Printf("Request %s %s failed for item %d: %s", c.Request.Method, c.Request.URL.Path, item.Index, item.Error.Message)
+			item.Error.Message = "An unexpected error occurred"
+		}
+	}
+
+	status := successStatus
+	if result.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, result)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"products/internal/domain"
+	"products/cmd/api/internal/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func serveBulk(result *domain.BulkResult) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/bulk", func(c *gin.Context) {
+		respondBulk(c, http.StatusCreated, result)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/bulk", nil))
+	return recorder
+}
+
+func TestRespondBulk_AllSucceeded(t *testing.T) {
+	result := domain.NewBulkResult()
+	result.Succeed(0, uuid.New())
+
+	recorder := serveBulk(result)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", recorder.Code)
+	}
+}
+
+func TestRespondBulk_PartialFailure(t *testing.T) {
+	result := domain.NewBulkResult()
+	result.Succeed(0, uuid.New())
+	result.Fail(1, nil, domain.ErrProductNotFound)
+	result.Fail(2, nil, errors.New("connection reset"))
+
+	recorder := serveBulk(result)
+	if recorder.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected status 207, got %d", recorder.Code)
+	}
+
+	var body domain.BulkResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	wantStatuses := []int{http.StatusCreated, http.StatusNotFound, http.StatusInternalServerError}
+	for i, want := range wantStatuses {
+		if body.Items[i].Status != want {
+			t.Errorf("Item %d: expected status %d, got %d", i, want, body.Items[i].Status)
+		}
+	}
+	if body.Items[2].Error.Message == "connection reset" {
+		t.Error("Expected internal error details to be hidden")
+	}
+}
+
+func TestValidateItem_ImportedRow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validation.Register()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/products/import", nil)
+
+	row := &domain.CreateProductRequest{Name: "Desk\x00 lamp", Description: "Warm\r\nlight\x07", Price: 19.99, Stock: 3}
+	if err := validateItem(c, row); err != nil {
+		t.Fatalf("Expected a valid row, got %v", err)
+	}
+	if row.Name != "Desk lamp" || row.Description != "Warm\nlight" {
+		t.Errorf("Expected the row to be sanitized, got %q and %q", row.Name, row.Description)
+	}
+
+	invalid := &domain.CreateProductRequest{Name: strings.Repeat("x", 201), Price: 1_000_000, Stock: 1_000_000}
+	var validationErr *domain.ValidationError
+	if err := validateItem(c, invalid); !errors.As(err, &validationErr) || len(validationErr.Fields) != 3 {
+		t.Errorf("Expected name, price and stock to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CatalogHandler handles the public product catalog
+type CatalogHandler struct {
+	catalogService *service.CatalogService
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(catalogService *service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// SetVisibility publishes or unpublishes one of the user's products
+func (h *CatalogHandler) SetVisibility(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	var req domain.ProductVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	if err := h.catalogService.SetPublic(c.Request.Context(), id, userID, *req.Public); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product visibility updated successfully"})
+}
+
+// SetSlug sets the slug of the user's public catalog
+func (h *CatalogHandler) SetSlug(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req domain.SlugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	if err := h.catalogService.SetSlug(c.Request.Context(), userID, req.Slug); err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Catalog slug updated successfully"})
+}
+
+// GetProduct returns a public product to anonymous visitors
+func (h *CatalogHandler) GetProduct(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	product, err := h.catalogService.GetProduct(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	if wantsHTMLDescriptions(c) {
+		c.JSON(http.StatusOK, htmlPublicProducts([]domain.PublicProductResponse{*product})[0])
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// ListBySlug returns the public catalog of a user
+func (h *CatalogHandler) ListBySlug(c *gin.Context) {
+	pagination := domain.Pagination{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			pagination.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			pagination.PageSize = pageSize
+		}
+	}
+
+	response, err := h.catalogService.ListBySlug(c.Request.Context(), c.Param("slug"), pagination)
+	if err != nil {
+		respondError(c, http.StatusNotFound, domain.CodeNotFound, err)
+		return
+	}
+
+	if wantsHTMLDescriptions(c) {
+		rendered := *response
+		rendered.Products = htmlPublicProducts(response.Products)
+		response = &rendered
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/cmd/api/internal/changelog"
+	"github.com/gin-gonic/gin"
+)
+
+// ChangelogHandler serves the machine-readable API changelog
+type ChangelogHandler struct{}
+
+// NewChangelogHandler creates a new changelog handler
+func NewChangelogHandler() *ChangelogHandler {
+	return &ChangelogHandler{}
+}
+
+// Get returns the API changelog, optionally restricted to deprecations
+func (h *ChangelogHandler) Get(c *gin.Context) {
+	if c.Query("type") == changelog.TypeDeprecated {
+		c.JSON(http.StatusOK, changelog.Changelog{
+			CurrentVersion: changelog.CurrentVersion,
+			Entries:        changelog.Deprecations(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, changelog.Get())
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// DebugConfig enables the debugging aids used to reproduce and profile
+// performance problems; both are off by default
+type DebugConfig struct {
+	// Faults are the latency and errors injected into matching routes
+	Faults []FaultRule
+
+	// Pprof serves the runtime profiles under /debug/pprof to admins
+	Pprof bool
+}
+
+// FaultRule injects latency and errors into the requests of matching routes
+type FaultRule struct {
+	// Method is the HTTP method matched, or empty for every method
+	Method string
+
+	// Route is the route pattern matched, such as /api/v1/products/:id; a pattern
+	// ending in * matches the routes it prefixes, so * alone matches every route
+	Route string
+
+	// Latency is added to each request, plus a random duration up to Jitter
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// ErrorRate is the fraction of requests failed with ErrorStatus
+	ErrorRate   float64
+	ErrorStatus int
+}
+
+// faultExemptRoutes are never slowed down or failed, so that probes keep
+// reporting on the instance
+var faultExemptRoutes = map[string]bool{"/health": true, "/ready": true, "/readyz": true}
+
+// matches reports whether the rule applies to a request for route
+func (r FaultRule) matches(method, route string) bool {
+	return routeMatches(r.Method, r.Route, method, route)
+}
+
+// routeMatches reports whether a request for route matches a route pattern and,
+// when set, patternMethod. A pattern ending in * matches the routes it prefixes.
+func routeMatches(patternMethod, pattern, method, route string) bool {
+	if patternMethod != "" && patternMethod != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return pattern == route
+}
+
+// parseRouteTarget parses "[METHOD ]pattern", returning the upper-cased method
+// and the pattern
+func parseRouteTarget(target string) (string, string, error) {
+	method, pattern := "", strings.TrimSpace(target)
+	if before, after, ok := strings.Cut(pattern, " "); ok {
+		method, pattern = strings.ToUpper(before), strings.TrimSpace(after)
+	}
+	if pattern == "" {
+		return "", "", fmt.Errorf("missing route in %q", target)
+	}
+	return method, pattern, nil
+}
+
+// delay returns the latency injected into one request
+func (r FaultRule) delay() time.Duration {
+	if r.Jitter <= 0 {
+		return r.Latency
+	}
+	return r.Latency + time.Duration(rand.Int63n(int64(r.Jitter)+1))
+}
+
+// ParseFaultRules parses semicolon-separated rules of the form
+// "[METHOD ]route=option:value,...", e.g.
+// "GET /api/v1/products/filtered=latency:200ms,jitter:50ms,error_rate:0.05;*=latency:10ms".
+// The options are latency, jitter, error_rate and status (500 by default). Rules
+// are tried in order and the first matching rule applies.
+func ParseFaultRules(value string) ([]FaultRule, error) {
+	var rules []FaultRule
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		target, options, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected route=options, got %q", entry)
+		}
+		method, route, err := parseRouteTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		rule := FaultRule{Method: method, Route: route, ErrorStatus: http.StatusInternalServerError}
+
+		for _, option := range strings.Split(options, ",") {
+			name, rawValue, ok := strings.Cut(strings.TrimSpace(option), ":")
+			if !ok {
+				return nil, fmt.Errorf("%s: expected option:value, got %q", rule.Route, option)
+			}
+			rawValue = strings.TrimSpace(rawValue)
+
+			var err error
+			switch name {
+			case "latency":
+				rule.Latency, err = time.ParseDuration(rawValue)
+				if err == nil && rule.Latency < 0 {
+					err = fmt.Errorf("negative latency")
+				}
+			case "jitter":
+				rule.Jitter, err = time.ParseDuration(rawValue)
+				if err == nil && rule.Jitter < 0 {
+					err = fmt.Errorf("negative jitter")
+				}
+			case "error_rate":
+				rule.ErrorRate, err = strconv.ParseFloat(rawValue, 64)
+				if err == nil && (rule.ErrorRate < 0 || rule.ErrorRate > 1) {
+					err = fmt.Errorf("must be between 0 and 1")
+				}
+			case "status":
+				rule.ErrorStatus, err = strconv.Atoi(rawValue)
+				if err == nil && (rule.ErrorStatus < 400 || rule.ErrorStatus > 599) {
+					err = fmt.Errorf("must be an error status")
+				}
+			default:
+				err = fmt.Errorf("unknown option")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid %s %q: %v", rule.Route, name, rawValue, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// FaultInjectionMiddleware delays and fails requests as the first rule matching
+// their route says, so that slow or failing endpoints can be reproduced under
+// load. Delayed responses carry an X-Injected-Latency header and failed ones the
+// code INJECTED_FAULT. It must run after ErrorMiddleware and TimeoutMiddleware, so
+// that injected latency counts against the request's deadline.
+func FaultInjectionMiddleware(rules []FaultRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" || faultExemptRoutes[route] {
+			c.Next()
+			return
+		}
+
+		for _, rule := range rules {
+			if !rule.matches(c.Request.Method, route) {
+				continue
+			}
+
+			if delay := rule.delay(); delay > 0 {
+				c.Header("X-Injected-Latency", delay.String())
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-c.Request.Context().Done():
+					// TimeoutMiddleware answers requests that ran out of time
+					timer.Stop()
+					c.Abort()
+					return
+				}
+			}
+			if rule.ErrorRate > 0 && rand.Float64() < rule.ErrorRate {
+				respondProblem(c, rule.ErrorStatus, domain.CodeInjectedFault, "Fault injected for testing")
+				return
+			}
+			break
+		}
+		c.Next()
+	}
+}
+
+// PprofHandler serves the runtime profiles of net/http/pprof; it must be routed
+// as /debug/pprof/*name
+func PprofHandler(c *gin.Context) {
+	switch c.Param("name") {
+	case "/cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "/profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "/symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "/trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// the index lists the profiles and serves the one named in the path
+		pprof.Index(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseFaultRules(t *testing.T) {
+	rules, err := ParseFaultRules("get /api/v1/products/filtered=latency:200ms,jitter:50ms,error_rate:0.05; /api/v1/*=status:503,error_rate:1")
+	if err != nil {
+		t.Fatalf("ParseFaultRules: %v", err)
+	}
+	want := []FaultRule{
+		{Method: http.MethodGet, Route: "/api/v1/products/filtered", Latency: 200 * time.Millisecond, Jitter: 50 * time.Millisecond, ErrorRate: 0.05, ErrorStatus: http.StatusInternalServerError},
+		{Route: "/api/v1/*", ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("Expected %d rules, got %+v", len(want), rules)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("Rule %d: expected %+v, got %+v", i, want[i], rules[i])
+		}
+	}
+
+	for _, invalid := range []string{
+		"/products",
+		"=latency:1s",
+		"/products=latency:fast",
+		"/products=error_rate:2",
+		"/products=status:200",
+		"/products=retries:3",
+	} {
+		if _, err := ParseFaultRules(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestFaultInjectionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rules := []FaultRule{
+		{Method: http.MethodGet, Route: "/products/:id", ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable},
+		{Route: "/products*", Latency: 5 * time.Millisecond},
+		{Route: "*", ErrorRate: 1, ErrorStatus: http.StatusInternalServerError},
+	}
+	router := gin.New()
+	router.Use(ErrorMiddleware(), FaultInjectionMiddleware(rules))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/products/:id", ok)
+	router.PUT("/products/:id", ok)
+	router.GET("/products", ok)
+	router.GET("/orders", ok)
+	router.GET("/health", ok)
+
+	tests := []struct {
+		method  string
+		path    string
+		want    int
+		delayed bool
+	}{
+		{http.MethodGet, "/products/1", http.StatusServiceUnavailable, false},
+		// the first matching rule applies
+		{http.MethodPut, "/products/1", http.StatusOK, true},
+		{http.MethodGet, "/products", http.StatusOK, true},
+		{http.MethodGet, "/orders", http.StatusInternalServerError, false},
+		// probes are exempt
+		{http.MethodGet, "/health", http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+		if recorder.Code != tt.want {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.want, recorder.Code)
+		}
+		if tt.want >= http.StatusInternalServerError && !strings.Contains(recorder.Body.String(), `"code":"`+domain.CodeInjectedFault+`"`) {
+			t.Errorf("%s %s: expected code %s, got %s", tt.method, tt.path, domain.CodeInjectedFault, recorder.Body.String())
+		}
+		if delayed := recorder.Header().Get("X-Injected-Latency") != ""; delayed != tt.delayed {
+			t.Errorf("%s %s: expected delayed %v, got %v", tt.method, tt.path, tt.delayed, delayed)
+		}
+	}
+}
+
+func TestFaultInjectionMiddleware_Timeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorMiddleware(), TimeoutMiddleware(10*time.Millisecond),
+		FaultInjectionMiddleware([]FaultRule{{Route: "*", Latency: time.Minute}}))
+	router.GET("/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/products", nil))
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected the injected latency to run into the deadline, got %d", recorder.Code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the request to end at its deadline, took %s", elapsed)
+	}
+}
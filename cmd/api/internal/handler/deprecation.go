@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"products/cmd/api/internal/deprecation"
+	"github.com/gin-gonic/gin"
+)
+
+// deprecationBodyLimit bounds how much of a request body is inspected for deprecated fields
+const deprecationBodyLimit = 1 << 20
+
+// deprecationResponseWriter buffers the response so the deprecations can be added to its meta
+type deprecationResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *deprecationResponseWriter) Write(data []byte) (int, error) {
+	if w.streaming() {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *deprecationResponseWriter) WriteString(s string) (int, error) {
+	if w.streaming() {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+// streaming reports whether the response is NDJSON, which is passed through as it
+// is written and carries the deprecations in its Warning headers only
+func (w *deprecationResponseWriter) streaming() bool {
+	return w.Header().Get("Content-Type") == ndjsonContentType
+}
+
+func (w *deprecationResponseWriter) Written() bool {
+	return w.body.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// DeprecationMiddleware reports the deprecated query parameters and request fields a
+// request used, and the deprecated response fields it receives, from the central
+// registry: each one adds a Warning header, and JSON object responses carry them all
+// in meta.deprecations. It must run before ErrorMiddleware so problem responses are
+// covered too.
+func DeprecationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		used := usedDeprecations(c)
+		if len(used) == 0 {
+			c.Next()
+			return
+		}
+
+		for _, entry := range used {
+			c.Writer.Header().Add("Warning", fmt.Sprintf("299 - %q", entry.Message))
+		}
+
+		writer := &deprecationResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		body := writer.body.Bytes()
+		if strings.Contains(c.Writer.Header().Get("Content-Type"), "json") {
+			body = withDeprecationMeta(body, used)
+		}
+		if len(body) > 0 {
+			c.Writer.Write(body)
+		}
+	}
+}
+
+// usedDeprecations returns the registered deprecations that apply to the request
+func usedDeprecations(c *gin.Context) []deprecation.Deprecation {
+	entries := deprecation.ForEndpoint(c.Request.Method, c.FullPath())
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	var used []deprecation.Deprecation
+	for i, entry := range entries {
+		switch entry.Kind {
+		case deprecation.KindQueryParameter:
+			if _, ok := c.Request.URL.Query()[entry.Name]; !ok {
+				continue
+			}
+		case deprecation.KindRequestField:
+			if fields == nil {
+				fields = requestFields(c)
+			}
+			if _, ok := fields[entry.Name]; !ok {
+				continue
+			}
+		}
+		used = append(used, entries[i])
+	}
+	return used
+}
+
+// requestFields returns the top-level fields of a JSON request body, leaving the
+// body readable for the handler
+func requestFields(c *gin.Context) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if c.Request.Body == nil {
+		return fields
+	}
+
+	captured, err := io.ReadAll(io.LimitReader(c.Request.Body, deprecationBodyLimit))
+	if err != nil {
+		return fields
+	}
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(captured), c.Request.Body), c.Request.Body}
+
+	json.Unmarshal(captured, &fields)
+	return fields
+}
+
+// withDeprecationMeta adds the deprecations to the meta object of a JSON object body.
+// Other bodies are returned unchanged.
+func withDeprecationMeta(body []byte, used []deprecation.Deprecation) []byte {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil || object == nil {
+		return body
+	}
+
+	meta := map[string]interface{}{}
+	if existing, ok := object["meta"]; ok {
+		if err := json.Unmarshal(existing, &meta); err != nil || meta == nil {
+			return body
+		}
+	}
+	meta["deprecations"] = used
+
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return body
+	}
+
+	// Append the meta member without re-encoding the body so its field order is kept
+	if _, ok := object["meta"]; !ok {
+		trimmed := bytes.TrimRight(body, " \t\r\n")
+		closing := len(trimmed) - 1
+		separator := ","
+		if len(object) == 0 {
+			separator = ""
+		}
+		result := append([]byte{}, trimmed[:closing]...)
+		result = append(result, separator+`"meta":`...)
+		result = append(result, encoded...)
+		return append(result, '}')
+	}
+
+	object["meta"] = encoded
+	result, err := json.Marshal(object)
+	if err != nil {
+		return body
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serveDeprecation(method, target, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(DeprecationMiddleware(), ErrorMiddleware())
+	router.GET("/api/v1/products/filtered", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"products": []gin.H{}, "total": 0})
+	})
+	router.POST("/api/v1/products/", func(c *gin.Context) {
+		var req map[string]interface{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"name": req["name"], "price": req["price"]})
+	})
+	router.GET("/api/v2/products/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"products": []gin.H{}})
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return recorder
+}
+
+func deprecationIDs(t *testing.T, recorder *httptest.ResponseRecorder) []string {
+	t.Helper()
+
+	var response struct {
+		Meta struct {
+			Deprecations []struct {
+				ID string `json:"id"`
+			} `json:"deprecations"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response %s: %v", recorder.Body.String(), err)
+	}
+
+	ids := []string{}
+	for _, entry := range response.Meta.Deprecations {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+func TestDeprecationMiddleware_QueryParameters(t *testing.T) {
+	recorder := serveDeprecation(http.MethodGet, "/api/v1/products/filtered?min_price=10&name=x", "")
+
+	if got := deprecationIDs(t, recorder); strings.Join(got, ",") != "v1-product-price-response,v1-min-price-parameter" {
+		t.Errorf("Unexpected deprecations %v", got)
+	}
+	if warnings := recorder.Header().Values("Warning"); len(warnings) != 2 || !strings.HasPrefix(warnings[1], `299 - "The min_price parameter`) {
+		t.Errorf("Unexpected Warning headers %v", warnings)
+	}
+	if !strings.HasPrefix(recorder.Body.String(), `{"products":[],"total":0,"meta":`) {
+		t.Errorf("Expected the body to keep its fields and order, got %s", recorder.Body.String())
+	}
+}
+
+func TestDeprecationMiddleware_RequestFields(t *testing.T) {
+	recorder := serveDeprecation(http.MethodPost, "/api/v1/products/", `{"name":"Lamp","price":9.5}`)
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected the handler to still read the body, got status %d", recorder.Code)
+	}
+	if got := deprecationIDs(t, recorder); strings.Join(got, ",") != "v1-product-price-response,v1-product-price-request" {
+		t.Errorf("Unexpected deprecations %v", got)
+	}
+	if !strings.Contains(recorder.Body.String(), `"price":9.5`) {
+		t.Errorf("Expected the handler response to be kept, got %s", recorder.Body.String())
+	}
+
+	recorder = serveDeprecation(http.MethodPost, "/api/v1/products/", `{"name":"Lamp"}`)
+	if got := deprecationIDs(t, recorder); strings.Join(got, ",") != "v1-product-price-response" {
+		t.Errorf("Expected only the response field deprecation, got %v", got)
+	}
+}
+
+func TestDeprecationMiddleware_LeavesOtherRoutesAlone(t *testing.T) {
+	recorder := serveDeprecation(http.MethodGet, "/api/v2/products/?min_price=10", "")
+
+	if warnings := recorder.Header().Values("Warning"); len(warnings) != 0 {
+		t.Errorf("Expected no Warning headers, got %v", warnings)
+	}
+	if recorder.Body.String() != `{"products":[]}` {
+		t.Errorf("Expected an unchanged body, got %s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"products/internal/domain"
+	"products/internal/markdown"
+	"github.com/gin-gonic/gin"
+)
+
+// descriptionFormatHTML is the ?format= value that returns descriptions as rendered HTML
+const descriptionFormatHTML = "html"
+
+// wantsHTMLDescriptions reports whether the client asked for descriptions rendered as HTML
+func wantsHTMLDescriptions(c *gin.Context) bool {
+	return c.Query("format") == descriptionFormatHTML
+}
+
+// renderedDescription returns the stored HTML of a description, rendering descriptions
+// saved before Markdown support on the fly
+func renderedDescription(description, rendered string) string {
+	if rendered == "" && description != "" {
+		return markdown.Render(description)
+	}
+	return rendered
+}
+
+// htmlProducts returns copies of products whose description is the rendered HTML;
+// the originals may be shared with other requests and are left untouched
+func htmlProducts(products []domain.Product) []domain.Product {
+	result := make([]domain.Product, len(products))
+	for i, product := range products {
+		product.Description = renderedDescription(product.Description, product.DescriptionHTML)
+		product.DescriptionHTML = ""
+		result[i] = product
+	}
+	return result
+}
+
+// htmlPublicProducts is htmlProducts for public catalog products
+func htmlPublicProducts(products []domain.PublicProductResponse) []domain.PublicProductResponse {
+	result := make([]domain.PublicProductResponse, len(products))
+	for i, product := range products {
+		product.Description = renderedDescription(product.Description, product.DescriptionHTML)
+		product.DescriptionHTML = ""
+		result[i] = product
+	}
+	return result
+}
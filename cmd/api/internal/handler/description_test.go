@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"testing"
+
+	"products/internal/domain"
+)
+
+func TestHTMLProducts(t *testing.T) {
+	products := []domain.Product{
+		{Description: "**stored**", DescriptionHTML: "<p><strong>stored</strong></p>"},
+		{Description: "saved _before_ Markdown"},
+		{},
+	}
+
+	rendered := htmlProducts(products)
+
+	if rendered[0].Description != "<p><strong>stored</strong></p>" || rendered[0].DescriptionHTML != "" {
+		t.Errorf("Expected the stored HTML as description, got %+v", rendered[0])
+	}
+	if rendered[1].Description != "<p>saved <em>before</em> Markdown</p>\n" {
+		t.Errorf("Expected a description rendered on the fly, got %q", rendered[1].Description)
+	}
+	if rendered[2].Description != "" {
+		t.Errorf("Expected an empty description to stay empty, got %q", rendered[2].Description)
+	}
+	if products[0].Description != "**stored**" {
+		t.Error("Expected the original products to be left untouched")
+	}
+}
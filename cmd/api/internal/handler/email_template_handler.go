@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EmailTemplateHandler handles administration of transactional email templates
+type EmailTemplateHandler struct {
+	emailTemplateService *service.EmailTemplateService
+}
+
+// NewEmailTemplateHandler creates a new email template handler
+func NewEmailTemplateHandler(emailTemplateService *service.EmailTemplateService) *EmailTemplateHandler {
+	return &EmailTemplateHandler{
+		emailTemplateService: emailTemplateService,
+	}
+}
+
+// List returns every email template with its allowed variables
+func (h *EmailTemplateHandler) List(c *gin.Context) {
+	templates, err := h.emailTemplateService.List(c.Request.Context())
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve email templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// Get returns a single email template
+func (h *EmailTemplateHandler) Get(c *gin.Context) {
+	tmpl, err := h.emailTemplateService.Get(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// Update replaces the subject and body of an email template; the body is validated by BindJSON
+func (h *EmailTemplateHandler) Update(c *gin.Context) {
+	req := requestBody[domain.EmailTemplateRequest](c)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	tmpl, err := h.emailTemplateService.Update(c.Request.Context(), c.Param("key"), adminID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// Reset restores the default version of an email template
+func (h *EmailTemplateHandler) Reset(c *gin.Context) {
+	if err := h.emailTemplateService.Reset(c.Request.Context(), c.Param("key")); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email template reset to default"})
+}
+
+// Preview renders an email template, or unsaved changes to it, with sample variables
+func (h *EmailTemplateHandler) Preview(c *gin.Context) {
+	req := requestBody[domain.EmailTemplatePreviewRequest](c)
+
+	rendered, err := h.emailTemplateService.Preview(c.Request.Context(), c.Param("key"), *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rendered)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EventHandler handles queries of the domain event log and real-time event streams
+type EventHandler struct {
+	eventService  *service.EventService
+	streamService *service.StreamService
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventService *service.EventService, streamService *service.StreamService) *EventHandler {
+	return &EventHandler{
+		eventService:  eventService,
+		streamService: streamService,
+	}
+}
+
+// List returns a page of the user's events matching the query filters, or streams
+// them as they happen to clients accepting server-sent events
+func (h *EventHandler) List(c *gin.Context) {
+	if isEventStream(c.Request) {
+		h.Stream(c)
+		return
+	}
+
+	filter, ok := parseEventFilter(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	response, err := h.eventService.Query(c.Request.Context(), userID, filter)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListAll returns a page of the events of every user matching the query
+// filters, optionally narrowed down to one user
+func (h *EventHandler) ListAll(c *gin.Context) {
+	filter, ok := parseEventFilter(c)
+	if !ok {
+		return
+	}
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := validateUUID(userIDStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "user_id: "+err.Error())
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	response, err := h.eventService.QueryAll(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseEventFilter parses the event query filters, responding with 400 to invalid ones
+func parseEventFilter(c *gin.Context) (domain.EventFilter, bool) {
+	filter := domain.EventFilter{
+		Types: c.QueryArray("type"),
+		Limit: domain.DefaultEventPageSize,
+	}
+
+	if subjectIDStr := c.Query("subject_id"); subjectIDStr != "" {
+		subjectID, err := validateUUID(subjectIDStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "subject_id: "+err.Error())
+			return filter, false
+		}
+		filter.SubjectID = &subjectID
+	}
+
+	for _, bound := range []struct {
+		name  string
+		value **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		if value := c.Query(bound.name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, bound.name+": use an RFC 3339 timestamp")
+				return filter, false
+			}
+			*bound.value = &parsed
+		}
+	}
+
+	if afterStr := c.Query("after"); afterStr != "" {
+		after, err := strconv.ParseInt(afterStr, 10, 64)
+		if err != nil || after < 0 {
+			respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "after: invalid event sequence")
+			return filter, false
+		}
+		filter.After = after
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= domain.MaxEventPageSize {
+			filter.Limit = parsed
+		}
+	}
+
+	return filter, true
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportHandler handles configuration of scheduled exports to user-owned buckets
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// GetDestination returns the authenticated user's export destination and its delivery status
+func (h *ExportHandler) GetDestination(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	destination, err := h.exportService.GetDestination(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, domain.CodeNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, destination)
+}
+
+// PutDestination creates or replaces the authenticated user's export destination
+func (h *ExportHandler) PutDestination(c *gin.Context) {
+	var req domain.ExportDestinationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	destination, err := h.exportService.ConfigureDestination(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, destination)
+}
+
+// DeleteDestination removes the authenticated user's export destination
+func (h *ExportHandler) DeleteDestination(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.exportService.RemoveDestination(c.Request.Context(), userID); err != nil {
+		respondError(c, http.StatusNotFound, domain.CodeNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Export destination removed successfully"})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FavoriteHandler handles favorite/watchlist HTTP requests
+type FavoriteHandler struct {
+	favoriteService *service.FavoriteService
+}
+
+// NewFavoriteHandler creates a new favorite handler
+func NewFavoriteHandler(favoriteService *service.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{
+		favoriteService: favoriteService,
+	}
+}
+
+// Add puts a product on the authenticated user's favorites
+func (h *FavoriteHandler) Add(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.favoriteService.Add(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product added to favorites"})
+}
+
+// Remove takes a product off the authenticated user's favorites
+func (h *FavoriteHandler) Remove(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.favoriteService.Remove(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product removed from favorites"})
+}
+
+// List returns the authenticated user's favorite products
+func (h *FavoriteHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	products, err := h.favoriteService.List(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve favorites")
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+)
+
+// HTTPSRedirect redirects plain HTTP requests to the same URL over HTTPS on the
+// given port, permanently and keeping the method and body (308)
+func HTTPSRedirect(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		target    string
+		expected  string
+	}{
+		{"default port", "443", "http://example.com/api/v1/products?page=2", "https://example.com/api/v1/products?page=2"},
+		{"strips http port", "443", "http://example.com:80/health", "https://example.com/health"},
+		{"custom port", "8443", "http://example.com:8080/health", "https://example.com:8443/health"},
+		{"ipv6 host", "8443", "http://[::1]:8080/health", "https://[::1]:8443/health"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			HTTPSRedirect(tt.httpsPort).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			if recorder.Code != http.StatusPermanentRedirect {
+				t.Errorf("Expected status 308, got %d", recorder.Code)
+			}
+			if location := recorder.Header().Get("Location"); location != tt.expected {
+				t.Errorf("Expected Location %q, got %q", tt.expected, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ImportHandler handles CSV product imports and their mapping profiles
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// Import creates products from an uploaded CSV file, sent either as the multipart
+// field "file" or as a text/csv request body
+func (h *ImportHandler) Import(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var profileID *uuid.UUID
+	if raw := c.Query("profile_id"); raw != "" {
+		id, err := validateUUID(raw)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+			return
+		}
+		profileID = &id
+	}
+
+	var data io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "A CSV file is required in the \"file\" field")
+			return
+		}
+		opened, err := file.Open()
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Failed to read uploaded file")
+			return
+		}
+		defer opened.Close()
+		data = opened
+	}
+
+	validateRow := func(req *domain.CreateProductRequest) error { return validateItem(c, req) }
+	result, err := h.importService.Import(c.Request.Context(), userID, profileID, data, validateRow)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	respondBulk(c, http.StatusCreated, result)
+}
+
+// ListProfiles returns the authenticated user's import profiles
+func (h *ImportHandler) ListProfiles(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	profiles, err := h.importService.ListProfiles(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve import profiles")
+		return
+	}
+
+	c.JSON(http.StatusOK, profiles)
+}
+
+// CreateProfile saves a new import profile; the body is validated by BindJSON
+func (h *ImportHandler) CreateProfile(c *gin.Context) {
+	req := requestBody[domain.ImportProfileRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	profile, err := h.importService.CreateProfile(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// UpdateProfile replaces an import profile; the body is validated by BindJSON
+func (h *ImportHandler) UpdateProfile(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	req := requestBody[domain.ImportProfileRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	profile, err := h.importService.UpdateProfile(c.Request.Context(), userID, id, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// DeleteProfile removes an import profile
+func (h *ImportHandler) DeleteProfile(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.importService.DeleteProfile(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Import profile deleted successfully"})
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// inventorySocketWriteTimeout bounds each message sent to a socket, so a client
+	// that stopped reading is disconnected instead of holding the stream
+	inventorySocketWriteTimeout = 10 * time.Second
+	// inventorySocketMaxMessage is the largest message accepted from a client
+	inventorySocketMaxMessage = 64 << 10
+)
+
+// webSocketRoute is the route serving inventory sockets, see InventorySocketHandler.Serve
+const webSocketRoute = "/api/v1/ws"
+
+// isWebSocket reports whether a request asks to upgrade to a WebSocket
+func isWebSocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// opensWebSocket reports whether a request opens an inventory socket: it asks to
+// upgrade to a WebSocket on webSocketRoute. The Upgrade header alone does not,
+// since clients choose it on any route.
+func opensWebSocket(c *gin.Context) bool {
+	return c.FullPath() == webSocketRoute && isWebSocket(c.Request)
+}
+
+// InventorySocketHandler serves the WebSocket of live inventory dashboards
+type InventorySocketHandler struct {
+	streamService *service.StreamService
+}
+
+// NewInventorySocketHandler creates a new inventory socket handler
+func NewInventorySocketHandler(streamService *service.StreamService) *InventorySocketHandler {
+	return &InventorySocketHandler{
+		streamService: streamService,
+	}
+}
+
+// Serve upgrades the request to a WebSocket sending the stock and price changes
+// of the user's products as they happen: of every product, until the client
+// subscribes to specific ones
+func (h *InventorySocketHandler) Serve(c *gin.Context) {
+	if !isWebSocket(c.Request) {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "This endpoint only accepts WebSocket upgrades")
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	events, err := h.streamService.Subscribe(ctx, userID)
+	if err != nil {
+		respondError(c, http.StatusServiceUnavailable, domain.CodeUnavailable, err)
+		return
+	}
+
+	// Tokens, not cookies, authenticate the socket, so other origins gain nothing
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			conn.MaxPayloadBytes = inventorySocketMaxMessage
+			serveInventorySocket(ctx, conn, events)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// serveInventorySocket relays stock and price changes to the socket and applies
+// the client's subscriptions until either side closes
+func serveInventorySocket(ctx context.Context, conn *websocket.Conn, events <-chan domain.StreamEvent) {
+	defer conn.Close()
+
+	subscriptions := make(chan domain.InventorySubscription)
+	go func() {
+		defer close(subscriptions)
+		for {
+			var subscription domain.InventorySubscription
+			if err := websocket.JSON.Receive(conn, &subscription); err != nil {
+				return
+			}
+			select {
+			case subscriptions <- subscription:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	send := func(message domain.InventoryMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(inventorySocketWriteTimeout))
+		return websocket.JSON.Send(conn, message) == nil
+	}
+
+	watched := map[uuid.UUID]bool{}
+	heartbeat := time.NewTicker(StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		var message domain.InventoryMessage
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			update, relevant, err := domain.NewInventoryMessage(event)
+			if err != nil || !relevant || (len(watched) > 0 && !watched[*update.ProductID]) {
+				continue
+			}
+			message = update
+		case subscription, ok := <-subscriptions:
+			if !ok {
+				return
+			}
+			message = applyInventorySubscription(watched, subscription)
+		case <-heartbeat.C:
+			message = domain.InventoryMessage{Type: domain.InventoryMessageHeartbeat}
+		}
+
+		if !send(message) {
+			return
+		}
+	}
+}
+
+// applyInventorySubscription updates the watched products and returns the reply:
+// the products now watched, or why the subscription was refused
+func applyInventorySubscription(watched map[uuid.UUID]bool, subscription domain.InventorySubscription) domain.InventoryMessage {
+	switch subscription.Action {
+	case domain.InventorySubscribe:
+		added := 0
+		for _, id := range subscription.ProductIDs {
+			if !watched[id] {
+				added++
+			}
+		}
+		if len(watched)+added > domain.MaxWatchedProducts {
+			return domain.InventoryMessage{
+				Type:    domain.InventoryMessageError,
+				Message: fmt.Sprintf("at most %d products can be watched", domain.MaxWatchedProducts),
+			}
+		}
+		for _, id := range subscription.ProductIDs {
+			watched[id] = true
+		}
+	case domain.InventoryUnsubscribe:
+		for _, id := range subscription.ProductIDs {
+			delete(watched, id)
+		}
+	default:
+		return domain.InventoryMessage{
+			Type:    domain.InventoryMessageError,
+			Message: fmt.Sprintf("unknown action %q; use %q or %q", subscription.Action, domain.InventorySubscribe, domain.InventoryUnsubscribe),
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(watched))
+	for id := range watched {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	return domain.InventoryMessage{Type: domain.InventoryMessageSubscribed, ProductIDs: ids}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"products/internal/service/memorycache"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+func TestInventorySocketHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	streamService := service.NewStreamService(memorycache.New(), nil)
+	socketHandler := NewInventorySocketHandler(streamService)
+
+	router := gin.New()
+	router.Use(TimeoutMiddleware(50 * time.Millisecond))
+	router.GET(webSocketRoute, func(c *gin.Context) { c.Set("user_id", userID) }, socketHandler.Serve)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+webSocketRoute, "", server.URL)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	watchedID, otherID := uuid.New(), uuid.New()
+	if err := websocket.JSON.Send(conn, domain.InventorySubscription{Action: domain.InventorySubscribe, ProductIDs: []uuid.UUID{watchedID}}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	var reply domain.InventoryMessage
+	if err := websocket.JSON.Receive(conn, &reply); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if reply.Type != domain.InventoryMessageSubscribed || len(reply.ProductIDs) != 1 || reply.ProductIDs[0] != watchedID {
+		t.Fatalf("Unexpected subscription reply %+v", reply)
+	}
+
+	// Outlive the request timeout, which sockets are exempt from
+	time.Sleep(100 * time.Millisecond)
+	ctx := context.Background()
+	publish := func(eventType string, id uuid.UUID, stock int) {
+		event, err := domain.NewProductEvent(eventType, &domain.Product{ID: id, UserID: userID, Stock: stock, Price: 9.5}, &domain.Product{ID: id, Stock: 1})
+		if err != nil {
+			t.Fatalf("Failed to create event: %v", err)
+		}
+		event.Sequence = int64(stock)
+		streamService.PublishEvent(ctx, event)
+	}
+	publish(domain.EventProductStockChanged, otherID, 5)
+	publish(domain.EventProductUpdated, watchedID, 6)
+	publish(domain.EventStockAdjusted, watchedID, 7)
+
+	var update domain.InventoryMessage
+	if err := websocket.JSON.Receive(conn, &update); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if update.Type != domain.EventProductStockChanged || *update.ProductID != watchedID || *update.Stock != 7 || *update.PreviousStock != 1 {
+		t.Errorf("Expected the quick stock update of the watched product, got %+v", update)
+	}
+}
+
+func TestApplyInventorySubscription(t *testing.T) {
+	watched := map[uuid.UUID]bool{}
+	id := uuid.New()
+
+	reply := applyInventorySubscription(watched, domain.InventorySubscription{Action: domain.InventorySubscribe, ProductIDs: []uuid.UUID{id}})
+	if reply.Type != domain.InventoryMessageSubscribed || !watched[id] {
+		t.Errorf("Expected the product watched, got %+v", reply)
+	}
+
+	reply = applyInventorySubscription(watched, domain.InventorySubscription{Action: domain.InventoryUnsubscribe, ProductIDs: []uuid.UUID{id}})
+	if reply.Type != domain.InventoryMessageSubscribed || len(watched) != 0 {
+		t.Errorf("Expected no product watched, got %+v", reply)
+	}
+
+	reply = applyInventorySubscription(watched, domain.InventorySubscription{Action: "watch"})
+	if reply.Type != domain.InventoryMessageError {
+		t.Errorf("Expected an error for an unknown action, got %+v", reply)
+	}
+
+	tooMany := make([]uuid.UUID, domain.MaxWatchedProducts+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+	reply = applyInventorySubscription(watched, domain.InventorySubscription{Action: domain.InventorySubscribe, ProductIDs: tooMany})
+	if reply.Type != domain.InventoryMessageError || len(watched) != 0 {
+		t.Errorf("Expected the subscription refused, got %+v", reply)
+	}
+}
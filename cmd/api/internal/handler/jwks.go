@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys of the RS256 signing keys so that other
+// services can validate access tokens. Retired keys stay listed until they are
+// removed from the configuration.
+func JWKSHandler(signingKeys *service.SigningKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, signingKeys.JWKS())
+	}
+}
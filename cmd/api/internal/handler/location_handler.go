@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LocationHandler handles location and per-location stock HTTP requests
+type LocationHandler struct {
+	locationService *service.LocationService
+}
+
+// NewLocationHandler creates a new location handler
+func NewLocationHandler(locationService *service.LocationService) *LocationHandler {
+	return &LocationHandler{
+		locationService: locationService,
+	}
+}
+
+// List returns the authenticated user's locations by name
+func (h *LocationHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	locations, err := h.locationService.List(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve locations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"locations": locations})
+}
+
+// Create adds a location; the body is validated by BindJSON
+func (h *LocationHandler) Create(c *gin.Context) {
+	req := requestBody[domain.LocationRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	location, err := h.locationService.Create(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, location)
+}
+
+// Get returns one of the authenticated user's locations
+func (h *LocationHandler) Get(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	location, err := h.locationService.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// Update replaces a location's details; the body is validated by BindJSON
+func (h *LocationHandler) Update(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.LocationRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	location, err := h.locationService.Update(c.Request.Context(), userID, id, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// Delete removes a location that holds no stock
+func (h *LocationHandler) Delete(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.locationService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Location deleted successfully"})
+}
+
+// Stats returns statistics of the stock kept at a location
+func (h *LocationHandler) Stats(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	stats, err := h.locationService.Stats(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// ProductLocations returns where a product's stock is kept
+func (h *LocationHandler) ProductLocations(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	locations, err := h.locationService.ProductLocations(c.Request.Context(), productID, userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// AdjustStock changes a product's stock at a location; the body is validated by BindJSON
+func (h *LocationHandler) AdjustStock(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	locationID, err := validateUUID(c.Param("location_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.LocationStockAdjustRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	stock, err := h.locationService.AdjustStock(c.Request.Context(), productID, userID, locationID, req.Delta)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stock)
+}
+
+// locationFilter parses the optional location_id query parameter, responding with
+// 400 when it is invalid
+func locationFilter(c *gin.Context) (*uuid.UUID, bool) {
+	locationStr := c.Query("location_id")
+	if locationStr == "" {
+		return nil, true
+	}
+	locationID, err := validateUUID(locationStr)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "location_id must be a valid UUID")
+		return nil, false
+	}
+	return &locationID, true
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LoginDeviceHandler handles the devices users log in from
+type LoginDeviceHandler struct {
+	loginDeviceService *service.LoginDeviceService
+}
+
+// NewLoginDeviceHandler creates a new login device handler
+func NewLoginDeviceHandler(loginDeviceService *service.LoginDeviceService) *LoginDeviceHandler {
+	return &LoginDeviceHandler{
+		loginDeviceService: loginDeviceService,
+	}
+}
+
+// List returns the authenticated user's login devices, marking the current one
+func (h *LoginDeviceHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := c.MustGet("session_id").(string)
+
+	devices, err := h.loginDeviceService.List(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve devices")
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// Update renames a login device or changes whether it is trusted; the body is
+// validated by BindJSON
+func (h *LoginDeviceHandler) Update(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	req := requestBody[domain.LoginDeviceRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	device, err := h.loginDeviceService.Update(c.Request.Context(), userID, id, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// Delete forgets a login device and logs out its sessions
+func (h *LoginDeviceHandler) Delete(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.loginDeviceService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device deleted successfully"})
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LoginHistoryHandler handles users' login history
+type LoginHistoryHandler struct {
+	loginHistoryService *service.LoginHistoryService
+}
+
+// NewLoginHistoryHandler creates a new login history handler
+func NewLoginHistoryHandler(loginHistoryService *service.LoginHistoryService) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		loginHistoryService: loginHistoryService,
+	}
+}
+
+// List returns a page of the authenticated user's logins, most recent first
+func (h *LoginHistoryHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	pagination := domain.Pagination{
+		Page:     1,
+		PageSize: defaultPageSize(c),
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			pagination.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			pagination.PageSize = pageSize
+		}
+	}
+
+	history, err := h.loginHistoryService.History(c.Request.Context(), userID, pagination)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve login history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
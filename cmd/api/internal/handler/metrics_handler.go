@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MetricsHandler handles archived statistics requests
+type MetricsHandler struct {
+	metricsService *service.MetricsService
+}
+
+// NewMetricsHandler creates a new metrics handler
+func NewMetricsHandler(metricsService *service.MetricsService) *MetricsHandler {
+	return &MetricsHandler{
+		metricsService: metricsService,
+	}
+}
+
+// GetStatsHistory returns daily (recent) and monthly (older) statistics snapshots
+func (h *MetricsHandler) GetStatsHistory(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid 'from' date, expected RFC3339")
+			return
+		}
+		from = parsed
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid 'to' date, expected RFC3339")
+			return
+		}
+		to = parsed
+	}
+
+	history, err := h.metricsService.GetHistory(c.Request.Context(), userID, from, to)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
@@ -1,38 +1,39 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"products/cmd/api/internal/i18n"
 	"products/internal/domain"
 	"products/internal/service"
 )
 
 // AuthMiddleware validates JWT tokens and sets user context
-func AuthMiddleware(userService *service.UserService, jwtSecret string) gin.HandlerFunc {
+func AuthMiddleware(userService *service.UserService, signingKeys *service.SigningKeys) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get Authorization header
+		// Get Authorization header; browsers cannot set it on WebSocket upgrades,
+		// which may pass the token as a query parameter to the socket route instead
 		authHeader := c.GetHeader("Authorization")
+		if token := c.Query("access_token"); authHeader == "" && token != "" && opensWebSocket(c) {
+			authHeader = "Bearer " + token
+		}
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Authorization header is required",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Authorization header is required")
 			return
 		}
 
 		// Check if header starts with "Bearer "
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Invalid authorization header format",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Invalid authorization header format")
 			return
 		}
 
@@ -40,97 +41,62 @@ func AuthMiddleware(userService *service.UserService, jwtSecret string) gin.Hand
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			return []byte(jwtSecret), nil
-		})
+		token, err := signingKeys.Parse(tokenString)
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Invalid or expired token",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Invalid or expired token")
 			return
 		}
 
 		// Extract claims
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Invalid token claims",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Invalid token claims")
 			return
 		}
 
 		// Extract user ID and session ID
 		userIDStr, ok := claims["user_id"].(string)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Invalid user ID in token",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Invalid user ID in token")
 			return
 		}
 
 		sessionID, ok := claims["session_id"].(string)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Invalid session ID in token",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Invalid session ID in token")
 			return
 		}
 
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Invalid user ID format",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Invalid user ID format")
 			return
 		}
 
 		// Validate session is still active
 		isValid, err := userService.ValidateSession(c.Request.Context(), sessionID)
 		if err != nil || !isValid {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Session expired or invalid",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Session expired or invalid")
 			return
 		}
 
 		// Check if token is blacklisted
 		isBlacklisted, err := userService.IsTokenBlacklisted(c.Request.Context(), tokenString)
 		if err != nil || isBlacklisted {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Token has been invalidated",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Token has been invalidated")
 			return
 		}
 
 		// Check if user's session has been blacklisted by logout all
 		isUserBlacklisted, err := userService.IsUserSessionBlacklisted(c.Request.Context(), userID, sessionID)
 		if err != nil || isUserBlacklisted {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-				Error:   "Unauthorized",
-				Message: "Session has been invalidated by logout all",
-			})
-			c.Abort()
+			respondProblem(c, http.StatusUnauthorized, domain.CodeUnauthorized, "Session has been invalidated by logout all")
 			return
 		}
 
+		userService.RecordSessionActivity(c.Request.Context(), sessionID)
+
 		// Set user ID, session ID, and token in context
 		c.Set("user_id", userID)
 		c.Set("session_id", sessionID)
@@ -138,3 +104,216 @@ func AuthMiddleware(userService *service.UserService, jwtSecret string) gin.Hand
 		c.Next()
 	}
 }
+
+// AdminMiddleware restricts access to users with the admin role.
+// It must run after AuthMiddleware.
+func AdminMiddleware(userService *service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("user_id").(uuid.UUID)
+
+		user, err := userService.GetByID(c.Request.Context(), userID)
+		if err != nil || user.Role != domain.RoleAdmin {
+			respondProblem(c, http.StatusForbidden, domain.CodeForbidden, "Admin privileges are required")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// QuotaWarningMiddleware adds a Warning header when the user is near or over the product quota.
+// It must run after AuthMiddleware.
+func QuotaWarningMiddleware(quotaService *service.QuotaService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("user_id").(uuid.UUID)
+
+		status, err := quotaService.Status(c.Request.Context(), userID)
+		if err == nil && status.Warning {
+			message := fmt.Sprintf("Product quota %.0f%% used (%d/%d)", status.Percent, status.Used, status.Limit)
+			if status.GraceEndsAt != nil {
+				message += fmt.Sprintf("; grace period ends %s", status.GraceEndsAt.UTC().Format(time.RFC3339))
+			}
+			c.Writer.Header().Add("Warning", fmt.Sprintf("299 - %q", message))
+		}
+
+		c.Next()
+	}
+}
+
+// PageSizeMiddleware sets the default_page_size of GET requests without a
+// page_size parameter from the user's settings, see defaultPageSize. It must run
+// after AuthMiddleware.
+func PageSizeMiddleware(onboardingService *service.OnboardingService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet && c.Query("page_size") == "" {
+			userID := c.MustGet("user_id").(uuid.UUID)
+			c.Set("default_page_size", onboardingService.DefaultPageSize(c.Request.Context(), userID))
+		}
+
+		c.Next()
+	}
+}
+
+// PriceFormatMiddleware sets the price_format of GET and batch-get requests with
+// ?price_format=locale from the user's currency and locale settings, see
+// priceFormat, responding with 400 to other values than locale and raw. It must run
+// after AuthMiddleware.
+func PriceFormatMiddleware(onboardingService *service.OnboardingService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.FullPath() == batchGetRoute {
+			switch c.Query("price_format") {
+			case "", priceFormatRaw:
+			case priceFormatLocale:
+				preferences := onboardingService.CachedPreferences(c.Request.Context(), c.MustGet("user_id").(uuid.UUID))
+				c.Set("price_format", i18n.NewPriceFormat(preferences.Locale, preferences.Currency))
+			default:
+				respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "price_format must be raw or locale")
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// defaultPageSize returns the page size of a list requested without page_size
+func defaultPageSize(c *gin.Context) int {
+	if size := c.GetInt("default_page_size"); size > 0 {
+		return size
+	}
+	return domain.DefaultListPageSize
+}
+
+// UsageMiddleware counts the user's requests and the body bytes they send and
+// receive. It must run after AuthMiddleware.
+func UsageMiddleware(usageService *service.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		userID := c.MustGet("user_id").(uuid.UUID)
+		usageService.Record(userID, c.Request.ContentLength, int64(c.Writer.Size()))
+	}
+}
+
+// ProductCodeMiddleware lets the :id parameter of product routes be a product's
+// short code instead of its UUID, replacing the code with the ID it resolves to.
+// It must run after AuthMiddleware.
+func ProductCodeMiddleware(productService *service.ProductService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		param := c.Param("id")
+		if _, err := uuid.Parse(param); err == nil {
+			c.Next()
+			return
+		}
+
+		userID := c.MustGet("user_id").(uuid.UUID)
+		id, err := productService.ResolveCode(c.Request.Context(), userID, param)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+			return
+		}
+
+		for i := range c.Params {
+			if c.Params[i].Key == "id" {
+				c.Params[i].Value = id.String()
+			}
+		}
+		c.Next()
+	}
+}
+
+// requestTimeoutDetail is the detail of responses to requests that ran out of time
+const requestTimeoutDetail = "The request took too long to complete; please retry"
+
+// TimeoutMiddleware gives each request a deadline of timeout, which database and
+// Redis calls made with the request context honor. A request whose deadline
+// passes before a response is written is answered with 504; a timeout of 0
+// disables the deadline, and requests opening an event stream or a WebSocket have
+// none. It must run after ErrorMiddleware.
+func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || opensEventStream(c) || opensWebSocket(c) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			respondProblem(c, http.StatusGatewayTimeout, domain.CodeTimeout, requestTimeoutDetail)
+		}
+	}
+}
+
+// ReadOnlyMiddleware rejects every request that could modify data with 503, for
+// maintenance windows in which the instance is served from a read replica.
+// It must run after ErrorMiddleware.
+func ReadOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		respondProblem(c, http.StatusServiceUnavailable, domain.CodeReadOnly, "The API is in read-only maintenance mode; changes are temporarily unavailable")
+	}
+}
+
+// RateLimitMiddleware limits requests per client IP, failing open when the limiter is unavailable
+func RateLimitMiddleware(limiter *service.RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, remaining, reset, err := limiter.Allow(c.Request.Context(), c.ClientIP())
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.FormatInt(limiter.Limit(), 10))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Seconds())+1))
+			respondProblem(c, http.StatusTooManyRequests, domain.CodeRateLimited, "Rate limit exceeded, please retry later")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PlanRateLimitMiddleware limits each user's requests per window to the rate limit
+// of the user's plan, failing open when the plan or limiter is unavailable. It
+// must run after AuthMiddleware.
+func PlanRateLimitMiddleware(planService *service.PlanService, limiter *service.RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("user_id").(uuid.UUID)
+
+		plan, err := planService.ForUser(c.Request.Context(), userID)
+		if err != nil || plan.RateLimit <= 0 {
+			c.Next()
+			return
+		}
+
+		allowed, remaining, reset, err := limiter.AllowLimit(c.Request.Context(), userID.String(), plan.RateLimit)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.FormatInt(plan.RateLimit, 10))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Seconds())+1))
+			respondProblem(c, http.StatusTooManyRequests, domain.CodeRateLimited, fmt.Sprintf("Rate limit of the %s plan exceeded, please retry later", plan.Name))
+			return
+		}
+
+		c.Next()
+	}
+}
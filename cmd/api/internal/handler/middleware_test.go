@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorMiddleware(), ReadOnlyMiddleware())
+	router.GET("/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/products", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.DELETE("/products/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/products", http.StatusOK},
+		{http.MethodPost, "/products", http.StatusServiceUnavailable},
+		{http.MethodDelete, "/products/1", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+		if recorder.Code != tt.want {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.want, recorder.Code)
+		}
+		if tt.want == http.StatusServiceUnavailable && !strings.Contains(recorder.Body.String(), `"code":"`+domain.CodeReadOnly+`"`) {
+			t.Errorf("%s %s: expected code %s, got %s", tt.method, tt.path, domain.CodeReadOnly, recorder.Body.String())
+		}
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// waitForDeadline blocks like a database call until the request context is done
+	waitForDeadline := func(c *gin.Context) error {
+		<-c.Request.Context().Done()
+		return c.Request.Context().Err()
+	}
+
+	router := gin.New()
+	router.Use(ErrorMiddleware(), TimeoutMiddleware(10*time.Millisecond))
+	router.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/failed", func(c *gin.Context) {
+		err := waitForDeadline(c)
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, errors.Join(errors.New("query failed"), err))
+	})
+	router.GET("/silent", func(c *gin.Context) {
+		if errors.Is(waitForDeadline(c), context.DeadlineExceeded) {
+			c.Error(errors.New("gave up"))
+		}
+	})
+	// Event streams have no deadline, so the stream ends when the client leaves
+	router.GET(eventStreamRoute, func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); !ok {
+			c.Status(http.StatusOK)
+			return
+		}
+		if errors.Is(waitForDeadline(c), context.DeadlineExceeded) {
+			c.Error(errors.New("gave up"))
+		}
+	})
+
+	tests := []struct {
+		path    string
+		accept  string
+		upgrade string
+		want    int
+	}{
+		{"/fast", "", "", http.StatusOK},
+		{"/failed", "", "", http.StatusGatewayTimeout},
+		{"/silent", "", "", http.StatusGatewayTimeout},
+		{"/silent", "text/event-stream", "", http.StatusGatewayTimeout},
+		{"/silent", "", "websocket", http.StatusGatewayTimeout},
+		{eventStreamRoute, "text/event-stream", "", http.StatusOK},
+		{eventStreamRoute, "", "", http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.accept != "" {
+			request.Header.Set("Accept", tt.accept)
+		}
+		if tt.upgrade != "" {
+			request.Header.Set("Upgrade", tt.upgrade)
+		}
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != tt.want {
+			t.Errorf("GET %s (Accept %q, Upgrade %q): expected status %d, got %d", tt.path, tt.accept, tt.upgrade, tt.want, recorder.Code)
+		}
+		if tt.want == http.StatusGatewayTimeout && !strings.Contains(recorder.Body.String(), `"code":"`+domain.CodeTimeout+`"`) {
+			t.Errorf("GET %s: expected code %s, got %s", tt.path, domain.CodeTimeout, recorder.Body.String())
+		}
+	}
+}
+
+func TestAuthMiddleware_QueryTokenOnlyOnSocketRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorMiddleware(), AuthMiddleware(nil, nil))
+	router.GET("/api/v1/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/products?access_token=abc", nil)
+	request.Header.Set("Upgrade", "websocket")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusUnauthorized || !strings.Contains(recorder.Body.String(), "Authorization header is required") {
+		t.Errorf("Expected the query token to be ignored, got %d %s", recorder.Code, recorder.Body.String())
+	}
+}
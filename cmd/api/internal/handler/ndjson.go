@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// ndjsonContentType is the media type of newline-delimited JSON responses
+	ndjsonContentType = "application/x-ndjson"
+	// ndjsonFlushEvery is the number of lines written between flushes
+	ndjsonFlushEvery = 100
+)
+
+// wantsNDJSON reports whether a request accepts newline-delimited JSON
+func wantsNDJSON(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), ndjsonContentType)
+}
+
+// streamProducts writes every one of the user's products matching the query as a
+// line of JSON while they are read from the database, instead of a page of them.
+// Once lines were sent, a failure is reported by a last line holding the problem.
+func (h *ProductHandler) streamProducts(c *gin.Context, userID uuid.UUID, query domain.ProductQuery) {
+	html := wantsHTMLDescriptions(c)
+	format := priceFormat(c)
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+
+	start := func() {
+		c.Header("Content-Type", ndjsonContentType)
+		c.Status(http.StatusOK)
+	}
+
+	err := h.productService.StreamProducts(c.Request.Context(), userID, query, func(product *domain.Product) error {
+		if written == 0 {
+			start()
+		}
+		if html {
+			product.Description = renderedDescription(product.Description, product.DescriptionHTML)
+		}
+		if format != nil {
+			product.PriceFormatted = format.Format(product.Price)
+		}
+		if err := encoder.Encode(product); err != nil {
+			return err
+		}
+		written++
+		if written == 1 || written%ndjsonFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		if written == 0 {
+			start()
+			c.Writer.WriteHeaderNow()
+		}
+	case written > 0:
+		encoder.Encode(gin.H{"error": Problem{
+			Type:      problemType(domain.CodeInternal),
+			Title:     http.StatusText(http.StatusInternalServerError),
+			Status:    http.StatusInternalServerError,
+			Detail:    "Failed to retrieve all products; the list is incomplete",
+			Instance:  c.Request.URL.Path,
+			Code:      domain.CodeInternal,
+			RequestID: c.GetString("request_id"),
+		}})
+	case errors.Is(err, service.ErrInvalidFilter):
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+	default:
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve products")
+	}
+}
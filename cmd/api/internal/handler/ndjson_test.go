@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"products/internal/domain"
+	"products/internal/repository/memory"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestProductHandler_StreamNDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := memory.NewProductRepository(memory.NewStore())
+	for i := 0; i < 3; i++ {
+		if err := repo.Create(ctx, &domain.Product{ID: uuid.New(), UserID: userID, Name: fmt.Sprintf("Product %d", i), Stock: i}); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+	productHandler := NewProductHandler(service.NewProductService(repo, nil, nil, nil, service.DefaultProductAuthorizer(), nil))
+
+	router := gin.New()
+	router.Use(DeprecationMiddleware(), ErrorMiddleware())
+	router.GET("/api/v1/products", func(c *gin.Context) { c.Set("user_id", userID) }, productHandler.GetAllByUser)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/products?page_size=1&sort_field=stock&sort_direction=desc", nil)
+	request.Header.Set("Accept", "application/x-ndjson")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("Expected an NDJSON response, got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	if recorder.Header().Get("Warning") == "" {
+		t.Error("Expected the deprecations reported in a Warning header")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(recorder.Body.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected every product on its own line regardless of page_size, got %q", recorder.Body.String())
+	}
+	for i, line := range lines {
+		var product domain.Product
+		if err := json.Unmarshal([]byte(line), &product); err != nil {
+			t.Fatalf("Line %d is not a product: %v", i, err)
+		}
+		if product.Stock != 2-i {
+			t.Errorf("Expected products sorted by stock descending, got stock %d on line %d", product.Stock, i)
+		}
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/api/v1/products?status=draft", nil)
+	request.Header.Set("Accept", "application/x-ndjson")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Body.Len() != 0 {
+		t.Errorf("Expected an empty NDJSON response, got %d %q", recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NoteHandler handles internal product note HTTP requests
+type NoteHandler struct {
+	noteService *service.NoteService
+}
+
+// NewNoteHandler creates a new note handler
+func NewNoteHandler(noteService *service.NoteService) *NoteHandler {
+	return &NoteHandler{
+		noteService: noteService,
+	}
+}
+
+// List returns a page of a product's notes, oldest first
+func (h *NoteHandler) List(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	notes, err := h.noteService.List(c.Request.Context(), productID, userID, pageQuery(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// Create adds a note to a product's thread; the body is validated by BindJSON
+func (h *NoteHandler) Create(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.NoteRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	note, err := h.noteService.Create(c.Request.Context(), productID, userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// Update edits a note the user wrote; the body is validated by BindJSON
+func (h *NoteHandler) Update(c *gin.Context) {
+	productID, noteID, ok := noteParams(c)
+	if !ok {
+		return
+	}
+	req := requestBody[domain.NoteRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	note, err := h.noteService.Update(c.Request.Context(), productID, noteID, userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// Delete removes a note the user wrote
+func (h *NoteHandler) Delete(c *gin.Context) {
+	productID, noteID, ok := noteParams(c)
+	if !ok {
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.noteService.Delete(c.Request.Context(), productID, noteID, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Note deleted successfully"})
+}
+
+// noteParams parses the product and note IDs of the request, responding with 400
+// when either is invalid
+func noteParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	noteID, err := validateUUID(c.Param("note_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return productID, noteID, true
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OnboardingHandler handles guided setup HTTP requests
+type OnboardingHandler struct {
+	onboardingService *service.OnboardingService
+}
+
+// NewOnboardingHandler creates a new onboarding handler
+func NewOnboardingHandler(onboardingService *service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboardingService: onboardingService,
+	}
+}
+
+// Get returns the authenticated user's onboarding state and next step
+func (h *OnboardingHandler) Get(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	onboarding, err := h.onboardingService.Get(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve onboarding state")
+		return
+	}
+
+	c.JSON(http.StatusOK, onboarding)
+}
+
+// RequestEmailVerification sends a verification email to the authenticated user
+func (h *OnboardingHandler) RequestEmailVerification(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.onboardingService.RequestEmailVerification(c.Request.Context(), userID); err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+// VerifyEmail confirms an email address using a token from the verification email
+func (h *OnboardingHandler) VerifyEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	if err := h.onboardingService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// GetPreferences returns the authenticated user's preferences
+func (h *OnboardingHandler) GetPreferences(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	preferences, err := h.onboardingService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdatePreferences stores the authenticated user's preferences
+func (h *OnboardingHandler) UpdatePreferences(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req domain.PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	preferences, err := h.onboardingService.UpdatePreferences(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to update preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// GetSettings exports all of the authenticated user's settings
+func (h *OnboardingHandler) GetSettings(c *gin.Context) {
+	h.GetPreferences(c)
+}
+
+// UpdateSettings replaces all of the authenticated user's settings; the body is
+// validated by BindJSON
+func (h *OnboardingHandler) UpdateSettings(c *gin.Context) {
+	req := requestBody[domain.UserSettingsRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	settings, err := h.onboardingService.UpdateSettings(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
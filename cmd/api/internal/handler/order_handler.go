@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OrderHandler handles order HTTP requests
+type OrderHandler struct {
+	orderService *service.OrderService
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+// Create places an order, taking its quantities from stock; the body is validated by BindJSON
+func (h *OrderHandler) Create(c *gin.Context) {
+	req := requestBody[domain.CreateOrderRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	order, err := h.orderService.Create(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// List returns a page of the authenticated user's orders, newest first
+func (h *OrderHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	query := domain.OrderQuery{
+		Pagination: domain.Pagination{
+			Page:     1,
+			PageSize: defaultPageSize(c),
+		},
+		Status: c.Query("status"),
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			query.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			query.PageSize = pageSize
+		}
+	}
+
+	if query.Status != "" && query.Status != domain.OrderStatusPlaced && query.Status != domain.OrderStatusCancelled {
+		respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "status must be placed or cancelled")
+		return
+	}
+
+	orders, err := h.orderService.List(c.Request.Context(), userID, query)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// Get returns one of the authenticated user's orders
+func (h *OrderHandler) Get(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	order, err := h.orderService.Get(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// Cancel cancels an order, putting its quantities back in stock
+func (h *OrderHandler) Cancel(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	order, err := h.orderService.Cancel(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PlanHandler handles subscription plans
+type PlanHandler struct {
+	planService  *service.PlanService
+	quotaService *service.QuotaService
+}
+
+// NewPlanHandler creates a new plan handler
+func NewPlanHandler(planService *service.PlanService, quotaService *service.QuotaService) *PlanHandler {
+	return &PlanHandler{
+		planService:  planService,
+		quotaService: quotaService,
+	}
+}
+
+// List returns the plans and their limits
+func (h *PlanHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, h.planService.List())
+}
+
+// SetUserPlan moves a user to another plan, for administrators; the body is
+// validated by BindJSON
+func (h *PlanHandler) SetUserPlan(c *gin.Context) {
+	userID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.UserPlanRequest](c)
+
+	user, err := h.planService.SetUserPlan(c.Request.Context(), userID, req.Plan)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	// A larger quota may end the user's grace period
+	h.quotaService.Refresh(c.Request.Context(), userID)
+
+	c.JSON(http.StatusOK, domain.NewUserResponse(user))
+}
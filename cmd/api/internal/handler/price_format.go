@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"products/cmd/api/internal/i18n"
+	"products/internal/domain"
+)
+
+// ?price_format= values: raw numbers only, the default, or raw numbers alongside
+// prices formatted for the user's locale and currency
+const (
+	priceFormatRaw    = "raw"
+	priceFormatLocale = "locale"
+)
+
+// batchGetRoute is the route of BatchGet, which reads products with a POST
+const batchGetRoute = "/api/v1/products/batch-get"
+
+// priceFormat returns the format of prices the client asked for with
+// ?price_format=locale, or nil; see PriceFormatMiddleware
+func priceFormat(c *gin.Context) *i18n.PriceFormat {
+	if format, ok := c.Get("price_format"); ok {
+		return format.(*i18n.PriceFormat)
+	}
+	return nil
+}
+
+// formattedPriceProducts returns copies of products with their price formatted;
+// the originals may be shared with other requests and are left untouched
+func formattedPriceProducts(products []domain.Product, format *i18n.PriceFormat) []domain.Product {
+	result := make([]domain.Product, len(products))
+	for i, product := range products {
+		product.PriceFormatted = format.Format(product.Price)
+		result[i] = product
+	}
+	return result
+}
+
+// formattedProductList is a page of products with formatted prices and the
+// format's metadata
+type formattedProductList struct {
+	*domain.ProductListResponse
+	PriceFormat *i18n.PriceFormat `json:"price_format"`
+}
+
+// formattedProductCursorList is formattedProductList for cursor pages
+type formattedProductCursorList struct {
+	*domain.ProductListCursorResponse
+	PriceFormat *i18n.PriceFormat `json:"price_format"`
+}
+
+// formattedBatchGetProductResponse is formattedProductList for fetches by ID
+type formattedBatchGetProductResponse struct {
+	*domain.BatchGetProductResponse
+	PriceFormat *i18n.PriceFormat `json:"price_format"`
+}
+
+// formattedProductV2List is formattedProductList for v2 pages
+type formattedProductV2List struct {
+	domain.ProductV2ListResponse
+	PriceFormat *i18n.PriceFormat `json:"price_format"`
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"products/cmd/api/internal/i18n"
+	"products/internal/domain"
+)
+
+func TestFormattedProductList(t *testing.T) {
+	products := []domain.Product{{Price: 1234.5}, {Price: 0}}
+	format := i18n.NewPriceFormat("de", "EUR")
+
+	response := &domain.ProductListResponse{Products: formattedPriceProducts(products, format), Page: 1}
+	data, err := json.Marshal(formattedProductList{response, format})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded struct {
+		Products []struct {
+			Price          float64 `json:"price"`
+			PriceFormatted string  `json:"price_formatted"`
+		} `json:"products"`
+		Page        int `json:"page"`
+		PriceFormat struct {
+			Currency      string `json:"currency"`
+			DecimalPlaces int    `json:"decimal_places"`
+			Locale        string `json:"locale"`
+		} `json:"price_format"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded.Products) != 2 || decoded.Products[0].Price != 1234.5 || decoded.Products[0].PriceFormatted != "€ 1.234,50" || decoded.Products[1].PriceFormatted != "€ 0,00" {
+		t.Errorf("Expected raw and formatted prices, got %s", data)
+	}
+	if decoded.Page != 1 || decoded.PriceFormat.Currency != "EUR" || decoded.PriceFormat.DecimalPlaces != 2 || decoded.PriceFormat.Locale != "de" {
+		t.Errorf("Expected the list and format metadata, got %s", data)
+	}
+	if products[0].PriceFormatted != "" {
+		t.Error("Expected the original products to be left untouched")
+	}
+}
+
+func TestFormattedProductV2List(t *testing.T) {
+	format := i18n.NewPriceFormat("en", "USD")
+	list := &domain.ProductListResponse{Products: formattedPriceProducts([]domain.Product{{Price: 19.99}}, format), Page: 1}
+
+	data, err := json.Marshal(formattedProductV2List{domain.NewProductV2ListResponse(list), format})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded struct {
+		Products []struct {
+			PriceCents     int64  `json:"price_cents"`
+			PriceFormatted string `json:"price_formatted"`
+		} `json:"products"`
+		PriceFormat struct {
+			Currency string `json:"currency"`
+		} `json:"price_format"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded.Products) != 1 || decoded.Products[0].PriceCents != 1999 || decoded.Products[0].PriceFormatted != "$ 19.99" || decoded.PriceFormat.Currency != "USD" {
+		t.Errorf("Expected v2 cents, formatted prices and format metadata, got %s", data)
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// problemContentType is the media type of RFC 7807 error responses
+const problemContentType = "application/problem+json"
+
+// Problem represents an RFC 7807 problem details response
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Errors   []domain.FieldError `json:"errors,omitempty"`
+	Conflict *domain.ConflictDetails `json:"conflict,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// problemError is an error queued on the gin context for ErrorMiddleware to render
+type problemError struct {
+	status int
+	code   string
+	detail string
+	errors []domain.FieldError
+	conflict *domain.ConflictDetails
+
+	// cause is the error behind a server error, for the error reporter
+	cause error
+	stack []uintptr
+}
+
+func (e *problemError) Error() string {
+	return e.detail
+}
+
+// codeStatus maps typed domain error codes to HTTP statuses
+var codeStatus = map[string]int{
+	domain.CodeBadRequest:           http.StatusBadRequest,
+	domain.CodeValidationFailed:     http.StatusBadRequest,
+	domain.CodeInvalidInput:         http.StatusBadRequest,
+	domain.CodeInvalidFilter:        http.StatusBadRequest,
+	domain.CodeOperationFailed:      http.StatusBadRequest,
+	domain.CodeUnauthorized:         http.StatusUnauthorized,
+	domain.CodeAuthenticationFailed: http.StatusUnauthorized,
+	domain.CodeCaptchaRequired:      http.StatusUnauthorized,
+	domain.CodeDeviceVerification:   http.StatusUnauthorized,
+	domain.CodeSessionLimit:         http.StatusConflict,
+	domain.CodeInvalidToken:         http.StatusUnauthorized,
+	domain.CodeForbidden:            http.StatusForbidden,
+	domain.CodeQuotaExceeded:        http.StatusForbidden,
+	domain.CodeNotFound:             http.StatusNotFound,
+	domain.CodeProductNotFound:      http.StatusNotFound,
+	domain.CodeConflict:             http.StatusConflict,
+	domain.CodeOutOfStock:           http.StatusConflict,
+	domain.CodeRateLimited:          http.StatusTooManyRequests,
+	domain.CodeReadOnly:             http.StatusServiceUnavailable,
+	domain.CodeTimeout:              http.StatusGatewayTimeout,
+	domain.CodePayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	domain.CodeUnavailable:          http.StatusServiceUnavailable,
+	domain.CodeInternal:             http.StatusInternalServerError,
+}
+
+// respondProblem aborts the request with a problem response
+func respondProblem(c *gin.Context, status int, code, detail string) {
+	c.Error(&problemError{status: status, code: code, detail: detail})
+	c.Abort()
+}
+
+// kindStatus maps domain error kinds to HTTP statuses
+var kindStatus = []struct {
+	kind   error
+	status int
+}{
+	{domain.ErrNotFound, http.StatusNotFound},
+	{domain.ErrForbidden, http.StatusForbidden},
+	{domain.ErrConflict, http.StatusConflict},
+}
+
+// respondError aborts the request with a problem response for err. Typed domain
+// errors and error kinds determine the status and code; other errors use the
+// given fallback, and errors caused by the request deadline are a 504. Details of
+// server errors are logged rather than returned.
+func respondError(c *gin.Context, status int, code string, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		respondProblem(c, http.StatusGatewayTimeout, domain.CodeTimeout, requestTimeoutDetail)
+		return
+	}
+
+	var typed *domain.Error
+	if errors.As(err, &typed) {
+		if typedStatus, ok := codeStatus[typed.Code]; ok {
+			status = typedStatus
+		}
+		code = typed.Code
+	}
+	var validation *domain.ValidationError
+	if errors.As(err, &validation) {
+		status, code = http.StatusBadRequest, domain.CodeValidationFailed
+	}
+	for _, mapping := range kindStatus {
+		if errors.Is(err, mapping.kind) {
+			status = mapping.status
+			break
+		}
+	}
+
+	detail := err.Error()
+	if status >= http.StatusInternalServerError {
+		log.Printf("Request %s %s failed: %v", c.Request.Method, c.Request.URL.Path, err)
+		detail = "An unexpected error occurred"
+	}
+
+	problem := &problemError{status: status, code: code, detail: detail}
+	if status >= http.StatusInternalServerError {
+		problem.cause = err
+		problem.stack = service.CaptureStack(1)
+	}
+	if typed != nil && status == http.StatusConflict {
+		problem.conflict = typed.Conflict
+	}
+	if validation != nil {
+		problem.errors = validation.Fields
+	}
+	c.Error(problem)
+	c.Abort()
+}
+
+// ErrorMiddleware renders errors queued by handlers as application/problem+json
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		problem := &problemError{
+			status: http.StatusInternalServerError,
+			code:   domain.CodeInternal,
+			detail: "An unexpected error occurred",
+		}
+		errors.As(c.Errors.Last().Err, &problem)
+
+		writeProblem(c, problem)
+	}
+}
+
+// NoRoute renders unknown routes as problem responses
+func NoRoute(c *gin.Context) {
+	writeProblem(c, &problemError{status: http.StatusNotFound, code: domain.CodeNotFound, detail: "The requested resource does not exist"})
+}
+
+// NoMethod renders unsupported methods as problem responses
+func NoMethod(c *gin.Context) {
+	writeProblem(c, &problemError{status: http.StatusMethodNotAllowed, code: domain.CodeBadRequest, detail: "The requested method is not allowed for this resource"})
+}
+
+// problemType returns the problem type URI of an error code
+func problemType(code string) string {
+	return "/problems/" + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// writeProblem writes a problem details body
+func writeProblem(c *gin.Context, problem *problemError) {
+	c.Header("Content-Type", problemContentType)
+	c.JSON(problem.status, Problem{
+		Type:      problemType(problem.code),
+		Title:     http.StatusText(problem.status),
+		Status:    problem.status,
+		Detail:    problem.detail,
+		Instance:  c.Request.URL.Path,
+		Code:      problem.code,
+		Errors:    problem.errors,
+		Conflict:  problem.conflict,
+		RequestID: c.GetString("request_id"),
+	})
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func serveProblem(t *testing.T, handler gin.HandlerFunc) (*httptest.ResponseRecorder, Problem) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.GET("/test", handler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	var problem Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	return recorder, problem
+}
+
+func TestRespondError_TypedError(t *testing.T) {
+	recorder, problem := serveProblem(t, func(c *gin.Context) {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, domain.NewError(domain.CodeQuotaExceeded, "product quota exceeded"))
+	})
+
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != problemContentType {
+		t.Errorf("Expected content type %s, got %s", problemContentType, contentType)
+	}
+	if problem.Code != domain.CodeQuotaExceeded || problem.Type != "/problems/quota-exceeded" || problem.Instance != "/test" {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
+}
+
+func TestRespondError_ConflictDetails(t *testing.T) {
+	updatedAt := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	recorder, problem := serveProblem(t, func(c *gin.Context) {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, domain.StockConflictError(3, updatedAt))
+	})
+
+	if recorder.Code != http.StatusConflict || problem.Code != domain.CodeConflict {
+		t.Fatalf("Unexpected response %d %+v", recorder.Code, problem)
+	}
+	if problem.Conflict == nil || problem.Conflict.CurrentStock == nil || *problem.Conflict.CurrentStock != 3 || !problem.Conflict.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Expected the current stock and update time, got %+v", problem.Conflict)
+	}
+}
+
+func TestRespondError_OutOfStock(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Stock: 5, ReservedStock: 4}
+	recorder, problem := serveProblem(t, func(c *gin.Context) {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, domain.InsufficientStockError(product))
+	})
+
+	if recorder.Code != http.StatusConflict || problem.Code != domain.CodeOutOfStock || problem.Type != "/problems/out-of-stock" {
+		t.Fatalf("Unexpected response %d %+v", recorder.Code, problem)
+	}
+	if problem.Conflict == nil || problem.Conflict.AvailableStock == nil || *problem.Conflict.AvailableStock != 1 {
+		t.Errorf("Expected the available stock, got %+v", problem.Conflict)
+	}
+}
+
+func TestRespondError_UntypedErrorUsesFallback(t *testing.T) {
+	recorder, problem := serveProblem(t, func(c *gin.Context) {
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, errors.New("user already exists"))
+	})
+
+	if recorder.Code != http.StatusBadRequest || problem.Code != domain.CodeOperationFailed || problem.Detail != "user already exists" {
+		t.Errorf("Unexpected response %d %+v", recorder.Code, problem)
+	}
+}
+
+func TestRespondError_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("loading product: %w", domain.ErrProductNotFound), http.StatusNotFound},
+		{domain.ErrProductForbidden, http.StatusForbidden},
+		{domain.ConflictError(domain.CodeConflict, "stock cannot go below zero"), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		recorder, _ := serveProblem(t, func(c *gin.Context) {
+			respondError(c, http.StatusInternalServerError, domain.CodeInternal, tt.err)
+		})
+		if recorder.Code != tt.status {
+			t.Errorf("Expected status %d for %q, got %d", tt.status, tt.err, recorder.Code)
+		}
+	}
+}
+
+func TestRespondError_HidesServerErrorDetail(t *testing.T) {
+	recorder, problem := serveProblem(t, func(c *gin.Context) {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, errors.New("connection refused"))
+	})
+
+	if recorder.Code != http.StatusInternalServerError || problem.Detail == "connection refused" {
+		t.Errorf("Unexpected response %d %+v", recorder.Code, problem)
+	}
+}
+
+func TestErrorMiddleware_UnknownError(t *testing.T) {
+	recorder, problem := serveProblem(t, func(c *gin.Context) {
+		c.Error(errors.New("database is down"))
+	})
+
+	if recorder.Code != http.StatusInternalServerError || problem.Code != domain.CodeInternal {
+		t.Errorf("Unexpected response %d %+v", recorder.Code, problem)
+	}
+	if problem.Detail == "database is down" {
+		t.Error("Expected internal error details to be hidden")
+	}
+}
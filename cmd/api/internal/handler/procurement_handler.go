@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProcurementHandler handles supplier and purchase order HTTP requests
+type ProcurementHandler struct {
+	procurementService *service.ProcurementService
+}
+
+// NewProcurementHandler creates a new procurement handler
+func NewProcurementHandler(procurementService *service.ProcurementService) *ProcurementHandler {
+	return &ProcurementHandler{
+		procurementService: procurementService,
+	}
+}
+
+// ListSuppliers returns the authenticated user's suppliers by name
+func (h *ProcurementHandler) ListSuppliers(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	suppliers, err := h.procurementService.ListSuppliers(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve suppliers")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suppliers": suppliers})
+}
+
+// CreateSupplier adds a supplier; the body is validated by BindJSON
+func (h *ProcurementHandler) CreateSupplier(c *gin.Context) {
+	req := requestBody[domain.SupplierRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	supplier, err := h.procurementService.CreateSupplier(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, supplier)
+}
+
+// GetSupplier returns one of the authenticated user's suppliers
+func (h *ProcurementHandler) GetSupplier(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	supplier, err := h.procurementService.GetSupplier(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+// UpdateSupplier replaces a supplier's details; the body is validated by BindJSON
+func (h *ProcurementHandler) UpdateSupplier(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.SupplierRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	supplier, err := h.procurementService.UpdateSupplier(c.Request.Context(), userID, id, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+// DeleteSupplier removes a supplier that has no open purchase orders
+func (h *ProcurementHandler) DeleteSupplier(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.procurementService.DeleteSupplier(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier deleted successfully"})
+}
+
+// CreatePurchaseOrder opens a purchase order from a supplier; the body is validated by BindJSON
+func (h *ProcurementHandler) CreatePurchaseOrder(c *gin.Context) {
+	req := requestBody[domain.PurchaseOrderRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	order, err := h.procurementService.CreatePurchaseOrder(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListPurchaseOrders returns a page of the authenticated user's purchase orders, newest first
+func (h *ProcurementHandler) ListPurchaseOrders(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	query := domain.PurchaseOrderQuery{
+		Pagination: domain.Pagination{
+			Page:     1,
+			PageSize: defaultPageSize(c),
+		},
+		Status: c.Query("status"),
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			query.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			query.PageSize = pageSize
+		}
+	}
+
+	switch query.Status {
+	case "", domain.PurchaseOrderStatusOpen, domain.PurchaseOrderStatusReceived, domain.PurchaseOrderStatusCancelled:
+	default:
+		respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "status must be open, received or cancelled")
+		return
+	}
+
+	if supplierStr := c.Query("supplier_id"); supplierStr != "" {
+		supplierID, err := validateUUID(supplierStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "supplier_id must be a valid UUID")
+			return
+		}
+		query.SupplierID = &supplierID
+	}
+
+	orders, err := h.procurementService.ListPurchaseOrders(c.Request.Context(), userID, query)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve purchase orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetPurchaseOrder returns one of the authenticated user's purchase orders
+func (h *ProcurementHandler) GetPurchaseOrder(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	order, err := h.procurementService.GetPurchaseOrder(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// ReceivePurchaseOrder marks an open purchase order received, adding its quantities to stock
+func (h *ProcurementHandler) ReceivePurchaseOrder(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	order, err := h.procurementService.ReceivePurchaseOrder(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelPurchaseOrder cancels an open purchase order
+func (h *ProcurementHandler) CancelPurchaseOrder(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	order, err := h.procurementService.CancelPurchaseOrder(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
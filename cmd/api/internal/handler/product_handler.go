@@ -2,13 +2,14 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"products/internal/domain"
 	"products/internal/service"
-	"products/cmd/api/internal/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -39,66 +40,117 @@ func validateUUID(id string) (uuid.UUID, error) {
 	return parsedID, nil
 }
 
-// Create handles product creation with enhanced validation
-func (h *ProductHandler) Create(c *gin.Context) {
-	var req domain.CreateProductRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid request format: " + err.Error(),
-		})
-		return
+// parseTotalMode reads include_total: false skips counting the total, estimate
+// returns the planner's estimate instead; anything else counts it exactly
+func parseTotalMode(c *gin.Context) string {
+	switch c.Query("include_total") {
+	case "false":
+		return domain.TotalNone
+	case "estimate":
+		return domain.TotalEstimate
+	default:
+		return ""
 	}
+}
 
-	// Sanitize inputs
-	req.Name = validation.SanitizeInput(req.Name)
-	req.Description = validation.SanitizeInput(req.Description)
-	
-	// Validate product name
-	if err := validation.ValidateProductName(req.Name); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
+// statusFilter parses the optional status query parameter, responding with 400 when
+// it is not a known product status
+func statusFilter(c *gin.Context) (string, bool) {
+	switch status := c.Query("status"); status {
+	case "", domain.ProductStatusDraft, domain.ProductStatusPublished, domain.ProductStatusArchived:
+		return status, true
+	default:
+		respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "status must be draft, published or archived")
+		return "", false
 	}
-	
-	// Validate description
-	if err := validation.ValidateDescription(req.Description); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
+}
+
+// stockAndPriceFilters parses the optional stock_status and price_bucket query
+// parameters, comma-separated or repeated, into filter, responding with 400 when
+// one names an unknown stock status or price bucket
+func stockAndPriceFilters(c *gin.Context, filter *domain.ProductFilter) bool {
+	stockStatuses, err := domain.ParseStockStatuses(c.QueryArray("stock_status"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return false
 	}
-	
-	// Validate price
-	if err := validation.ValidatePrice(req.Price); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
+	priceBuckets, err := domain.ParsePriceBuckets(c.QueryArray("price_bucket"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return false
 	}
-	
-	// Validate stock
-	if err := validation.ValidateStock(req.Stock); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
+	filter.StockStatuses, filter.PriceBuckets = stockStatuses, priceBuckets
+	return true
+}
+
+// parseSort parses the order of a product list: repeated sort parameters of the form
+// field[:asc|desc[:nulls_first|nulls_last]], or the older sort_field and sort_direction
+// parameters. Unknown fields are rejected with 400 in sort parameters only, as
+// sort_field has always ignored them. Fields named in aliases are renamed first.
+func parseSort(c *gin.Context, aliases map[string]string) ([]domain.SortField, bool) {
+	if params := c.QueryArray("sort"); len(params) > 0 {
+		for i, param := range params {
+			field, options, found := strings.Cut(param, ":")
+			if alias, ok := aliases[field]; ok && found {
+				params[i] = alias + ":" + options
+			} else if ok {
+				params[i] = alias
+			}
+		}
+		fields, err := domain.ParseSortFields(params)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "sort: "+err.Error())
+			return nil, false
+		}
+		return fields, true
 	}
-	
-	// Check for SQL injection patterns
-	if validation.CheckSQLInjection(req.Name) || validation.CheckSQLInjection(req.Description) {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Security Error",
-			Message: "Invalid input detected",
+
+	fields := []domain.SortField{}
+	if sortField := c.Query("sort_field"); sortField != "" {
+		if alias, ok := aliases[sortField]; ok {
+			sortField = alias
+		}
+		fields = append(fields, domain.SortField{
+			Field:     sortField,
+			Direction: c.DefaultQuery("sort_direction", "asc"),
 		})
+	}
+	return fields, true
+}
+
+// parseAttributeFilter collects attr.<key>=<value> query parameters
+func parseAttributeFilter(c *gin.Context) domain.Attributes {
+	var attributes domain.Attributes
+	for key, values := range c.Request.URL.Query() {
+		if !strings.HasPrefix(key, "attr.") || len(values) == 0 {
+			continue
+		}
+		if attributes == nil {
+			attributes = domain.Attributes{}
+		}
+		attributes[strings.TrimPrefix(key, "attr.")] = values[0]
+	}
+	return attributes
+}
+
+// Create handles product creation; the body is validated by BindJSON
+func (h *ProductHandler) Create(c *gin.Context) {
+	req := requestBody[domain.CreateProductRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	product := newProduct(req)
+
+	if err := h.productService.Create(c.Request.Context(), product, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
 		return
 	}
 
+	c.JSON(http.StatusCreated, product)
+}
+
+// CreateDraft saves an incomplete product as a draft; the body is validated by BindJSON
+func (h *ProductHandler) CreateDraft(c *gin.Context) {
+	req := requestBody[domain.CreateDraftRequest](c)
 	userID := c.MustGet("user_id").(uuid.UUID)
 
 	product := &domain.Product{
@@ -106,13 +158,15 @@ func (h *ProductHandler) Create(c *gin.Context) {
 		Description: req.Description,
 		Price:       req.Price,
 		Stock:       req.Stock,
+		Attributes:  req.Attributes,
+		Status:      domain.ProductStatusDraft,
+
+		LowStockThreshold: req.LowStockThreshold,
+		ReorderQuantity:   req.ReorderQuantity,
 	}
 
 	if err := h.productService.Create(c.Request.Context(), product, userID); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Creation Failed",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
 		return
 	}
 
@@ -126,10 +180,7 @@ func (h *ProductHandler) GetByID(c *gin.Context) {
 	// Validate UUID format
 	id, err := validateUUID(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
 		return
 	}
 
@@ -137,30 +188,103 @@ func (h *ProductHandler) GetByID(c *gin.Context) {
 
 	product, err := h.productService.GetByID(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, domain.ErrorResponse{
-			Error:   "Not Found",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	products := []domain.Product{*product}
+	if wantsHTMLDescriptions(c) {
+		products = htmlProducts(products)
+	}
+	if format := priceFormat(c); format != nil {
+		products = formattedPriceProducts(products, format)
+	}
+
+	c.JSON(http.StatusOK, products[0])
 }
 
-// GetAllByUser handles retrieving all products for the authenticated user
+// GetAllByUser handles listing the authenticated user's products a page at a time.
+// ?all=true keeps the deprecated unbounded behavior, returning every product as an array.
 func (h *ProductHandler) GetAllByUser(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
 
-	products, err := h.productService.GetAllByUser(c.Request.Context(), userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to retrieve products",
-		})
+	if idsStr := c.Query("ids"); idsStr != "" {
+		var ids []uuid.UUID
+		for _, raw := range strings.Split(idsStr, ",") {
+			if raw = strings.TrimSpace(raw); raw == "" {
+				continue
+			}
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, fmt.Sprintf("ids: invalid product ID %q", raw))
+				return
+			}
+			ids = append(ids, id)
+		}
+		h.respondBatch(c, userID, ids)
+		return
+	}
+
+	if all, _ := strconv.ParseBool(c.Query("all")); all && !wantsNDJSON(c) {
+		// The array has no room for the format's metadata
+		if priceFormat(c) != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "price_format=locale is not supported with all=true; page through the list instead")
+			return
+		}
+
+		products, err := h.productService.GetAllByUser(c.Request.Context(), userID)
+		if err != nil {
+			respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve products")
+			return
+		}
+
+		if wantsHTMLDescriptions(c) {
+			products = htmlProducts(products)
+		}
+
+		c.JSON(http.StatusOK, products)
 		return
 	}
 
-	c.JSON(http.StatusOK, products)
+	query := domain.ProductQuery{
+		Filter: domain.ProductFilter{},
+		Sort:   []domain.SortField{},
+		Pagination: domain.Pagination{
+			Page:     1,
+			PageSize: defaultPageSize(c),
+		},
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			query.Pagination.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			query.Pagination.PageSize = pageSize
+		}
+	}
+	query.Total = parseTotalMode(c)
+
+	status, ok := statusFilter(c)
+	if !ok {
+		return
+	}
+	query.Filter.Status = status
+
+	if !stockAndPriceFilters(c, &query.Filter) {
+		return
+	}
+
+	sortFields, ok := parseSort(c, nil)
+	if !ok {
+		return
+	}
+	query.Sort = sortFields
+
+	h.respondProductList(c, userID, query)
 }
 
 // GetProductsWithFilters handles advanced product querying with filters, sorting, and pagination
@@ -173,7 +297,7 @@ func (h *ProductHandler) GetProductsWithFilters(c *gin.Context) {
 		Sort:   []domain.SortField{},
 		Pagination: domain.Pagination{
 			Page:     1,
-			PageSize: 20,
+			PageSize: defaultPageSize(c),
 		},
 	}
 
@@ -195,6 +319,15 @@ func (h *ProductHandler) GetProductsWithFilters(c *gin.Context) {
 		query.Filter.Name = &name
 	}
 
+	if codeStr := c.Query("code"); codeStr != "" {
+		code, err := domain.NormalizeProductCode(codeStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+			return
+		}
+		query.Filter.Code = &code
+	}
+
 	if minPriceStr := c.Query("min_price"); minPriceStr != "" {
 		if minPrice, err := strconv.ParseFloat(minPriceStr, 64); err == nil {
 			query.Filter.MinPrice = &minPrice
@@ -231,25 +364,33 @@ func (h *ProductHandler) GetProductsWithFilters(c *gin.Context) {
 		}
 	}
 
-	// Parse sorting
-	if sortField := c.Query("sort_field"); sortField != "" {
-		sortDirection := c.DefaultQuery("sort_direction", "asc")
-		query.Sort = append(query.Sort, domain.SortField{
-			Field:     sortField,
-			Direction: sortDirection,
-		})
+	status, ok := statusFilter(c)
+	if !ok {
+		return
 	}
+	query.Filter.Status = status
 
-	response, err := h.productService.GetProductsWithFilters(c.Request.Context(), userID, query)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to retrieve products",
-		})
+	if !stockAndPriceFilters(c, &query.Filter) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	locationID, ok := locationFilter(c)
+	if !ok {
+		return
+	}
+	query.Filter.LocationID = locationID
+
+	query.Filter.Attributes = parseAttributeFilter(c)
+	query.Total = parseTotalMode(c)
+
+	// Parse sorting
+	sortFields, ok := parseSort(c, nil)
+	if !ok {
+		return
+	}
+	query.Sort = sortFields
+
+	h.respondProductList(c, userID, query)
 }
 
 // GetProductsWithCursor handles cursor-based pagination
@@ -260,7 +401,7 @@ func (h *ProductHandler) GetProductsWithCursor(c *gin.Context) {
 		Filter: domain.ProductFilter{},
 		Sort:   []domain.SortField{},
 		Pagination: domain.CursorPagination{
-			PageSize: 20,
+			PageSize: defaultPageSize(c),
 		},
 	}
 
@@ -280,6 +421,15 @@ func (h *ProductHandler) GetProductsWithCursor(c *gin.Context) {
 		query.Filter.Name = &name
 	}
 
+	if codeStr := c.Query("code"); codeStr != "" {
+		code, err := domain.NormalizeProductCode(codeStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+			return
+		}
+		query.Filter.Code = &code
+	}
+
 	if minPriceStr := c.Query("min_price"); minPriceStr != "" {
 		if minPrice, err := strconv.ParseFloat(minPriceStr, 64); err == nil {
 			query.Filter.MinPrice = &minPrice
@@ -292,148 +442,226 @@ func (h *ProductHandler) GetProductsWithCursor(c *gin.Context) {
 		}
 	}
 
+	status, ok := statusFilter(c)
+	if !ok {
+		return
+	}
+	query.Filter.Status = status
+
+	if !stockAndPriceFilters(c, &query.Filter) {
+		return
+	}
+
+	locationID, ok := locationFilter(c)
+	if !ok {
+		return
+	}
+	query.Filter.LocationID = locationID
+
+	query.Filter.Attributes = parseAttributeFilter(c)
+
 	// Parse sorting
-	if sortField := c.Query("sort_field"); sortField != "" {
-		sortDirection := c.DefaultQuery("sort_direction", "asc")
-		query.Sort = append(query.Sort, domain.SortField{
-			Field:     sortField,
-			Direction: sortDirection,
-		})
+	sortFields, ok := parseSort(c, nil)
+	if !ok {
+		return
+	}
+	query.Sort = sortFields
+
+	if format := priceFormat(c); wantsHTMLDescriptions(c) || format != nil {
+		response, err := h.productService.GetProductsWithCursor(c.Request.Context(), userID, query)
+		if errors.Is(err, service.ErrInvalidFilter) {
+			respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+			return
+		}
+		if err != nil {
+			respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve products")
+			return
+		}
+
+		rendered := *response
+		if wantsHTMLDescriptions(c) {
+			rendered.Products = htmlProducts(rendered.Products)
+		}
+		if format != nil {
+			rendered.Products = formattedPriceProducts(rendered.Products, format)
+			c.JSON(http.StatusOK, formattedProductCursorList{&rendered, format})
+			return
+		}
+		c.JSON(http.StatusOK, rendered)
+		return
 	}
 
-	response, err := h.productService.GetProductsWithCursor(c.Request.Context(), userID, query)
+	response, err := h.productService.GetProductsWithCursorJSON(c.Request.Context(), userID, query)
+	if errors.Is(err, service.ErrInvalidFilter) {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to retrieve products",
-		})
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve products")
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.Data(http.StatusOK, "application/json; charset=utf-8", response)
 }
 
-// GetProductStats retrieves product statistics for the authenticated user
-func (h *ProductHandler) GetProductStats(c *gin.Context) {
+// Publish publishes a draft product once it meets the rules that apply on publishing
+func (h *ProductHandler) Publish(c *gin.Context) {
+	h.setStatus(c, domain.ProductStatusPublished)
+}
+
+// Unpublish turns a product back into a draft
+func (h *ProductHandler) Unpublish(c *gin.Context) {
+	h.setStatus(c, domain.ProductStatusDraft)
+}
+
+// setStatus publishes or unpublishes the product of the request and responds with it
+func (h *ProductHandler) setStatus(c *gin.Context, status string) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
 	userID := c.MustGet("user_id").(uuid.UUID)
 
-	stats, err := h.productService.GetProductStats(c.Request.Context(), userID)
+	var product *domain.Product
+	if status == domain.ProductStatusPublished {
+		product, err = h.productService.Publish(c.Request.Context(), id, userID)
+	} else {
+		product, err = h.productService.Unpublish(c.Request.Context(), id, userID)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to retrieve product statistics",
-		})
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	c.JSON(http.StatusOK, product)
 }
 
-// Update handles product updates with enhanced validation
-func (h *ProductHandler) Update(c *gin.Context) {
-	idStr := c.Param("id")
-	
-	// Validate UUID format
-	id, err := validateUUID(idStr)
+// Archive archives one of the user's products, keeping it out of lists and statistics
+func (h *ProductHandler) Archive(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// Unarchive returns an archived product to lists and statistics
+func (h *ProductHandler) Unarchive(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+// setArchived archives or unarchives the product of the request and responds with it
+func (h *ProductHandler) setArchived(c *gin.Context, archived bool) {
+	id, err := validateUUID(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
 		return
 	}
+	userID := c.MustGet("user_id").(uuid.UUID)
 
-	var req domain.UpdateProductRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid request format: " + err.Error(),
-		})
+	var product *domain.Product
+	if archived {
+		product, err = h.productService.Archive(c.Request.Context(), id, userID)
+	} else {
+		product, err = h.productService.Unarchive(c.Request.Context(), id, userID)
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
 		return
 	}
 
+	c.JSON(http.StatusOK, product)
+}
+
+// GetProductStats retrieves product statistics for the authenticated user
+func (h *ProductHandler) GetProductStats(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
 
-	// Validate provided fields
-	if req.Name != nil {
-		*req.Name = validation.SanitizeInput(*req.Name)
-		if err := validation.ValidateProductName(*req.Name); err != nil {
-			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-				Error:   "Validation Error",
-				Message: "Name: " + err.Error(),
-			})
-			return
-		}
-		if validation.CheckSQLInjection(*req.Name) {
-			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-				Error:   "Security Error",
-				Message: "Invalid name input detected",
-			})
-			return
-		}
+	stats, err := h.productService.GetProductStats(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve product statistics")
+		return
 	}
-	
-	if req.Description != nil {
-		*req.Description = validation.SanitizeInput(*req.Description)
-		if err := validation.ValidateDescription(*req.Description); err != nil {
-			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-				Error:   "Validation Error",
-				Message: "Description: " + err.Error(),
-			})
-			return
-		}
-		if validation.CheckSQLInjection(*req.Description) {
-			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-				Error:   "Security Error",
-				Message: "Invalid description input detected",
-			})
-			return
-		}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// SortFields describes the fields product lists can be sorted by
+func (h *ProductHandler) SortFields(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.NewSortFieldsResponse())
+}
+
+// FilterOptions describes the stock statuses and price buckets product lists can
+// be filtered by
+func (h *ProductHandler) FilterOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.NewFilterOptionsResponse())
+}
+
+// Suggest returns names of the authenticated user's products completing the q
+// query, for search-as-you-type
+func (h *ProductHandler) Suggest(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	suggestions, err := h.productService.SuggestNames(c.Request.Context(), userID, c.Query("q"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
 	}
-	
-	if req.Price != nil {
-		if err := validation.ValidatePrice(*req.Price); err != nil {
-			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-				Error:   "Validation Error",
-				Message: "Price: " + err.Error(),
-			})
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// GetStatsSeries returns a metric of the authenticated user's products per day
+// or week they were created in, for charts
+func (h *ProductHandler) GetStatsSeries(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	metric := c.DefaultQuery("metric", domain.StatsMetricCount)
+	interval := c.DefaultQuery("interval", domain.StatsIntervalDay)
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid 'from' date, expected RFC3339")
 			return
 		}
+		from = parsed
 	}
-	
-	if req.Stock != nil {
-		if err := validation.ValidateStock(*req.Stock); err != nil {
-			c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-				Error:   "Validation Error",
-				Message: "Stock: " + err.Error(),
-			})
+
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid 'to' date, expected RFC3339")
 			return
 		}
+		to = parsed
 	}
 
-	// Create product with only the fields to update
-	product := &domain.Product{
-		ID: id,
+	series, err := h.productService.GetStatsSeries(c.Request.Context(), userID, metric, interval, from, to)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
 	}
 
-	if req.Name != nil {
-		product.Name = *req.Name
-	}
-	if req.Description != nil {
-		product.Description = *req.Description
-	}
-	if req.Price != nil {
-		product.Price = *req.Price
-	}
-	if req.Stock != nil {
-		product.Stock = *req.Stock
+	c.JSON(http.StatusOK, series)
+}
+
+// Update handles product updates; the body is validated by BindJSON
+func (h *ProductHandler) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	
+	// Validate UUID format
+	id, err := validateUUID(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
 	}
 
-	if err := h.productService.Update(c.Request.Context(), product, userID); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Update Failed",
-			Message: err.Error(),
-		})
+	req := requestBody[domain.UpdateProductRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.productService.Update(c.Request.Context(), id, req, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
 		return
 	}
 
@@ -447,22 +675,202 @@ func (h *ProductHandler) Delete(c *gin.Context) {
 	// Validate UUID format
 	id, err := validateUUID(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
 		return
 	}
 
 	userID := c.MustGet("user_id").(uuid.UUID)
 
 	if err := h.productService.Delete(c.Request.Context(), id, userID); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Deletion Failed",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
-} 
\ No newline at end of file
+}
+
+// DecrementStock atomically takes units out of a product's available stock; the
+// body is validated by BindJSON
+func (h *ProductHandler) DecrementStock(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.StockDecrementRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	stock, err := h.productService.DecrementStock(c.Request.Context(), id, userID, req.Quantity)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stock)
+}
+
+// BulkCreate creates every valid item as a product, reporting the outcome of each
+func (h *ProductHandler) BulkCreate(c *gin.Context) {
+	req := requestBody[domain.BulkProductRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	result := domain.NewBulkResult()
+	for i, raw := range req.Items {
+		item, err := decodeBulkItem[domain.CreateProductRequest](c, raw)
+		if err != nil {
+			result.Fail(i, nil, err)
+			continue
+		}
+
+		product := newProduct(item)
+		if err := h.productService.Create(c.Request.Context(), product, userID); err != nil {
+			result.Fail(i, nil, err)
+			continue
+		}
+		result.Succeed(i, product.ID)
+	}
+
+	respondBulk(c, http.StatusCreated, result)
+}
+
+// BulkUpdate applies each item to the product it identifies, reporting the outcome of each
+func (h *ProductHandler) BulkUpdate(c *gin.Context) {
+	req := requestBody[domain.BulkProductRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	result := domain.NewBulkResult()
+	for i, raw := range req.Items {
+		ref, err := decodeBulkItem[domain.BulkUpdateProductItem](c, raw)
+		if err != nil {
+			result.Fail(i, nil, err)
+			continue
+		}
+
+		item, err := decodeBulkItem[domain.UpdateProductRequest](c, raw)
+		if err != nil {
+			result.Fail(i, &ref.ID, err)
+			continue
+		}
+
+		if err := h.productService.Update(c.Request.Context(), ref.ID, item, userID); err != nil {
+			result.Fail(i, &ref.ID, err)
+			continue
+		}
+		result.Succeed(i, ref.ID)
+	}
+
+	respondBulk(c, http.StatusOK, result)
+}
+
+// BatchGet fetches the listed products in one query, for lists of IDs too long
+// for the ids parameter of GetAllByUser; the body is validated by BindJSON
+func (h *ProductHandler) BatchGet(c *gin.Context) {
+	req := requestBody[domain.BatchGetProductRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	h.respondBatch(c, userID, req.IDs)
+}
+
+// respondBatch responds with the products of the given IDs the user may view,
+// listing the others as missing
+func (h *ProductHandler) respondBatch(c *gin.Context, userID uuid.UUID, ids []uuid.UUID) {
+	response, err := h.productService.GetByIDs(c.Request.Context(), userID, ids)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	if wantsHTMLDescriptions(c) {
+		response.Products = htmlProducts(response.Products)
+	}
+	if format := priceFormat(c); format != nil {
+		response.Products = formattedPriceProducts(response.Products, format)
+		c.JSON(http.StatusOK, formattedBatchGetProductResponse{response, format})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkDelete deletes the listed products, reporting the outcome of each
+func (h *ProductHandler) BulkDelete(c *gin.Context) {
+	req := requestBody[domain.BulkDeleteProductRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	result := domain.NewBulkResult()
+	for i, raw := range req.IDs {
+		id, err := validateUUID(raw)
+		if err != nil {
+			result.Fail(i, nil, domain.NewError(domain.CodeInvalidFilter, err.Error()))
+			continue
+		}
+
+		if err := h.productService.Delete(c.Request.Context(), id, userID); err != nil {
+			result.Fail(i, &id, err)
+			continue
+		}
+		result.Succeed(i, id)
+	}
+
+	respondBulk(c, http.StatusOK, result)
+}
+
+// newProduct builds the product described by a create request
+func newProduct(req *domain.CreateProductRequest) *domain.Product {
+	return &domain.Product{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Stock:       req.Stock,
+		Attributes:  req.Attributes,
+		Public:      req.Public,
+
+		LowStockThreshold: req.LowStockThreshold,
+		ReorderQuantity:   req.ReorderQuantity,
+	}
+}
+
+// respondProductList writes a page of the user's products matching the query, or
+// streams all of them to clients accepting NDJSON
+func (h *ProductHandler) respondProductList(c *gin.Context, userID uuid.UUID, query domain.ProductQuery) {
+	if wantsNDJSON(c) {
+		h.streamProducts(c, userID, query)
+		return
+	}
+
+	if format := priceFormat(c); wantsHTMLDescriptions(c) || format != nil {
+		response, err := h.productService.GetProductsWithFilters(c.Request.Context(), userID, query)
+		if errors.Is(err, service.ErrInvalidFilter) {
+			respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+			return
+		}
+		if err != nil {
+			respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve products")
+			return
+		}
+
+		rendered := *response
+		if wantsHTMLDescriptions(c) {
+			rendered.Products = htmlProducts(rendered.Products)
+		}
+		if format != nil {
+			rendered.Products = formattedPriceProducts(rendered.Products, format)
+			c.JSON(http.StatusOK, formattedProductList{&rendered, format})
+			return
+		}
+		c.JSON(http.StatusOK, rendered)
+		return
+	}
+
+	response, err := h.productService.GetProductsWithFiltersJSON(c.Request.Context(), userID, query)
+	if errors.Is(err, service.ErrInvalidFilter) {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve products")
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", response)
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProductV2Handler serves products in the v2 shape, sharing the v1 product service
+type ProductV2Handler struct {
+	productService *service.ProductService
+}
+
+// NewProductV2Handler creates a new v2 product handler
+func NewProductV2Handler(productService *service.ProductService) *ProductV2Handler {
+	return &ProductV2Handler{
+		productService: productService,
+	}
+}
+
+// Create handles v2 product creation
+func (h *ProductV2Handler) Create(c *gin.Context) {
+	req := requestBody[domain.CreateProductV2Request](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	product := &domain.Product{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       domain.CentsToPrice(req.PriceCents),
+		Stock:       req.Stock,
+		Attributes:  req.Attributes,
+		Public:      req.Public,
+	}
+
+	if err := h.productService.Create(c.Request.Context(), product, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.NewProductV2Response(product))
+}
+
+// GetByID handles retrieving a v2 product by ID
+func (h *ProductV2Handler) GetByID(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	product, err := h.productService.GetByID(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	if wantsHTMLDescriptions(c) {
+		product = &htmlProducts([]domain.Product{*product})[0]
+	}
+	if format := priceFormat(c); format != nil {
+		product = &formattedPriceProducts([]domain.Product{*product}, format)[0]
+	}
+
+	c.JSON(http.StatusOK, domain.NewProductV2Response(product))
+}
+
+// List handles v2 product listing with filters, sorting, and pagination
+func (h *ProductV2Handler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	query := domain.ProductQuery{
+		Filter: domain.ProductFilter{},
+		Sort:   []domain.SortField{},
+		Pagination: domain.Pagination{
+			Page:     1,
+			PageSize: defaultPageSize(c),
+		},
+	}
+
+	// Parse pagination
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			query.Pagination.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			query.Pagination.PageSize = pageSize
+		}
+	}
+
+	// Parse filters
+	if name := c.Query("name"); name != "" {
+		query.Filter.Name = &name
+	}
+
+	if codeStr := c.Query("code"); codeStr != "" {
+		code, err := domain.NormalizeProductCode(codeStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+			return
+		}
+		query.Filter.Code = &code
+	}
+
+	if minPriceStr := c.Query("min_price_cents"); minPriceStr != "" {
+		if minPriceCents, err := strconv.ParseInt(minPriceStr, 10, 64); err == nil {
+			minPrice := domain.CentsToPrice(minPriceCents)
+			query.Filter.MinPrice = &minPrice
+		}
+	}
+
+	if maxPriceStr := c.Query("max_price_cents"); maxPriceStr != "" {
+		if maxPriceCents, err := strconv.ParseInt(maxPriceStr, 10, 64); err == nil {
+			maxPrice := domain.CentsToPrice(maxPriceCents)
+			query.Filter.MaxPrice = &maxPrice
+		}
+	}
+
+	if minStockStr := c.Query("min_stock"); minStockStr != "" {
+		if minStock, err := strconv.Atoi(minStockStr); err == nil {
+			query.Filter.MinStock = &minStock
+		}
+	}
+
+	if maxStockStr := c.Query("max_stock"); maxStockStr != "" {
+		if maxStock, err := strconv.Atoi(maxStockStr); err == nil {
+			query.Filter.MaxStock = &maxStock
+		}
+	}
+
+	status, ok := statusFilter(c)
+	if !ok {
+		return
+	}
+	query.Filter.Status = status
+
+	if !stockAndPriceFilters(c, &query.Filter) {
+		return
+	}
+
+	locationID, ok := locationFilter(c)
+	if !ok {
+		return
+	}
+	query.Filter.LocationID = locationID
+
+	query.Filter.Attributes = parseAttributeFilter(c)
+	query.Total = parseTotalMode(c)
+
+	// Parse sorting; prices are sorted by as price_cents in v2
+	sortFields, ok := parseSort(c, map[string]string{"price_cents": "price"})
+	if !ok {
+		return
+	}
+	query.Sort = sortFields
+
+	response, err := h.productService.GetProductsWithFilters(c.Request.Context(), userID, query)
+	if errors.Is(err, service.ErrInvalidFilter) {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
+	}
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve products")
+		return
+	}
+
+	if wantsHTMLDescriptions(c) {
+		rendered := *response
+		rendered.Products = htmlProducts(response.Products)
+		response = &rendered
+	}
+	if format := priceFormat(c); format != nil {
+		rendered := *response
+		rendered.Products = formattedPriceProducts(response.Products, format)
+		c.JSON(http.StatusOK, formattedProductV2List{domain.NewProductV2ListResponse(&rendered), format})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewProductV2ListResponse(response))
+}
+
+// Update handles v2 product updates
+func (h *ProductV2Handler) Update(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
+	}
+
+	req := requestBody[domain.UpdateProductV2Request](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	changes := &domain.UpdateProductRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Stock:       req.Stock,
+		Attributes:  req.Attributes,
+	}
+	if req.PriceCents != nil {
+		price := domain.CentsToPrice(*req.PriceCents)
+		changes.Price = &price
+	}
+
+	if err := h.productService.Update(c.Request.Context(), id, changes, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	updated, err := h.productService.GetByID(c.Request.Context(), id, userID)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully"})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewProductV2Response(updated))
+}
+
+// Delete handles v2 product deletion
+func (h *ProductV2Handler) Delete(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.productService.Delete(c.Request.Context(), id, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
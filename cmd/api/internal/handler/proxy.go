@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProxyConfig sets which reverse proxies are trusted to report the client's address,
+// which keys login throttling, rate limits and audit logs. By default none are, and
+// the client IP is the address of the connection, so that clients cannot choose it
+// with X-Forwarded-For.
+type ProxyConfig struct {
+	// TrustedProxies are the IPs and CIDRs of the proxies whose X-Forwarded-For and
+	// X-Real-IP headers are used, see ParseTrustedProxies
+	TrustedProxies []string
+
+	// TrustedPlatform is a header holding the client IP set by the platform the API
+	// runs behind, such as CF-Connecting-IP; it is only safe when every request
+	// reaches the API through that platform
+	TrustedPlatform string
+}
+
+// ParseTrustedProxies parses a comma-separated list of IPs and CIDRs
+func ParseTrustedProxies(value string) ([]string, error) {
+	var proxies []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, err := netip.ParsePrefix(entry); err != nil {
+			if _, err := netip.ParseAddr(entry); err != nil {
+				return nil, fmt.Errorf("%q is not an IP or CIDR", entry)
+			}
+		}
+		proxies = append(proxies, entry)
+	}
+	return proxies, nil
+}
+
+// ApplyProxyConfig configures how the engine resolves the client IP. Proxies that
+// are not valid, which ParseTrustedProxies rejects, leave no proxy trusted.
+func ApplyProxyConfig(engine *gin.Engine, config ProxyConfig) {
+	engine.TrustedPlatform = config.TrustedPlatform
+	if err := engine.SetTrustedProxies(config.TrustedProxies); err != nil {
+		_ = engine.SetTrustedProxies(nil)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestApplyProxyConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ProxyConfig
+		remote   string
+		headers  map[string]string
+		expected string
+	}{
+		{"spoofed forwarded for is ignored", ProxyConfig{}, "203.0.113.7:4711", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "203.0.113.7"},
+		{"spoofed real ip is ignored", ProxyConfig{}, "203.0.113.7:4711", map[string]string{"X-Real-IP": "198.51.100.1"}, "203.0.113.7"},
+		{"spoofed platform header is ignored", ProxyConfig{}, "203.0.113.7:4711", map[string]string{"CF-Connecting-IP": "198.51.100.1"}, "203.0.113.7"},
+		{"untrusted proxy", ProxyConfig{TrustedProxies: []string{"10.0.0.0/8"}}, "203.0.113.7:4711", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", ProxyConfig{TrustedProxies: []string{"10.0.0.0/8"}}, "10.1.2.3:4711", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "198.51.100.1"},
+		{"chain through trusted proxies", ProxyConfig{TrustedProxies: []string{"10.0.0.0/8"}}, "10.1.2.3:4711", map[string]string{"X-Forwarded-For": "192.0.2.9, 198.51.100.1, 10.4.5.6"}, "198.51.100.1"},
+		{"trusted platform", ProxyConfig{TrustedPlatform: "CF-Connecting-IP"}, "203.0.113.7:4711", map[string]string{"CF-Connecting-IP": "198.51.100.1"}, "198.51.100.1"},
+		{"invalid proxies trust none", ProxyConfig{TrustedProxies: []string{"not-an-ip"}}, "10.1.2.3:4711", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			ApplyProxyConfig(router, tt.config)
+			router.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remote
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Body.String() != tt.expected {
+				t.Errorf("Expected client IP %s, got %s", tt.expected, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies(" 10.0.0.0/8, 192.0.2.1 ,,2001:db8::/32")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(proxies) != 3 || proxies[0] != "10.0.0.0/8" || proxies[1] != "192.0.2.1" || proxies[2] != "2001:db8::/32" {
+		t.Errorf("Unexpected proxies %v", proxies)
+	}
+
+	if _, err := ParseTrustedProxies("10.0.0.0/8,proxy.internal"); err == nil {
+		t.Error("Expected a host name to be rejected")
+	}
+}
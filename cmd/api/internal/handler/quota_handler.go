@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// QuotaHandler handles quota-related HTTP requests
+type QuotaHandler struct {
+	quotaService *service.QuotaService
+}
+
+// NewQuotaHandler creates a new quota handler
+func NewQuotaHandler(quotaService *service.QuotaService) *QuotaHandler {
+	return &QuotaHandler{
+		quotaService: quotaService,
+	}
+}
+
+// Get returns the authenticated user's product quota usage
+func (h *QuotaHandler) Get(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	status, err := h.quotaService.Status(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve quota")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
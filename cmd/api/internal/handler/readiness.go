@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"products/internal/resilience"
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessHandler reports the circuit breaker state of each dependency and answers
+// 503 until the circuits of all required dependencies are closed, and from the
+// moment draining is set at shutdown so that load balancers stop routing requests
+func ReadinessHandler(dependencies []*resilience.Executor, draining *atomic.Bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if draining != nil && draining.Load() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+			return
+		}
+
+		ready := true
+		statuses := make([]resilience.Status, 0, len(dependencies))
+		for _, dependency := range dependencies {
+			status := dependency.Status()
+			if status.State != resilience.StateClosed && !status.Optional {
+				ready = false
+			}
+			statuses = append(statuses, status)
+		}
+
+		if !ready {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "dependencies": statuses})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready", "dependencies": statuses})
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"products/internal/resilience"
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policy := resilience.Policy{BreakerThreshold: 1, BreakerCooldown: time.Minute}
+	alwaysTransient := func(error) bool { return true }
+	database := resilience.NewExecutor("database", policy, alwaysTransient)
+	redis := resilience.NewExecutor("redis", policy, alwaysTransient)
+	redis.Optional = true
+
+	var draining atomic.Bool
+	router := gin.New()
+	router.GET("/ready", ReadinessHandler([]*resilience.Executor{database, redis}, &draining))
+	ready := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return recorder
+	}
+
+	fail := func(context.Context) error { return errors.New("connection refused") }
+
+	redis.DoOnce(context.Background(), fail)
+	if recorder := ready(); recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"state":"open"`) {
+		t.Errorf("Expected ready with an open optional circuit, got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	database.DoOnce(context.Background(), fail)
+	if recorder := ready(); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with an open required circuit, got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	draining.Store(true)
+	if recorder := ready(); recorder.Code != http.StatusServiceUnavailable || !strings.Contains(recorder.Body.String(), `"status":"draining"`) {
+		t.Errorf("Expected 503 while draining, got %d %s", recorder.Code, recorder.Body.String())
+	}
+}
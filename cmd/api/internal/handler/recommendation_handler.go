@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RecommendationHandler handles related product HTTP requests
+type RecommendationHandler struct {
+	recommendationService *service.RecommendationService
+}
+
+// NewRecommendationHandler creates a new recommendation handler
+func NewRecommendationHandler(recommendationService *service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+	}
+}
+
+// Related returns the products related to a product, most relevant first
+func (h *RecommendationHandler) Related(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	limit := domain.DefaultRelatedLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= domain.MaxRelatedLimit {
+			limit = parsed
+		}
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	related, err := h.recommendationService.Related(c.Request.Context(), productID, userID, limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, related)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"products/internal/domain"
+	"products/internal/redact"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// reportedHeaders are the request headers sent with error reports; credentials
+// and cookies are left out
+var reportedHeaders = []string{"Accept", "Content-Type", "User-Agent", "X-Api-Version"}
+
+// panicsTotal counts recovered panics by route, served with the other runtime
+// variables on /debug/vars
+var panicsTotal = expvar.NewMap("http_panics_total")
+
+// RecoveryMiddleware answers requests whose handler panicked with a 500 problem
+// response carrying the request ID, logs the panic to logger, counts it in
+// http_panics_total and sends the panics and the causes of server errors to
+// reporter with the request and user they failed. It must run before
+// ErrorMiddleware.
+func RecoveryMiddleware(reporter service.ErrorReporter, logger *slog.Logger) gin.HandlerFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// the handler gave up on the response on purpose
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			err, ok := recovered.(error)
+			if !ok {
+				err = fmt.Errorf("%v", recovered)
+			}
+			route := c.FullPath()
+			if route == "" {
+				route = "unmatched"
+			}
+			panicsTotal.Add(route, 1)
+			attrs := []slog.Attr{
+				slog.String("request_id", c.GetString("request_id")),
+				slog.String("method", c.Request.Method),
+				slog.String("route", route),
+				slog.String("path", redact.Emails(c.Request.URL.Path)),
+				slog.String("error", err.Error()),
+				slog.String("stack", string(debug.Stack())),
+			}
+			if userID, ok := c.Get("user_id"); ok {
+				if id, ok := userID.(uuid.UUID); ok {
+					attrs = append(attrs, slog.String("user_id", id.String()))
+				}
+			}
+			logger.LogAttrs(c.Request.Context(), slog.LevelError, "panic", attrs...)
+			reporter.Report(c.Request.Context(), errorReport(c, err, true, service.CaptureStack(2)))
+
+			if !c.Writer.Written() {
+				writeProblem(c, &problemError{status: http.StatusInternalServerError, code: domain.CodeInternal, detail: "An unexpected error occurred"})
+			}
+			c.Abort()
+		}()
+
+		c.Next()
+
+		if c.Writer.Status() < http.StatusInternalServerError {
+			return
+		}
+		for _, ginErr := range c.Errors {
+			var problem *problemError
+			switch {
+			case errors.As(ginErr.Err, &problem):
+				if problem.cause != nil {
+					reporter.Report(c.Request.Context(), errorReport(c, problem.cause, false, problem.stack))
+				}
+			default:
+				reporter.Report(c.Request.Context(), errorReport(c, ginErr.Err, false, nil))
+			}
+		}
+	}
+}
+
+// errorReport describes an error of the request, leaving out credentials
+func errorReport(c *gin.Context, err error, panicked bool, stack []uintptr) service.ErrorReport {
+	url := redact.Emails(c.Request.URL.Path)
+	if query := c.Request.URL.RawQuery; query != "" {
+		url += "?" + redact.Query(query)
+	}
+	headers := map[string]string{}
+	for _, name := range reportedHeaders {
+		if value := c.GetHeader(name); value != "" {
+			headers[name] = value
+		}
+	}
+
+	report := service.ErrorReport{
+		Err:   err,
+		Panic: panicked,
+		Stack: stack,
+		Request: &service.ReportedRequest{
+			Method:   c.Request.Method,
+			URL:      url,
+			Route:    c.FullPath(),
+			ClientIP: c.ClientIP(),
+			Headers:  headers,
+		},
+	}
+	if requestID := c.GetString("request_id"); requestID != "" {
+		report.Tags = map[string]string{"request_id": requestID}
+	}
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uuid.UUID); ok {
+			report.UserID = &id
+		}
+	}
+	return report
+}
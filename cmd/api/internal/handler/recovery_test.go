@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// recordingReporter keeps the reports it receives
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports []service.ErrorReport
+}
+
+func (r *recordingReporter) Report(ctx context.Context, report service.ErrorReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+func (r *recordingReporter) Flush(ctx context.Context) error { return nil }
+
+func TestRecoveryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reporter := &recordingReporter{}
+	userID := uuid.New()
+
+	var output bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&output, nil))
+
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), RecoveryMiddleware(reporter, logger), ErrorMiddleware())
+	router.Use(func(c *gin.Context) { c.Set("user_id", userID) })
+	router.GET("/panic", func(c *gin.Context) { panic("nil map") })
+	router.GET("/failed", func(c *gin.Context) {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, errors.New("connection refused"))
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, domain.ErrProductNotFound)
+	})
+
+	tests := []struct {
+		path  string
+		want  int
+		panic bool
+		cause string
+	}{
+		{"/panic?token=secret", http.StatusInternalServerError, true, "nil map"},
+		{"/failed", http.StatusInternalServerError, false, "connection refused"},
+		// client errors are not reported
+		{"/missing", http.StatusNotFound, false, ""},
+	}
+
+	for _, tt := range tests {
+		reporter.reports = nil
+		output.Reset()
+		panics := panicsTotal.Get("/panic")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if recorder.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.want, recorder.Code)
+		}
+		if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, problemContentType) {
+			t.Errorf("%s: expected a problem response, got %s", tt.path, got)
+		}
+
+		if tt.cause == "" {
+			if len(reporter.reports) != 0 {
+				t.Errorf("%s: expected no report, got %+v", tt.path, reporter.reports)
+			}
+			continue
+		}
+		if len(reporter.reports) != 1 {
+			t.Fatalf("%s: expected one report, got %d", tt.path, len(reporter.reports))
+		}
+		report := reporter.reports[0]
+		if report.Err.Error() != tt.cause || report.Panic != tt.panic || len(report.Stack) == 0 {
+			t.Errorf("%s: unexpected report %+v", tt.path, report)
+		}
+		if report.UserID == nil || *report.UserID != userID {
+			t.Errorf("%s: expected the report of user %s, got %v", tt.path, userID, report.UserID)
+		}
+		if strings.Contains(report.Request.URL, "secret") {
+			t.Errorf("%s: expected the token redacted, got %s", tt.path, report.Request.URL)
+		}
+
+		requestID := recorder.Header().Get(RequestIDHeader)
+		var problem Problem
+		if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("%s: failed to decode problem: %v", tt.path, err)
+		}
+		if requestID == "" || problem.RequestID != requestID || report.Tags["request_id"] != requestID {
+			t.Errorf("%s: expected request ID %q in the problem and report, got %q and %q", tt.path, requestID, problem.RequestID, report.Tags["request_id"])
+		}
+		if !tt.panic {
+			continue
+		}
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: expected a JSON panic log, got %q", tt.path, output.String())
+		}
+		if entry["msg"] != "panic" || entry["request_id"] != requestID || entry["route"] != "/panic" || entry["user_id"] != userID.String() {
+			t.Errorf("%s: unexpected panic log %v", tt.path, entry)
+		}
+		if stack, _ := entry["stack"].(string); !strings.Contains(stack, "goroutine") {
+			t.Errorf("%s: expected the stack in the panic log, got %v", tt.path, entry["stack"])
+		}
+		if panicsTotal.Get("/panic").String() == fmt.Sprint(panics) {
+			t.Errorf("%s: expected the panic counted", tt.path)
+		}
+	}
+}
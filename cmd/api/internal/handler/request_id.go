@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID of a request in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern accepts IDs set by proxies, such as UUIDs and trace IDs
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestIDMiddleware gives each request an ID, kept from the X-Request-ID header
+// when a proxy set a valid one and generated otherwise. The ID is returned in the
+// header of the response and in problem responses, so clients can quote it when
+// reporting a failure, and is logged with the request.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
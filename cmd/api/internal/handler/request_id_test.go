@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, c.GetString("request_id")) })
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"generated", "", false},
+		{"from proxy", "trace-7f3a:01.2", true},
+		{"invalid characters", "id\nwith newline", false},
+		{"too long", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.incoming != "" {
+			request.Header.Set(RequestIDHeader, tt.incoming)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		id := recorder.Header().Get(RequestIDHeader)
+		if id != recorder.Body.String() {
+			t.Errorf("%s: header %q differs from context %q", tt.name, id, recorder.Body.String())
+		}
+		if tt.keep && id != tt.incoming {
+			t.Errorf("%s: expected %q kept, got %q", tt.name, tt.incoming, id)
+		}
+		if !tt.keep {
+			if _, err := uuid.Parse(id); err != nil {
+				t.Errorf("%s: expected a generated UUID, got %q", tt.name, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReservationHandler handles stock reservation HTTP requests
+type ReservationHandler struct {
+	reservationService *service.ReservationService
+}
+
+// NewReservationHandler creates a new reservation handler
+func NewReservationHandler(reservationService *service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{
+		reservationService: reservationService,
+	}
+}
+
+// Create reserves units of a product's available stock; the body is validated by BindJSON
+func (h *ReservationHandler) Create(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.StockReservationRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	reservation, err := h.reservationService.Reserve(c.Request.Context(), productID, userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// Get returns a live reservation
+func (h *ReservationHandler) Get(c *gin.Context) {
+	productID, reservationID, ok := reservationIDs(c)
+	if !ok {
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	reservation, err := h.reservationService.Get(c.Request.Context(), productID, reservationID, userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// Confirm takes a reservation's units out of stock and returns the updated product
+func (h *ReservationHandler) Confirm(c *gin.Context) {
+	productID, reservationID, ok := reservationIDs(c)
+	if !ok {
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	product, err := h.reservationService.Confirm(c.Request.Context(), productID, reservationID, userID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// Release ends a reservation, making its units available again
+func (h *ReservationHandler) Release(c *gin.Context) {
+	productID, reservationID, ok := reservationIDs(c)
+	if !ok {
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.reservationService.Release(c.Request.Context(), productID, reservationID, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// reservationIDs parses the product and reservation IDs of a reservation route,
+// responding with 400 when either is invalid
+func reservationIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	reservationID, err := validateUUID(c.Param("reservation_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return productID, reservationID, true
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReviewHandler handles product review HTTP requests
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// Create reviews a public product; the body is validated by BindJSON
+func (h *ReviewHandler) Create(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.ReviewRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	review, err := h.reviewService.Create(c.Request.Context(), productID, userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// List returns every review of one of the user's products, newest first
+func (h *ReviewHandler) List(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	query := domain.ReviewQuery{
+		Pagination: pageQuery(c),
+		Status:     c.Query("status"),
+	}
+	if query.Status != "" && query.Status != domain.ReviewStatusPublished && query.Status != domain.ReviewStatusHidden {
+		respondProblem(c, http.StatusBadRequest, domain.CodeInvalidFilter, "status must be published or hidden")
+		return
+	}
+
+	reviews, err := h.reviewService.List(c.Request.Context(), productID, userID, query)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// Moderate publishes or hides a review of one of the user's products; the body
+// is validated by BindJSON
+func (h *ReviewHandler) Moderate(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	reviewID, err := validateUUID(c.Param("review_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.ReviewModerationRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	review, err := h.reviewService.Moderate(c.Request.Context(), productID, reviewID, userID, req.Status)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ListPublic returns the published reviews of a public product to anonymous visitors
+func (h *ReviewHandler) ListPublic(c *gin.Context) {
+	productID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	reviews, err := h.reviewService.ListPublic(c.Request.Context(), productID, pageQuery(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// pageQuery parses the page and page_size query parameters of a listing
+func pageQuery(c *gin.Context) domain.Pagination {
+	pagination := domain.Pagination{
+		Page:     1,
+		PageSize: defaultPageSize(c),
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			pagination.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			pagination.PageSize = pageSize
+		}
+	}
+
+	return pagination
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SavedSearchHandler handles saved product search HTTP requests
+type SavedSearchHandler struct {
+	savedSearchService *service.SavedSearchService
+	productHandler     *ProductHandler
+}
+
+// NewSavedSearchHandler creates a new saved search handler; saved searches are
+// run by the product handler's filtered list
+func NewSavedSearchHandler(savedSearchService *service.SavedSearchService, productHandler *ProductHandler) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+		productHandler:     productHandler,
+	}
+}
+
+// List returns the authenticated user's saved searches by name
+func (h *SavedSearchHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	searches, err := h.savedSearchService.List(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve saved searches")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"saved_searches": searches})
+}
+
+// Create saves a product query under a name; the body is validated by BindJSON
+func (h *SavedSearchHandler) Create(c *gin.Context) {
+	req := requestBody[domain.SavedSearchRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	search, err := h.savedSearchService.Create(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// Get returns one of the authenticated user's saved searches
+func (h *SavedSearchHandler) Get(c *gin.Context) {
+	id, err := validateUUID(c.Param("search_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	search, err := h.savedSearchService.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// Update replaces a saved search; the body is validated by BindJSON
+func (h *SavedSearchHandler) Update(c *gin.Context) {
+	id, err := validateUUID(c.Param("search_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.SavedSearchRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	search, err := h.savedSearchService.Update(c.Request.Context(), userID, id, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// Delete removes a saved search
+func (h *SavedSearchHandler) Delete(c *gin.Context) {
+	id, err := validateUUID(c.Param("search_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.savedSearchService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
+
+// SetSubscription subscribes to or unsubscribes from a saved search; the body is
+// validated by BindJSON
+func (h *SavedSearchHandler) SetSubscription(c *gin.Context) {
+	id, err := validateUUID(c.Param("search_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	req := requestBody[domain.SavedSearchSubscriptionRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	search, err := h.savedSearchService.SetSubscribed(c.Request.Context(), userID, id, *req.Subscribed)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// Execute runs a saved search and responds like the filtered product list. The
+// page and page_size query parameters override the saved pagination.
+func (h *SavedSearchHandler) Execute(c *gin.Context) {
+	id, err := validateUUID(c.Param("search_id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	search, err := h.savedSearchService.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	query := search.Query
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			query.Pagination.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			query.Pagination.PageSize = pageSize
+		}
+	}
+
+	h.productHandler.respondProductList(c, userID, query)
+}
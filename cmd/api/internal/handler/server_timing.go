@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"time"
+
+	"products/internal/timing"
+	"github.com/gin-gonic/gin"
+)
+
+// ServerTimingMiddleware reports where each request spent its time in a
+// Server-Timing header: database and cache calls, the remaining app time, the
+// total and, when the request has a deadline, its latency budget. It must run
+// before the other middleware so that the total covers them.
+func ServerTimingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx, recorder := timing.NewContext(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := &serverTimingWriter{ResponseWriter: c.Writer, c: c, start: start, recorder: recorder}
+		c.Writer = writer
+
+		c.Next()
+
+		// Responses without a body are written after the middleware returns
+		writer.setHeader()
+	}
+}
+
+// serverTimingWriter sets the Server-Timing header just before the response
+// headers are written, once the handler's calls have been recorded
+type serverTimingWriter struct {
+	gin.ResponseWriter
+	c        *gin.Context
+	start    time.Time
+	recorder *timing.Recorder
+	done     bool
+}
+
+// setHeader sets the Server-Timing header unless the headers were already written
+func (w *serverTimingWriter) setHeader() {
+	if w.done || w.Written() {
+		return
+	}
+	w.done = true
+
+	var budget time.Duration
+	if deadline, ok := w.c.Request.Context().Deadline(); ok {
+		budget = deadline.Sub(w.start)
+	}
+	w.Header().Set("Server-Timing", w.recorder.Header(time.Since(w.start), budget))
+}
+
+// WriteHeaderNow implements gin.ResponseWriter
+func (w *serverTimingWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write implements gin.ResponseWriter
+func (w *serverTimingWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString implements gin.ResponseWriter
+func (w *serverTimingWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush implements http.Flusher
+func (w *serverTimingWriter) Flush() {
+	w.setHeader()
+	w.ResponseWriter.Flush()
+}
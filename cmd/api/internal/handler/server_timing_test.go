@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"products/internal/timing"
+	"github.com/gin-gonic/gin"
+)
+
+func TestServerTimingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ServerTimingMiddleware(), TimeoutMiddleware(time.Second))
+	router.GET("/products", func(c *gin.Context) {
+		timing.FromContext(c.Request.Context()).Add(timing.Database, 5*time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"data": []string{}})
+	})
+	router.DELETE("/products/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		method string
+		path   string
+		want   []string
+	}{
+		{http.MethodGet, "/products", []string{`db;desc="1 query";dur=5.0`, "app;dur=", "total;dur=", "budget;dur=100"}},
+		{http.MethodDelete, "/products/1", []string{"app;dur=", "total;dur="}},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+		header := recorder.Header().Get("Server-Timing")
+		for _, want := range tt.want {
+			if !strings.Contains(header, want) {
+				t.Errorf("%s %s: expected Server-Timing to contain %q, got %q", tt.method, tt.path, want, header)
+			}
+		}
+	}
+}
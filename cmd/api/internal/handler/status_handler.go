@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"products/internal/service"
+)
+
+// StatusHandler serves the data of the public status page
+type StatusHandler struct {
+	statusService *service.StatusService
+}
+
+// NewStatusHandler creates a new status handler
+func NewStatusHandler(statusService *service.StatusService) *StatusHandler {
+	return &StatusHandler{statusService: statusService}
+}
+
+// Get returns the uptime, latency and dependency health of the API; the data is
+// computed at most every 30 seconds, and caches may keep it as long
+func (h *StatusHandler) Get(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, h.statusService.Status())
+}
+
+// StatusMiddleware records the latency and status of requests for the status
+// page. Event streams and WebSockets, which stay open until the client leaves,
+// are not recorded.
+func StatusMiddleware(statusService *service.StatusService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if opensEventStream(c) || opensWebSocket(c) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+		statusService.Record(time.Since(start), c.Writer.Status())
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StockTemplateHandler handles per-category stock templates and product stock levels
+type StockTemplateHandler struct {
+	stockTemplateService *service.StockTemplateService
+}
+
+// NewStockTemplateHandler creates a new stock template handler
+func NewStockTemplateHandler(stockTemplateService *service.StockTemplateService) *StockTemplateHandler {
+	return &StockTemplateHandler{
+		stockTemplateService: stockTemplateService,
+	}
+}
+
+// List returns the authenticated user's stock templates
+func (h *StockTemplateHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	templates, err := h.stockTemplateService.List(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve stock templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// Define creates or replaces the stock template of a category
+func (h *StockTemplateHandler) Define(c *gin.Context) {
+	req := requestBody[domain.StockTemplateRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	template, err := h.stockTemplateService.Define(c.Request.Context(), userID, c.Param("category"), *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// Delete removes the stock template of a category
+func (h *StockTemplateHandler) Delete(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.stockTemplateService.Delete(c.Request.Context(), userID, c.Param("category")); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock template deleted successfully"})
+}
+
+// Levels returns the low-stock threshold and reorder quantity that apply to a product
+func (h *StockTemplateHandler) Levels(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeInvalidFilter, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	levels, err := h.stockTemplateService.Levels(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, levels)
+}
+
+// LowStock lists the authenticated user's products below their low-stock threshold,
+// overall or at the location given by location_id
+func (h *StockTemplateHandler) LowStock(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	locationID, ok := locationFilter(c)
+	if !ok {
+		return
+	}
+
+	products, err := h.stockTemplateService.LowStock(c.Request.Context(), userID, locationID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve low stock products")
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
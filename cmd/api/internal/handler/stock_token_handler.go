@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StockTokenHandler handles QR-based quick stock updates
+type StockTokenHandler struct {
+	stockTokenService *service.StockTokenService
+}
+
+// NewStockTokenHandler creates a new stock token handler
+func NewStockTokenHandler(stockTokenService *service.StockTokenService) *StockTokenHandler {
+	return &StockTokenHandler{
+		stockTokenService: stockTokenService,
+	}
+}
+
+// Issue creates a signed stock token for one of the user's products
+func (h *StockTokenHandler) Issue(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	var req domain.StockTokenRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+			return
+		}
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "ttl must be a positive duration such as 8h")
+			return
+		}
+	}
+
+	response, err := h.stockTokenService.Issue(c.Request.Context(), id, userID, ttl)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	// The token and the URL embedding it are bearer credentials; keep them out of the audit trail
+	c.Set(auditSkipResponseBody, true)
+	c.JSON(http.StatusCreated, response)
+}
+
+// Revoke invalidates all stock tokens issued for one of the user's products
+func (h *StockTokenHandler) Revoke(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	if err := h.stockTokenService.RevokeAll(c.Request.Context(), id, userID); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock tokens revoked successfully"})
+}
+
+// Get returns the product a stock token grants access to
+func (h *StockTokenHandler) Get(c *gin.Context) {
+	response, err := h.stockTokenService.Get(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, domain.CodeUnauthorized, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Adjust changes a product's stock using a stock token instead of a login
+func (h *StockTokenHandler) Adjust(c *gin.Context) {
+	var req domain.QuickStockAdjustRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
+		return
+	}
+
+	response, ownerID, err := h.stockTokenService.Adjust(c.Request.Context(), req.Token, req.Delta, req.LocationID)
+	if ownerID != uuid.Nil {
+		// Attribute the audit record to the product owner the token acts for
+		c.Set("user_id", ownerID)
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
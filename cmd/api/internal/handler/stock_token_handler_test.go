@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/repository/memory"
+	"products/internal/service"
+	"products/internal/service/memorycache"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestStockTokenHandler_IssueKeepsTokenOutOfAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := memory.NewProductRepository(memory.NewStore())
+	product := &domain.Product{ID: uuid.New(), UserID: userID, Name: "Lamp"}
+	if err := repo.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	productService := service.NewProductService(repo, nil, nil, nil, service.DefaultProductAuthorizer(), nil)
+	stockTokenHandler := NewStockTokenHandler(service.NewStockTokenService(productService, repo, nil, memorycache.New(), "secret", time.Hour))
+
+	var audited string
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		audited = auditedResponseBody(c, writer)
+	})
+	router.POST("/api/v1/products/:id/stock-token", func(c *gin.Context) { c.Set("user_id", userID) }, stockTokenHandler.Issue)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/products/"+product.ID.String()+"/stock-token", nil))
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var response domain.StockTokenResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Token == "" {
+		t.Fatalf("Expected a token in the response, got %s", recorder.Body.String())
+	}
+	if strings.Contains(audited, response.Token) || strings.Contains(audited, "token") {
+		t.Errorf("Expected the audited body to hold no token, got %q", audited)
+	}
+}
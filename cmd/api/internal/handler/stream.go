@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// StreamHeartbeat is how often an idle event stream sends a comment, so that
+	// proxies keep the connection open and clients notice when it drops
+	StreamHeartbeat = 15 * time.Second
+	// streamRetry is how long clients wait before reconnecting a dropped stream
+	streamRetry = 3 * time.Second
+)
+
+// eventStreamRoute is the route serving event streams, see EventHandler.List
+const eventStreamRoute = "/api/v1/events"
+
+// isEventStream reports whether a request asks for server-sent events
+func isEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// opensEventStream reports whether a request opens an event stream: it asks for
+// server-sent events on eventStreamRoute. The Accept header alone does not, since
+// clients choose it on any route.
+func opensEventStream(c *gin.Context) bool {
+	return c.FullPath() == eventStreamRoute && isEventStream(c.Request)
+}
+
+// Stream sends the user's events and notifications as server-sent events until
+// the client disconnects. Events carry their sequence as ID, so a client
+// reconnecting with Last-Event-ID first receives the events it missed.
+func (h *EventHandler) Stream(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var after int64
+	if lastEventID := c.GetHeader("Last-Event-ID"); lastEventID != "" {
+		parsed, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || parsed < 0 {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Last-Event-ID must be the sequence of an event")
+			return
+		}
+		after = parsed
+	}
+
+	ctx := c.Request.Context()
+	events, err := h.streamService.Subscribe(ctx, userID)
+	if err != nil {
+		respondError(c, http.StatusServiceUnavailable, domain.CodeUnavailable, err)
+		return
+	}
+
+	c.Set(auditSkipResponseBody, true)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	// Subscribed before replaying, so events recorded meanwhile arrive twice
+	// rather than never; the copies are skipped by sequence
+	for more := after > 0; more; {
+		page, hasMore, err := h.streamService.Replay(ctx, userID, after)
+		if err != nil {
+			return
+		}
+		for _, event := range page {
+			writeStreamEvent(c.Writer, event)
+			after = event.Sequence
+		}
+		more = hasMore
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Sequence != 0 && event.Sequence <= after {
+				continue
+			}
+			writeStreamEvent(c.Writer, event)
+		case <-heartbeat.C:
+			io.WriteString(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeStreamEvent writes an event in the server-sent events format; notifications
+// have no ID, so reconnecting clients resume from the last event
+func writeStreamEvent(w io.Writer, event domain.StreamEvent) {
+	if event.Sequence != 0 {
+		fmt.Fprintf(w, "id: %d\n", event.Sequence)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"products/internal/service/memorycache"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestEventHandler_Stream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	streamService := service.NewStreamService(memorycache.New(), nil)
+	eventHandler := NewEventHandler(nil, streamService)
+
+	router := gin.New()
+	router.Use(TimeoutMiddleware(50 * time.Millisecond))
+	router.GET(eventStreamRoute, func(c *gin.Context) { c.Set("user_id", userID) }, eventHandler.List)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+eventStreamRoute, nil)
+	request.Header.Set("Accept", "text/event-stream")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer response.Body.Close()
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", contentType)
+	}
+
+	// Outlive the request timeout, which streams are exempt from
+	time.Sleep(100 * time.Millisecond)
+	streamService.PublishNotification(ctx, &domain.Notification{ID: uuid.New(), UserID: uuid.New(), Title: "Someone else's"})
+	streamService.PublishNotification(ctx, &domain.Notification{ID: uuid.New(), UserID: userID, Title: "Export ready"})
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var received []string
+	for len(received) < 2 {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("Stream ended early after %v", received)
+			}
+			if strings.HasPrefix(line, "event:") || strings.HasPrefix(line, "data:") {
+				received = append(received, line)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected the notification, got %v", received)
+		}
+	}
+
+	if received[0] != "event: notification" || !strings.Contains(received[1], "Export ready") {
+		t.Errorf("Unexpected event %v", received)
+	}
+}
+
+func TestEventHandler_StreamInvalidLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	eventHandler := NewEventHandler(nil, service.NewStreamService(memorycache.New(), nil))
+
+	router := gin.New()
+	router.Use(ErrorMiddleware())
+	router.GET("/events", func(c *gin.Context) { c.Set("user_id", uuid.New()) }, eventHandler.List)
+
+	request := httptest.NewRequest(http.MethodGet, "/events", nil)
+	request.Header.Set("Accept", "text/event-stream")
+	request.Header.Set("Last-Event-ID", "abc")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SyncHandler handles sync device registrations and the product changes API
+type SyncHandler struct {
+	syncService *service.SyncService
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// ListDevices returns the authenticated user's sync devices
+func (h *SyncHandler) ListDevices(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	devices, err := h.syncService.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve sync devices")
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// RegisterDevice registers a device for offline sync; the body is validated by BindJSON
+func (h *SyncHandler) RegisterDevice(c *gin.Context) {
+	req := requestBody[domain.SyncDeviceRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	device, err := h.syncService.RegisterDevice(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+// DeleteDevice removes a sync device
+func (h *SyncHandler) DeleteDevice(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.syncService.DeleteDevice(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sync device deleted successfully"})
+}
+
+// Changes returns the product changes after the since token, or after the device's
+// last acknowledged token when since is omitted
+func (h *SyncHandler) Changes(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	var since *int64
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		token, err := strconv.ParseInt(sinceStr, 10, 64)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "since: invalid sync token")
+			return
+		}
+		since = &token
+	}
+
+	limit := domain.DefaultSyncPageSize
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= domain.MaxSyncPageSize {
+			limit = parsed
+		}
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	changes, err := h.syncService.Changes(c.Request.Context(), userID, id, since, limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// ProductChanges returns the IDs of the products created, updated and deleted
+// after the since timestamp
+func (h *SyncHandler) ProductChanges(c *gin.Context) {
+	since, err := time.Parse(time.RFC3339Nano, c.Query("since"))
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "since: an RFC 3339 timestamp is required")
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	changes, err := h.syncService.ProductChanges(c.Request.Context(), userID, since)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
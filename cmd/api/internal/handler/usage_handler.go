@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UsageHandler handles API usage statistics requests
+type UsageHandler struct {
+	usageService *service.UsageService
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(usageService *service.UsageService) *UsageHandler {
+	return &UsageHandler{
+		usageService: usageService,
+	}
+}
+
+// Get returns the authenticated user's daily API usage
+func (h *UsageHandler) Get(c *gin.Context) {
+	h.respond(c, c.MustGet("user_id").(uuid.UUID))
+}
+
+// GetForUser returns any user's daily API usage, for administrators
+func (h *UsageHandler) GetForUser(c *gin.Context) {
+	userID, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	h.respond(c, userID)
+}
+
+// respond returns the user's usage between the 'from' and 'to' query dates,
+// the last 30 days by default
+func (h *UsageHandler) respond(c *gin.Context, userID uuid.UUID) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid 'from' date, expected RFC3339")
+			return
+		}
+		from = parsed
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid 'to' date, expected RFC3339")
+			return
+		}
+		to = parsed
+	}
+
+	usage, err := h.usageService.GetUsage(c.Request.Context(), userID, from, to)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
@@ -2,11 +2,9 @@ package handler
 
 import (
 	"net/http"
-	"strings"
 
 	"products/internal/domain"
 	"products/internal/service"
-	"products/cmd/api/internal/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -23,56 +21,9 @@ func NewUserHandler(userService *service.UserService) *UserHandler {
 	}
 }
 
-// Register handles user registration with enhanced validation
+// Register handles user registration; the body is validated by BindJSON
 func (h *UserHandler) Register(c *gin.Context) {
-	var req domain.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid request format: " + err.Error(),
-		})
-		return
-	}
-
-	// Sanitize inputs
-	req.Email = validation.SanitizeInput(req.Email)
-	req.Name = validation.SanitizeInput(req.Name)
-	
-	// Validate email
-	if err := validation.ValidateEmail(req.Email); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
-	}
-	
-	// Validate password
-	if err := validation.ValidatePassword(req.Password); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
-	}
-	
-	// Validate name
-	if err := validation.ValidateName(req.Name); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
-	}
-
-	// Check for SQL injection patterns (additional security)
-	if validation.CheckSQLInjection(req.Email) {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Security Error",
-			Message: "Invalid input detected",
-		})
-		return
-	}
+	req := requestBody[domain.CreateUserRequest](c)
 
 	user := &domain.User{
 		Email:    req.Email,
@@ -81,72 +32,33 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 
 	if err := h.userService.Register(c.Request.Context(), user); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Registration Failed",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, domain.CodeOperationFailed, err)
 		return
 	}
 
-	// Don't return password in response
-	user.Password = ""
-	c.JSON(http.StatusCreated, user)
+	// Attribute the audit record to the new user
+	c.Set("user_id", user.ID)
+
+	c.JSON(http.StatusCreated, domain.NewUserResponse(user))
 }
 
-// Login handles user authentication with enhanced validation
+// Login handles user authentication; the body is validated by BindJSON
 func (h *UserHandler) Login(c *gin.Context) {
-	var req domain.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid request format: " + err.Error(),
-		})
-		return
-	}
-
-	// Sanitize inputs
-	req.Email = validation.SanitizeInput(req.Email)
-	
-	// Validate email
-	if err := validation.ValidateEmail(req.Email); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: err.Error(),
-		})
-		return
-	}
-	
-	// Validate password is not empty
-	if strings.TrimSpace(req.Password) == "" {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Validation Error",
-			Message: "Password is required",
-		})
-		return
-	}
-
-	// Check for SQL injection patterns
-	if validation.CheckSQLInjection(req.Email) {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Security Error",
-			Message: "Invalid input detected",
-		})
-		return
-	}
+	req := requestBody[domain.LoginRequest](c)
 
 	// Get client IP and user agent
 	ipAddress := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
 
-	response, err := h.userService.Login(c.Request.Context(), req.Email, req.Password, ipAddress, userAgent)
+	response, err := h.userService.Login(c.Request.Context(), req, ipAddress, userAgent)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-			Error:   "Authentication Failed",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusUnauthorized, domain.CodeAuthenticationFailed, err)
 		return
 	}
 
+	// Attribute the audit record to the authenticated user
+	c.Set("user_id", response.User.ID)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -154,19 +66,13 @@ func (h *UserHandler) Login(c *gin.Context) {
 func (h *UserHandler) RefreshToken(c *gin.Context) {
 	var req domain.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Invalid request format: " + err.Error(),
-		})
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Invalid request format: " + err.Error())
 		return
 	}
 
 	response, err := h.userService.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{
-			Error:   "Token Refresh Failed",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusUnauthorized, domain.CodeAuthenticationFailed, err)
 		return
 	}
 
@@ -180,28 +86,19 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	token := c.MustGet("token").(string)
 	
 	if sessionID == "" || token == "" {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{
-			Error:   "Bad Request",
-			Message: "Session ID or token not found",
-		})
+		respondProblem(c, http.StatusBadRequest, domain.CodeBadRequest, "Session ID or token not found")
 		return
 	}
 
 	// Blacklist the token first
 	if err := h.userService.BlacklistToken(c.Request.Context(), token); err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Logout Failed",
-			Message: "Failed to blacklist token",
-		})
+		respondProblem(c, http.StatusInternalServerError, domain.CodeOperationFailed, "Failed to blacklist token")
 		return
 	}
 
 	// Then logout the session
 	if err := h.userService.Logout(c.Request.Context(), sessionID); err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Logout Failed",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, domain.CodeOperationFailed, err)
 		return
 	}
 
@@ -213,10 +110,7 @@ func (h *UserHandler) LogoutAll(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
 
 	if err := h.userService.LogoutAll(c.Request.Context(), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Logout All Failed",
-			Message: err.Error(),
-		})
+		respondError(c, http.StatusInternalServerError, domain.CodeOperationFailed, err)
 		return
 	}
 
@@ -229,10 +123,7 @@ func (h *UserHandler) GetUserSessions(c *gin.Context) {
 
 	sessions, err := h.userService.GetUserSessions(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:   "Internal Server Error",
-			Message: "Failed to retrieve user sessions",
-		})
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve user sessions")
 		return
 	}
 
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"products/cmd/api/internal/changelog"
+	"products/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// API versions
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// apiVersions lists every served API version, oldest first
+var apiVersions = []domain.APIVersion{
+	{
+		Name:       APIVersionV1,
+		BasePath:   "/api/v1",
+		Deprecated: true,
+		SunsetDate: &changelog.V1SunsetDate,
+		Successor:  APIVersionV2,
+	},
+	{
+		Name:     APIVersionV2,
+		BasePath: "/api/v2",
+	},
+}
+
+// VersionMiddleware records the API version serving the request and emits
+// Deprecation, Sunset and Link headers when that version is deprecated
+func VersionMiddleware(version string) gin.HandlerFunc {
+	var current *domain.APIVersion
+	var successor *domain.APIVersion
+	for i := range apiVersions {
+		if apiVersions[i].Name == version {
+			current = &apiVersions[i]
+		}
+	}
+	if current != nil && current.Successor != "" {
+		for i := range apiVersions {
+			if apiVersions[i].Name == current.Successor {
+				successor = &apiVersions[i]
+			}
+		}
+	}
+
+	return func(c *gin.Context) {
+		c.Set("api_version", version)
+		c.Header("API-Version", version)
+
+		if current != nil && current.Deprecated {
+			c.Header("Deprecation", "true")
+			if current.SunsetDate != nil {
+				c.Header("Sunset", current.SunsetDate.Format(http.TimeFormat))
+			}
+			if successor != nil {
+				c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor.BasePath))
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// ListVersions returns the served API versions and their deprecation status
+func ListVersions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"versions": apiVersions})
+}
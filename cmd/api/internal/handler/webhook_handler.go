@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"products/internal/domain"
+	"products/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// List returns the authenticated user's webhook subscriptions
+func (h *WebhookHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	subscriptions, err := h.webhookService.List(c.Request.Context(), userID)
+	if err != nil {
+		respondProblem(c, http.StatusInternalServerError, domain.CodeInternal, "Failed to retrieve webhook subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// Create registers a webhook subscription and returns its signing secret; the body is validated by BindJSON
+func (h *WebhookHandler) Create(c *gin.Context) {
+	req := requestBody[domain.WebhookSubscriptionRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	subscription, err := h.webhookService.Create(c.Request.Context(), userID, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// Update replaces a webhook subscription; the body is validated by BindJSON
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	req := requestBody[domain.WebhookSubscriptionRequest](c)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	subscription, err := h.webhookService.Update(c.Request.Context(), userID, id, *req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// Delete removes a webhook subscription
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.webhookService.Delete(c.Request.Context(), userID, id); err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+// Deliveries returns the recent deliveries of a webhook subscription
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	id, err := validateUUID(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, domain.CodeBadRequest, err)
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	deliveries, err := h.webhookService.Deliveries(c.Request.Context(), userID, id)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.CodeInternal, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
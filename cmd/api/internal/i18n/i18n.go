@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is used when the client accepts none of the supported languages
+const DefaultLanguage = "en"
+
+// Message keys
+const (
+	ValidationFailed      = "validation.failed"
+	ValidationRequired    = "validation.required"
+	ValidationEmail       = "validation.email"
+	ValidationPassword    = "validation.password"
+	ValidationPersonName  = "validation.person_name"
+	ValidationProductName = "validation.product_name"
+	ValidationDescription = "validation.description"
+	ValidationPrice       = "validation.price"
+	ValidationPriceCents  = "validation.price_cents"
+	ValidationStock       = "validation.stock"
+	ValidationAttributes  = "validation.attributes"
+	ValidationRule        = "validation.rule"
+)
+
+// catalogs holds the format strings of each supported language
+var catalogs = map[string]map[string]string{
+	"en": {
+		ValidationFailed:      "Request validation failed",
+		ValidationRequired:    "is required",
+		ValidationEmail:       "must be a valid email address",
+		ValidationPassword:    "must be %d-%d characters with upper and lower case letters, a number and one of @$!%%*?&",
+		ValidationPersonName:  "must be %d-%d letters, spaces, hyphens, apostrophes or dots",
+		ValidationProductName: "must be %d-%d letters, digits, spaces or -_.,!?()&",
+		ValidationDescription: "must be at most %d characters without unsupported symbols",
+		ValidationPrice:       "must be between %.2f and %.2f",
+		ValidationPriceCents:  "must be between %d and %d",
+		ValidationStock:       "must be between %d and %d",
+		ValidationAttributes:  "must hold at most %d attributes with well-formed keys and safe values",
+		ValidationRule:        "does not satisfy %s",
+	},
+	"pt": {
+		ValidationFailed:      "A validação da requisição falhou",
+		ValidationRequired:    "é obrigatório",
+		ValidationEmail:       "deve ser um endereço de e-mail válido",
+		ValidationPassword:    "deve ter de %d a %d caracteres com letras maiúsculas e minúsculas, um número e um de @$!%%*?&",
+		ValidationPersonName:  "deve ter de %d a %d letras, espaços, hífens, apóstrofos ou pontos",
+		ValidationProductName: "deve ter de %d a %d letras, dígitos, espaços ou -_.,!?()&",
+		ValidationDescription: "deve ter no máximo %d caracteres sem símbolos não suportados",
+		ValidationPrice:       "deve estar entre %.2f e %.2f",
+		ValidationPriceCents:  "deve estar entre %d e %d",
+		ValidationStock:       "deve estar entre %d e %d",
+		ValidationAttributes:  "deve conter no máximo %d atributos com chaves bem formadas e valores seguros",
+		ValidationRule:        "não atende a %s",
+	},
+	"es": {
+		ValidationFailed:      "La validación de la solicitud falló",
+		ValidationRequired:    "es obligatorio",
+		ValidationEmail:       "debe ser una dirección de correo electrónico válida",
+		ValidationPassword:    "debe tener entre %d y %d caracteres con mayúsculas y minúsculas, un número y uno de @$!%%*?&",
+		ValidationPersonName:  "debe tener entre %d y %d letras, espacios, guiones, apóstrofos o puntos",
+		ValidationProductName: "debe tener entre %d y %d letras, dígitos, espacios o -_.,!?()&",
+		ValidationDescription: "debe tener como máximo %d caracteres sin símbolos no admitidos",
+		ValidationPrice:       "debe estar entre %.2f y %.2f",
+		ValidationPriceCents:  "debe estar entre %d y %d",
+		ValidationStock:       "debe estar entre %d y %d",
+		ValidationAttributes:  "debe contener como máximo %d atributos con claves bien formadas y valores seguros",
+		ValidationRule:        "no cumple %s",
+	},
+	"de": {
+		ValidationFailed:      "Die Validierung der Anfrage ist fehlgeschlagen",
+		ValidationRequired:    "ist erforderlich",
+		ValidationEmail:       "muss eine gültige E-Mail-Adresse sein",
+		ValidationPassword:    "muss %d-%d Zeichen mit Groß- und Kleinbuchstaben, einer Ziffer und einem von @$!%%*?& enthalten",
+		ValidationPersonName:  "muss aus %d-%d Buchstaben, Leerzeichen, Bindestrichen, Apostrophen oder Punkten bestehen",
+		ValidationProductName: "muss aus %d-%d Buchstaben, Ziffern, Leerzeichen oder -_.,!?()& bestehen",
+		ValidationDescription: "darf höchstens %d Zeichen ohne nicht unterstützte Symbole enthalten",
+		ValidationPrice:       "muss zwischen %.2f und %.2f liegen",
+		ValidationPriceCents:  "muss zwischen %d und %d liegen",
+		ValidationStock:       "muss zwischen %d und %d liegen",
+		ValidationAttributes:  "darf höchstens %d Attribute mit gültigen Schlüsseln und sicheren Werten enthalten",
+		ValidationRule:        "erfüllt %s nicht",
+	},
+}
+
+// supported lists the catalog languages, the default first so it wins ties
+var supported = []language.Tag{language.English, language.Portuguese, language.Spanish, language.German}
+
+var matcher = language.NewMatcher(supported)
+
+// Negotiate picks the supported language that best matches an Accept-Language header
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLanguage
+	}
+
+	base, _ := supported[index].Base()
+	return base.String()
+}
+
+// T formats the message for key in lang, falling back to the default language
+func T(lang, key string, args ...interface{}) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+		if !ok {
+			return key
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,68 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"pt-BR,pt;q=0.9,en;q=0.8", "pt"},
+		{"es-MX", "es"},
+		{"fr-FR,de;q=0.7", "de"},
+		{"ja", "en"},
+		{"not a header;;", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("pt", ValidationStock, 0, 10); got != "deve estar entre 0 e 10" {
+		t.Errorf("Unexpected Portuguese message %q", got)
+	}
+	if got := T("xx", ValidationRequired); got != "is required" {
+		t.Errorf("Expected English fallback, got %q", got)
+	}
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key := range catalogs[DefaultLanguage] {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("Catalog %s is missing %s", lang, key)
+			}
+		}
+	}
+}
+
+func TestPriceFormat(t *testing.T) {
+	tests := []struct {
+		locale, currency string
+		price            float64
+		want             string
+		decimalPlaces    int
+	}{
+		{"en-US", "USD", 1234.5, "$ 1,234.50", 2},
+		{"de", "EUR", 1234.5, "€ 1.234,50", 2},
+		{"pt-BR", "BRL", 0.5, "R$ 0,50", 2},
+		{"ja", "JPY", 1234.5, "￥ 1,235", 0},
+		{"", "", 3, "$ 3.00", 2},
+		{"not a locale!", "XYZW", 3, "$ 3.00", 2},
+	}
+
+	for _, tt := range tests {
+		format := NewPriceFormat(tt.locale, tt.currency)
+		if got := format.Format(tt.price); got != tt.want {
+			t.Errorf("Format(%v) in %s %s = %q, want %q", tt.price, tt.locale, tt.currency, got, tt.want)
+		}
+		if format.DecimalPlaces != tt.decimalPlaces {
+			t.Errorf("Expected %d decimal places for %s, got %d", tt.decimalPlaces, tt.currency, format.DecimalPlaces)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// DefaultCurrency is used when the user has no valid currency setting
+const DefaultCurrency = "USD"
+
+// PriceFormat formats prices in a currency for a locale, with the currency's
+// narrow symbol and standard number of decimal places. It is also the metadata
+// of responses with formatted prices.
+type PriceFormat struct {
+	Currency      string `json:"currency"`
+	DecimalPlaces int    `json:"decimal_places"`
+	Locale        string `json:"locale"`
+
+	unit    currency.Unit
+	printer *message.Printer
+}
+
+// NewPriceFormat creates the price format of a locale and ISO 4217 currency code,
+// falling back to DefaultLanguage and DefaultCurrency when either is not valid
+func NewPriceFormat(locale, currencyCode string) *PriceFormat {
+	tag, err := language.Parse(locale)
+	if err != nil || locale == "" {
+		tag = language.Make(DefaultLanguage)
+	}
+	unit, err := currency.ParseISO(currencyCode)
+	if err != nil {
+		unit = currency.MustParseISO(DefaultCurrency)
+	}
+	decimalPlaces, _ := currency.Standard.Rounding(unit)
+
+	return &PriceFormat{
+		Currency:      unit.String(),
+		DecimalPlaces: decimalPlaces,
+		Locale:        tag.String(),
+		unit:          unit,
+		printer:       message.NewPrinter(tag),
+	}
+}
+
+// Format returns a price as a string such as "$ 1,234.50" or "€ 1.234,50",
+// rounded to the currency's decimal places
+func (f *PriceFormat) Format(price float64) string {
+	return f.printer.Sprint(currency.NarrowSymbol(f.unit.Amount(price)))
+}
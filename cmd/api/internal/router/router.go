@@ -1,59 +1,447 @@
 package router
 
 import (
+	"expvar"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/resilience"
 	"products/internal/service"
 	"products/cmd/api/internal/handler"
+	"products/cmd/api/internal/validation"
 
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRouter configures the application routes
-func SetupRouter(userService *service.UserService, productService *service.ProductService, jwtSecret string) *gin.Engine {
-	router := gin.Default()
+func SetupRouter(userService *service.UserService, productService *service.ProductService, attributeService *service.AttributeService, backupService *service.BackupService, accountService *service.AccountService, exportService *service.ExportService, notificationService *service.NotificationService, auditService *service.AuditService, metricsService *service.MetricsService, favoriteService *service.FavoriteService, quotaService *service.QuotaService, auditExportService *service.AuditExportService, catalogService *service.CatalogService, publicLimiter *service.RateLimiter, onboardingService *service.OnboardingService, stockTokenService *service.StockTokenService, emailTemplateService *service.EmailTemplateService, importService *service.ImportService, webhookService *service.WebhookService, stockTemplateService *service.StockTemplateService, syncService *service.SyncService, eventService *service.EventService, loginDeviceService *service.LoginDeviceService, loginHistoryService *service.LoginHistoryService, usageService *service.UsageService, planService *service.PlanService, billingService *service.BillingService, orderService *service.OrderService, reservationService *service.ReservationService, procurementService *service.ProcurementService, locationService *service.LocationService, recommendationService *service.RecommendationService, reviewService *service.ReviewService, noteService *service.NoteService, savedSearchService *service.SavedSearchService, avatarService *service.AvatarService, streamService *service.StreamService, statusService *service.StatusService, userLimiter *service.RateLimiter, dependencies []*resilience.Executor, draining *atomic.Bool, requestTimeout time.Duration, bodyLimits handler.BodyLimits, serverTiming bool, readOnly bool, signingKeys *service.SigningKeys, accessLog handler.AccessLogConfig, proxy handler.ProxyConfig, errorReporter service.ErrorReporter, debug handler.DebugConfig, logger *slog.Logger) *gin.Engine {
+	validation.Register()
+
+	router := gin.New()
+	handler.ApplyProxyConfig(router, proxy)
+	router.Use(handler.RequestIDMiddleware())
+	if accessLog.Logger != nil {
+		router.Use(handler.AccessLogMiddleware(accessLog))
+	}
+	router.Use(handler.RecoveryMiddleware(errorReporter, logger))
+	router.Use(handler.StatusMiddleware(statusService))
+	if serverTiming {
+		router.Use(handler.ServerTimingMiddleware())
+	}
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(handler.NoRoute)
+	router.NoMethod(handler.NoMethod)
+	// Audit logs are written to the primary, which is unavailable in read-only mode
+	if !readOnly {
+		router.Use(handler.AuditMiddleware(auditService))
+	}
+	router.Use(handler.DeprecationMiddleware())
+	router.Use(handler.ErrorMiddleware())
+	router.Use(handler.BodyLimitMiddleware(bodyLimits.Default))
+	router.Use(handler.TimeoutMiddleware(requestTimeout))
+	if readOnly {
+		router.Use(handler.ReadOnlyMiddleware())
+	}
+	if len(debug.Faults) > 0 {
+		router.Use(handler.FaultInjectionMiddleware(debug.Faults))
+	}
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"status": "healthy",
 			"message": "Products CRUD API is running",
+			"read_only": readOnly,
 		})
 	})
 
+	// Readiness probe: fails while the circuit of a required dependency is open and
+	// once the instance starts draining for shutdown
+	readiness := handler.ReadinessHandler(dependencies, draining)
+	router.GET("/ready", readiness)
+	router.GET("/readyz", readiness)
+
+	// Public status page data (anonymous, rate limited)
+	router.GET("/status", handler.RateLimitMiddleware(publicLimiter), handler.NewStatusHandler(statusService).Get)
+
+	// Public keys for validating access tokens signed with RS256 keys
+	router.GET("/.well-known/jwks.json", handler.JWKSHandler(signingKeys))
+
+	// Runtime variables, including the panic counters, for admins only
+	router.GET("/debug/vars", handler.AuthMiddleware(userService, signingKeys), handler.AdminMiddleware(userService), gin.WrapH(expvar.Handler()))
+
+	// Runtime profiles, for admins only
+	if debug.Pprof {
+		router.GET("/debug/pprof/*name", handler.AuthMiddleware(userService, signingKeys), handler.AdminMiddleware(userService), handler.PprofHandler)
+		router.POST("/debug/pprof/*name", handler.AuthMiddleware(userService, signingKeys), handler.AdminMiddleware(userService), handler.PprofHandler)
+	}
+
 	// Create handlers
 	userHandler := handler.NewUserHandler(userService)
 	productHandler := handler.NewProductHandler(productService)
+	productV2Handler := handler.NewProductV2Handler(productService)
+	attributeHandler := handler.NewAttributeHandler(attributeService)
+	backupHandler := handler.NewBackupHandler(backupService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	exportHandler := handler.NewExportHandler(exportService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	auditExportHandler := handler.NewAuditExportHandler(auditExportService)
+	emailTemplateHandler := handler.NewEmailTemplateHandler(emailTemplateService)
+	importHandler := handler.NewImportHandler(importService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	stockTemplateHandler := handler.NewStockTemplateHandler(stockTemplateService)
+	syncHandler := handler.NewSyncHandler(syncService)
+	eventHandler := handler.NewEventHandler(eventService, streamService)
+	loginDeviceHandler := handler.NewLoginDeviceHandler(loginDeviceService)
+	loginHistoryHandler := handler.NewLoginHistoryHandler(loginHistoryService)
+	usageHandler := handler.NewUsageHandler(usageService)
+	planHandler := handler.NewPlanHandler(planService, quotaService)
+	billingHandler := handler.NewBillingHandler(billingService)
+	orderHandler := handler.NewOrderHandler(orderService)
+	reservationHandler := handler.NewReservationHandler(reservationService)
+	procurementHandler := handler.NewProcurementHandler(procurementService)
+	locationHandler := handler.NewLocationHandler(locationService)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	noteHandler := handler.NewNoteHandler(noteService)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService, productHandler)
+	metricsHandler := handler.NewMetricsHandler(metricsService)
+	favoriteHandler := handler.NewFavoriteHandler(favoriteService)
+	quotaHandler := handler.NewQuotaHandler(quotaService)
+	catalogHandler := handler.NewCatalogHandler(catalogService)
+	onboardingHandler := handler.NewOnboardingHandler(onboardingService)
+	stockTokenHandler := handler.NewStockTokenHandler(stockTokenService)
+	avatarHandler := handler.NewAvatarHandler(avatarService)
+	inventorySocketHandler := handler.NewInventorySocketHandler(streamService)
+	changelogHandler := handler.NewChangelogHandler()
+
+	// Authentication bodies are small; bulk operations, imports, restores and avatars may be large
+	authBodyLimit := handler.BodyLimitMiddleware(bodyLimits.Auth)
+	bulkBodyLimit := handler.BodyLimitMiddleware(bodyLimits.Bulk)
+
+	// Embedded product management UI; its pages authenticate with the UI's cookie
+	adminUIHandler := handler.NewAdminUIHandler(handler.AuthMiddleware(userService, signingKeys))
+	router.GET(handler.AdminUIPath+"/*path", adminUIHandler.Serve)
+
+	// API changelog and versions
+	router.GET("/api/changelog", changelogHandler.Get)
+	router.GET("/api/versions", handler.ListVersions)
 
 	// Public routes (no authentication required)
 	public := router.Group("/api/v1")
+	public.Use(handler.VersionMiddleware(handler.APIVersionV1))
+	public.Use(authBodyLimit)
+	{
+		public.POST("/auth/register", handler.BindJSON[domain.CreateUserRequest](), userHandler.Register)
+		public.POST("/auth/login", handler.BindJSON[domain.LoginRequest](), userHandler.Login)
+		public.POST("/auth/verify-email", onboardingHandler.VerifyEmail)
+	}
+
+	// Public catalog routes (anonymous, rate limited)
+	catalog := router.Group("/api/v1/public")
+	catalog.Use(handler.VersionMiddleware(handler.APIVersionV1))
+	catalog.Use(handler.RateLimitMiddleware(publicLimiter))
+	{
+		catalog.GET("/products/:id", catalogHandler.GetProduct)
+		catalog.GET("/products/:id/reviews", reviewHandler.ListPublic)
+		catalog.GET("/users/:slug/products", catalogHandler.ListBySlug)
+		catalog.GET("/avatars/:id", avatarHandler.Get)
+	}
+
+	// Quick stock updates authorized by QR stock tokens (anonymous, rate limited)
+	quick := router.Group("/api/v1/quick")
+	quick.Use(handler.VersionMiddleware(handler.APIVersionV1))
+	quick.Use(handler.RateLimitMiddleware(publicLimiter))
+	{
+		quick.GET("/stock", stockTokenHandler.Get)
+		quick.POST("/stock", stockTokenHandler.Adjust)
+	}
+
+	// Stripe webhooks (authenticated by their signature)
+	stripe := router.Group("/api/v1/billing")
+	stripe.Use(handler.VersionMiddleware(handler.APIVersionV1))
 	{
-		public.POST("/auth/register", userHandler.Register)
-		public.POST("/auth/login", userHandler.Login)
+		stripe.POST("/webhook", billingHandler.Webhook)
 	}
 
 	// Protected routes (authentication required)
 	protected := router.Group("/api/v1")
-	protected.Use(handler.AuthMiddleware(userService, jwtSecret))
+	protected.Use(handler.VersionMiddleware(handler.APIVersionV1))
+	protected.Use(handler.AuthMiddleware(userService, signingKeys))
+	protected.Use(handler.PlanRateLimitMiddleware(planService, userLimiter))
+	protected.Use(handler.QuotaWarningMiddleware(quotaService))
+	protected.Use(handler.PageSizeMiddleware(onboardingService))
+	protected.Use(handler.PriceFormatMiddleware(onboardingService))
+	if !readOnly {
+		protected.Use(handler.UsageMiddleware(usageService))
+	}
 	{
 		// Authentication routes
-		auth := protected.Group("/auth")
+		auth := protected.Group("/auth", authBodyLimit)
 		{
 			auth.POST("/refresh", userHandler.RefreshToken)
 			auth.POST("/logout", userHandler.Logout)
 			auth.POST("/logout-all", userHandler.LogoutAll)
 			auth.GET("/sessions", userHandler.GetUserSessions)
+			auth.GET("/login-history", loginHistoryHandler.List)
+		}
+
+		// Account routes
+		users := protected.Group("/users")
+		{
+			users.DELETE("/me", accountHandler.Delete)
+			users.POST("/me/cancel-deletion", accountHandler.CancelDeletion)
+			users.GET("/me/export", accountHandler.Export)
+			users.GET("/me/quota", quotaHandler.Get)
+			users.PUT("/me/slug", catalogHandler.SetSlug)
+			users.POST("/me/avatar", bulkBodyLimit, avatarHandler.Upload)
+			users.GET("/me/onboarding", onboardingHandler.Get)
+			users.POST("/me/verify-email", onboardingHandler.RequestEmailVerification)
+			users.GET("/me/preferences", onboardingHandler.GetPreferences)
+			users.PUT("/me/preferences", onboardingHandler.UpdatePreferences)
+			users.GET("/me/settings", onboardingHandler.GetSettings)
+			users.PUT("/me/settings", handler.BindJSON[domain.UserSettingsRequest](), onboardingHandler.UpdateSettings)
+			users.GET("/me/export-destination", exportHandler.GetDestination)
+			users.PUT("/me/export-destination", exportHandler.PutDestination)
+			users.DELETE("/me/export-destination", exportHandler.DeleteDestination)
+			users.GET("/me/devices", loginDeviceHandler.List)
+			users.PUT("/me/devices/:id", handler.BindJSON[domain.LoginDeviceRequest](), loginDeviceHandler.Update)
+			users.DELETE("/me/devices/:id", loginDeviceHandler.Delete)
+			users.GET("/me/usage", usageHandler.Get)
+		}
+
+		protected.GET("/plans", planHandler.List)
+
+		// Billing routes
+		billing := protected.Group("/billing")
+		{
+			billing.POST("/checkout", handler.BindJSON[domain.CheckoutRequest](), billingHandler.Checkout)
+			billing.GET("/portal-link", billingHandler.PortalLink)
+			billing.GET("/invoices", billingHandler.Invoices)
+		}
+
+		// Notification routes
+		notifications := protected.Group("/notifications")
+		{
+			notifications.GET("/", notificationHandler.List)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+		}
+
+		// Webhook routes
+		webhooks := protected.Group("/webhooks")
+		{
+			webhooks.GET("/", webhookHandler.List)
+			webhooks.POST("/", handler.BindJSON[domain.WebhookSubscriptionRequest](), webhookHandler.Create)
+			webhooks.PUT("/:id", handler.BindJSON[domain.WebhookSubscriptionRequest](), webhookHandler.Update)
+			webhooks.DELETE("/:id", webhookHandler.Delete)
+			webhooks.GET("/:id/deliveries", webhookHandler.Deliveries)
+		}
+
+		// Event log routes
+		protected.GET("/events", eventHandler.List)
+		protected.GET("/ws", inventorySocketHandler.Serve)
+
+		// Offline sync routes
+		syncDevices := protected.Group("/sync/devices")
+		{
+			syncDevices.GET("/", syncHandler.ListDevices)
+			syncDevices.POST("/", handler.BindJSON[domain.SyncDeviceRequest](), syncHandler.RegisterDevice)
+			syncDevices.DELETE("/:id", syncHandler.DeleteDevice)
+			syncDevices.GET("/:id/changes", syncHandler.Changes)
+		}
+
+		// Admin routes
+		admin := protected.Group("/admin")
+		admin.Use(handler.AdminMiddleware(userService))
+		{
+			admin.GET("/audit-logs", auditHandler.List)
+			admin.GET("/events", eventHandler.ListAll)
+			admin.GET("/users/:id/usage", usageHandler.GetForUser)
+			admin.PUT("/users/:id/plan", handler.BindJSON[domain.UserPlanRequest](), planHandler.SetUserPlan)
+			admin.POST("/audit-exports", auditExportHandler.Create)
+			admin.GET("/audit-exports/:id", auditExportHandler.Get)
+			admin.GET("/audit-exports/:id/download", auditExportHandler.Download)
+			admin.GET("/email-templates", emailTemplateHandler.List)
+			admin.GET("/email-templates/:key", emailTemplateHandler.Get)
+			admin.PUT("/email-templates/:key", handler.BindJSON[domain.EmailTemplateRequest](), emailTemplateHandler.Update)
+			admin.DELETE("/email-templates/:key", emailTemplateHandler.Reset)
+			admin.POST("/email-templates/:key/preview", handler.BindJSON[domain.EmailTemplatePreviewRequest](), emailTemplateHandler.Preview)
 		}
 
 		// Product routes
 		products := protected.Group("/products")
 		{
-			products.POST("/", productHandler.Create)
+			products.POST("/", handler.BindJSON[domain.CreateProductRequest](), productHandler.Create)
+			products.POST("/drafts", handler.BindJSON[domain.CreateDraftRequest](), productHandler.CreateDraft)
 			products.GET("/", productHandler.GetAllByUser)
 			products.GET("/filtered", productHandler.GetProductsWithFilters)
 			products.GET("/cursor", productHandler.GetProductsWithCursor)
 			products.GET("/stats", productHandler.GetProductStats)
-			products.GET("/:id", productHandler.GetByID)
-			products.PUT("/:id", productHandler.Update)
-			products.DELETE("/:id", productHandler.Delete)
+			products.GET("/stats/history", metricsHandler.GetStatsHistory)
+			products.GET("/stats/timeseries", productHandler.GetStatsSeries)
+			products.GET("/changes", syncHandler.ProductChanges)
+			products.GET("/suggest", productHandler.Suggest)
+			products.GET("/sort-fields", productHandler.SortFields)
+			products.GET("/filter-options", productHandler.FilterOptions)
+			products.GET("/favorites", favoriteHandler.List)
+			products.GET("/saved-searches", savedSearchHandler.List)
+			products.POST("/saved-searches", handler.BindJSON[domain.SavedSearchRequest](), savedSearchHandler.Create)
+			products.GET("/saved-searches/:search_id", savedSearchHandler.Get)
+			products.PUT("/saved-searches/:search_id", handler.BindJSON[domain.SavedSearchRequest](), savedSearchHandler.Update)
+			products.DELETE("/saved-searches/:search_id", savedSearchHandler.Delete)
+			products.PUT("/saved-searches/:search_id/subscription", handler.BindJSON[domain.SavedSearchSubscriptionRequest](), savedSearchHandler.SetSubscription)
+			products.GET("/saved-searches/:search_id/results", savedSearchHandler.Execute)
+			products.GET("/low-stock", stockTemplateHandler.LowStock)
+			products.POST("/bulk", bulkBodyLimit, handler.BindJSON[domain.BulkProductRequest](), productHandler.BulkCreate)
+			products.PUT("/bulk", bulkBodyLimit, handler.BindJSON[domain.BulkProductRequest](), productHandler.BulkUpdate)
+			products.POST("/batch-get", handler.BindJSON[domain.BatchGetProductRequest](), productHandler.BatchGet)
+			products.DELETE("/bulk", bulkBodyLimit, handler.BindJSON[domain.BulkDeleteProductRequest](), productHandler.BulkDelete)
+			products.POST("/import", bulkBodyLimit, importHandler.Import)
+			products.GET("/import-profiles", importHandler.ListProfiles)
+			products.POST("/import-profiles", handler.BindJSON[domain.ImportProfileRequest](), importHandler.CreateProfile)
+			products.PUT("/import-profiles/:id", handler.BindJSON[domain.ImportProfileRequest](), importHandler.UpdateProfile)
+			products.DELETE("/import-profiles/:id", importHandler.DeleteProfile)
+
+			// A product may be addressed by its short code as well as its ID
+			product := products.Group("/:id", handler.ProductCodeMiddleware(productService))
+			{
+				product.GET("", productHandler.GetByID)
+				product.PUT("", handler.BindJSON[domain.UpdateProductRequest](), productHandler.Update)
+				product.DELETE("", productHandler.Delete)
+				product.POST("/favorite", favoriteHandler.Add)
+				product.DELETE("/favorite", favoriteHandler.Remove)
+				product.PUT("/visibility", catalogHandler.SetVisibility)
+				product.POST("/publish", productHandler.Publish)
+				product.POST("/unpublish", productHandler.Unpublish)
+				product.POST("/archive", productHandler.Archive)
+				product.POST("/unarchive", productHandler.Unarchive)
+				product.POST("/stock-token", stockTokenHandler.Issue)
+				product.DELETE("/stock-tokens", stockTokenHandler.Revoke)
+				product.GET("/stock-levels", stockTemplateHandler.Levels)
+				product.POST("/stock/decrement", handler.BindJSON[domain.StockDecrementRequest](), productHandler.DecrementStock)
+				product.POST("/reservations", handler.BindJSON[domain.StockReservationRequest](), reservationHandler.Create)
+				product.GET("/reservations/:reservation_id", reservationHandler.Get)
+				product.POST("/reservations/:reservation_id/confirm", reservationHandler.Confirm)
+				product.DELETE("/reservations/:reservation_id", reservationHandler.Release)
+				product.GET("/locations", locationHandler.ProductLocations)
+				product.GET("/related", recommendationHandler.Related)
+				product.GET("/reviews", reviewHandler.List)
+				product.POST("/reviews", handler.BindJSON[domain.ReviewRequest](), reviewHandler.Create)
+				product.PUT("/reviews/:review_id/status", handler.BindJSON[domain.ReviewModerationRequest](), reviewHandler.Moderate)
+				product.GET("/notes", noteHandler.List)
+				product.POST("/notes", handler.BindJSON[domain.NoteRequest](), noteHandler.Create)
+				product.PUT("/notes/:note_id", handler.BindJSON[domain.NoteRequest](), noteHandler.Update)
+				product.DELETE("/notes/:note_id", noteHandler.Delete)
+				product.POST("/locations/:location_id/stock", handler.BindJSON[domain.LocationStockAdjustRequest](), locationHandler.AdjustStock)
+			}
+		}
+
+		// Order routes
+		orders := protected.Group("/orders")
+		{
+			orders.GET("/", orderHandler.List)
+			orders.POST("/", handler.BindJSON[domain.CreateOrderRequest](), orderHandler.Create)
+			orders.GET("/:id", orderHandler.Get)
+			orders.POST("/:id/cancel", orderHandler.Cancel)
+		}
+
+		// Supplier routes
+		suppliers := protected.Group("/suppliers")
+		{
+			suppliers.GET("/", procurementHandler.ListSuppliers)
+			suppliers.POST("/", handler.BindJSON[domain.SupplierRequest](), procurementHandler.CreateSupplier)
+			suppliers.GET("/:id", procurementHandler.GetSupplier)
+			suppliers.PUT("/:id", handler.BindJSON[domain.SupplierRequest](), procurementHandler.UpdateSupplier)
+			suppliers.DELETE("/:id", procurementHandler.DeleteSupplier)
+		}
+
+		// Location routes
+		locations := protected.Group("/locations")
+		{
+			locations.GET("/", locationHandler.List)
+			locations.POST("/", handler.BindJSON[domain.LocationRequest](), locationHandler.Create)
+			locations.GET("/:id", locationHandler.Get)
+			locations.PUT("/:id", handler.BindJSON[domain.LocationRequest](), locationHandler.Update)
+			locations.DELETE("/:id", locationHandler.Delete)
+			locations.GET("/:id/stats", locationHandler.Stats)
+		}
+
+		// Purchase order routes
+		purchaseOrders := protected.Group("/purchase-orders")
+		{
+			purchaseOrders.GET("/", procurementHandler.ListPurchaseOrders)
+			purchaseOrders.POST("/", handler.BindJSON[domain.PurchaseOrderRequest](), procurementHandler.CreatePurchaseOrder)
+			purchaseOrders.GET("/:id", procurementHandler.GetPurchaseOrder)
+			purchaseOrders.POST("/:id/receive", procurementHandler.ReceivePurchaseOrder)
+			purchaseOrders.POST("/:id/cancel", procurementHandler.CancelPurchaseOrder)
+		}
+
+		// Attribute definition routes
+		attributes := protected.Group("/attributes")
+		{
+			attributes.GET("/", attributeHandler.List)
+			attributes.PUT("/:key", attributeHandler.Define)
+			attributes.DELETE("/:key", attributeHandler.Delete)
+		}
+
+		// Stock template routes
+		stockTemplates := protected.Group("/stock-templates")
+		{
+			stockTemplates.GET("/", stockTemplateHandler.List)
+			stockTemplates.PUT("/:category", handler.BindJSON[domain.StockTemplateRequest](), stockTemplateHandler.Define)
+			stockTemplates.DELETE("/:category", stockTemplateHandler.Delete)
+		}
+
+		// Backup routes
+		backups := protected.Group("/backups")
+		{
+			backups.POST("/", backupHandler.Create)
+			backups.GET("/", backupHandler.List)
+			backups.POST("/restore", bulkBodyLimit, backupHandler.Restore)
+		}
+	}
+
+	// v2 routes share services and authentication with v1 but use v2 DTO shapes
+	publicV2 := router.Group("/api/v2")
+	publicV2.Use(handler.VersionMiddleware(handler.APIVersionV2))
+	publicV2.Use(authBodyLimit)
+	{
+		publicV2.POST("/auth/register", handler.BindJSON[domain.CreateUserRequest](), userHandler.Register)
+		publicV2.POST("/auth/login", handler.BindJSON[domain.LoginRequest](), userHandler.Login)
+		publicV2.POST("/auth/verify-email", onboardingHandler.VerifyEmail)
+	}
+
+	protectedV2 := router.Group("/api/v2")
+	protectedV2.Use(handler.VersionMiddleware(handler.APIVersionV2))
+	protectedV2.Use(handler.AuthMiddleware(userService, signingKeys))
+	protectedV2.Use(handler.PlanRateLimitMiddleware(planService, userLimiter))
+	protectedV2.Use(handler.QuotaWarningMiddleware(quotaService))
+	protectedV2.Use(handler.PageSizeMiddleware(onboardingService))
+	protectedV2.Use(handler.PriceFormatMiddleware(onboardingService))
+	if !readOnly {
+		protectedV2.Use(handler.UsageMiddleware(usageService))
+	}
+	{
+		auth := protectedV2.Group("/auth", authBodyLimit)
+		{
+			auth.POST("/refresh", userHandler.RefreshToken)
+			auth.POST("/logout", userHandler.Logout)
+		}
+
+		products := protectedV2.Group("/products")
+		{
+			products.POST("/", handler.BindJSON[domain.CreateProductV2Request](), productV2Handler.Create)
+			products.GET("/", productV2Handler.List)
+
+			product := products.Group("/:id", handler.ProductCodeMiddleware(productService))
+			{
+				product.GET("", productV2Handler.GetByID)
+				product.PUT("", handler.BindJSON[domain.UpdateProductV2Request](), productV2Handler.Update)
+				product.DELETE("", productV2Handler.Delete)
+			}
 		}
 	}
 
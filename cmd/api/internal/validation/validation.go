@@ -4,6 +4,9 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+
+	"products/internal/domain"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Validation constants
@@ -22,34 +25,16 @@ const (
 	MaxStock            = 999999
 )
 
-// Validation regex patterns
+// Validation regex patterns. Names and descriptions accept letters, combining marks
+// and digits from any script, so "Café" and "Müller" are valid.
 var (
 	emailRegex       = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	passwordRegex    = regexp.MustCompile(`^[A-Za-z\d@$!%*?&]{8,}$`)
-	nameRegex        = regexp.MustCompile(`^[a-zA-Z\s\-'\.]+$`)
-	productNameRegex = regexp.MustCompile(`^[a-zA-Z0-9\s\-_.,!?()&]+$`)
-	descriptionRegex = regexp.MustCompile(`^[a-zA-Z0-9\s\-_.,!?()&@#$%*+=:;'"<>[\]{}|\\/~]+$`)
+	nameRegex        = regexp.MustCompile(`^[\p{L}\p{M}\s\-'’\.]+$`)
+	productNameRegex = regexp.MustCompile(`^[\p{L}\p{M}\p{N}\s\-_.,!?()&]+$`)
+	descriptionRegex = regexp.MustCompile(`^[\p{L}\p{M}\p{N}\s\-_.,!?()&@#$%*+=:;'’"“”<>[\]{}|\\/~^\x60€£¥]+$`)
 )
 
-// ValidateEmail validates email format and length
-func ValidateEmail(email string) error {
-	email = strings.TrimSpace(email)
-	
-	if email == "" {
-		return errors.New("email is required")
-	}
-	
-	if len(email) > MaxEmailLength {
-		return errors.New("email is too long")
-	}
-	
-	if !emailRegex.MatchString(email) {
-		return errors.New("invalid email format")
-	}
-	
-	return nil
-}
-
 // ValidatePassword validates password strength and length
 func ValidatePassword(password string) error {
 	password = strings.TrimSpace(password)
@@ -94,101 +79,40 @@ func ValidatePassword(password string) error {
 	return nil
 }
 
-// ValidateName validates name format and length
-func ValidateName(name string) error {
-	name = strings.TrimSpace(name)
-	
-	if name == "" {
-		return errors.New("name is required")
-	}
-	
-	if len(name) < MinNameLength {
-		return errors.New("name must be at least 2 characters long")
-	}
-	
-	if len(name) > MaxNameLength {
-		return errors.New("name is too long")
-	}
-	
-	if !nameRegex.MatchString(name) {
-		return errors.New("name contains invalid characters")
+// ValidateAttributes validates the structure of product attributes.
+// Schema checks against user-defined attribute definitions happen in the service layer.
+func ValidateAttributes(attributes map[string]interface{}) error {
+	if len(attributes) > domain.MaxAttributes {
+		return errors.New("too many attributes")
 	}
-	
-	return nil
-}
 
-// ValidateProductName validates product name format and length
-func ValidateProductName(name string) error {
-	name = strings.TrimSpace(name)
-	
-	if name == "" {
-		return errors.New("product name is required")
-	}
-	
-	if len(name) < MinProductNameLength {
-		return errors.New("product name must be at least 2 characters long")
-	}
-	
-	if len(name) > MaxProductNameLength {
-		return errors.New("product name is too long")
-	}
-	
-	if !productNameRegex.MatchString(name) {
-		return errors.New("product name contains invalid characters")
+	for key := range attributes {
+		if err := domain.ValidateAttributeKey(key); err != nil {
+			return err
+		}
 	}
-	
-	return nil
-}
 
-// ValidateDescription validates product description format and length
-func ValidateDescription(description string) error {
-	if description == "" {
-		return nil // Description is optional
-	}
-	
-	description = strings.TrimSpace(description)
-	
-	if len(description) > MaxDescriptionLength {
-		return errors.New("description is too long")
-	}
-	
-	if !descriptionRegex.MatchString(description) {
-		return errors.New("description contains invalid characters")
-	}
-	
 	return nil
 }
 
-// ValidatePrice validates product price range
-func ValidatePrice(price float64) error {
-	if price < MinPrice {
-		return errors.New("price must be greater than 0")
-	}
-	
-	if price > MaxPrice {
-		return errors.New("price is too high")
-	}
-	
-	return nil
+// SanitizeInput removes potentially dangerous characters and normalizes Unicode to NFC,
+// so precomposed and decomposed accents validate and compare the same
+func SanitizeInput(input string) string {
+	return sanitize(input, false)
 }
 
-// ValidateStock validates product stock range
-func ValidateStock(stock int) error {
-	if stock < MinStock {
-		return errors.New("stock cannot be negative")
-	}
-	
-	if stock > MaxStock {
-		return errors.New("stock value is too high")
-	}
-	
-	return nil
+// SanitizeMultiline is SanitizeInput for multi-line text such as Markdown; it keeps
+// line breaks and tabs and normalizes CRLF line endings to LF
+func SanitizeMultiline(input string) string {
+	return sanitize(strings.ReplaceAll(input, "\r\n", "\n"), true)
 }
 
-// SanitizeInput removes potentially dangerous characters
-func SanitizeInput(input string) string {
-	// Remove null bytes and control characters
+// sanitize removes null bytes and control characters, optionally keeping line breaks and tabs
+func sanitize(input string, multiline bool) string {
 	input = strings.Map(func(r rune) rune {
+		if multiline && (r == '\n' || r == '\t') {
+			return r
+		}
 		if r < 32 || r == 127 {
 			return -1
 		}
@@ -196,23 +120,5 @@ func SanitizeInput(input string) string {
 	}, input)
 	
 	// Trim whitespace
-	return strings.TrimSpace(input)
-}
-
-// CheckSQLInjection checks for common SQL injection patterns
-func CheckSQLInjection(input string) bool {
-	lowerInput := strings.ToLower(input)
-	dangerousPatterns := []string{
-		"union", "select", "insert", "update", "delete", "drop", "create",
-		"alter", "exec", "execute", "script", "javascript", "vbscript",
-		"<script", "javascript:", "onload", "onerror", "onclick",
-	}
-	
-	for _, pattern := range dangerousPatterns {
-		if strings.Contains(lowerInput, pattern) {
-			return true
-		}
-	}
-	
-	return false
+	return strings.TrimSpace(norm.NFC.String(input))
 }
@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"products/internal/domain"
+	"products/cmd/api/internal/i18n"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// validators maps custom binding tags to their implementations
+var validators = map[string]validator.Func{
+	"email_address": func(fl validator.FieldLevel) bool {
+		email := fl.Field().String()
+		return len(email) <= MaxEmailLength && emailRegex.MatchString(email)
+	},
+	"password": func(fl validator.FieldLevel) bool {
+		return ValidatePassword(fl.Field().String()) == nil
+	},
+	"person_name": func(fl validator.FieldLevel) bool {
+		name := fl.Field().String()
+		length := utf8.RuneCountInString(name)
+		return length >= MinNameLength && length <= MaxNameLength && nameRegex.MatchString(name)
+	},
+	"product_name": func(fl validator.FieldLevel) bool {
+		name := fl.Field().String()
+		length := utf8.RuneCountInString(name)
+		return length >= MinProductNameLength && length <= MaxProductNameLength && productNameRegex.MatchString(name)
+	},
+	"description": func(fl validator.FieldLevel) bool {
+		description := fl.Field().String()
+		return description == "" || (utf8.RuneCountInString(description) <= MaxDescriptionLength && descriptionRegex.MatchString(description))
+	},
+	"price": func(fl validator.FieldLevel) bool {
+		price := fl.Field().Float()
+		return price >= MinPrice && price <= MaxPrice
+	},
+	"price_cents": func(fl validator.FieldLevel) bool {
+		cents := fl.Field().Int()
+		return cents >= domain.PriceToCents(MinPrice) && cents <= domain.PriceToCents(MaxPrice)
+	},
+	"stock": func(fl validator.FieldLevel) bool {
+		stock := fl.Field().Int()
+		return stock >= MinStock && stock <= MaxStock
+	},
+	"attributes": func(fl validator.FieldLevel) bool {
+		attributes, ok := fl.Field().Interface().(domain.Attributes)
+		return ok && ValidateAttributes(attributes) == nil
+	},
+}
+
+// message is the i18n key and format arguments describing a failed binding tag
+type message struct {
+	key  string
+	args []interface{}
+}
+
+// messages describes each failed binding tag to API clients
+var messages = map[string]message{
+	"required":      {i18n.ValidationRequired, nil},
+	"email":         {i18n.ValidationEmail, nil},
+	"email_address": {i18n.ValidationEmail, nil},
+	"password":      {i18n.ValidationPassword, []interface{}{MinPasswordLength, MaxPasswordLength}},
+	"person_name":   {i18n.ValidationPersonName, []interface{}{MinNameLength, MaxNameLength}},
+	"product_name":  {i18n.ValidationProductName, []interface{}{MinProductNameLength, MaxProductNameLength}},
+	"description":   {i18n.ValidationDescription, []interface{}{MaxDescriptionLength}},
+	"price":         {i18n.ValidationPrice, []interface{}{MinPrice, MaxPrice}},
+	"price_cents":   {i18n.ValidationPriceCents, []interface{}{domain.PriceToCents(MinPrice), domain.PriceToCents(MaxPrice)}},
+	"stock":         {i18n.ValidationStock, []interface{}{MinStock, MaxStock}},
+	"attributes":    {i18n.ValidationAttributes, []interface{}{domain.MaxAttributes}},
+}
+
+var registerOnce sync.Once
+
+// Register installs the custom validators and JSON field naming on gin's validator
+func Register() {
+	registerOnce.Do(func() {
+		engine, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+
+		for tag, fn := range validators {
+			if err := engine.RegisterValidation(tag, fn); err != nil {
+				panic(fmt.Sprintf("failed to register validator %q: %v", tag, err))
+			}
+		}
+	})
+}
+
+// FieldErrors converts validator errors into one entry per failed field, with
+// messages in the given language
+func FieldErrors(err error, lang string) []domain.FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	result := make([]domain.FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		text := i18n.T(lang, i18n.ValidationRule, strings.TrimSpace(fieldErr.Tag()+"="+fieldErr.Param()))
+		if msg, ok := messages[fieldErr.Tag()]; ok {
+			text = i18n.T(lang, msg.key, msg.args...)
+		}
+		result = append(result, domain.FieldError{Field: fieldErr.Field(), Message: text})
+	}
+	return result
+}
+
+// Sanitize cleans every string field tagged `sanitize:"true"` in the struct pointed to by v;
+// fields tagged `sanitize:"multiline"` keep their line breaks
+func Sanitize(v interface{}) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+	value = value.Elem()
+
+	for i := 0; i < value.NumField(); i++ {
+		mode := value.Type().Field(i).Tag.Get("sanitize")
+		if mode != "true" && mode != "multiline" {
+			continue
+		}
+
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.String || !field.CanSet() {
+			continue
+		}
+
+		if mode == "multiline" {
+			field.SetString(SanitizeMultiline(field.String()))
+		} else {
+			field.SetString(SanitizeInput(field.String()))
+		}
+	}
+}
@@ -2,17 +2,34 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"products/internal/database"
+	"products/internal/domain"
+	"products/internal/lifecycle"
 	"products/internal/repository"
+	"products/internal/resilience"
 	"products/internal/service"
+	"products/internal/service/memorycache"
+	"products/internal/storage"
+	"products/internal/timing"
+	"products/cmd/api/internal/handler"
 	"products/cmd/api/internal/router"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/acme/autocert"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -22,66 +39,838 @@ func main() {
 		jwtSecret = "your-super-secret-jwt-key-change-in-production"
 	}
 
-	// Initialize database
+	// Token signing keys come from JWT_KEYS or the file named by JWT_KEYS_FILE;
+	// JWT_SECRET stays configured as the legacy key so that tokens issued before
+	// rotation remain valid until they expire
+	var signingKeysConfig service.SigningKeysConfig
+	if value := os.Getenv("JWT_KEYS"); value != "" {
+		parsed, err := service.ParseSigningKeysConfig([]byte(value))
+		if err != nil {
+			log.Fatalf("Invalid JWT_KEYS: %v", err)
+		}
+		signingKeysConfig = parsed
+	} else if path := os.Getenv("JWT_KEYS_FILE"); path != "" {
+		loaded, err := service.LoadSigningKeysConfig(path)
+		if err != nil {
+			log.Fatalf("Invalid JWT_KEYS_FILE: %v", err)
+		}
+		signingKeysConfig = loaded
+	}
+	if !slices.ContainsFunc(signingKeysConfig.Keys, func(key service.SigningKeyConfig) bool { return key.ID == service.LegacyKeyID }) {
+		signingKeysConfig.Keys = append(signingKeysConfig.Keys, service.SigningKeyConfig{ID: service.LegacyKeyID, Algorithm: service.AlgorithmHS256, Secret: jwtSecret})
+	}
+	signingKeys, err := service.NewSigningKeys(signingKeysConfig)
+	if err != nil {
+		log.Fatalf("Invalid signing keys: %v", err)
+	}
+
+	// Stored export credentials are encrypted with a dedicated key, never a default
+	exportEncryptionKey := os.Getenv("EXPORT_ENCRYPTION_KEY")
+	if exportEncryptionKey == "" {
+		log.Fatal("EXPORT_ENCRYPTION_KEY is required")
+	}
+
+	deletionGracePeriod := service.DefaultDeletionGracePeriod
+	if value := os.Getenv("ACCOUNT_DELETION_GRACE_PERIOD"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid ACCOUNT_DELETION_GRACE_PERIOD: %v", err)
+		}
+		deletionGracePeriod = parsed
+	}
+
+	auditConfig := service.DefaultAuditConfig()
+	if value := os.Getenv("AUDIT_RETENTION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid AUDIT_RETENTION: %v", err)
+		}
+		auditConfig.Retention = parsed
+	}
+	if value := os.Getenv("AUDIT_MAX_BODY_BYTES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("Invalid AUDIT_MAX_BODY_BYTES: %v", err)
+		}
+		auditConfig.MaxBodyBytes = parsed
+	}
+	if value := os.Getenv("AUDIT_SAMPLE_RATES"); value != "" {
+		parsed, err := domain.ParseAuditSampleRates(value)
+		if err != nil {
+			log.Fatalf("Invalid AUDIT_SAMPLE_RATES: %v", err)
+		}
+		auditConfig.Sampling.Rates = parsed
+	}
+	if value := os.Getenv("AUDIT_SAMPLE_OVERRIDES"); value != "" {
+		parsed, err := domain.ParseAuditSampleOverrides(value)
+		if err != nil {
+			log.Fatalf("Invalid AUDIT_SAMPLE_OVERRIDES: %v", err)
+		}
+		auditConfig.Sampling.Overrides = parsed
+	}
+
+	// Logins from an address with repeated failures require a CAPTCHA when a
+	// provider is configured
+	var captchaVerifier service.CaptchaVerifier
+	if provider := os.Getenv("CAPTCHA_PROVIDER"); provider != "" {
+		verifier, err := service.NewCaptchaVerifier(provider, os.Getenv("CAPTCHA_SECRET"))
+		if err != nil {
+			log.Fatalf("Invalid CAPTCHA_PROVIDER: %v", err)
+		}
+		captchaVerifier = verifier
+	}
+
+	captchaThreshold := int64(service.DefaultCaptchaThreshold)
+	if value := os.Getenv("CAPTCHA_THRESHOLD"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid CAPTCHA_THRESHOLD: %q", value)
+		}
+		captchaThreshold = parsed
+	}
+
+	captchaWindow := service.DefaultCaptchaWindow
+	if value := os.Getenv("CAPTCHA_WINDOW"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid CAPTCHA_WINDOW: %q", value)
+		}
+		captchaWindow = parsed
+	}
+
+	newDeviceVerification := false
+	if value := os.Getenv("NEW_DEVICE_VERIFICATION"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid NEW_DEVICE_VERIFICATION: %v", err)
+		}
+		newDeviceVerification = parsed
+	}
+
+	var geoLocator service.GeoLocator
+	if value := os.Getenv("GEOIP_LOOKUP_URL"); value != "" {
+		locator, err := service.NewHTTPGeoLocator(value)
+		if err != nil {
+			log.Fatalf("Invalid GEOIP_LOOKUP_URL: %v", err)
+		}
+		geoLocator = locator
+	}
+
+	snapshotRetention := service.DefaultDailySnapshotRetention
+	if value := os.Getenv("METRICS_DAILY_RETENTION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid METRICS_DAILY_RETENTION: %v", err)
+		}
+		snapshotRetention = parsed
+	}
+
+	eventRetention := service.DefaultEventRetention
+	if value := os.Getenv("EVENT_RETENTION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid EVENT_RETENTION: %v", err)
+		}
+		eventRetention = parsed
+	}
+
+	// PRODUCT_QUOTA sets the product quota of the free plan, the first of the
+	// default plans; PLANS overrides the limits of any plan
+	plans := domain.DefaultPlans()
+	if value := os.Getenv("PRODUCT_QUOTA"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid PRODUCT_QUOTA: %v", err)
+		}
+		plans[0].ProductLimit = parsed
+	}
+	if value := os.Getenv("PLANS"); value != "" {
+		if err := domain.ApplyPlanLimits(plans, []byte(value)); err != nil {
+			log.Fatalf("Invalid PLANS: %v", err)
+		}
+	}
+
+	// Billing takes plan payments through Stripe when both keys are set
+	billingConfig := service.BillingConfig{
+		SecretKey:       os.Getenv("STRIPE_SECRET_KEY"),
+		WebhookSecret:   os.Getenv("STRIPE_WEBHOOK_SECRET"),
+		Prices:          map[string]string{},
+		SuccessURL:      os.Getenv("BILLING_SUCCESS_URL"),
+		CancelURL:       os.Getenv("BILLING_CANCEL_URL"),
+		PortalReturnURL: os.Getenv("BILLING_PORTAL_RETURN_URL"),
+	}
+	if (billingConfig.SecretKey == "") != (billingConfig.WebhookSecret == "") {
+		log.Fatal("STRIPE_SECRET_KEY and STRIPE_WEBHOOK_SECRET must be set together")
+	}
+	if value := os.Getenv("STRIPE_PRICE_PRO"); value != "" {
+		billingConfig.Prices[domain.PlanPro] = value
+	}
+	if value := os.Getenv("STRIPE_PRICE_ENTERPRISE"); value != "" {
+		billingConfig.Prices[domain.PlanEnterprise] = value
+	}
+	if billingConfig.Enabled() {
+		if len(billingConfig.Prices) == 0 {
+			log.Fatal("Billing requires STRIPE_PRICE_PRO or STRIPE_PRICE_ENTERPRISE")
+		}
+		if billingConfig.SuccessURL == "" || billingConfig.CancelURL == "" {
+			log.Fatal("Billing requires BILLING_SUCCESS_URL and BILLING_CANCEL_URL")
+		}
+	}
+
+	quotaConfig := service.DefaultQuotaConfig()
+	if value := os.Getenv("PRODUCT_QUOTA_GRACE_PERIOD"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid PRODUCT_QUOTA_GRACE_PERIOD: %v", err)
+		}
+		quotaConfig.GracePeriod = parsed
+	}
+
+	stockTokenTTL := service.DefaultStockTokenTTL
+	if value := os.Getenv("STOCK_TOKEN_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid STOCK_TOKEN_TTL: %v", err)
+		}
+		stockTokenTTL = parsed
+	}
+
+	publicRateLimit := int64(30)
+	if value := os.Getenv("PUBLIC_RATE_LIMIT"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid PUBLIC_RATE_LIMIT: %v", err)
+		}
+		publicRateLimit = parsed
+	}
+
+	shutdownDrainPeriod := 10 * time.Second
+	if value := os.Getenv("SHUTDOWN_DRAIN_PERIOD"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid SHUTDOWN_DRAIN_PERIOD: %q", value)
+		}
+		shutdownDrainPeriod = parsed
+	}
+
+	shutdownTimeout := 30 * time.Second
+	if value := os.Getenv("SHUTDOWN_TIMEOUT"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid SHUTDOWN_TIMEOUT: %q", value)
+		}
+		shutdownTimeout = parsed
+	}
+
+	requestTimeout := 30 * time.Second
+	if value := os.Getenv("REQUEST_TIMEOUT"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid REQUEST_TIMEOUT: %q", value)
+		}
+		requestTimeout = parsed
+	}
+
+	bodyLimits := handler.DefaultBodyLimits()
+	for name, limit := range map[string]*int64{
+		"MAX_BODY_SIZE":      &bodyLimits.Default,
+		"MAX_AUTH_BODY_SIZE": &bodyLimits.Auth,
+		"MAX_BULK_BODY_SIZE": &bodyLimits.Bulk,
+	} {
+		if value := os.Getenv(name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed <= 0 {
+				log.Fatalf("Invalid %s: %q", name, value)
+			}
+			*limit = parsed
+		}
+	}
+
+	serverTiming := true
+	if value := os.Getenv("SERVER_TIMING"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid SERVER_TIMING: %v", err)
+		}
+		serverTiming = parsed
+	}
+
+	// Structured access log on stdout; ACCESS_LOG=false turns it off
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	accessLog := handler.DefaultAccessLogConfig()
+	accessLog.Logger = logger
+	if value := os.Getenv("ACCESS_LOG"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid ACCESS_LOG: %v", err)
+		}
+		if !parsed {
+			accessLog.Logger = nil
+		}
+	}
+	if value := os.Getenv("ACCESS_LOG_BODIES"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid ACCESS_LOG_BODIES: %v", err)
+		}
+		accessLog.Bodies = parsed
+	}
+	if value := os.Getenv("ACCESS_LOG_MAX_BODY_BYTES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid ACCESS_LOG_MAX_BODY_BYTES: %q", value)
+		}
+		accessLog.MaxBodyBytes = parsed
+	}
+	if value := os.Getenv("ACCESS_LOG_SAMPLE_RATES"); value != "" {
+		parsed, err := handler.ParseAccessLogSampleRates(value)
+		if err != nil {
+			log.Fatalf("Invalid ACCESS_LOG_SAMPLE_RATES: %v", err)
+		}
+		accessLog.SampleRates = parsed
+	}
+	if value := os.Getenv("ACCESS_LOG_SLOW_THRESHOLD"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid ACCESS_LOG_SLOW_THRESHOLD: %q", value)
+		}
+		accessLog.SlowThreshold = parsed
+	}
+
+	// Reverse proxies trusted to report the client IP; none by default
+	var proxyConfig handler.ProxyConfig
+	if value := os.Getenv("TRUSTED_PROXIES"); value != "" {
+		parsed, err := handler.ParseTrustedProxies(value)
+		if err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+		}
+		proxyConfig.TrustedProxies = parsed
+	}
+	proxyConfig.TrustedPlatform = os.Getenv("TRUSTED_PLATFORM")
+
+	// Error tracking: panics and server errors are sent to Sentry when SENTRY_DSN is set
+	sentryConfig := service.DefaultSentryConfig()
+	sentryConfig.DSN = os.Getenv("SENTRY_DSN")
+	if value := os.Getenv("APP_ENV"); value != "" {
+		sentryConfig.Environment = value
+	}
+	if value := os.Getenv("SENTRY_ENVIRONMENT"); value != "" {
+		sentryConfig.Environment = value
+	}
+	sentryConfig.Release = os.Getenv("SENTRY_RELEASE")
+	if value := os.Getenv("SENTRY_USER_IDS"); value != "" {
+		sentryConfig.UserIDs = value
+	}
+	errorReporter, err := service.NewErrorReporter(sentryConfig)
+	if err != nil {
+		log.Fatalf("Invalid Sentry configuration: %v", err)
+	}
+
+	// Debugging aids for reproducing and profiling performance problems
+	var debug handler.DebugConfig
+	if value := os.Getenv("DEBUG_FAULTS"); value != "" {
+		parsed, err := handler.ParseFaultRules(value)
+		if err != nil {
+			log.Fatalf("Invalid DEBUG_FAULTS: %v", err)
+		}
+		debug.Faults = parsed
+		log.Printf("Injecting faults into %d route patterns (DEBUG_FAULTS)", len(parsed))
+	}
+	if value := os.Getenv("DEBUG_PPROF"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid DEBUG_PPROF: %v", err)
+		}
+		debug.Pprof = parsed
+	}
+
+	port := "8080"
+	if value := os.Getenv("PORT"); value != "" {
+		port = value
+	}
+
+	readHeaderTimeout := 10 * time.Second
+	if value := os.Getenv("SERVER_READ_HEADER_TIMEOUT"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid SERVER_READ_HEADER_TIMEOUT: %q", value)
+		}
+		readHeaderTimeout = parsed
+	}
+
+	idleTimeout := 120 * time.Second
+	if value := os.Getenv("SERVER_IDLE_TIMEOUT"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid SERVER_IDLE_TIMEOUT: %q", value)
+		}
+		idleTimeout = parsed
+	}
+
+	// TLS is terminated with a certificate and key, or with certificates obtained
+	// from Let's Encrypt for the autocert domains
+	tlsCertFile := os.Getenv("TLS_CERT_FILE")
+	tlsKeyFile := os.Getenv("TLS_KEY_FILE")
+	if (tlsCertFile == "") != (tlsKeyFile == "") {
+		log.Fatal("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	var autocertDomains []string
+	if value := os.Getenv("TLS_AUTOCERT_DOMAINS"); value != "" {
+		for _, host := range strings.Split(value, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				autocertDomains = append(autocertDomains, host)
+			}
+		}
+	}
+	if tlsCertFile != "" && len(autocertDomains) > 0 {
+		log.Fatal("TLS_CERT_FILE and TLS_AUTOCERT_DOMAINS are mutually exclusive")
+	}
+	autocertCacheDir := "certs"
+	if value := os.Getenv("TLS_AUTOCERT_CACHE_DIR"); value != "" {
+		autocertCacheDir = value
+	}
+	tlsEnabled := tlsCertFile != "" || len(autocertDomains) > 0
+
+	httpRedirectPort := os.Getenv("HTTP_REDIRECT_PORT")
+	if httpRedirectPort != "" && !tlsEnabled {
+		log.Fatal("HTTP_REDIRECT_PORT requires TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS")
+	}
+
+	readOnly := false
+	if value := os.Getenv("READ_ONLY_MODE"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid READ_ONLY_MODE: %v", err)
+		}
+		readOnly = parsed
+	}
+
+	redisRequired := false
+	if value := os.Getenv("REDIS_REQUIRED"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid REDIS_REQUIRED: %v", err)
+		}
+		redisRequired = parsed
+	}
+
+	sessionStatelessFallback := false
+	if value := os.Getenv("SESSION_STATELESS_FALLBACK"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid SESSION_STATELESS_FALLBACK: %v", err)
+		}
+		sessionStatelessFallback = parsed
+	}
+
+	// Lifetimes of sessions and refresh tokens, longer for remember-me logins
+	sessionTTL := service.DefaultSessionDuration
+	if value := os.Getenv("SESSION_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid SESSION_TTL: %q", value)
+		}
+		sessionTTL = parsed
+	}
+	sessionRememberMeTTL := service.DefaultRememberMeDuration
+	if value := os.Getenv("SESSION_REMEMBER_ME_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid SESSION_REMEMBER_ME_TTL: %q", value)
+		}
+		sessionRememberMeTTL = parsed
+	}
+
+	// Sliding expiration keeps active sessions alive up to a maximum lifetime
+	sessionSliding := false
+	if value := os.Getenv("SESSION_SLIDING_EXPIRATION"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid SESSION_SLIDING_EXPIRATION: %v", err)
+		}
+		sessionSliding = parsed
+	}
+	sessionMaxLifetime := 7 * 24 * time.Hour
+	if value := os.Getenv("SESSION_MAX_LIFETIME"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid SESSION_MAX_LIFETIME: %q", value)
+		}
+		sessionMaxLifetime = parsed
+	}
+
+	// Cap on the active sessions of a user (0 for no limit) and what logins beyond it do
+	sessionLimit := 0
+	if value := os.Getenv("SESSION_LIMIT"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid SESSION_LIMIT: %q", value)
+		}
+		sessionLimit = parsed
+	}
+	sessionLimitPolicy := os.Getenv("SESSION_LIMIT_POLICY")
+	if sessionLimitPolicy == "" {
+		sessionLimitPolicy = service.SessionLimitEvictOldest
+	}
+	if sessionLimitPolicy != service.SessionLimitEvictOldest && sessionLimitPolicy != service.SessionLimitReject {
+		log.Fatalf("Invalid SESSION_LIMIT_POLICY: %q", sessionLimitPolicy)
+	}
+
+	// Where sessions are kept: Redis (default) or PostgreSQL, for deployments without Redis
+	sessionStore := os.Getenv("SESSION_STORE")
+	if sessionStore == "" {
+		sessionStore = service.SessionStoreRedis
+	}
+	if sessionStore != service.SessionStoreRedis && sessionStore != service.SessionStorePostgres {
+		log.Fatalf("Invalid SESSION_STORE: %q", sessionStore)
+	}
+
+	// Resilience policies of the database and Redis, see resilience.PolicyFromEnv
+	dbPolicy, err := resilience.PolicyFromEnv("DB", resilience.DefaultDatabasePolicy)
+	if err != nil {
+		log.Fatalf("Invalid database resilience policy: %v", err)
+	}
+	redisPolicy, err := resilience.PolicyFromEnv("REDIS", resilience.DefaultRedisPolicy)
+	if err != nil {
+		log.Fatalf("Invalid Redis resilience policy: %v", err)
+	}
+
+	// Initialize database; read-only mode serves from the replica during primary maintenance
 	dbConfig := database.NewConfig()
+	if readOnly {
+		log.Println("Read-only mode: serving GET requests from the database replica")
+		dbConfig = database.NewReplicaConfig()
+	}
 	db, err := database.Connect(dbConfig)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Initialize Redis
-	redisConfig := database.NewRedisConfig()
-	redisClient, err := database.ConnectRedis(redisConfig)
-	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+	// Initialize Redis; unless it is required, start degraded and let the cache
+	// circuit breaker pick it up once it becomes reachable. The test environment
+	// keeps the cache in memory instead.
+	hermetic := os.Getenv("APP_ENV") == "test"
+	var redisClient *redis.Client
+	if hermetic {
+		log.Println("APP_ENV=test: using the in-memory cache instead of Redis")
+	} else {
+		redisConfig := database.NewRedisConfig()
+		if redisClient, err = database.ConnectRedis(redisConfig); err != nil {
+			if redisRequired {
+				log.Fatalf("Failed to connect to Redis: %v", err)
+			}
+			log.Printf("Redis unavailable, starting without cache: %v", err)
+			redisClient = database.NewRedisClient(redisConfig)
+		}
 	}
-	defer database.CloseRedis(redisClient)
 
 	// Run database migrations
-	if err := database.Migrate(db); err != nil {
-		log.Fatalf("Failed to run database migrations: %v", err)
+	if !readOnly {
+		if err := database.Migrate(db); err != nil {
+			log.Fatalf("Failed to run database migrations: %v", err)
+		}
+	}
+
+	// Protect database calls once migrations, which may run long, are done
+	dbExecutor := resilience.NewExecutor("database", dbPolicy, database.IsTransientError)
+	if err := resilience.InstrumentGORM(db, dbExecutor); err != nil {
+		log.Fatalf("Failed to instrument database: %v", err)
+	}
+	if err := timing.RegisterGORM(db); err != nil {
+		log.Fatalf("Failed to instrument database: %v", err)
+	}
+	redisExecutor := resilience.NewExecutor("redis", redisPolicy, database.IsTransientRedisError)
+	redisExecutor.Optional = !redisRequired
+	dependencies := []*resilience.Executor{dbExecutor}
+	if !hermetic {
+		dependencies = append(dependencies, redisExecutor)
+	}
+
+	// Initialize object storage (optional)
+	var objectStore storage.ObjectStore
+	if s3Store, err := storage.NewS3Store(storage.NewS3Config()); err != nil {
+		log.Printf("Object storage disabled: %v", err)
+	} else {
+		objectStore = s3Store
 	}
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
-	productRepo := repository.NewProductRepository(db)
+	attributeRepo := repository.NewAttributeDefinitionRepository(db)
+	productRepo, err := repository.NewProductRepositoryWithDriver(db, dbConfig.RepositoryDriver)
+	if err != nil {
+		log.Fatalf("Failed to initialize product repository: %v", err)
+	}
+	partitioned, err := database.ProductsPartitioned(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize product repository: %v", err)
+	}
+	productRepo.SetPartitioned(partitioned)
+	var replica *gorm.DB
+	if dbConfig.ReplicaReads && !readOnly {
+		replica, err = database.Connect(database.NewReplicaConfig())
+		if err != nil {
+			log.Fatalf("Failed to connect to database replica: %v", err)
+		}
+		replicaExecutor := resilience.NewExecutor("database_replica", dbPolicy, database.IsTransientError)
+		if err := resilience.InstrumentGORM(replica, replicaExecutor); err != nil {
+			log.Fatalf("Failed to instrument database replica: %v", err)
+		}
+		if err := timing.RegisterGORM(replica); err != nil {
+			log.Fatalf("Failed to instrument database replica: %v", err)
+		}
+		dependencies = append(dependencies, replicaExecutor)
+		if err := productRepo.UseReplica(replica); err != nil {
+			log.Fatalf("Failed to initialize product repository: %v", err)
+		}
+		log.Println("Routing product reads to the database replica (DB_REPLICA_READS)")
+	}
+	if dbConfig.ExplainQueries {
+		if err := productRepo.LogQueryPlans(); err != nil {
+			log.Fatalf("Failed to enable query plan logging: %v", err)
+		}
+		log.Println("Logging product query plans (DB_EXPLAIN_QUERIES)")
+	}
+	exportDestinationRepo := repository.NewExportDestinationRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	auditExportRepo := repository.NewAuditExportRepository(db)
+	snapshotRepo := repository.NewStatsSnapshotRepository(db)
+	favoriteRepo := repository.NewFavoriteRepository(db)
+	emailTemplateRepo := repository.NewEmailTemplateRepository(db)
+	importProfileRepo := repository.NewImportProfileRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	stockTemplateRepo := repository.NewStockTemplateRepository(db)
+	syncDeviceRepo := repository.NewSyncDeviceRepository(db)
+	loginDeviceRepo := repository.NewLoginDeviceRepository(db)
+	loginEventRepo := repository.NewLoginEventRepository(db)
+	productSyncRepo := repository.NewProductSyncRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	usageRepo := repository.NewUsageRepository(db)
+	billingRepo := repository.NewBillingRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	locationRepo := repository.NewLocationRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	noteRepo := repository.NewNoteRepository(db)
+	savedSearchRepo := repository.NewSavedSearchRepository(db)
 
 	// Initialize services
-	cacheService := service.NewCacheService(redisClient)
-	sessionService := service.NewSessionService(cacheService)
-	userService := service.NewUserService(userRepo, sessionService, jwtSecret)
-	productService := service.NewProductService(productRepo, cacheService)
+	var cacheService domain.Cache
+	var broker domain.Broker
+	if hermetic {
+		memoryCache := memorycache.New()
+		cacheService, broker = memoryCache, memoryCache
+	} else {
+		redisCache := service.NewCacheService(redisClient, redisExecutor)
+		cacheService, broker = redisCache, redisCache
+	}
+	var sessions domain.SessionStore = service.NewCacheSessionStore(cacheService)
+	if sessionStore == service.SessionStorePostgres {
+		sessions = repository.NewSessionRepository(db)
+	}
+	sessionService := service.NewSessionService(sessions)
+	sessionService.SetStatelessFallback(sessionStatelessFallback)
+	sessionService.SetSessionLimit(sessionLimit, sessionLimitPolicy)
+	sessionService.SetDurations(sessionTTL, sessionRememberMeTTL)
+	sessionService.SetSlidingExpiration(sessionSliding, sessionMaxLifetime)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	planService := service.NewPlanService(userRepo, cacheService, notificationService, plans)
+	webhookService := service.NewWebhookService(webhookRepo, webhookDeliveryRepo, planService)
+	eventService := service.NewEventService(eventRepo, webhookService, eventRetention)
+	streamService := service.NewStreamService(broker, eventService)
+	eventService.SetStream(streamService)
+	notificationService.SetStream(streamService)
+	var loginGuard *service.LoginGuard
+	if captchaVerifier != nil {
+		loginGuard = service.NewLoginGuard(cacheService, captchaVerifier, captchaThreshold, captchaWindow)
+	}
+	emailTemplateService := service.NewEmailTemplateService(emailTemplateRepo)
+	mailer := service.NewLogMailer()
+	loginDeviceService := service.NewLoginDeviceService(loginDeviceRepo, sessionService, cacheService, emailTemplateService, mailer, newDeviceVerification)
+	loginHistoryService := service.NewLoginHistoryService(loginEventRepo, geoLocator, eventService, emailTemplateService, mailer, service.DefaultLoginHistoryBufferSize)
+	userService := service.NewUserService(userRepo, sessionService, eventService, signingKeys, loginGuard, loginDeviceService, loginHistoryService)
+	quotaService := service.NewQuotaService(userRepo, productRepo, notificationService, cacheService, planService, quotaConfig)
+	billingService := service.NewBillingService(billingRepo, userRepo, planService, quotaService, billingConfig)
+	productAuthorizer := service.DefaultProductAuthorizer()
+	productService := service.NewProductService(productRepo, attributeRepo, cacheService, quotaService, productAuthorizer, eventService)
+	orderService := service.NewOrderService(orderRepo, productService)
+	reservationService := service.NewReservationService(productRepo, productService, cacheService)
+	procurementService := service.NewProcurementService(supplierRepo, purchaseOrderRepo, productService)
+	locationService := service.NewLocationService(locationRepo, productService)
+	reviewService := service.NewReviewService(reviewRepo, productRepo, productService)
+	savedSearchService := service.NewSavedSearchService(savedSearchRepo, productRepo, productService, notificationService)
+	noteService := service.NewNoteService(noteRepo, userRepo, productService, notificationService)
+	recommendationService := service.NewRecommendationService(productService, service.NewAttributeRecommender(productRepo), cacheService)
+	stockTemplateService := service.NewStockTemplateService(stockTemplateRepo, productRepo, productService)
+	syncService := service.NewSyncService(syncDeviceRepo, productSyncRepo)
+	attributeService := service.NewAttributeService(attributeRepo)
+	backupService := service.NewBackupService(userRepo, productRepo, attributeRepo, productService, objectStore)
+	avatarService := service.NewAvatarService(userRepo, objectStore)
+	accountService := service.NewAccountService(userRepo, productRepo, productService, sessionService, avatarService, deletionGracePeriod)
+	auditService := service.NewAuditService(auditRepo, auditConfig)
+	auditExportService := service.NewAuditExportService(auditExportRepo, auditRepo, userRepo)
+	catalogService := service.NewCatalogService(productRepo, userRepo, cacheService, productAuthorizer)
+	stockTokenService := service.NewStockTokenService(productService, productRepo, locationService, cacheService, jwtSecret, stockTokenTTL)
+	importService := service.NewImportService(importProfileRepo, attributeRepo, productService)
+	onboardingService := service.NewOnboardingService(userRepo, productRepo, cacheService, emailTemplateService, mailer)
+	publicLimiter := service.NewRateLimiter(cacheService, "public", publicRateLimit, time.Minute)
+	userLimiter := service.NewRateLimiter(cacheService, "user", 0, time.Minute)
+	metricsService := service.NewMetricsService(snapshotRepo, snapshotRetention)
+	usageService := service.NewUsageService(usageRepo, cacheService)
+	favoriteService := service.NewFavoriteService(favoriteRepo, productRepo, productAuthorizer)
+	exportService, err := service.NewExportService(exportDestinationRepo, backupService, notificationService, exportEncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize export service: %v", err)
+	}
+
+	// Start background workers. Cancelling workerCtx stops them from starting new
+	// jobs; workers is done once the jobs in progress have finished.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	startWorker := func(run func(ctx context.Context, interval time.Duration), interval time.Duration) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx, interval)
+		}()
+	}
+	go auditService.Start()
+	go loginHistoryService.Start()
+	statusService := service.NewStatusService(dependencies)
+	startWorker(statusService.StartSampler, time.Minute)
+	if !readOnly {
+		startWorker(accountService.StartDeletionWorker, time.Hour)
+		startWorker(exportService.StartScheduler, time.Hour)
+		startWorker(auditService.StartRetentionWorker, time.Hour)
+		startWorker(auditExportService.StartWorker, time.Minute)
+		startWorker(metricsService.StartSnapshotWorker, 6*time.Hour)
+		startWorker(webhookService.StartWorker, time.Minute)
+		startWorker(eventService.StartRetentionWorker, time.Hour)
+		startWorker(usageService.StartRollupWorker, 10*time.Second)
+		startWorker(reservationService.StartExpiryWorker, 30*time.Second)
+		startWorker(savedSearchService.StartAlertWorker, 5*time.Minute)
+		if sessionStore == service.SessionStorePostgres {
+			startWorker(sessionService.StartCleanupWorker, time.Hour)
+		}
+	}
+
+	// draining fails readiness checks from the start of shutdown
+	var draining atomic.Bool
 
 	// Setup router
-	router := router.SetupRouter(userService, productService, jwtSecret)
+	router := router.SetupRouter(userService, productService, attributeService, backupService, accountService, exportService, notificationService, auditService, metricsService, favoriteService, quotaService, auditExportService, catalogService, publicLimiter, onboardingService, stockTokenService, emailTemplateService, importService, webhookService, stockTemplateService, syncService, eventService, loginDeviceService, loginHistoryService, usageService, planService, billingService, orderService, reservationService, procurementService, locationService, recommendationService, reviewService, noteService, savedSearchService, avatarService, streamService, statusService, userLimiter, dependencies, &draining, requestTimeout, bodyLimits, serverTiming, readOnly, signingKeys, accessLog, proxyConfig, errorReporter, debug, logger)
 
-	// Create HTTP server
+	// Create HTTP server; slow clients may not hold connections open indefinitely
 	server := &http.Server{
-		Addr:    ":8080",
-		Handler: router,
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: readHeaderTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+	// Event streams never finish on their own; end them once shutdown begins
+	server.RegisterOnShutdown(streamService.Close)
+
+	// Over TLS, net/http negotiates HTTP/2 with clients that support it
+	redirectHandler := handler.HTTPSRedirect(port)
+	if tlsCertFile != "" {
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if len(autocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(autocertDomains...),
+			Cache:      autocert.DirCache(autocertCacheDir),
+			Email:      os.Getenv("TLS_AUTOCERT_EMAIL"),
+		}
+		server.TLSConfig = manager.TLSConfig()
+		server.TLSConfig.MinVersion = tls.VersionTLS12
+		// The redirect listener also answers HTTP-01 challenges
+		redirectHandler = manager.HTTPHandler(redirectHandler)
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting server on port 8080...")
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsEnabled {
+			log.Printf("Starting server with TLS on port %s...", port)
+			err = server.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+		} else {
+			log.Printf("Starting server on port %s...", port)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
 
+	var redirectServer *http.Server
+	if httpRedirectPort != "" {
+		redirectServer = &http.Server{
+			Addr:              ":" + httpRedirectPort,
+			Handler:           redirectHandler,
+			ReadHeaderTimeout: readHeaderTimeout,
+			IdleTimeout:       idleTimeout,
+		}
+		go func() {
+			log.Printf("Redirecting HTTP on port %s to HTTPS...", httpRedirectPort)
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start HTTP redirect server: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
 
-	// Create a deadline for server shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// Fail readiness checks first and keep serving while load balancers notice and
+	// stop routing requests here; a second signal skips the wait
+	log.Printf("Draining for %s before shutting down...", shutdownDrainPeriod)
+	draining.Store(true)
+	server.SetKeepAlivesEnabled(false)
+	if redirectServer != nil {
+		redirectServer.SetKeepAlivesEnabled(false)
+	}
+	select {
+	case <-time.After(shutdownDrainPeriod):
+	case <-quit:
+	}
 
-	// Attempt graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+	// Stop the components in order: requests first, then the background work they
+	// queue, then the pools everything else uses. Each gets its own timeout so that
+	// one stuck component still lets the connection pools close.
+	const flushTimeout = 10 * time.Second
+	const poolCloseTimeout = 5 * time.Second
+	log.Println("Shutting down server...")
+	shutdown := lifecycle.NewManager()
+	shutdown.Add("http server", shutdownTimeout, server.Shutdown)
+	if redirectServer != nil {
+		shutdown.Add("http redirect server", shutdownTimeout, redirectServer.Shutdown)
+	}
+	// Workers, including the export scheduler and webhook deliveries, stop claiming
+	// jobs and finish the ones in progress; the usage worker flushes its counts
+	shutdown.Add("background workers", shutdownTimeout, func(ctx context.Context) error {
+		stopWorkers()
+		workers.Wait()
+		return nil
+	})
+	shutdown.Add("audit log queue", flushTimeout, lifecycle.Blocking(auditService.Close))
+	shutdown.Add("login history queue", flushTimeout, lifecycle.Blocking(loginHistoryService.Close))
+	shutdown.Add("error reporter", flushTimeout, errorReporter.Flush)
+	if redisClient != nil {
+		shutdown.Add("redis pool", poolCloseTimeout, lifecycle.Closer(func() error { return database.CloseRedis(redisClient) }))
+	}
+	if replica != nil {
+		shutdown.Add("database replica pool", poolCloseTimeout, lifecycle.Closer(func() error { return database.Close(replica) }))
+	}
+	shutdown.Add("database pool", poolCloseTimeout, lifecycle.Closer(func() error { return database.Close(db) }))
+	if err := shutdown.Shutdown(context.Background()); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
 	}
 
 	log.Println("Server exited")
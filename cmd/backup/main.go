@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/database"
+	"products/internal/repository"
+	"products/internal/service"
+	"products/internal/storage"
+)
+
+func main() {
+	userIDStr := flag.String("user", "", "ID of the user to back up or restore")
+	restoreKey := flag.String("restore", "", "object key of the backup to restore")
+	list := flag.Bool("list", false, "list stored backups for the user")
+	timeout := flag.Duration("timeout", 5*time.Minute, "maximum duration of the operation")
+	flag.Parse()
+
+	userID, err := uuid.Parse(*userIDStr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "usage: backup -user <uuid> [-list | -restore <key>]")
+		os.Exit(2)
+	}
+
+	// Initialize database
+	db, err := database.Connect(database.NewConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// Initialize Redis
+	redisClient, err := database.ConnectRedis(database.NewRedisConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer database.CloseRedis(redisClient)
+
+	// Initialize object storage
+	store, err := storage.NewS3Store(storage.NewS3Config())
+	if err != nil {
+		log.Fatalf("Failed to configure object storage: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	partitioned, err := database.ProductsPartitioned(db)
+	if err != nil {
+		log.Fatalf("Failed to check products partitioning: %v", err)
+	}
+	productRepo.SetPartitioned(partitioned)
+	attributeRepo := repository.NewAttributeDefinitionRepository(db)
+	cacheService := service.NewCacheService(redisClient, nil)
+	productService := service.NewProductService(productRepo, attributeRepo, cacheService, nil, service.DefaultProductAuthorizer(), nil)
+	backupService := service.NewBackupService(userRepo, productRepo, attributeRepo, productService, store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch {
+	case *list:
+		backups, err := backupService.ListBackups(ctx, userID)
+		if err != nil {
+			log.Fatalf("Failed to list backups: %v", err)
+		}
+		for _, backup := range backups {
+			fmt.Printf("%s\t%d\t%s\n", backup.Key, backup.Size, backup.CreatedAt.Format(time.RFC3339))
+		}
+	case *restoreKey != "":
+		result, err := backupService.RestoreBackup(ctx, userID, *restoreKey)
+		if err != nil {
+			log.Fatalf("Failed to restore backup: %v", err)
+		}
+		log.Printf("Restored %d products from %s", result.RestoredProducts, result.Key)
+	default:
+		backup, err := backupService.CreateBackup(ctx, userID)
+		if err != nil {
+			log.Fatalf("Failed to create backup: %v", err)
+		}
+		log.Printf("Backup written to %s (%d bytes)", backup.Key, backup.Size)
+	}
+}
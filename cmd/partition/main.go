@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"products/internal/database"
+)
+
+func main() {
+	partitions := flag.Int("partitions", 16, "number of hash partitions of user IDs")
+	batchSize := flag.Int("batch", 5000, "number of products copied per statement")
+	flag.Parse()
+
+	// Initialize database
+	db, err := database.Connect(database.NewConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	options := database.PartitionOptions{Partitions: *partitions, BatchSize: *batchSize}
+	if err := database.PartitionProducts(context.Background(), db, options); err != nil {
+		log.Fatalf("Failed to partition products: %v", err)
+	}
+}
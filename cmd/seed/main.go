@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"products/internal/database"
+	"products/internal/seed"
+)
+
+func main() {
+	users := flag.Int("users", 10, "number of users to create")
+	products := flag.Int("products", 100, "number of products to create per user")
+	batchSize := flag.Int("batch", 500, "number of rows inserted per statement")
+	workers := flag.Int("workers", 4, "number of users whose products are inserted concurrently")
+	seedValue := flag.Int64("seed", 0, "seed of the generated data (default: random)")
+	password := flag.String("password", "Seed-Passw0rd!", "password of the users created")
+	flag.Parse()
+
+	if *seedValue == 0 {
+		*seedValue = time.Now().UnixNano()
+	}
+
+	// Initialize database
+	db, err := database.Connect(database.NewConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	options := seed.Options{
+		Users:           *users,
+		ProductsPerUser: *products,
+		BatchSize:       *batchSize,
+		Workers:         *workers,
+		Seed:            *seedValue,
+		Password:        *password,
+	}
+	start := time.Now()
+	result, err := seed.Run(ctx, db, options)
+	if err != nil {
+		log.Fatalf("Failed to seed the database after creating %d users and %d products: %v", result.Users, result.Products, err)
+	}
+	log.Printf("Created %d users and %d products in %s (seed %d); users log in with password %q",
+		result.Users, result.Products, time.Since(start).Round(time.Millisecond), *seedValue, *password)
+}
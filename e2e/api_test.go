@@ -0,0 +1,240 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// password is accepted by the password validation rules
+const password = "Str0ng-Passw0rd!"
+
+// client calls the API, authenticated as one user once logged in
+type client struct {
+	t      *testing.T
+	token  string
+	userID uuid.UUID
+}
+
+// newUser registers a user with a unique email and logs them in
+func newUser(t *testing.T) *client {
+	t.Helper()
+	c := &client{t: t}
+	email := fmt.Sprintf("e2e-%s@example.com", uuid.NewString()[:8])
+
+	var user domain.User
+	c.expect(http.StatusCreated, http.MethodPost, "/api/v1/auth/register",
+		domain.CreateUserRequest{Email: email, Password: password, Name: "End To End"}, &user)
+
+	var login domain.LoginResponse
+	c.expect(http.StatusOK, http.MethodPost, "/api/v1/auth/login",
+		domain.LoginRequest{Email: email, Password: password}, &login)
+	if login.AccessToken == "" || login.User.ID != user.ID {
+		t.Fatalf("Unexpected login response %+v", login)
+	}
+
+	c.token = login.AccessToken
+	c.userID = user.ID
+	return c
+}
+
+// do sends a request with a JSON body, decoding a JSON response into out, and
+// returns the response status
+func (c *client) do(method, path string, body, out interface{}) int {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatalf("Failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequest(method, baseURL+path, reader)
+	if err != nil {
+		c.t.Fatalf("Failed to create request: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		c.t.Fatalf("Failed to read response: %v", err)
+	}
+	if out != nil && response.StatusCode < http.StatusBadRequest {
+		if err := json.Unmarshal(data, out); err != nil {
+			c.t.Fatalf("Failed to decode response of %s %s: %v: %s", method, path, err, data)
+		}
+	}
+	return response.StatusCode
+}
+
+// expect sends a request like do and fails the test unless it answers status
+func (c *client) expect(status int, method, path string, body, out interface{}) {
+	c.t.Helper()
+	if got := c.do(method, path, body, out); got != status {
+		c.t.Fatalf("Expected %s %s to answer %d, got %d", method, path, status, got)
+	}
+}
+
+// createProduct creates a product and returns it
+func (c *client) createProduct(name string, price float64, stock int) domain.Product {
+	c.t.Helper()
+	var product domain.Product
+	c.expect(http.StatusCreated, http.MethodPost, "/api/v1/products/",
+		domain.CreateProductRequest{Name: name, Price: price, Stock: stock}, &product)
+	return product
+}
+
+// cached reports whether Redis holds a key matching pattern
+func cached(t *testing.T, pattern string) bool {
+	t.Helper()
+	keys, err := redisClient.Keys(context.Background(), pattern).Result()
+	if err != nil {
+		t.Fatalf("Failed to list cache keys: %v", err)
+	}
+	return len(keys) > 0
+}
+
+func TestAuth(t *testing.T) {
+	anonymous := &client{t: t}
+	anonymous.expect(http.StatusUnauthorized, http.MethodGet, "/api/v1/products/", nil, nil)
+	anonymous.expect(http.StatusUnauthorized, http.MethodPost, "/api/v1/auth/login",
+		domain.LoginRequest{Email: "nobody@example.com", Password: password}, nil)
+
+	user := newUser(t)
+	user.expect(http.StatusOK, http.MethodGet, "/api/v1/products/", nil, nil)
+	user.expect(http.StatusOK, http.MethodPost, "/api/v1/auth/logout", nil, nil)
+	user.expect(http.StatusUnauthorized, http.MethodGet, "/api/v1/products/", nil, nil)
+}
+
+func TestProductCRUD(t *testing.T) {
+	user := newUser(t)
+	product := user.createProduct("Desk lamp", 29.5, 4)
+	path := "/api/v1/products/" + product.ID.String()
+
+	var got domain.Product
+	user.expect(http.StatusOK, http.MethodGet, path, nil, &got)
+	if got.Name != "Desk lamp" || got.Price != 29.5 || got.Stock != 4 {
+		t.Errorf("Unexpected product %+v", got)
+	}
+
+	price := 35.0
+	user.expect(http.StatusOK, http.MethodPut, path, domain.UpdateProductRequest{Price: &price}, nil)
+	user.expect(http.StatusOK, http.MethodGet, path, nil, &got)
+	if got.Price != 35 {
+		t.Errorf("Expected the updated price 35, got %v", got.Price)
+	}
+
+	// other users cannot see the product
+	newUser(t).expect(http.StatusNotFound, http.MethodGet, path, nil, nil)
+
+	user.expect(http.StatusOK, http.MethodDelete, path, nil, nil)
+	user.expect(http.StatusNotFound, http.MethodGet, path, nil, nil)
+}
+
+func TestProductFilters(t *testing.T) {
+	user := newUser(t)
+	for i, price := range []float64{10, 20, 30, 40, 50} {
+		user.createProduct(fmt.Sprintf("Chair %d", i+1), price, i)
+	}
+	user.createProduct("Table", 100, 1)
+
+	query := url.Values{
+		"name":           {"chair"},
+		"min_price":      {"20"},
+		"sort_field":     {"price"},
+		"sort_direction": {"desc"},
+		"include_total":  {"true"},
+	}
+	var list domain.ProductListResponse
+	user.expect(http.StatusOK, http.MethodGet, "/api/v1/products/filtered?"+query.Encode(), nil, &list)
+	if len(list.Products) != 4 || list.Products[0].Price != 50 || list.Products[3].Price != 20 {
+		t.Errorf("Expected chairs from 50 down to 20, got %+v", list.Products)
+	}
+	if list.Total == nil || *list.Total != 4 {
+		t.Errorf("Expected a total of 4, got %v", list.Total)
+	}
+}
+
+func TestProductCursor(t *testing.T) {
+	user := newUser(t)
+	for i := 0; i < 5; i++ {
+		user.createProduct(fmt.Sprintf("Shelf %d", i+1), float64(10+i), 1)
+	}
+
+	seen := map[uuid.UUID]bool{}
+	query := url.Values{"page_size": {"2"}, "sort_field": {"price"}}
+	for page := 0; ; page++ {
+		if page == 5 {
+			t.Fatal("Expected the cursor to reach the last page")
+		}
+		var list domain.ProductListCursorResponse
+		user.expect(http.StatusOK, http.MethodGet, "/api/v1/products/cursor?"+query.Encode(), nil, &list)
+		for _, product := range list.Products {
+			if seen[product.ID] {
+				t.Errorf("Product %s listed twice", product.ID)
+			}
+			seen[product.ID] = true
+		}
+		if !list.HasNext {
+			break
+		}
+		query.Set("cursor", *list.NextCursor)
+	}
+	if len(seen) != 5 {
+		t.Errorf("Expected 5 products over all pages, got %d", len(seen))
+	}
+}
+
+func TestProductCache(t *testing.T) {
+	user := newUser(t)
+	product := user.createProduct("Bookcase", 120, 2)
+	path := "/api/v1/products/" + product.ID.String()
+	productKey := fmt.Sprintf("product:%s:%s", user.userID, product.ID)
+	listKeys := fmt.Sprintf("user_products_filtered:%s:*", user.userID)
+
+	user.expect(http.StatusOK, http.MethodGet, path, nil, nil)
+	user.expect(http.StatusOK, http.MethodGet, "/api/v1/products/filtered", nil, nil)
+	if !cached(t, productKey) {
+		t.Errorf("Expected %s to be cached", productKey)
+	}
+	if !cached(t, listKeys) {
+		t.Errorf("Expected the filtered list to be cached")
+	}
+
+	// changing the product evicts it and the lists it appears in
+	stock := 3
+	user.expect(http.StatusOK, http.MethodPut, path, domain.UpdateProductRequest{Stock: &stock}, nil)
+	if cached(t, productKey) {
+		t.Errorf("Expected %s to be evicted", productKey)
+	}
+	if cached(t, listKeys) {
+		t.Errorf("Expected the filtered lists to be evicted")
+	}
+
+	var got domain.Product
+	user.expect(http.StatusOK, http.MethodGet, path, nil, &got)
+	if got.Stock != 3 {
+		t.Errorf("Expected the updated stock 3 after eviction, got %d", got.Stock)
+	}
+}
@@ -0,0 +1,11 @@
+// Package e2e holds the end-to-end tests of the API. They build cmd/api, run it
+// against Postgres and Redis containers started with the docker CLI and exercise it
+// over HTTP. The tests only build with the e2e tag:
+//
+//	go test -tags e2e -count=1 ./e2e
+//
+// The containers are driven through the docker CLI rather than
+// github.com/ory/dockertest: the few commands needed (run, port, rm) do not
+// warrant adding dockertest and its Docker client dependencies to the module,
+// which every build would then have to download.
+package e2e
@@ -0,0 +1,235 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// startupTimeout bounds the time the containers and the API take to become ready
+const startupTimeout = 2 * time.Minute
+
+var (
+	// baseURL is the address of the API under test
+	baseURL string
+
+	// redisClient is connected to the Redis the API caches in
+	redisClient *redis.Client
+)
+
+// container is a docker container started for the tests
+type container struct {
+	id string
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run starts Postgres, Redis and the API, runs the tests and tears everything down
+func run(m *testing.M) int {
+	flag.Parse()
+	if _, err := exec.LookPath("docker"); err != nil {
+		log.Printf("The e2e tests need docker: %v", err)
+		return 1
+	}
+
+	postgres, err := startContainer("postgres:15-alpine", "5432/tcp",
+		"-e", "POSTGRES_USER=products_user",
+		"-e", "POSTGRES_PASSWORD=products_password",
+		"-e", "POSTGRES_DB=products_db")
+	if err != nil {
+		log.Printf("Failed to start Postgres: %v", err)
+		return 1
+	}
+	defer postgres.stop()
+
+	cache, err := startContainer("redis:7-alpine", "6379/tcp")
+	if err != nil {
+		log.Printf("Failed to start Redis: %v", err)
+		return 1
+	}
+	defer cache.stop()
+
+	// the server only listens on TCP once the database is initialized
+	if err := waitFor(func() error {
+		return postgres.exec("pg_isready", "-h", "127.0.0.1", "-U", "products_user", "-d", "products_db")
+	}); err != nil {
+		log.Printf("Postgres did not become ready: %v", err)
+		return 1
+	}
+	if err := waitFor(func() error { return cache.exec("redis-cli", "ping") }); err != nil {
+		log.Printf("Redis did not become ready: %v", err)
+		return 1
+	}
+
+	dbPort, err := postgres.hostPort("5432/tcp")
+	if err != nil {
+		log.Printf("Failed to get the Postgres port: %v", err)
+		return 1
+	}
+	redisPort, err := cache.hostPort("6379/tcp")
+	if err != nil {
+		log.Printf("Failed to get the Redis port: %v", err)
+		return 1
+	}
+	redisClient = redis.NewClient(&redis.Options{Addr: "127.0.0.1:" + redisPort})
+	defer redisClient.Close()
+
+	api, err := startAPI(dbPort, redisPort)
+	if err != nil {
+		log.Printf("Failed to start the API: %v", err)
+		return 1
+	}
+	defer func() {
+		api.Process.Signal(syscall.SIGTERM)
+		api.Wait()
+	}()
+
+	return m.Run()
+}
+
+// startAPI builds cmd/api, runs it against the containers and waits until it is ready
+func startAPI(dbPort, redisPort string) (*exec.Cmd, error) {
+	dir, err := os.MkdirTemp("", "products-e2e")
+	if err != nil {
+		return nil, err
+	}
+	binary := filepath.Join(dir, "api")
+	build := exec.Command("go", "build", "-o", binary, "products/cmd/api")
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		return nil, fmt.Errorf("failed to build the API: %w", err)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+	baseURL = "http://127.0.0.1:" + port
+
+	api := exec.Command(binary)
+	api.Env = append(os.Environ(),
+		"DB_HOST=127.0.0.1",
+		"DB_PORT="+dbPort,
+		"DB_USER=products_user",
+		"DB_PASSWORD=products_password",
+		"DB_NAME=products_db",
+		"REDIS_HOST=127.0.0.1",
+		"REDIS_PORT="+redisPort,
+		"REDIS_REQUIRED=true",
+		"JWT_SECRET=e2e-secret",
+		"EXPORT_ENCRYPTION_KEY=e2e-export-key",
+		"PORT="+port,
+	)
+	if testing.Verbose() {
+		api.Stdout, api.Stderr = os.Stdout, os.Stderr
+	}
+	if err := api.Start(); err != nil {
+		return nil, err
+	}
+
+	err = waitFor(func() error {
+		response, err := http.Get(baseURL + "/ready")
+		if err != nil {
+			return err
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %d", response.StatusCode)
+		}
+		return nil
+	})
+	if err != nil {
+		api.Process.Kill()
+		api.Wait()
+		return nil, fmt.Errorf("API did not become ready: %w", err)
+	}
+	return api, nil
+}
+
+// startContainer runs image detached, publishing port on a random host port
+func startContainer(image, port string, args ...string) (*container, error) {
+	args = append([]string{"run", "-d", "--rm", "-p", "127.0.0.1::" + port}, args...)
+	output, err := exec.Command("docker", append(args, image)...).Output()
+	if err != nil {
+		return nil, commandError(err)
+	}
+	return &container{id: strings.TrimSpace(string(output))}, nil
+}
+
+// hostPort returns the host port a container port is published on
+func (c *container) hostPort(port string) (string, error) {
+	output, err := exec.Command("docker", "port", c.id, port).Output()
+	if err != nil {
+		return "", commandError(err)
+	}
+	// one line per address, such as 127.0.0.1:49153
+	line := strings.SplitN(strings.TrimSpace(string(output)), "\n", 2)[0]
+	_, hostPort, err := net.SplitHostPort(line)
+	return hostPort, err
+}
+
+// exec runs a command in the container
+func (c *container) exec(command ...string) error {
+	_, err := exec.Command("docker", append([]string{"exec", c.id}, command...)...).Output()
+	return commandError(err)
+}
+
+// stop removes the container
+func (c *container) stop() {
+	if err := exec.Command("docker", "rm", "-f", c.id).Run(); err != nil {
+		log.Printf("Failed to remove container %s: %v", c.id, err)
+	}
+}
+
+// waitFor calls check until it succeeds or startupTimeout elapses
+func waitFor(check func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// freePort returns a TCP port that is free on the loopback interface
+func freePort() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer listener.Close()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	return port, err
+}
+
+// commandError adds the standard error of a failed command to its error
+func commandError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
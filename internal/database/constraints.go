@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// constraintSQL enforces the constraints the gorm tags declare on required columns
+// at the database level, including on databases created before the tags did; each
+// statement is a no-op once the constraint holds
+var constraintSQL = []string{
+	"ALTER TABLE users ALTER COLUMN email SET NOT NULL, ALTER COLUMN password SET NOT NULL, ALTER COLUMN name SET NOT NULL",
+	"CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email)",
+	// emails are stored normalized; this also serves case-insensitive lookups
+	"CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email))",
+	"ALTER TABLE products ALTER COLUMN name SET NOT NULL, ALTER COLUMN price SET NOT NULL, ALTER COLUMN stock SET NOT NULL, ALTER COLUMN user_id SET NOT NULL",
+}
+
+// prepareConstraints fixes the rows of an existing database that would keep the
+// constraints from being added: missing stock counts as none and emails are
+// normalized, while emails that differ only in case and products without a name,
+// price or owner need an operator to decide
+func prepareConstraints(db *gorm.DB) error {
+	if db.Migrator().HasTable(&domain.Product{}) {
+		if err := db.Exec("UPDATE products SET stock = 0 WHERE stock IS NULL").Error; err != nil {
+			return fmt.Errorf("failed to fill in missing stock: %w", err)
+		}
+
+		var missing int64
+		err := db.Model(&domain.Product{}).Where("name IS NULL OR price IS NULL OR user_id IS NULL").Count(&missing).Error
+		if err != nil {
+			return err
+		}
+		if missing > 0 {
+			return fmt.Errorf("%d products have no name, price or owner; fix or delete them before migrating", missing)
+		}
+	}
+
+	if db.Migrator().HasTable(&domain.User{}) {
+		var duplicates []string
+		err := db.Model(&domain.User{}).
+			Select("LOWER(TRIM(email)) AS email").
+			Group("LOWER(TRIM(email))").
+			Having("COUNT(*) > 1").
+			Limit(5).
+			Pluck("email", &duplicates).Error
+		if err != nil {
+			return err
+		}
+		if len(duplicates) > 0 {
+			return fmt.Errorf("several users share the emails %v, ignoring case; merge or rename these accounts before migrating", duplicates)
+		}
+
+		// Emails registered before they were normalized
+		err = db.Exec("UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email))").Error
+		if err != nil {
+			return fmt.Errorf("failed to normalize emails: %w", err)
+		}
+	}
+	return nil
+}
+
+// enforceConstraints adds the constraints of constraintSQL
+func enforceConstraints(db *gorm.DB) error {
+	for _, statement := range constraintSQL {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to enforce constraints (%s): %w", statement, err)
+		}
+	}
+	return nil
+}
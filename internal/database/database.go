@@ -19,6 +19,18 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// RepositoryDriver selects the driver for hot read paths ("gorm" or "pgx")
+	RepositoryDriver string
+
+	// ReadOnly makes every transaction on the connection read-only
+	ReadOnly bool
+
+	// ReplicaReads routes read-only product queries to the DB_REPLICA_* database
+	ReplicaReads bool
+
+	// ExplainQueries logs the plan of every product query; meant for development only
+	ExplainQueries bool
 }
 
 // NewConfig creates a new database configuration from environment variables
@@ -30,13 +42,34 @@ func NewConfig() *Config {
 		Password: getEnv("DB_PASSWORD", "products_password"),
 		DBName:   getEnv("DB_NAME", "products_db"),
 		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+		RepositoryDriver: getEnv("DB_REPOSITORY_DRIVER", "gorm"),
+		ReplicaReads:     getEnv("DB_REPLICA_READS", "false") == "true",
+		ExplainQueries:   getEnv("DB_EXPLAIN_QUERIES", "false") == "true",
 	}
 }
 
+// NewReplicaConfig creates the read-only configuration of the replica, used during primary
+// maintenance and for product reads when ReplicaReads is set.
+// DB_REPLICA_* variables override the corresponding primary settings.
+func NewReplicaConfig() *Config {
+	config := NewConfig()
+	config.Host = getEnv("DB_REPLICA_HOST", config.Host)
+	config.Port = getEnv("DB_REPLICA_PORT", config.Port)
+	config.User = getEnv("DB_REPLICA_USER", config.User)
+	config.Password = getEnv("DB_REPLICA_PASSWORD", config.Password)
+	config.DBName = getEnv("DB_REPLICA_NAME", config.DBName)
+	config.ReadOnly = true
+	return config
+}
+
 // Connect establishes a database connection
 func Connect(config *Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+	if config.ReadOnly {
+		dsn += " default_transaction_read_only=on"
+	}
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
@@ -48,15 +81,172 @@ func Connect(config *Config) (*gorm.DB, error) {
 	return db, nil
 }
 
+// productIndexes are the product indexes GORM tags cannot express, covering the
+// per-user list filters and sorts
+var productIndexes = []struct {
+	name string
+	sql  string
+}{
+	// attribute containment filters (attributes @> ...)
+	{"attributes", "CREATE INDEX IF NOT EXISTS idx_products_attributes ON products USING GIN (attributes jsonb_path_ops)"},
+	// the default list order and created date ranges
+	{"created_at", "CREATE INDEX IF NOT EXISTS idx_products_user_created_at ON products (user_id, created_at DESC)"},
+	// price ranges and price sorting
+	{"price", "CREATE INDEX IF NOT EXISTS idx_products_user_price ON products (user_id, price)"},
+	// stock ranges and stock sorting
+	{"stock", "CREATE INDEX IF NOT EXISTS idx_products_user_stock ON products (user_id, stock)"},
+	// short product codes, unique per user
+	{"code", "CREATE UNIQUE INDEX IF NOT EXISTS idx_products_user_code ON products (user_id, code) WHERE code IS NOT NULL"},
+}
+
+// productSyncSQL installs the triggers that bump a product's version on every
+// update and record its latest change in product_sync with a new sync token.
+// Changes are serialized per user until commit, so that a user's tokens become
+// visible in increasing order and clients never skip a change.
+var productSyncSQL = []string{
+	"CREATE SEQUENCE IF NOT EXISTS product_sync_token_seq",
+	`CREATE OR REPLACE FUNCTION products_bump_version() RETURNS trigger AS $$
+	BEGIN
+		NEW.version := OLD.version + 1;
+		RETURN NEW;
+	END;
+	$$ LANGUAGE plpgsql`,
+	`CREATE OR REPLACE FUNCTION products_record_sync() RETURNS trigger AS $$
+	DECLARE
+		changed products%ROWTYPE;
+	BEGIN
+		IF TG_OP = 'DELETE' THEN
+			changed := OLD;
+		ELSE
+			changed := NEW;
+		END IF;
+		PERFORM pg_advisory_xact_lock(hashtext('product_sync:' || changed.user_id::text));
+		INSERT INTO product_sync (product_id, user_id, token, version, deleted, updated_at)
+		VALUES (changed.id, changed.user_id, nextval('product_sync_token_seq'), changed.version,
+			TG_OP = 'DELETE', CASE WHEN TG_OP = 'DELETE' THEN NOW() ELSE changed.updated_at END)
+		ON CONFLICT (product_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id, token = EXCLUDED.token, version = EXCLUDED.version,
+			deleted = EXCLUDED.deleted, updated_at = EXCLUDED.updated_at;
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql`,
+	"DROP TRIGGER IF EXISTS products_bump_version ON products",
+	"CREATE TRIGGER products_bump_version BEFORE UPDATE ON products FOR EACH ROW EXECUTE FUNCTION products_bump_version()",
+	"DROP TRIGGER IF EXISTS products_record_sync ON products",
+	"CREATE TRIGGER products_record_sync AFTER INSERT OR UPDATE OR DELETE ON products FOR EACH ROW EXECUTE FUNCTION products_record_sync()",
+	// products created before sync tokens existed
+	`INSERT INTO product_sync (product_id, user_id, token, version, deleted, updated_at)
+	SELECT id, user_id, nextval('product_sync_token_seq'), version, false, updated_at FROM products p
+	WHERE NOT EXISTS (SELECT 1 FROM product_sync s WHERE s.product_id = p.id)`,
+}
+
+// eventLogSQL makes the event log append-only: events can be deleted by the
+// retention policy and with their user's account, but never changed
+var eventLogSQL = []string{
+	`CREATE OR REPLACE FUNCTION events_append_only() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'events are append-only';
+	END;
+	$$ LANGUAGE plpgsql`,
+	"DROP TRIGGER IF EXISTS events_append_only ON events",
+	"CREATE TRIGGER events_append_only BEFORE UPDATE ON events FOR EACH ROW EXECUTE FUNCTION events_append_only()",
+}
+
+// Close closes the connection pool of db
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
 // Migrate runs database migrations
 func Migrate(db *gorm.DB) error {
 	log.Println("Running database migrations...")
 	
-	err := db.AutoMigrate(&domain.User{}, &domain.Product{})
+	partitioned, err := ProductsPartitioned(db)
 	if err != nil {
+		return err
+	}
+	if err := prepareConstraints(db); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	migrator := db
+	if partitioned {
+		// foreign keys cannot reference partitioned products, see PartitionProducts
+		migrator = withoutForeignKeys(db)
+	}
+
+	err = migrator.AutoMigrate(
+		&domain.User{},
+		&domain.Product{},
+		&domain.ExportDestination{},
+		&domain.Notification{},
+		&domain.AuditLog{},
+		&domain.AttributeDefinition{},
+		&domain.StatsSnapshot{},
+		&domain.Favorite{},
+		&domain.AuditExport{},
+		&domain.EmailTemplate{},
+		&domain.ImportProfile{}, &domain.WebhookSubscription{}, &domain.WebhookDelivery{},
+		&domain.StockTemplate{},
+		&domain.SyncDevice{}, &domain.ProductSync{},
+		&domain.Event{},
+		&domain.LoginDevice{},
+		&domain.LoginEvent{},
+		&domain.APIUsage{},
+		&domain.BillingCustomer{},
+		&domain.Invoice{},
+		&domain.Order{}, &domain.OrderItem{},
+		&domain.Supplier{}, &domain.PurchaseOrder{}, &domain.PurchaseOrderItem{},
+		&domain.Location{}, &domain.LocationStock{},
+		&domain.Review{},
+		&domain.ProductNote{},
+		&domain.SavedSearch{},
+		&domain.Session{}, &domain.SessionRevocation{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	if err := enforceConstraints(db); err != nil {
+		return err
+	}
+
+	for _, index := range productIndexes {
+		if err := db.Exec(index.sql).Error; err != nil {
+			return fmt.Errorf("failed to create %s index: %w", index.name, err)
+		}
+	}
+
+	for _, statement := range productSyncSQL {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to install product sync tracking: %w", err)
+		}
+	}
+
+	for _, statement := range eventLogSQL {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to install event log protection: %w", err)
+		}
+	}
+
+	// Trigram index for name searches (LOWER(name) LIKE ...), skipped where pg_trgm is unavailable
+	err = db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error
+	if err == nil {
+		err = db.Exec("CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (LOWER(name) gin_trgm_ops)").Error
+	}
+	if err != nil {
+		log.Printf("Skipping product name trigram index: %v", err)
+	}
+
+	// Index for name prefix searches of a user's products, such as short suggestion queries
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_products_user_name_prefix ON products (user_id, LOWER(name) text_pattern_ops)").Error; err != nil {
+		return fmt.Errorf("failed to create product name prefix index: %w", err)
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PartitionOptions configures PartitionProducts
+type PartitionOptions struct {
+	// Partitions is the number of hash partitions user IDs are spread over
+	Partitions int
+
+	// BatchSize is the number of products copied per statement
+	BatchSize int
+}
+
+// productIndex is an index of the products table
+type productIndex struct {
+	Name       string
+	Definition string
+	IsPrimary  bool
+}
+
+// ProductsPartitioned reports whether the products table is partitioned
+func ProductsPartitioned(db *gorm.DB) (bool, error) {
+	var partitioned bool
+	err := db.Raw(`SELECT EXISTS (
+		SELECT 1 FROM pg_partitioned_table pt
+		JOIN pg_class c ON c.oid = pt.partrelid
+		WHERE c.relname = 'products' AND c.relnamespace = current_schema()::regnamespace
+	)`).Scan(&partitioned).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check products partitioning: %w", err)
+	}
+	return partitioned, nil
+}
+
+// PartitionProducts turns the products table into a table partitioned by a hash of
+// user_id, so that each user's products live in one partition. Products are copied
+// in batches while the table stays in use; the tables are then swapped under a lock,
+// after copying the changes made meanwhile. The previous table is kept as
+// products_unpartitioned, to be dropped once the partitioned table is verified.
+//
+// Foreign keys cannot reference a partitioned table without its partition key, so
+// foreign keys to products are dropped; the repositories delete the rows that
+// depend on a product themselves.
+func PartitionProducts(ctx context.Context, db *gorm.DB, options PartitionOptions) error {
+	if options.Partitions < 1 || options.BatchSize < 1 {
+		return fmt.Errorf("invalid partition options %+v", options)
+	}
+	db = db.WithContext(ctx)
+
+	partitioned, err := ProductsPartitioned(db)
+	if err != nil {
+		return err
+	}
+	if partitioned {
+		return errors.New("products table is already partitioned")
+	}
+
+	var leftover bool
+	if err := db.Raw("SELECT to_regclass('products_unpartitioned') IS NOT NULL").Scan(&leftover).Error; err != nil {
+		return fmt.Errorf("failed to check for products_unpartitioned: %w", err)
+	}
+	if leftover {
+		return errors.New("products_unpartitioned exists; drop it before partitioning again")
+	}
+
+	var indexes []productIndex
+	err = db.Raw(`SELECT c.relname AS name, pg_get_indexdef(i.indexrelid) AS definition, i.indisprimary AS is_primary
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE i.indrelid = 'products'::regclass`).Scan(&indexes).Error
+	if err != nil {
+		return fmt.Errorf("failed to list product indexes: %w", err)
+	}
+
+	log.Printf("Creating products_partitioned with %d partitions...", options.Partitions)
+	if err := createPartitionedProducts(db, options.Partitions, indexes); err != nil {
+		return err
+	}
+
+	copied, err := copyProducts(db, options.BatchSize)
+	if err != nil {
+		return err
+	}
+	log.Printf("Copied %d products, swapping tables...", copied)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return swapProducts(tx, indexes)
+	}); err != nil {
+		return fmt.Errorf("failed to swap products tables: %w", err)
+	}
+
+	log.Println("Products table partitioned; the previous table is kept as products_unpartitioned")
+	return nil
+}
+
+// createPartitionedProducts creates products_partitioned and its partitions with
+// the columns, foreign keys and indexes of products. The indexes get the suffix
+// _part until the tables are swapped.
+func createPartitionedProducts(db *gorm.DB, partitions int, indexes []productIndex) error {
+	primaryKey := "products_pkey"
+	for _, index := range indexes {
+		if index.IsPrimary {
+			primaryKey = index.Name
+		}
+	}
+
+	statements := []string{
+		// a run interrupted before the swap leaves the table behind; its partitions go with it
+		"DROP TABLE IF EXISTS products_partitioned",
+		`CREATE TABLE products_partitioned (
+			LIKE products INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+			CONSTRAINT `+quoteIdentifier(primaryKey+"_part")+` PRIMARY KEY (id, user_id)
+		) PARTITION BY HASH (user_id)`,
+	}
+	for i := 0; i < partitions; i++ {
+		statements = append(statements, fmt.Sprintf(
+			"CREATE TABLE products_p%d PARTITION OF products_partitioned FOR VALUES WITH (MODULUS %d, REMAINDER %d)",
+			i, partitions, i))
+	}
+	for _, index := range indexes {
+		if index.IsPrimary {
+			continue
+		}
+		on := strings.Index(index.Definition, " ON ")
+		using := strings.Index(index.Definition, " USING ")
+		if on < 0 || using < on {
+			return fmt.Errorf("unexpected definition of index %s: %s", index.Name, index.Definition)
+		}
+		definition := strings.Replace(index.Definition[:on], index.Name, index.Name+"_part", 1) +
+			" ON products_partitioned" + index.Definition[using:]
+		statements = append(statements, definition)
+	}
+
+	var foreignKeys []struct {
+		Name       string
+		Definition string
+	}
+	err := db.Raw(`SELECT conname AS name, pg_get_constraintdef(oid) AS definition
+		FROM pg_constraint WHERE conrelid = 'products'::regclass AND contype = 'f'`).Scan(&foreignKeys).Error
+	if err != nil {
+		return fmt.Errorf("failed to list product foreign keys: %w", err)
+	}
+	for _, foreignKey := range foreignKeys {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE products_partitioned ADD CONSTRAINT %s %s",
+			quoteIdentifier(foreignKey.Name), foreignKey.Definition))
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to create partitioned products table: %w", err)
+		}
+	}
+	return nil
+}
+
+// copyProducts copies products into products_partitioned in batches ordered by ID,
+// returning the number of products copied
+func copyProducts(db *gorm.DB, batchSize int) (int64, error) {
+	var total int64
+	last := uuid.Nil
+
+	for {
+		var batch struct {
+			Count int64
+			Last  *uuid.UUID
+		}
+		err := db.Raw(`WITH batch AS (
+				SELECT * FROM products WHERE id > ? ORDER BY id LIMIT ?
+			), copied AS (
+				INSERT INTO products_partitioned SELECT * FROM batch RETURNING id
+			)
+			SELECT (SELECT COUNT(*) FROM copied) AS count, (SELECT id FROM copied ORDER BY id DESC LIMIT 1) AS last`,
+			last, batchSize).Scan(&batch).Error
+		if err != nil {
+			return total, fmt.Errorf("failed to copy products: %w", err)
+		}
+		if batch.Last == nil {
+			return total, nil
+		}
+
+		total += batch.Count
+		last = *batch.Last
+		log.Printf("Copied %d products", total)
+	}
+}
+
+// swapProducts locks products, copies the changes made while copying and puts
+// products_partitioned in its place. Every update increments a product's version,
+// so copied products whose version changed are copied again.
+func swapProducts(tx *gorm.DB, indexes []productIndex) error {
+	statements := []string{
+		"LOCK TABLE products IN ACCESS EXCLUSIVE MODE",
+		`DELETE FROM products_partitioned n
+		WHERE NOT EXISTS (SELECT 1 FROM products o WHERE o.id = n.id AND o.version = n.version)`,
+		`INSERT INTO products_partitioned SELECT * FROM products o
+		WHERE NOT EXISTS (SELECT 1 FROM products_partitioned n WHERE n.id = o.id)`,
+	}
+
+	var references []struct {
+		TableName string
+		Name      string
+	}
+	err := tx.Raw(`SELECT conrelid::regclass::text AS table_name, conname AS name
+		FROM pg_constraint WHERE confrelid = 'products'::regclass AND contype = 'f'`).Scan(&references).Error
+	if err != nil {
+		return fmt.Errorf("failed to list foreign keys to products: %w", err)
+	}
+	for _, reference := range references {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s",
+			reference.TableName, quoteIdentifier(reference.Name)))
+	}
+
+	statements = append(statements,
+		"DROP TRIGGER IF EXISTS products_bump_version ON products",
+		"DROP TRIGGER IF EXISTS products_record_sync ON products",
+		"ALTER TABLE products RENAME TO products_unpartitioned",
+	)
+	for _, index := range indexes {
+		statements = append(statements,
+			fmt.Sprintf("ALTER INDEX %s RENAME TO %s", quoteIdentifier(index.Name), quoteIdentifier(index.Name+"_unpartitioned")),
+			fmt.Sprintf("ALTER INDEX %s RENAME TO %s", quoteIdentifier(index.Name+"_part"), quoteIdentifier(index.Name)),
+		)
+	}
+	statements = append(statements, "ALTER TABLE products_partitioned RENAME TO products")
+	statements = append(statements, productSyncSQL...)
+
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutForeignKeys returns a session of db whose migrations create no foreign keys
+func withoutForeignKeys(db *gorm.DB) *gorm.DB {
+	config := *db.Config
+	config.DisableForeignKeyConstraintWhenMigrating = true
+
+	tx := db.Session(&gorm.Session{})
+	tx.Config = &config
+	return tx
+}
+
+// quoteIdentifier quotes a Postgres identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
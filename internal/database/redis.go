@@ -2,11 +2,13 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"products/internal/timing"
 )
 
 // RedisConfig holds Redis configuration
@@ -27,19 +29,28 @@ func NewRedisConfig() *RedisConfig {
 	}
 }
 
-// ConnectRedis establishes a Redis connection
-func ConnectRedis(config *RedisConfig) (*redis.Client, error) {
+// NewRedisClient creates a Redis client without connecting; connections are
+// opened on first use
+func NewRedisClient(config *RedisConfig) *redis.Client {
 	client := redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", config.Host, config.Port),
 		Password: config.Password,
 		DB:       config.DB,
 		PoolSize: 10,
 		MinIdleConns: 5,
-		MaxRetries:   3,
+		// Retries are left to the cache's resilience policy
+		MaxRetries:   -1,
 		DialTimeout:  5 * time.Second,
 		ReadTimeout:  3 * time.Second,
 		WriteTimeout: 3 * time.Second,
 	})
+	client.AddHook(timing.RedisHook{})
+	return client
+}
+
+// ConnectRedis establishes a Redis connection
+func ConnectRedis(config *RedisConfig) (*redis.Client, error) {
+	client := NewRedisClient(config)
 
 	// Test the connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -47,6 +58,7 @@ func ConnectRedis(config *RedisConfig) (*redis.Client, error) {
 
 	_, err := client.Ping(ctx).Result()
 	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
@@ -58,3 +70,13 @@ func ConnectRedis(config *RedisConfig) (*redis.Client, error) {
 func CloseRedis(client *redis.Client) error {
 	return client.Close()
 }
+
+// IsTransientRedisError reports whether err means Redis could not be reached or
+// answered in time, as opposed to an error reply such as a missing key
+func IsTransientRedisError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var reply redis.Error
+	return !errors.As(err, &reply)
+}
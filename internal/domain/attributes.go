@@ -0,0 +1,284 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attribute types
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
+// Attribute limits
+const (
+	MaxAttributes           = 50
+	MaxAttributeStringValue = 500
+)
+
+var attributeKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// Attributes holds user-defined product attributes stored as JSONB
+type Attributes map[string]interface{}
+
+// Value implements driver.Valuer
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (a *Attributes) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Attributes", value)
+	}
+	return json.Unmarshal(data, a)
+}
+
+// GormDataType returns the column type used by GORM
+func (Attributes) GormDataType() string {
+	return "jsonb"
+}
+
+// StringList is a list of strings stored as JSONB
+type StringList []string
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = StringList{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+	return json.Unmarshal(data, l)
+}
+
+// GormDataType returns the column type used by GORM
+func (StringList) GormDataType() string {
+	return "jsonb"
+}
+
+// AttributeDefinition describes a user-defined product attribute
+type AttributeDefinition struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_attribute_definitions_user_key"`
+	Key           string     `json:"key" gorm:"not null;uniqueIndex:idx_attribute_definitions_user_key"`
+	Type          string     `json:"type" gorm:"not null"`
+	Required      bool       `json:"required"`
+	AllowedValues StringList `json:"allowed_values,omitempty" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for AttributeDefinition
+func (AttributeDefinition) TableName() string {
+	return "attribute_definitions"
+}
+
+// AttributeDefinitionRequest represents the request for defining an attribute
+type AttributeDefinitionRequest struct {
+	Type          string   `json:"type" binding:"required"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values"`
+}
+
+// ValidateAttributeKey checks that an attribute key is well-formed
+func ValidateAttributeKey(key string) error {
+	if !attributeKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid attribute key %q: use lowercase letters, digits and underscores", key)
+	}
+	return nil
+}
+
+// ValidateAttributeDefinition checks that a definition is internally consistent
+func ValidateAttributeDefinition(definition AttributeDefinition) error {
+	if err := ValidateAttributeKey(definition.Key); err != nil {
+		return err
+	}
+
+	switch definition.Type {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBoolean:
+	default:
+		return fmt.Errorf("invalid attribute type %q", definition.Type)
+	}
+
+	if len(definition.AllowedValues) > 0 && definition.Type != AttributeTypeString {
+		return errors.New("allowed values are only supported for string attributes")
+	}
+
+	return nil
+}
+
+// ValidateAttributes checks attributes against the user's definitions.
+// Without definitions any well-formed scalar attributes are accepted.
+func ValidateAttributes(attributes Attributes, definitions []AttributeDefinition) error {
+	if err := ValidateDraftAttributes(attributes, definitions); err != nil {
+		return err
+	}
+
+	for _, definition := range definitions {
+		if _, present := attributes[definition.Key]; definition.Required && !present {
+			return fmt.Errorf("attribute %q is required", definition.Key)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDraftAttributes checks attributes against the user's definitions like
+// ValidateAttributes, except that required attributes may be missing
+func ValidateDraftAttributes(attributes Attributes, definitions []AttributeDefinition) error {
+	if len(attributes) > MaxAttributes {
+		return fmt.Errorf("too many attributes (maximum %d)", MaxAttributes)
+	}
+
+	byKey := make(map[string]AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		byKey[definition.Key] = definition
+	}
+
+	for key, value := range attributes {
+		if err := ValidateAttributeKey(key); err != nil {
+			return err
+		}
+
+		definition, defined := byKey[key]
+		if !defined {
+			if len(definitions) > 0 {
+				return fmt.Errorf("attribute %q is not defined", key)
+			}
+			if err := validateScalar(key, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := validateAttributeValue(definition, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CoerceAttributeFilter converts raw query values to the types declared by the definitions
+func CoerceAttributeFilter(raw map[string]string, definitions []AttributeDefinition) (Attributes, error) {
+	byKey := make(map[string]AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		byKey[definition.Key] = definition
+	}
+
+	result := make(Attributes, len(raw))
+	for key, value := range raw {
+		if err := ValidateAttributeKey(key); err != nil {
+			return nil, err
+		}
+
+		switch byKey[key].Type {
+		case AttributeTypeNumber:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %q must be a number", key)
+			}
+			result[key] = number
+		case AttributeTypeBoolean:
+			boolean, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %q must be a boolean", key)
+			}
+			result[key] = boolean
+		default:
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+// validateAttributeValue checks a value against its definition
+func validateAttributeValue(definition AttributeDefinition, value interface{}) error {
+	switch definition.Type {
+	case AttributeTypeString:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("attribute %q must be a string", definition.Key)
+		}
+		if len(str) > MaxAttributeStringValue {
+			return fmt.Errorf("attribute %q is too long", definition.Key)
+		}
+		if len(definition.AllowedValues) > 0 {
+			for _, allowed := range definition.AllowedValues {
+				if str == allowed {
+					return nil
+				}
+			}
+			return fmt.Errorf("attribute %q must be one of %v", definition.Key, []string(definition.AllowedValues))
+		}
+	case AttributeTypeNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("attribute %q must be a number", definition.Key)
+		}
+	case AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("attribute %q must be a boolean", definition.Key)
+		}
+	}
+	return nil
+}
+
+// validateScalar checks that an undefined attribute holds a simple value
+func validateScalar(key string, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if len(v) > MaxAttributeStringValue {
+			return fmt.Errorf("attribute %q is too long", key)
+		}
+	case float64, bool:
+	default:
+		return fmt.Errorf("attribute %q must be a string, number or boolean", key)
+	}
+	return nil
+}
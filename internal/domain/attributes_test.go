@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestValidateAttributes_WithoutDefinitions(t *testing.T) {
+	if err := ValidateAttributes(Attributes{"color": "red", "weight": 1.5, "fragile": true}, nil); err != nil {
+		t.Errorf("Expected scalar attributes to be accepted, got %v", err)
+	}
+
+	if err := ValidateAttributes(Attributes{"tags": []interface{}{"a"}}, nil); err == nil {
+		t.Error("Expected non-scalar attribute to be rejected")
+	}
+
+	if err := ValidateAttributes(Attributes{"Color": "red"}, nil); err == nil {
+		t.Error("Expected invalid attribute key to be rejected")
+	}
+}
+
+func TestValidateAttributes_WithDefinitions(t *testing.T) {
+	definitions := []AttributeDefinition{
+		{Key: "color", Type: AttributeTypeString, Required: true, AllowedValues: StringList{"red", "blue"}},
+		{Key: "size", Type: AttributeTypeNumber},
+	}
+
+	if err := ValidateAttributes(Attributes{"color": "red", "size": 42.0}, definitions); err != nil {
+		t.Errorf("Expected valid attributes, got %v", err)
+	}
+
+	cases := map[string]Attributes{
+		"missing required": {"size": 42.0},
+		"not allowed":      {"color": "green"},
+		"wrong type":       {"color": "red", "size": "large"},
+		"undefined key":    {"color": "red", "material": "wood"},
+	}
+
+	for name, attributes := range cases {
+		if err := ValidateAttributes(attributes, definitions); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestCoerceAttributeFilter(t *testing.T) {
+	definitions := []AttributeDefinition{
+		{Key: "size", Type: AttributeTypeNumber},
+		{Key: "fragile", Type: AttributeTypeBoolean},
+	}
+
+	attributes, err := CoerceAttributeFilter(map[string]string{"size": "42", "fragile": "true", "color": "red"}, definitions)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if attributes["size"] != 42.0 {
+		t.Errorf("Expected size 42, got %v", attributes["size"])
+	}
+	if attributes["fragile"] != true {
+		t.Errorf("Expected fragile true, got %v", attributes["fragile"])
+	}
+	if attributes["color"] != "red" {
+		t.Errorf("Expected color 'red', got %v", attributes["color"])
+	}
+
+	if _, err := CoerceAttributeFilter(map[string]string{"size": "big"}, definitions); err == nil {
+		t.Error("Expected error for non-numeric size")
+	}
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit event categories
+const (
+	AuditCategoryAuth     = "auth"
+	AuditCategoryImport   = "import"
+	AuditCategoryMutation = "mutation"
+	AuditCategoryRead     = "read"
+)
+
+// Audit export formats
+const (
+	AuditExportFormatCSV  = "csv"
+	AuditExportFormatJSON = "json"
+)
+
+// Audit export statuses
+const (
+	AuditExportPending   = "pending"
+	AuditExportRunning   = "running"
+	AuditExportCompleted = "completed"
+	AuditExportFailed    = "failed"
+)
+
+// AuditCategory classifies a recorded API call for compliance exports
+func AuditCategory(log AuditLog) string {
+	switch {
+	case strings.Contains(log.Route, "/auth/"):
+		return AuditCategoryAuth
+	case strings.HasSuffix(log.Route, "/restore") || strings.HasSuffix(log.Route, "/import"):
+		return AuditCategoryImport
+	case log.Method == "GET" || log.Method == "HEAD" || log.Method == "OPTIONS":
+		return AuditCategoryRead
+	default:
+		return AuditCategoryMutation
+	}
+}
+
+// auditCategories lists every audit category
+var auditCategories = []string{AuditCategoryAuth, AuditCategoryImport, AuditCategoryMutation, AuditCategoryRead}
+
+// AuditSampling sets the fraction of API calls recorded per audit category, with
+// per-user overrides for read-heavy tenants. Categories without a rate are always recorded.
+type AuditSampling struct {
+	Rates     map[string]float64
+	Overrides map[uuid.UUID]map[string]float64
+}
+
+// Rate returns the fraction of calls in the category recorded for the user
+func (s AuditSampling) Rate(category string, userID *uuid.UUID) float64 {
+	if userID != nil {
+		if rate, ok := s.Overrides[*userID][category]; ok {
+			return rate
+		}
+	}
+	if rate, ok := s.Rates[category]; ok {
+		return rate
+	}
+	return 1
+}
+
+// ParseAuditSampleRates parses comma-separated category=rate pairs, e.g. "read=0.01,mutation=1"
+func ParseAuditSampleRates(value string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		category, rawRate, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected category=rate, got %q", pair)
+		}
+		category = strings.TrimSpace(category)
+		known := false
+		for _, candidate := range auditCategories {
+			known = known || candidate == category
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown audit category %q", category)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rawRate), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("rate for %q must be between 0 and 1", category)
+		}
+		rates[category] = rate
+	}
+	return rates, nil
+}
+
+// ParseAuditSampleOverrides parses semicolon-separated per-user rates, e.g.
+// "<user-id>:read=0.001;<user-id>:read=1,mutation=1"
+func ParseAuditSampleOverrides(value string) (map[uuid.UUID]map[string]float64, error) {
+	overrides := map[uuid.UUID]map[string]float64{}
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		rawUserID, rawRates, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected user-id:rates, got %q", entry)
+		}
+		userID, err := uuid.Parse(strings.TrimSpace(rawUserID))
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID %q", rawUserID)
+		}
+
+		rates, err := ParseAuditSampleRates(rawRates)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: %w", userID, err)
+		}
+		overrides[userID] = rates
+	}
+	return overrides, nil
+}
+
+// AuditExport represents an asynchronously generated audit trail export
+type AuditExport struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	RequestedBy  uuid.UUID  `json:"requested_by" gorm:"type:uuid;not null;index"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	From         time.Time  `json:"from" gorm:"not null"`
+	To           time.Time  `json:"to" gorm:"not null"`
+	Format       string     `json:"format" gorm:"not null"`
+	IncludeReads bool       `json:"include_reads" gorm:"not null;default:false"`
+	Status       string     `json:"status" gorm:"not null;index"`
+	Error        string     `json:"error,omitempty"`
+	RowCount     int64      `json:"row_count"`
+	Content      []byte     `json:"-" gorm:"type:bytea"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName specifies the table name for AuditExport
+func (AuditExport) TableName() string {
+	return "audit_exports"
+}
+
+// AuditExportRequest represents the request for exporting a user's audit trail
+type AuditExportRequest struct {
+	UserID       string    `json:"user_id" binding:"required"`
+	From         time.Time `json:"from" binding:"required"`
+	To           time.Time `json:"to" binding:"required"`
+	Format       string    `json:"format" binding:"required,oneof=csv json"`
+	IncludeReads bool      `json:"include_reads"`
+}
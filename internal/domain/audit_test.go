@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditCategory(t *testing.T) {
+	tests := []struct {
+		method string
+		route  string
+		want   string
+	}{
+		{"POST", "/api/v1/auth/login", AuditCategoryAuth},
+		{"POST", "/api/v1/auth/logout", AuditCategoryAuth},
+		{"POST", "/api/v1/backups/restore", AuditCategoryImport},
+		{"POST", "/api/v1/products/", AuditCategoryMutation},
+		{"DELETE", "/api/v1/products/:id", AuditCategoryMutation},
+		{"GET", "/api/v1/products/:id", AuditCategoryRead},
+	}
+
+	for _, tt := range tests {
+		got := AuditCategory(AuditLog{Method: tt.method, Route: tt.route})
+		if got != tt.want {
+			t.Errorf("AuditCategory(%s %s) = %s, want %s", tt.method, tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestAuditSampling_Rate(t *testing.T) {
+	tenant := uuid.New()
+	other := uuid.New()
+	sampling := AuditSampling{
+		Rates:     map[string]float64{AuditCategoryRead: 0.01},
+		Overrides: map[uuid.UUID]map[string]float64{tenant: {AuditCategoryRead: 0.001, AuditCategoryMutation: 0.5}},
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		userID   *uuid.UUID
+		want     float64
+	}{
+		{"default rate", AuditCategoryRead, &other, 0.01},
+		{"anonymous", AuditCategoryRead, nil, 0.01},
+		{"unconfigured category", AuditCategoryAuth, &other, 1},
+		{"override", AuditCategoryRead, &tenant, 0.001},
+		{"override of unconfigured category", AuditCategoryMutation, &tenant, 0.5},
+		{"category without override", AuditCategoryImport, &tenant, 1},
+	}
+
+	for _, tt := range tests {
+		if got := sampling.Rate(tt.category, tt.userID); got != tt.want {
+			t.Errorf("%s: Rate() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseAuditSampleRates(t *testing.T) {
+	rates, err := ParseAuditSampleRates(" read=0.01, mutation=1 ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rates) != 2 || rates[AuditCategoryRead] != 0.01 || rates[AuditCategoryMutation] != 1 {
+		t.Errorf("Unexpected rates %v", rates)
+	}
+
+	for _, value := range []string{"read", "views=0.5", "read=1.5", "read=-0.1", "read=often"} {
+		if _, err := ParseAuditSampleRates(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestParseAuditSampleOverrides(t *testing.T) {
+	first := uuid.New()
+	second := uuid.New()
+
+	overrides, err := ParseAuditSampleOverrides(first.String() + ":read=0.001;" + second.String() + ":read=1,mutation=1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if overrides[first][AuditCategoryRead] != 0.001 || len(overrides[second]) != 2 {
+		t.Errorf("Unexpected overrides %v", overrides)
+	}
+
+	for _, value := range []string{"read=0.5", "not-a-uuid:read=0.5", first.String() + ":read=2"} {
+		if _, err := ParseAuditSampleOverrides(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
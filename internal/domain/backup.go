@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupFormatVersion is the current version of the backup file format
+const BackupFormatVersion = 1
+
+// Backup record types
+const (
+	BackupRecordMetadata            = "metadata"
+	BackupRecordProduct             = "product"
+	BackupRecordAttributeDefinition = "attribute_definition"
+)
+
+// BackupMetadata describes the owner and contents of a backup
+type BackupMetadata struct {
+	FormatVersion int       `json:"format_version"`
+	UserID        uuid.UUID `json:"user_id"`
+	Email         string    `json:"email"`
+	Name          string    `json:"name"`
+	ProductCount  int       `json:"product_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BackupRecord represents a single NDJSON line of a backup file
+type BackupRecord struct {
+	Type                string               `json:"type"`
+	Metadata            *BackupMetadata      `json:"metadata,omitempty"`
+	Product             *Product             `json:"product,omitempty"`
+	AttributeDefinition *AttributeDefinition `json:"attribute_definition,omitempty"`
+}
+
+// ParsedBackup holds the validated contents of a backup file
+type ParsedBackup struct {
+	Metadata             BackupMetadata
+	Products             []Product
+	AttributeDefinitions []AttributeDefinition
+}
+
+// BackupInfo represents a stored backup
+type BackupInfo struct {
+	Key       string    `json:"key"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreBackupRequest represents the request for restoring a backup
+type RestoreBackupRequest struct {
+	Key string `json:"key" binding:"required"`
+}
+
+// RestoreBackupResponse represents the result of a restore
+type RestoreBackupResponse struct {
+	Key                          string `json:"key"`
+	RestoredProducts             int    `json:"restored_products"`
+	RestoredAttributeDefinitions int    `json:"restored_attribute_definitions"`
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BillingCustomer links a user to their Stripe customer and the subscription that
+// pays for their plan
+type BillingCustomer struct {
+	UserID               uuid.UUID  `json:"-" gorm:"type:uuid;primaryKey"`
+	StripeCustomerID     string     `json:"-" gorm:"not null;uniqueIndex"`
+	StripeSubscriptionID string     `json:"-"`
+	SubscriptionStatus   string     `json:"subscription_status"`
+	Plan                 string     `json:"plan"`
+	CurrentPeriodEnd     *time.Time `json:"current_period_end,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for BillingCustomer
+func (BillingCustomer) TableName() string {
+	return "billing_customers"
+}
+
+// Invoice is a Stripe invoice of a user's subscription. Amounts are in the
+// smallest unit of the currency.
+type Invoice struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID           uuid.UUID  `json:"-" gorm:"type:uuid;not null;index"`
+	StripeInvoiceID  string     `json:"-" gorm:"not null;uniqueIndex"`
+	Number           string     `json:"number"`
+	Status           string     `json:"status"`
+	Currency         string     `json:"currency"`
+	AmountDue        int64      `json:"amount_due"`
+	AmountPaid       int64      `json:"amount_paid"`
+	HostedInvoiceURL string     `json:"hosted_invoice_url,omitempty"`
+	InvoicePDF       string     `json:"invoice_pdf,omitempty"`
+	PeriodStart      *time.Time `json:"period_start,omitempty"`
+	PeriodEnd        *time.Time `json:"period_end,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for Invoice
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// activeSubscriptionStatuses are the Stripe subscription statuses that keep the
+// subscribed plan; past_due subscriptions keep it while Stripe retries the payment
+var activeSubscriptionStatuses = []string{"active", "trialing", "past_due"}
+
+// SubscriptionActive reports whether a subscription in the Stripe status grants
+// its plan
+func SubscriptionActive(status string) bool {
+	return slices.Contains(activeSubscriptionStatuses, status)
+}
+
+// CheckoutRequest starts a payment for a paid plan
+type CheckoutRequest struct {
+	Plan string `json:"plan" binding:"required,oneof=pro enterprise"`
+}
+
+// BillingLinkResponse is a Stripe page to send the user to
+type BillingLinkResponse struct {
+	URL string `json:"url"`
+}
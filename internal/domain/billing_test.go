@@ -0,0 +1,22 @@
+package domain
+
+import "testing"
+
+func TestSubscriptionActive(t *testing.T) {
+	tests := map[string]bool{
+		"active":             true,
+		"trialing":           true,
+		"past_due":           true,
+		"canceled":           false,
+		"unpaid":             false,
+		"incomplete":         false,
+		"incomplete_expired": false,
+		"":                   false,
+	}
+
+	for status, expected := range tests {
+		if got := SubscriptionActive(status); got != expected {
+			t.Errorf("SubscriptionActive(%q) = %v, expected %v", status, got, expected)
+		}
+	}
+}
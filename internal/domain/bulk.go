@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// MaxBulkItems limits the number of items in one bulk request
+const MaxBulkItems = 100
+
+// BulkItemError describes why one item of a bulk operation failed, using the same
+// codes and field errors as problem responses
+type BulkItemError struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// BulkItemResult is the outcome of one item of a bulk operation. Index is the item's
+// position in the request array, or its line number in an imported CSV file.
+type BulkItemResult struct {
+	Index  int            `json:"index"`
+	ID     *uuid.UUID     `json:"id,omitempty"`
+	Status int            `json:"status"`
+	Error  *BulkItemError `json:"error,omitempty"`
+}
+
+// BulkResult is the multi-status response shared by bulk product operations and imports
+type BulkResult struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkItemResult `json:"items"`
+}
+
+// NewBulkResult creates an empty bulk result
+func NewBulkResult() *BulkResult {
+	return &BulkResult{Items: []BulkItemResult{}}
+}
+
+// Succeed records a successful item
+func (r *BulkResult) Succeed(index int, id uuid.UUID) {
+	r.Items = append(r.Items, BulkItemResult{Index: index, ID: &id})
+	r.Succeeded++
+}
+
+// Fail records a failed item. Typed errors keep their code; field errors are
+// reported individually; other errors are reported as internal errors.
+func (r *BulkResult) Fail(index int, id *uuid.UUID, err error) {
+	itemErr := &BulkItemError{Code: CodeInternal, Message: err.Error()}
+
+	var typed *Error
+	if errors.As(err, &typed) {
+		itemErr.Code = typed.Code
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		itemErr.Code = CodeTimeout
+	}
+
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		itemErr.Code = CodeValidationFailed
+		itemErr.Errors = validation.Fields
+	}
+
+	r.Items = append(r.Items, BulkItemResult{Index: index, ID: id, Error: itemErr})
+	r.Failed++
+}
+
+// Sort orders the items by index
+func (r *BulkResult) Sort() {
+	sort.SliceStable(r.Items, func(i, j int) bool {
+		return r.Items[i].Index < r.Items[j].Index
+	})
+}
+
+// ValidationError reports the invalid fields of a request or bulk item
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
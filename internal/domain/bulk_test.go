@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBulkResult_FailTimeout(t *testing.T) {
+	result := NewBulkResult()
+	result.Fail(0, nil, fmt.Errorf("failed to create product: %w", context.DeadlineExceeded))
+
+	if code := result.Items[0].Error.Code; code != CodeTimeout {
+		t.Errorf("Expected code %s for an item cut off by the deadline, got %s", CodeTimeout, code)
+	}
+}
+
+func TestBulkResult_Fail(t *testing.T) {
+	id := uuid.New()
+	result := NewBulkResult()
+	result.Fail(2, &id, ErrProductNotFound)
+	result.Fail(0, nil, &ValidationError{Message: "invalid", Fields: []FieldError{{Field: "price", Message: "required"}}})
+	result.Fail(1, nil, errors.New("connection reset"))
+	result.Succeed(3, uuid.New())
+	result.Sort()
+
+	if result.Succeeded != 1 || result.Failed != 3 {
+		t.Fatalf("Expected 1 succeeded and 3 failed, got %d and %d", result.Succeeded, result.Failed)
+	}
+
+	wantCodes := []string{CodeValidationFailed, CodeInternal, CodeProductNotFound}
+	for i, want := range wantCodes {
+		item := result.Items[i]
+		if item.Index != i {
+			t.Fatalf("Expected items sorted by index, got %+v", result.Items)
+		}
+		if item.Error == nil || item.Error.Code != want {
+			t.Errorf("Item %d: expected code %s, got %+v", i, want, item.Error)
+		}
+	}
+
+	if len(result.Items[0].Error.Errors) != 1 {
+		t.Errorf("Expected the field errors to be kept, got %+v", result.Items[0].Error)
+	}
+	if result.Items[2].ID == nil || *result.Items[2].ID != id {
+		t.Errorf("Expected the failed item to keep its ID, got %v", result.Items[2].ID)
+	}
+	if result.Items[3].Error != nil || result.Items[3].ID == nil {
+		t.Errorf("Expected a successful item with an ID, got %+v", result.Items[3])
+	}
+}
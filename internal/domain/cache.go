@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Cache defines the interface of the shared cache: JSON values, counters, hashes,
+// sets and schedules of members due at a time. Operations fail while the cache is
+// not Available.
+type Cache interface {
+	Available() bool
+
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	GetRaw(ctx context.Context, key string) ([]byte, error)
+	SetRaw(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	DeletePattern(ctx context.Context, pattern string) error
+
+	Incr(ctx context.Context, key string) (int64, error)
+	IncrHash(ctx context.Context, key string, increments map[string]int64, setKey, member string, expiration time.Duration) error
+	GetHashInts(ctx context.Context, key string) (map[string]int64, error)
+	SetMembers(ctx context.Context, key string) ([]string, error)
+
+	SetScheduled(ctx context.Context, key string, value interface{}, expiration time.Duration, setKey, member string, at time.Time) error
+	Schedule(ctx context.Context, setKey, member string, at time.Time) error
+	Unschedule(ctx context.Context, setKey, member string) (bool, error)
+	DueMembers(ctx context.Context, setKey string, now time.Time, limit int64) ([]string, error)
+}
+
+// Broker defines the interface of the shared publish/subscribe channels that carry
+// real-time updates between API instances. Messages published while nobody is
+// subscribed are dropped.
+type Broker interface {
+	Publish(ctx context.Context, channel string, message []byte) error
+	// Subscribe returns the messages published on channel until ctx is done, when
+	// the returned channel is closed
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
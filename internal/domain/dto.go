@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,55 +9,308 @@ import (
 
 // CreateUserRequest represents the request for user registration
 type CreateUserRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
-	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email_address" sanitize:"true"`
+	Password string `json:"password" binding:"required,password"`
+	Name     string `json:"name" binding:"required,person_name" sanitize:"true"`
 }
 
 // LoginRequest represents the request for user login
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email        string `json:"email" binding:"required,email_address" sanitize:"true"`
+	Password     string `json:"password" binding:"required"`
+	CaptchaToken string `json:"captcha_token,omitempty"`
+	DeviceID     string `json:"device_id,omitempty" binding:"max=200"`
+	DeviceCode   string `json:"device_code,omitempty"`
+	// RememberMe keeps the session and refresh token for 30 days instead of 24 hours
+	RememberMe bool `json:"remember_me,omitempty"`
+}
+
+// UserResponse is the API representation of a user. Credentials and internal
+// bookkeeping such as quota warnings and code sequences are left out, whatever
+// their tags on User.
+type UserResponse struct {
+	ID                  uuid.UUID       `json:"id"`
+	Email               string          `json:"email"`
+	Name                string          `json:"name"`
+	Role                string          `json:"role"`
+	Plan                string          `json:"plan"`
+	Slug                *string         `json:"slug,omitempty"`
+	AvatarURL           *string         `json:"avatar_url,omitempty"`
+	EmailVerifiedAt     *time.Time      `json:"email_verified_at,omitempty"`
+	DeletionScheduledAt *time.Time      `json:"deletion_scheduled_at,omitempty"`
+	Preferences         UserPreferences `json:"preferences"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
+// NewUserResponse maps a user to its API representation
+func NewUserResponse(user *User) UserResponse {
+	return UserResponse{
+		ID:                  user.ID,
+		Email:               user.Email,
+		Name:                user.Name,
+		Role:                user.Role,
+		Plan:                user.Plan,
+		Slug:                user.Slug,
+		AvatarURL:           user.AvatarURL(),
+		EmailVerifiedAt:     user.EmailVerifiedAt,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		Preferences:         user.Preferences,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+	}
 }
 
 // LoginResponse represents the response for user login
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	User         User   `json:"user"`
-	ExpiresIn    int64  `json:"expires_in"`
+	AccessToken  string       `json:"access_token"`
+	RefreshToken string       `json:"refresh_token"`
+	User         UserResponse `json:"user"`
+	ExpiresIn    int64        `json:"expires_in"`
+	// RevokedSessions are the oldest sessions of the user, logged out to keep
+	// within the concurrent session limit
+	RevokedSessions []SessionInfo `json:"revoked_sessions,omitempty"`
 }
 
 // CreateProductRequest represents the request for product creation
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"required,gte=0"`
+	Name        string     `json:"name" binding:"required,product_name" sanitize:"true"`
+	Description string     `json:"description" binding:"description" sanitize:"multiline"`
+	Price       float64    `json:"price" binding:"required,price"`
+	Stock       int        `json:"stock" binding:"stock"`
+	Attributes  Attributes `json:"attributes" binding:"omitempty,attributes"`
+	Public      bool       `json:"public"`
+
+	LowStockThreshold *int `json:"low_stock_threshold" binding:"omitempty,gte=0"`
+	ReorderQuantity   *int `json:"reorder_quantity" binding:"omitempty,gte=0"`
+}
+
+// CreateDraftRequest represents the request for saving a draft product. Only the
+// name is required; the rules of CreateProductRequest apply once it is published.
+type CreateDraftRequest struct {
+	Name        string     `json:"name" binding:"required,product_name" sanitize:"true"`
+	Description string     `json:"description" binding:"description" sanitize:"multiline"`
+	Price       float64    `json:"price" binding:"omitempty,price"`
+	Stock       int        `json:"stock" binding:"stock"`
+	Attributes  Attributes `json:"attributes" binding:"omitempty,attributes"`
+
+	LowStockThreshold *int `json:"low_stock_threshold" binding:"omitempty,gte=0"`
+	ReorderQuantity   *int `json:"reorder_quantity" binding:"omitempty,gte=0"`
 }
 
 // UpdateProductRequest represents the request for product update
 type UpdateProductRequest struct {
-	Name        *string  `json:"name"`
-	Description *string  `json:"description"`
-	Price       *float64 `json:"price"`
-	Stock       *int     `json:"stock"`
+	Name        *string    `json:"name" binding:"omitempty,product_name" sanitize:"true"`
+	Description *string    `json:"description" binding:"omitempty,description" sanitize:"multiline"`
+	Price       *float64   `json:"price" binding:"omitempty,price"`
+	Stock       *int       `json:"stock" binding:"omitempty,stock"`
+	Attributes  Attributes `json:"attributes" binding:"omitempty,attributes"`
+
+	LowStockThreshold *int `json:"low_stock_threshold" binding:"omitempty,gte=0"`
+	ReorderQuantity   *int `json:"reorder_quantity" binding:"omitempty,gte=0"`
+}
+
+// BulkProductRequest carries the items of a bulk create or update. Items are
+// decoded and validated one at a time so an invalid item fails on its own.
+type BulkProductRequest struct {
+	Items []json.RawMessage `json:"items" binding:"required,min=1,max=100"`
+}
+
+// BulkUpdateProductItem identifies the product a bulk update item applies to; the
+// remaining fields of the item are an UpdateProductRequest
+type BulkUpdateProductItem struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+}
+
+// MaxBatchGetProducts is the most products fetched by ID in one request
+const MaxBatchGetProducts = 200
+
+// BatchGetProductRequest lists the IDs of the products to fetch
+type BatchGetProductRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1,max=200"`
+}
+
+// BatchGetProductResponse holds the fetched products in the order requested.
+// Missing lists the requested IDs of products that do not exist or that the user
+// may not view, which are not told apart.
+type BatchGetProductResponse struct {
+	Products []Product   `json:"products"`
+	Missing  []uuid.UUID `json:"missing"`
+}
+
+// BulkDeleteProductRequest represents the request for deleting several products
+type BulkDeleteProductRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100"`
 }
 
 // ProductResponse represents the product response
 type ProductResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Stock       int       `json:"stock"`
-	UserID      uuid.UUID `json:"user_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
-
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-} 
\ No newline at end of file
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Price       float64    `json:"price"`
+	Stock       int        `json:"stock"`
+	Attributes  Attributes `json:"attributes"`
+	Public      bool       `json:"public"`
+	UserID      uuid.UUID  `json:"user_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AccountDeletionResponse represents the response for an account deletion request
+type AccountDeletionResponse struct {
+	Message      string    `json:"message"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
+// AccountExport represents a machine-readable archive of a user's personal data
+type AccountExport struct {
+	ExportedAt    time.Time             `json:"exported_at"`
+	User          UserResponse          `json:"user"`
+	Products      []Product             `json:"products"`
+	Sessions      []SessionInfo         `json:"sessions"`
+	LoginHistory  []LoginEvent          `json:"login_history"`
+	LoginDevices  []LoginDevice         `json:"login_devices"`
+	Orders        []Order               `json:"orders"`
+	Reviews       []Review              `json:"reviews"`
+	Notes         []ProductNote         `json:"notes"`
+	SavedSearches []SavedSearch         `json:"saved_searches"`
+	Webhooks      []WebhookSubscription `json:"webhooks"`
+	Notifications []Notification        `json:"notifications"`
+	APIUsage      []APIUsage            `json:"api_usage"`
+}
+
+// ExportDestinationRequest represents the request for configuring a user's export bucket
+type ExportDestinationRequest struct {
+	Endpoint        string `json:"endpoint" binding:"required"`
+	Region          string `json:"region" binding:"required"`
+	Bucket          string `json:"bucket" binding:"required"`
+	Prefix          string `json:"prefix"`
+	AccessKeyID     string `json:"access_key_id" binding:"required"`
+	SecretAccessKey string `json:"secret_access_key" binding:"required"`
+	UsePathStyle    bool   `json:"use_path_style"`
+}
+
+// NotificationListResponse represents a user's notifications
+type NotificationListResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+}
+
+// QuotaStatus represents a user's product quota usage
+type QuotaStatus struct {
+	Plan        string     `json:"plan"`
+	Limit       int64      `json:"limit"`
+	Used        int64      `json:"used"`
+	Percent     float64    `json:"percent"`
+	Warning     bool       `json:"warning"`
+	Exceeded    bool       `json:"exceeded"`
+	GraceEndsAt *time.Time `json:"grace_ends_at,omitempty"`
+}
+
+// ProductVisibilityRequest represents the request for publishing or unpublishing a product
+type ProductVisibilityRequest struct {
+	Public *bool `json:"public" binding:"required"`
+}
+
+// SlugRequest represents the request for setting a user's public catalog slug
+type SlugRequest struct {
+	Slug string `json:"slug" binding:"required"`
+}
+
+// PublicProductResponse represents a product in the public catalog
+type PublicProductResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	DescriptionHTML string     `json:"description_html,omitempty"`
+	Price           float64    `json:"price"`
+	Stock           int        `json:"stock"`
+	Attributes      Attributes `json:"attributes"`
+	RatingAverage   float64    `json:"rating_average"`
+	RatingCount     int        `json:"rating_count"`
+	Seller          string     `json:"seller,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// FavoriteProduct is a product on a user's favorites: one of the user's own
+// products, or another user's product as shown in the public catalog
+type FavoriteProduct struct {
+	Product *Product
+	Public  *PublicProductResponse
+}
+
+// MarshalJSON encodes the public product when set, otherwise the own product
+func (f FavoriteProduct) MarshalJSON() ([]byte, error) {
+	if f.Public != nil {
+		return json.Marshal(f.Public)
+	}
+	return json.Marshal(f.Product)
+}
+
+// PublicProductListResponse represents a paginated public catalog of a user
+type PublicProductListResponse struct {
+	Seller     string                  `json:"seller"`
+	Products   []PublicProductResponse `json:"products"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	PageSize   int                     `json:"page_size"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// StockTokenRequest represents the request for issuing a quick stock update token
+type StockTokenRequest struct {
+	TTL string `json:"ttl"`
+}
+
+// StockTokenResponse represents an issued quick stock update token
+type StockTokenResponse struct {
+	Token     string    `json:"token"`
+	ProductID uuid.UUID `json:"product_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	URL       string    `json:"url"`
+}
+
+// QuickStockAdjustRequest represents a stock adjustment authorized by a stock token,
+// optionally of the stock kept at one of the owner's locations
+type QuickStockAdjustRequest struct {
+	Token      string     `json:"token" binding:"required"`
+	Delta      int        `json:"delta" binding:"required"`
+	LocationID *uuid.UUID `json:"location_id"`
+}
+
+// StockDecrementRequest represents the request for taking units out of a product's stock
+type StockDecrementRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1,max=1000000"`
+}
+
+// StockResponse represents a product's stock after a change
+type StockResponse struct {
+	ProductID      uuid.UUID `json:"product_id"`
+	Stock          int       `json:"stock"`
+	ReservedStock  int       `json:"reserved_stock"`
+	AvailableStock int       `json:"available_stock"`
+}
+
+// QuickStockResponse represents the product view available to stock token holders
+type QuickStockResponse struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Name      string    `json:"name"`
+	Stock     int       `json:"stock"`
+}
+
+// JSONWebKey is a public token verification key published in a JWK set
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JSONWebKeySet is the response of the JWKS endpoint
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewUserResponse(t *testing.T) {
+	warnedAt := time.Now()
+	user := &User{
+		ID:             uuid.New(),
+		Email:          "jane@example.com",
+		Password:       "$2a$10$hash",
+		Name:           "Jane",
+		Role:           "user",
+		Plan:           "free",
+		QuotaWarnedAt:  &warnedAt,
+		ProductCodeSeq: 42,
+	}
+
+	body, err := json.Marshal(NewUserResponse(user))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	for _, leaked := range []string{"password", "$2a$10$hash", "quota_warned_at", "product_code_seq"} {
+		if strings.Contains(strings.ToLower(string(body)), strings.ToLower(leaked)) {
+			t.Errorf("Expected %q left out, got %s", leaked, body)
+		}
+	}
+	for _, field := range []string{`"id":"` + user.ID.String(), `"email":"jane@example.com"`, `"plan":"free"`} {
+		if !strings.Contains(string(body), field) {
+			t.Errorf("Expected %s in %s", field, body)
+		}
+	}
+}
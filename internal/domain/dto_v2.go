@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIVersion describes a served API version
+type APIVersion struct {
+	Name       string     `json:"name"`
+	BasePath   string     `json:"base_path"`
+	Deprecated bool       `json:"deprecated"`
+	SunsetDate *time.Time `json:"sunset_date,omitempty"`
+	Successor  string     `json:"successor,omitempty"`
+}
+
+// PriceToCents converts a decimal price to integer cents
+func PriceToCents(price float64) int64 {
+	return int64(math.Round(price * 100))
+}
+
+// CentsToPrice converts integer cents to a decimal price
+func CentsToPrice(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// CreateProductV2Request represents the v2 request for product creation
+type CreateProductV2Request struct {
+	Name        string     `json:"name" binding:"required,product_name" sanitize:"true"`
+	Description string     `json:"description" binding:"description" sanitize:"multiline"`
+	PriceCents  int64      `json:"price_cents" binding:"required,price_cents"`
+	Stock       int        `json:"stock" binding:"stock"`
+	Attributes  Attributes `json:"attributes" binding:"omitempty,attributes"`
+	Public      bool       `json:"public"`
+}
+
+// UpdateProductV2Request represents the v2 request for product update
+type UpdateProductV2Request struct {
+	Name        *string    `json:"name" binding:"omitempty,product_name" sanitize:"true"`
+	Description *string    `json:"description" binding:"omitempty,description" sanitize:"multiline"`
+	PriceCents  *int64     `json:"price_cents" binding:"omitempty,price_cents"`
+	Stock       *int       `json:"stock" binding:"omitempty,stock"`
+	Attributes  Attributes `json:"attributes" binding:"omitempty,attributes"`
+}
+
+// ProductV2Response represents a product in v2, with money as integer cents
+type ProductV2Response struct {
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	DescriptionHTML string     `json:"description_html,omitempty"`
+	PriceCents      int64      `json:"price_cents"`
+	PriceFormatted  string     `json:"price_formatted,omitempty"`
+	Stock           int        `json:"stock"`
+	ReservedStock   int        `json:"reserved_stock"`
+	AvailableStock  int        `json:"available_stock"`
+	Attributes      Attributes `json:"attributes"`
+	Public          bool       `json:"public"`
+	RatingAverage   float64    `json:"rating_average"`
+	RatingCount     int        `json:"rating_count"`
+	Status          string     `json:"status"`
+	ArchivedAt      *time.Time `json:"archived_at,omitempty"`
+	UserID          uuid.UUID  `json:"user_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ProductV2ListResponse represents a paginated list of v2 products
+type ProductV2ListResponse struct {
+	Products      []ProductV2Response `json:"products"`
+	Total         *int64              `json:"total,omitempty"`
+	TotalEstimate *int64              `json:"total_estimate,omitempty"`
+	Page          int                 `json:"page"`
+	PageSize      int                 `json:"page_size"`
+	TotalPages    *int                `json:"total_pages,omitempty"`
+	HasNext       bool                `json:"has_next"`
+	HasPrev       bool                `json:"has_prev"`
+}
+
+// NewProductV2Response converts a product to its v2 representation
+func NewProductV2Response(product *Product) ProductV2Response {
+	return ProductV2Response{
+		ID:              product.ID,
+		Name:            product.Name,
+		Description:     product.Description,
+		DescriptionHTML: product.DescriptionHTML,
+		PriceCents:      PriceToCents(product.Price),
+		PriceFormatted:  product.PriceFormatted,
+		Stock:           product.Stock,
+		ReservedStock:   product.ReservedStock,
+		AvailableStock:  product.AvailableStock(),
+		Attributes:      product.Attributes,
+		Public:          product.Public,
+		RatingAverage:   product.RatingAverage,
+		RatingCount:     product.RatingCount,
+		Status:          product.Status,
+		ArchivedAt:      product.ArchivedAt,
+		UserID:          product.UserID,
+		CreatedAt:       product.CreatedAt,
+		UpdatedAt:       product.UpdatedAt,
+	}
+}
+
+// NewProductV2ListResponse converts a product list to its v2 representation
+func NewProductV2ListResponse(list *ProductListResponse) ProductV2ListResponse {
+	response := ProductV2ListResponse{
+		Products:      make([]ProductV2Response, 0, len(list.Products)),
+		Total:         list.Total,
+		TotalEstimate: list.TotalEstimate,
+		Page:          list.Page,
+		PageSize:      list.PageSize,
+		TotalPages:    list.TotalPages,
+		HasNext:       list.HasNext,
+		HasPrev:       list.HasPrev,
+	}
+	for i := range list.Products {
+		response.Products = append(response.Products, NewProductV2Response(&list.Products[i]))
+	}
+	return response
+}
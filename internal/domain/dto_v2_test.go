@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestPriceToCents(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  int64
+	}{
+		{0.01, 1},
+		{19.99, 1999},
+		{0.29, 29},
+		{10.10, 1010},
+		{999999.99, 99999999},
+	}
+
+	for _, tt := range tests {
+		if got := PriceToCents(tt.price); got != tt.want {
+			t.Errorf("PriceToCents(%v) = %d, want %d", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestCentsToPrice_RoundTrip(t *testing.T) {
+	for _, cents := range []int64{1, 29, 1999, 99999999} {
+		if got := PriceToCents(CentsToPrice(cents)); got != cents {
+			t.Errorf("round trip of %d cents = %d", cents, got)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transactional email template keys
+const (
+	EmailTemplateVerification  = "verification"
+	EmailTemplatePasswordReset = "password_reset"
+	EmailTemplateDigest        = "digest"
+	EmailTemplateNewDevice     = "new_device"
+	EmailTemplateDeviceCode    = "device_verification"
+	EmailTemplateNewLocation   = "new_location"
+)
+
+// EmailTemplateSpec describes the variables a template may use and which of them it must use
+type EmailTemplateSpec struct {
+	Variables []string
+	Required  []string
+	Sample    map[string]string
+	Default   EmailTemplate
+}
+
+// EmailTemplateSpecs lists every transactional email template the application sends
+var EmailTemplateSpecs = map[string]EmailTemplateSpec{
+	EmailTemplateVerification: {
+		Variables: []string{"name", "email", "token", "expires_in"},
+		Required:  []string{"token"},
+		Sample:    map[string]string{"name": "Jane Doe", "email": "jane@example.com", "token": "3f2a9c...", "expires_in": "24 hours"},
+		Default: EmailTemplate{
+			Subject: "Verify your email address",
+			Body:    "Confirm your email address by submitting this token to POST /api/v1/auth/verify-email:\n\n{{.token}}\n\nThe token expires in {{.expires_in}}.",
+		},
+	},
+	EmailTemplatePasswordReset: {
+		Variables: []string{"name", "email", "token", "expires_in"},
+		Required:  []string{"token"},
+		Sample:    map[string]string{"name": "Jane Doe", "email": "jane@example.com", "token": "8b41d0...", "expires_in": "1 hour"},
+		Default: EmailTemplate{
+			Subject: "Reset your password",
+			Body:    "Hi {{.name}},\n\nUse this token to reset your password:\n\n{{.token}}\n\nThe token expires in {{.expires_in}}. If you did not ask for a reset, ignore this email.",
+		},
+	},
+	EmailTemplateDigest: {
+		Variables: []string{"name", "email", "summary"},
+		Required:  []string{"summary"},
+		Sample:    map[string]string{"name": "Jane Doe", "email": "jane@example.com", "summary": "3 products are low on stock."},
+		Default: EmailTemplate{
+			Subject: "Your product digest",
+			Body:    "Hi {{.name}},\n\n{{.summary}}",
+		},
+	},
+	EmailTemplateNewDevice: {
+		Variables: []string{"name", "email", "device", "ip_address", "time"},
+		Required:  []string{"device"},
+		Sample:    map[string]string{"name": "Jane Doe", "email": "jane@example.com", "device": "Mozilla/5.0 (X11; Linux x86_64)", "ip_address": "203.0.113.7", "time": "2026-10-17 12:00 UTC"},
+		Default: EmailTemplate{
+			Subject: "New sign-in to your account",
+			Body:    "Hi {{.name}},\n\nYour account was signed in to from a new device:\n\n{{.device}}\nIP address: {{.ip_address}}\nTime: {{.time}}\n\nIf this was not you, change your password and log out all devices.",
+		},
+	},
+	EmailTemplateDeviceCode: {
+		Variables: []string{"name", "email", "device", "ip_address", "code", "expires_in"},
+		Required:  []string{"code"},
+		Sample:    map[string]string{"name": "Jane Doe", "email": "jane@example.com", "device": "Mozilla/5.0 (X11; Linux x86_64)", "ip_address": "203.0.113.7", "code": "482915", "expires_in": "15 minutes"},
+		Default: EmailTemplate{
+			Subject: "Confirm your sign-in from a new device",
+			Body:    "Hi {{.name}},\n\nA sign-in from a new device needs confirming:\n\n{{.device}}\nIP address: {{.ip_address}}\n\nEnter this code to complete it:\n\n{{.code}}\n\nThe code expires in {{.expires_in}}. If this was not you, change your password.",
+		},
+	},
+	EmailTemplateNewLocation: {
+		Variables: []string{"name", "email", "country", "city", "ip_address", "user_agent", "time"},
+		Required:  []string{"country"},
+		Sample:    map[string]string{"name": "Jane Doe", "email": "jane@example.com", "country": "DE", "city": "Berlin", "ip_address": "203.0.113.7", "user_agent": "Mozilla/5.0 (X11; Linux x86_64)", "time": "2026-10-17 12:00 UTC"},
+		Default: EmailTemplate{
+			Subject: "Sign-in to your account from a new country",
+			Body:    "Hi {{.name}},\n\nYour account was signed in to from a country you have not signed in from before:\n\nLocation: {{.city}} {{.country}}\nIP address: {{.ip_address}}\nDevice: {{.user_agent}}\nTime: {{.time}}\n\nIf this was not you, change your password and log out all devices.",
+		},
+	},
+}
+
+// EmailTemplate represents an admin-managed transactional email template
+type EmailTemplate struct {
+	Key       string     `json:"key" gorm:"primary_key"`
+	Subject   string     `json:"subject" gorm:"not null"`
+	Body      string     `json:"body" gorm:"type:text;not null"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for EmailTemplate
+func (EmailTemplate) TableName() string {
+	return "email_templates"
+}
+
+// EmailTemplateRequest represents the request for updating an email template
+type EmailTemplateRequest struct {
+	Subject string `json:"subject" binding:"required,max=200"`
+	Body    string `json:"body" binding:"required,max=20000"`
+}
+
+// EmailTemplatePreviewRequest represents the request for previewing an email template.
+// Omitted subject or body fall back to the current template; omitted variables use sample values.
+type EmailTemplatePreviewRequest struct {
+	Subject   string            `json:"subject" binding:"max=200"`
+	Body      string            `json:"body" binding:"max=20000"`
+	Variables map[string]string `json:"variables"`
+}
+
+// EmailTemplateResponse represents an email template with its allowed variables
+type EmailTemplateResponse struct {
+	Key        string     `json:"key"`
+	Subject    string     `json:"subject"`
+	Body       string     `json:"body"`
+	Variables  []string   `json:"variables"`
+	Required   []string   `json:"required"`
+	Customized bool       `json:"customized"`
+	UpdatedBy  *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// RenderedEmail is an email template rendered with concrete variables
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// ValidateEmailTemplate parses the subject and body and checks they only use the
+// template's variables and include every required one
+func ValidateEmailTemplate(key, subject, body string) error {
+	spec, ok := EmailTemplateSpecs[key]
+	if !ok {
+		return fmt.Errorf("unknown email template %q", key)
+	}
+
+	allowed := make(map[string]bool, len(spec.Variables))
+	for _, variable := range spec.Variables {
+		allowed[variable] = true
+	}
+
+	used := map[string]bool{}
+	for name, text := range map[string]string{"subject": subject, "body": body} {
+		tmpl, err := template.New(name).Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+		collectTemplateFields(tmpl.Tree.Root, used)
+	}
+
+	var unknown []string
+	for variable := range used {
+		if !allowed[variable] {
+			unknown = append(unknown, variable)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown variables %s; allowed: %s", strings.Join(unknown, ", "), strings.Join(spec.Variables, ", "))
+	}
+
+	for _, variable := range spec.Required {
+		if !used[variable] {
+			return fmt.Errorf("template must use variable %q", variable)
+		}
+	}
+
+	return nil
+}
+
+// RenderEmailTemplate renders a template with the given variables
+func RenderEmailTemplate(tmpl EmailTemplate, variables map[string]string) (*RenderedEmail, error) {
+	subject, err := renderTemplateText("subject", tmpl.Subject, variables)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderTemplateText("body", tmpl.Body, variables)
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedEmail{Subject: subject, Body: body}, nil
+}
+
+// renderTemplateText executes a single template text, failing on missing variables
+func renderTemplateText(name, text string, variables map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %v", name, err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, variables); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", name, err)
+	}
+	return out.String(), nil
+}
+
+// collectTemplateFields records the top-level field names referenced by a template tree
+func collectTemplateFields(node parse.Node, fields map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectTemplateFields(child, fields)
+		}
+	case *parse.ActionNode:
+		collectTemplateFields(n.Pipe, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectTemplateFields(cmd, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectTemplateFields(arg, fields)
+		}
+	case *parse.FieldNode:
+		fields[n.Ident[0]] = true
+	case *parse.IfNode:
+		collectTemplateFields(n.Pipe, fields)
+		collectTemplateFields(n.List, fields)
+		collectTemplateFields(n.ElseList, fields)
+	case *parse.RangeNode:
+		collectTemplateFields(n.Pipe, fields)
+		collectTemplateFields(n.List, fields)
+		collectTemplateFields(n.ElseList, fields)
+	case *parse.WithNode:
+		collectTemplateFields(n.Pipe, fields)
+		collectTemplateFields(n.List, fields)
+		collectTemplateFields(n.ElseList, fields)
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateEmailTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		subject string
+		body    string
+		wantErr string
+	}{
+		{"valid", EmailTemplateVerification, "Welcome {{.name}}", "Token: {{.token}}", ""},
+		{"conditional", EmailTemplateDigest, "Digest", "{{if .name}}Hi {{.name}}{{end}} {{.summary}}", ""},
+		{"unknown key", "welcome", "Hi", "Hi", "unknown email template"},
+		{"parse error", EmailTemplateVerification, "Hi", "{{.token", "invalid body"},
+		{"unknown variable", EmailTemplateVerification, "Hi {{.password}}", "{{.token}}", "unknown variables password"},
+		{"missing required", EmailTemplatePasswordReset, "Reset", "Hi {{.name}}", `must use variable "token"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEmailTemplate(tt.key, tt.subject, tt.body)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDefaultEmailTemplatesAreValid(t *testing.T) {
+	for key, spec := range EmailTemplateSpecs {
+		if err := ValidateEmailTemplate(key, spec.Default.Subject, spec.Default.Body); err != nil {
+			t.Errorf("Default %s template is invalid: %v", key, err)
+		}
+		if _, err := RenderEmailTemplate(spec.Default, spec.Sample); err != nil {
+			t.Errorf("Default %s template does not render with sample values: %v", key, err)
+		}
+	}
+}
+
+func TestRenderEmailTemplate(t *testing.T) {
+	tmpl := EmailTemplate{Subject: "Hi {{.name}}", Body: "Token: {{.token}}"}
+
+	rendered, err := RenderEmailTemplate(tmpl, map[string]string{"name": "Jane", "token": "abc"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rendered.Subject != "Hi Jane" || rendered.Body != "Token: abc" {
+		t.Errorf("Unexpected rendering %+v", rendered)
+	}
+
+	if _, err := RenderEmailTemplate(tmpl, map[string]string{"name": "Jane"}); err == nil {
+		t.Error("Expected an error for a missing variable")
+	}
+}
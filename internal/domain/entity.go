@@ -1,32 +1,111 @@
 package domain
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User represents a user in the system
 type User struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	Email     string    `json:"email" gorm:"uniqueIndex;not null"`
 	Password  string    `json:"-" gorm:"not null"`
 	Name      string    `json:"name" gorm:"not null"`
+	Role      string    `json:"role" gorm:"not null;default:user"`
+	Plan      string    `json:"plan" gorm:"not null;default:free"`
+	Slug      *string   `json:"slug,omitempty" gorm:"uniqueIndex"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletionScheduledAt is set when the user has requested account deletion
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" gorm:"index"`
+
+	// QuotaWarnedAt is set when the user was warned about approaching the product quota
+	QuotaWarnedAt *time.Time `json:"-"`
+	// QuotaExceededAt is set when the user first exceeded the product quota and starts the grace window
+	QuotaExceededAt *time.Time `json:"-"`
+
+	// EmailVerifiedAt is set once the user confirmed their email address
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// Preferences holds user-level settings; PreferencesSetAt is set when they were first saved
+	Preferences      UserPreferences `json:"preferences" gorm:"type:jsonb;not null;default:'{}'"`
+	PreferencesSetAt *time.Time      `json:"-"`
+
+	// ProductCodeSeq is the number of product codes handed out to the user
+	ProductCodeSeq int64 `json:"-" gorm:"not null;default:0"`
+
+	// Avatar is the version of the user's uploaded avatar, empty when there is none
+	Avatar string `json:"-" gorm:"not null;default:''"`
+}
+
+// AvatarURL returns the path the user's avatar is served from, versioned so a new
+// upload is never answered from a cache, or nil when the user has no avatar
+func (u *User) AvatarURL() *string {
+	if u.Avatar == "" {
+		return nil
+	}
+	url := "/api/v1/public/avatars/" + u.ID.String() + "?v=" + u.Avatar
+	return &url
+}
+
+// NormalizeEmail returns the form emails are stored and looked up in, so that
+// addresses differing only in case or surrounding spaces name the same account
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 // Product represents a product in the system
 type Product struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name        string    `json:"name" gorm:"not null"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price" gorm:"not null"`
-	Stock       int       `json:"stock" gorm:"not null;default:0"`
-	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	User        User      `json:"user" gorm:"foreignKey:UserID"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name            string    `json:"name" gorm:"not null"`
+	Description     string    `json:"description"`
+	DescriptionHTML string    `json:"description_html,omitempty" gorm:"column:description_html"`
+	Price           float64   `json:"price" gorm:"not null"`
+	// PriceFormatted is Price formatted for the user's locale and currency; it is
+	// not stored and only set in responses to ?price_format=locale
+	PriceFormatted string `json:"price_formatted,omitempty" gorm:"-"`
+	// StockValue is Price times Stock as computed by the database, in exact decimal
+	// text; it is not stored and only read by cursor queries, whose cursors hold it
+	StockValue string `json:"-" gorm:"->;-:migration"`
+	Stock      int    `json:"stock" gorm:"not null;default:0"`
+	// ReservedStock is the part of Stock held by reservations; it is only changed
+	// by the statements placing and ending reservations
+	ReservedStock int `json:"reserved_stock" gorm:"<-:false;not null;default:0"`
+	// Code is the product's short code, unique per user, set while the owner enables product codes
+	Code *string `json:"code,omitempty" gorm:"size:13"`
+	// LowStockThreshold and ReorderQuantity override the product's stock template when set
+	LowStockThreshold *int       `json:"low_stock_threshold,omitempty"`
+	ReorderQuantity   *int       `json:"reorder_quantity,omitempty"`
+	Attributes        Attributes `json:"attributes" gorm:"type:jsonb;not null;default:'{}'"`
+	Public            bool       `json:"public" gorm:"not null;default:false;index"`
+	// RatingAverage and RatingCount aggregate the product's published reviews; they
+	// are only changed by the statements that refresh them after reviews change
+	RatingAverage float64 `json:"rating_average" gorm:"<-:false;not null;default:0"`
+	RatingCount   int     `json:"rating_count" gorm:"<-:false;not null;default:0"`
+	// Status is ProductStatusDraft or ProductStatusPublished
+	Status string `json:"status" gorm:"size:20;not null;default:published;index"`
+	// StockTokensRevokedAt is when the product's stock tokens were last revoked; it
+	// is only changed by ProductRepository.RevokeStockTokens
+	StockTokensRevokedAt *time.Time `json:"-" gorm:"<-:false"`
+	// ArchivedAt is set while the product is archived: kept, but left out of lists,
+	// statistics and low-stock alerts unless asked for
+	ArchivedAt *time.Time `json:"archived_at,omitempty" gorm:"index"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	// User is the owner, loaded for server-side use; it is never encoded
+	User User `json:"-" gorm:"foreignKey:UserID"`
+	// Version is incremented by the database on every update, see ProductSync
+	Version   int64     `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // TableName specifies the table name for Product
@@ -34,7 +113,128 @@ func (Product) TableName() string {
 	return "products"
 }
 
+// AvailableStock returns the units of the product that are not reserved
+func (p Product) AvailableStock() int {
+	return p.Stock - p.ReservedStock
+}
+
+// IsListedPublicly reports whether the product is in its owner's public catalog:
+// public, published and not archived
+func (p Product) IsListedPublicly() bool {
+	return p.Public && p.Status == ProductStatusPublished && p.ArchivedAt == nil
+}
+
+// MarshalJSON encodes the product with its available stock
+func (p Product) MarshalJSON() ([]byte, error) {
+	type product Product
+	return json.Marshal(struct {
+		product
+		AvailableStock int `json:"available_stock"`
+	}{product(p), p.AvailableStock()})
+}
+
 // TableName specifies the table name for User
 func (User) TableName() string {
 	return "users"
-} 
\ No newline at end of file
+}
+
+// ExportDestination represents a user-owned S3 bucket that receives nightly exports
+type ExportDestination struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Endpoint         string     `json:"endpoint" gorm:"not null"`
+	Region           string     `json:"region" gorm:"not null"`
+	Bucket           string     `json:"bucket" gorm:"not null"`
+	Prefix           string     `json:"prefix"`
+	AccessKeyID      string     `json:"access_key_id" gorm:"not null"`
+	SecretAccessKey  string     `json:"-" gorm:"not null"` // encrypted at rest
+	UsePathStyle     bool       `json:"use_path_style"`
+	LastExportAt     *time.Time `json:"last_export_at,omitempty"`
+	LastExportStatus string     `json:"last_export_status,omitempty"`
+	LastExportError  string     `json:"last_export_error,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Notification represents a message surfaced to a user
+type Notification struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Type      string     `json:"type" gorm:"not null"`
+	Title     string     `json:"title" gorm:"not null"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for ExportDestination
+func (ExportDestination) TableName() string {
+	return "export_destinations"
+}
+
+// TableName specifies the table name for Notification
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// AuditLog represents a recorded API call
+type AuditLog struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid;index"`
+	Method       string     `json:"method" gorm:"not null"`
+	Route        string     `json:"route" gorm:"index"`
+	Path         string     `json:"path" gorm:"not null"`
+	Status       int        `json:"status" gorm:"not null"`
+	LatencyMs    int64      `json:"latency_ms" gorm:"not null"`
+	ClientIP     string     `json:"client_ip"`
+	RequestBody  string     `json:"request_body,omitempty"`
+	ResponseBody string     `json:"response_body,omitempty"`
+	// SampleRate is the fraction of similar calls recorded when this one was, so
+	// sampled categories can be extrapolated
+	SampleRate float64   `json:"sample_rate" gorm:"not null;default:1"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for AuditLog
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// Snapshot granularities
+const (
+	GranularityDaily   = "daily"
+	GranularityMonthly = "monthly"
+)
+
+// StatsSnapshot represents a point-in-time copy of a user's product statistics
+type StatsSnapshot struct {
+	ID            uuid.UUID `json:"-" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        uuid.UUID `json:"-" gorm:"type:uuid;not null;uniqueIndex:idx_stats_snapshots_user_date"`
+	Date          time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_stats_snapshots_user_date"`
+	Granularity   string    `json:"granularity" gorm:"not null;uniqueIndex:idx_stats_snapshots_user_date"`
+	TotalProducts int64     `json:"total_products" gorm:"not null"`
+	TotalValue    float64   `json:"total_value" gorm:"not null"`
+	AvgPrice      float64   `json:"avg_price" gorm:"not null"`
+	LowStock      int64     `json:"low_stock" gorm:"not null"`
+	OutOfStock    int64     `json:"out_of_stock" gorm:"not null"`
+	CreatedAt     time.Time `json:"-"`
+}
+
+// TableName specifies the table name for StatsSnapshot
+func (StatsSnapshot) TableName() string {
+	return "stats_snapshots"
+}
+
+// Favorite represents a product on a user's watchlist
+type Favorite struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey;index"`
+	User      User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Product   Product   `json:"-" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for Favorite
+func (Favorite) TableName() string {
+	return "favorites"
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -21,6 +22,47 @@ func TestProduct_TableName(t *testing.T) {
 	}
 }
 
+func TestProduct_MarshalJSONIncludesAvailableStock(t *testing.T) {
+	product := Product{ID: uuid.New(), Name: "Widget", Stock: 10, ReservedStock: 3}
+
+	data, err := json.Marshal(&product)
+	if err != nil {
+		t.Fatalf("Failed to marshal product: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal product: %v", err)
+	}
+	if decoded["name"] != "Widget" || decoded["stock"] != float64(10) || decoded["reserved_stock"] != float64(3) {
+		t.Errorf("Expected the product's fields, got %v", decoded)
+	}
+	if decoded["available_stock"] != float64(7) {
+		t.Errorf("Expected available_stock 7, got %v", decoded["available_stock"])
+	}
+}
+
+func TestProduct_MarshalJSONLeavesOutOwner(t *testing.T) {
+	owner := User{ID: uuid.New(), Email: "owner@example.com", Password: "$2a$10$hash", Plan: "pro"}
+	product := Product{ID: uuid.New(), Name: "Widget", UserID: owner.ID, User: owner}
+
+	data, err := json.Marshal(&product)
+	if err != nil {
+		t.Fatalf("Failed to marshal product: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal product: %v", err)
+	}
+	if _, ok := decoded["user"]; ok {
+		t.Errorf("Expected the owner left out, got %s", data)
+	}
+	if decoded["user_id"] != owner.ID.String() {
+		t.Errorf("Expected user_id %s, got %v", owner.ID, decoded["user_id"])
+	}
+}
+
 func TestUser_Creation(t *testing.T) {
 	user := &User{
 		ID:        uuid.New(),
@@ -65,3 +107,17 @@ func TestProduct_Creation(t *testing.T) {
 		t.Errorf("Expected user ID %s, got %s", userID, product.UserID)
 	}
 }
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := map[string]string{
+		"bob@x.com":      "bob@x.com",
+		"Bob@X.com":      "bob@x.com",
+		"  BOB@x.com \n": "bob@x.com",
+		"":               "",
+	}
+	for input, want := range tests {
+		if got := NormalizeEmail(input); got != want {
+			t.Errorf("NormalizeEmail(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Stable machine-readable error codes returned to API clients
+const (
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeInvalidInput         = "INVALID_INPUT"
+	CodeInvalidFilter        = "INVALID_FILTER"
+	CodeOperationFailed      = "OPERATION_FAILED"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeAuthenticationFailed = "AUTHENTICATION_FAILED"
+	CodeCaptchaRequired      = "CAPTCHA_REQUIRED"
+	CodeDeviceVerification   = "DEVICE_VERIFICATION_REQUIRED"
+	CodeSessionLimit         = "SESSION_LIMIT_REACHED"
+	CodeInvalidToken         = "INVALID_TOKEN"
+	CodeForbidden            = "FORBIDDEN"
+	CodeQuotaExceeded        = "QUOTA_EXCEEDED"
+	CodeNotFound             = "NOT_FOUND"
+	CodeProductNotFound      = "PRODUCT_NOT_FOUND"
+	CodeConflict             = "CONFLICT"
+	CodeOutOfStock           = "OUT_OF_STOCK"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeReadOnly             = "READ_ONLY"
+	CodeTimeout              = "TIMEOUT"
+	CodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	CodeUnavailable          = "SERVICE_UNAVAILABLE"
+	CodeInternal             = "INTERNAL_ERROR"
+	CodeInjectedFault        = "INJECTED_FAULT"
+)
+
+// Error kinds, matched with errors.Is regardless of the specific error code
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("forbidden")
+	ErrConflict  = errors.New("conflict")
+)
+
+// Product errors
+var (
+	ErrProductNotFound  = NotFoundError(CodeProductNotFound, "product not found")
+	ErrProductForbidden = ForbiddenError(CodeForbidden, "unauthorized access to product")
+	ErrInvalidCursor    = NewError(CodeInvalidFilter, "invalid cursor")
+)
+
+// User errors
+var (
+	ErrUserExists = errors.New("user already exists")
+)
+
+// Error is a typed error carrying a stable error code
+type Error struct {
+	Code     string
+	Message  string
+	Conflict *ConflictDetails
+	kind     error
+}
+
+// ConflictDetails carries the current state of a resource a change conflicted with,
+// so clients can resolve the conflict without fetching it again
+type ConflictDetails struct {
+	CurrentStock   *int      `json:"current_stock,omitempty"`
+	AvailableStock *int      `json:"available_stock,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewError creates a typed error
+func NewError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// NotFoundError creates a typed error that matches ErrNotFound
+func NotFoundError(code, message string) *Error {
+	return &Error{Code: code, Message: message, kind: ErrNotFound}
+}
+
+// ForbiddenError creates a typed error that matches ErrForbidden
+func ForbiddenError(code, message string) *Error {
+	return &Error{Code: code, Message: message, kind: ErrForbidden}
+}
+
+// ConflictError creates a typed error that matches ErrConflict
+func ConflictError(code, message string) *Error {
+	return &Error{Code: code, Message: message, kind: ErrConflict}
+}
+
+// StockConflictError creates a conflict error for a stock adjustment that would take
+// the stock below zero, reporting the current stock
+func StockConflictError(stock int, updatedAt time.Time) *Error {
+	return &Error{
+		Code:     CodeConflict,
+		Message:  fmt.Sprintf("stock cannot go below zero; current stock is %d", stock),
+		Conflict: &ConflictDetails{CurrentStock: &stock, UpdatedAt: updatedAt},
+		kind:     ErrConflict,
+	}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error kind so errors.Is matches ErrNotFound, ErrForbidden and ErrConflict
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// ErrorCode returns the code of the first typed error in err's chain, or an empty string
+func ErrorCode(err error) string {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed.Code
+	}
+	return ""
+}
+
+// FieldError describes why a single request field failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
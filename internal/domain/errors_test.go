@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	base := NewError(CodeInvalidFilter, "invalid filter")
+	wrapped := fmt.Errorf("%w: unknown attribute", base)
+
+	if got := ErrorCode(wrapped); got != CodeInvalidFilter {
+		t.Errorf("Expected code %s, got %s", CodeInvalidFilter, got)
+	}
+	if !errors.Is(wrapped, base) {
+		t.Error("Expected wrapped error to match its sentinel")
+	}
+	if got := ErrorCode(errors.New("plain")); got != "" {
+		t.Errorf("Expected no code for untyped error, got %s", got)
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	wrapped := fmt.Errorf("loading: %w", ErrProductNotFound)
+
+	if !errors.Is(wrapped, ErrNotFound) {
+		t.Error("Expected product not found to match ErrNotFound")
+	}
+	if errors.Is(wrapped, ErrForbidden) {
+		t.Error("Expected product not found not to match ErrForbidden")
+	}
+	if !errors.Is(ErrProductForbidden, ErrForbidden) || ErrorCode(ErrProductForbidden) != CodeForbidden {
+		t.Error("Expected product forbidden to match ErrForbidden with code FORBIDDEN")
+	}
+	if !errors.Is(ConflictError(CodeConflict, "stock cannot go below zero"), ErrConflict) {
+		t.Error("Expected conflict error to match ErrConflict")
+	}
+}
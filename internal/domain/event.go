@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain event types. Product events other than stock.adjusted and
+// user.login_anomaly are also the webhook event types; stock.adjusted records a
+// quick stock update, which is published to webhooks as product.stock_changed.
+const (
+	EventProductCreated      = WebhookProductCreated
+	EventProductUpdated      = WebhookProductUpdated
+	EventProductDeleted      = WebhookProductDeleted
+	EventProductStockChanged = WebhookProductStockChanged
+	EventProductPriceChanged = WebhookProductPriceChanged
+	EventStockAdjusted       = "stock.adjusted"
+	EventUserRegistered      = "user.registered"
+	EventUserLoginAnomaly    = WebhookUserLoginAnomaly
+)
+
+// EventTypes lists every recorded event type
+var EventTypes = []string{
+	EventProductCreated,
+	EventProductUpdated,
+	EventProductDeleted,
+	EventProductStockChanged,
+	EventProductPriceChanged,
+	EventStockAdjusted,
+	EventUserRegistered,
+	EventUserLoginAnomaly,
+}
+
+// Event query limits
+const (
+	DefaultEventPageSize = 100
+	MaxEventPageSize     = 500
+)
+
+// Event is an entry of the append-only domain event log. Sequence orders the
+// events in the order they were recorded; SubjectID is the product or user the
+// event is about.
+type Event struct {
+	Sequence   int64     `gorm:"primaryKey;autoIncrement;index:idx_events_user_sequence,priority:2"`
+	ID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	Type       string    `gorm:"not null;index"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index:idx_events_user_sequence,priority:1"`
+	SubjectID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	Payload    []byte    `gorm:"type:jsonb;not null"`
+	OccurredAt time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for Event
+func (Event) TableName() string {
+	return "events"
+}
+
+// ProductEventPayload is the payload of product events: the product after the
+// change and, for changes of an existing product, before it
+type ProductEventPayload struct {
+	Product  WebhookProduct  `json:"product"`
+	Previous *WebhookProduct `json:"previous,omitempty"`
+}
+
+// UserEventPayload is the payload of user events
+type UserEventPayload struct {
+	Role string `json:"role"`
+}
+
+// NewProductEvent creates an event of a change of product; previous is the
+// product before the change, if any
+func NewProductEvent(eventType string, product, previous *Product) (*Event, error) {
+	payload := ProductEventPayload{Product: NewWebhookProduct(product)}
+	if previous != nil {
+		snapshot := NewWebhookProduct(previous)
+		payload.Previous = &snapshot
+	}
+	return newEvent(eventType, product.UserID, product.ID, payload)
+}
+
+// NewUserEvent creates an event about a user
+func NewUserEvent(eventType string, user *User) (*Event, error) {
+	return newEvent(eventType, user.ID, user.ID, UserEventPayload{Role: user.Role})
+}
+
+// NewLoginAnomalyEvent creates an event of a login from a country the user never
+// logged in from before
+func NewLoginAnomalyEvent(login *LoginEvent) (*Event, error) {
+	return newEvent(EventUserLoginAnomaly, login.UserID, login.UserID, LoginAnomalyPayload{Login: *login})
+}
+
+// newEvent creates an event with an encoded payload
+func newEvent(eventType string, userID, subjectID uuid.UUID, payload interface{}) (*Event, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return &Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		UserID:     userID,
+		SubjectID:  subjectID,
+		Payload:    encoded,
+		OccurredAt: time.Now(),
+	}, nil
+}
+
+// WebhookEvent returns the webhook event published for a product or login
+// anomaly event, with its ID, and whether the event is published to webhooks at all
+func (e *Event) WebhookEvent() (WebhookEvent, bool, error) {
+	eventType := e.Type
+	if eventType == EventStockAdjusted {
+		eventType = WebhookProductStockChanged
+	}
+	if !WebhookEvents(WebhookEventTypes).Contains(eventType) {
+		return WebhookEvent{}, false, nil
+	}
+
+	if eventType == WebhookUserLoginAnomaly {
+		var payload LoginAnomalyPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return WebhookEvent{}, false, fmt.Errorf("invalid %s event payload: %w", e.Type, err)
+		}
+		return WebhookEvent{
+			ID:         e.ID,
+			Type:       eventType,
+			OccurredAt: e.OccurredAt,
+			UserID:     e.UserID,
+			Login:      &payload.Login,
+		}, true, nil
+	}
+
+	var payload ProductEventPayload
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return WebhookEvent{}, false, fmt.Errorf("invalid %s event payload: %w", e.Type, err)
+	}
+	return WebhookEvent{
+		ID:         e.ID,
+		Type:       eventType,
+		OccurredAt: e.OccurredAt,
+		UserID:     e.UserID,
+		Product:    &payload.Product,
+		Previous:   payload.Previous,
+	}, true, nil
+}
+
+// EventFilter represents filters for event queries. After is the sequence of
+// the last event already read.
+type EventFilter struct {
+	UserID    *uuid.UUID
+	Types     []string
+	SubjectID *uuid.UUID
+	From      *time.Time
+	To        *time.Time
+	After     int64
+	Limit     int
+}
+
+// ValidateEventTypes checks that every type is a recorded event type
+func ValidateEventTypes(types []string) error {
+	for _, eventType := range types {
+		if !slices.Contains(EventTypes, eventType) {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+// EventResponse is an event returned by the event query API
+type EventResponse struct {
+	Sequence   int64           `json:"sequence"`
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	UserID     uuid.UUID       `json:"user_id"`
+	SubjectID  uuid.UUID       `json:"subject_id"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// NewEventResponse maps an event to its API representation
+func NewEventResponse(event Event) EventResponse {
+	return EventResponse{
+		Sequence:   event.Sequence,
+		ID:         event.ID,
+		Type:       event.Type,
+		UserID:     event.UserID,
+		SubjectID:  event.SubjectID,
+		Payload:    json.RawMessage(event.Payload),
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+// EventListResponse is a page of events in sequence order. After is the
+// sequence to request the next page with; HasMore is set while events remain.
+type EventListResponse struct {
+	Events  []EventResponse `json:"events"`
+	After   int64           `json:"after"`
+	HasMore bool            `json:"has_more"`
+}
+
+// NewEventListResponse builds a page of events from up to limit+1 events read
+// after the given sequence
+func NewEventListResponse(after int64, limit int, events []Event) *EventListResponse {
+	response := &EventListResponse{Events: make([]EventResponse, 0, len(events)), After: after}
+	if len(events) > limit {
+		events = events[:limit]
+		response.HasMore = true
+	}
+
+	for _, event := range events {
+		response.Events = append(response.Events, NewEventResponse(event))
+		response.After = event.Sequence
+	}
+	return response
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEvent_WebhookEvent(t *testing.T) {
+	previous := &Product{ID: uuid.New(), UserID: uuid.New(), Name: "Mug", Stock: 5}
+	adjusted := *previous
+	adjusted.Stock = 3
+
+	event, err := NewProductEvent(EventStockAdjusted, &adjusted, previous)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if event.UserID != previous.UserID || event.SubjectID != previous.ID {
+		t.Errorf("Expected the event to belong to the product's owner and be about the product, got %+v", event)
+	}
+
+	webhookEvent, published, err := event.WebhookEvent()
+	if err != nil || !published {
+		t.Fatalf("Expected a stock adjustment to be published, got %v, %v", published, err)
+	}
+	if webhookEvent.ID != event.ID || webhookEvent.Type != WebhookProductStockChanged {
+		t.Errorf("Expected a %s webhook event with the event's ID, got %+v", WebhookProductStockChanged, webhookEvent)
+	}
+	if webhookEvent.Product.Stock != 3 || webhookEvent.Previous == nil || webhookEvent.Previous.Stock != 5 {
+		t.Errorf("Expected the product before and after the adjustment, got %+v", webhookEvent)
+	}
+}
+
+func TestEvent_WebhookEventUserEvent(t *testing.T) {
+	event, err := NewUserEvent(EventUserRegistered, &User{ID: uuid.New(), Role: RoleUser})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, published, err := event.WebhookEvent(); err != nil || published {
+		t.Errorf("Expected registrations not to be published to webhooks, got %v, %v", published, err)
+	}
+}
+
+func TestEvent_WebhookEventLoginAnomaly(t *testing.T) {
+	login := &LoginEvent{ID: uuid.New(), UserID: uuid.New(), IPAddress: "203.0.113.7", Country: "DE", NewCountry: true}
+	event, err := NewLoginAnomalyEvent(login)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	webhookEvent, published, err := event.WebhookEvent()
+	if err != nil || !published {
+		t.Fatalf("Expected a login anomaly to be published, got %v, %v", published, err)
+	}
+	if webhookEvent.UserID != login.UserID || webhookEvent.Product != nil || webhookEvent.Login == nil || webhookEvent.Login.Country != "DE" {
+		t.Errorf("Expected the login from the new country, got %+v", webhookEvent)
+	}
+	if !(WebhookFilter{Attributes: map[string]string{"category": "shoes"}}).Matches(webhookEvent) {
+		t.Error("Expected product filters not to apply to login anomalies")
+	}
+}
+
+func TestNewEventListResponse(t *testing.T) {
+	events := []Event{{Sequence: 11, Payload: []byte(`{}`)}, {Sequence: 14, Payload: []byte(`{}`)}, {Sequence: 15, Payload: []byte(`{}`)}}
+
+	page := NewEventListResponse(10, 2, events)
+	if !page.HasMore || page.After != 14 || len(page.Events) != 2 {
+		t.Errorf("Expected 2 events up to sequence 14 and more to come, got %+v", page)
+	}
+
+	last := NewEventListResponse(15, 2, nil)
+	if last.HasMore || last.After != 15 || last.Events == nil {
+		t.Errorf("Expected an empty last page keeping the sequence, got %+v", last)
+	}
+}
+
+func TestValidateEventTypes(t *testing.T) {
+	if err := ValidateEventTypes([]string{EventStockAdjusted, EventUserRegistered}); err != nil {
+		t.Errorf("Expected known types to be accepted, got %v", err)
+	}
+	if err := ValidateEventTypes([]string{"product.renamed"}); err == nil {
+		t.Error("Expected an unknown type to be rejected")
+	}
+}
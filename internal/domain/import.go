@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product fields that CSV columns can be mapped to; attributes use ImportAttributePrefix
+const (
+	ImportFieldName        = "name"
+	ImportFieldDescription = "description"
+	ImportFieldPrice       = "price"
+	ImportFieldStock       = "stock"
+	ImportAttributePrefix  = "attr."
+)
+
+// Transforms applied to a CSV value before it is assigned to its field
+const (
+	TransformPriceCents   = "price_cents"
+	TransformDecimalComma = "decimal_comma"
+	TransformLowercase    = "lowercase"
+	TransformUppercase    = "uppercase"
+)
+
+// MaxImportRows limits the number of data rows accepted in a single import
+const MaxImportRows = 10000
+
+// ColumnMapping maps a CSV column to a product field with an optional transform
+type ColumnMapping struct {
+	Column    string `json:"column" binding:"required"`
+	Field     string `json:"field" binding:"required"`
+	Transform string `json:"transform,omitempty"`
+}
+
+// ColumnMappings is a list of column mappings stored as JSONB
+type ColumnMappings []ColumnMapping
+
+// Value implements driver.Valuer
+func (m ColumnMappings) Value() (driver.Value, error) {
+	if m == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (m *ColumnMappings) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = ColumnMappings{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ColumnMappings", value)
+	}
+	return json.Unmarshal(data, m)
+}
+
+// GormDataType returns the column type used by GORM
+func (ColumnMappings) GormDataType() string {
+	return "jsonb"
+}
+
+// ImportProfile is a saved CSV column-to-field mapping for recurring imports
+type ImportProfile struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_import_profiles_user_name"`
+	Name      string         `json:"name" gorm:"not null;uniqueIndex:idx_import_profiles_user_name"`
+	Mappings  ColumnMappings `json:"mappings" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TableName specifies the table name for ImportProfile
+func (ImportProfile) TableName() string {
+	return "import_profiles"
+}
+
+// ImportProfileRequest represents the request for saving an import profile
+type ImportProfileRequest struct {
+	Name     string          `json:"name" binding:"required,max=100"`
+	Mappings []ColumnMapping `json:"mappings" binding:"required,min=1,dive"`
+}
+
+// ImportRow holds the product values read from one CSV row
+type ImportRow struct {
+	Name        string
+	Description string
+	Price       float64
+	Stock       int
+	Attributes  map[string]string
+}
+
+// ValidateColumnMappings checks that mappings target known fields once each, use
+// transforms suited to their field, and cover the fields a product requires
+func ValidateColumnMappings(mappings []ColumnMapping) error {
+	seen := make(map[string]bool, len(mappings))
+	for _, mapping := range mappings {
+		if strings.TrimSpace(mapping.Column) == "" {
+			return errors.New("column is required")
+		}
+
+		switch {
+		case mapping.Field == ImportFieldName, mapping.Field == ImportFieldDescription,
+			mapping.Field == ImportFieldPrice, mapping.Field == ImportFieldStock:
+		case strings.HasPrefix(mapping.Field, ImportAttributePrefix):
+			if err := ValidateAttributeKey(strings.TrimPrefix(mapping.Field, ImportAttributePrefix)); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown field %q", mapping.Field)
+		}
+
+		if seen[mapping.Field] {
+			return fmt.Errorf("field %q is mapped more than once", mapping.Field)
+		}
+		seen[mapping.Field] = true
+
+		switch mapping.Transform {
+		case "":
+		case TransformPriceCents, TransformDecimalComma:
+			if mapping.Field != ImportFieldPrice {
+				return fmt.Errorf("transform %q only applies to the price field", mapping.Transform)
+			}
+		case TransformLowercase, TransformUppercase:
+			if mapping.Field == ImportFieldPrice || mapping.Field == ImportFieldStock {
+				return fmt.Errorf("transform %q only applies to text fields", mapping.Transform)
+			}
+		default:
+			return fmt.Errorf("unknown transform %q", mapping.Transform)
+		}
+	}
+
+	for _, field := range []string{ImportFieldName, ImportFieldPrice} {
+		if !seen[field] {
+			return fmt.Errorf("field %q must be mapped", field)
+		}
+	}
+
+	return nil
+}
+
+// DefaultColumnMappings maps header columns named after product fields to those fields
+func DefaultColumnMappings(header []string) []ColumnMapping {
+	var mappings []ColumnMapping
+	for _, column := range header {
+		field := strings.ToLower(strings.TrimSpace(column))
+		switch {
+		case field == ImportFieldName, field == ImportFieldDescription,
+			field == ImportFieldPrice, field == ImportFieldStock,
+			strings.HasPrefix(field, ImportAttributePrefix):
+			mappings = append(mappings, ColumnMapping{Column: column, Field: field})
+		}
+	}
+	return mappings
+}
+
+// MapImportRow applies mappings to a CSV record; columns holds each header's index
+func MapImportRow(columns map[string]int, record []string, mappings []ColumnMapping) (*ImportRow, error) {
+	row := &ImportRow{Attributes: map[string]string{}}
+
+	for _, mapping := range mappings {
+		index, ok := columns[mapping.Column]
+		if !ok || index >= len(record) {
+			return nil, fmt.Errorf("missing column %q", mapping.Column)
+		}
+
+		value := strings.TrimSpace(record[index])
+		switch mapping.Transform {
+		case TransformLowercase:
+			value = strings.ToLower(value)
+		case TransformUppercase:
+			value = strings.ToUpper(value)
+		case TransformDecimalComma:
+			value = strings.ReplaceAll(strings.ReplaceAll(value, ".", ""), ",", ".")
+		}
+
+		switch {
+		case mapping.Field == ImportFieldName:
+			row.Name = value
+		case mapping.Field == ImportFieldDescription:
+			row.Description = value
+		case mapping.Field == ImportFieldPrice:
+			price, err := parseImportPrice(value, mapping.Transform)
+			if err != nil {
+				return nil, err
+			}
+			row.Price = price
+		case mapping.Field == ImportFieldStock:
+			if value == "" {
+				continue
+			}
+			stock, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("stock %q is not a whole number", value)
+			}
+			row.Stock = stock
+		default:
+			if value != "" {
+				row.Attributes[strings.TrimPrefix(mapping.Field, ImportAttributePrefix)] = value
+			}
+		}
+	}
+
+	if row.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if row.Price <= 0 {
+		return nil, errors.New("price must be greater than 0")
+	}
+	if row.Stock < 0 {
+		return nil, errors.New("stock cannot be negative")
+	}
+
+	return row, nil
+}
+
+// parseImportPrice parses a price, interpreting it as cents for the price_cents transform
+func parseImportPrice(value, transform string) (float64, error) {
+	if transform == TransformPriceCents {
+		cents, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("price %q is not a whole number of cents", value)
+		}
+		return CentsToPrice(cents), nil
+	}
+
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("price %q is not a number", value)
+	}
+	return price, nil
+}
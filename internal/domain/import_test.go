@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateColumnMappings(t *testing.T) {
+	base := []ColumnMapping{
+		{Column: "Article", Field: ImportFieldName},
+		{Column: "Cost", Field: ImportFieldPrice, Transform: TransformPriceCents},
+	}
+
+	tests := []struct {
+		name     string
+		mappings []ColumnMapping
+		wantErr  string
+	}{
+		{"valid", base, ""},
+		{"attribute", append(base, ColumnMapping{Column: "Colour", Field: "attr.color", Transform: TransformLowercase}), ""},
+		{"unknown field", append(base, ColumnMapping{Column: "SKU", Field: "sku"}), "unknown field"},
+		{"duplicate field", append(base, ColumnMapping{Column: "Title", Field: ImportFieldName}), "more than once"},
+		{"transform mismatch", append(base, ColumnMapping{Column: "Qty", Field: ImportFieldStock, Transform: TransformPriceCents}), "only applies to the price field"},
+		{"unknown transform", append(base, ColumnMapping{Column: "Qty", Field: ImportFieldStock, Transform: "double"}), "unknown transform"},
+		{"missing price", base[:1], `field "price" must be mapped`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateColumnMappings(tt.mappings)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMapImportRow(t *testing.T) {
+	columns := map[string]int{"Article": 0, "Cost": 1, "Qty": 2, "Colour": 3}
+	mappings := []ColumnMapping{
+		{Column: "Article", Field: ImportFieldName},
+		{Column: "Cost", Field: ImportFieldPrice, Transform: TransformPriceCents},
+		{Column: "Qty", Field: ImportFieldStock},
+		{Column: "Colour", Field: "attr.color", Transform: TransformLowercase},
+	}
+
+	row, err := MapImportRow(columns, []string{" Desk lamp ", "1999", "4", "RED"}, mappings)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if row.Name != "Desk lamp" || row.Price != 19.99 || row.Stock != 4 || row.Attributes["color"] != "red" {
+		t.Errorf("Unexpected row %+v", row)
+	}
+
+	if _, err := MapImportRow(columns, []string{"Desk lamp", "19.99", "4", "red"}, mappings); err == nil {
+		t.Error("Expected an error for a decimal price with the price_cents transform")
+	}
+	if _, err := MapImportRow(columns, []string{"", "1999", "4", "red"}, mappings); err == nil {
+		t.Error("Expected an error for a missing name")
+	}
+}
+
+func TestMapImportRow_DecimalComma(t *testing.T) {
+	columns := map[string]int{"Name": 0, "Preis": 1}
+	mappings := []ColumnMapping{
+		{Column: "Name", Field: ImportFieldName},
+		{Column: "Preis", Field: ImportFieldPrice, Transform: TransformDecimalComma},
+	}
+
+	row, err := MapImportRow(columns, []string{"Kaffeemaschine", "1.249,50"}, mappings)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if row.Price != 1249.50 {
+		t.Errorf("Expected price 1249.50, got %v", row.Price)
+	}
+}
+
+func TestDefaultColumnMappings(t *testing.T) {
+	mappings := DefaultColumnMappings([]string{"Name", "price", "SKU", "attr.color"})
+
+	if len(mappings) != 3 {
+		t.Fatalf("Expected 3 mappings, got %+v", mappings)
+	}
+	if mappings[0].Column != "Name" || mappings[0].Field != ImportFieldName {
+		t.Errorf("Unexpected mapping %+v", mappings[0])
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Location errors
+var (
+	ErrLocationNotFound = NotFoundError(CodeNotFound, "location not found")
+	ErrLocationInUse    = ConflictError(CodeConflict, "location still holds stock")
+)
+
+// Location is a warehouse or other place a user keeps stock in
+type Location struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	Name      string    `json:"name" gorm:"not null"`
+	Address   string    `json:"address,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Location
+func (Location) TableName() string {
+	return "locations"
+}
+
+// LocationRequest represents the request for creating or replacing a location
+type LocationRequest struct {
+	Name    string `json:"name" binding:"required,min=1,max=100" sanitize:"true"`
+	Address string `json:"address" binding:"max=500" sanitize:"multiline"`
+}
+
+// LocationStock is the stock of a product kept at one location. A product's stock
+// is the sum of its location stocks plus any stock not assigned to a location.
+type LocationStock struct {
+	ProductID  uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	LocationID uuid.UUID `json:"location_id" gorm:"type:uuid;primaryKey;index"`
+	Stock      int       `json:"stock" gorm:"not null;default:0"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Product  *Product  `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Location *Location `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for LocationStock
+func (LocationStock) TableName() string {
+	return "location_stocks"
+}
+
+// InsufficientLocationStockError creates a conflict error for taking more of a
+// product out of a location than is kept there
+func InsufficientLocationStockError(locationID uuid.UUID, stock int) *Error {
+	return &Error{
+		Code:     CodeOutOfStock,
+		Message:  fmt.Sprintf("insufficient stock at location %s; %d kept there", locationID, stock),
+		Conflict: &ConflictDetails{CurrentStock: &stock},
+		kind:     ErrConflict,
+	}
+}
+
+// LocationStockAdjustRequest represents the request for adjusting a product's stock
+// at a location
+type LocationStockAdjustRequest struct {
+	Delta int `json:"delta" binding:"required"`
+}
+
+// LocationStockLevel is a product's stock at one location
+type LocationStockLevel struct {
+	LocationID uuid.UUID `json:"location_id"`
+	Name       string    `json:"name"`
+	Stock      int       `json:"stock"`
+}
+
+// ProductLocationsResponse represents where a product's stock is kept. Unassigned
+// is the part of its stock not kept at any location, such as stock changed by
+// orders or adjustments made without a location.
+type ProductLocationsResponse struct {
+	ProductID  uuid.UUID            `json:"product_id"`
+	Stock      int                  `json:"stock"`
+	Unassigned int                  `json:"unassigned"`
+	Locations  []LocationStockLevel `json:"locations"`
+}
+
+// NewProductLocationsResponse creates the stock breakdown of a product from its
+// location stock levels
+func NewProductLocationsResponse(product *Product, levels []LocationStockLevel) *ProductLocationsResponse {
+	if levels == nil {
+		levels = []LocationStockLevel{}
+	}
+	located := 0
+	for _, level := range levels {
+		located += level.Stock
+	}
+	return &ProductLocationsResponse{
+		ProductID:  product.ID,
+		Stock:      product.Stock,
+		Unassigned: product.Stock - located,
+		Locations:  levels,
+	}
+}
+
+// LocationStockResponse represents a product's stock after a change at a location
+type LocationStockResponse struct {
+	ProductID      uuid.UUID `json:"product_id"`
+	LocationID     uuid.UUID `json:"location_id"`
+	LocationStock  int       `json:"location_stock"`
+	Stock          int       `json:"stock"`
+	ReservedStock  int       `json:"reserved_stock"`
+	AvailableStock int       `json:"available_stock"`
+}
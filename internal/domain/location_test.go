@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewProductLocationsResponse(t *testing.T) {
+	product := &Product{ID: uuid.New(), Stock: 30}
+	levels := []LocationStockLevel{
+		{LocationID: uuid.New(), Name: "North", Stock: 12},
+		{LocationID: uuid.New(), Name: "South", Stock: 8},
+	}
+
+	response := NewProductLocationsResponse(product, levels)
+	if response.ProductID != product.ID || response.Stock != 30 {
+		t.Errorf("Expected the product's total stock, got %+v", response)
+	}
+	if response.Unassigned != 10 {
+		t.Errorf("Expected 10 unassigned units, got %d", response.Unassigned)
+	}
+	if len(response.Locations) != 2 {
+		t.Errorf("Expected 2 locations, got %d", len(response.Locations))
+	}
+}
+
+func TestNewProductLocationsResponse_NoLocations(t *testing.T) {
+	response := NewProductLocationsResponse(&Product{ID: uuid.New(), Stock: 5}, nil)
+	if response.Locations == nil || len(response.Locations) != 0 {
+		t.Errorf("Expected an empty location list, got %v", response.Locations)
+	}
+	if response.Unassigned != 5 {
+		t.Errorf("Expected all 5 units unassigned, got %d", response.Unassigned)
+	}
+}
+
+func TestInsufficientLocationStockError(t *testing.T) {
+	err := InsufficientLocationStockError(uuid.New(), 3)
+	if err.Code != CodeOutOfStock || !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected an out-of-stock conflict, got %v", err)
+	}
+	if err.Conflict == nil || *err.Conflict.CurrentStock != 3 {
+		t.Errorf("Expected the location's stock in the conflict details, got %+v", err.Conflict)
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginDevice is a device a user has logged in from, recognized by its
+// fingerprint. Trusted devices may log in without confirming a code emailed to
+// the user when new device verification is required.
+type LoginDevice struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        uuid.UUID `json:"-" gorm:"type:uuid;not null;uniqueIndex:idx_login_devices_user_fingerprint,priority:1"`
+	Fingerprint   string    `json:"-" gorm:"not null;uniqueIndex:idx_login_devices_user_fingerprint,priority:2"`
+	Name          string    `json:"name" gorm:"not null"`
+	Trusted       bool      `json:"trusted" gorm:"not null;default:false"`
+	UserAgent     string    `json:"user_agent"`
+	LastIPAddress string    `json:"last_ip_address"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for LoginDevice
+func (LoginDevice) TableName() string {
+	return "login_devices"
+}
+
+// DeviceFingerprint identifies the device a login comes from by the device ID the
+// client sent or, without one, by its user agent. Only the hash is stored.
+func DeviceFingerprint(deviceID, userAgent string) string {
+	source := "ua:" + userAgent
+	if deviceID = strings.TrimSpace(deviceID); deviceID != "" {
+		source = "id:" + deviceID
+	}
+	hash := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(hash[:])
+}
+
+// DefaultDeviceName names a new device after its user agent
+func DefaultDeviceName(userAgent string) string {
+	const maxLength = 100
+	name := strings.TrimSpace(userAgent)
+	if name == "" {
+		return "Unknown device"
+	}
+	if runes := []rune(name); len(runes) > maxLength {
+		name = string(runes[:maxLength])
+	}
+	return name
+}
+
+// LoginDeviceResponse is a login device; Current marks the device of the
+// requesting session
+type LoginDeviceResponse struct {
+	LoginDevice
+	Current bool `json:"current"`
+}
+
+// LoginDeviceRequest renames a login device or changes whether it is trusted
+type LoginDeviceRequest struct {
+	Name    *string `json:"name" binding:"omitempty,min=1,max=100" sanitize:"true"`
+	Trusted *bool   `json:"trusted"`
+}
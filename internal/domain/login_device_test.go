@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeviceFingerprint(t *testing.T) {
+	userAgent := "Mozilla/5.0 (X11; Linux x86_64)"
+
+	if DeviceFingerprint("", userAgent) != DeviceFingerprint("  ", userAgent) {
+		t.Error("Expected a blank device ID to fall back to the user agent")
+	}
+	if DeviceFingerprint("phone-1", userAgent) != DeviceFingerprint("phone-1", "Other/1.0") {
+		t.Error("Expected the device ID to identify the device regardless of user agent")
+	}
+	if DeviceFingerprint("phone-1", userAgent) == DeviceFingerprint("phone-2", userAgent) {
+		t.Error("Expected different device IDs to be different devices")
+	}
+	if DeviceFingerprint(userAgent, "") == DeviceFingerprint("", userAgent) {
+		t.Error("Expected a device ID equal to a user agent not to collide with it")
+	}
+	if fingerprint := DeviceFingerprint("phone-1", ""); len(fingerprint) != 64 || strings.Contains(fingerprint, "phone") {
+		t.Errorf("Expected a hex SHA-256 fingerprint, got %q", fingerprint)
+	}
+}
+
+func TestDefaultDeviceName(t *testing.T) {
+	if name := DefaultDeviceName(" "); name != "Unknown device" {
+		t.Errorf("Expected unknown device, got %q", name)
+	}
+	if name := DefaultDeviceName(strings.Repeat("é", 150)); len([]rune(name)) != 100 {
+		t.Errorf("Expected name truncated to 100 characters, got %d", len([]rune(name)))
+	}
+}
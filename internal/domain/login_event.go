@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginEvent is a successful login in a user's login history. Country is the
+// ISO 3166-1 alpha-2 code the IP address was located in, empty when unknown;
+// NewCountry marks the first login from a country after logins from others.
+type LoginEvent struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID  `json:"-" gorm:"type:uuid;not null;index:idx_login_events_user_created,priority:1"`
+	DeviceID   *uuid.UUID `json:"device_id,omitempty" gorm:"type:uuid"`
+	IPAddress  string     `json:"ip_address" gorm:"not null"`
+	UserAgent  string     `json:"user_agent"`
+	Country    string     `json:"country,omitempty" gorm:"size:2"`
+	City       string     `json:"city,omitempty"`
+	NewCountry bool       `json:"new_country" gorm:"not null;default:false"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"not null;index:idx_login_events_user_created,priority:2"`
+}
+
+// TableName specifies the table name for LoginEvent
+func (LoginEvent) TableName() string {
+	return "login_events"
+}
+
+// GeoLocation is where an IP address was located
+type GeoLocation struct {
+	Country string `json:"country"`
+	City    string `json:"city,omitempty"`
+}
+
+// LoginHistoryResponse represents a page of a user's login history, most recent first
+type LoginHistoryResponse struct {
+	Logins     []LoginEvent `json:"logins"`
+	Total      int64        `json:"total"`
+	Page       int          `json:"page"`
+	PageSize   int          `json:"page_size"`
+	TotalPages int          `json:"total_pages"`
+}
+
+// LoginAnomalyPayload is the payload of user.login_anomaly events
+type LoginAnomalyPayload struct {
+	Login LoginEvent `json:"login"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Note errors
+var (
+	ErrNoteNotFound  = NotFoundError(CodeNotFound, "note not found")
+	ErrNoteNotAuthor = ForbiddenError(CodeForbidden, "only the author can change a note")
+)
+
+// mentionRegex matches a mention of a user by email address, e.g. @jane@example.com
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@])@([\w.%+-]+@[\w-]+(?:\.[\w-]+)*\.[A-Za-z]{2,})`)
+
+// ProductNote is an internal note in a product's thread, seen only by the users
+// who may work on the product and never in the public catalog
+type ProductNote struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID uuid.UUID  `json:"product_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Body      string     `json:"body" gorm:"not null"`
+	Mentions  StringList `json:"mentions" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// EditedAt is set once the note's body has been edited
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+
+	Author  *User    `json:"author,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Product *Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for ProductNote
+func (ProductNote) TableName() string {
+	return "product_notes"
+}
+
+// NoteRequest represents the request for writing or editing a product note
+type NoteRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=5000" sanitize:"multiline"`
+}
+
+// NoteListResponse represents a page of a product's notes, oldest first
+type NoteListResponse struct {
+	Notes      []ProductNote `json:"notes"`
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"page_size"`
+	TotalPages int           `json:"total_pages"`
+}
+
+// ParseMentions returns the lowercased email addresses mentioned in a note body
+// with @, once each and in order of appearance
+func ParseMentions(body string) StringList {
+	mentions := StringList{}
+	seen := map[string]bool{}
+	for _, match := range mentionRegex.FindAllStringSubmatch(body, -1) {
+		email := strings.ToLower(strings.TrimRight(match[1], "."))
+		if !seen[email] {
+			seen[email] = true
+			mentions = append(mentions, email)
+		}
+	}
+	return mentions
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	cases := map[string]StringList{
+		"no mentions here":                           {},
+		"@jane@example.com please recount":           {"jane@example.com"},
+		"cc @Jane@Example.com and @bob@shop.co.uk.":  {"jane@example.com", "bob@shop.co.uk"},
+		"@jane@example.com, again @jane@example.com": {"jane@example.com"},
+		"mail jane@example.com without a mention":    {},
+		"(@ops@example.com) restock":                 {"ops@example.com"},
+	}
+
+	for body, expected := range cases {
+		if mentions := ParseMentions(body); !reflect.DeepEqual(mentions, expected) {
+			t.Errorf("%q: expected %v, got %v", body, expected, mentions)
+		}
+	}
+}
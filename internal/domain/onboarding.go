@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Onboarding steps, in the order users are guided through them
+const (
+	OnboardingStepEmailVerified = "email_verified"
+	OnboardingStepFirstProduct  = "first_product"
+	OnboardingStepPreferences   = "preferences"
+)
+
+// UserPreferences holds user-level settings stored as JSONB
+type UserPreferences struct {
+	Currency          string `json:"currency,omitempty"`
+	Locale            string `json:"locale,omitempty"`
+	LowStockThreshold int    `json:"low_stock_threshold,omitempty"`
+	// ProductCodes gives the user's products short codes usable instead of their IDs
+	ProductCodes bool `json:"product_codes,omitempty"`
+	// DefaultPageSize is the page size of lists requested without page_size
+	DefaultPageSize int `json:"default_page_size,omitempty"`
+	// Notifications turns notification types on or off; types not listed are on
+	Notifications map[string]bool `json:"notifications,omitempty"`
+}
+
+// Value implements driver.Valuer
+func (p UserPreferences) Value() (driver.Value, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (p *UserPreferences) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = UserPreferences{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("cannot scan %T into UserPreferences", value)
+	}
+}
+
+// GormDataType returns the column type used by GORM
+func (UserPreferences) GormDataType() string {
+	return "jsonb"
+}
+
+// PreferencesRequest represents the request for updating user preferences
+type PreferencesRequest struct {
+	Currency          string `json:"currency" binding:"required,len=3,uppercase"`
+	Locale            string `json:"locale" binding:"omitempty,min=2,max=10"`
+	LowStockThreshold int    `json:"low_stock_threshold" binding:"gte=0"`
+	ProductCodes      bool   `json:"product_codes"`
+}
+
+// VerifyEmailRequest represents the request for confirming an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// OnboardingAction tells clients which request completes a step
+type OnboardingAction struct {
+	Method string `json:"method"`
+	Href   string `json:"href"`
+}
+
+// OnboardingStep represents one step of the guided setup
+type OnboardingStep struct {
+	Key         string            `json:"key"`
+	Title       string            `json:"title"`
+	Hint        string            `json:"hint"`
+	Completed   bool              `json:"completed"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+	Action      *OnboardingAction `json:"action,omitempty"`
+}
+
+// OnboardingResponse represents a user's onboarding state
+type OnboardingResponse struct {
+	Steps       []OnboardingStep `json:"steps"`
+	CurrentStep string           `json:"current_step,omitempty"`
+	Completed   bool             `json:"completed"`
+	Next        *OnboardingStep  `json:"next,omitempty"`
+}
+
+// OnboardingProgress records when each onboarding step was completed
+type OnboardingProgress struct {
+	EmailVerifiedAt  *time.Time
+	FirstProductAt   *time.Time
+	PreferencesSetAt *time.Time
+}
+
+// BuildOnboarding derives the onboarding state machine from a user's progress.
+// The current step is the first incomplete step, so steps completed early are kept.
+func BuildOnboarding(progress OnboardingProgress) OnboardingResponse {
+	steps := []OnboardingStep{
+		{
+			Key:         OnboardingStepEmailVerified,
+			Title:       "Verify your email address",
+			Hint:        "Request a verification email and submit the token it contains.",
+			CompletedAt: progress.EmailVerifiedAt,
+			Action:      &OnboardingAction{Method: "POST", Href: "/api/v1/users/me/verify-email"},
+		},
+		{
+			Key:         OnboardingStepFirstProduct,
+			Title:       "Create your first product",
+			Hint:        "Add a product with a name, price and stock level.",
+			CompletedAt: progress.FirstProductAt,
+			Action:      &OnboardingAction{Method: "POST", Href: "/api/v1/products"},
+		},
+		{
+			Key:         OnboardingStepPreferences,
+			Title:       "Set your preferences",
+			Hint:        "Choose your currency, locale and low stock threshold.",
+			CompletedAt: progress.PreferencesSetAt,
+			Action:      &OnboardingAction{Method: "PUT", Href: "/api/v1/users/me/preferences"},
+		},
+	}
+
+	response := OnboardingResponse{Steps: steps, Completed: true}
+	for i := range response.Steps {
+		step := &response.Steps[i]
+		step.Completed = step.CompletedAt != nil
+		if step.Completed {
+			step.Action = nil
+			continue
+		}
+		if response.Next == nil {
+			response.Next = step
+			response.CurrentStep = step.Key
+			response.Completed = false
+		}
+	}
+
+	return response
+}
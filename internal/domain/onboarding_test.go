@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildOnboarding_NewUser(t *testing.T) {
+	response := BuildOnboarding(OnboardingProgress{})
+
+	if response.Completed {
+		t.Error("Expected onboarding to be incomplete")
+	}
+	if response.CurrentStep != OnboardingStepEmailVerified {
+		t.Errorf("Expected current step %s, got %s", OnboardingStepEmailVerified, response.CurrentStep)
+	}
+	if response.Next == nil || response.Next.Action == nil {
+		t.Fatal("Expected a next step with an action")
+	}
+}
+
+func TestBuildOnboarding_OutOfOrder(t *testing.T) {
+	now := time.Now()
+	response := BuildOnboarding(OnboardingProgress{
+		EmailVerifiedAt:  &now,
+		PreferencesSetAt: &now,
+	})
+
+	if response.CurrentStep != OnboardingStepFirstProduct {
+		t.Errorf("Expected current step %s, got %s", OnboardingStepFirstProduct, response.CurrentStep)
+	}
+	if !response.Steps[2].Completed || response.Steps[2].Action != nil {
+		t.Error("Expected preferences step to be completed without an action")
+	}
+}
+
+func TestBuildOnboarding_Completed(t *testing.T) {
+	now := time.Now()
+	response := BuildOnboarding(OnboardingProgress{
+		EmailVerifiedAt:  &now,
+		FirstProductAt:   &now,
+		PreferencesSetAt: &now,
+	})
+
+	if !response.Completed || response.Next != nil || response.CurrentStep != "" {
+		t.Errorf("Expected completed onboarding, got %+v", response)
+	}
+}
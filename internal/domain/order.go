@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order statuses
+const (
+	OrderStatusPlaced    = "placed"
+	OrderStatusCancelled = "cancelled"
+)
+
+// Order errors
+var (
+	ErrOrderNotFound  = NotFoundError(CodeNotFound, "order not found")
+	ErrOrderCancelled = ConflictError(CodeConflict, "order is already cancelled")
+)
+
+// Order is a sale of a user's products. Placing it takes the ordered quantities from
+// stock and cancelling it puts them back.
+type Order struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      uuid.UUID   `json:"-" gorm:"type:uuid;not null;index:idx_orders_user_created,priority:1"`
+	Status      string      `json:"status" gorm:"size:20;not null;default:placed"`
+	Total       float64     `json:"total" gorm:"not null"`
+	Items       []OrderItem `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	CancelledAt *time.Time  `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"not null;index:idx_orders_user_created,priority:2"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// TableName specifies the table name for Order
+func (Order) TableName() string {
+	return "orders"
+}
+
+// OrderItem is a line item of an order. The product's name and price are copied at
+// the time of the order, so the item stays intact when the product changes or is
+// deleted.
+type OrderItem struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrderID     uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	ProductID   uuid.UUID `json:"product_id" gorm:"type:uuid;not null;index"`
+	ProductName string    `json:"product_name" gorm:"not null"`
+	UnitPrice   float64   `json:"unit_price" gorm:"not null"`
+	Quantity    int       `json:"quantity" gorm:"not null"`
+	Total       float64   `json:"total" gorm:"not null"`
+}
+
+// TableName specifies the table name for OrderItem
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+// NewOrderItem creates a line item for a quantity of a product at its current price
+func NewOrderItem(product Product, quantity int) OrderItem {
+	return OrderItem{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		UnitPrice:   product.Price,
+		Quantity:    quantity,
+		Total:       roundCents(product.Price * float64(quantity)),
+	}
+}
+
+// CalculateTotal sets the order's total to the sum of its items
+func (o *Order) CalculateTotal() {
+	var total float64
+	for _, item := range o.Items {
+		total += item.Total
+	}
+	o.Total = roundCents(total)
+}
+
+// roundCents rounds an amount to whole cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// InsufficientStockError creates a conflict error for taking or reserving more of a
+// product than is available, reporting its current and available stock
+func InsufficientStockError(product Product) *Error {
+	stock, available := product.Stock, product.AvailableStock()
+	return &Error{
+		Code:     CodeOutOfStock,
+		Message:  fmt.Sprintf("insufficient stock for product %s; %d available", product.ID, available),
+		Conflict: &ConflictDetails{CurrentStock: &stock, AvailableStock: &available, UpdatedAt: product.UpdatedAt},
+		kind:     ErrConflict,
+	}
+}
+
+// OrderItemRequest orders a quantity of a product
+type OrderItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1,max=1000000"`
+}
+
+// CreateOrderRequest represents the request for placing an order
+type CreateOrderRequest struct {
+	Items []OrderItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// ValidateOrderItems checks that every product of an order appears in one item only
+func ValidateOrderItems(items []OrderItemRequest) error {
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if seen[item.ProductID] {
+			return fmt.Errorf("product %s appears in more than one item", item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+	return nil
+}
+
+// OrderQuery filters and paginates a user's orders
+type OrderQuery struct {
+	Pagination
+	Status string
+}
+
+// OrderListResponse represents a page of a user's orders, newest first
+type OrderListResponse struct {
+	Orders     []Order `json:"orders"`
+	Total      int64   `json:"total"`
+	Page       int     `json:"page"`
+	PageSize   int     `json:"page_size"`
+	TotalPages int     `json:"total_pages"`
+}
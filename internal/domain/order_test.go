@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewOrderItem(t *testing.T) {
+	product := Product{ID: uuid.New(), Name: "Widget", Price: 19.99}
+
+	item := NewOrderItem(product, 3)
+	if item.ProductID != product.ID || item.ProductName != "Widget" || item.UnitPrice != 19.99 {
+		t.Errorf("Expected the item to copy the product, got %+v", item)
+	}
+	if item.Total != 59.97 {
+		t.Errorf("Expected a total of 59.97, got %v", item.Total)
+	}
+}
+
+func TestOrder_CalculateTotal(t *testing.T) {
+	order := Order{Items: []OrderItem{
+		NewOrderItem(Product{Price: 0.1}, 1),
+		NewOrderItem(Product{Price: 0.2}, 1),
+		NewOrderItem(Product{Price: 1.15}, 2),
+	}}
+
+	order.CalculateTotal()
+	if order.Total != 2.6 {
+		t.Errorf("Expected a total of 2.6, got %v", order.Total)
+	}
+}
+
+func TestValidateOrderItems(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	if err := ValidateOrderItems([]OrderItemRequest{{ProductID: first, Quantity: 1}, {ProductID: second, Quantity: 2}}); err != nil {
+		t.Errorf("Expected distinct products to be valid, got %v", err)
+	}
+	if err := ValidateOrderItems([]OrderItemRequest{{ProductID: first, Quantity: 1}, {ProductID: first, Quantity: 2}}); err == nil {
+		t.Error("Expected an error for a product in two items")
+	}
+}
+
+func TestInsufficientStockError(t *testing.T) {
+	err := InsufficientStockError(Product{ID: uuid.New(), Stock: 5, ReservedStock: 3, UpdatedAt: time.Now()})
+
+	if !errors.Is(err, ErrConflict) {
+		t.Error("Expected the error to match ErrConflict")
+	}
+	if err.Conflict == nil || *err.Conflict.CurrentStock != 5 || *err.Conflict.AvailableStock != 2 {
+		t.Errorf("Expected the current and available stock in the conflict details, got %+v", err.Conflict)
+	}
+}
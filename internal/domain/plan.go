@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Subscription plans
+const (
+	PlanFree       = "free"
+	PlanPro        = "pro"
+	PlanEnterprise = "enterprise"
+)
+
+// PlanKeys lists every plan, from the smallest to the largest
+var PlanKeys = []string{PlanFree, PlanPro, PlanEnterprise}
+
+// Plan is a subscription tier and the limits it grants. A limit of 0 is unlimited.
+type Plan struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// ProductLimit is the product quota
+	ProductLimit int64 `json:"product_limit"`
+	// RateLimit is the number of authenticated requests allowed per minute
+	RateLimit int64 `json:"rate_limit"`
+	// WebhookLimit is the number of webhook subscriptions allowed
+	WebhookLimit int64 `json:"webhook_limit"`
+}
+
+// PlanLimits overrides the limits of a plan
+type PlanLimits struct {
+	ProductLimit *int64 `json:"product_limit"`
+	RateLimit    *int64 `json:"rate_limit"`
+	WebhookLimit *int64 `json:"webhook_limit"`
+}
+
+// DefaultPlans returns the plans with their default limits
+func DefaultPlans() []Plan {
+	return []Plan{
+		{Key: PlanFree, Name: "Free", ProductLimit: 1000, RateLimit: 120, WebhookLimit: MaxWebhookSubscriptions},
+		{Key: PlanPro, Name: "Pro", ProductLimit: 10000, RateLimit: 600, WebhookLimit: 50},
+		{Key: PlanEnterprise, Name: "Enterprise", ProductLimit: 0, RateLimit: 0, WebhookLimit: 0},
+	}
+}
+
+// ApplyPlanLimits overrides the limits of plans with the JSON object data, which
+// maps plan keys to the limits to change
+func ApplyPlanLimits(plans []Plan, data []byte) error {
+	var overrides map[string]PlanLimits
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("invalid plan limits: %w", err)
+	}
+
+	for key, limits := range overrides {
+		i := slices.IndexFunc(plans, func(plan Plan) bool { return plan.Key == key })
+		if i < 0 {
+			return fmt.Errorf("unknown plan %q", key)
+		}
+		for _, limit := range []*int64{limits.ProductLimit, limits.RateLimit, limits.WebhookLimit} {
+			if limit != nil && *limit < 0 {
+				return fmt.Errorf("plan %q has a negative limit", key)
+			}
+		}
+
+		if limits.ProductLimit != nil {
+			plans[i].ProductLimit = *limits.ProductLimit
+		}
+		if limits.RateLimit != nil {
+			plans[i].RateLimit = *limits.RateLimit
+		}
+		if limits.WebhookLimit != nil {
+			plans[i].WebhookLimit = *limits.WebhookLimit
+		}
+	}
+	return nil
+}
+
+// UserPlanRequest changes a user's plan
+type UserPlanRequest struct {
+	Plan string `json:"plan" binding:"required,oneof=free pro enterprise"`
+}
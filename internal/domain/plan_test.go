@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestApplyPlanLimits(t *testing.T) {
+	plans := DefaultPlans()
+	if err := ApplyPlanLimits(plans, []byte(`{"pro": {"product_limit": 20000, "webhook_limit": 0}}`)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pro := plans[1]
+	if pro.ProductLimit != 20000 || pro.WebhookLimit != 0 || pro.RateLimit != DefaultPlans()[1].RateLimit {
+		t.Errorf("Expected only the given limits to change, got %+v", pro)
+	}
+	if plans[0] != DefaultPlans()[0] {
+		t.Errorf("Expected other plans to keep their limits, got %+v", plans[0])
+	}
+}
+
+func TestApplyPlanLimits_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown plan":   `{"platinum": {"product_limit": 10}}`,
+		"negative limit": `{"free": {"rate_limit": -1}}`,
+		"not an object":  `["free"]`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ApplyPlanLimits(DefaultPlans(), []byte(data)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purchase order statuses
+const (
+	PurchaseOrderStatusOpen      = "open"
+	PurchaseOrderStatusReceived  = "received"
+	PurchaseOrderStatusCancelled = "cancelled"
+)
+
+// Procurement errors
+var (
+	ErrSupplierNotFound      = NotFoundError(CodeNotFound, "supplier not found")
+	ErrSupplierInUse         = ConflictError(CodeConflict, "supplier has open purchase orders")
+	ErrPurchaseOrderNotFound = NotFoundError(CodeNotFound, "purchase order not found")
+	ErrPurchaseOrderClosed   = ConflictError(CodeConflict, "purchase order is already received or cancelled")
+)
+
+// Supplier is a business a user buys products from
+type Supplier struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	Name      string    `json:"name" gorm:"not null"`
+	Email     string    `json:"email,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Supplier
+func (Supplier) TableName() string {
+	return "suppliers"
+}
+
+// SupplierRequest represents the request for creating or replacing a supplier
+type SupplierRequest struct {
+	Name  string `json:"name" binding:"required,min=1,max=200" sanitize:"true"`
+	Email string `json:"email" binding:"omitempty,email_address" sanitize:"true"`
+	Phone string `json:"phone" binding:"max=50" sanitize:"true"`
+	Notes string `json:"notes" binding:"max=2000" sanitize:"multiline"`
+}
+
+// PurchaseOrder is an order of products from a supplier. Receiving it adds the
+// ordered quantities to stock.
+type PurchaseOrder struct {
+	ID         uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID           `json:"-" gorm:"type:uuid;not null;index:idx_purchase_orders_user_created,priority:1"`
+	SupplierID uuid.UUID           `json:"supplier_id" gorm:"type:uuid;not null;index"`
+	Status     string              `json:"status" gorm:"size:20;not null;default:open"`
+	Total      float64             `json:"total" gorm:"not null"`
+	Notes      string              `json:"notes,omitempty"`
+	Items      []PurchaseOrderItem `json:"items" gorm:"foreignKey:PurchaseOrderID;constraint:OnDelete:CASCADE"`
+	ReceivedAt *time.Time          `json:"received_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at" gorm:"not null;index:idx_purchase_orders_user_created,priority:2"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// TableName specifies the table name for PurchaseOrder
+func (PurchaseOrder) TableName() string {
+	return "purchase_orders"
+}
+
+// PurchaseOrderItem is a line item of a purchase order, at the supplier's unit cost
+type PurchaseOrderItem struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PurchaseOrderID uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	ProductID       uuid.UUID `json:"product_id" gorm:"type:uuid;not null;index"`
+	Quantity        int       `json:"quantity" gorm:"not null"`
+	UnitCost        float64   `json:"unit_cost" gorm:"not null"`
+	Total           float64   `json:"total" gorm:"not null"`
+}
+
+// TableName specifies the table name for PurchaseOrderItem
+func (PurchaseOrderItem) TableName() string {
+	return "purchase_order_items"
+}
+
+// NewPurchaseOrder creates an open purchase order from a request
+func NewPurchaseOrder(userID uuid.UUID, req PurchaseOrderRequest) *PurchaseOrder {
+	order := &PurchaseOrder{
+		UserID:     userID,
+		SupplierID: req.SupplierID,
+		Status:     PurchaseOrderStatusOpen,
+		Notes:      req.Notes,
+	}
+
+	var total float64
+	for _, item := range req.Items {
+		itemTotal := roundCents(item.UnitCost * float64(item.Quantity))
+		order.Items = append(order.Items, PurchaseOrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitCost:  item.UnitCost,
+			Total:     itemTotal,
+		})
+		total += itemTotal
+	}
+	order.Total = roundCents(total)
+
+	return order
+}
+
+// PurchaseOrderItemRequest orders a quantity of a product at a unit cost
+type PurchaseOrderItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1,max=1000000"`
+	UnitCost  float64   `json:"unit_cost" binding:"gte=0,lte=999999.99"`
+}
+
+// PurchaseOrderRequest represents the request for creating a purchase order
+type PurchaseOrderRequest struct {
+	SupplierID uuid.UUID                  `json:"supplier_id" binding:"required"`
+	Items      []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+	Notes      string                     `json:"notes" binding:"max=2000" sanitize:"multiline"`
+}
+
+// ValidatePurchaseOrderItems checks that every product of a purchase order appears
+// in one item only
+func ValidatePurchaseOrderItems(items []PurchaseOrderItemRequest) error {
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if seen[item.ProductID] {
+			return fmt.Errorf("product %s appears in more than one item", item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+	return nil
+}
+
+// PurchaseOrderQuery filters and paginates a user's purchase orders
+type PurchaseOrderQuery struct {
+	Pagination
+	Status     string
+	SupplierID *uuid.UUID
+}
+
+// PurchaseOrderListResponse represents a page of a user's purchase orders, newest first
+type PurchaseOrderListResponse struct {
+	PurchaseOrders []PurchaseOrder `json:"purchase_orders"`
+	Total          int64           `json:"total"`
+	Page           int             `json:"page"`
+	PageSize       int             `json:"page_size"`
+	TotalPages     int             `json:"total_pages"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewPurchaseOrder(t *testing.T) {
+	userID, supplierID := uuid.New(), uuid.New()
+	req := PurchaseOrderRequest{
+		SupplierID: supplierID,
+		Items: []PurchaseOrderItemRequest{
+			{ProductID: uuid.New(), Quantity: 3, UnitCost: 0.1},
+			{ProductID: uuid.New(), Quantity: 2, UnitCost: 1.15},
+		},
+		Notes: "Spring restock",
+	}
+
+	order := NewPurchaseOrder(userID, req)
+	if order.UserID != userID || order.SupplierID != supplierID || order.Notes != "Spring restock" {
+		t.Errorf("Expected the order to copy the request, got %+v", order)
+	}
+	if order.Status != PurchaseOrderStatusOpen {
+		t.Errorf("Expected an open order, got %q", order.Status)
+	}
+	if len(order.Items) != 2 || order.Items[0].Total != 0.3 || order.Items[1].Total != 2.3 {
+		t.Errorf("Expected item totals of 0.3 and 2.3, got %+v", order.Items)
+	}
+	if order.Total != 2.6 {
+		t.Errorf("Expected a total of 2.6, got %v", order.Total)
+	}
+}
+
+func TestValidatePurchaseOrderItems(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	if err := ValidatePurchaseOrderItems([]PurchaseOrderItemRequest{{ProductID: first, Quantity: 1}, {ProductID: second, Quantity: 2}}); err != nil {
+		t.Errorf("Expected distinct products to be valid, got %v", err)
+	}
+	if err := ValidatePurchaseOrderItems([]PurchaseOrderItemRequest{{ProductID: first, Quantity: 1}, {ProductID: first, Quantity: 2}}); err == nil {
+		t.Error("Expected a repeated product to be rejected")
+	}
+}
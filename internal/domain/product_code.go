@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// productCodeAlphabet is Crockford's base32 alphabet, which leaves out I, L, O and U
+// so that codes read aloud or copied by hand are not mistaken for one another
+const productCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// productCodeMinLength pads short codes so that they do not look like quantities
+const productCodeMinLength = 4
+
+// maxProductCodeLength is the length of the largest code, that of the largest int64
+const maxProductCodeLength = 13
+
+// EncodeProductCode returns the short code of the n-th product a user created
+// while product codes were enabled
+func EncodeProductCode(n int64) string {
+	var digits [maxProductCodeLength]byte
+	i := len(digits)
+	for n > 0 || len(digits)-i < productCodeMinLength {
+		i--
+		digits[i] = productCodeAlphabet[n%32]
+		n /= 32
+	}
+	return string(digits[i:])
+}
+
+// NormalizeProductCode returns the canonical form of a code typed by a person:
+// uppercase, without hyphens or spaces, with O read as 0 and I and L as 1
+func NormalizeProductCode(code string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		switch r {
+		case '-', ' ':
+			continue
+		case 'O':
+			r = '0'
+		case 'I', 'L':
+			r = '1'
+		}
+		if !strings.ContainsRune(productCodeAlphabet, r) {
+			return "", fmt.Errorf("invalid product code %q", code)
+		}
+		b.WriteRune(r)
+	}
+
+	normalized := b.String()
+	if len(normalized) < productCodeMinLength || len(normalized) > maxProductCodeLength {
+		return "", fmt.Errorf("invalid product code %q: use %d to %d characters", code, productCodeMinLength, maxProductCodeLength)
+	}
+	return normalized, nil
+}
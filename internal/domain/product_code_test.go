@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodeProductCode(t *testing.T) {
+	tests := map[int64]string{
+		1:             "0001",
+		31:            "000Z",
+		32:            "0010",
+		1 << 20:       "10000",
+		math.MaxInt64: "7ZZZZZZZZZZZZ",
+	}
+	for n, want := range tests {
+		if got := EncodeProductCode(n); got != want {
+			t.Errorf("EncodeProductCode(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestNormalizeProductCode(t *testing.T) {
+	tests := map[string]string{
+		"00a1":      "00A1",
+		"oo-il":     "0011",
+		" 7zzz-zzz": "7ZZZZZZ",
+	}
+	for code, want := range tests {
+		got, err := NormalizeProductCode(code)
+		if err != nil || got != want {
+			t.Errorf("NormalizeProductCode(%q) = %q, %v, want %q", code, got, err, want)
+		}
+	}
+
+	for _, code := range []string{"", "ABC", "00U1", "00_1", "12345678901234", "5f1b7c1e-2d0a-4c4b-9d3e-0c8a7f6b5e4d"} {
+		if _, err := NormalizeProductCode(code); err == nil {
+			t.Errorf("Expected %q to be rejected", code)
+		}
+	}
+}
+
+func TestNormalizeProductCode_RoundTrip(t *testing.T) {
+	for _, n := range []int64{1, 42, 1024, 99999, 1 << 40} {
+		code := EncodeProductCode(n)
+		if got, err := NormalizeProductCode(code); err != nil || got != code {
+			t.Errorf("Expected %q to normalize to itself, got %q, %v", code, got, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stock statuses products can be filtered by. A product is out of stock at zero,
+// low below its low-stock threshold, resolved like ResolveStockLevels, and in stock
+// otherwise. The stats endpoint counts the same thresholds, though its low_stock
+// count also includes products out of stock.
+const (
+	StockStatusInStock = "in_stock"
+	StockStatusLow     = "low"
+	StockStatusOut     = "out"
+)
+
+// StockStatuses lists the stock statuses, from most to least stock
+var StockStatuses = []string{StockStatusInStock, StockStatusLow, StockStatusOut}
+
+// StockStatusOf returns the stock status of a product's stock given its low-stock threshold
+func StockStatusOf(stock, lowStockThreshold int) string {
+	switch {
+	case stock <= 0:
+		return StockStatusOut
+	case stock < lowStockThreshold:
+		return StockStatusLow
+	default:
+		return StockStatusInStock
+	}
+}
+
+// PriceBucket is a named price range from Min up to, but not including, Max. The
+// last bucket has no Max.
+type PriceBucket struct {
+	Name string   `json:"name"`
+	Min  float64  `json:"min"`
+	Max  *float64 `json:"max,omitempty"`
+}
+
+// Contains reports whether a price falls in the bucket
+func (b PriceBucket) Contains(price float64) bool {
+	return price >= b.Min && (b.Max == nil || price < *b.Max)
+}
+
+var (
+	budgetMaxPrice = 20.0
+	midMaxPrice    = 100.0
+)
+
+// PriceBuckets are the price ranges products can be filtered by, cheapest first
+var PriceBuckets = []PriceBucket{
+	{Name: "budget", Min: 0, Max: &budgetMaxPrice},
+	{Name: "mid", Min: budgetMaxPrice, Max: &midMaxPrice},
+	{Name: "premium", Min: midMaxPrice},
+}
+
+// PriceBucketByName returns the price bucket with the given name
+func PriceBucketByName(name string) (PriceBucket, bool) {
+	for _, bucket := range PriceBuckets {
+		if bucket.Name == name {
+			return bucket, true
+		}
+	}
+	return PriceBucket{}, false
+}
+
+// FilterOptionsResponse describes the values of the enum filters of product lists
+type FilterOptionsResponse struct {
+	StockStatuses []string      `json:"stock_statuses"`
+	PriceBuckets  []PriceBucket `json:"price_buckets"`
+}
+
+// NewFilterOptionsResponse describes the stock statuses and price buckets
+func NewFilterOptionsResponse() *FilterOptionsResponse {
+	return &FilterOptionsResponse{StockStatuses: StockStatuses, PriceBuckets: PriceBuckets}
+}
+
+// ParseStockStatuses parses stock_status filter values, each a comma-separated
+// list of stock statuses, rejecting unknown statuses
+func ParseStockStatuses(values []string) ([]string, error) {
+	return parseFilterEnum(values, func(status string) bool {
+		return status == StockStatusInStock || status == StockStatusLow || status == StockStatusOut
+	}, "stock_status must be in_stock, low or out")
+}
+
+// ParsePriceBuckets parses price_bucket filter values, each a comma-separated list
+// of price bucket names, rejecting unknown buckets
+func ParsePriceBuckets(values []string) ([]string, error) {
+	names := make([]string, len(PriceBuckets))
+	for i, bucket := range PriceBuckets {
+		names[i] = bucket.Name
+	}
+	return parseFilterEnum(values, func(name string) bool {
+		_, ok := PriceBucketByName(name)
+		return ok
+	}, fmt.Sprintf("price_bucket must be one of %s", strings.Join(names, ", ")))
+}
+
+// parseFilterEnum splits comma-separated filter values, dropping blanks and
+// duplicates, and fails with message on the first value not valid
+func parseFilterEnum(values []string, valid func(string) bool, message string) ([]string, error) {
+	var parsed []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.ToLower(strings.TrimSpace(item))
+			if item == "" || seen[item] {
+				continue
+			}
+			if !valid(item) {
+				return nil, NewError(CodeInvalidFilter, message)
+			}
+			seen[item] = true
+			parsed = append(parsed, item)
+		}
+	}
+	return parsed, nil
+}
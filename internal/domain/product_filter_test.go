@@ -0,0 +1,39 @@
+package domain
+
+import "testing"
+
+func TestStockStatusOf(t *testing.T) {
+	tests := []struct {
+		stock, threshold int
+		expected         string
+	}{
+		{0, 10, StockStatusOut},
+		{0, 0, StockStatusOut},
+		{9, 10, StockStatusLow},
+		{10, 10, StockStatusInStock},
+		{1, 0, StockStatusInStock},
+	}
+	for _, tt := range tests {
+		if got := StockStatusOf(tt.stock, tt.threshold); got != tt.expected {
+			t.Errorf("StockStatusOf(%d, %d) = %s, expected %s", tt.stock, tt.threshold, got, tt.expected)
+		}
+	}
+}
+
+func TestParseFilterEnums(t *testing.T) {
+	statuses, err := ParseStockStatuses([]string{"low, OUT", "low"})
+	if err != nil || len(statuses) != 2 || statuses[0] != StockStatusLow || statuses[1] != StockStatusOut {
+		t.Errorf("Expected low and out, got %v, %v", statuses, err)
+	}
+	if _, err := ParseStockStatuses([]string{"low,backordered"}); ErrorCode(err) != CodeInvalidFilter {
+		t.Errorf("Expected an unknown status rejected, got %v", err)
+	}
+
+	buckets, err := ParsePriceBuckets([]string{"budget,premium"})
+	if err != nil || len(buckets) != 2 {
+		t.Errorf("Expected budget and premium, got %v, %v", buckets, err)
+	}
+	if _, err := ParsePriceBuckets([]string{"luxury"}); ErrorCode(err) != CodeInvalidFilter {
+		t.Errorf("Expected an unknown bucket rejected, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+// Product statuses. New products are published unless saved as drafts; drafts
+// skip the rules that apply on publishing. Archived is not stored in Status but
+// selects archived products in list filters, see Product.ArchivedAt.
+const (
+	ProductStatusDraft     = "draft"
+	ProductStatusPublished = "published"
+	ProductStatusArchived  = "archived"
+)
+
+// ValidatePublish checks that a product meets the rules that apply on publishing,
+// which creating a published product enforces through request validation: a price
+// and every required attribute. It returns a *ValidationError listing every unmet rule.
+func ValidatePublish(product *Product, definitions []AttributeDefinition) error {
+	var fields []FieldError
+	if product.Price <= 0 {
+		fields = append(fields, FieldError{Field: "price", Message: "price is required to publish"})
+	}
+	if err := ValidateAttributes(product.Attributes, definitions); err != nil {
+		fields = append(fields, FieldError{Field: "attributes", Message: err.Error()})
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Message: "product is not ready to publish", Fields: fields}
+	}
+	return nil
+}
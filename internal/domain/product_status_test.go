@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePublish(t *testing.T) {
+	definitions := []AttributeDefinition{
+		{Key: "color", Type: AttributeTypeString, Required: true},
+	}
+
+	ready := &Product{Price: 9.99, Attributes: Attributes{"color": "red"}}
+	if err := ValidatePublish(ready, definitions); err != nil {
+		t.Errorf("Expected a priced product with its required attributes to publish, got %v", err)
+	}
+
+	err := ValidatePublish(&Product{}, definitions)
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+	if len(validation.Fields) != 2 || validation.Fields[0].Field != "price" || validation.Fields[1].Field != "attributes" {
+		t.Errorf("Expected price and attributes to be reported, got %+v", validation.Fields)
+	}
+}
+
+func TestValidateDraftAttributes(t *testing.T) {
+	definitions := []AttributeDefinition{
+		{Key: "color", Type: AttributeTypeString, Required: true, AllowedValues: StringList{"red", "blue"}},
+	}
+
+	if err := ValidateDraftAttributes(Attributes{}, definitions); err != nil {
+		t.Errorf("Expected a draft to skip required attributes, got %v", err)
+	}
+	if err := ValidateDraftAttributes(Attributes{"color": "green"}, definitions); err == nil {
+		t.Error("Expected a draft attribute outside the allowed values to be rejected")
+	}
+}
@@ -2,11 +2,14 @@ package domain
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // ProductFilter represents filters for product queries
 type ProductFilter struct {
 	Name        *string    `json:"name" form:"name"`
+	Code        *string    `json:"code" form:"code"`
 	MinPrice    *float64   `json:"min_price" form:"min_price"`
 	MaxPrice    *float64   `json:"max_price" form:"max_price"`
 	MinStock    *int       `json:"min_stock" form:"min_stock"`
@@ -15,12 +18,31 @@ type ProductFilter struct {
 	CreatedTo   *time.Time `json:"created_to" form:"created_to"`
 	UpdatedFrom *time.Time `json:"updated_from" form:"updated_from"`
 	UpdatedTo   *time.Time `json:"updated_to" form:"updated_to"`
+
+	// Status selects products by status: draft, published or archived. Archived
+	// products are only listed with ProductStatusArchived.
+	Status string `json:"status,omitempty" form:"-"`
+
+	// LocationID matches products with stock recorded at the location
+	LocationID *uuid.UUID `json:"location_id,omitempty" form:"-"`
+
+	// Attributes matches products whose attributes contain all given key/value pairs
+	Attributes Attributes `json:"attributes,omitempty" form:"-"`
+
+	// StockStatuses matches products with any of the stock statuses, see StockStatusOf
+	StockStatuses []string `json:"stock_status,omitempty" form:"-"`
+
+	// PriceBuckets matches products priced in any of the named PriceBuckets
+	PriceBuckets []string `json:"price_bucket,omitempty" form:"-"`
 }
 
 // SortField represents a field to sort by
 type SortField struct {
 	Field     string `json:"field" form:"field"`
 	Direction string `json:"direction" form:"direction"` // "asc" or "desc"
+	// Nulls places the products without a value of a nullable field "first" or
+	// "last"; by default they sort as larger than any value, like in PostgreSQL
+	Nulls string `json:"nulls,omitempty" form:"nulls"`
 }
 
 // Pagination represents pagination parameters
@@ -35,11 +57,21 @@ type CursorPagination struct {
 	PageSize int     `json:"page_size" form:"page_size" binding:"min=1,max=100"`
 }
 
+// Total modes of paginated product lists
+const (
+	TotalExact    = "exact"
+	TotalNone     = "none"
+	TotalEstimate = "estimate"
+)
+
 // ProductQuery represents a complete product query with filters, sorting, and pagination
 type ProductQuery struct {
 	Filter     ProductFilter `json:"filter"`
 	Sort       []SortField   `json:"sort"`
 	Pagination Pagination    `json:"pagination"`
+	// Total selects whether the total is counted exactly (the default), skipped or
+	// estimated from planner statistics
+	Total string `json:"total,omitempty"`
 }
 
 // ProductQueryCursor represents a cursor-based product query
@@ -49,15 +81,17 @@ type ProductQueryCursor struct {
 	Pagination CursorPagination `json:"pagination"`
 }
 
-// ProductListResponse represents a paginated list of products
+// ProductListResponse represents a paginated list of products. Total and TotalPages
+// are only set when the total is counted exactly.
 type ProductListResponse struct {
-	Products   []Product `json:"products"`
-	Total      int64     `json:"total"`
-	Page       int       `json:"page"`
-	PageSize   int       `json:"page_size"`
-	TotalPages int       `json:"total_pages"`
-	HasNext    bool      `json:"has_next"`
-	HasPrev    bool      `json:"has_prev"`
+	Products      []Product `json:"products"`
+	Total         *int64    `json:"total,omitempty"`
+	TotalEstimate *int64    `json:"total_estimate,omitempty"`
+	Page          int       `json:"page"`
+	PageSize      int       `json:"page_size"`
+	TotalPages    *int      `json:"total_pages,omitempty"`
+	HasNext       bool      `json:"has_next"`
+	HasPrev       bool      `json:"has_prev"`
 }
 
 // ProductListCursorResponse represents a cursor-based list of products
@@ -88,9 +122,12 @@ type SessionInfo struct {
 	Email       string    `json:"email"`
 	CreatedAt   time.Time `json:"created_at"`
 	ExpiresAt   time.Time `json:"expires_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
 	IPAddress   string    `json:"ip_address"`
 	UserAgent   string    `json:"user_agent"`
+	DeviceID    string    `json:"device_id,omitempty"`
 	IsActive    bool      `json:"is_active"`
+	RememberMe  bool      `json:"remember_me"`
 }
 
 // UserSessionsResponse represents user sessions information
@@ -98,3 +135,42 @@ type UserSessionsResponse struct {
 	ActiveSessions []SessionInfo `json:"active_sessions"`
 	TotalSessions  int64         `json:"total_sessions"`
 }
+
+// AuditLogFilter represents filters for audit log queries
+type AuditLogFilter struct {
+	UserID *string    `json:"user_id" form:"user_id"`
+	Route  *string    `json:"route" form:"route"`
+	Status *int       `json:"status" form:"status"`
+	From   *time.Time `json:"from" form:"from"`
+	To     *time.Time `json:"to" form:"to"`
+}
+
+// AuditLogListResponse represents a paginated list of audit logs
+type AuditLogListResponse struct {
+	Logs       []AuditLog `json:"logs"`
+	Total      int64      `json:"total"`
+	Page       int        `json:"page"`
+	PageSize   int        `json:"page_size"`
+	TotalPages int        `json:"total_pages"`
+}
+
+// StatsHistoryResponse represents a user's product statistics over time
+type StatsHistoryResponse struct {
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Points []StatsSnapshot `json:"points"`
+}
+
+// Product name suggestion limits
+const (
+	MaxSuggestions = 10
+	// MinContainsSuggestionQuery is the shortest query matched anywhere in names;
+	// shorter queries, shorter than a trigram, only match the start of names
+	MinContainsSuggestionQuery = 3
+	MaxSuggestionQuery         = 100
+)
+
+// ProductSuggestionsResponse lists product names completing a search-as-you-type query
+type ProductSuggestionsResponse struct {
+	Suggestions []string `json:"suggestions"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Related products limits
+const (
+	DefaultRelatedLimit = 5
+	MaxRelatedLimit     = 20
+)
+
+// RelatedProduct is a product recommended alongside another, with the score it
+// was ranked by; higher scores are more relevant
+type RelatedProduct struct {
+	Product Product `json:"product"`
+	Score   float64 `json:"score"`
+}
+
+// RelatedProductsResponse represents the products related to a product, most
+// relevant first
+type RelatedProductsResponse struct {
+	ProductID uuid.UUID        `json:"product_id"`
+	Related   []RelatedProduct `json:"related"`
+}
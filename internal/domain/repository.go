@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -19,10 +20,49 @@ type Repository[T any] interface {
 type UserRepository interface {
 	Repository[User]
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetBySlug(ctx context.Context, slug string) (*User, error)
+	GetScheduledForDeletion(ctx context.Context, before time.Time) ([]User, error)
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	SetSlug(ctx context.Context, id uuid.UUID, slug string) error
+	SetPlan(ctx context.Context, id uuid.UUID, plan string) error
+	SetPreferences(ctx context.Context, id uuid.UUID, preferences UserPreferences) error
+	SetAvatar(ctx context.Context, id uuid.UUID, avatar string) error
+	SetQuotaState(ctx context.Context, id uuid.UUID, warnedAt, exceededAt *time.Time) error
+	SetDeletionSchedule(ctx context.Context, id uuid.UUID, scheduledAt *time.Time) error
+	DeleteAccount(ctx context.Context, id uuid.UUID) error
+	LoadAccountData(ctx context.Context, id uuid.UUID, export *AccountExport) error
 }
 
 // ProductRepository defines the interface for product-specific operations
 type ProductRepository interface {
 	Repository[Product]
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]Product, error)
-} 
\ No newline at end of file
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]Product, error)
+	GetPublicByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]Product, int64, error)
+	GetProductsWithFilters(ctx context.Context, userID uuid.UUID, query ProductQuery) (*ProductListResponse, error)
+	GetProductsWithCursor(ctx context.Context, userID uuid.UUID, query ProductQueryCursor) (*ProductListCursorResponse, error)
+	StreamProductsWithFilters(ctx context.Context, userID uuid.UUID, query ProductQuery, yield func(*Product) error) error
+	GetLowStock(ctx context.Context, userID uuid.UUID, locationID *uuid.UUID, limit int) ([]Product, error)
+	GetRelated(ctx context.Context, product *Product, limit int) ([]RelatedProduct, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]Product, error)
+	GetIDByCode(ctx context.Context, userID uuid.UUID, code string) (*uuid.UUID, error)
+	SuggestNames(ctx context.Context, userID uuid.UUID, query string, limit int) ([]string, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	FirstCreatedAt(ctx context.Context, userID uuid.UUID) (*time.Time, error)
+	GetProductStats(ctx context.Context, userID uuid.UUID) (*ProductStats, error)
+	GetStatsSeries(ctx context.Context, userID uuid.UUID, metric, interval string, from, to time.Time) (map[time.Time]float64, error)
+	SetPublic(ctx context.Context, id uuid.UUID, public bool) error
+	SetStatus(ctx context.Context, id uuid.UUID, status string) error
+	SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error
+	RevokeStockTokens(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+	GetStockTokensRevokedAt(ctx context.Context, id uuid.UUID) (*time.Time, error)
+	UpsertForUser(ctx context.Context, userID uuid.UUID, products []Product) error
+	UpdateColumns(ctx context.Context, product *Product, columns []string) error
+	AdjustStock(ctx context.Context, id uuid.UUID, delta int) (int, error)
+	DecrementStock(ctx context.Context, id uuid.UUID, quantity int) (*Product, error)
+	Reserve(ctx context.Context, id uuid.UUID, quantity int) error
+	ReleaseReserved(ctx context.Context, id uuid.UUID, quantity int) error
+	ConsumeReserved(ctx context.Context, id uuid.UUID, quantity int) (int, error)
+	ReserveProductCodes(ctx context.Context, userID uuid.UUID, count int) (int64, error)
+	AssignMissingCodes(ctx context.Context, userID uuid.UUID) (int, error)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reservation lifetimes
+const (
+	DefaultReservationTTL = 15 * time.Minute
+	MaxReservationTTL     = 24 * time.Hour
+)
+
+// ErrReservationNotFound is returned for reservations that do not exist, have
+// expired or have already been confirmed or released
+var ErrReservationNotFound = NotFoundError(CodeNotFound, "reservation not found or expired")
+
+// StockReservation is a time-limited hold on units of a product. Held units count
+// towards the product's reserved stock until the reservation is confirmed, which
+// takes them out of stock, or released or expired, which makes them available again.
+type StockReservation struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+	UserID    uuid.UUID `json:"-"`
+	Quantity  int       `json:"quantity"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Hold encodes what the reservation holds, so that it can be released after its
+// details have expired
+func (r StockReservation) Hold() string {
+	return fmt.Sprintf("%s:%s:%d", r.ID, r.ProductID, r.Quantity)
+}
+
+// ParseReservationHold decodes a hold into a reservation with its ID, product and quantity
+func ParseReservationHold(hold string) (StockReservation, error) {
+	parts := strings.Split(hold, ":")
+	if len(parts) != 3 {
+		return StockReservation{}, fmt.Errorf("invalid reservation hold %q", hold)
+	}
+
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return StockReservation{}, fmt.Errorf("invalid reservation hold %q: %w", hold, err)
+	}
+	productID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return StockReservation{}, fmt.Errorf("invalid reservation hold %q: %w", hold, err)
+	}
+	quantity, err := strconv.Atoi(parts[2])
+	if err != nil || quantity <= 0 {
+		return StockReservation{}, fmt.Errorf("invalid reservation hold %q", hold)
+	}
+
+	return StockReservation{ID: id, ProductID: productID, Quantity: quantity}, nil
+}
+
+// StockReservationRequest represents the request for reserving units of a product
+// for TTLSeconds, DefaultReservationTTL when omitted
+type StockReservationRequest struct {
+	Quantity   int `json:"quantity" binding:"required,min=1,max=1000000"`
+	TTLSeconds int `json:"ttl_seconds" binding:"omitempty,min=1,max=86400"`
+}
+
+// TTL returns how long the requested reservation holds its units
+func (r StockReservationRequest) TTL() time.Duration {
+	if r.TTLSeconds == 0 {
+		return DefaultReservationTTL
+	}
+	return time.Duration(r.TTLSeconds) * time.Second
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStockReservation_HoldRoundTrip(t *testing.T) {
+	reservation := StockReservation{ID: uuid.New(), ProductID: uuid.New(), Quantity: 4}
+
+	parsed, err := ParseReservationHold(reservation.Hold())
+	if err != nil {
+		t.Fatalf("Expected the hold to parse, got %v", err)
+	}
+	if parsed.ID != reservation.ID || parsed.ProductID != reservation.ProductID || parsed.Quantity != 4 {
+		t.Errorf("Expected %+v, got %+v", reservation, parsed)
+	}
+}
+
+func TestParseReservationHold_Invalid(t *testing.T) {
+	id := uuid.New().String()
+	tests := map[string]string{
+		"missing quantity": id + ":" + id,
+		"invalid id":       "x:" + id + ":1",
+		"invalid product":  id + ":x:1",
+		"zero quantity":    id + ":" + id + ":0",
+	}
+
+	for name, hold := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseReservationHold(hold); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestStockReservationRequest_TTL(t *testing.T) {
+	if ttl := (StockReservationRequest{Quantity: 1}).TTL(); ttl != DefaultReservationTTL {
+		t.Errorf("Expected the default TTL, got %s", ttl)
+	}
+	if ttl := (StockReservationRequest{Quantity: 1, TTLSeconds: 90}).TTL(); ttl != 90*time.Second {
+		t.Errorf("Expected 90s, got %s", ttl)
+	}
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review statuses. Reviews are published when created; the product's owner
+// moderates them by hiding them, which leaves them out of the public listing and
+// the product's rating.
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusHidden    = "hidden"
+)
+
+// Review errors
+var (
+	ErrReviewNotFound   = NotFoundError(CodeNotFound, "review not found")
+	ErrReviewExists     = ConflictError(CodeConflict, "you have already reviewed this product")
+	ErrOwnProductReview = ForbiddenError(CodeForbidden, "you cannot review your own product")
+)
+
+// Review is a user's rating of a public product, one per user and product
+type Review struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;not null;uniqueIndex:idx_reviews_product_user"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_reviews_product_user;index"`
+	Rating    int       `json:"rating" gorm:"not null"`
+	Comment   string    `json:"comment"`
+	Status    string    `json:"status" gorm:"size:20;not null;default:published"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Product *Product `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name for Review
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// ReviewRequest represents the request for reviewing a product
+type ReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=2000" sanitize:"multiline"`
+}
+
+// ReviewModerationRequest represents the request for publishing or hiding a review
+type ReviewModerationRequest struct {
+	Status string `json:"status" binding:"required,oneof=published hidden"`
+}
+
+// ReviewQuery filters and paginates a product's reviews
+type ReviewQuery struct {
+	Pagination
+	Status string
+}
+
+// ReviewListResponse represents a page of a product's reviews, newest first
+type ReviewListResponse struct {
+	Reviews    []Review `json:"reviews"`
+	Total      int64    `json:"total"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"page_size"`
+	TotalPages int      `json:"total_pages"`
+}
+
+// PublicReviewResponse represents a published review in the public catalog
+type PublicReviewResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicReviewListResponse represents a page of a public product's published
+// reviews, newest first
+type PublicReviewListResponse struct {
+	ProductID     uuid.UUID              `json:"product_id"`
+	RatingAverage float64                `json:"rating_average"`
+	RatingCount   int                    `json:"rating_count"`
+	Reviews       []PublicReviewResponse `json:"reviews"`
+	Total         int64                  `json:"total"`
+	Page          int                    `json:"page"`
+	PageSize      int                    `json:"page_size"`
+	TotalPages    int                    `json:"total_pages"`
+}
+
+// NewPublicReviewListResponse creates the public listing of a page of a product's
+// published reviews
+func NewPublicReviewListResponse(product *Product, page *ReviewListResponse) *PublicReviewListResponse {
+	response := &PublicReviewListResponse{
+		ProductID:     product.ID,
+		RatingAverage: product.RatingAverage,
+		RatingCount:   product.RatingCount,
+		Reviews:       make([]PublicReviewResponse, 0, len(page.Reviews)),
+		Total:         page.Total,
+		Page:          page.Page,
+		PageSize:      page.PageSize,
+		TotalPages:    page.TotalPages,
+	}
+	for _, review := range page.Reviews {
+		response.Reviews = append(response.Reviews, PublicReviewResponse{
+			ID:        review.ID,
+			Rating:    review.Rating,
+			Comment:   review.Comment,
+			CreatedAt: review.CreatedAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewPublicReviewListResponse(t *testing.T) {
+	product := &Product{ID: uuid.New(), RatingAverage: 4.5, RatingCount: 2}
+	page := &ReviewListResponse{
+		Reviews: []Review{
+			{ID: uuid.New(), UserID: uuid.New(), Rating: 5, Comment: "Great", Status: ReviewStatusPublished},
+			{ID: uuid.New(), UserID: uuid.New(), Rating: 4, Status: ReviewStatusPublished},
+		},
+		Total:      2,
+		Page:       1,
+		PageSize:   20,
+		TotalPages: 1,
+	}
+
+	response := NewPublicReviewListResponse(product, page)
+	if response.ProductID != product.ID || response.RatingAverage != 4.5 || response.RatingCount != 2 {
+		t.Errorf("Expected the product's rating, got %+v", response)
+	}
+	if len(response.Reviews) != 2 || response.Reviews[0].Rating != 5 || response.Reviews[0].Comment != "Great" {
+		t.Errorf("Expected both reviews, got %+v", response.Reviews)
+	}
+	if response.Total != 2 || response.TotalPages != 1 {
+		t.Errorf("Expected the page totals to be kept, got %+v", response)
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Saved search errors
+var (
+	ErrSavedSearchNotFound = NotFoundError(CodeNotFound, "saved search not found")
+	ErrSavedSearchExists   = ConflictError(CodeConflict, "a saved search with this name already exists")
+)
+
+// SavedSearch is a product query a user saved under a name. Subscribed searches
+// notify their user of products created since the search was last checked that
+// match it.
+type SavedSearch struct {
+	ID         uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID    `json:"-" gorm:"type:uuid;not null;uniqueIndex:idx_saved_searches_user_name"`
+	Name       string       `json:"name" gorm:"not null;uniqueIndex:idx_saved_searches_user_name"`
+	Query      ProductQuery `json:"query" gorm:"not null"`
+	Subscribed bool         `json:"subscribed" gorm:"not null;default:false;index"`
+	// CheckedAt is when the subscribed search was last checked for new matches;
+	// products created since are new
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for SavedSearch
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// SavedSearchRequest represents the request for saving or replacing a search
+type SavedSearchRequest struct {
+	Name       string       `json:"name" binding:"required,min=1,max=100" sanitize:"true"`
+	Query      ProductQuery `json:"query" binding:"-"`
+	Subscribed bool         `json:"subscribed"`
+}
+
+// SavedSearchSubscriptionRequest represents the request for subscribing to or
+// unsubscribing from a saved search
+type SavedSearchSubscriptionRequest struct {
+	Subscribed *bool `json:"subscribed" binding:"required"`
+}
+
+// NormalizeSavedQuery checks the filters of a query to save and fills in its
+// default pagination, as the filtered product list does for query parameters
+func NormalizeSavedQuery(query *ProductQuery) error {
+	switch query.Filter.Status {
+	case "", ProductStatusDraft, ProductStatusPublished, ProductStatusArchived:
+	default:
+		return NewError(CodeInvalidFilter, "status must be draft, published or archived")
+	}
+	switch query.Total {
+	case "", TotalExact, TotalNone, TotalEstimate:
+	default:
+		return NewError(CodeInvalidFilter, "total must be exact, none or estimate")
+	}
+	stockStatuses, err := ParseStockStatuses(query.Filter.StockStatuses)
+	if err != nil {
+		return err
+	}
+	priceBuckets, err := ParsePriceBuckets(query.Filter.PriceBuckets)
+	if err != nil {
+		return err
+	}
+	query.Filter.StockStatuses, query.Filter.PriceBuckets = stockStatuses, priceBuckets
+
+	if query.Pagination.Page < 1 {
+		query.Pagination.Page = 1
+	}
+	if query.Pagination.PageSize < 1 || query.Pagination.PageSize > 100 {
+		query.Pagination.PageSize = 20
+	}
+	if query.Sort == nil {
+		query.Sort = []SortField{}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, storing saved queries as JSONB
+func (q ProductQuery) Value() (driver.Value, error) {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (q *ProductQuery) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*q = ProductQuery{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ProductQuery", value)
+	}
+	return json.Unmarshal(data, q)
+}
+
+// GormDataType returns the column type used by GORM
+func (ProductQuery) GormDataType() string {
+	return "jsonb"
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeSavedQuery(t *testing.T) {
+	query := ProductQuery{Pagination: Pagination{Page: 0, PageSize: 500}}
+	if err := NormalizeSavedQuery(&query); err != nil {
+		t.Fatalf("Expected an empty query to be valid, got %v", err)
+	}
+	if query.Pagination.Page != 1 || query.Pagination.PageSize != 20 || query.Sort == nil {
+		t.Errorf("Expected the default pagination and sort, got %+v", query)
+	}
+
+	if err := NormalizeSavedQuery(&ProductQuery{Filter: ProductFilter{Status: "deleted"}}); err == nil {
+		t.Error("Expected an unknown status to be rejected")
+	}
+	if err := NormalizeSavedQuery(&ProductQuery{Total: "approximate"}); err == nil {
+		t.Error("Expected an unknown total mode to be rejected")
+	}
+}
+
+func TestProductQuery_ValueScan(t *testing.T) {
+	name := "lamp"
+	minPrice := 10.5
+	query := ProductQuery{
+		Filter:     ProductFilter{Name: &name, MinPrice: &minPrice, Status: ProductStatusDraft},
+		Sort:       []SortField{{Field: "price", Direction: "desc"}},
+		Pagination: Pagination{Page: 2, PageSize: 50},
+	}
+
+	value, err := query.Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	var scanned ProductQuery
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if !reflect.DeepEqual(scanned, query) {
+		t.Errorf("Expected %+v after a round trip, got %+v", query, scanned)
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ErrSessionNotFound is returned by session stores for unknown or expired sessions
+var ErrSessionNotFound = NotFoundError(CodeNotFound, "session not found")
+
+// Session represents a user session
+type Session struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"not null;index"`
+	Email     string    `json:"email" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	DeviceID  string    `json:"device_id,omitempty"`
+	IsActive  bool      `json:"is_active" gorm:"not null;default:true"`
+	// TTL is how long the session lasts after login, a token refresh or, with
+	// sliding expiration, its last activity; longer for remember-me logins
+	TTL        time.Duration `json:"ttl"`
+	RememberMe bool          `json:"remember_me,omitempty" gorm:"not null;default:false"`
+	// LastActiveAt is when the session last made an authenticated request, to
+	// within the activity throttling interval
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// TableName specifies the table name for Session
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// Info converts the session to its API representation
+func (s *Session) Info() SessionInfo {
+	lastActiveAt := s.LastActiveAt
+	if lastActiveAt.IsZero() {
+		lastActiveAt = s.CreatedAt
+	}
+
+	return SessionInfo{
+		SessionID:    s.ID,
+		UserID:       s.UserID,
+		Email:        s.Email,
+		CreatedAt:    s.CreatedAt,
+		ExpiresAt:    s.ExpiresAt,
+		LastActiveAt: lastActiveAt,
+		IPAddress:    s.IPAddress,
+		UserAgent:    s.UserAgent,
+		DeviceID:     s.DeviceID,
+		IsActive:     s.IsActive,
+		RememberMe:   s.RememberMe,
+	}
+}
+
+// SessionRevocation marks a token or session as revoked until it would have
+// expired anyway
+type SessionRevocation struct {
+	Key       string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for SessionRevocation
+func (SessionRevocation) TableName() string {
+	return "session_revocations"
+}
+
+// SessionActivity is an authenticated request made with a session
+type SessionActivity struct {
+	At time.Time
+	// Interval throttles writes: no activity is recorded within Interval of the last
+	Interval time.Duration
+	// Slide moves the expiry of the session to its TTL after At, but never
+	// earlier than it was nor later than MaxLifetime after its creation
+	Slide       bool
+	MaxLifetime time.Duration
+}
+
+// ExpiresAt returns the expiry of session after the activity
+func (a SessionActivity) ExpiresAt(session *Session) time.Time {
+	if !a.Slide || session.TTL <= 0 {
+		return session.ExpiresAt
+	}
+	expiresAt := a.At.Add(session.TTL)
+	if a.MaxLifetime > 0 {
+		if limit := session.CreatedAt.Add(a.MaxLifetime); expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+	if expiresAt.Before(session.ExpiresAt) {
+		return session.ExpiresAt
+	}
+	return expiresAt
+}
+
+// SessionStore keeps sessions and revocations where every API instance sees
+// them, so that any instance can validate a session created by another
+type SessionStore interface {
+	// Save creates or replaces a session, kept until it expires
+	Save(ctx context.Context, session *Session) error
+	// Get returns a session, or ErrSessionNotFound
+	Get(ctx context.Context, id string) (*Session, error)
+	Delete(ctx context.Context, id string) error
+	// ListByUser returns the user's sessions, including expired ones not yet removed
+	ListByUser(ctx context.Context, userID string) ([]Session, error)
+	DeleteByUser(ctx context.Context, userID string) error
+	// TouchActivity records an activity of a session, unless one was already
+	// recorded less than its interval before by any instance
+	TouchActivity(ctx context.Context, id string, activity SessionActivity) error
+
+	// Revoke records key as revoked for ttl
+	Revoke(ctx context.Context, key string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, key string) (bool, error)
+}
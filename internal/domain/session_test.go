@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionActivity_ExpiresAt(t *testing.T) {
+	created := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	session := &Session{CreatedAt: created, ExpiresAt: created.Add(24 * time.Hour), TTL: 24 * time.Hour}
+
+	tests := []struct {
+		name     string
+		activity SessionActivity
+		want     time.Time
+	}{
+		{"not sliding", SessionActivity{At: created.Add(20 * time.Hour)}, created.Add(24 * time.Hour)},
+		{"extended", SessionActivity{At: created.Add(20 * time.Hour), Slide: true, MaxLifetime: 7 * 24 * time.Hour}, created.Add(44 * time.Hour)},
+		{"capped at the maximum lifetime", SessionActivity{At: created.Add(150 * time.Hour), Slide: true, MaxLifetime: 7 * 24 * time.Hour}, created.Add(7 * 24 * time.Hour)},
+		{"without maximum", SessionActivity{At: created.Add(400 * time.Hour), Slide: true}, created.Add(424 * time.Hour)},
+		{"never shortened", SessionActivity{At: created.Add(time.Hour), Slide: true, MaxLifetime: 2 * time.Hour}, created.Add(24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.activity.ExpiresAt(session); !got.Equal(tt.want) {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
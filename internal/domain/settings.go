@@ -0,0 +1,44 @@
+package domain
+
+// List page sizes
+const (
+	DefaultListPageSize = 20
+	MaxListPageSize     = 100
+)
+
+// UserSettingsRequest represents the request for replacing all of a user's
+// settings, as exported by GET /users/me/settings
+type UserSettingsRequest struct {
+	Currency          string          `json:"currency" binding:"required,len=3,uppercase"`
+	Locale            string          `json:"locale" binding:"omitempty,min=2,max=10"`
+	LowStockThreshold int             `json:"low_stock_threshold" binding:"gte=0"`
+	ProductCodes      bool            `json:"product_codes"`
+	DefaultPageSize   int             `json:"default_page_size" binding:"omitempty,min=1,max=100"`
+	Notifications     map[string]bool `json:"notifications"`
+}
+
+// NewUserSettings creates the preferences stored for a settings request
+func NewUserSettings(req *UserSettingsRequest) UserPreferences {
+	return UserPreferences{
+		Currency:          req.Currency,
+		Locale:            req.Locale,
+		LowStockThreshold: req.LowStockThreshold,
+		ProductCodes:      req.ProductCodes,
+		DefaultPageSize:   req.DefaultPageSize,
+		Notifications:     req.Notifications,
+	}
+}
+
+// PageSize returns the page size of lists requested without page_size
+func (p UserPreferences) PageSize() int {
+	if p.DefaultPageSize > 0 && p.DefaultPageSize <= MaxListPageSize {
+		return p.DefaultPageSize
+	}
+	return DefaultListPageSize
+}
+
+// NotificationEnabled reports whether the user receives notifications of a type
+func (p UserPreferences) NotificationEnabled(notificationType string) bool {
+	enabled, ok := p.Notifications[notificationType]
+	return !ok || enabled
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestUserPreferences_PageSize(t *testing.T) {
+	cases := map[int]int{0: DefaultListPageSize, 50: 50, 500: DefaultListPageSize}
+	for stored, expected := range cases {
+		if size := (UserPreferences{DefaultPageSize: stored}).PageSize(); size != expected {
+			t.Errorf("default_page_size %d: expected %d, got %d", stored, expected, size)
+		}
+	}
+}
+
+func TestUserPreferences_NotificationEnabled(t *testing.T) {
+	preferences := UserPreferences{Notifications: map[string]bool{"export_failed": true, "note_mention": false}}
+
+	if !preferences.NotificationEnabled("export_failed") {
+		t.Error("Expected an enabled type to be on")
+	}
+	if preferences.NotificationEnabled("note_mention") {
+		t.Error("Expected a disabled type to be off")
+	}
+	if !preferences.NotificationEnabled("saved_search_match") {
+		t.Error("Expected an unlisted type to be on")
+	}
+}
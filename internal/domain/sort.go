@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sort directions and null orderings
+const (
+	SortAsc        = "asc"
+	SortDesc       = "desc"
+	SortNullsFirst = "first"
+	SortNullsLast  = "last"
+)
+
+// MaxSortFields is the most fields a product list can be sorted by
+const MaxSortFields = 5
+
+// Types of sort field values
+const (
+	SortTypeString    = "string"
+	SortTypeNumber    = "number"
+	SortTypeInteger   = "integer"
+	SortTypeTimestamp = "timestamp"
+)
+
+// SortableField describes a field products can be sorted by. Computed fields
+// are derived from other columns by the database.
+type SortableField struct {
+	Field       string `json:"field"`
+	Type        string `json:"type"`
+	Nullable    bool   `json:"nullable"`
+	Computed    bool   `json:"computed"`
+	Description string `json:"description"`
+}
+
+// ProductSortFields is the allowlist of fields products can be sorted by
+var ProductSortFields = []SortableField{
+	{Field: "name", Type: SortTypeString, Description: "Product name"},
+	{Field: "price", Type: SortTypeNumber, Description: "Unit price"},
+	{Field: "stock", Type: SortTypeInteger, Description: "Units in stock"},
+	{Field: "stock_value", Type: SortTypeNumber, Computed: true, Description: "Value of the stock: price times stock"},
+	{Field: "code", Type: SortTypeString, Nullable: true, Description: "Short product code, unset while codes are disabled"},
+	{Field: "created_at", Type: SortTypeTimestamp, Description: "Creation time"},
+	{Field: "updated_at", Type: SortTypeTimestamp, Description: "Time of the last update"},
+}
+
+// DefaultProductSort is the order of product lists that are not sorted
+var DefaultProductSort = SortField{Field: "created_at", Direction: SortDesc}
+
+// ProductSortField returns the description of a sortable field
+func ProductSortField(field string) (SortableField, bool) {
+	for _, sortable := range ProductSortFields {
+		if sortable.Field == field {
+			return sortable, true
+		}
+	}
+	return SortableField{}, false
+}
+
+// SortFieldsResponse describes how product lists can be sorted
+type SortFieldsResponse struct {
+	Fields    []SortableField `json:"fields"`
+	Default   string          `json:"default"`
+	MaxFields int             `json:"max_fields"`
+}
+
+// NewSortFieldsResponse describes the sortable product fields
+func NewSortFieldsResponse() *SortFieldsResponse {
+	return &SortFieldsResponse{
+		Fields:    ProductSortFields,
+		Default:   DefaultProductSort.Field + ":" + DefaultProductSort.Direction,
+		MaxFields: MaxSortFields,
+	}
+}
+
+// ParseSortFields parses sort parameters of the form field[:asc|desc[:nulls_first|nulls_last]],
+// e.g. "price:desc" or "code:asc:nulls_last", rejecting unknown fields
+func ParseSortFields(params []string) ([]SortField, error) {
+	if len(params) > MaxSortFields {
+		return nil, fmt.Errorf("at most %d sort fields are allowed", MaxSortFields)
+	}
+
+	fields := make([]SortField, 0, len(params))
+	seen := make(map[string]bool, len(params))
+	for _, param := range params {
+		parts := strings.Split(param, ":")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid sort %q: expected field[:direction[:nulls]]", param)
+		}
+
+		field := SortField{Field: strings.TrimSpace(parts[0]), Direction: SortAsc}
+		if _, ok := ProductSortField(field.Field); !ok {
+			return nil, fmt.Errorf("cannot sort by %q; see GET /api/v1/products/sort-fields", field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("cannot sort by %q twice", field.Field)
+		}
+		seen[field.Field] = true
+
+		if len(parts) > 1 {
+			field.Direction = strings.ToLower(parts[1])
+			if field.Direction != SortAsc && field.Direction != SortDesc {
+				return nil, fmt.Errorf("invalid sort direction %q: use asc or desc", parts[1])
+			}
+		}
+		if len(parts) > 2 {
+			switch strings.ToLower(parts[2]) {
+			case "nulls_first":
+				field.Nulls = SortNullsFirst
+			case "nulls_last":
+				field.Nulls = SortNullsLast
+			default:
+				return nil, fmt.Errorf("invalid null ordering %q: use nulls_first or nulls_last", parts[2])
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
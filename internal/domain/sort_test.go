@@ -0,0 +1,45 @@
+package domain
+
+import "testing"
+
+func TestParseSortFields(t *testing.T) {
+	fields, err := ParseSortFields([]string{"stock_value:DESC", "code:asc:nulls_first", "name"})
+	if err != nil {
+		t.Fatalf("ParseSortFields failed: %v", err)
+	}
+	expected := []SortField{
+		{Field: "stock_value", Direction: SortDesc},
+		{Field: "code", Direction: SortAsc, Nulls: SortNullsFirst},
+		{Field: "name", Direction: SortAsc},
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got %+v", len(expected), fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("Expected field %d to be %+v, got %+v", i, expected[i], fields[i])
+		}
+	}
+}
+
+func TestParseSortFields_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []string
+	}{
+		{"unknown field", []string{"password"}},
+		{"duplicate field", []string{"name", "name:desc"}},
+		{"invalid direction", []string{"name:up"}},
+		{"invalid null ordering", []string{"code:asc:nulls_middle"}},
+		{"too many parts", []string{"code:asc:nulls_last:x"}},
+		{"too many fields", []string{"name", "price", "stock", "code", "created_at", "updated_at"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSortFields(tt.params); err == nil {
+				t.Errorf("Expected %v to be rejected", tt.params)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+// ProductStats represents statistics of a user's products that are not archived
+type ProductStats struct {
+	TotalProducts int64   `json:"total_products"`
+	TotalValue    float64 `json:"total_value"`
+	AvgPrice      float64 `json:"avg_price"`
+	MedianPrice   float64 `json:"median_price"`
+	LowStock      int64   `json:"low_stock"`
+	OutOfStock    int64   `json:"out_of_stock"`
+	// StockValuePercentiles are percentiles of the stock value, price times stock,
+	// of single products
+	StockValuePercentiles StockValuePercentiles `json:"stock_value_percentiles"`
+}
+
+// StockValuePercentiles holds percentiles of a distribution of stock values
+type StockValuePercentiles struct {
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+}
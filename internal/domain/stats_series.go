@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Time-series metrics of a user's products, by the period products were created in
+const (
+	// StatsMetricCount counts the products created in each bucket
+	StatsMetricCount = "count"
+	// StatsMetricValue sums the stock value of the products created in each bucket
+	StatsMetricValue = "value"
+)
+
+// Time-series bucket intervals; weeks start on Monday, as with Postgres date_trunc
+const (
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+)
+
+// MaxStatsSeriesBuckets bounds the number of buckets of a time series
+const MaxStatsSeriesBuckets = 400
+
+// StatsSeriesPoint is the value of a metric in the bucket starting at Bucket
+type StatsSeriesPoint struct {
+	Bucket time.Time `json:"bucket"`
+	Value  float64   `json:"value"`
+}
+
+// StatsSeriesResponse represents a metric of a user's products over time, with a
+// point for every bucket between From and To, oldest first
+type StatsSeriesResponse struct {
+	Metric   string             `json:"metric"`
+	Interval string             `json:"interval"`
+	From     time.Time          `json:"from"`
+	To       time.Time          `json:"to"`
+	Points   []StatsSeriesPoint `json:"points"`
+}
+
+// TruncateToBucket returns the start, in UTC, of the bucket of the given interval
+// that t falls in
+func TruncateToBucket(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == StatsIntervalWeek {
+		// Monday is the first day of the week
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+// StatsBuckets returns the starts of the buckets of the given interval covering
+// from to to, oldest first
+func StatsBuckets(metric, interval string, from, to time.Time) ([]time.Time, error) {
+	if metric != StatsMetricCount && metric != StatsMetricValue {
+		return nil, NewError(CodeBadRequest, "metric must be count or value")
+	}
+	if interval != StatsIntervalDay && interval != StatsIntervalWeek {
+		return nil, NewError(CodeBadRequest, "interval must be day or week")
+	}
+	if to.Before(from) {
+		return nil, NewError(CodeBadRequest, "'from' must not be after 'to'")
+	}
+
+	step := 1
+	if interval == StatsIntervalWeek {
+		step = 7
+	}
+	var buckets []time.Time
+	for bucket := TruncateToBucket(from, interval); !bucket.After(to); bucket = bucket.AddDate(0, 0, step) {
+		if len(buckets) == MaxStatsSeriesBuckets {
+			return nil, NewError(CodeBadRequest, fmt.Sprintf("requested range spans more than %d buckets", MaxStatsSeriesBuckets))
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTruncateToBucket(t *testing.T) {
+	// Thursday
+	at := time.Date(2026, time.October, 15, 13, 45, 0, 0, time.UTC)
+
+	if day := TruncateToBucket(at, StatsIntervalDay); !day.Equal(time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the start of the day, got %v", day)
+	}
+	if week := TruncateToBucket(at, StatsIntervalWeek); !week.Equal(time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the Monday of the week, got %v", week)
+	}
+	sunday := time.Date(2026, time.October, 18, 23, 0, 0, 0, time.UTC)
+	if week := TruncateToBucket(sunday, StatsIntervalWeek); !week.Equal(time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected Sunday to belong to the week starting Monday, got %v", week)
+	}
+}
+
+func TestStatsBuckets(t *testing.T) {
+	from := time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.October, 7, 8, 0, 0, 0, time.UTC)
+
+	days, err := StatsBuckets(StatsMetricCount, StatsIntervalDay, from, to)
+	if err != nil || len(days) != 7 {
+		t.Fatalf("Expected 7 daily buckets, got %d (%v)", len(days), err)
+	}
+
+	weeks, err := StatsBuckets(StatsMetricValue, StatsIntervalWeek, from, to)
+	if err != nil || len(weeks) != 2 || weeks[0].Weekday() != time.Monday {
+		t.Fatalf("Expected 2 weekly buckets starting Monday, got %v (%v)", weeks, err)
+	}
+
+	if _, err := StatsBuckets("median", StatsIntervalDay, from, to); err == nil {
+		t.Error("Expected an unknown metric to be rejected")
+	}
+	if _, err := StatsBuckets(StatsMetricCount, "month", from, to); err == nil {
+		t.Error("Expected an unknown interval to be rejected")
+	}
+	if _, err := StatsBuckets(StatsMetricCount, StatsIntervalDay, to, from); err == nil {
+		t.Error("Expected a reversed range to be rejected")
+	}
+	if _, err := StatsBuckets(StatsMetricCount, StatsIntervalDay, from, from.AddDate(2, 0, 0)); err == nil {
+		t.Error("Expected too many buckets to be rejected")
+	}
+}
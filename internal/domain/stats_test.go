@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProductStats_JSONKeys(t *testing.T) {
+	data, err := json.Marshal(ProductStats{})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	// Clients depend on these keys; renaming one is a breaking change
+	for _, key := range []string{"total_products", "total_value", "avg_price", "median_price", "low_stock", "out_of_stock", "stock_value_percentiles"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("Expected key %q in %s", key, data)
+		}
+	}
+	percentiles := decoded["stock_value_percentiles"].(map[string]interface{})
+	for _, key := range []string{"p25", "p50", "p75", "p90"} {
+		if _, ok := percentiles[key]; !ok {
+			t.Errorf("Expected percentile %q in %s", key, data)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Service statuses of the public status page
+const (
+	ServiceOperational = "operational"
+	ServiceDegraded    = "degraded"
+	ServiceOutage      = "outage"
+)
+
+// Dependency health on the public status page
+const (
+	DependencyUp       = "up"
+	DependencyDegraded = "degraded"
+	DependencyDown     = "down"
+)
+
+// StatusResponse is the data of the public status page. Uptime is the share of
+// the minutes of UptimeWindow in which every required dependency was up, and the
+// latency averages the requests of LatencyWindow; both only cover the instance
+// answering, since it started.
+type StatusResponse struct {
+	Status           string             `json:"status"`
+	UptimePercent    float64            `json:"uptime_percent"`
+	UptimeWindow     string             `json:"uptime_window"`
+	AverageLatencyMs float64            `json:"average_latency_ms"`
+	LatencyWindow    string             `json:"latency_window"`
+	Requests         int64              `json:"requests"`
+	ErrorRate        float64            `json:"error_rate"`
+	Dependencies     []DependencyHealth `json:"dependencies"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+// DependencyHealth is the health of a dependency of the API
+type DependencyHealth struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Optional bool   `json:"optional"`
+}
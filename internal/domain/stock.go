@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultLowStockThreshold is the low-stock threshold of products nothing else configures
+const DefaultLowStockThreshold = 10
+
+// CategoryAttribute is the product attribute whose value selects a stock template
+const CategoryAttribute = "category"
+
+// Sources of a product's effective stock levels, most specific first
+const (
+	StockLevelSourceProduct    = "product"
+	StockLevelSourceCategory   = "category"
+	StockLevelSourcePreference = "preference"
+	StockLevelSourceDefault    = "default"
+)
+
+// StockTemplate holds the default low-stock threshold and reorder quantity of the
+// products whose category attribute equals Category
+type StockTemplate struct {
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID            uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_stock_templates_user_category"`
+	Category          string    `json:"category" gorm:"not null;uniqueIndex:idx_stock_templates_user_category"`
+	LowStockThreshold int       `json:"low_stock_threshold" gorm:"not null"`
+	ReorderQuantity   int       `json:"reorder_quantity" gorm:"not null;default:0"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for StockTemplate
+func (StockTemplate) TableName() string {
+	return "stock_templates"
+}
+
+// StockTemplateRequest represents the request for defining a category's stock template
+type StockTemplateRequest struct {
+	LowStockThreshold int `json:"low_stock_threshold" binding:"gte=0"`
+	ReorderQuantity   int `json:"reorder_quantity" binding:"gte=0"`
+}
+
+// ValidateStockCategory checks a category name used as a stock template key
+func ValidateStockCategory(category string) error {
+	if category == "" || len(category) > 100 {
+		return fmt.Errorf("invalid category %q: use 1 to 100 characters", category)
+	}
+	return nil
+}
+
+// StockLevels are the low-stock threshold and reorder quantity that apply to a product
+type StockLevels struct {
+	LowStockThreshold int    `json:"low_stock_threshold"`
+	ThresholdSource   string `json:"threshold_source"`
+	ReorderQuantity   int    `json:"reorder_quantity"`
+	ReorderSource     string `json:"reorder_source"`
+	LowStock          bool   `json:"low_stock"`
+}
+
+// LowStockProduct is a product below its low-stock threshold
+type LowStockProduct struct {
+	ID     uuid.UUID   `json:"id"`
+	Name   string      `json:"name"`
+	Stock  int         `json:"stock"`
+	Levels StockLevels `json:"levels"`
+}
+
+// ProductCategory returns the value of the product's category attribute as text, matching
+// how PostgreSQL reads it with ->>, or an empty string when it is not set
+func ProductCategory(product *Product) string {
+	value, ok := product.Attributes[CategoryAttribute]
+	if !ok || value == nil {
+		return ""
+	}
+	if category, ok := value.(string); ok {
+		return category
+	}
+	return fmt.Sprint(value)
+}
+
+// ResolveStockLevels returns the stock levels of a product: its own overrides, else
+// those of its category's template (nil when there is none), else the owner's
+// preferred threshold (0 when unset), else the default threshold and no reorder quantity
+func ResolveStockLevels(product *Product, template *StockTemplate, preferredThreshold int) StockLevels {
+	levels := StockLevels{
+		LowStockThreshold: DefaultLowStockThreshold,
+		ThresholdSource:   StockLevelSourceDefault,
+		ReorderSource:     StockLevelSourceDefault,
+	}
+
+	switch {
+	case product.LowStockThreshold != nil:
+		levels.LowStockThreshold = *product.LowStockThreshold
+		levels.ThresholdSource = StockLevelSourceProduct
+	case template != nil:
+		levels.LowStockThreshold = template.LowStockThreshold
+		levels.ThresholdSource = StockLevelSourceCategory
+	case preferredThreshold > 0:
+		levels.LowStockThreshold = preferredThreshold
+		levels.ThresholdSource = StockLevelSourcePreference
+	}
+
+	switch {
+	case product.ReorderQuantity != nil:
+		levels.ReorderQuantity = *product.ReorderQuantity
+		levels.ReorderSource = StockLevelSourceProduct
+	case template != nil:
+		levels.ReorderQuantity = template.ReorderQuantity
+		levels.ReorderSource = StockLevelSourceCategory
+	}
+
+	levels.LowStock = product.Stock < levels.LowStockThreshold
+	return levels
+}
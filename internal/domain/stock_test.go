@@ -0,0 +1,52 @@
+package domain
+
+import "testing"
+
+func TestResolveStockLevels(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	template := &StockTemplate{Category: "shoes", LowStockThreshold: 20, ReorderQuantity: 50}
+
+	tests := []struct {
+		name          string
+		product       Product
+		template      *StockTemplate
+		preferred     int
+		wantThreshold int
+		wantSource    string
+		wantReorder   int
+		wantLowStock  bool
+	}{
+		{"default", Product{Stock: 9}, nil, 0, DefaultLowStockThreshold, StockLevelSourceDefault, 0, true},
+		{"preference", Product{Stock: 9}, nil, 5, 5, StockLevelSourcePreference, 0, false},
+		{"category", Product{Stock: 15}, template, 5, 20, StockLevelSourceCategory, 50, true},
+		{"product override", Product{Stock: 15, LowStockThreshold: intPtr(3), ReorderQuantity: intPtr(7)}, template, 5, 3, StockLevelSourceProduct, 7, false},
+		{"threshold override keeps category reorder", Product{Stock: 1, LowStockThreshold: intPtr(2)}, template, 0, 2, StockLevelSourceProduct, 50, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := ResolveStockLevels(&tt.product, tt.template, tt.preferred)
+			if levels.LowStockThreshold != tt.wantThreshold || levels.ThresholdSource != tt.wantSource {
+				t.Errorf("Expected threshold %d from %s, got %d from %s", tt.wantThreshold, tt.wantSource, levels.LowStockThreshold, levels.ThresholdSource)
+			}
+			if levels.ReorderQuantity != tt.wantReorder {
+				t.Errorf("Expected reorder quantity %d, got %d", tt.wantReorder, levels.ReorderQuantity)
+			}
+			if levels.LowStock != tt.wantLowStock {
+				t.Errorf("Expected low stock %v, got %v", tt.wantLowStock, levels.LowStock)
+			}
+		})
+	}
+}
+
+func TestProductCategory(t *testing.T) {
+	if got := ProductCategory(&Product{Attributes: Attributes{"category": "shoes"}}); got != "shoes" {
+		t.Errorf("Expected shoes, got %q", got)
+	}
+	if got := ProductCategory(&Product{Attributes: Attributes{"category": float64(5)}}); got != "5" {
+		t.Errorf("Expected numbers as text, got %q", got)
+	}
+	if got := ProductCategory(&Product{}); got != "" {
+		t.Errorf("Expected no category, got %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StreamEventNotification is the type of stream events carrying a notification
+const StreamEventNotification = "notification"
+
+// StreamEvent is a real-time update sent to a user's open event streams: an event
+// from the event log, identified by its sequence, or a notification, which has none
+type StreamEvent struct {
+	Sequence int64           `json:"sequence,omitempty"`
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// NewEventStreamEvent maps a logged event to a stream event
+func NewEventStreamEvent(event Event) (StreamEvent, error) {
+	data, err := json.Marshal(NewEventResponse(event))
+	if err != nil {
+		return StreamEvent{}, err
+	}
+	return StreamEvent{Sequence: event.Sequence, Type: event.Type, Data: data}, nil
+}
+
+// NewNotificationStreamEvent maps a notification to a stream event
+func NewNotificationStreamEvent(notification *Notification) (StreamEvent, error) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return StreamEvent{}, err
+	}
+	return StreamEvent{Type: StreamEventNotification, Data: data}, nil
+}
+
+// Inventory socket subscription actions
+const (
+	InventorySubscribe   = "subscribe"
+	InventoryUnsubscribe = "unsubscribe"
+)
+
+// Inventory socket message types
+const (
+	InventoryMessageSubscribed = "subscribed"
+	InventoryMessageHeartbeat  = "heartbeat"
+	InventoryMessageError      = "error"
+)
+
+// MaxWatchedProducts is the number of products one inventory socket may watch
+const MaxWatchedProducts = 500
+
+// InventorySubscription is a message from an inventory socket client, adding
+// products to or removing them from the ones it watches
+type InventorySubscription struct {
+	Action     string      `json:"action"`
+	ProductIDs []uuid.UUID `json:"product_ids"`
+}
+
+// InventoryMessage is a message to an inventory socket client: a stock or price
+// change, or a reply to a subscription
+type InventoryMessage struct {
+	Type          string      `json:"type"`
+	Sequence      int64       `json:"sequence,omitempty"`
+	ProductID     *uuid.UUID  `json:"product_id,omitempty"`
+	Name          string      `json:"name,omitempty"`
+	Stock         *int        `json:"stock,omitempty"`
+	PreviousStock *int        `json:"previous_stock,omitempty"`
+	Price         *float64    `json:"price,omitempty"`
+	PreviousPrice *float64    `json:"previous_price,omitempty"`
+	OccurredAt    *time.Time  `json:"occurred_at,omitempty"`
+	ProductIDs    []uuid.UUID `json:"product_ids,omitempty"`
+	Message       string      `json:"message,omitempty"`
+}
+
+// NewInventoryMessage maps a stream event to the stock or price change sent to
+// inventory sockets, reporting false for events that are neither. Quick stock
+// updates are reported as product.stock_changed.
+func NewInventoryMessage(event StreamEvent) (InventoryMessage, bool, error) {
+	eventType := event.Type
+	if eventType == EventStockAdjusted {
+		eventType = EventProductStockChanged
+	}
+	if eventType != EventProductStockChanged && eventType != EventProductPriceChanged {
+		return InventoryMessage{}, false, nil
+	}
+
+	var logged EventResponse
+	if err := json.Unmarshal(event.Data, &logged); err != nil {
+		return InventoryMessage{}, false, fmt.Errorf("invalid %s stream event: %w", event.Type, err)
+	}
+	var payload ProductEventPayload
+	if err := json.Unmarshal(logged.Payload, &payload); err != nil {
+		return InventoryMessage{}, false, fmt.Errorf("invalid %s event payload: %w", event.Type, err)
+	}
+
+	message := InventoryMessage{
+		Type:       eventType,
+		Sequence:   event.Sequence,
+		ProductID:  &payload.Product.ID,
+		Name:       payload.Product.Name,
+		Stock:      &payload.Product.Stock,
+		Price:      &payload.Product.Price,
+		OccurredAt: &logged.OccurredAt,
+	}
+	if payload.Previous != nil {
+		message.PreviousStock = &payload.Previous.Stock
+		message.PreviousPrice = &payload.Previous.Price
+	}
+	return message, true, nil
+}
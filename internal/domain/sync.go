@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sync change operations
+const (
+	SyncOperationUpsert = "upsert"
+	SyncOperationDelete = "delete"
+)
+
+// Sync limits
+const (
+	MaxSyncDevices      = 20
+	DefaultSyncPageSize = 100
+	MaxSyncPageSize     = 500
+)
+
+// SyncDevice is a device registered for offline sync. Token is the last sync token
+// the device acknowledged by requesting the changes after it.
+type SyncDevice struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name         string     `json:"name" gorm:"not null"`
+	Platform     string     `json:"platform"`
+	Token        int64      `json:"sync_token" gorm:"not null;default:0"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for SyncDevice
+func (SyncDevice) TableName() string {
+	return "sync_devices"
+}
+
+// SyncDeviceRequest represents the request for registering a sync device
+type SyncDeviceRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Platform string `json:"platform" binding:"max=50"`
+}
+
+// ProductSync is the latest change of a product. Token comes from a global sequence
+// and is reassigned on every change, so the products changed after a token are
+// those with a greater one. Deleted products are kept as tombstones.
+type ProductSync struct {
+	ProductID uuid.UUID `gorm:"type:uuid;primary_key"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_product_sync_user_token,priority:1;index:idx_product_sync_user_updated,priority:1"`
+	Token     int64     `gorm:"not null;index:idx_product_sync_user_token,priority:2"`
+	Version   int64     `gorm:"not null"`
+	Deleted   bool      `gorm:"not null;default:false"`
+	UpdatedAt time.Time `gorm:"not null;index:idx_product_sync_user_updated,priority:2"`
+}
+
+// TableName specifies the table name for ProductSync
+func (ProductSync) TableName() string {
+	return "product_sync"
+}
+
+// SyncChange is a product change returned by the changes API. Version and
+// UpdatedAt are the server's, for clients to detect conflicting offline edits.
+type SyncChange struct {
+	Token     int64     `json:"sync_token"`
+	Operation string    `json:"operation"`
+	ProductID uuid.UUID `json:"product_id"`
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Product   *Product  `json:"product,omitempty"`
+}
+
+// SyncChangesResponse is a page of changes after a sync token. SyncToken is the
+// token to request the next page with; HasMore is set while changes remain.
+type SyncChangesResponse struct {
+	Changes   []SyncChange `json:"changes"`
+	SyncToken int64        `json:"sync_token"`
+	HasMore   bool         `json:"has_more"`
+}
+
+// ValidateSyncToken checks a sync token sent by a client
+func ValidateSyncToken(token int64) error {
+	if token < 0 {
+		return fmt.Errorf("invalid sync token %d: must not be negative", token)
+	}
+	return nil
+}
+
+// NewSyncChangesResponse builds a page of changes from the product changes after
+// since, fetched with one more than limit to tell whether more remain, and the
+// current state of the products that still exist
+func NewSyncChangesResponse(since int64, limit int, syncs []ProductSync, products map[uuid.UUID]*Product) *SyncChangesResponse {
+	response := &SyncChangesResponse{Changes: []SyncChange{}, SyncToken: since}
+	if len(syncs) > limit {
+		syncs = syncs[:limit]
+		response.HasMore = true
+	}
+
+	for _, sync := range syncs {
+		change := SyncChange{
+			Token:     sync.Token,
+			Operation: SyncOperationDelete,
+			ProductID: sync.ProductID,
+			Version:   sync.Version,
+			UpdatedAt: sync.UpdatedAt,
+		}
+		// Products deleted after their change was read are reported as deleted, and
+		// products changed since then with their current version
+		if product, ok := products[sync.ProductID]; ok && !sync.Deleted {
+			change.Operation = SyncOperationUpsert
+			change.Version = product.Version
+			change.UpdatedAt = product.UpdatedAt
+			change.Product = product
+		}
+		response.Changes = append(response.Changes, change)
+		response.SyncToken = sync.Token
+	}
+
+	return response
+}
+
+// ProductChangesSettle is how far the until timestamp of a product changes
+// response trails the request: a transaction committing late records its changes
+// with an earlier time, so the next request re-reads the last few seconds
+const ProductChangesSettle = 5 * time.Second
+
+// ProductChange is the latest change of a product read by timestamp. CreatedAt is
+// nil once the product no longer exists.
+type ProductChange struct {
+	ProductID uuid.UUID
+	Deleted   bool
+	CreatedAt *time.Time
+	UpdatedAt time.Time
+}
+
+// ProductChangesResponse lists the IDs of the products created, updated and deleted
+// after a timestamp. Until is the timestamp to request the next changes after;
+// changes may be reported again by that request, so clients apply them idempotently.
+type ProductChangesResponse struct {
+	Created []uuid.UUID `json:"created"`
+	Updated []uuid.UUID `json:"updated"`
+	Deleted []uuid.UUID `json:"deleted"`
+	Until   time.Time   `json:"until"`
+}
+
+// NewProductChangesResponse sorts the product changes after since by kind. A
+// product created and changed again since is reported as created only.
+func NewProductChangesResponse(since, until time.Time, changes []ProductChange) *ProductChangesResponse {
+	response := &ProductChangesResponse{
+		Created: []uuid.UUID{},
+		Updated: []uuid.UUID{},
+		Deleted: []uuid.UUID{},
+		Until:   until,
+	}
+
+	for _, change := range changes {
+		switch {
+		case change.Deleted || change.CreatedAt == nil:
+			response.Deleted = append(response.Deleted, change.ProductID)
+		case change.CreatedAt.After(since):
+			response.Created = append(response.Created, change.ProductID)
+		default:
+			response.Updated = append(response.Updated, change.ProductID)
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewSyncChangesResponse(t *testing.T) {
+	updated := time.Now()
+	kept, removed, raced := uuid.New(), uuid.New(), uuid.New()
+	syncs := []ProductSync{
+		{ProductID: kept, Token: 11, Version: 2, UpdatedAt: updated},
+		{ProductID: removed, Token: 12, Version: 3, Deleted: true, UpdatedAt: updated},
+		{ProductID: raced, Token: 13, Version: 1, UpdatedAt: updated},
+	}
+	products := map[uuid.UUID]*Product{
+		kept: {ID: kept, Version: 4, UpdatedAt: updated.Add(time.Second)},
+	}
+
+	response := NewSyncChangesResponse(10, 5, syncs, products)
+
+	if response.HasMore || response.SyncToken != 13 || len(response.Changes) != 3 {
+		t.Fatalf("Expected 3 changes up to token 13, got %+v", response)
+	}
+	upsert := response.Changes[0]
+	if upsert.Operation != SyncOperationUpsert || upsert.Product == nil || upsert.Version != 4 || !upsert.UpdatedAt.Equal(updated.Add(time.Second)) {
+		t.Errorf("Expected an upsert with the product's current version, got %+v", upsert)
+	}
+	if response.Changes[1].Operation != SyncOperationDelete || response.Changes[1].Version != 3 {
+		t.Errorf("Expected a tombstone to be reported as deleted, got %+v", response.Changes[1])
+	}
+	if response.Changes[2].Operation != SyncOperationDelete || response.Changes[2].Product != nil {
+		t.Errorf("Expected a product missing from the snapshot to be reported as deleted, got %+v", response.Changes[2])
+	}
+}
+
+func TestNewSyncChangesResponse_Pages(t *testing.T) {
+	syncs := []ProductSync{
+		{ProductID: uuid.New(), Token: 3, Deleted: true},
+		{ProductID: uuid.New(), Token: 5, Deleted: true},
+		{ProductID: uuid.New(), Token: 8, Deleted: true},
+	}
+
+	response := NewSyncChangesResponse(2, 2, syncs, nil)
+	if !response.HasMore || response.SyncToken != 5 || len(response.Changes) != 2 {
+		t.Errorf("Expected a page of 2 changes up to token 5 with more remaining, got %+v", response)
+	}
+
+	empty := NewSyncChangesResponse(8, 2, nil, nil)
+	if empty.HasMore || empty.SyncToken != 8 || empty.Changes == nil || len(empty.Changes) != 0 {
+		t.Errorf("Expected an empty page to keep the since token, got %+v", empty)
+	}
+}
+
+func TestValidateSyncToken(t *testing.T) {
+	if err := ValidateSyncToken(0); err != nil {
+		t.Errorf("Expected token 0 to be valid, got %v", err)
+	}
+	if err := ValidateSyncToken(-1); err == nil {
+		t.Error("Expected a negative token to be rejected")
+	}
+}
+
+func TestNewProductChangesResponse(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	before, after := since.Add(-time.Hour), since.Add(time.Minute)
+	created, updated, deleted, gone := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	changes := []ProductChange{
+		{ProductID: created, CreatedAt: &after, UpdatedAt: after},
+		{ProductID: updated, CreatedAt: &before, UpdatedAt: after},
+		{ProductID: deleted, Deleted: true, UpdatedAt: after},
+		{ProductID: gone, UpdatedAt: after},
+	}
+
+	response := NewProductChangesResponse(since, after, changes)
+
+	if len(response.Created) != 1 || response.Created[0] != created {
+		t.Errorf("Expected %s created, got %v", created, response.Created)
+	}
+	if len(response.Updated) != 1 || response.Updated[0] != updated {
+		t.Errorf("Expected %s updated, got %v", updated, response.Updated)
+	}
+	if len(response.Deleted) != 2 || response.Deleted[0] != deleted || response.Deleted[1] != gone {
+		t.Errorf("Expected %s and %s deleted, got %v", deleted, gone, response.Deleted)
+	}
+	if !response.Until.Equal(after) {
+		t.Errorf("Expected until %v, got %v", after, response.Until)
+	}
+}
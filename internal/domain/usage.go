@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsage is a user's API usage on one day (UTC): the authenticated requests
+// made and the request and response body bytes transferred
+type APIUsage struct {
+	UserID    uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Date      time.Time `json:"date" gorm:"type:date;primaryKey;index"`
+	Requests  int64     `json:"requests" gorm:"not null;default:0"`
+	BytesIn   int64     `json:"bytes_in" gorm:"not null;default:0"`
+	BytesOut  int64     `json:"bytes_out" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// TableName specifies the table name for APIUsage
+func (APIUsage) TableName() string {
+	return "api_usage"
+}
+
+// UsageTotals sums API usage over a period
+type UsageTotals struct {
+	Requests int64 `json:"requests"`
+	BytesIn  int64 `json:"bytes_in"`
+	BytesOut int64 `json:"bytes_out"`
+}
+
+// UsageResponse represents a user's API usage between From and To, by day
+type UsageResponse struct {
+	UserID uuid.UUID   `json:"user_id"`
+	From   time.Time   `json:"from"`
+	To     time.Time   `json:"to"`
+	Days   []APIUsage  `json:"days"`
+	Total  UsageTotals `json:"total"`
+}
+
+// NewUsageResponse builds a usage response from the days with usage, in date order
+func NewUsageResponse(userID uuid.UUID, from, to time.Time, days []APIUsage) *UsageResponse {
+	response := &UsageResponse{UserID: userID, From: from, To: to, Days: days}
+	if response.Days == nil {
+		response.Days = []APIUsage{}
+	}
+
+	for _, day := range days {
+		response.Total.Requests += day.Requests
+		response.Total.BytesIn += day.BytesIn
+		response.Total.BytesOut += day.BytesOut
+	}
+	return response
+}
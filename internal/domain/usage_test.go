@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewUsageResponse(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+
+	response := NewUsageResponse(userID, from, to, []APIUsage{
+		{Date: from, Requests: 10, BytesIn: 200, BytesOut: 3000},
+		{Date: from.AddDate(0, 0, 2), Requests: 5, BytesIn: 0, BytesOut: 1000},
+	})
+	want := UsageTotals{Requests: 15, BytesIn: 200, BytesOut: 4000}
+	if response.Total != want || response.UserID != userID || len(response.Days) != 2 {
+		t.Errorf("Expected totals %+v over 2 days, got %+v", want, response)
+	}
+
+	empty := NewUsageResponse(userID, from, to, nil)
+	if empty.Days == nil || empty.Total != (UsageTotals{}) {
+		t.Errorf("Expected no days and zero totals, got %+v", empty)
+	}
+}
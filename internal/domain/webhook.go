@@ -0,0 +1,311 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types
+const (
+	WebhookProductCreated      = "product.created"
+	WebhookProductUpdated      = "product.updated"
+	WebhookProductDeleted      = "product.deleted"
+	WebhookProductStockChanged = "product.stock_changed"
+	WebhookProductPriceChanged = "product.price_changed"
+	WebhookUserLoginAnomaly    = "user.login_anomaly"
+)
+
+// WebhookEventTypes lists every event a subscription can select
+var WebhookEventTypes = []string{
+	WebhookProductCreated,
+	WebhookProductUpdated,
+	WebhookProductDeleted,
+	WebhookProductStockChanged,
+	WebhookProductPriceChanged,
+	WebhookUserLoginAnomaly,
+}
+
+// Price change directions a webhook filter can require
+const (
+	PriceChangeDrop = "drop"
+	PriceChangeRise = "rise"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryRunning   = "running"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// MaxWebhookSubscriptions limits the number of subscriptions a user on the free
+// plan can register by default
+const MaxWebhookSubscriptions = 10
+
+// WebhookEvent describes a product change, or a login from a new country,
+// published to webhook subscribers
+type WebhookEvent struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	UserID     uuid.UUID       `json:"-"`
+	Product    *WebhookProduct `json:"product,omitempty"`
+	Previous   *WebhookProduct `json:"previous,omitempty"`
+	Login      *LoginEvent     `json:"login,omitempty"`
+}
+
+// WebhookProduct is the product state carried by a webhook event
+type WebhookProduct struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Price       float64    `json:"price"`
+	Stock       int        `json:"stock"`
+	Attributes  Attributes `json:"attributes"`
+	Public      bool       `json:"public"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// NewWebhookProduct copies the fields of a product that are sent to subscribers
+func NewWebhookProduct(product *Product) WebhookProduct {
+	return WebhookProduct{
+		ID:          product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Stock:       product.Stock,
+		Attributes:  product.Attributes,
+		Public:      product.Public,
+		UpdatedAt:   product.UpdatedAt,
+	}
+}
+
+// WebhookEvents is a list of event types stored as JSONB
+type WebhookEvents []string
+
+// Value implements driver.Valuer
+func (e WebhookEvents) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (e *WebhookEvents) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*e = WebhookEvents{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookEvents", value)
+	}
+	return json.Unmarshal(data, e)
+}
+
+// GormDataType returns the column type used by GORM
+func (WebhookEvents) GormDataType() string {
+	return "jsonb"
+}
+
+// Contains reports whether the list selects the event type
+func (e WebhookEvents) Contains(eventType string) bool {
+	for _, selected := range e {
+		if selected == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookFilter narrows the events delivered to a subscription. Attributes must
+// all equal the product's; price conditions only match price changes.
+type WebhookFilter struct {
+	Attributes            map[string]string `json:"attributes,omitempty"`
+	PriceChange           string            `json:"price_change,omitempty"`
+	MinPriceChangePercent float64           `json:"min_price_change_percent,omitempty"`
+}
+
+// Value implements driver.Valuer
+func (f WebhookFilter) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (f *WebhookFilter) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*f = WebhookFilter{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookFilter", value)
+	}
+	return json.Unmarshal(data, f)
+}
+
+// GormDataType returns the column type used by GORM
+func (WebhookFilter) GormDataType() string {
+	return "jsonb"
+}
+
+// hasPriceConditions reports whether the filter constrains price changes
+func (f WebhookFilter) hasPriceConditions() bool {
+	return f.PriceChange != "" || f.MinPriceChangePercent > 0
+}
+
+// Matches reports whether an event passes the filter. The filter constrains
+// product events only.
+func (f WebhookFilter) Matches(event WebhookEvent) bool {
+	if event.Login != nil {
+		return true
+	}
+
+	var attributes Attributes
+	if event.Product != nil {
+		attributes = event.Product.Attributes
+	}
+	for key, want := range f.Attributes {
+		value, ok := attributes[key]
+		if !ok || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+
+	if !f.hasPriceConditions() {
+		return true
+	}
+	if event.Product == nil || event.Previous == nil || event.Previous.Price <= 0 || event.Previous.Price == event.Product.Price {
+		return false
+	}
+
+	change := event.Product.Price - event.Previous.Price
+	switch f.PriceChange {
+	case PriceChangeDrop:
+		if change > 0 {
+			return false
+		}
+	case PriceChangeRise:
+		if change < 0 {
+			return false
+		}
+	}
+
+	percent := math.Abs(change) / event.Previous.Price * 100
+	return percent >= f.MinPriceChangePercent
+}
+
+// WebhookSubscription is an endpoint notified of selected product events
+type WebhookSubscription struct {
+	ID        uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID     `json:"user_id" gorm:"type:uuid;not null;index"`
+	URL       string        `json:"url" gorm:"not null"`
+	Secret    string        `json:"-" gorm:"not null"`
+	Events    WebhookEvents `json:"events" gorm:"type:jsonb;not null;default:'[]'"`
+	Filter    WebhookFilter `json:"filter" gorm:"type:jsonb;not null;default:'{}'"`
+	Active    bool          `json:"active" gorm:"not null;default:true"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// TableName specifies the table name for WebhookSubscription
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Accepts reports whether an event should be delivered to the subscription
+func (s *WebhookSubscription) Accepts(event WebhookEvent) bool {
+	return s.Active && s.UserID == event.UserID && s.Events.Contains(event.Type) && s.Filter.Matches(event)
+}
+
+// WebhookDelivery is a queued or attempted delivery of an event to a subscription
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SubscriptionID uuid.UUID  `json:"subscription_id" gorm:"type:uuid;not null;index"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	Payload        []byte     `json:"-" gorm:"type:jsonb;not null"`
+	Status         string     `json:"status" gorm:"not null;index"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// TableName specifies the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookSubscriptionRequest represents the request for creating or replacing a webhook subscription
+type WebhookSubscriptionRequest struct {
+	URL    string        `json:"url" binding:"required,url,max=2048"`
+	Events []string      `json:"events" binding:"required,min=1,dive,required"`
+	Filter WebhookFilter `json:"filter"`
+	Active *bool         `json:"active"`
+}
+
+// WebhookSubscriptionCreatedResponse includes the signing secret, which is only shown once
+type WebhookSubscriptionCreatedResponse struct {
+	*WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// ValidateWebhookSubscription checks the selected events and filter of a subscription request
+func ValidateWebhookSubscription(events []string, filter WebhookFilter) error {
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		if !WebhookEvents(WebhookEventTypes).Contains(event) {
+			return fmt.Errorf("unknown event %q", event)
+		}
+		if seen[event] {
+			return fmt.Errorf("event %q is selected more than once", event)
+		}
+		seen[event] = true
+	}
+
+	for key := range filter.Attributes {
+		if err := ValidateAttributeKey(key); err != nil {
+			return err
+		}
+	}
+
+	switch filter.PriceChange {
+	case "", PriceChangeDrop, PriceChangeRise:
+	default:
+		return fmt.Errorf("price_change must be %q or %q", PriceChangeDrop, PriceChangeRise)
+	}
+	if filter.MinPriceChangePercent < 0 {
+		return errors.New("min_price_change_percent cannot be negative")
+	}
+	if filter.hasPriceConditions() && !seen[WebhookProductPriceChanged] {
+		return fmt.Errorf("price filters require the %q event", WebhookProductPriceChanged)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func priceEvent(previous, current float64, attributes Attributes) WebhookEvent {
+	return WebhookEvent{
+		Type:     WebhookProductPriceChanged,
+		Product:  &WebhookProduct{Price: current, Attributes: attributes},
+		Previous: &WebhookProduct{Price: previous, Attributes: attributes},
+	}
+}
+
+func TestWebhookFilter_Matches(t *testing.T) {
+	shoes := Attributes{"category": "shoes"}
+
+	tests := []struct {
+		name   string
+		filter WebhookFilter
+		event  WebhookEvent
+		want   bool
+	}{
+		{"empty filter", WebhookFilter{}, priceEvent(100, 120, nil), true},
+		{"attribute match", WebhookFilter{Attributes: map[string]string{"category": "shoes"}}, priceEvent(100, 90, shoes), true},
+		{"attribute mismatch", WebhookFilter{Attributes: map[string]string{"category": "hats"}}, priceEvent(100, 90, shoes), false},
+		{"attribute missing", WebhookFilter{Attributes: map[string]string{"category": "shoes"}}, priceEvent(100, 90, nil), false},
+		{"drop over threshold", WebhookFilter{PriceChange: PriceChangeDrop, MinPriceChangePercent: 10}, priceEvent(100, 85, nil), true},
+		{"drop under threshold", WebhookFilter{PriceChange: PriceChangeDrop, MinPriceChangePercent: 10}, priceEvent(100, 95, nil), false},
+		{"rise when drop required", WebhookFilter{PriceChange: PriceChangeDrop}, priceEvent(100, 150, nil), false},
+		{"rise", WebhookFilter{PriceChange: PriceChangeRise}, priceEvent(100, 101, nil), true},
+		{"threshold either way", WebhookFilter{MinPriceChangePercent: 20}, priceEvent(100, 125, nil), true},
+		{"price filter without previous", WebhookFilter{PriceChange: PriceChangeDrop}, WebhookEvent{Type: WebhookProductCreated}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookSubscription_Accepts(t *testing.T) {
+	userID := uuid.New()
+	subscription := &WebhookSubscription{
+		UserID: userID,
+		Events: WebhookEvents{WebhookProductStockChanged},
+		Active: true,
+	}
+
+	event := WebhookEvent{Type: WebhookProductStockChanged, UserID: userID}
+	if !subscription.Accepts(event) {
+		t.Error("Expected the subscription to accept a selected event")
+	}
+
+	if subscription.Accepts(WebhookEvent{Type: WebhookProductDeleted, UserID: userID}) {
+		t.Error("Expected the subscription to ignore unselected events")
+	}
+	if subscription.Accepts(WebhookEvent{Type: WebhookProductStockChanged, UserID: uuid.New()}) {
+		t.Error("Expected the subscription to ignore other users' events")
+	}
+
+	subscription.Active = false
+	if subscription.Accepts(event) {
+		t.Error("Expected an inactive subscription to accept nothing")
+	}
+}
+
+func TestValidateWebhookSubscription(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []string
+		filter  WebhookFilter
+		wantErr string
+	}{
+		{"valid", []string{WebhookProductStockChanged}, WebhookFilter{Attributes: map[string]string{"category": "shoes"}}, ""},
+		{"price filter", []string{WebhookProductPriceChanged}, WebhookFilter{PriceChange: PriceChangeDrop, MinPriceChangePercent: 10}, ""},
+		{"unknown event", []string{"product.viewed"}, WebhookFilter{}, "unknown event"},
+		{"duplicate event", []string{WebhookProductCreated, WebhookProductCreated}, WebhookFilter{}, "more than once"},
+		{"bad attribute key", []string{WebhookProductCreated}, WebhookFilter{Attributes: map[string]string{"Bad Key": "x"}}, "invalid attribute key"},
+		{"bad direction", []string{WebhookProductPriceChanged}, WebhookFilter{PriceChange: "sideways"}, "price_change"},
+		{"negative percent", []string{WebhookProductPriceChanged}, WebhookFilter{MinPriceChangePercent: -1}, "negative"},
+		{"price filter without event", []string{WebhookProductStockChanged}, WebhookFilter{PriceChange: PriceChangeDrop}, "require"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhookSubscription(tt.events, tt.filter)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
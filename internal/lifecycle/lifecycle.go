@@ -0,0 +1,87 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Hook stops one component of the application
+type Hook struct {
+	Name    string
+	Timeout time.Duration
+	Stop    func(ctx context.Context) error
+}
+
+// Manager stops the components of the application in the order they were added,
+// giving each at most its own timeout so that a stuck component cannot keep the
+// ones after it, such as connection pools, from being closed
+type Manager struct {
+	hooks []Hook
+}
+
+// NewManager creates a manager without hooks
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Add registers a hook run on shutdown after the hooks added before it; a zero
+// timeout leaves the hook bounded only by the shutdown context
+func (m *Manager) Add(name string, timeout time.Duration, stop func(ctx context.Context) error) {
+	m.hooks = append(m.hooks, Hook{Name: name, Timeout: timeout, Stop: stop})
+}
+
+// Shutdown runs every hook in order. A hook that fails or outlives its timeout
+// is logged and left behind, and the next hook runs; the errors of all hooks are
+// returned together.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, hook := range m.hooks {
+		start := time.Now()
+		if err := run(ctx, hook); err != nil {
+			log.Printf("Shutdown: %s failed after %s: %v", hook.Name, time.Since(start).Round(time.Millisecond), err)
+			errs = append(errs, fmt.Errorf("%s: %w", hook.Name, err))
+			continue
+		}
+		log.Printf("Shutdown: %s stopped in %s", hook.Name, time.Since(start).Round(time.Millisecond))
+	}
+	return errors.Join(errs...)
+}
+
+// run calls the hook and stops waiting for it once its timeout has passed
+func run(ctx context.Context, hook Hook) error {
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.Timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.Stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("gave up waiting: %w", ctx.Err())
+	}
+}
+
+// Blocking adapts a stop function that cannot be cancelled, such as closing a
+// queue and waiting for it to drain, into a hook
+func Blocking(stop func()) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		stop()
+		return nil
+	}
+}
+
+// Closer adapts a Close method into a hook
+func Closer(close func() error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return close()
+	}
+}
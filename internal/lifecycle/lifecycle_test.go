@@ -0,0 +1,63 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	var order []string
+	stopped := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	manager := NewManager()
+	manager.Add("http", time.Second, stopped("http"))
+	manager.Add("stuck", 20*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	manager.Add("failing", time.Second, func(ctx context.Context) error {
+		return errors.New("connection reset")
+	})
+	manager.Add("database", time.Second, stopped("database"))
+
+	start := time.Now()
+	err := manager.Shutdown(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the stuck hook abandoned at its timeout, took %s", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the timeout reported, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "failing: connection reset") {
+		t.Errorf("expected the failure reported, got %v", err)
+	}
+	if len(order) != 2 || order[0] != "http" || order[1] != "database" {
+		t.Errorf("expected hooks run in order after failures, got %v", order)
+	}
+}
+
+func TestManager_ShutdownDeadline(t *testing.T) {
+	manager := NewManager()
+	var deadline time.Time
+	manager.Add("workers", time.Hour, func(ctx context.Context) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Until(deadline) > time.Minute {
+		t.Errorf("expected the shutdown deadline to bound the hook, got %s", deadline)
+	}
+}
@@ -0,0 +1,34 @@
+// Package markdown renders user-supplied Markdown to HTML that is safe to embed in a page
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// renderer converts GitHub-flavoured Markdown; raw HTML in the source is omitted
+var renderer = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(html.WithHardWraps()),
+)
+
+// policy allows the formatting produced by Markdown and adds rel="nofollow" to links
+var policy = bluemonday.UGCPolicy()
+
+// Render converts Markdown to sanitized HTML. Output is empty for empty input.
+func Render(source string) string {
+	if source == "" {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		// goldmark only fails on writer errors, which a bytes.Buffer never returns
+		return policy.Sanitize(source)
+	}
+	return policy.Sanitize(buf.String())
+}
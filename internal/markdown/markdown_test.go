@@ -0,0 +1,42 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		without []string
+	}{
+		{"empty", "", nil, nil},
+		{"emphasis", "Hand **made** in _Porto_", []string{"<strong>made</strong>", "<em>Porto</em>"}, nil},
+		{"list", "- oak\n- walnut", []string{"<ul>", "<li>oak</li>"}, nil},
+		{"link", "[shop](https://example.com)", []string{`href="https://example.com"`, `rel="nofollow"`}, nil},
+		{"script", "Nice <script>alert(1)</script> chair", []string{"Nice"}, []string{"<script"}},
+		{"javascript link", "[x](javascript:alert(1))", nil, []string{"javascript:"}},
+		{"event handler", `<img src="x" onerror="alert(1)">`, nil, []string{"onerror"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.source)
+			if tt.source == "" && got != "" {
+				t.Errorf("Expected empty output, got %q", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in %q", want, got)
+				}
+			}
+			for _, unwanted := range tt.without {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Did not expect %q in %q", unwanted, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package mocks provides mocks of the domain interfaces for service tests.
+package mocks
+
+//go:generate go run ./mockgen -out mocks_gen.go
@@ -0,0 +1,174 @@
+// Command mockgen writes mocks of the domain interfaces services depend on. Each mock
+// has a function field per method, run by the method, and records the calls made.
+//
+// Usage, from internal/mocks: go run ./mockgen -out mocks_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// domainPath is the import path of the package declaring the interfaces
+const domainPath = "products/internal/domain"
+
+// interfaces are the names of the mocked interfaces
+var interfaces = []string{"ProductRepository", "UserRepository", "Cache"}
+
+func main() {
+	out := flag.String("out", "mocks_gen.go", "file to write the mocks to")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	pkg, err := importer.ForCompiler(fset, "source", nil).Import(domainPath)
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", domainPath, err)
+	}
+
+	imports := map[string]string{"sync": "sync", domainPath: "domain"}
+	qualifier := func(p *types.Package) string {
+		imports[p.Path()] = p.Name()
+		return p.Name()
+	}
+
+	var body bytes.Buffer
+	for _, name := range interfaces {
+		object := pkg.Scope().Lookup(name)
+		if object == nil {
+			log.Fatalf("%s.%s not found", domainPath, name)
+		}
+		iface, ok := object.Type().Underlying().(*types.Interface)
+		if !ok {
+			log.Fatalf("%s.%s is not an interface", domainPath, name)
+		}
+		writeMock(&body, name, iface.Complete(), qualifier)
+	}
+
+	var file bytes.Buffer
+	file.WriteString("// Code generated by mockgen; DO NOT EDIT.\n\npackage mocks\n\nimport (\n")
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if isStandard(paths[i]) != isStandard(paths[j]) {
+			return isStandard(paths[i])
+		}
+		return paths[i] < paths[j]
+	})
+	for i, path := range paths {
+		// standard library packages first
+		if i > 0 && isStandard(paths[i-1]) && !isStandard(path) {
+			file.WriteString("\n")
+		}
+		fmt.Fprintf(&file, "\t%q\n", path)
+	}
+	file.WriteString(")\n")
+	file.Write(body.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		log.Fatalf("Failed to format mocks: %v", err)
+	}
+	if err := os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatalf("Failed to write mocks: %v", err)
+	}
+}
+
+// writeMock writes the mock of the interface named name
+func writeMock(w *bytes.Buffer, name string, iface *types.Interface, qualifier types.Qualifier) {
+	fmt.Fprintf(w, "\n// %s is a mock of domain.%s. Methods run the function in the field\n", name, name)
+	fmt.Fprintf(w, "// named after them and panic when it is not set.\n")
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		fmt.Fprintf(w, "\t%sFunc %s\n", method.Name(), funcType(method.Type().(*types.Signature), qualifier))
+	}
+	fmt.Fprintf(w, "\n\tmu    sync.Mutex\n\tcalls []string\n}\n")
+	fmt.Fprintf(w, "\nvar _ domain.%s = (*%s)(nil)\n", name, name)
+
+	fmt.Fprintf(w, "\n// Calls returns the names of the methods called, in order\n")
+	fmt.Fprintf(w, "func (m *%s) Calls() []string {\n\tm.mu.Lock()\n\tdefer m.mu.Unlock()\n\treturn append([]string(nil), m.calls...)\n}\n", name)
+
+	fmt.Fprintf(w, "\n// CallCount returns the number of calls of a method\n")
+	fmt.Fprintf(w, "func (m *%s) CallCount(method string) int {\n\tm.mu.Lock()\n\tdefer m.mu.Unlock()\n", name)
+	fmt.Fprintf(w, "\tcount := 0\n\tfor _, call := range m.calls {\n\t\tif call == method {\n\t\t\tcount++\n\t\t}\n\t}\n\treturn count\n}\n")
+
+	fmt.Fprintf(w, "\nfunc (m *%s) record(method string) {\n\tm.mu.Lock()\n\tdefer m.mu.Unlock()\n\tm.calls = append(m.calls, method)\n}\n", name)
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		signature := method.Type().(*types.Signature)
+		params, args := parameters(signature, qualifier)
+
+		fmt.Fprintf(w, "\n// %s runs %sFunc\n", method.Name(), method.Name())
+		fmt.Fprintf(w, "func (m *%s) %s(%s)%s {\n", name, method.Name(), params, results(signature, qualifier))
+		fmt.Fprintf(w, "\tm.record(%q)\n", method.Name())
+		fmt.Fprintf(w, "\tif m.%sFunc == nil {\n\t\tpanic(\"mocks: unexpected call of %s.%s\")\n\t}\n", method.Name(), name, method.Name())
+		call := fmt.Sprintf("m.%sFunc(%s)", method.Name(), args)
+		if signature.Results().Len() > 0 {
+			fmt.Fprintf(w, "\treturn %s\n}\n", call)
+		} else {
+			fmt.Fprintf(w, "\t%s\n}\n", call)
+		}
+	}
+}
+
+// isStandard reports whether path is the import path of a standard library package
+func isStandard(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".") && !strings.HasPrefix(path, "products/")
+}
+
+// funcType returns the function type of a method signature
+func funcType(signature *types.Signature, qualifier types.Qualifier) string {
+	params, _ := parameters(signature, qualifier)
+	return "func(" + params + ")" + results(signature, qualifier)
+}
+
+// parameters returns the named parameter list of a signature and the arguments
+// passing them on
+func parameters(signature *types.Signature, qualifier types.Qualifier) (string, string) {
+	var params, args []string
+	for i := 0; i < signature.Params().Len(); i++ {
+		param := signature.Params().At(i)
+		name := param.Name()
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+
+		typ := types.TypeString(param.Type(), qualifier)
+		arg := name
+		if signature.Variadic() && i == signature.Params().Len()-1 {
+			typ = "..." + types.TypeString(param.Type().(*types.Slice).Elem(), qualifier)
+			arg += "..."
+		}
+		params = append(params, name+" "+typ)
+		args = append(args, arg)
+	}
+	return strings.Join(params, ", "), strings.Join(args, ", ")
+}
+
+// results returns the result list of a signature
+func results(signature *types.Signature, qualifier types.Qualifier) string {
+	var list []string
+	for i := 0; i < signature.Results().Len(); i++ {
+		list = append(list, types.TypeString(signature.Results().At(i).Type(), qualifier))
+	}
+	switch len(list) {
+	case 0:
+		return ""
+	case 1:
+		return " " + list[0]
+	default:
+		return " (" + strings.Join(list, ", ") + ")"
+	}
+}
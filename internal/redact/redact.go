@@ -0,0 +1,105 @@
+// Package redact removes secrets and personal data from request data before it is
+// logged or stored
+package redact
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive fields
+const Redacted = "[REDACTED]"
+
+// sensitiveFields are the JSON fields and query parameters whose values are secrets
+var sensitiveFields = map[string]bool{
+	"password":          true,
+	"current_password":  true,
+	"new_password":      true,
+	"access_token":      true,
+	"refresh_token":     true,
+	"token":             true,
+	"captcha_token":     true,
+	"device_code":       true,
+	"secret":            true,
+	"secret_access_key": true,
+	"authorization":     true,
+}
+
+// emailPattern matches email addresses within text
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// Value replaces the values of sensitive keys throughout a decoded JSON value, and
+// with emails set masks the email addresses in its strings
+func Value(value interface{}, emails bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = Value(child, emails)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = Value(child, emails)
+		}
+	case string:
+		if emails {
+			return Emails(v)
+		}
+	}
+	return value
+}
+
+// JSON redacts a JSON document like Value. Other content is returned with only
+// its email addresses masked when emails is set, since its fields are unknown.
+func JSON(body string, emails bool) string {
+	if body == "" {
+		return ""
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(body), &parsed); err == nil {
+		if redacted, err := json.Marshal(Value(parsed, emails)); err == nil {
+			return string(redacted)
+		}
+	}
+	if emails {
+		return Emails(body)
+	}
+	return body
+}
+
+// Emails masks the email addresses in text, keeping the first character of the
+// local part and the domain, e.g. "j***@example.com"
+func Emails(text string) string {
+	return emailPattern.ReplaceAllStringFunc(text, func(email string) string {
+		local, domain, _ := strings.Cut(email, "@")
+		return local[:1] + "***@" + domain
+	})
+}
+
+// Query redacts the sensitive parameters and masks the email addresses of a raw
+// query string
+func Query(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return Emails(rawQuery)
+	}
+	for key, list := range values {
+		for i := range list {
+			if sensitiveFields[strings.ToLower(key)] {
+				list[i] = Redacted
+			} else {
+				list[i] = Emails(list[i])
+			}
+		}
+	}
+	return values.Encode()
+}
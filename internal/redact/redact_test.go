@@ -0,0 +1,36 @@
+package redact
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	body := `{"email":"jane@example.com","password":"hunter2","items":[{"Token":"abc","note":"ask bob.smith@shop.io"}]}`
+
+	got := JSON(body, true)
+	want := `{"email":"j***@example.com","items":[{"Token":"[REDACTED]","note":"ask b***@shop.io"}],"password":"[REDACTED]"}`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	got = JSON(body, false)
+	want = `{"email":"jane@example.com","items":[{"Token":"[REDACTED]","note":"ask bob.smith@shop.io"}],"password":"[REDACTED]"}`
+	if got != want {
+		t.Errorf("Expected emails kept, got %s", got)
+	}
+
+	if got := JSON("email=jane@example.com", true); got != "email=j***@example.com" {
+		t.Errorf("Expected emails masked in non-JSON bodies, got %s", got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	got, err := url.ParseQuery(Query("email=jane%40example.com&token=abc&page=2"))
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	if got.Get("email") != "j***@example.com" || got.Get("token") != Redacted || got.Get("page") != "2" {
+		t.Errorf("Unexpected redacted query %v", got)
+	}
+}
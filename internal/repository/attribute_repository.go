@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// AttributeDefinitionRepository implements storage of user-defined attribute schemas
+type AttributeDefinitionRepository struct {
+	db *gorm.DB
+}
+
+// NewAttributeDefinitionRepository creates a new attribute definition repository
+func NewAttributeDefinitionRepository(db *gorm.DB) *AttributeDefinitionRepository {
+	return &AttributeDefinitionRepository{db: db}
+}
+
+// GetByUserID retrieves all attribute definitions of a user
+func (r *AttributeDefinitionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.AttributeDefinition, error) {
+	var definitions []domain.AttributeDefinition
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("key ASC").Find(&definitions).Error
+	return definitions, err
+}
+
+// GetByKey retrieves a single attribute definition of a user
+func (r *AttributeDefinitionRepository) GetByKey(ctx context.Context, userID uuid.UUID, key string) (*domain.AttributeDefinition, error) {
+	var definition domain.AttributeDefinition
+	err := r.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).First(&definition).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attribute definition not found")
+		}
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// Upsert creates or replaces attribute definitions by (user, key)
+func (r *AttributeDefinitionRepository) Upsert(ctx context.Context, definitions ...domain.AttributeDefinition) error {
+	if len(definitions) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "required", "allowed_values", "updated_at"}),
+	}).Create(&definitions).Error
+}
+
+// Delete removes a user's attribute definition
+func (r *AttributeDefinitionRepository) Delete(ctx context.Context, userID uuid.UUID, key string) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).Delete(&domain.AttributeDefinition{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("attribute definition not found")
+	}
+	return nil
+}
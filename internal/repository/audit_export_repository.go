@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AuditExportRepository implements storage of audit export jobs
+type AuditExportRepository struct {
+	*GenericRepository[domain.AuditExport]
+	db *gorm.DB
+}
+
+// NewAuditExportRepository creates a new audit export repository
+func NewAuditExportRepository(db *gorm.DB) *AuditExportRepository {
+	return &AuditExportRepository{
+		GenericRepository: NewGenericRepository[domain.AuditExport](db),
+		db:                db,
+	}
+}
+
+// GetByID retrieves an export job without its content
+func (r *AuditExportRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AuditExport, error) {
+	var export domain.AuditExport
+	err := r.db.WithContext(ctx).Omit("content").Where("id = ?", id).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("audit export not found")
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetContent retrieves an export job including its generated content
+func (r *AuditExportRepository) GetContent(ctx context.Context, id uuid.UUID) (*domain.AuditExport, error) {
+	var export domain.AuditExport
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("audit export not found")
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ClaimPending marks the oldest pending export as running and returns it, or nil when none are pending
+func (r *AuditExportRepository) ClaimPending(ctx context.Context) (*domain.AuditExport, error) {
+	var export domain.AuditExport
+	result := r.db.WithContext(ctx).Raw(`
+		UPDATE audit_exports SET status = ?
+		WHERE id = (
+			SELECT id FROM audit_exports WHERE status = ?
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, requested_by, user_id, "from", "to", format, include_reads, status, created_at`,
+		domain.AuditExportRunning, domain.AuditExportPending).Scan(&export)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &export, nil
+}
+
+// Complete stores the generated content of an export
+func (r *AuditExportRepository) Complete(ctx context.Context, id uuid.UUID, content []byte, rowCount int64) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&domain.AuditExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       domain.AuditExportCompleted,
+			"content":      content,
+			"row_count":    rowCount,
+			"completed_at": now,
+		}).Error
+}
+
+// Fail records why an export could not be generated
+func (r *AuditExportRepository) Fail(ctx context.Context, id uuid.UUID, reason string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&domain.AuditExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       domain.AuditExportFailed,
+			"error":        reason,
+			"completed_at": now,
+		}).Error
+}
+
+// ResetRunning returns exports interrupted by a restart to the pending queue
+func (r *AuditExportRepository) ResetRunning(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.AuditExport{}).
+		Where("status = ?", domain.AuditExportRunning).
+		Update("status", domain.AuditExportPending).Error
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AuditRepository implements storage of audit logs
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// CreateBatch stores several audit logs at once
+func (r *AuditRepository) CreateBatch(ctx context.Context, logs []domain.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(logs, 100).Error
+}
+
+// Query retrieves audit logs matching the filter, newest first
+func (r *AuditRepository) Query(ctx context.Context, filter domain.AuditLogFilter, pagination domain.Pagination) (*domain.AuditLogListResponse, error) {
+	var logs []domain.AuditLog
+	var total int64
+
+	dbQuery := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+
+	if filter.UserID != nil {
+		dbQuery = dbQuery.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Route != nil {
+		dbQuery = dbQuery.Where("route = ?", *filter.Route)
+	}
+	if filter.Status != nil {
+		dbQuery = dbQuery.Where("status = ?", *filter.Status)
+	}
+	if filter.From != nil {
+		dbQuery = dbQuery.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		dbQuery = dbQuery.Where("created_at <= ?", *filter.To)
+	}
+
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	if err := dbQuery.Order("created_at DESC").Offset(offset).Limit(pagination.PageSize).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch audit logs: %w", err)
+	}
+
+	if logs == nil {
+		logs = []domain.AuditLog{}
+	}
+
+	return &domain.AuditLogListResponse{
+		Logs:       logs,
+		Total:      total,
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		TotalPages: int((total + int64(pagination.PageSize) - 1) / int64(pagination.PageSize)),
+	}, nil
+}
+
+// Each calls fn for every audit log of a user in the time range, oldest first
+func (r *AuditRepository) Each(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(domain.AuditLog) error) error {
+	var batch []domain.AuditLog
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND created_at >= ? AND created_at <= ?", userID, from, to).
+		Order("created_at ASC").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, entry := range batch {
+				if err := fn(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// DeleteOlderThan removes audit logs created before the cutoff
+func (r *AuditRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&domain.AuditLog{})
+	return result.RowsAffected, result.Error
+}
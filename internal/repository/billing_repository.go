@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// BillingRepository implements storage of users' Stripe customers and invoices
+type BillingRepository struct {
+	db *gorm.DB
+}
+
+// NewBillingRepository creates a new billing repository
+func NewBillingRepository(db *gorm.DB) *BillingRepository {
+	return &BillingRepository{db: db}
+}
+
+// GetCustomerByUserID retrieves the Stripe customer of a user, or nil if the user
+// never started a checkout
+func (r *BillingRepository) GetCustomerByUserID(ctx context.Context, userID uuid.UUID) (*domain.BillingCustomer, error) {
+	return r.getCustomer(ctx, "user_id = ?", userID)
+}
+
+// GetCustomerByStripeID retrieves the user's link to a Stripe customer, or nil if
+// the customer is unknown
+func (r *BillingRepository) GetCustomerByStripeID(ctx context.Context, customerID string) (*domain.BillingCustomer, error) {
+	return r.getCustomer(ctx, "stripe_customer_id = ?", customerID)
+}
+
+// getCustomer retrieves the billing customer matching a condition, or nil
+func (r *BillingRepository) getCustomer(ctx context.Context, query string, args ...interface{}) (*domain.BillingCustomer, error) {
+	var customer domain.BillingCustomer
+	err := r.db.WithContext(ctx).Where(query, args...).First(&customer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// SaveCustomer creates or replaces the billing customer of a user
+func (r *BillingRepository) SaveCustomer(ctx context.Context, customer *domain.BillingCustomer) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"stripe_customer_id", "stripe_subscription_id", "subscription_status", "plan", "current_period_end", "updated_at"}),
+		}).
+		Create(customer).Error
+}
+
+// SaveInvoice creates an invoice or updates the stored copy of it
+func (r *BillingRepository) SaveInvoice(ctx context.Context, invoice *domain.Invoice) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "stripe_invoice_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"number", "status", "currency", "amount_due", "amount_paid", "hosted_invoice_url", "invoice_pdf", "period_start", "period_end", "updated_at"}),
+		}).
+		Create(invoice).Error
+}
+
+// GetInvoicesByUserID retrieves a user's invoices, newest first
+func (r *BillingRepository) GetInvoicesByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&invoices).Error
+	return invoices, err
+}
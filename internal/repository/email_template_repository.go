@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// EmailTemplateRepository implements storage of customized email templates
+type EmailTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailTemplateRepository creates a new email template repository
+func NewEmailTemplateRepository(db *gorm.DB) *EmailTemplateRepository {
+	return &EmailTemplateRepository{db: db}
+}
+
+// GetAll retrieves every customized email template
+func (r *EmailTemplateRepository) GetAll(ctx context.Context) ([]domain.EmailTemplate, error) {
+	var templates []domain.EmailTemplate
+	err := r.db.WithContext(ctx).Order("key ASC").Find(&templates).Error
+	return templates, err
+}
+
+// GetByKey retrieves a customized email template, or nil if the default is in use
+func (r *EmailTemplateRepository) GetByKey(ctx context.Context, key string) (*domain.EmailTemplate, error) {
+	var tmpl domain.EmailTemplate
+	err := r.db.WithContext(ctx).Where("key = ?", key).First(&tmpl).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// Upsert creates or replaces a customized email template by key
+func (r *EmailTemplateRepository) Upsert(ctx context.Context, tmpl *domain.EmailTemplate) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"subject", "body", "updated_by", "updated_at"}),
+	}).Create(tmpl).Error
+}
+
+// Delete removes a customized email template, restoring the default
+func (r *EmailTemplateRepository) Delete(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("key = ?", key).Delete(&domain.EmailTemplate{}).Error
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// EventRepository implements storage of the append-only domain event log
+type EventRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRepository creates a new event repository
+func NewEventRepository(db *gorm.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Create appends an event to the log
+func (r *EventRepository) Create(ctx context.Context, event *domain.Event) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// Query retrieves up to filter.Limit+1 events matching the filter after
+// filter.After, in sequence order
+func (r *EventRepository) Query(ctx context.Context, filter domain.EventFilter) ([]domain.Event, error) {
+	dbQuery := r.db.WithContext(ctx).Where("sequence > ?", filter.After)
+
+	if filter.UserID != nil {
+		dbQuery = dbQuery.Where("user_id = ?", *filter.UserID)
+	}
+	if len(filter.Types) > 0 {
+		dbQuery = dbQuery.Where("type IN ?", filter.Types)
+	}
+	if filter.SubjectID != nil {
+		dbQuery = dbQuery.Where("subject_id = ?", *filter.SubjectID)
+	}
+	if filter.From != nil {
+		dbQuery = dbQuery.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		dbQuery = dbQuery.Where("occurred_at <= ?", *filter.To)
+	}
+
+	var events []domain.Event
+	err := dbQuery.Order("sequence ASC").Limit(filter.Limit + 1).Find(&events).Error
+	return events, err
+}
+
+// DeleteOlderThan deletes events that occurred before cutoff
+func (r *EventRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("occurred_at < ?", cutoff).Delete(&domain.Event{})
+	return result.RowsAffected, result.Error
+}
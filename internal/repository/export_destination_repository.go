@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ExportDestinationRepository implements storage of user export destinations
+type ExportDestinationRepository struct {
+	*GenericRepository[domain.ExportDestination]
+	db *gorm.DB
+}
+
+// NewExportDestinationRepository creates a new export destination repository
+func NewExportDestinationRepository(db *gorm.DB) *ExportDestinationRepository {
+	return &ExportDestinationRepository{
+		GenericRepository: NewGenericRepository[domain.ExportDestination](db),
+		db:                db,
+	}
+}
+
+// GetByUserID retrieves the export destination configured by a user
+func (r *ExportDestinationRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.ExportDestination, error) {
+	var destination domain.ExportDestination
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&destination).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("export destination not found")
+		}
+		return nil, err
+	}
+	return &destination, nil
+}
+
+// GetDue retrieves destinations that have not been exported to since the given time
+func (r *ExportDestinationRepository) GetDue(ctx context.Context, since time.Time) ([]domain.ExportDestination, error) {
+	var destinations []domain.ExportDestination
+	err := r.db.WithContext(ctx).
+		Where("last_export_at IS NULL OR last_export_at < ?", since).
+		Find(&destinations).Error
+	return destinations, err
+}
+
+// DeleteByUserID removes the export destination configured by a user
+func (r *ExportDestinationRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.ExportDestination{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("export destination not found")
+	}
+	return nil
+}
+
+// UpdateStatus records the outcome of the latest export
+func (r *ExportDestinationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, exportedAt time.Time, status, exportErr string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.ExportDestination{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_export_at":     exportedAt,
+			"last_export_status": status,
+			"last_export_error":  exportErr,
+		}).Error
+}
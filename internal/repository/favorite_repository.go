@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// FavoriteRepository implements storage of user favorites
+type FavoriteRepository struct {
+	db *gorm.DB
+}
+
+// NewFavoriteRepository creates a new favorite repository
+func NewFavoriteRepository(db *gorm.DB) *FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+// Add puts a product on the user's favorites; adding twice is a no-op
+func (r *FavoriteRepository) Add(ctx context.Context, favorite *domain.Favorite) error {
+	return r.db.WithContext(ctx).
+		Omit("User", "Product").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(favorite).Error
+}
+
+// Remove takes a product off the user's favorites
+func (r *FavoriteRepository) Remove(ctx context.Context, userID, productID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		Delete(&domain.Favorite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.NotFoundError(domain.CodeNotFound, "favorite not found")
+	}
+	return nil
+}
+
+// GetProductsByUserID retrieves the user's favorite products that the user can
+// still see, most recently added first: their own products, and other users'
+// products while those are listed in their owner's public catalog
+func (r *FavoriteRepository) GetProductsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Product, error) {
+	var products []domain.Product
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Joins("JOIN favorites ON favorites.product_id = products.id").
+		Where("favorites.user_id = ?", userID).
+		Where("products.user_id = ? OR (products.public AND products.status = ? AND products.archived_at IS NULL)", userID, domain.ProductStatusPublished).
+		Order("favorites.created_at DESC").
+		Find(&products).Error
+	return products, err
+}
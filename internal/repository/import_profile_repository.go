@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ImportProfileRepository implements storage of CSV import mapping profiles
+type ImportProfileRepository struct {
+	*GenericRepository[domain.ImportProfile]
+	db *gorm.DB
+}
+
+// NewImportProfileRepository creates a new import profile repository
+func NewImportProfileRepository(db *gorm.DB) *ImportProfileRepository {
+	return &ImportProfileRepository{
+		GenericRepository: NewGenericRepository[domain.ImportProfile](db),
+		db:                db,
+	}
+}
+
+// GetByUserID retrieves a user's import profiles ordered by name
+func (r *ImportProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.ImportProfile, error) {
+	var profiles []domain.ImportProfile
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name ASC").Find(&profiles).Error
+	return profiles, err
+}
+
+// GetByName retrieves a user's import profile by name, or nil if there is none
+func (r *ImportProfileRepository) GetByName(ctx context.Context, userID uuid.UUID, name string) (*domain.ImportProfile, error) {
+	var profile domain.ImportProfile
+	err := r.db.WithContext(ctx).Where("user_id = ? AND name = ?", userID, name).First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
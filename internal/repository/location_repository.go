@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// LocationRepository implements storage of locations and the stock kept at them
+type LocationRepository struct {
+	*GenericRepository[domain.Location]
+	db *gorm.DB
+}
+
+// NewLocationRepository creates a new location repository
+func NewLocationRepository(db *gorm.DB) *LocationRepository {
+	return &LocationRepository{
+		GenericRepository: NewGenericRepository[domain.Location](db),
+		db:                db,
+	}
+}
+
+// GetByUserID retrieves a user's locations by name
+func (r *LocationRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Location, error) {
+	var locations []domain.Location
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name ASC").Find(&locations).Error
+	return locations, err
+}
+
+// DeleteUnlessStocked deletes a location, and its empty stock records, unless it
+// still holds stock of a product
+func (r *LocationRepository) DeleteUnlessStocked(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stocked int64
+		err := tx.Model(&domain.LocationStock{}).Where("location_id = ? AND stock > 0", id).Count(&stocked).Error
+		if err != nil {
+			return err
+		}
+		if stocked > 0 {
+			return domain.ErrLocationInUse
+		}
+		if err := tx.Where("location_id = ?", id).Delete(&domain.LocationStock{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.Location{}).Error
+	})
+}
+
+// GetProductLevels retrieves a product's stock at each location it is kept at, by
+// location name
+func (r *LocationRepository) GetProductLevels(ctx context.Context, productID uuid.UUID) ([]domain.LocationStockLevel, error) {
+	var levels []domain.LocationStockLevel
+	err := r.db.WithContext(ctx).
+		Table("location_stocks ls").
+		Select("ls.location_id, l.name, ls.stock").
+		Joins("JOIN locations l ON l.id = ls.location_id").
+		Where("ls.product_id = ?", productID).
+		Order("l.name ASC").
+		Scan(&levels).Error
+	return levels, err
+}
+
+// AdjustStock atomically adds delta to a product's stock at one of its owner's
+// locations and to the product's total stock, refusing to take more out of the
+// location than is kept there or to go below the product's reserved stock. It
+// returns the product's new stock levels and the new stock at the location.
+func (r *LocationRepository) AdjustStock(ctx context.Context, productID, locationID uuid.UUID, delta int) (*domain.Product, int, error) {
+	var product domain.Product
+	var levels domain.LocationStock
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "user_id", "stock", "reserved_stock", "updated_at").
+			Where("id = ?", productID).
+			First(&product).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrProductNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var locations int64
+		err = tx.Model(&domain.Location{}).Where("id = ? AND user_id = ?", locationID, product.UserID).Count(&locations).Error
+		if err != nil {
+			return err
+		}
+		if locations == 0 {
+			return domain.ErrLocationNotFound
+		}
+
+		// The product row lock serializes every change of the product's location stock
+		if err := tx.Where("product_id = ? AND location_id = ?", productID, locationID).Find(&levels).Error; err != nil {
+			return err
+		}
+		if levels.Stock+delta < 0 {
+			return domain.InsufficientLocationStockError(locationID, levels.Stock)
+		}
+		if product.Stock+delta < product.ReservedStock {
+			return domain.InsufficientStockError(product)
+		}
+
+		now := time.Now()
+		levels = domain.LocationStock{ProductID: productID, LocationID: locationID, Stock: levels.Stock + delta, UpdatedAt: now}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "location_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"stock", "updated_at"}),
+		}).Create(&levels).Error
+		if err != nil {
+			return fmt.Errorf("failed to update location stock: %w", err)
+		}
+
+		err = tx.Model(&domain.Product{}).Where("id = ?", productID).
+			Updates(map[string]interface{}{"stock": gorm.Expr("stock + ?", delta), "updated_at": now}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update stock: %w", err)
+		}
+		product.Stock += delta
+		product.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &product, levels.Stock, nil
+}
+
+// GetStats retrieves statistics of the stock a user keeps at a location, counting
+// products against their low-stock threshold by their stock at the location and
+// leaving out archived products
+func (r *LocationRepository) GetStats(ctx context.Context, userID, locationID uuid.UUID) (map[string]interface{}, error) {
+	var stats struct {
+		TotalProducts int64
+		TotalStock    int64
+		TotalValue    float64
+		LowStock      int64
+		OutOfStock    int64
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("location_stocks ls").
+		Joins("JOIN products p ON p.id = ls.product_id").
+		Joins("JOIN users u ON u.id = p.user_id").
+		Joins(stockTemplateJoinSQL).
+		Where("ls.location_id = ? AND p.user_id = ? AND p.archived_at IS NULL", locationID, userID).
+		Select(`
+			COUNT(*) as total_products,
+			COALESCE(SUM(ls.stock), 0) as total_stock,
+			COALESCE(SUM(p.price * ls.stock), 0) as total_value,
+			COUNT(CASE WHEN ls.stock < ` + lowStockThresholdSQL + ` THEN 1 END) as low_stock,
+			COUNT(CASE WHEN ls.stock = 0 THEN 1 END) as out_of_stock
+		`).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location stats: %w", err)
+	}
+
+	return map[string]interface{}{
+		"location_id":    locationID,
+		"total_products": stats.TotalProducts,
+		"total_stock":    stats.TotalStock,
+		"total_value":    stats.TotalValue,
+		"low_stock":      stats.LowStock,
+		"out_of_stock":   stats.OutOfStock,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// LoginDeviceRepository implements storage of the devices users log in from
+type LoginDeviceRepository struct {
+	*GenericRepository[domain.LoginDevice]
+	db *gorm.DB
+}
+
+// NewLoginDeviceRepository creates a new login device repository
+func NewLoginDeviceRepository(db *gorm.DB) *LoginDeviceRepository {
+	return &LoginDeviceRepository{
+		GenericRepository: NewGenericRepository[domain.LoginDevice](db),
+		db:                db,
+	}
+}
+
+// GetByFingerprint retrieves a user's device by its fingerprint, or nil if the
+// user never logged in from it
+func (r *LoginDeviceRepository) GetByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*domain.LoginDevice, error) {
+	var device domain.LoginDevice
+	err := r.db.WithContext(ctx).Where("user_id = ? AND fingerprint = ?", userID, fingerprint).First(&device).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// CreateIfNotExists records a new device, keeping the existing one when the same
+// device logged in concurrently. It reports whether the device was created.
+func (r *LoginDeviceRepository) CreateIfNotExists(ctx context.Context, device *domain.LoginDevice) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "fingerprint"}},
+			DoNothing: true,
+		}).
+		Create(device)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetByUserID retrieves a user's devices, most recently seen first
+func (r *LoginDeviceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.LoginDevice, error) {
+	var devices []domain.LoginDevice
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("last_seen_at DESC").Find(&devices).Error
+	return devices, err
+}
+
+// CountByUserID counts a user's devices
+func (r *LoginDeviceRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.LoginDevice{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products/internal/domain"
+)
+
+// LoginEventRepository implements storage of users' login history
+type LoginEventRepository struct {
+	*GenericRepository[domain.LoginEvent]
+	db *gorm.DB
+}
+
+// NewLoginEventRepository creates a new login event repository
+func NewLoginEventRepository(db *gorm.DB) *LoginEventRepository {
+	return &LoginEventRepository{
+		GenericRepository: NewGenericRepository[domain.LoginEvent](db),
+		db:                db,
+	}
+}
+
+// GetCountries retrieves the countries a user has logged in from
+func (r *LoginEventRepository) GetCountries(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var countries []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.LoginEvent{}).
+		Where("user_id = ? AND country <> ''", userID).
+		Distinct().
+		Pluck("country", &countries).Error
+	return countries, err
+}
+
+// GetByUserID retrieves a page of a user's login history, most recent first
+func (r *LoginEventRepository) GetByUserID(ctx context.Context, userID uuid.UUID, pagination domain.Pagination) (*domain.LoginHistoryResponse, error) {
+	var logins []domain.LoginEvent
+	var total int64
+
+	dbQuery := r.db.WithContext(ctx).Model(&domain.LoginEvent{}).Where("user_id = ?", userID)
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count logins: %w", err)
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	if err := dbQuery.Order("created_at DESC").Offset(offset).Limit(pagination.PageSize).Find(&logins).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch logins: %w", err)
+	}
+
+	if logins == nil {
+		logins = []domain.LoginEvent{}
+	}
+
+	return &domain.LoginHistoryResponse{
+		Logins:     logins,
+		Total:      total,
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		TotalPages: int((total + int64(pagination.PageSize) - 1) / int64(pagination.PageSize)),
+	}, nil
+}
@@ -0,0 +1,888 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// ProductRepository implements domain.ProductRepository in memory. Locations are not
+// stored, so location filters match no product.
+type ProductRepository struct {
+	store *Store
+}
+
+var _ domain.ProductRepository = (*ProductRepository)(nil)
+
+// NewProductRepository creates a product repository on the store
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{store: store}
+}
+
+// Create adds a product with the defaults of the products table
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.insert(product)
+	return nil
+}
+
+// insert adds a product; r.store.mu must be held
+func (r *ProductRepository) insert(product *domain.Product) {
+	if product.ID == uuid.Nil {
+		product.ID = uuid.New()
+	}
+	now := r.store.now()
+	if product.CreatedAt.IsZero() {
+		product.CreatedAt = now
+	}
+	product.UpdatedAt = now
+	if product.Attributes == nil {
+		product.Attributes = domain.Attributes{}
+	}
+	if product.Status == "" {
+		product.Status = domain.ProductStatusPublished
+	}
+	product.Version = 1
+	product.ReservedStock, product.RatingAverage, product.RatingCount = 0, 0, 0
+
+	stored := copyProduct(*product)
+	stored.User = domain.User{}
+	r.store.products[product.ID] = stored
+}
+
+// GetByID retrieves a product by ID with user information
+func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	product, ok := r.store.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	product = r.store.withUser(product)
+	return &product, nil
+}
+
+// GetAll retrieves all products
+func (r *ProductRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
+	return r.find(func(domain.Product) bool { return true }), nil
+}
+
+// Update stores a product, keeping the columns only the database changes
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.products[product.ID]
+	if !ok {
+		r.insert(product)
+		return nil
+	}
+
+	updated := copyProduct(*product)
+	updated.User = domain.User{}
+	updated.ReservedStock = stored.ReservedStock
+	updated.RatingAverage, updated.RatingCount = stored.RatingAverage, stored.RatingCount
+	updated.Version = stored.Version + 1
+	updated.UpdatedAt = r.store.now()
+	r.store.products[product.ID] = updated
+
+	product.UpdatedAt, product.Version = updated.UpdatedAt, updated.Version
+	return nil
+}
+
+// Delete deletes a product by ID
+func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.products, id)
+	return nil
+}
+
+// GetByUserID retrieves all products for a specific user
+func (r *ProductRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Product, error) {
+	return r.find(func(product domain.Product) bool { return product.UserID == userID }), nil
+}
+
+// GetActiveByUserID retrieves all products of a user that are not archived
+func (r *ProductRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Product, error) {
+	return r.find(func(product domain.Product) bool {
+		return product.UserID == userID && product.ArchivedAt == nil
+	}), nil
+}
+
+// GetPublicByUserID retrieves a page of a user's public, published products that
+// are not archived, newest first
+func (r *ProductRepository) GetPublicByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]domain.Product, int64, error) {
+	products := r.find(func(product domain.Product) bool {
+		return product.UserID == userID && product.Public && product.Status == domain.ProductStatusPublished && product.ArchivedAt == nil
+	})
+	sortBy(products, func(a, b domain.Product) bool { return a.CreatedAt.After(b.CreatedAt) })
+	return window(products, offset, limit), int64(len(products)), nil
+}
+
+// GetProductsWithFilters retrieves products with filtering, sorting, and pagination.
+// Estimated totals are exact.
+func (r *ProductRepository) GetProductsWithFilters(ctx context.Context, userID uuid.UUID, query domain.ProductQuery) (*domain.ProductListResponse, error) {
+	products := r.filter(userID, query.Filter, query.Sort)
+	total := int64(len(products))
+
+	page := window(products, (query.Pagination.Page-1)*query.Pagination.PageSize, query.Pagination.PageSize)
+	response := &domain.ProductListResponse{
+		Products: page,
+		Page:     query.Pagination.Page,
+		PageSize: query.Pagination.PageSize,
+		HasPrev:  query.Pagination.Page > 1,
+		HasNext:  int64(query.Pagination.Page*query.Pagination.PageSize) < total,
+	}
+
+	switch query.Total {
+	case domain.TotalNone:
+	case domain.TotalEstimate:
+		response.TotalEstimate = &total
+	default:
+		totalPages := int((total + int64(query.Pagination.PageSize) - 1) / int64(query.Pagination.PageSize))
+		response.Total = &total
+		response.TotalPages = &totalPages
+	}
+	return response, nil
+}
+
+// StreamProductsWithFilters calls yield with each product matching the query's
+// filters, in its order; pagination is ignored
+func (r *ProductRepository) StreamProductsWithFilters(ctx context.Context, userID uuid.UUID, query domain.ProductQuery, yield func(*domain.Product) error) error {
+	for _, product := range r.filter(userID, query.Filter, query.Sort) {
+		if err := yield(&product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetProductsWithCursor retrieves products with cursor-based pagination. Cursors
+// hold the ID of the product a page ends with.
+func (r *ProductRepository) GetProductsWithCursor(ctx context.Context, userID uuid.UUID, query domain.ProductQueryCursor) (*domain.ProductListCursorResponse, error) {
+	products := r.filter(userID, query.Filter, query.Sort)
+
+	start := 0
+	if query.Pagination.Cursor != nil {
+		id, err := decodeCursor(*query.Pagination.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		start = -1
+		for i, product := range products {
+			if product.ID == id {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("%w: its product is no longer listed", domain.ErrInvalidCursor)
+		}
+	}
+
+	page := window(products, start, query.Pagination.PageSize)
+	response := &domain.ProductListCursorResponse{
+		Products: page,
+		HasNext:  start+len(page) < len(products),
+		HasPrev:  query.Pagination.Cursor != nil,
+	}
+	if len(page) > 0 {
+		next := encodeCursor(page[len(page)-1].ID)
+		response.NextCursor = &next
+		if query.Pagination.Cursor != nil {
+			prev := encodeCursor(page[0].ID)
+			response.PrevCursor = &prev
+		}
+	}
+	return response, nil
+}
+
+// GetLowStock retrieves up to limit of a user's products whose stock is below their
+// low-stock threshold, lowest stock first, leaving out archived products. Stock
+// templates and locations are not stored, so they are not taken into account.
+func (r *ProductRepository) GetLowStock(ctx context.Context, userID uuid.UUID, locationID *uuid.UUID, limit int) ([]domain.Product, error) {
+	if locationID != nil {
+		return []domain.Product{}, nil
+	}
+
+	r.store.mu.RLock()
+	products := []domain.Product{}
+	for _, product := range r.store.products {
+		if product.UserID == userID && product.ArchivedAt == nil && product.Stock < r.store.lowStockThreshold(product) {
+			products = append(products, r.store.withUser(product))
+		}
+	}
+	r.store.mu.RUnlock()
+
+	sortBy(products, func(a, b domain.Product) bool {
+		if a.Stock != b.Stock {
+			return a.Stock < b.Stock
+		}
+		return a.ID.String() < b.ID.String()
+	})
+	return window(products, 0, limit), nil
+}
+
+// GetRelated retrieves up to limit of the owner's other products that are not
+// archived, ranked like the database repository: one point per shared attribute
+// value plus up to one point for a close price
+func (r *ProductRepository) GetRelated(ctx context.Context, product *domain.Product, limit int) ([]domain.RelatedProduct, error) {
+	candidates := r.find(func(candidate domain.Product) bool {
+		return candidate.UserID == product.UserID && candidate.ID != product.ID && candidate.ArchivedAt == nil
+	})
+
+	related := make([]domain.RelatedProduct, 0, len(candidates))
+	for _, candidate := range candidates {
+		score := 0.0
+		for key, value := range candidate.Attributes {
+			if other, ok := product.Attributes[key]; ok && sameJSON(value, other) {
+				score++
+			}
+		}
+		score += 1 - math.Min(math.Abs(candidate.Price-product.Price)/math.Max(math.Max(candidate.Price, product.Price), 0.01), 1)
+		related = append(related, domain.RelatedProduct{Product: candidate, Score: score})
+	}
+
+	sortBy(related, func(a, b domain.RelatedProduct) bool {
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Product.ID.String() < b.Product.ID.String()
+	})
+	return window(related, 0, limit), nil
+}
+
+// GetByIDs retrieves the products with the given IDs, with their owners
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return r.find(func(product domain.Product) bool {
+		return wanted[product.ID]
+	}), nil
+}
+
+// GetIDByCode returns the ID of the user's product with the given code, or nil when there is none
+func (r *ProductRepository) GetIDByCode(ctx context.Context, userID uuid.UUID, code string) (*uuid.UUID, error) {
+	products := r.find(func(product domain.Product) bool {
+		return product.UserID == userID && product.Code != nil && *product.Code == code
+	})
+	if len(products) == 0 {
+		return nil, nil
+	}
+	return &products[0].ID, nil
+}
+
+// SuggestNames returns up to limit distinct names of the user's products that are
+// not archived matching a query, names starting with it first. Queries shorter than
+// a trigram only match the start of names.
+func (r *ProductRepository) SuggestNames(ctx context.Context, userID uuid.UUID, query string, limit int) ([]string, error) {
+	query = strings.ToLower(query)
+	contains := len([]rune(query)) >= domain.MinContainsSuggestionQuery
+
+	seen := map[string]bool{}
+	var names []string
+	products, _ := r.GetActiveByUserID(ctx, userID)
+	for _, product := range products {
+		name := strings.ToLower(product.Name)
+		if seen[product.Name] || !strings.HasPrefix(name, query) && !(contains && strings.Contains(name, query)) {
+			continue
+		}
+		seen[product.Name] = true
+		names = append(names, product.Name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		iPrefix := strings.HasPrefix(strings.ToLower(names[i]), query)
+		jPrefix := strings.HasPrefix(strings.ToLower(names[j]), query)
+		if iPrefix != jPrefix {
+			return iPrefix
+		}
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > limit {
+		names = names[:limit]
+	}
+	return names, nil
+}
+
+// CountByUserID counts the products owned by a user
+func (r *ProductRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	products, _ := r.GetByUserID(ctx, userID)
+	return int64(len(products)), nil
+}
+
+// FirstCreatedAt returns when the user created their first product, or nil if they have none
+func (r *ProductRepository) FirstCreatedAt(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	products, _ := r.GetByUserID(ctx, userID)
+	var first *time.Time
+	for i := range products {
+		if first == nil || products[i].CreatedAt.Before(*first) {
+			first = &products[i].CreatedAt
+		}
+	}
+	return first, nil
+}
+
+// GetProductStats retrieves statistics of a user's products that are not archived
+func (r *ProductRepository) GetProductStats(ctx context.Context, userID uuid.UUID) (*domain.ProductStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stats := &domain.ProductStats{}
+	var prices, values []float64
+	for _, product := range r.store.products {
+		if product.UserID != userID || product.ArchivedAt != nil {
+			continue
+		}
+		value := product.Price * float64(product.Stock)
+		stats.TotalProducts++
+		stats.TotalValue += value
+		if product.Stock < r.store.lowStockThreshold(product) {
+			stats.LowStock++
+		}
+		if product.Stock == 0 {
+			stats.OutOfStock++
+		}
+		prices = append(prices, product.Price)
+		values = append(values, value)
+	}
+	if stats.TotalProducts == 0 {
+		return stats, nil
+	}
+
+	sum := 0.0
+	for _, price := range prices {
+		sum += price
+	}
+	stats.AvgPrice = sum / float64(len(prices))
+	stats.MedianPrice = percentile(prices, 0.5)
+	stats.StockValuePercentiles = domain.StockValuePercentiles{
+		P25: percentile(values, 0.25),
+		P50: percentile(values, 0.5),
+		P75: percentile(values, 0.75),
+		P90: percentile(values, 0.9),
+	}
+	return stats, nil
+}
+
+// GetStatsSeries returns the number or stock value of the user's products that are
+// not archived per bucket of creation dates, between from and to
+func (r *ProductRepository) GetStatsSeries(ctx context.Context, userID uuid.UUID, metric, interval string, from, to time.Time) (map[time.Time]float64, error) {
+	products := r.find(func(product domain.Product) bool {
+		return product.UserID == userID && product.ArchivedAt == nil && !product.CreatedAt.Before(from) && product.CreatedAt.Before(to)
+	})
+
+	series := make(map[time.Time]float64)
+	for _, product := range products {
+		bucket := domain.TruncateToBucket(product.CreatedAt.UTC(), interval)
+		if metric == domain.StatsMetricValue {
+			series[bucket] += product.Price * float64(product.Stock)
+		} else {
+			series[bucket]++
+		}
+	}
+	return series, nil
+}
+
+// SetPublic publishes or unpublishes a product
+func (r *ProductRepository) SetPublic(ctx context.Context, id uuid.UUID, public bool) error {
+	return r.update(id, func(product *domain.Product) error {
+		product.Public = public
+		return nil
+	})
+}
+
+// SetStatus sets the status of a product
+func (r *ProductRepository) SetStatus(ctx context.Context, id uuid.UUID, status string) error {
+	return r.update(id, func(product *domain.Product) error {
+		product.Status = status
+		return nil
+	})
+}
+
+// RevokeStockTokens records that the stock tokens of a product issued up to revokedAt are revoked
+func (r *ProductRepository) RevokeStockTokens(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	return r.update(id, func(product *domain.Product) error {
+		product.StockTokensRevokedAt = &revokedAt
+		return nil
+	})
+}
+
+// GetStockTokensRevokedAt returns when the stock tokens of a product were last revoked
+func (r *ProductRepository) GetStockTokensRevokedAt(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	product, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return product.StockTokensRevokedAt, nil
+}
+
+// SetArchived archives a product at archivedAt, or unarchives it when archivedAt is nil
+func (r *ProductRepository) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	return r.update(id, func(product *domain.Product) error {
+		product.ArchivedAt = archivedAt
+		return nil
+	})
+}
+
+// UpsertForUser inserts or updates products. Existing products are only
+// overwritten when they belong to the same user.
+func (r *ProductRepository) UpsertForUser(ctx context.Context, userID uuid.UUID, products []domain.Product) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range products {
+		products[i].UserID = userID
+		stored, ok := r.store.products[products[i].ID]
+		if !ok {
+			r.insert(&products[i])
+			continue
+		}
+		if stored.UserID != userID {
+			continue
+		}
+
+		upserted := products[i]
+		stored.Name, stored.Description, stored.DescriptionHTML = upserted.Name, upserted.Description, upserted.DescriptionHTML
+		stored.Price, stored.Stock, stored.Public = upserted.Price, upserted.Stock, upserted.Public
+		stored.LowStockThreshold, stored.ReorderQuantity = upserted.LowStockThreshold, upserted.ReorderQuantity
+		stored.Attributes = copyProduct(upserted).Attributes
+		stored.UpdatedAt = r.store.now()
+		stored.Version++
+		r.store.products[stored.ID] = stored
+	}
+	return nil
+}
+
+// UpdateColumns writes only the named columns of a product, refusing a new stock
+// below its reserved stock
+func (r *ProductRepository) UpdateColumns(ctx context.Context, product *domain.Product, columns []string) error {
+	return r.update(product.ID, func(stored *domain.Product) error {
+		for _, column := range columns {
+			switch column {
+			case "name":
+				stored.Name = product.Name
+			case "description":
+				stored.Description = product.Description
+			case "description_html":
+				stored.DescriptionHTML = product.DescriptionHTML
+			case "price":
+				stored.Price = product.Price
+			case "stock":
+				if product.Stock < stored.ReservedStock {
+					return domain.InsufficientStockError(*stored)
+				}
+				stored.Stock = product.Stock
+			case "attributes":
+				stored.Attributes = copyProduct(*product).Attributes
+			case "low_stock_threshold":
+				stored.LowStockThreshold = product.LowStockThreshold
+			case "reorder_quantity":
+				stored.ReorderQuantity = product.ReorderQuantity
+			}
+		}
+		return nil
+	})
+}
+
+// AdjustStock adds delta to a product's stock, refusing to go below zero or below
+// its reserved stock
+func (r *ProductRepository) AdjustStock(ctx context.Context, id uuid.UUID, delta int) (int, error) {
+	var stock int
+	err := r.update(id, func(product *domain.Product) error {
+		if product.Stock+delta < product.ReservedStock || product.Stock+delta < 0 {
+			if product.ReservedStock > 0 {
+				return domain.InsufficientStockError(*product)
+			}
+			return domain.StockConflictError(product.Stock, product.UpdatedAt)
+		}
+		product.Stock += delta
+		stock = product.Stock
+		return nil
+	})
+	return stock, err
+}
+
+// DecrementStock takes quantity units out of a product's available stock and
+// returns the product's new stock levels
+func (r *ProductRepository) DecrementStock(ctx context.Context, id uuid.UUID, quantity int) (*domain.Product, error) {
+	var levels *domain.Product
+	err := r.update(id, func(product *domain.Product) error {
+		if product.AvailableStock() < quantity {
+			return domain.InsufficientStockError(*product)
+		}
+		product.Stock -= quantity
+		levels = &domain.Product{ID: product.ID, Stock: product.Stock, ReservedStock: product.ReservedStock}
+		return nil
+	})
+	if levels != nil {
+		levels.UpdatedAt = r.store.now()
+	}
+	return levels, err
+}
+
+// Reserve holds quantity units of a product's available stock
+func (r *ProductRepository) Reserve(ctx context.Context, id uuid.UUID, quantity int) error {
+	return r.update(id, func(product *domain.Product) error {
+		if product.AvailableStock() < quantity {
+			return domain.InsufficientStockError(*product)
+		}
+		product.ReservedStock += quantity
+		return nil
+	})
+}
+
+// ReleaseReserved gives quantity held units of a product back to its available stock.
+// Products deleted since are skipped.
+func (r *ProductRepository) ReleaseReserved(ctx context.Context, id uuid.UUID, quantity int) error {
+	err := r.update(id, func(product *domain.Product) error {
+		product.ReservedStock = max(product.ReservedStock-quantity, 0)
+		return nil
+	})
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return nil
+	}
+	return err
+}
+
+// ConsumeReserved takes quantity held units of a product out of its stock and
+// returns the new stock
+func (r *ProductRepository) ConsumeReserved(ctx context.Context, id uuid.UUID, quantity int) (int, error) {
+	var stock int
+	err := r.update(id, func(product *domain.Product) error {
+		if product.Stock < quantity {
+			return domain.StockConflictError(product.Stock, product.UpdatedAt)
+		}
+		product.Stock -= quantity
+		product.ReservedStock = max(product.ReservedStock-quantity, 0)
+		stock = product.Stock
+		return nil
+	})
+	return stock, err
+}
+
+// ReserveProductCodes hands out count consecutive product codes to the user if they
+// enabled product codes, returning the number of the first, or 0 when disabled
+func (r *ProductRepository) ReserveProductCodes(ctx context.Context, userID uuid.UUID, count int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userID]
+	if !ok || !user.Preferences.ProductCodes {
+		return 0, nil
+	}
+	first := user.ProductCodeSeq + 1
+	user.ProductCodeSeq += int64(count)
+	r.store.users[userID] = user
+	return first, nil
+}
+
+// AssignMissingCodes gives the user's products without a code one, oldest first,
+// and returns how many were assigned
+func (r *ProductRepository) AssignMissingCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var missing []domain.Product
+	for _, product := range r.store.products {
+		if product.UserID == userID && product.Code == nil {
+			missing = append(missing, product)
+		}
+	}
+	sortBy(missing, func(a, b domain.Product) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+
+	user := r.store.users[userID]
+	for _, product := range missing {
+		user.ProductCodeSeq++
+		code := domain.EncodeProductCode(user.ProductCodeSeq)
+		product.Code = &code
+		r.store.products[product.ID] = product
+	}
+	if len(missing) > 0 {
+		r.store.users[userID] = user
+	}
+	return len(missing), nil
+}
+
+// find returns copies of the products match selects, with their owners, by ID
+func (r *ProductRepository) find(match func(domain.Product) bool) []domain.Product {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	products := []domain.Product{}
+	for _, product := range r.store.products {
+		if match(product) {
+			products = append(products, r.store.withUser(product))
+		}
+	}
+	sortBy(products, func(a, b domain.Product) bool { return a.ID.String() < b.ID.String() })
+	return products
+}
+
+// update changes a stored product, bumping its version and update time unless
+// change fails
+func (r *ProductRepository) update(id uuid.UUID, change func(*domain.Product) error) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	product, ok := r.store.products[id]
+	if !ok {
+		return domain.ErrProductNotFound
+	}
+	if err := change(&product); err != nil {
+		return err
+	}
+	product.Version++
+	product.UpdatedAt = r.store.now()
+	r.store.products[id] = product
+	return nil
+}
+
+// filter returns the user's products matching filter, in the order of sortFields:
+// by default newest first, with ties broken by ID
+func (r *ProductRepository) filter(userID uuid.UUID, filter domain.ProductFilter, sortFields []domain.SortField) []domain.Product {
+	products := r.find(func(product domain.Product) bool {
+		return product.UserID == userID && matches(product, filter) &&
+			(len(filter.StockStatuses) == 0 || slices.Contains(filter.StockStatuses, domain.StockStatusOf(product.Stock, r.store.lowStockThreshold(product))))
+	})
+
+	keys := sortKeys(sortFields)
+	sort.SliceStable(products, func(i, j int) bool {
+		for _, key := range keys {
+			if c := compareKey(products[i], products[j], key); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return products
+}
+
+// matches reports whether a product matches a filter, like the database repository's filters
+func matches(product domain.Product, filter domain.ProductFilter) bool {
+	switch filter.Status {
+	case domain.ProductStatusArchived:
+		if product.ArchivedAt == nil {
+			return false
+		}
+	case domain.ProductStatusDraft, domain.ProductStatusPublished:
+		if product.Status != filter.Status || product.ArchivedAt != nil {
+			return false
+		}
+	default:
+		if product.ArchivedAt != nil {
+			return false
+		}
+	}
+
+	switch {
+	case filter.Name != nil && *filter.Name != "" && !strings.Contains(strings.ToLower(product.Name), strings.ToLower(*filter.Name)):
+		return false
+	case filter.Code != nil && (product.Code == nil || *product.Code != *filter.Code):
+		return false
+	case filter.MinPrice != nil && product.Price < *filter.MinPrice,
+		filter.MaxPrice != nil && product.Price > *filter.MaxPrice:
+		return false
+	case filter.MinStock != nil && product.Stock < *filter.MinStock,
+		filter.MaxStock != nil && product.Stock > *filter.MaxStock:
+		return false
+	case len(filter.PriceBuckets) > 0 && !inPriceBucket(product.Price, filter.PriceBuckets):
+		return false
+	case filter.CreatedFrom != nil && product.CreatedAt.Before(*filter.CreatedFrom),
+		filter.CreatedTo != nil && product.CreatedAt.After(*filter.CreatedTo),
+		filter.UpdatedFrom != nil && product.UpdatedAt.Before(*filter.UpdatedFrom),
+		filter.UpdatedTo != nil && product.UpdatedAt.After(*filter.UpdatedTo):
+		return false
+	case filter.LocationID != nil:
+		return false
+	}
+
+	for key, value := range filter.Attributes {
+		if actual, ok := product.Attributes[key]; !ok || !sameJSON(actual, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// inPriceBucket reports whether a price falls in any of the named price buckets;
+// like the database repository, unknown names are ignored
+func inPriceBucket(price float64, names []string) bool {
+	known := false
+	for _, name := range names {
+		if bucket, ok := domain.PriceBucketByName(name); ok {
+			if bucket.Contains(price) {
+				return true
+			}
+			known = true
+		}
+	}
+	return !known
+}
+
+// sortKey is a field products are sorted by, its direction, ASC or DESC, and
+// whether products without a value of a nullable field come first
+type sortKey struct {
+	Field      string
+	Direction  string
+	NullsFirst bool
+}
+
+// sortKeys returns the ordering of sortFields like the database repository: the
+// valid fields, or newest first, followed by the ID
+func sortKeys(sortFields []domain.SortField) []sortKey {
+	var keys []sortKey
+	seen := make(map[string]bool, len(sortFields))
+	for _, sortField := range sortFields {
+		if _, ok := domain.ProductSortField(sortField.Field); !ok || seen[sortField.Field] || len(keys) == domain.MaxSortFields {
+			continue
+		}
+		seen[sortField.Field] = true
+
+		key := sortKey{Field: sortField.Field, Direction: strings.ToUpper(sortField.Direction)}
+		if key.Direction != "DESC" {
+			key.Direction = "ASC"
+		}
+		key.NullsFirst = sortField.Nulls == domain.SortNullsFirst || sortField.Nulls != domain.SortNullsLast && key.Direction == "DESC"
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		keys = []sortKey{{Field: "created_at", Direction: "DESC"}}
+	}
+	return append(keys, sortKey{Field: "id", Direction: "ASC"})
+}
+
+// compareKey compares two products in the order of a sort key
+func compareKey(a, b domain.Product, key sortKey) int {
+	aNull, bNull := a.Code == nil && key.Field == "code", b.Code == nil && key.Field == "code"
+	switch {
+	case aNull && bNull:
+		return 0
+	case aNull:
+		if key.NullsFirst {
+			return -1
+		}
+		return 1
+	case bNull:
+		if key.NullsFirst {
+			return 1
+		}
+		return -1
+	}
+
+	c := compareField(a, b, key.Field)
+	if key.Direction == "DESC" {
+		return -c
+	}
+	return c
+}
+
+// compareField compares two products by a sort field
+func compareField(a, b domain.Product, field string) int {
+	switch field {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "price":
+		return compareOrdered(a.Price, b.Price)
+	case "stock":
+		return compareOrdered(a.Stock, b.Stock)
+	case "stock_value":
+		return compareOrdered(a.Price*float64(a.Stock), b.Price*float64(b.Stock))
+	case "code":
+		return strings.Compare(*a.Code, *b.Code)
+	case "created_at":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return bytes.Compare(a.ID[:], b.ID[:])
+	}
+}
+
+// compareOrdered compares two numbers
+func compareOrdered[T int | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sameJSON reports whether two attribute values encode to the same JSON
+func sameJSON(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+// percentile interpolates the fraction percentile of values like percentile_cont
+func percentile(values []float64, fraction float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	position := fraction * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (position-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// window returns up to limit items from offset on
+func window[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// encodeCursor encodes a cursor pointing after the product with the given ID
+func encodeCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+// decodeCursor decodes a cursor into the ID of the product it points after
+func decodeCursor(encoded string) (uuid.UUID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: malformed", domain.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(data)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: malformed", domain.ErrInvalidCursor)
+	}
+	return id, nil
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// newProducts returns a product repository holding the given products of one user
+func newProducts(t *testing.T, products ...domain.Product) (*ProductRepository, uuid.UUID) {
+	t.Helper()
+	store := NewStore()
+	userID := uuid.New()
+	if err := NewUserRepository(store).Create(context.Background(), &domain.User{ID: userID, Email: "owner@example.com"}); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+
+	repo := NewProductRepository(store)
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range products {
+		products[i].UserID = userID
+		products[i].CreatedAt = created.Add(time.Duration(i) * time.Hour)
+		if err := repo.Create(context.Background(), &products[i]); err != nil {
+			t.Fatalf("Create product: %v", err)
+		}
+	}
+	return repo, userID
+}
+
+func TestProductRepository_Filters(t *testing.T) {
+	archived := time.Now()
+	repo, userID := newProducts(t,
+		domain.Product{Name: "Desk lamp", Price: 30, Stock: 5, Attributes: domain.Attributes{"color": "red"}},
+		domain.Product{Name: "Floor lamp", Price: 80, Stock: 0, Attributes: domain.Attributes{"color": "blue"}},
+		domain.Product{Name: "Chair", Price: 45, Stock: 12},
+		domain.Product{Name: "Old lamp", Price: 10, Stock: 1, ArchivedAt: &archived},
+	)
+	ctx := context.Background()
+
+	minPrice := 20.0
+	name := "LAMP"
+	response, err := repo.GetProductsWithFilters(ctx, userID, domain.ProductQuery{
+		Filter:     domain.ProductFilter{Name: &name, MinPrice: &minPrice},
+		Sort:       []domain.SortField{{Field: "price", Direction: "desc"}},
+		Pagination: domain.Pagination{Page: 1, PageSize: 10},
+	})
+	if err != nil {
+		t.Fatalf("GetProductsWithFilters: %v", err)
+	}
+	if *response.Total != 2 || response.Products[0].Name != "Floor lamp" || response.Products[1].Name != "Desk lamp" {
+		t.Errorf("Expected both lamps by price, got %+v", response.Products)
+	}
+	if response.Products[0].User.Email != "owner@example.com" {
+		t.Errorf("Expected the owner loaded, got %+v", response.Products[0].User)
+	}
+
+	response, _ = repo.GetProductsWithFilters(ctx, userID, domain.ProductQuery{
+		Filter:     domain.ProductFilter{Attributes: domain.Attributes{"color": "red"}},
+		Pagination: domain.Pagination{Page: 1, PageSize: 10},
+	})
+	if len(response.Products) != 1 || response.Products[0].Name != "Desk lamp" {
+		t.Errorf("Expected the red lamp, got %+v", response.Products)
+	}
+
+	response, _ = repo.GetProductsWithFilters(ctx, userID, domain.ProductQuery{
+		Filter:     domain.ProductFilter{Status: domain.ProductStatusArchived},
+		Pagination: domain.Pagination{Page: 1, PageSize: 10},
+	})
+	if len(response.Products) != 1 || response.Products[0].Name != "Old lamp" {
+		t.Errorf("Expected the archived lamp, got %+v", response.Products)
+	}
+}
+
+func TestProductRepository_StockStatusAndPriceBucketFilters(t *testing.T) {
+	repo, userID := newProducts(t,
+		domain.Product{Name: "Pen", Price: 2, Stock: 0},
+		domain.Product{Name: "Lamp", Price: 20, Stock: 3},
+		domain.Product{Name: "Desk", Price: 150, Stock: 12},
+		domain.Product{Name: "Chair", Price: 99.99, Stock: 40},
+	)
+	ctx := context.Background()
+
+	list := func(filter domain.ProductFilter) []string {
+		response, err := repo.GetProductsWithFilters(ctx, userID, domain.ProductQuery{
+			Filter:     filter,
+			Sort:       []domain.SortField{{Field: "name", Direction: "asc"}},
+			Pagination: domain.Pagination{Page: 1, PageSize: 10},
+		})
+		if err != nil {
+			t.Fatalf("GetProductsWithFilters: %v", err)
+		}
+		names := []string{}
+		for _, product := range response.Products {
+			names = append(names, product.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		filter   domain.ProductFilter
+		expected string
+	}{
+		// below the default threshold of 10
+		{"low", domain.ProductFilter{StockStatuses: []string{domain.StockStatusLow}}, "[Lamp]"},
+		{"low or out", domain.ProductFilter{StockStatuses: []string{domain.StockStatusLow, domain.StockStatusOut}}, "[Lamp Pen]"},
+		{"in stock", domain.ProductFilter{StockStatuses: []string{domain.StockStatusInStock}}, "[Chair Desk]"},
+		{"mid includes its minimum", domain.ProductFilter{PriceBuckets: []string{"mid"}}, "[Chair Lamp]"},
+		{"budget or premium", domain.ProductFilter{PriceBuckets: []string{"budget", "premium"}}, "[Desk Pen]"},
+		{"both", domain.ProductFilter{StockStatuses: []string{domain.StockStatusInStock}, PriceBuckets: []string{"mid"}}, "[Chair]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if names := fmt.Sprint(list(tt.filter)); names != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestProductRepository_Cursor(t *testing.T) {
+	repo, userID := newProducts(t,
+		domain.Product{Name: "A"}, domain.Product{Name: "B"}, domain.Product{Name: "C"},
+	)
+	ctx := context.Background()
+
+	query := domain.ProductQueryCursor{Pagination: domain.CursorPagination{PageSize: 2}}
+	var names []string
+	for page := 0; page < 3; page++ {
+		response, err := repo.GetProductsWithCursor(ctx, userID, query)
+		if err != nil {
+			t.Fatalf("GetProductsWithCursor: %v", err)
+		}
+		for _, product := range response.Products {
+			names = append(names, product.Name)
+		}
+		if !response.HasNext {
+			break
+		}
+		query.Pagination.Cursor = response.NextCursor
+	}
+
+	// newest first by default
+	if len(names) != 3 || names[0] != "C" || names[1] != "B" || names[2] != "A" {
+		t.Errorf("Expected C, B, A, got %v", names)
+	}
+
+	malformed := "not a cursor"
+	query.Pagination.Cursor = &malformed
+	if _, err := repo.GetProductsWithCursor(ctx, userID, query); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestProductRepository_Stock(t *testing.T) {
+	repo, _ := newProducts(t, domain.Product{Name: "Lamp", Stock: 5})
+	products, _ := repo.GetAll(context.Background())
+	id := products[0].ID
+	ctx := context.Background()
+
+	if err := repo.Reserve(ctx, id, 3); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if _, err := repo.DecrementStock(ctx, id, 3); err == nil {
+		t.Error("Expected reserved units to be unavailable")
+	}
+	if _, err := repo.AdjustStock(ctx, id, -3); err == nil {
+		t.Error("Expected stock to stay above the reserved stock")
+	}
+
+	stock, err := repo.ConsumeReserved(ctx, id, 3)
+	if err != nil || stock != 2 {
+		t.Fatalf("Expected stock 2 after consuming the reservation, got %d, %v", stock, err)
+	}
+	product, _ := repo.GetByID(ctx, id)
+	if product.ReservedStock != 0 || product.Version != 3 {
+		t.Errorf("Expected no reserved stock at version 3, got %d at version %d", product.ReservedStock, product.Version)
+	}
+}
+
+func TestProductRepository_Stats(t *testing.T) {
+	repo, userID := newProducts(t,
+		domain.Product{Price: 10, Stock: 1},
+		domain.Product{Price: 20, Stock: 0},
+		domain.Product{Price: 30, Stock: 20},
+		domain.Product{Price: 40, Stock: 10},
+	)
+
+	stats, err := repo.GetProductStats(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetProductStats: %v", err)
+	}
+	if stats.TotalProducts != 4 || stats.TotalValue != 1010 || stats.AvgPrice != 25 || stats.MedianPrice != 25 {
+		t.Errorf("Unexpected totals %+v", stats)
+	}
+	// below the default threshold of 10
+	if stats.LowStock != 2 || stats.OutOfStock != 1 {
+		t.Errorf("Expected 2 low-stock and 1 out-of-stock products, got %+v", stats)
+	}
+	// stock values 0, 10, 400, 600
+	if stats.StockValuePercentiles.P50 != 205 || stats.StockValuePercentiles.P90 != 540 {
+		t.Errorf("Unexpected percentiles %+v", stats.StockValuePercentiles)
+	}
+}
+
+func TestProductRepository_SuggestNames(t *testing.T) {
+	archived := time.Now()
+	repo, userID := newProducts(t,
+		domain.Product{Name: "Table lamp"},
+		domain.Product{Name: "Lamp shade"},
+		domain.Product{Name: "Lamp"},
+		domain.Product{Name: "Lamp"},
+		domain.Product{Name: "Lampoon", ArchivedAt: &archived},
+		domain.Product{Name: "Clamp"},
+	)
+	ctx := context.Background()
+
+	names, _ := repo.SuggestNames(ctx, userID, "LAM", 10)
+	want := []string{"Lamp", "Lamp shade", "Clamp", "Table lamp"}
+	if len(names) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, names)
+		}
+	}
+
+	if names, _ := repo.SuggestNames(ctx, userID, "la", 10); len(names) != 2 {
+		t.Errorf("Expected short queries to match name prefixes only, got %v", names)
+	}
+	if names, _ := repo.SuggestNames(ctx, userID, "lam", 1); len(names) != 1 || names[0] != "Lamp" {
+		t.Errorf("Expected the best suggestion only, got %v", names)
+	}
+}
@@ -0,0 +1,65 @@
+// Package memory implements the domain repositories in process memory, for tests
+// and demos that run without Postgres. Entities are copied in and out, so callers
+// never share state with the store.
+package memory
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// errNotFound matches the error of the database repositories' generic GetByID
+var errNotFound = errors.New("entity not found")
+
+// Store holds the users and products the repositories of one store share
+type Store struct {
+	mu       sync.RWMutex
+	users    map[uuid.UUID]domain.User
+	products map[uuid.UUID]domain.Product
+	now      func() time.Time
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{
+		users:    make(map[uuid.UUID]domain.User),
+		products: make(map[uuid.UUID]domain.Product),
+		now:      time.Now,
+	}
+}
+
+// copyProduct returns a copy of product not sharing its attributes
+func copyProduct(product domain.Product) domain.Product {
+	if product.Attributes != nil {
+		attributes := make(domain.Attributes, len(product.Attributes))
+		for key, value := range product.Attributes {
+			attributes[key] = value
+		}
+		product.Attributes = attributes
+	}
+	return product
+}
+
+// withUser returns a copy of product with its owner loaded, like the database
+// repositories preloading User; s.mu must be held
+func (s *Store) withUser(product domain.Product) domain.Product {
+	product = copyProduct(product)
+	product.User = s.users[product.UserID]
+	return product
+}
+
+// lowStockThreshold resolves the low-stock threshold of a product like
+// domain.ResolveStockLevels, without stock templates; s.mu must be held
+func (s *Store) lowStockThreshold(product domain.Product) int {
+	return domain.ResolveStockLevels(&product, nil, s.users[product.UserID].Preferences.LowStockThreshold).LowStockThreshold
+}
+
+// sortBy sorts items stably by less
+func sortBy[T any](items []T, less func(a, b T) bool) {
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+}
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// UserRepository implements domain.UserRepository in memory
+type UserRepository struct {
+	store *Store
+}
+
+var _ domain.UserRepository = (*UserRepository)(nil)
+
+// NewUserRepository creates a user repository on the store
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// Create adds a user, refusing an email or slug already in use
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if domain.NormalizeEmail(existing.Email) == domain.NormalizeEmail(user.Email) {
+			return domain.ErrUserExists
+		}
+		if user.Slug != nil && existing.Slug != nil && *existing.Slug == *user.Slug {
+			return errors.New("duplicate slug")
+		}
+	}
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	now := r.store.now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+	if user.Plan == "" {
+		user.Plan = "free"
+	}
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &user, nil
+}
+
+// GetAll retrieves all users
+func (r *UserRepository) GetAll(ctx context.Context) ([]domain.User, error) {
+	return r.find(func(domain.User) bool { return true }), nil
+}
+
+// Update stores a user
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	return r.update(user.ID, func(stored *domain.User) {
+		*stored = *user
+	})
+}
+
+// Delete deletes a user by ID
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.users, id)
+	return nil
+}
+
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	email = domain.NormalizeEmail(email)
+	return r.first(func(user domain.User) bool { return domain.NormalizeEmail(user.Email) == email })
+}
+
+// GetBySlug retrieves a user by public catalog slug
+func (r *UserRepository) GetBySlug(ctx context.Context, slug string) (*domain.User, error) {
+	return r.first(func(user domain.User) bool { return user.Slug != nil && *user.Slug == slug })
+}
+
+// GetScheduledForDeletion retrieves users whose deletion is due at or before the given time
+func (r *UserRepository) GetScheduledForDeletion(ctx context.Context, before time.Time) ([]domain.User, error) {
+	return r.find(func(user domain.User) bool {
+		return user.DeletionScheduledAt != nil && !user.DeletionScheduledAt.After(before)
+	}), nil
+}
+
+// MarkEmailVerified records that a user confirmed their email address
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	return r.update(id, func(user *domain.User) {
+		if user.EmailVerifiedAt == nil {
+			now := r.store.now()
+			user.EmailVerifiedAt = &now
+		}
+	})
+}
+
+// SetSlug sets the public catalog slug of a user
+func (r *UserRepository) SetSlug(ctx context.Context, id uuid.UUID, slug string) error {
+	return r.update(id, func(user *domain.User) { user.Slug = &slug })
+}
+
+// SetPlan changes a user's subscription plan
+func (r *UserRepository) SetPlan(ctx context.Context, id uuid.UUID, plan string) error {
+	return r.update(id, func(user *domain.User) { user.Plan = plan })
+}
+
+// SetAvatar records the version of a user's avatar, empty when removed
+func (r *UserRepository) SetAvatar(ctx context.Context, id uuid.UUID, avatar string) error {
+	return r.update(id, func(user *domain.User) {
+		user.Avatar = avatar
+	})
+}
+
+// SetPreferences stores a user's preferences, recording when they were first set
+func (r *UserRepository) SetPreferences(ctx context.Context, id uuid.UUID, preferences domain.UserPreferences) error {
+	return r.update(id, func(user *domain.User) {
+		user.Preferences = preferences
+		if user.PreferencesSetAt == nil {
+			now := r.store.now()
+			user.PreferencesSetAt = &now
+		}
+	})
+}
+
+// SetQuotaState records when the user was warned about, and first exceeded, the product quota
+func (r *UserRepository) SetQuotaState(ctx context.Context, id uuid.UUID, warnedAt, exceededAt *time.Time) error {
+	return r.update(id, func(user *domain.User) {
+		user.QuotaWarnedAt = warnedAt
+		user.QuotaExceededAt = exceededAt
+	})
+}
+
+// SetDeletionSchedule sets or clears the scheduled deletion time of a user
+func (r *UserRepository) SetDeletionSchedule(ctx context.Context, id uuid.UUID, scheduledAt *time.Time) error {
+	return r.update(id, func(user *domain.User) { user.DeletionScheduledAt = scheduledAt })
+}
+
+// DeleteAccount permanently deletes a user and their products
+func (r *UserRepository) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for productID, product := range r.store.products {
+		if product.UserID == id {
+			delete(r.store.products, productID)
+		}
+	}
+	delete(r.store.users, id)
+	return nil
+}
+
+// LoadAccountData fills export with empty lists; the store only keeps users and products
+func (r *UserRepository) LoadAccountData(ctx context.Context, id uuid.UUID, export *domain.AccountExport) error {
+	export.LoginHistory = []domain.LoginEvent{}
+	export.LoginDevices = []domain.LoginDevice{}
+	export.Orders = []domain.Order{}
+	export.Reviews = []domain.Review{}
+	export.Notes = []domain.ProductNote{}
+	export.SavedSearches = []domain.SavedSearch{}
+	export.Webhooks = []domain.WebhookSubscription{}
+	export.Notifications = []domain.Notification{}
+	export.APIUsage = []domain.APIUsage{}
+	return nil
+}
+
+// first returns the first user, by email, that match selects
+func (r *UserRepository) first(match func(domain.User) bool) (*domain.User, error) {
+	users := r.find(match)
+	if len(users) == 0 {
+		return nil, errors.New("user not found")
+	}
+	return &users[0], nil
+}
+
+// find returns the users match selects, by email
+func (r *UserRepository) find(match func(domain.User) bool) []domain.User {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := []domain.User{}
+	for _, user := range r.store.users {
+		if match(user) {
+			users = append(users, user)
+		}
+	}
+	sortBy(users, func(a, b domain.User) bool { return strings.Compare(a.Email, b.Email) < 0 })
+	return users
+}
+
+// update changes a stored user; users that do not exist are skipped, like updates
+// matching no row
+func (r *UserRepository) update(id uuid.UUID, change func(*domain.User)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil
+	}
+	change(&user)
+	user.ID = id
+	user.UpdatedAt = r.store.now()
+	r.store.users[id] = user
+	return nil
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"products/internal/domain"
+)
+
+func TestUserRepository_DuplicateEmail(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(NewStore())
+
+	if err := repo.Create(ctx, &domain.User{Email: "jane@example.com"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := repo.Create(ctx, &domain.User{Email: "jane@example.com"}); !errors.Is(err, domain.ErrUserExists) {
+		t.Errorf("Expected ErrUserExists, got %v", err)
+	}
+}
+
+func TestUserRepository_EmailIgnoresCase(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(NewStore())
+
+	if err := repo.Create(ctx, &domain.User{Email: "bob@x.com"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := repo.Create(ctx, &domain.User{Email: "Bob@X.com"}); !errors.Is(err, domain.ErrUserExists) {
+		t.Errorf("Expected ErrUserExists, got %v", err)
+	}
+	if _, err := repo.GetByEmail(ctx, "BOB@x.com"); err != nil {
+		t.Errorf("Expected user found regardless of case, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// NoteRepository implements storage of internal product notes
+type NoteRepository struct {
+	*GenericRepository[domain.ProductNote]
+	db *gorm.DB
+}
+
+// NewNoteRepository creates a new note repository
+func NewNoteRepository(db *gorm.DB) *NoteRepository {
+	return &NoteRepository{
+		GenericRepository: NewGenericRepository[domain.ProductNote](db),
+		db:                db,
+	}
+}
+
+// GetByProductID retrieves a page of a product's notes with their authors, oldest first
+func (r *NoteRepository) GetByProductID(ctx context.Context, productID uuid.UUID, pagination domain.Pagination) (*domain.NoteListResponse, error) {
+	var notes []domain.ProductNote
+	var total int64
+
+	dbQuery := r.db.WithContext(ctx).Model(&domain.ProductNote{}).Where("product_id = ?", productID)
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count notes: %w", err)
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	if err := dbQuery.Preload("Author").Order("created_at ASC, id ASC").Offset(offset).Limit(pagination.PageSize).Find(&notes).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch notes: %w", err)
+	}
+
+	if notes == nil {
+		notes = []domain.ProductNote{}
+	}
+
+	return &domain.NoteListResponse{
+		Notes:      notes,
+		Total:      total,
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		TotalPages: int((total + int64(pagination.PageSize) - 1) / int64(pagination.PageSize)),
+	}, nil
+}
+
+// GetForProduct retrieves a note of a product with its author
+func (r *NoteRepository) GetForProduct(ctx context.Context, productID, id uuid.UUID) (*domain.ProductNote, error) {
+	var note domain.ProductNote
+	err := r.db.WithContext(ctx).Preload("Author").Where("id = ? AND product_id = ?", id, productID).First(&note).Error
+	if err != nil {
+		return nil, domain.ErrNoteNotFound
+	}
+	return &note, nil
+}
+
+// UpdateBody saves a note's edited body and mentions
+func (r *NoteRepository) UpdateBody(ctx context.Context, note *domain.ProductNote) error {
+	return r.db.WithContext(ctx).
+		Model(note).
+		Omit(clause.Associations).
+		Select("body", "mentions", "edited_at", "updated_at").
+		Updates(note).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository implements storage of user notifications
+type NotificationRepository struct {
+	*GenericRepository[domain.Notification]
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{
+		GenericRepository: NewGenericRepository[domain.Notification](db),
+		db:                db,
+	}
+}
+
+// GetByUserID retrieves the most recent notifications for a user
+func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnread counts a user's unread notifications
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks a user's notification as read
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("notification not found")
+	}
+	return nil
+}
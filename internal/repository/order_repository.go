@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// OrderRepository implements storage of orders and the stock changes they make
+type OrderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// Create places an order for products authorize allows the user to order, taking
+// the ordered quantities from their unreserved stock in the same transaction. The
+// products are locked while they are authorized and their stock is checked, so
+// concurrent orders cannot sell the same units. It returns the order and the
+// products as they were before the order.
+func (r *OrderRepository) Create(ctx context.Context, userID uuid.UUID, items []domain.OrderItemRequest, authorize func(*domain.Product) error) (*domain.Order, []domain.Product, error) {
+	order := &domain.Order{UserID: userID, Status: domain.OrderStatusPlaced}
+	var products []domain.Product
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := make([]uuid.UUID, len(items))
+		for i, item := range items {
+			ids[i] = item.ProductID
+		}
+
+		locked, err := lockProducts(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, item := range items {
+			product, ok := locked[item.ProductID]
+			if !ok {
+				return domain.ErrProductNotFound
+			}
+			if err := authorize(&product); err != nil {
+				return err
+			}
+			if product.AvailableStock() < item.Quantity {
+				return domain.InsufficientStockError(product)
+			}
+
+			err := tx.Model(&domain.Product{}).Where("id = ?", product.ID).
+				Updates(map[string]interface{}{"stock": gorm.Expr("stock - ?", item.Quantity), "updated_at": now}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update stock: %w", err)
+			}
+
+			order.Items = append(order.Items, domain.NewOrderItem(product, item.Quantity))
+			products = append(products, product)
+		}
+
+		order.CalculateTotal()
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, products, nil
+}
+
+// Cancel cancels one of the user's orders and puts its quantities back in stock in
+// the same transaction. Products deleted since the order, or that authorize no
+// longer allows the user to order, are skipped. It returns the cancelled order and
+// its remaining products as they were before.
+func (r *OrderRepository) Cancel(ctx context.Context, id, userID uuid.UUID, authorize func(*domain.Product) error) (*domain.Order, []domain.Product, error) {
+	var order domain.Order
+	var products []domain.Product
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", id, userID).
+			First(&order).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrOrderNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if order.Status == domain.OrderStatusCancelled {
+			return domain.ErrOrderCancelled
+		}
+
+		if err := tx.Where("order_id = ?", order.ID).Find(&order.Items).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(order.Items))
+		for i, item := range order.Items {
+			ids[i] = item.ProductID
+		}
+		locked, err := lockProducts(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, item := range order.Items {
+			product, ok := locked[item.ProductID]
+			if !ok {
+				continue
+			}
+			if err := authorize(&product); errors.Is(err, domain.ErrForbidden) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			err := tx.Model(&domain.Product{}).Where("id = ?", product.ID).
+				Updates(map[string]interface{}{"stock": gorm.Expr("stock + ?", item.Quantity), "updated_at": now}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update stock: %w", err)
+			}
+			products = append(products, product)
+		}
+
+		order.Status = domain.OrderStatusCancelled
+		order.CancelledAt = &now
+		order.UpdatedAt = now
+		return tx.Model(&order).Select("status", "cancelled_at", "updated_at").Updates(&order).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &order, products, nil
+}
+
+// lockProducts locks the products with the IDs for update, in ID order so that
+// concurrent transactions cannot deadlock, and returns them by ID; callers
+// authorize them
+func lockProducts(tx *gorm.DB, ids []uuid.UUID) (map[uuid.UUID]domain.Product, error) {
+	var products []domain.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&products).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+
+	locked := make(map[uuid.UUID]domain.Product, len(products))
+	for _, product := range products {
+		locked[product.ID] = product
+	}
+	return locked, nil
+}
+
+// GetByID retrieves one of the user's orders with its items, or nil if there is none
+func (r *OrderRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Order, error) {
+	var order domain.Order
+	err := r.db.WithContext(ctx).Preload("Items").Where("id = ? AND user_id = ?", id, userID).First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetByUserID retrieves a page of a user's orders with their items, newest first
+func (r *OrderRepository) GetByUserID(ctx context.Context, userID uuid.UUID, query domain.OrderQuery) (*domain.OrderListResponse, error) {
+	var orders []domain.Order
+	var total int64
+
+	dbQuery := r.db.WithContext(ctx).Model(&domain.Order{}).Where("user_id = ?", userID)
+	if query.Status != "" {
+		dbQuery = dbQuery.Where("status = ?", query.Status)
+	}
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := dbQuery.Preload("Items").Order("created_at DESC").Offset(offset).Limit(query.PageSize).Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+
+	if orders == nil {
+		orders = []domain.Order{}
+	}
+
+	return &domain.OrderListResponse{
+		Orders:     orders,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
+	}, nil
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"products/internal/domain"
+	"products/internal/resilience"
+	"products/internal/timing"
+	"gorm.io/gorm"
+)
+
+// Repository drivers
+const (
+	DriverGORM = "gorm"
+	DriverPgx  = "pgx"
+)
+
+// productColumns are selected by every pgx product query, in scan order
+const productColumns = `p.id, p.code, p.name, p.description, p.description_html, p.price, (p.price * p.stock)::text, p.stock, p.reserved_stock, p.low_stock_threshold, p.reorder_quantity, p.attributes, p.public, p.rating_average, p.rating_count, p.status, p.archived_at, p.user_id, p.version, p.created_at, p.updated_at,
+	u.id, u.email, u.name, u.created_at, u.updated_at, u.deletion_scheduled_at`
+
+// pgxProductQueries implements the hot product read paths with hand-written SQL
+// executed directly on pgx connections, bypassing GORM's reflection.
+type pgxProductQueries struct {
+	db       *sql.DB
+	executor *resilience.Executor
+	explain  bool
+}
+
+// newPgxProductQueries creates pgx-backed product queries on top of a pgx stdlib
+// pool, protected by the executor the GORM database was instrumented with, if any
+func newPgxProductQueries(db *gorm.DB) (*pgxProductQueries, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return &pgxProductQueries{db: sqlDB, executor: resilience.ExecutorOf(db)}, nil
+}
+
+// withConn runs fn with the native pgx connection behind a pooled database/sql
+// connection. The queries are reads, so transient failures are retried.
+func (q *pgxProductQueries) withConn(ctx context.Context, fn func(ctx context.Context, conn *pgx.Conn) error) error {
+	defer timing.Start(ctx, timing.Database)()
+
+	return q.executor.Do(ctx, func(ctx context.Context) error {
+		sqlConn, err := q.db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer sqlConn.Close()
+
+		return sqlConn.Raw(func(driverConn any) error {
+			conn, ok := driverConn.(*stdlib.Conn)
+			if !ok {
+				return errors.New("pgx driver is not in use")
+			}
+			return fn(ctx, conn.Conn())
+		})
+	})
+}
+
+// GetProductsWithFilters retrieves products with filtering, sorting, and pagination
+func (q *pgxProductQueries) GetProductsWithFilters(ctx context.Context, userID uuid.UUID, query domain.ProductQuery) (*domain.ProductListResponse, error) {
+	where := newSQLWhere()
+	where.add("p.user_id = ?", userID)
+	applyFilterConditions(where, userID, query.Filter)
+
+	var total, estimate *int64
+	var products []domain.Product
+
+	err := q.withConn(ctx, func(ctx context.Context, conn *pgx.Conn) error {
+		switch query.Total {
+		case domain.TotalNone:
+		case domain.TotalEstimate:
+			var output []byte
+			explainSQL := "EXPLAIN (FORMAT JSON) SELECT 1 FROM products p WHERE " + where.sql()
+			if err := conn.QueryRow(ctx, explainSQL, where.args...).Scan(&output); err != nil {
+				return fmt.Errorf("failed to estimate products: %w", err)
+			}
+			rows, err := explainPlanRows(output)
+			if err != nil {
+				return err
+			}
+			estimate = &rows
+		default:
+			var count int64
+			countSQL := "SELECT COUNT(*) FROM products p WHERE " + where.sql()
+			if err := conn.QueryRow(ctx, countSQL, where.args...).Scan(&count); err != nil {
+				return fmt.Errorf("failed to count products: %w", err)
+			}
+			total = &count
+		}
+
+		offset := (query.Pagination.Page - 1) * query.Pagination.PageSize
+		args := append(where.args, pageLimit(query), offset)
+		listSQL := fmt.Sprintf("SELECT %s FROM products p JOIN users u ON u.id = p.user_id WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d",
+			productColumns, where.sql(), orderByClause(query.Sort), len(where.args)+1, len(where.args)+2)
+
+		if q.explain {
+			explainPgx(ctx, conn, listSQL, args...)
+		}
+
+		var err error
+		products, err = queryProducts(ctx, conn, listSQL, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offsetPage(query, products, total, estimate), nil
+}
+
+// GetProductsWithCursor retrieves products with cursor-based pagination
+func (q *pgxProductQueries) GetProductsWithCursor(ctx context.Context, userID uuid.UUID, query domain.ProductQueryCursor) (*domain.ProductListCursorResponse, error) {
+	where := newSQLWhere()
+	where.add("p.user_id = ?", userID)
+	applyFilterConditions(where, userID, query.Filter)
+
+	keys := cursorSortKeys(query.Sort)
+	if query.Pagination.Cursor != nil {
+		values, err := decodeProductCursor(keys, *query.Pagination.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		condition, args := keysetCondition("p.", keys, values)
+		where.add(condition, args...)
+	}
+
+	var products []domain.Product
+	err := q.withConn(ctx, func(ctx context.Context, conn *pgx.Conn) error {
+		args := append(where.args, query.Pagination.PageSize+1)
+		listSQL := fmt.Sprintf("SELECT %s FROM products p JOIN users u ON u.id = p.user_id WHERE %s ORDER BY %s LIMIT $%d",
+			productColumns, where.sql(), orderByKeys(keys), len(where.args)+1)
+
+		if q.explain {
+			explainPgx(ctx, conn, listSQL, args...)
+		}
+
+		var err error
+		products, err = queryProducts(ctx, conn, listSQL, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cursorPage(keys, products, query)
+}
+
+// GetProductStats retrieves statistics of a user's products that are not archived
+func (q *pgxProductQueries) GetProductStats(ctx context.Context, userID uuid.UUID) (*domain.ProductStats, error) {
+	var stats domain.ProductStats
+	percentiles := &stats.StockValuePercentiles
+
+	err := q.withConn(ctx, func(ctx context.Context, conn *pgx.Conn) error {
+		return conn.QueryRow(ctx, `
+			SELECT `+productStatsSQL+`
+			FROM products p
+			JOIN users u ON u.id = p.user_id
+			`+stockTemplateJoinSQL+`
+			WHERE p.user_id = $1 AND p.archived_at IS NULL`, userID,
+		).Scan(&stats.TotalProducts, &stats.TotalValue, &stats.AvgPrice, &stats.MedianPrice, &stats.LowStock, &stats.OutOfStock,
+			&percentiles.P25, &percentiles.P50, &percentiles.P75, &percentiles.P90)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// queryProducts runs a product query and scans rows selected with productColumns
+func queryProducts(ctx context.Context, conn *pgx.Conn, query string, args ...interface{}) ([]domain.Product, error) {
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []domain.Product{}
+	for rows.Next() {
+		var p domain.Product
+		var description, descriptionHTML sql.NullString
+		var attributes []byte
+		if err := rows.Scan(
+			&p.ID, &p.Code, &p.Name, &description, &descriptionHTML, &p.Price, &p.StockValue, &p.Stock, &p.ReservedStock, &p.LowStockThreshold, &p.ReorderQuantity, &attributes, &p.Public, &p.RatingAverage, &p.RatingCount, &p.Status, &p.ArchivedAt, &p.UserID, &p.Version, &p.CreatedAt, &p.UpdatedAt,
+			&p.User.ID, &p.User.Email, &p.User.Name, &p.User.CreatedAt, &p.User.UpdatedAt, &p.User.DeletionScheduledAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		p.Description = description.String
+		p.DescriptionHTML = descriptionHTML.String
+		if err := p.Attributes.Scan(attributes); err != nil {
+			return nil, fmt.Errorf("failed to decode product attributes: %w", err)
+		}
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
+	}
+
+	return products, nil
+}
+
+// sqlWhere accumulates AND-ed conditions, rewriting ? placeholders to $n
+type sqlWhere struct {
+	conditions []string
+	args       []interface{}
+}
+
+func newSQLWhere() *sqlWhere {
+	return &sqlWhere{}
+}
+
+// add appends a condition with one ? placeholder per argument
+func (w *sqlWhere) add(condition string, args ...interface{}) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(w.args)), 1)
+	}
+	w.conditions = append(w.conditions, condition)
+}
+
+func (w *sqlWhere) sql() string {
+	return strings.Join(w.conditions, " AND ")
+}
+
+// applyFilterConditions mirrors ProductRepository.applyFilters for raw SQL
+func applyFilterConditions(where *sqlWhere, userID uuid.UUID, filter domain.ProductFilter) {
+	if filter.Name != nil && *filter.Name != "" {
+		where.add("LOWER(p.name) LIKE LOWER(?)", "%"+*filter.Name+"%")
+	}
+	if filter.Code != nil {
+		where.add("p.code = ?", *filter.Code)
+	}
+	if filter.MinPrice != nil {
+		where.add("p.price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		where.add("p.price <= ?", *filter.MaxPrice)
+	}
+	if filter.MinStock != nil {
+		where.add("p.stock >= ?", *filter.MinStock)
+	}
+	if filter.MaxStock != nil {
+		where.add("p.stock <= ?", *filter.MaxStock)
+	}
+	if len(filter.StockStatuses) > 0 {
+		where.add("p.id IN ("+stockStatusProductsSQL+" = ANY(?))", userID, filter.StockStatuses)
+	}
+	if condition, args := priceBucketCondition("p.", filter.PriceBuckets); condition != "" {
+		where.add(condition, args...)
+	}
+	switch filter.Status {
+	case domain.ProductStatusArchived:
+		where.add("p.archived_at IS NOT NULL")
+	case domain.ProductStatusDraft, domain.ProductStatusPublished:
+		where.add("p.status = ? AND p.archived_at IS NULL", filter.Status)
+	default:
+		where.add("p.archived_at IS NULL")
+	}
+	if filter.LocationID != nil {
+		where.add("p.id IN (SELECT product_id FROM location_stocks WHERE location_id = ?)", *filter.LocationID)
+	}
+	if filter.CreatedFrom != nil {
+		where.add("p.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		where.add("p.created_at <= ?", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		where.add("p.updated_at >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		where.add("p.updated_at <= ?", *filter.UpdatedTo)
+	}
+	if len(filter.Attributes) > 0 {
+		if data, err := json.Marshal(filter.Attributes); err == nil {
+			where.add("p.attributes @> ?::jsonb", string(data))
+		}
+	}
+}
+
+// orderByClause mirrors ProductRepository.applySorting for raw SQL
+func orderByClause(sortFields []domain.SortField) string {
+	return orderByKeys(productSortKeys(sortFields))
+}
+
+// orderByKeys renders sort keys as an ORDER BY list on the products alias
+func orderByKeys(keys []sortKey) string {
+	clauses := make([]string, 0, len(keys))
+	for _, key := range keys {
+		clauses = append(clauses, key.orderBy("p."))
+	}
+	return strings.Join(clauses, ", ")
+}
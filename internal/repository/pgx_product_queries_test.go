@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+func TestSQLWhere_NumbersPlaceholders(t *testing.T) {
+	name := "chair"
+	minPrice := 10.0
+	where := newSQLWhere()
+	where.add("p.user_id = ?", uuid.New())
+	applyFilterConditions(where, uuid.Nil, domain.ProductFilter{Name: &name, MinPrice: &minPrice})
+
+	expected := "p.user_id = $1 AND LOWER(p.name) LIKE LOWER($2) AND p.price >= $3 AND p.archived_at IS NULL"
+	if where.sql() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, where.sql())
+	}
+
+	if len(where.args) != 3 {
+		t.Errorf("Expected 3 args, got %d", len(where.args))
+	}
+}
+
+func TestSQLWhere_ArchivedStatus(t *testing.T) {
+	where := newSQLWhere()
+	applyFilterConditions(where, uuid.Nil, domain.ProductFilter{Status: domain.ProductStatusArchived})
+
+	if where.sql() != "p.archived_at IS NOT NULL" {
+		t.Errorf("Expected only archived products to match, got '%s'", where.sql())
+	}
+}
+
+func TestSQLWhere_StockStatusAndPriceBuckets(t *testing.T) {
+	userID := uuid.New()
+	where := newSQLWhere()
+	applyFilterConditions(where, userID, domain.ProductFilter{
+		StockStatuses: []string{domain.StockStatusLow},
+		PriceBuckets:  []string{"budget", "premium", "unknown"},
+	})
+
+	sql := where.sql()
+	if !strings.Contains(sql, "p.id IN (SELECT p.id FROM products p JOIN users u") || !strings.Contains(sql, "END = ANY($2))") {
+		t.Errorf("Expected a stock status subquery, got '%s'", sql)
+	}
+	if !strings.Contains(sql, "((p.price >= $3 AND p.price < $4) OR p.price >= $5)") {
+		t.Errorf("Expected price bucket ranges, got '%s'", sql)
+	}
+	if len(where.args) != 5 || where.args[0] != userID {
+		t.Errorf("Expected the user and 4 bounds as args, got %v", where.args)
+	}
+}
+
+func TestSQLWhere_KeepsValuesOutOfSQL(t *testing.T) {
+	name := "'; DROP TABLE products; --"
+	where := newSQLWhere()
+	applyFilterConditions(where, uuid.Nil, domain.ProductFilter{Name: &name, Attributes: map[string]interface{}{"color": "red' OR '1'='1"}})
+
+	sql := where.sql()
+	if strings.Contains(sql, "DROP") || strings.Contains(sql, "OR '1'") {
+		t.Errorf("Expected filter values to be bound as arguments, got '%s'", sql)
+	}
+	if len(where.args) != 2 {
+		t.Errorf("Expected 2 args, got %d", len(where.args))
+	}
+}
+
+func TestOrderByClause(t *testing.T) {
+	if clause := orderByClause(nil); clause != "p.created_at DESC" {
+		t.Errorf("Expected default ordering, got '%s'", clause)
+	}
+
+	clause := orderByClause([]domain.SortField{
+		{Field: "price", Direction: "desc"},
+		{Field: "password", Direction: "asc"},
+		{Field: "name", Direction: "sideways"},
+	})
+	if clause != "p.price DESC, p.name ASC" {
+		t.Errorf("Expected 'p.price DESC, p.name ASC', got '%s'", clause)
+	}
+}
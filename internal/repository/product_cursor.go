@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// sortKey is one column of the effective product ordering. Nulls is FIRST or LAST
+// for nullable fields and empty for the others.
+type sortKey struct {
+	Field     string
+	Direction string
+	Nulls     string
+}
+
+// String renders the key as it is ordered by, which identifies the ordering of cursors
+func (k sortKey) String() string {
+	if k.Nulls == "" {
+		return k.Field + " " + k.Direction
+	}
+	return k.Field + " " + k.Direction + " NULLS " + k.Nulls
+}
+
+// orderBy renders the key as an ORDER BY item with columns prefixed with prefix
+func (k sortKey) orderBy(prefix string) string {
+	if k.Nulls == "" {
+		return sortExpression(prefix, k.Field) + " " + k.Direction
+	}
+	return sortExpression(prefix, k.Field) + " " + k.Direction + " NULLS " + k.Nulls
+}
+
+// stockValueColumn selects the stock value as exact decimal text into Product.StockValue
+func stockValueColumn(prefix string) string {
+	return sortExpression(prefix, "stock_value") + "::text AS stock_value"
+}
+
+// sortExpression returns the SQL of a sort field: its column, or the expression
+// computing it, with columns prefixed with prefix
+func sortExpression(prefix, field string) string {
+	switch field {
+	case "stock_value":
+		return "(" + prefix + "price * " + prefix + "stock)"
+	default:
+		return prefix + field
+	}
+}
+
+// productSortKeys returns the valid requested sort columns with normalized
+// directions, or the default created_at DESC ordering. The products without a
+// value of a nullable field sort as larger than any value unless asked otherwise.
+func productSortKeys(sortFields []domain.SortField) []sortKey {
+	var keys []sortKey
+	seen := make(map[string]bool, len(sortFields))
+	for _, sortField := range sortFields {
+		sortable, ok := domain.ProductSortField(sortField.Field)
+		if !ok || seen[sortField.Field] || len(keys) == domain.MaxSortFields {
+			continue
+		}
+		seen[sortField.Field] = true
+
+		key := sortKey{Field: sortField.Field, Direction: strings.ToUpper(sortField.Direction)}
+		if key.Direction != "ASC" && key.Direction != "DESC" {
+			key.Direction = "ASC"
+		}
+		if sortable.Nullable {
+			switch {
+			case sortField.Nulls == domain.SortNullsFirst:
+				key.Nulls = "FIRST"
+			case sortField.Nulls == domain.SortNullsLast, key.Direction == "ASC":
+				key.Nulls = "LAST"
+			default:
+				key.Nulls = "FIRST"
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return []sortKey{{Field: domain.DefaultProductSort.Field, Direction: strings.ToUpper(domain.DefaultProductSort.Direction)}}
+	}
+	return keys
+}
+
+// cursorSortKeys returns the ordering of cursor pages: the sort columns followed by
+// the id, which makes the order total so no row is skipped or repeated
+func cursorSortKeys(sortFields []domain.SortField) []sortKey {
+	return append(productSortKeys(sortFields), sortKey{Field: "id", Direction: "ASC"})
+}
+
+// productCursor is the position after a product in a cursor ordering: the product's
+// values of the sort columns, ending with its id
+type productCursor struct {
+	Sort   []string          `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// encodeProductCursor encodes the position of product in the ordering
+func encodeProductCursor(keys []sortKey, product *domain.Product) (string, error) {
+	cursor := productCursor{}
+	for _, key := range keys {
+		value, err := json.Marshal(sortValue(product, key.Field))
+		if err != nil {
+			return "", err
+		}
+		cursor.Sort = append(cursor.Sort, key.String())
+		cursor.Values = append(cursor.Values, value)
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeProductCursor decodes a cursor issued for the same ordering into the
+// values of its sort columns
+func decodeProductCursor(keys []sortKey, encoded string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed", domain.ErrInvalidCursor)
+	}
+
+	var cursor productCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Sort) != len(keys) || len(cursor.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: malformed", domain.ErrInvalidCursor)
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if cursor.Sort[i] != key.String() {
+			return nil, fmt.Errorf("%w: it was issued for a different sort order", domain.ErrInvalidCursor)
+		}
+		value, err := parseSortValue(key.Field, cursor.Values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad %s value", domain.ErrInvalidCursor, key.Field)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// keysetCondition returns the condition selecting the rows after the cursor values,
+// e.g. "(price < ?) OR (price = ? AND id > ?)" for price DESC, id ASC. Columns are
+// prefixed with prefix. Nullable keys compare nulls as equal and place them
+// before or after every value as they are ordered.
+func keysetCondition(prefix string, keys []sortKey, values []interface{}) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for i, key := range keys {
+		expression := sortExpression(prefix, key.Field)
+		operator := ">"
+		if key.Direction == "DESC" {
+			operator = "<"
+		}
+
+		// The rows after the cursor's value of this key
+		var after string
+		var afterArgs []interface{}
+		switch {
+		case key.Nulls == "" || values[i] != nil && key.Nulls == "FIRST":
+			after, afterArgs = expression+" "+operator+" ?", []interface{}{values[i]}
+		case values[i] != nil:
+			after, afterArgs = "("+expression+" "+operator+" ? OR "+expression+" IS NULL)", []interface{}{values[i]}
+		case key.Nulls == "FIRST":
+			after = expression + " IS NOT NULL"
+		default:
+			// nothing follows a null placed last but the rows tied on it
+			continue
+		}
+
+		var parts []string
+		for j := 0; j < i; j++ {
+			if keys[j].Nulls == "" {
+				parts = append(parts, sortExpression(prefix, keys[j].Field)+" = ?")
+			} else {
+				parts = append(parts, sortExpression(prefix, keys[j].Field)+" IS NOT DISTINCT FROM ?")
+			}
+			args = append(args, values[j])
+		}
+		parts = append(parts, after)
+		args = append(args, afterArgs...)
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// sortValue returns a product's value of a sortable column
+func sortValue(product *domain.Product, field string) interface{} {
+	switch field {
+	case "name":
+		return product.Name
+	case "price":
+		return product.Price
+	case "stock":
+		return product.Stock
+	case "stock_value":
+		// the database's exact value, since the float product of price and stock
+		// may not equal the decimal the keyset condition compares with
+		if product.StockValue != "" {
+			return product.StockValue
+		}
+		return strconv.FormatFloat(product.Price*float64(product.Stock), 'f', -1, 64)
+	case "code":
+		return product.Code
+	case "created_at":
+		return product.CreatedAt
+	case "updated_at":
+		return product.UpdatedAt
+	default:
+		return product.ID
+	}
+}
+
+// parseSortValue decodes a cursor value of a sortable column
+func parseSortValue(field string, raw json.RawMessage) (interface{}, error) {
+	var err error
+	switch field {
+	case "name":
+		var value string
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case "price":
+		var value float64
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case "stock_value":
+		var value string
+		if err = json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if _, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "code":
+		var value *string
+		if err = json.Unmarshal(raw, &value); err != nil || value == nil {
+			return nil, err
+		}
+		return *value, nil
+	case "stock":
+		var value int
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	case "created_at", "updated_at":
+		var value time.Time
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	default:
+		var value uuid.UUID
+		err = json.Unmarshal(raw, &value)
+		return value, err
+	}
+}
+
+// cursorPage trims the extra row fetched to detect a next page and returns the
+// cursor response for the page
+func cursorPage(keys []sortKey, products []domain.Product, query domain.ProductQueryCursor) (*domain.ProductListCursorResponse, error) {
+	hasNext := len(products) > query.Pagination.PageSize
+	if hasNext {
+		products = products[:query.Pagination.PageSize]
+	}
+
+	var nextCursor, prevCursor *string
+	if len(products) > 0 {
+		last, err := encodeProductCursor(keys, &products[len(products)-1])
+		if err != nil {
+			return nil, err
+		}
+		nextCursor = &last
+
+		if query.Pagination.Cursor != nil {
+			first, err := encodeProductCursor(keys, &products[0])
+			if err != nil {
+				return nil, err
+			}
+			prevCursor = &first
+		}
+	}
+
+	return &domain.ProductListCursorResponse{
+		Products:   products,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+		HasNext:    hasNext,
+		HasPrev:    query.Pagination.Cursor != nil,
+	}, nil
+}
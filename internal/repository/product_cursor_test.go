@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+func TestProductCursor_RoundTrip(t *testing.T) {
+	keys := cursorSortKeys([]domain.SortField{{Field: "price", Direction: "desc"}, {Field: "created_at", Direction: "asc"}})
+	product := &domain.Product{
+		ID:        uuid.New(),
+		Price:     19.99,
+		CreatedAt: time.Date(2026, time.October, 17, 12, 30, 0, 123456000, time.UTC),
+	}
+
+	encoded, err := encodeProductCursor(keys, product)
+	if err != nil {
+		t.Fatalf("Failed to encode cursor: %v", err)
+	}
+
+	values, err := decodeProductCursor(keys, encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode cursor: %v", err)
+	}
+	if values[0] != 19.99 || !values[1].(time.Time).Equal(product.CreatedAt) || values[2] != product.ID {
+		t.Errorf("Unexpected cursor values %v", values)
+	}
+}
+
+func TestProductCursor_RejectsOtherSortOrders(t *testing.T) {
+	product := &domain.Product{ID: uuid.New(), Name: "Lamp"}
+	encoded, err := encodeProductCursor(cursorSortKeys([]domain.SortField{{Field: "name", Direction: "asc"}}), product)
+	if err != nil {
+		t.Fatalf("Failed to encode cursor: %v", err)
+	}
+
+	tests := map[string]string{
+		"other sort":  encoded,
+		"legacy uuid": uuid.New().String(),
+		"not json":    base64.RawURLEncoding.EncodeToString([]byte("nope")),
+	}
+	keys := cursorSortKeys([]domain.SortField{{Field: "name", Direction: "desc"}})
+	for name, cursor := range tests {
+		if _, err := decodeProductCursor(keys, cursor); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("%s: expected ErrInvalidCursor, got %v", name, err)
+		}
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	keys := cursorSortKeys([]domain.SortField{{Field: "price", Direction: "desc"}, {Field: "name", Direction: "asc"}})
+	id := uuid.New()
+
+	condition, args := keysetCondition("p.", keys, []interface{}{10.0, "Lamp", id})
+
+	expected := "((p.price < ?) OR (p.price = ? AND p.name > ?) OR (p.price = ? AND p.name = ? AND p.id > ?))"
+	if condition != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, condition)
+	}
+	if len(args) != 6 || args[0] != 10.0 || args[4] != "Lamp" || args[5] != id {
+		t.Errorf("Unexpected args %v", args)
+	}
+
+	where := newSQLWhere()
+	where.add("p.user_id = ?", uuid.New())
+	where.add(condition, args...)
+	if len(where.args) != 7 || where.sql() != "p.user_id = $1 AND ((p.price < $2) OR (p.price = $3 AND p.name > $4) OR (p.price = $5 AND p.name = $6 AND p.id > $7))" {
+		t.Errorf("Unexpected SQL '%s'", where.sql())
+	}
+}
+
+func TestCursorSortKeys_DefaultsAndTieBreaker(t *testing.T) {
+	keys := cursorSortKeys([]domain.SortField{{Field: "password", Direction: "asc"}})
+	if len(keys) != 2 || keys[0] != (sortKey{Field: "created_at", Direction: "DESC"}) || keys[1] != (sortKey{Field: "id", Direction: "ASC"}) {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+}
+
+func TestKeysetCondition_NullableAndComputed(t *testing.T) {
+	keys := cursorSortKeys([]domain.SortField{{Field: "code", Direction: "asc", Nulls: domain.SortNullsLast}, {Field: "stock_value", Direction: "desc"}})
+	id := uuid.New()
+
+	condition, args := keysetCondition("p.", keys, []interface{}{"A1", 50.0, id})
+	expected := "(((p.code > ? OR p.code IS NULL)) OR (p.code IS NOT DISTINCT FROM ? AND (p.price * p.stock) < ?) OR (p.code IS NOT DISTINCT FROM ? AND (p.price * p.stock) = ? AND p.id > ?))"
+	if condition != expected || len(args) != 6 {
+		t.Errorf("Expected '%s', got '%s' with %v", expected, condition, args)
+	}
+
+	// Only the rows tied on a null placed last follow it
+	condition, args = keysetCondition("p.", keys, []interface{}{nil, 50.0, id})
+	expected = "((p.code IS NOT DISTINCT FROM ? AND (p.price * p.stock) < ?) OR (p.code IS NOT DISTINCT FROM ? AND (p.price * p.stock) = ? AND p.id > ?))"
+	if condition != expected || len(args) != 5 || args[0] != nil {
+		t.Errorf("Expected '%s', got '%s' with %v", expected, condition, args)
+	}
+}
+
+func TestProductCursor_NullValues(t *testing.T) {
+	keys := cursorSortKeys([]domain.SortField{{Field: "code", Direction: "desc"}})
+	if keys[0].String() != "code DESC NULLS FIRST" {
+		t.Fatalf("Expected nulls first by default when descending, got %s", keys[0])
+	}
+
+	encoded, err := encodeProductCursor(keys, &domain.Product{ID: uuid.New()})
+	if err != nil {
+		t.Fatalf("Failed to encode cursor: %v", err)
+	}
+	values, err := decodeProductCursor(keys, encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode cursor: %v", err)
+	}
+	if values[0] != nil {
+		t.Errorf("Expected a null code, got %#v", values[0])
+	}
+}
+
+func TestProductCursor_StockValueTiesOnFractionalPrice(t *testing.T) {
+	keys := cursorSortKeys([]domain.SortField{{Field: "stock_value", Direction: "desc"}})
+	// 19.99 * 5 is 99.95 in the database's decimals but 99.94999999999999 as floats,
+	// so a cursor holding the float would repeat or skip the rows tied on 99.95
+	last := &domain.Product{ID: uuid.New(), Price: 19.99, Stock: 5, StockValue: "99.95"}
+	if sortValue(&domain.Product{Price: last.Price, Stock: last.Stock}, "stock_value") == "99.95" {
+		t.Fatal("Expected the float product to differ from the decimal one")
+	}
+
+	encoded, err := encodeProductCursor(keys, last)
+	if err != nil {
+		t.Fatalf("Failed to encode cursor: %v", err)
+	}
+	values, err := decodeProductCursor(keys, encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode cursor: %v", err)
+	}
+	if values[0] != "99.95" || values[1] != last.ID {
+		t.Fatalf("Expected the database's stock value, got %v", values)
+	}
+
+	// The next page holds the rows tied on the exact value with a greater id
+	condition, args := keysetCondition("p.", keys, values)
+	expected := "(((p.price * p.stock) < ?) OR ((p.price * p.stock) = ? AND p.id > ?))"
+	if condition != expected || len(args) != 3 || args[0] != "99.95" || args[1] != "99.95" || args[2] != last.ID {
+		t.Errorf("Expected '%s' with the exact value, got '%s' with %v", expected, condition, args)
+	}
+}
+
+func TestProductCursor_RejectsNonNumericStockValue(t *testing.T) {
+	keys := cursorSortKeys([]domain.SortField{{Field: "stock_value", Direction: "asc"}})
+	encoded, err := encodeProductCursor(keys, &domain.Product{ID: uuid.New(), StockValue: "1; DROP TABLE products"})
+	if err != nil {
+		t.Fatalf("Failed to encode cursor: %v", err)
+	}
+	if _, err := decodeProductCursor(keys, encoded); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"products/internal/domain"
+)
+
+// pageLimit returns how many rows to fetch for an offset page. Without an exact
+// total one extra row is fetched to tell whether a next page exists.
+func pageLimit(query domain.ProductQuery) int {
+	if query.Total == domain.TotalNone || query.Total == domain.TotalEstimate {
+		return query.Pagination.PageSize + 1
+	}
+	return query.Pagination.PageSize
+}
+
+// offsetPage builds the response of an offset page. total is nil unless the total
+// was counted exactly, in which case it determines has_next.
+func offsetPage(query domain.ProductQuery, products []domain.Product, total, estimate *int64) *domain.ProductListResponse {
+	response := &domain.ProductListResponse{
+		TotalEstimate: estimate,
+		Page:          query.Pagination.Page,
+		PageSize:      query.Pagination.PageSize,
+		HasPrev:       query.Pagination.Page > 1,
+	}
+
+	if total != nil {
+		totalPages := int((*total + int64(query.Pagination.PageSize) - 1) / int64(query.Pagination.PageSize))
+		response.Total = total
+		response.TotalPages = &totalPages
+		response.HasNext = query.Pagination.Page < totalPages
+	} else if len(products) > query.Pagination.PageSize {
+		products = products[:query.Pagination.PageSize]
+		response.HasNext = true
+	}
+
+	response.Products = products
+	return response
+}
+
+// explainPlanRows extracts the planner's row estimate from EXPLAIN (FORMAT JSON) output
+func explainPlanRows(output []byte) (int64, error) {
+	var plans []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(output, &plans); err != nil {
+		return 0, fmt.Errorf("failed to decode query plan: %w", err)
+	}
+	if len(plans) == 0 {
+		return 0, errors.New("query plan is empty")
+	}
+	return int64(plans[0].Plan.PlanRows), nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+
+	"products/internal/domain"
+)
+
+func pageQuery(page int, total string) domain.ProductQuery {
+	return domain.ProductQuery{Pagination: domain.Pagination{Page: page, PageSize: 2}, Total: total}
+}
+
+func TestOffsetPage_ExactTotal(t *testing.T) {
+	total := int64(5)
+	response := offsetPage(pageQuery(2, ""), make([]domain.Product, 2), &total, nil)
+
+	if response.Total == nil || *response.Total != 5 || response.TotalPages == nil || *response.TotalPages != 3 {
+		t.Errorf("Expected total 5 over 3 pages, got %+v", response)
+	}
+	if !response.HasNext || !response.HasPrev {
+		t.Errorf("Expected next and previous pages, got %+v", response)
+	}
+}
+
+func TestOffsetPage_WithoutTotal(t *testing.T) {
+	query := pageQuery(1, domain.TotalNone)
+	if limit := pageLimit(query); limit != 3 {
+		t.Fatalf("Expected one extra row to be fetched, got limit %d", limit)
+	}
+
+	response := offsetPage(query, make([]domain.Product, 3), nil, nil)
+	if len(response.Products) != 2 || !response.HasNext || response.Total != nil || response.TotalPages != nil {
+		t.Errorf("Expected a trimmed page with a next page and no total, got %+v", response)
+	}
+
+	response = offsetPage(query, make([]domain.Product, 2), nil, nil)
+	if response.HasNext {
+		t.Error("Expected no next page when the extra row is missing")
+	}
+}
+
+func TestExplainPlanRows(t *testing.T) {
+	rows, err := explainPlanRows([]byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1234, "Plan Width": 4}}]`))
+	if err != nil || rows != 1234 {
+		t.Errorf("Expected 1234 rows, got %d (%v)", rows, err)
+	}
+
+	if _, err := explainPlanRows([]byte(`[]`)); err == nil {
+		t.Error("Expected an error for an empty plan")
+	}
+}
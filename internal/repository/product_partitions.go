@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products/internal/domain"
+)
+
+// productDependents are the models whose rows are deleted with their product. Their
+// foreign keys cascade product deletions, but foreign keys cannot reference a
+// partitioned products table, so the repositories delete these rows themselves.
+var productDependents = []interface{}{&domain.Favorite{}, &domain.LocationStock{}, &domain.ProductNote{}, &domain.Review{}}
+
+// deleteProductDependents deletes the rows depending on the given products, a list
+// of IDs or a subquery selecting them
+func deleteProductDependents(tx *gorm.DB, productIDs interface{}) error {
+	for _, model := range productDependents {
+		if err := tx.Where("product_id IN (?)", productIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetPartitioned tells the repository whether the products table is partitioned by
+// user_id, see database.PartitionProducts. Product IDs are then only unique together
+// with their user ID.
+func (r *ProductRepository) SetPartitioned(partitioned bool) {
+	r.partitioned = partitioned
+}
+
+// Delete deletes a product and the rows depending on it
+func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteProductDependents(tx, []uuid.UUID{id}); err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.Product{}).Error
+	})
+}
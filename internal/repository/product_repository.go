@@ -2,21 +2,36 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"products/internal/domain"
 )
 
 // ProductRepository implements the product repository interface
 type ProductRepository struct {
 	*GenericRepository[domain.Product]
-	db *gorm.DB
+	db   *gorm.DB
+	fast *pgxProductQueries
+
+	replica     *gorm.DB
+	fastReplica *pgxProductQueries
+
+	// partitioned is set while the products table is partitioned by user_id
+	partitioned bool
 }
 
+var _ domain.ProductRepository = (*ProductRepository)(nil)
+
 // NewProductRepository creates a new product repository
 func NewProductRepository(db *gorm.DB) *ProductRepository {
 	return &ProductRepository{
@@ -25,20 +40,268 @@ func NewProductRepository(db *gorm.DB) *ProductRepository {
 	}
 }
 
+// NewProductRepositoryWithDriver creates a product repository whose list and
+// stats queries run on the given driver ("gorm" or "pgx")
+func NewProductRepositoryWithDriver(db *gorm.DB, driver string) (*ProductRepository, error) {
+	repo := NewProductRepository(db)
+
+	switch driver {
+	case "", DriverGORM:
+	case DriverPgx:
+		fast, err := newPgxProductQueries(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access connection pool: %w", err)
+		}
+		repo.fast = fast
+	default:
+		return nil, fmt.Errorf("unknown repository driver %q", driver)
+	}
+
+	return repo, nil
+}
+
 // GetByUserID retrieves all products for a specific user
 func (r *ProductRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Product, error) {
 	var products []domain.Product
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&products).Error
+	err := r.reader(ctx).Where("user_id = ?", userID).Find(&products).Error
 	return products, err
 }
 
+// GetActiveByUserID retrieves all products of a user that are not archived
+func (r *ProductRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Product, error) {
+	var products []domain.Product
+	err := r.reader(ctx).Where("user_id = ? AND archived_at IS NULL", userID).Find(&products).Error
+	return products, err
+}
+
+// CountByUserID counts the products owned by a user
+func (r *ProductRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Product{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// SetPublic publishes or unpublishes a product
+func (r *ProductRepository) SetPublic(ctx context.Context, id uuid.UUID, public bool) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"public": public, "updated_at": time.Now()}).Error
+}
+
+// SetStatus sets the status of a product
+func (r *ProductRepository) SetStatus(ctx context.Context, id uuid.UUID, status string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "updated_at": time.Now()}).Error
+}
+
+// SetArchived archives a product at archivedAt, or unarchives it when archivedAt is nil
+func (r *ProductRepository) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"archived_at": archivedAt, "updated_at": time.Now()}).Error
+}
+
+// RevokeStockTokens records that the stock tokens of a product issued up to revokedAt are revoked
+func (r *ProductRepository) RevokeStockTokens(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).Exec("UPDATE products SET stock_tokens_revoked_at = ? WHERE id = ?", revokedAt, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrProductNotFound
+	}
+	return nil
+}
+
+// GetStockTokensRevokedAt returns when the stock tokens of a product were last
+// revoked, or nil when they never were; it reads the primary, so a revocation is
+// seen at once
+func (r *ProductRepository) GetStockTokensRevokedAt(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	var product domain.Product
+	err := r.db.WithContext(ctx).Select("id", "stock_tokens_revoked_at").Where("id = ?", id).First(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return product.StockTokensRevokedAt, nil
+}
+
+// GetPublicByUserID retrieves a page of a user's public, published products that
+// are not archived, newest first
+func (r *ProductRepository) GetPublicByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]domain.Product, int64, error) {
+	var products []domain.Product
+	var total int64
+
+	dbQuery := r.reader(ctx).Model(&domain.Product{}).Where("user_id = ? AND public = ? AND status = ? AND archived_at IS NULL", userID, true, domain.ProductStatusPublished)
+
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count public products: %w", err)
+	}
+
+	if err := dbQuery.Order("created_at DESC").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch public products: %w", err)
+	}
+
+	return products, total, nil
+}
+
+// AdjustStock atomically adds delta to a product's stock, refusing to go below zero
+// or below its reserved stock
+func (r *ProductRepository) AdjustStock(ctx context.Context, id uuid.UUID, delta int) (int, error) {
+	var stock int
+	result := r.db.WithContext(ctx).Raw(
+		"UPDATE products SET stock = stock + ?, updated_at = ? WHERE id = ? AND stock + ? >= reserved_stock RETURNING stock",
+		delta, time.Now(), id, delta,
+	).Scan(&stock)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.currentStock(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		if current.ReservedStock > 0 {
+			return 0, domain.InsufficientStockError(*current)
+		}
+		return 0, domain.StockConflictError(current.Stock, current.UpdatedAt)
+	}
+	return stock, nil
+}
+
+// UpdateColumns writes only the named columns of a product, leaving concurrent
+// changes to the others in place. A new stock below the product's reserved stock
+// is refused with an insufficient stock error.
+func (r *ProductRepository) UpdateColumns(ctx context.Context, product *domain.Product, columns []string) error {
+	query := r.db.WithContext(ctx).Model(&domain.Product{}).Where("id = ?", product.ID)
+	if slices.Contains(columns, "stock") {
+		query = query.Where("reserved_stock <= ?", product.Stock)
+	}
+
+	result := query.Select(columns).Updates(product)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.currentStock(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		return domain.InsufficientStockError(*current)
+	}
+	return nil
+}
+
+// DecrementStock atomically takes quantity units out of a product's available stock
+// and returns the product's new stock levels
+func (r *ProductRepository) DecrementStock(ctx context.Context, id uuid.UUID, quantity int) (*domain.Product, error) {
+	var product domain.Product
+	result := r.db.WithContext(ctx).Raw(
+		"UPDATE products SET stock = stock - ?, updated_at = ? WHERE id = ? AND stock - reserved_stock >= ? RETURNING id, stock, reserved_stock, updated_at",
+		quantity, time.Now(), id, quantity,
+	).Scan(&product)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.currentStock(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, domain.InsufficientStockError(*current)
+	}
+	return &product, nil
+}
+
+// Reserve atomically holds quantity units of a product's available stock
+func (r *ProductRepository) Reserve(ctx context.Context, id uuid.UUID, quantity int) error {
+	result := r.db.WithContext(ctx).Exec(
+		"UPDATE products SET reserved_stock = reserved_stock + ? WHERE id = ? AND stock - reserved_stock >= ?",
+		quantity, id, quantity,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.currentStock(ctx, id)
+		if err != nil {
+			return err
+		}
+		return domain.InsufficientStockError(*current)
+	}
+	return nil
+}
+
+// ReleaseReserved gives quantity held units of a product back to its available stock.
+// Products deleted since are skipped.
+func (r *ProductRepository) ReleaseReserved(ctx context.Context, id uuid.UUID, quantity int) error {
+	return r.db.WithContext(ctx).Exec(
+		"UPDATE products SET reserved_stock = GREATEST(reserved_stock - ?, 0) WHERE id = ?",
+		quantity, id,
+	).Error
+}
+
+// ConsumeReserved atomically takes quantity held units of a product out of its stock
+// and returns the new stock
+func (r *ProductRepository) ConsumeReserved(ctx context.Context, id uuid.UUID, quantity int) (int, error) {
+	var stock int
+	result := r.db.WithContext(ctx).Raw(
+		"UPDATE products SET stock = stock - ?, reserved_stock = GREATEST(reserved_stock - ?, 0), updated_at = ? WHERE id = ? AND stock >= ? RETURNING stock",
+		quantity, quantity, time.Now(), id, quantity,
+	).Scan(&stock)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		current, err := r.currentStock(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		return 0, domain.StockConflictError(current.Stock, current.UpdatedAt)
+	}
+	return stock, nil
+}
+
+// currentStock reads the stock, reserved stock and update time of a product
+func (r *ProductRepository) currentStock(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	var current domain.Product
+	err := r.db.WithContext(ctx).Select("id", "stock", "reserved_stock", "updated_at").Where("id = ?", id).First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &current, nil
+}
+
+// FirstCreatedAt returns when the user created their first product, or nil if they have none
+func (r *ProductRepository) FirstCreatedAt(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	var first sql.NullTime
+	err := r.db.WithContext(ctx).
+		Model(&domain.Product{}).
+		Select("MIN(created_at)").
+		Where("user_id = ?", userID).
+		Scan(&first).Error
+	if err != nil || !first.Valid {
+		return nil, err
+	}
+	return &first.Time, nil
+}
+
 // GetByID retrieves a product by ID with user information
 func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 	var product domain.Product
-	err := r.db.WithContext(ctx).Preload("User").Where("id = ?", id).First(&product).Error
+	err := r.reader(ctx).Preload("User").Where("id = ?", id).First(&product).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, domain.ErrProductNotFound
 		}
 		return nil, err
 	}
@@ -47,58 +310,118 @@ func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 
 // GetProductsWithFilters retrieves products with advanced filtering, sorting, and pagination
 func (r *ProductRepository) GetProductsWithFilters(ctx context.Context, userID uuid.UUID, query domain.ProductQuery) (*domain.ProductListResponse, error) {
+	if fast := r.fastReader(ctx); fast != nil {
+		return fast.GetProductsWithFilters(ctx, userID, query)
+	}
+
 	var products []domain.Product
-	var total int64
+	var total, estimate *int64
 
-	dbQuery := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	dbQuery := r.reader(ctx).Where("user_id = ?", userID)
 
-	dbQuery = r.applyFilters(dbQuery, query.Filter)
+	dbQuery = r.applyFilters(dbQuery, userID, query.Filter)
 
-	if err := dbQuery.Model(&domain.Product{}).Count(&total).Error; err != nil {
-		return nil, fmt.Errorf("failed to count products: %w", err)
+	switch query.Total {
+	case domain.TotalNone:
+	case domain.TotalEstimate:
+		rows, err := r.estimateRows(ctx, dbQuery)
+		if err != nil {
+			return nil, err
+		}
+		estimate = &rows
+	default:
+		var count int64
+		if err := dbQuery.Model(&domain.Product{}).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count products: %w", err)
+		}
+		total = &count
 	}
 
 	dbQuery = r.applySorting(dbQuery, query.Sort)
 
 	offset := (query.Pagination.Page - 1) * query.Pagination.PageSize
-	dbQuery = dbQuery.Offset(offset).Limit(query.Pagination.PageSize)
+	dbQuery = dbQuery.Offset(offset).Limit(pageLimit(query))
 
 	if err := dbQuery.Preload("User").Find(&products).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch products: %w", err)
 	}
 
-	totalPages := int((total + int64(query.Pagination.PageSize) - 1) / int64(query.Pagination.PageSize))
-	hasNext := query.Pagination.Page < totalPages
-	hasPrev := query.Pagination.Page > 1
+	return offsetPage(query, products, total, estimate), nil
+}
 
-	return &domain.ProductListResponse{
-		Products:   products,
-		Total:      total,
-		Page:       query.Pagination.Page,
-		PageSize:   query.Pagination.PageSize,
-		TotalPages: totalPages,
-		HasNext:    hasNext,
-		HasPrev:    hasPrev,
-	}, nil
+// StreamProductsWithFilters calls yield with each product matching the query's
+// filters, in its order, as rows are read from the database cursor; pagination is
+// ignored. Iteration stops at the first error yield returns.
+func (r *ProductRepository) StreamProductsWithFilters(ctx context.Context, userID uuid.UUID, query domain.ProductQuery, yield func(*domain.Product) error) error {
+	var owner domain.User
+	if err := r.reader(ctx).First(&owner, "id = ?", userID).Error; err != nil {
+		return fmt.Errorf("failed to fetch product owner: %w", err)
+	}
+
+	dbQuery := r.reader(ctx).Model(&domain.Product{}).Where("user_id = ?", userID)
+	dbQuery = r.applySorting(r.applyFilters(dbQuery, userID, query.Filter), query.Sort)
+
+	rows, err := dbQuery.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to fetch products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product domain.Product
+		if err := dbQuery.ScanRows(rows, &product); err != nil {
+			return fmt.Errorf("failed to read product: %w", err)
+		}
+		product.User = owner
+		if err := yield(&product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// estimateRows returns the planner's estimate of the rows a product query matches,
+// which comes from table statistics instead of a COUNT(*) scan
+func (r *ProductRepository) estimateRows(ctx context.Context, dbQuery *gorm.DB) (int64, error) {
+	statement := dbQuery.Session(&gorm.Session{DryRun: true}).Model(&domain.Product{}).Select("id").Find(&[]domain.Product{}).Statement
+
+	sqlDB, err := dbQuery.DB()
+	if err != nil {
+		return 0, err
+	}
+
+	var output []byte
+	if err := sqlDB.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+statement.SQL.String(), statement.Vars...).Scan(&output); err != nil {
+		return 0, fmt.Errorf("failed to estimate products: %w", err)
+	}
+	return explainPlanRows(output)
 }
 
 // GetProductsWithCursor retrieves products with cursor-based pagination
 func (r *ProductRepository) GetProductsWithCursor(ctx context.Context, userID uuid.UUID, query domain.ProductQueryCursor) (*domain.ProductListCursorResponse, error) {
+	if fast := r.fastReader(ctx); fast != nil {
+		return fast.GetProductsWithCursor(ctx, userID, query)
+	}
+
 	var products []domain.Product
 
-	dbQuery := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	dbQuery := r.reader(ctx).Select("products.*, "+stockValueColumn("products.")).Where("user_id = ?", userID)
 
-	dbQuery = r.applyFilters(dbQuery, query.Filter)
+	dbQuery = r.applyFilters(dbQuery, userID, query.Filter)
 
-	dbQuery = r.applySorting(dbQuery, query.Sort)
+	keys := cursorSortKeys(query.Sort)
+	for _, key := range keys {
+		dbQuery = dbQuery.Order(key.orderBy(""))
+	}
 
 	if query.Pagination.Cursor != nil {
-		cursor, err := uuid.Parse(*query.Pagination.Cursor)
+		values, err := decodeProductCursor(keys, *query.Pagination.Cursor)
 		if err != nil {
-			return nil, fmt.Errorf("invalid cursor: %w", err)
+			return nil, err
 		}
 
-		dbQuery = dbQuery.Where("id > ?", cursor)
+		condition, args := keysetCondition("", keys, values)
+		dbQuery = dbQuery.Where(condition, args...)
 	}
 
 	limit := query.Pagination.PageSize + 1
@@ -106,37 +429,19 @@ func (r *ProductRepository) GetProductsWithCursor(ctx context.Context, userID uu
 		return nil, fmt.Errorf("failed to fetch products: %w", err)
 	}
 
-	hasNext := len(products) > query.Pagination.PageSize
-	if hasNext {
-		products = products[:query.Pagination.PageSize]
-	}
-
-	var nextCursor, prevCursor *string
-	if len(products) > 0 {
-		lastID := products[len(products)-1].ID.String()
-		nextCursor = &lastID
-
-		if query.Pagination.Cursor != nil {
-			firstID := products[0].ID.String()
-			prevCursor = &firstID
-		}
-	}
-
-	return &domain.ProductListCursorResponse{
-		Products:   products,
-		NextCursor: nextCursor,
-		PrevCursor: prevCursor,
-		HasNext:    hasNext,
-		HasPrev:    query.Pagination.Cursor != nil,
-	}, nil
+	return cursorPage(keys, products, query)
 }
 
-// applyFilters applies filters to the database query
-func (r *ProductRepository) applyFilters(dbQuery *gorm.DB, filter domain.ProductFilter) *gorm.DB {
+// applyFilters applies filters to the database query of the user's products
+func (r *ProductRepository) applyFilters(dbQuery *gorm.DB, userID uuid.UUID, filter domain.ProductFilter) *gorm.DB {
 	if filter.Name != nil && *filter.Name != "" {
 		dbQuery = dbQuery.Where("LOWER(name) LIKE LOWER(?)", "%"+*filter.Name+"%")
 	}
 
+	if filter.Code != nil {
+		dbQuery = dbQuery.Where("code = ?", *filter.Code)
+	}
+
 	if filter.MinPrice != nil {
 		dbQuery = dbQuery.Where("price >= ?", *filter.MinPrice)
 	}
@@ -153,6 +458,27 @@ func (r *ProductRepository) applyFilters(dbQuery *gorm.DB, filter domain.Product
 		dbQuery = dbQuery.Where("stock <= ?", *filter.MaxStock)
 	}
 
+	if len(filter.StockStatuses) > 0 {
+		dbQuery = dbQuery.Where("id IN ("+stockStatusProductsSQL+" IN ?)", userID, filter.StockStatuses)
+	}
+
+	if condition, args := priceBucketCondition("", filter.PriceBuckets); condition != "" {
+		dbQuery = dbQuery.Where(condition, args...)
+	}
+
+	switch filter.Status {
+	case domain.ProductStatusArchived:
+		dbQuery = dbQuery.Where("archived_at IS NOT NULL")
+	case domain.ProductStatusDraft, domain.ProductStatusPublished:
+		dbQuery = dbQuery.Where("status = ? AND archived_at IS NULL", filter.Status)
+	default:
+		dbQuery = dbQuery.Where("archived_at IS NULL")
+	}
+
+	if filter.LocationID != nil {
+		dbQuery = dbQuery.Where("id IN (SELECT product_id FROM location_stocks WHERE location_id = ?)", *filter.LocationID)
+	}
+
 	if filter.CreatedFrom != nil {
 		dbQuery = dbQuery.Where("created_at >= ?", *filter.CreatedFrom)
 	}
@@ -169,73 +495,329 @@ func (r *ProductRepository) applyFilters(dbQuery *gorm.DB, filter domain.Product
 		dbQuery = dbQuery.Where("updated_at <= ?", *filter.UpdatedTo)
 	}
 
+	if len(filter.Attributes) > 0 {
+		if data, err := json.Marshal(filter.Attributes); err == nil {
+			dbQuery = dbQuery.Where("attributes @> ?::jsonb", string(data))
+		}
+	}
+
 	return dbQuery
 }
 
+// priceBucketCondition matches prices in any of the named price buckets, ignoring
+// unknown names; it is empty when no bucket is known
+func priceBucketCondition(prefix string, names []string) (string, []interface{}) {
+	var ranges []string
+	var args []interface{}
+	for _, name := range names {
+		bucket, ok := domain.PriceBucketByName(name)
+		if !ok {
+			continue
+		}
+		if bucket.Max == nil {
+			ranges = append(ranges, prefix+"price >= ?")
+			args = append(args, bucket.Min)
+		} else {
+			ranges = append(ranges, "("+prefix+"price >= ? AND "+prefix+"price < ?)")
+			args = append(args, bucket.Min, *bucket.Max)
+		}
+	}
+	if len(ranges) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(ranges, " OR ") + ")", args
+}
+
 // applySorting applies sorting to the database query
 func (r *ProductRepository) applySorting(dbQuery *gorm.DB, sortFields []domain.SortField) *gorm.DB {
-	if len(sortFields) == 0 {
-		// Default sorting by created_at desc
-		return dbQuery.Order("created_at DESC")
+	for _, key := range productSortKeys(sortFields) {
+		dbQuery = dbQuery.Order(key.orderBy(""))
+	}
+
+	return dbQuery
+}
+
+// productStatsSQL selects the statistics of products p joined with their owner u
+// and stock template st, in the order of the fields of domain.ProductStats
+var productStatsSQL = `
+	COUNT(*) AS total_products,
+	COALESCE(SUM(p.price * p.stock), 0)::float8 AS total_value,
+	COALESCE(AVG(p.price), 0)::float8 AS avg_price,
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY p.price), 0)::float8 AS median_price,
+	COUNT(CASE WHEN p.stock < ` + lowStockThresholdSQL + ` THEN 1 END) AS low_stock,
+	COUNT(CASE WHEN p.stock = 0 THEN 1 END) AS out_of_stock,
+	COALESCE(percentile_cont(0.25) WITHIN GROUP (ORDER BY p.price * p.stock), 0)::float8 AS p25,
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY p.price * p.stock), 0)::float8 AS p50,
+	COALESCE(percentile_cont(0.75) WITHIN GROUP (ORDER BY p.price * p.stock), 0)::float8 AS p75,
+	COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY p.price * p.stock), 0)::float8 AS p90`
+
+// GetProductStats retrieves statistics of a user's products that are not archived
+func (r *ProductRepository) GetProductStats(ctx context.Context, userID uuid.UUID) (*domain.ProductStats, error) {
+	if fast := r.fastReader(ctx); fast != nil {
+		return fast.GetProductStats(ctx, userID)
+	}
+
+	var row struct {
+		TotalProducts      int64
+		TotalValue         float64
+		AvgPrice           float64
+		MedianPrice        float64
+		LowStock           int64
+		OutOfStock         int64
+		P25, P50, P75, P90 float64
+	}
+
+	err := r.reader(ctx).
+		Table("products p").
+		Joins("JOIN users u ON u.id = p.user_id").
+		Joins(stockTemplateJoinSQL).
+		Where("p.user_id = ? AND p.archived_at IS NULL", userID).
+		Select(productStatsSQL).
+		Scan(&row).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product stats: %w", err)
+	}
+
+	return &domain.ProductStats{
+		TotalProducts: row.TotalProducts,
+		TotalValue:    row.TotalValue,
+		AvgPrice:      row.AvgPrice,
+		MedianPrice:   row.MedianPrice,
+		LowStock:      row.LowStock,
+		OutOfStock:    row.OutOfStock,
+		StockValuePercentiles: domain.StockValuePercentiles{
+			P25: row.P25,
+			P50: row.P50,
+			P75: row.P75,
+			P90: row.P90,
+		},
+	}, nil
+}
+
+// GetStatsSeries retrieves a metric of the user's products that are not archived
+// per bucket of the given interval they were created in, between from and to.
+// Buckets without products are left out.
+func (r *ProductRepository) GetStatsSeries(ctx context.Context, userID uuid.UUID, metric, interval string, from, to time.Time) (map[time.Time]float64, error) {
+	value := "COUNT(*)"
+	if metric == domain.StatsMetricValue {
+		value = "COALESCE(SUM(p.price * p.stock), 0)"
+	}
+
+	var rows []struct {
+		Bucket time.Time
+		Value  float64
+	}
+	err := r.reader(ctx).
+		Table("products p").
+		Select("date_trunc(?, p.created_at AT TIME ZONE 'UTC') AS bucket, "+value+" AS value", interval).
+		Where("p.user_id = ? AND p.archived_at IS NULL AND p.created_at >= ? AND p.created_at < ?", userID, from, to).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats series: %w", err)
+	}
+
+	series := make(map[time.Time]float64, len(rows))
+	for _, row := range rows {
+		series[domain.TruncateToBucket(row.Bucket, interval)] = row.Value
+	}
+	return series, nil
+}
+
+// GetLowStock retrieves up to limit of a user's products whose stock is below their
+// low-stock threshold, lowest stock first, leaving out archived products. Given a location, products kept there are
+// compared by, and returned with, their stock at the location instead.
+func (r *ProductRepository) GetLowStock(ctx context.Context, userID uuid.UUID, locationID *uuid.UUID, limit int) ([]domain.Product, error) {
+	stock, columns := "p.stock", "p.*"
+	dbQuery := r.reader(ctx).
+		Table("products p").
+		Joins("JOIN users u ON u.id = p.user_id").
+		Joins(stockTemplateJoinSQL)
+	if locationID != nil {
+		stock, columns = "ls.stock", "p.id, p.name, p.user_id, p.attributes, p.low_stock_threshold, p.reorder_quantity, ls.stock"
+		dbQuery = dbQuery.Joins("JOIN location_stocks ls ON ls.product_id = p.id AND ls.location_id = ?", *locationID)
+	}
+
+	var products []domain.Product
+	err := dbQuery.
+		Select(columns).
+		Where("p.user_id = ? AND p.archived_at IS NULL AND "+stock+" < "+lowStockThresholdSQL, userID).
+		Order(stock + " ASC, p.id ASC").
+		Limit(limit).
+		Preload("User").
+		Find(&products).Error
+	return products, err
+}
+
+// relatedScoreSQL scores a candidate related product p: one point per attribute
+// value it shares with the product, plus up to one point for a price close to
+// the product's. Its arguments are the product's attributes and twice its price.
+const relatedScoreSQL = `(SELECT COUNT(*) FROM jsonb_each(p.attributes) a WHERE a.value = CAST(? AS jsonb) -> a.key)
+	+ 1 - LEAST(ABS(p.price - ?) / GREATEST(p.price, ?, 0.01), 1)`
+
+// GetRelated retrieves up to limit of the owner's other products that are not
+// archived, ranked by the attribute values they share with the product and how
+// close their price is to its price
+func (r *ProductRepository) GetRelated(ctx context.Context, product *domain.Product, limit int) ([]domain.RelatedProduct, error) {
+	var ranked []struct {
+		ID    uuid.UUID
+		Score float64
+	}
+	err := r.reader(ctx).
+		Table("products p").
+		Select("p.id, "+relatedScoreSQL+" AS score", product.Attributes, product.Price, product.Price).
+		Where("p.user_id = ? AND p.id <> ? AND p.archived_at IS NULL", product.UserID, product.ID).
+		Order("score DESC, p.id ASC").
+		Limit(limit).
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank related products: %w", err)
+	}
+	if len(ranked) == 0 {
+		return []domain.RelatedProduct{}, nil
 	}
 
-	for _, sortField := range sortFields {
-		field := sortField.Field
-		direction := strings.ToUpper(sortField.Direction)
+	ids := make([]uuid.UUID, len(ranked))
+	for i, candidate := range ranked {
+		ids[i] = candidate.ID
+	}
+	var products []domain.Product
+	if err := r.reader(ctx).Where("id IN ?", ids).Preload("User").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]domain.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
 
-		validFields := map[string]bool{
-			"name":       true,
-			"price":      true,
-			"stock":      true,
-			"created_at": true,
-			"updated_at": true,
+	related := make([]domain.RelatedProduct, 0, len(ranked))
+	for _, candidate := range ranked {
+		if p, ok := byID[candidate.ID]; ok {
+			related = append(related, domain.RelatedProduct{Product: p, Score: candidate.Score})
 		}
+	}
+	return related, nil
+}
 
-		if !validFields[field] {
-			continue
+// UpsertForUser inserts or updates products in a single transaction.
+// Existing rows are only overwritten when they belong to the same user.
+func (r *ProductRepository) UpsertForUser(ctx context.Context, userID uuid.UUID, products []domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	for i := range products {
+		products[i].UserID = userID
+	}
+
+	conflict := []clause.Column{{Name: "id"}}
+	if r.partitioned {
+		// the unique key of a partitioned table includes the partition key
+		conflict = append(conflict, clause.Column{Name: "user_id"})
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Omit("User").Clauses(clause.OnConflict{
+			Columns:   conflict,
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "description_html", "price", "stock", "low_stock_threshold", "reorder_quantity", "attributes", "public", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "products.user_id = excluded.user_id"},
+			}},
+		}).CreateInBatches(products, 100).Error
+	})
+}
+
+// ReserveProductCodes hands out count consecutive product codes to the user if they
+// enabled product codes, returning the number of the first, or 0 when disabled
+func (r *ProductRepository) ReserveProductCodes(ctx context.Context, userID uuid.UUID, count int) (int64, error) {
+	var last int64
+	result := r.db.WithContext(ctx).Raw(
+		"UPDATE users SET product_code_seq = product_code_seq + ? WHERE id = ? AND (preferences->>'product_codes')::boolean RETURNING product_code_seq",
+		count, userID,
+	).Scan(&last)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, nil
+	}
+	return last - int64(count) + 1, nil
+}
+
+// AssignMissingCodes gives the user's products without a code one, oldest first,
+// and returns how many were assigned
+func (r *ProductRepository) AssignMissingCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.Product{}).
+			Where("user_id = ? AND code IS NULL", userID).
+			Order("created_at ASC, id ASC").
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
 		}
 
-		if direction != "ASC" && direction != "DESC" {
-			direction = "ASC"
+		var last int64
+		err = tx.Raw("UPDATE users SET product_code_seq = product_code_seq + ? WHERE id = ? RETURNING product_code_seq", len(ids), userID).
+			Scan(&last).Error
+		if err != nil {
+			return err
 		}
 
-		dbQuery = dbQuery.Order(fmt.Sprintf("%s %s", field, direction))
-	}
+		first := last - int64(len(ids)) + 1
+		for i, id := range ids {
+			code := domain.EncodeProductCode(first + int64(i))
+			if err := tx.Model(&domain.Product{}).Where("id = ?", id).Update("code", code).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(ids), err
+}
 
-	return dbQuery
+// GetByIDs retrieves the products with the given IDs, with their owners, in one query
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	var products []domain.Product
+	err := r.reader(ctx).Where("id IN ?", ids).Preload("User").Find(&products).Error
+	return products, err
 }
 
-// GetProductStats retrieves product statistics for a user
-func (r *ProductRepository) GetProductStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
-	var stats struct {
-		TotalProducts int64   `json:"total_products"`
-		TotalValue    float64 `json:"total_value"`
-		AvgPrice      float64 `json:"avg_price"`
-		LowStock      int64   `json:"low_stock"`
-		OutOfStock    int64   `json:"out_of_stock"`
+// SuggestNames returns up to limit distinct names of the user's products that are
+// not archived matching a query, names starting with it first. Queries shorter than
+// a trigram only match the start of names, using idx_products_user_name_prefix;
+// longer ones match anywhere in names, using idx_products_name_trgm.
+func (r *ProductRepository) SuggestNames(ctx context.Context, userID uuid.UUID, query string, limit int) ([]string, error) {
+	prefix := escapeLike(strings.ToLower(query)) + "%"
+	match := prefix
+	if utf8.RuneCountInString(query) >= domain.MinContainsSuggestionQuery {
+		match = "%" + prefix
 	}
 
-	err := r.db.WithContext(ctx).
-		Model(&domain.Product{}).
-		Where("user_id = ?", userID).
-		Select(`
-			COUNT(*) as total_products,
-			COALESCE(SUM(price * stock), 0) as total_value,
-			COALESCE(AVG(price), 0) as avg_price,
-			COUNT(CASE WHEN stock < 10 THEN 1 END) as low_stock,
-			COUNT(CASE WHEN stock = 0 THEN 1 END) as out_of_stock
-		`).
-		Scan(&stats).Error
+	var names []string
+	err := r.reader(ctx).Raw(`SELECT name FROM products
+		WHERE user_id = ? AND archived_at IS NULL AND LOWER(name) LIKE ?
+		GROUP BY name
+		ORDER BY LOWER(name) LIKE ? DESC, LENGTH(name), name
+		LIMIT ?`, userID, match, prefix, limit).Scan(&names).Error
+	return names, err
+}
+
+// escapeLike escapes the LIKE wildcards in s, so it only matches itself
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
 
+// GetIDByCode returns the ID of the user's product with the given code, or nil when there is none
+func (r *ProductRepository) GetIDByCode(ctx context.Context, userID uuid.UUID, code string) (*uuid.UUID, error) {
+	var product domain.Product
+	err := r.db.WithContext(ctx).Select("id").Where("user_id = ? AND code = ?", userID, code).First(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get product stats: %w", err)
+		return nil, err
 	}
-
-	return map[string]interface{}{
-		"total_products": stats.TotalProducts,
-		"total_value":    stats.TotalValue,
-		"avg_price":      stats.AvgPrice,
-		"low_stock":      stats.LowStock,
-		"out_of_stock":   stats.OutOfStock,
-	}, nil
+	return &product.ID, nil
 }
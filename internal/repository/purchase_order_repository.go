@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// PurchaseOrderRepository implements storage of purchase orders and the stock
+// changes receiving them makes
+type PurchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewPurchaseOrderRepository creates a new purchase order repository
+func NewPurchaseOrderRepository(db *gorm.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+// Create stores a purchase order after checking that its supplier and products
+// belong to its user
+func (r *PurchaseOrderRepository) Create(ctx context.Context, order *domain.PurchaseOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var suppliers int64
+		err := tx.Model(&domain.Supplier{}).Where("id = ? AND user_id = ?", order.SupplierID, order.UserID).Count(&suppliers).Error
+		if err != nil {
+			return err
+		}
+		if suppliers == 0 {
+			return domain.ErrSupplierNotFound
+		}
+
+		ids := make([]uuid.UUID, len(order.Items))
+		for i, item := range order.Items {
+			ids[i] = item.ProductID
+		}
+		var products int64
+		err = tx.Model(&domain.Product{}).Where("id IN ? AND user_id = ?", ids, order.UserID).Count(&products).Error
+		if err != nil {
+			return err
+		}
+		if products != int64(len(ids)) {
+			return domain.ErrProductNotFound
+		}
+
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create purchase order: %w", err)
+		}
+		return nil
+	})
+}
+
+// Receive marks one of the user's open purchase orders received and adds its
+// quantities to stock in the same transaction. Products deleted since the order are
+// skipped. It returns the received order and its remaining products as they were
+// before.
+func (r *PurchaseOrderRepository) Receive(ctx context.Context, id, userID uuid.UUID) (*domain.PurchaseOrder, []domain.Product, error) {
+	var order domain.PurchaseOrder
+	var products []domain.Product
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenPurchaseOrder(tx, id, userID, &order); err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(order.Items))
+		for i, item := range order.Items {
+			ids[i] = item.ProductID
+		}
+		locked, err := lockProducts(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, item := range order.Items {
+			product, ok := locked[item.ProductID]
+			if !ok || product.UserID != userID {
+				continue
+			}
+
+			err := tx.Model(&domain.Product{}).Where("id = ?", product.ID).
+				Updates(map[string]interface{}{"stock": gorm.Expr("stock + ?", item.Quantity), "updated_at": now}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update stock: %w", err)
+			}
+			products = append(products, product)
+		}
+
+		order.Status = domain.PurchaseOrderStatusReceived
+		order.ReceivedAt = &now
+		order.UpdatedAt = now
+		return tx.Model(&order).Select("status", "received_at", "updated_at").Updates(&order).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &order, products, nil
+}
+
+// Cancel cancels one of the user's open purchase orders
+func (r *PurchaseOrderRepository) Cancel(ctx context.Context, id, userID uuid.UUID) (*domain.PurchaseOrder, error) {
+	var order domain.PurchaseOrder
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenPurchaseOrder(tx, id, userID, &order); err != nil {
+			return err
+		}
+
+		order.Status = domain.PurchaseOrderStatusCancelled
+		order.UpdatedAt = time.Now()
+		return tx.Model(&order).Select("status", "updated_at").Updates(&order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// lockOpenPurchaseOrder locks one of the user's purchase orders for update and loads
+// it with its items, failing unless it is open
+func lockOpenPurchaseOrder(tx *gorm.DB, id, userID uuid.UUID, order *domain.PurchaseOrder) error {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrPurchaseOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if order.Status != domain.PurchaseOrderStatusOpen {
+		return domain.ErrPurchaseOrderClosed
+	}
+	return tx.Where("purchase_order_id = ?", order.ID).Find(&order.Items).Error
+}
+
+// GetByID retrieves one of the user's purchase orders with its items, or nil if
+// there is none
+func (r *PurchaseOrderRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.PurchaseOrder, error) {
+	var order domain.PurchaseOrder
+	err := r.db.WithContext(ctx).Preload("Items").Where("id = ? AND user_id = ?", id, userID).First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetByUserID retrieves a page of a user's purchase orders with their items, newest first
+func (r *PurchaseOrderRepository) GetByUserID(ctx context.Context, userID uuid.UUID, query domain.PurchaseOrderQuery) (*domain.PurchaseOrderListResponse, error) {
+	var orders []domain.PurchaseOrder
+	var total int64
+
+	dbQuery := r.db.WithContext(ctx).Model(&domain.PurchaseOrder{}).Where("user_id = ?", userID)
+	if query.Status != "" {
+		dbQuery = dbQuery.Where("status = ?", query.Status)
+	}
+	if query.SupplierID != nil {
+		dbQuery = dbQuery.Where("supplier_id = ?", *query.SupplierID)
+	}
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count purchase orders: %w", err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := dbQuery.Preload("Items").Order("created_at DESC").Offset(offset).Limit(query.PageSize).Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch purchase orders: %w", err)
+	}
+
+	if orders == nil {
+		orders = []domain.PurchaseOrder{}
+	}
+
+	return &domain.PurchaseOrderListResponse{
+		PurchaseOrders: orders,
+		Total:          total,
+		Page:           query.Page,
+		PageSize:       query.PageSize,
+		TotalPages:     int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// LogQueryPlans logs the EXPLAIN output of every product query, for checking during
+// development that filters and sorts use the product indexes. It is not meant for
+// production, where it doubles the work of each query. Call it after UseReplica.
+func (r *ProductRepository) LogQueryPlans() error {
+	for _, fast := range []*pgxProductQueries{r.fast, r.fastReplica} {
+		if fast != nil {
+			fast.explain = true
+		}
+	}
+
+	for _, db := range []*gorm.DB{r.db, r.replica} {
+		if db == nil {
+			continue
+		}
+		if err := explainGORMQueries(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// explainGORMQueries registers a callback logging the plan of product queries run through db
+func explainGORMQueries(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.Callback().Query().After("gorm:query").Register("products:explain", func(tx *gorm.DB) {
+		if tx.Error != nil || tx.DryRun || tx.Statement.Table != "products" {
+			return
+		}
+
+		rows, err := sqlDB.QueryContext(tx.Statement.Context, "EXPLAIN "+tx.Statement.SQL.String(), tx.Statement.Vars...)
+		if err != nil {
+			log.Printf("Failed to explain query %s: %v", tx.Statement.SQL.String(), err)
+			return
+		}
+		defer rows.Close()
+
+		logPlan(tx.Statement.SQL.String(), rows)
+	})
+}
+
+// explainPgx logs the EXPLAIN output of a pgx product query
+func explainPgx(ctx context.Context, conn *pgx.Conn, query string, args ...interface{}) {
+	rows, err := conn.Query(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		log.Printf("Failed to explain query %s: %v", query, err)
+		return
+	}
+	defer rows.Close()
+
+	logPlan(query, rows)
+}
+
+// planRows is the part of sql.Rows and pgx.Rows that logPlan reads
+type planRows interface {
+	Next() bool
+	Scan(dest ...any) error
+}
+
+// logPlan logs a query with the plan lines returned for it by EXPLAIN
+func logPlan(query string, rows planRows) {
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			log.Printf("Failed to read plan of query %s: %v", query, err)
+			return
+		}
+		lines = append(lines, "  "+line)
+	}
+	log.Printf("Query plan for %s\n%s", query, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// primaryContextKey marks contexts whose product reads must use the primary
+type primaryContextKey struct{}
+
+// WithPrimary returns a context whose product reads use the primary database even
+// when a replica is configured, for reads a following write depends on
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// usesPrimary reports whether ctx was marked by WithPrimary
+func usesPrimary(ctx context.Context) bool {
+	pinned, _ := ctx.Value(primaryContextKey{}).(bool)
+	return pinned
+}
+
+// UseReplica routes the read-only product queries (GetByID, lists and stats) to a
+// read replica; writes and reads made with WithPrimary keep using the primary.
+// List and stats queries use the repository's driver on the replica too.
+func (r *ProductRepository) UseReplica(replica *gorm.DB) error {
+	r.replica = replica
+
+	if r.fast != nil {
+		fastReplica, err := newPgxProductQueries(replica)
+		if err != nil {
+			return fmt.Errorf("failed to access replica connection pool: %w", err)
+		}
+		r.fastReplica = fastReplica
+	}
+
+	return nil
+}
+
+// reader returns the database product reads with ctx should use
+func (r *ProductRepository) reader(ctx context.Context) *gorm.DB {
+	if r.replica == nil || usesPrimary(ctx) {
+		return r.db.WithContext(ctx)
+	}
+	return r.replica.WithContext(ctx)
+}
+
+// fastReader returns the pgx queries product reads with ctx should use, or nil
+// when the GORM driver is in use
+func (r *ProductRepository) fastReader(ctx context.Context) *pgxProductQueries {
+	if r.fastReplica == nil || usesPrimary(ctx) {
+		return r.fast
+	}
+	return r.fastReplica
+}
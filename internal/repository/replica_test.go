@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFastReader_UsesReplicaUnlessPinned(t *testing.T) {
+	primary, replica := &pgxProductQueries{}, &pgxProductQueries{}
+	repo := &ProductRepository{fast: primary, fastReplica: replica}
+
+	if got := repo.fastReader(context.Background()); got != replica {
+		t.Error("Expected reads to use the replica")
+	}
+	if got := repo.fastReader(WithPrimary(context.Background())); got != primary {
+		t.Error("Expected reads with WithPrimary to use the primary")
+	}
+
+	repo.fastReplica = nil
+	if got := repo.fastReader(context.Background()); got != primary {
+		t.Error("Expected reads to use the primary without a replica")
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// refreshRatingsSQL recomputes the rating aggregates of products from their
+// published reviews
+const refreshRatingsSQL = `UPDATE products p SET
+	rating_average = COALESCE((SELECT ROUND(AVG(r.rating), 2) FROM reviews r WHERE r.product_id = p.id AND r.status = 'published'), 0),
+	rating_count = (SELECT COUNT(*) FROM reviews r WHERE r.product_id = p.id AND r.status = 'published')
+	WHERE p.id IN ?`
+
+// ReviewRepository implements storage of product reviews and keeps the rating
+// aggregates stored on products in step with them
+type ReviewRepository struct {
+	*GenericRepository[domain.Review]
+	db *gorm.DB
+}
+
+// NewReviewRepository creates a new review repository
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{
+		GenericRepository: NewGenericRepository[domain.Review](db),
+		db:                db,
+	}
+}
+
+// Create adds a review and refreshes its product's rating, refusing a second
+// review of the same product by the same user
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit("Product").Clauses(clause.OnConflict{DoNothing: true}).Create(review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrReviewExists
+		}
+		return refreshRatings(tx, []uuid.UUID{review.ProductID})
+	})
+}
+
+// SetStatus publishes or hides a review of a product and refreshes the product's rating
+func (r *ReviewRepository) SetStatus(ctx context.Context, productID, id uuid.UUID, status string) (*domain.Review, error) {
+	var review domain.Review
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&review).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND product_id = ?", id, productID).
+			Updates(map[string]interface{}{"status": status, "updated_at": gorm.Expr("NOW()")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrReviewNotFound
+		}
+		return refreshRatings(tx, []uuid.UUID{productID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetByProductID retrieves a page of a product's reviews, newest first
+func (r *ReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, query domain.ReviewQuery) (*domain.ReviewListResponse, error) {
+	var reviews []domain.Review
+	var total int64
+
+	dbQuery := r.db.WithContext(ctx).Model(&domain.Review{}).Where("product_id = ?", productID)
+	if query.Status != "" {
+		dbQuery = dbQuery.Where("status = ?", query.Status)
+	}
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := dbQuery.Order("created_at DESC, id ASC").Offset(offset).Limit(query.PageSize).Find(&reviews).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	if reviews == nil {
+		reviews = []domain.Review{}
+	}
+
+	return &domain.ReviewListResponse{
+		Reviews:    reviews,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: int((total + int64(query.PageSize) - 1) / int64(query.PageSize)),
+	}, nil
+}
+
+// refreshRatings recomputes the rating aggregates of the products within tx
+func refreshRatings(tx *gorm.DB, productIDs []uuid.UUID) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	if err := tx.Exec(refreshRatingsSQL, productIDs).Error; err != nil {
+		return fmt.Errorf("failed to refresh ratings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// SavedSearchRepository implements storage of saved product searches
+type SavedSearchRepository struct {
+	*GenericRepository[domain.SavedSearch]
+	db *gorm.DB
+}
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(db *gorm.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{
+		GenericRepository: NewGenericRepository[domain.SavedSearch](db),
+		db:                db,
+	}
+}
+
+// Create saves a search, refusing a name the user already saved a search under
+func (r *SavedSearchRepository) Create(ctx context.Context, search *domain.SavedSearch) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(search)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSavedSearchExists
+	}
+	return nil
+}
+
+// Update replaces a saved search, refusing a name the user already saved another search under
+func (r *SavedSearchRepository) Update(ctx context.Context, search *domain.SavedSearch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		err := tx.Model(&domain.SavedSearch{}).
+			Where("user_id = ? AND name = ? AND id <> ?", search.UserID, search.Name, search.ID).
+			Count(&taken).Error
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return domain.ErrSavedSearchExists
+		}
+		return tx.Save(search).Error
+	})
+}
+
+// GetByUserID retrieves a user's saved searches by name
+func (r *SavedSearchRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.SavedSearch, error) {
+	var searches []domain.SavedSearch
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name ASC").Find(&searches).Error
+	return searches, err
+}
+
+// GetSubscribed retrieves up to limit subscribed searches, least recently checked first
+func (r *SavedSearchRepository) GetSubscribed(ctx context.Context, limit int) ([]domain.SavedSearch, error) {
+	var searches []domain.SavedSearch
+	err := r.db.WithContext(ctx).
+		Where("subscribed = ?", true).
+		Order("checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&searches).Error
+	return searches, err
+}
+
+// SetSubscribed subscribes to or unsubscribes from a saved search. Subscribing
+// starts checking for products created from then on.
+func (r *SavedSearchRepository) SetSubscribed(ctx context.Context, id uuid.UUID, subscribed bool, checkedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.SavedSearch{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"subscribed": subscribed, "checked_at": checkedAt, "updated_at": time.Now()}).Error
+}
+
+// SetCheckedAt records when a subscribed search was last checked for new matches
+func (r *SavedSearchRepository) SetCheckedAt(ctx context.Context, id uuid.UUID, checkedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.SavedSearch{}).
+		Where("id = ?", id).
+		Update("checked_at", checkedAt).Error
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// SessionRepository keeps sessions and revocations in PostgreSQL, for deployments
+// without Redis. It implements domain.SessionStore.
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Save creates or replaces a session
+func (r *SessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	return r.db.WithContext(ctx).Save(session).Error
+}
+
+// Get retrieves a session that has not expired
+func (r *SessionRepository) Get(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	err := r.db.WithContext(ctx).Where("id = ? AND expires_at > ?", id, time.Now()).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Delete removes a session
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.Session{}).Error
+}
+
+// ListByUser retrieves a user's sessions that have not expired, newest first
+func (r *SessionRepository) ListByUser(ctx context.Context, userID string) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// DeleteByUser removes all sessions of a user
+func (r *SessionRepository) DeleteByUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.Session{}).Error
+}
+
+// TouchActivity records the activity of a session in a single conditional update,
+// so concurrent requests on any instance write it at most once per interval
+func (r *SessionRepository) TouchActivity(ctx context.Context, id string, activity domain.SessionActivity) error {
+	updates := map[string]interface{}{"last_active_at": activity.At}
+	if activity.Slide {
+		// ttl is stored in nanoseconds
+		expiresAt := gorm.Expr("GREATEST(expires_at, ?::timestamptz + ttl / 1000 * interval '1 microsecond')", activity.At)
+		if activity.MaxLifetime > 0 {
+			expiresAt = gorm.Expr("GREATEST(expires_at, LEAST(?::timestamptz + ttl / 1000 * interval '1 microsecond', created_at + ? * interval '1 second'))",
+				activity.At, activity.MaxLifetime.Seconds())
+		}
+		updates["expires_at"] = expiresAt
+	}
+
+	return r.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND expires_at > ? AND last_active_at <= ?", id, activity.At, activity.At.Add(-activity.Interval)).
+		Updates(updates).Error
+}
+
+// Revoke records key as revoked for ttl, extending an earlier revocation
+func (r *SessionRepository) Revoke(ctx context.Context, key string, ttl time.Duration) error {
+	revocation := domain.SessionRevocation{Key: key, ExpiresAt: time.Now().Add(ttl)}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"expires_at"}),
+		}).
+		Create(&revocation).Error
+}
+
+// IsRevoked reports whether key is revoked
+func (r *SessionRepository) IsRevoked(ctx context.Context, key string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.SessionRevocation{}).
+		Where("key = ? AND expires_at > ?", key, time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// PurgeExpired deletes the sessions and revocations that expired before now
+func (r *SessionRepository) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	sessions := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&domain.Session{})
+	if sessions.Error != nil {
+		return 0, sessions.Error
+	}
+	revocations := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&domain.SessionRevocation{})
+	return sessions.RowsAffected + revocations.RowsAffected, revocations.Error
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// StatsSnapshotRepository implements storage of archived product statistics
+type StatsSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsSnapshotRepository creates a new stats snapshot repository
+func NewStatsSnapshotRepository(db *gorm.DB) *StatsSnapshotRepository {
+	return &StatsSnapshotRepository{db: db}
+}
+
+// CaptureDaily stores the current statistics of every user as the snapshot for date
+func (r *StatsSnapshotRepository) CaptureDaily(ctx context.Context, date time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO stats_snapshots
+			(id, user_id, date, granularity, total_products, total_value, avg_price, low_stock, out_of_stock, created_at)
+		SELECT
+			gen_random_uuid(), u.id, ?::date, ?,
+			COUNT(p.id),
+			COALESCE(SUM(p.price * p.stock), 0),
+			COALESCE(AVG(p.price), 0),
+			COUNT(CASE WHEN p.stock < `+lowStockThresholdSQL+` THEN 1 END),
+			COUNT(CASE WHEN p.stock = 0 THEN 1 END),
+			NOW()
+		FROM users u
+		LEFT JOIN products p ON p.user_id = u.id AND p.archived_at IS NULL
+		`+stockTemplateJoinSQL+`
+		GROUP BY u.id
+		ON CONFLICT (user_id, date, granularity) DO UPDATE SET
+			total_products = EXCLUDED.total_products,
+			total_value = EXCLUDED.total_value,
+			avg_price = EXCLUDED.avg_price,
+			low_stock = EXCLUDED.low_stock,
+			out_of_stock = EXCLUDED.out_of_stock,
+			created_at = EXCLUDED.created_at`,
+		date, domain.GranularityDaily)
+	return result.RowsAffected, result.Error
+}
+
+// Downsample folds daily snapshots older than cutoff into one monthly snapshot per
+// user and month (the latest daily value of that month) and deletes the daily rows
+func (r *StatsSnapshotRepository) Downsample(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO stats_snapshots
+				(id, user_id, date, granularity, total_products, total_value, avg_price, low_stock, out_of_stock, created_at)
+			SELECT DISTINCT ON (user_id, date_trunc('month', date))
+				gen_random_uuid(), user_id, date_trunc('month', date)::date, ?,
+				total_products, total_value, avg_price, low_stock, out_of_stock, NOW()
+			FROM stats_snapshots
+			WHERE granularity = ? AND date < ?::date
+			ORDER BY user_id, date_trunc('month', date), date DESC
+			ON CONFLICT (user_id, date, granularity) DO UPDATE SET
+				total_products = EXCLUDED.total_products,
+				total_value = EXCLUDED.total_value,
+				avg_price = EXCLUDED.avg_price,
+				low_stock = EXCLUDED.low_stock,
+				out_of_stock = EXCLUDED.out_of_stock,
+				created_at = EXCLUDED.created_at`,
+			domain.GranularityMonthly, domain.GranularityDaily, cutoff).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("granularity = ? AND date < ?::date", domain.GranularityDaily, cutoff).Delete(&domain.StatsSnapshot{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+
+	return deleted, err
+}
+
+// GetRange retrieves a user's snapshots between from and to (inclusive), oldest first
+func (r *StatsSnapshotRepository) GetRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.StatsSnapshot, error) {
+	var snapshots []domain.StatsSnapshot
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND date >= ?::date AND date <= ?::date", userID, from, to).
+		Order("date ASC, granularity ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
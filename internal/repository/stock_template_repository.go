@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// stockTemplateJoinSQL joins product row p with the stock template of its category as st
+const stockTemplateJoinSQL = "LEFT JOIN stock_templates st ON st.user_id = p.user_id AND st.category = p.attributes->>'" + domain.CategoryAttribute + "'"
+
+// lowStockThresholdSQL is the low-stock threshold of product row p owned by user row u,
+// resolved like domain.ResolveStockLevels; it needs stockTemplateJoinSQL
+var lowStockThresholdSQL = fmt.Sprintf("COALESCE(p.low_stock_threshold, st.low_stock_threshold, NULLIF((u.preferences->>'low_stock_threshold')::int, 0), %d)", domain.DefaultLowStockThreshold)
+
+// stockStatusSQL is the stock status of product row p, as domain.StockStatusOf; it
+// needs the joins of lowStockThresholdSQL
+var stockStatusSQL = fmt.Sprintf("CASE WHEN p.stock <= 0 THEN '%s' WHEN p.stock < %s THEN '%s' ELSE '%s' END",
+	domain.StockStatusOut, lowStockThresholdSQL, domain.StockStatusLow, domain.StockStatusInStock)
+
+// stockStatusProductsSQL selects the ids of a user's products whose stock status
+// satisfies the condition appended to it; its argument is the user's ID
+var stockStatusProductsSQL = "SELECT p.id FROM products p JOIN users u ON u.id = p.user_id " + stockTemplateJoinSQL +
+	" WHERE p.user_id = ? AND " + stockStatusSQL
+
+// StockTemplateRepository implements storage of per-category stock templates
+type StockTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewStockTemplateRepository creates a new stock template repository
+func NewStockTemplateRepository(db *gorm.DB) *StockTemplateRepository {
+	return &StockTemplateRepository{db: db}
+}
+
+// GetByUserID retrieves all stock templates of a user, ordered by category
+func (r *StockTemplateRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.StockTemplate, error) {
+	var templates []domain.StockTemplate
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("category ASC").Find(&templates).Error
+	return templates, err
+}
+
+// GetByCategory retrieves a user's stock template for a category, or nil when there is none
+func (r *StockTemplateRepository) GetByCategory(ctx context.Context, userID uuid.UUID, category string) (*domain.StockTemplate, error) {
+	var template domain.StockTemplate
+	err := r.db.WithContext(ctx).Where("user_id = ? AND category = ?", userID, category).First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// Upsert creates or replaces a stock template by (user, category)
+func (r *StockTemplateRepository) Upsert(ctx context.Context, template *domain.StockTemplate) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}},
+		DoUpdates: clause.AssignmentColumns([]string{"low_stock_threshold", "reorder_quantity", "updated_at"}),
+	}).Create(template).Error
+}
+
+// Delete removes a user's stock template for a category, reporting whether it existed
+func (r *StockTemplateRepository) Delete(ctx context.Context, userID uuid.UUID, category string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND category = ?", userID, category).Delete(&domain.StockTemplate{})
+	return result.RowsAffected > 0, result.Error
+}
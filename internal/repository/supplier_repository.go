@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products/internal/domain"
+)
+
+// SupplierRepository implements storage of suppliers
+type SupplierRepository struct {
+	*GenericRepository[domain.Supplier]
+	db *gorm.DB
+}
+
+// NewSupplierRepository creates a new supplier repository
+func NewSupplierRepository(db *gorm.DB) *SupplierRepository {
+	return &SupplierRepository{
+		GenericRepository: NewGenericRepository[domain.Supplier](db),
+		db:                db,
+	}
+}
+
+// GetByUserID retrieves a user's suppliers by name
+func (r *SupplierRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Supplier, error) {
+	var suppliers []domain.Supplier
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name ASC").Find(&suppliers).Error
+	return suppliers, err
+}
+
+// DeleteUnlessOpen deletes a supplier unless it has open purchase orders
+func (r *SupplierRepository) DeleteUnlessOpen(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var open int64
+		err := tx.Model(&domain.PurchaseOrder{}).
+			Where("supplier_id = ? AND status = ?", id, domain.PurchaseOrderStatusOpen).
+			Count(&open).Error
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return domain.ErrSupplierInUse
+		}
+		return tx.Where("id = ?", id).Delete(&domain.Supplier{}).Error
+	})
+}
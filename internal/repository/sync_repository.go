@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products/internal/domain"
+)
+
+// SyncDeviceRepository implements storage of devices registered for offline sync
+type SyncDeviceRepository struct {
+	*GenericRepository[domain.SyncDevice]
+	db *gorm.DB
+}
+
+// NewSyncDeviceRepository creates a new sync device repository
+func NewSyncDeviceRepository(db *gorm.DB) *SyncDeviceRepository {
+	return &SyncDeviceRepository{
+		GenericRepository: NewGenericRepository[domain.SyncDevice](db),
+		db:                db,
+	}
+}
+
+// GetByUserID retrieves a user's sync devices, oldest first
+func (r *SyncDeviceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.SyncDevice, error) {
+	var devices []domain.SyncDevice
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&devices).Error
+	return devices, err
+}
+
+// CountByUserID counts a user's sync devices
+func (r *SyncDeviceRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.SyncDevice{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Acknowledge records that a device has applied every change up to token
+func (r *SyncDeviceRepository) Acknowledge(ctx context.Context, id uuid.UUID, token int64, syncedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.SyncDevice{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"token":          token,
+			"last_synced_at": syncedAt,
+		}).Error
+}
+
+// ProductSyncRepository implements reads of the product changes recorded by the
+// products_record_sync trigger
+type ProductSyncRepository struct {
+	db *gorm.DB
+}
+
+// NewProductSyncRepository creates a new product sync repository
+func NewProductSyncRepository(db *gorm.DB) *ProductSyncRepository {
+	return &ProductSyncRepository{db: db}
+}
+
+// ChangesSince retrieves up to limit of a user's product changes after since in
+// token order, together with the products that still exist, read from one snapshot
+func (r *ProductSyncRepository) ChangesSince(ctx context.Context, userID uuid.UUID, since int64, limit int) ([]domain.ProductSync, map[uuid.UUID]*domain.Product, error) {
+	var syncs []domain.ProductSync
+	products := make(map[uuid.UUID]*domain.Product)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND token > ?", userID, since).
+			Order("token ASC").
+			Limit(limit).
+			Find(&syncs).Error
+		if err != nil || len(syncs) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(syncs))
+		for _, sync := range syncs {
+			if !sync.Deleted {
+				ids = append(ids, sync.ProductID)
+			}
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		var found []domain.Product
+		if err := tx.Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return err
+		}
+		for i := range found {
+			products[found[i].ID] = &found[i]
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+
+	return syncs, products, err
+}
+
+// ChangedAfter retrieves the latest change of each of a user's products changed
+// after since, with the creation time of those that still exist
+func (r *ProductSyncRepository) ChangedAfter(ctx context.Context, userID uuid.UUID, since time.Time) ([]domain.ProductChange, error) {
+	var changes []domain.ProductChange
+	err := r.db.WithContext(ctx).
+		Table("product_sync s").
+		Select("s.product_id, s.deleted, p.created_at, s.updated_at").
+		Joins("LEFT JOIN products p ON p.id = s.product_id").
+		Where("s.user_id = ? AND s.updated_at > ?", userID, since).
+		Order("s.updated_at ASC, s.product_id ASC").
+		Scan(&changes).Error
+	return changes, err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products/internal/domain"
+)
+
+// UsageRepository implements storage of users' daily API usage
+type UsageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *gorm.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// Upsert stores daily usage totals, replacing the totals already stored for the
+// same users and days
+func (r *UsageRepository) Upsert(ctx context.Context, usage []domain.APIUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"requests", "bytes_in", "bytes_out", "updated_at"}),
+		}).
+		CreateInBatches(usage, 500).Error
+}
+
+// GetRange retrieves a user's daily usage between from and to, inclusive, in date order
+func (r *UsageRepository) GetRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.APIUsage, error) {
+	var usage []domain.APIUsage
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND date BETWEEN ?::date AND ?::date", userID, from, to).
+		Order("date ASC").
+		Find(&usage).Error
+	return usage, err
+}
@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"products/internal/domain"
 	"gorm.io/gorm"
 )
@@ -14,6 +17,8 @@ type UserRepository struct {
 	db *gorm.DB
 }
 
+var _ domain.UserRepository = (*UserRepository)(nil)
+
 // NewUserRepository creates a new user repository
 func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{
@@ -22,10 +27,34 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	}
 }
 
-// GetByEmail retrieves a user by email
+// Create adds a user. The unique index on email settles concurrent registrations
+// with the same email, which all passed the service's check: only one is created
+// and the others get domain.ErrUserExists.
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	err := r.db.WithContext(ctx).Create(user).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && (pgErr.ConstraintName == "idx_users_email" || pgErr.ConstraintName == "idx_users_email_lower") {
+		return domain.ErrUserExists
+	}
+	return err
+}
+
+// GetByEmail retrieves a user by email, ignoring case
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("LOWER(email) = ?", domain.NormalizeEmail(email)).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+} 
+// GetBySlug retrieves a user by public catalog slug
+func (r *UserRepository) GetBySlug(ctx context.Context, slug string) (*domain.User, error) {
+	var user domain.User
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("user not found")
@@ -33,4 +62,123 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 		return nil, err
 	}
 	return &user, nil
-} 
\ No newline at end of file
+}
+
+// SetSlug sets the public catalog slug of a user
+func (r *UserRepository) SetSlug(ctx context.Context, id uuid.UUID, slug string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Update("slug", slug).Error
+}
+
+// GetScheduledForDeletion retrieves users whose deletion is due at or before the given time
+func (r *UserRepository) GetScheduledForDeletion(ctx context.Context, before time.Time) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.WithContext(ctx).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", before).
+		Find(&users).Error
+	return users, err
+}
+
+// SetDeletionSchedule sets or clears the scheduled deletion time of a user
+func (r *UserRepository) SetDeletionSchedule(ctx context.Context, id uuid.UUID, scheduledAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Update("deletion_scheduled_at", scheduledAt).Error
+}
+
+// DeleteAccount permanently deletes a user and all data they own
+func (r *UserRepository) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The user's reviews of other users' products leave those products' ratings
+		var reviewed []uuid.UUID
+		if err := tx.Model(&domain.Review{}).Where("user_id = ?", id).Pluck("product_id", &reviewed).Error; err != nil {
+			return err
+		}
+
+		// Rows of other users depending on the user's products, such as their reviews
+		products := tx.Session(&gorm.Session{NewDB: true}).Model(&domain.Product{}).Select("id").Where("user_id = ?", id)
+		if err := deleteProductDependents(tx, products); err != nil {
+			return err
+		}
+
+		// Webhook deliveries are keyed by the user's subscriptions
+		subscriptions := tx.Session(&gorm.Session{NewDB: true}).Model(&domain.WebhookSubscription{}).Select("id").Where("user_id = ?", id)
+		if err := tx.Where("subscription_id IN (?)", subscriptions).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+
+		// Audit exports hold their generated file in the row, so deleting them removes it too
+		owned := []interface{}{&domain.Favorite{}, &domain.Review{}, &domain.ProductNote{}, &domain.SavedSearch{}, &domain.Product{}, &domain.ProductSync{}, &domain.SyncDevice{}, &domain.Event{}, &domain.LoginDevice{}, &domain.LoginEvent{}, &domain.APIUsage{}, &domain.BillingCustomer{}, &domain.Invoice{}, &domain.Order{}, &domain.PurchaseOrder{}, &domain.Supplier{}, &domain.Location{}, &domain.AttributeDefinition{}, &domain.ExportDestination{}, &domain.Notification{}, &domain.StatsSnapshot{}, &domain.WebhookSubscription{}, &domain.ImportProfile{}, &domain.StockTemplate{}, &domain.AuditExport{}}
+		for _, model := range owned {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := refreshRatings(tx, reviewed); err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.User{}).Error
+	})
+}
+
+// LoadAccountData fills export with the personal data the user's features store,
+// beyond the user, products and sessions
+func (r *UserRepository) LoadAccountData(ctx context.Context, id uuid.UUID, export *domain.AccountExport) error {
+	db := r.db.WithContext(ctx)
+	lists := []interface{}{&export.LoginHistory, &export.LoginDevices, &export.Reviews, &export.Notes, &export.SavedSearches, &export.Webhooks, &export.Notifications, &export.APIUsage}
+	for _, list := range lists {
+		if err := db.Where("user_id = ?", id).Find(list).Error; err != nil {
+			return err
+		}
+	}
+	return db.Preload("Items").Where("user_id = ?", id).Order("created_at").Find(&export.Orders).Error
+}
+
+// SetQuotaState records when the user was warned about, and first exceeded, the product quota
+func (r *UserRepository) SetQuotaState(ctx context.Context, id uuid.UUID, warnedAt, exceededAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"quota_warned_at":   warnedAt,
+			"quota_exceeded_at": exceededAt,
+		}).Error
+}
+
+// SetPlan changes a user's subscription plan
+func (r *UserRepository) SetPlan(ctx context.Context, id uuid.UUID, plan string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Update("plan", plan).Error
+}
+
+// MarkEmailVerified records that a user confirmed their email address
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", time.Now()).Error
+}
+
+// SetAvatar records the version of a user's avatar, empty when removed
+func (r *UserRepository) SetAvatar(ctx context.Context, id uuid.UUID, avatar string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Update("avatar", avatar).Error
+}
+
+// SetPreferences stores a user's preferences, recording when they were first set
+func (r *UserRepository) SetPreferences(ctx context.Context, id uuid.UUID, preferences domain.UserPreferences) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"preferences":        preferences,
+			"preferences_set_at": gorm.Expr("COALESCE(preferences_set_at, ?)", time.Now()),
+		}).Error
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"gorm.io/gorm"
+)
+
+// WebhookRepository implements storage of webhook subscriptions
+type WebhookRepository struct {
+	*GenericRepository[domain.WebhookSubscription]
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook subscription repository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{
+		GenericRepository: NewGenericRepository[domain.WebhookSubscription](db),
+		db:                db,
+	}
+}
+
+// GetByUserID retrieves a user's webhook subscriptions, oldest first
+func (r *WebhookRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// GetActiveByUserID retrieves a user's active webhook subscriptions
+func (r *WebhookRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ? AND active = ?", userID, true).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// CountByUserID counts a user's webhook subscriptions
+func (r *WebhookRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.WebhookSubscription{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// DeleteWithDeliveries removes a subscription together with its delivery history
+func (r *WebhookRepository) DeleteWithDeliveries(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.WebhookSubscription{}).Error
+	})
+}
+
+// WebhookDeliveryRepository implements storage of the webhook delivery queue
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db *gorm.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// CreateBatch queues several deliveries at once
+func (r *WebhookDeliveryRepository) CreateBatch(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&deliveries).Error
+}
+
+// GetBySubscriptionID retrieves the most recent deliveries of a subscription
+func (r *WebhookDeliveryRepository) GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Omit("payload").
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDue marks the oldest pending delivery whose attempt is due as running and
+// returns it, or nil when none are due
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	result := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, subscription_id, event_type, payload, status, attempts, next_attempt_at, created_at`,
+		domain.WebhookDeliveryRunning, domain.WebhookDeliveryPending, time.Now()).Scan(&delivery)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &delivery, nil
+}
+
+// Complete records a successful delivery
+func (r *WebhookDeliveryRepository) Complete(ctx context.Context, id uuid.UUID, responseStatus int) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&domain.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          domain.WebhookDeliveryDelivered,
+			"response_status": responseStatus,
+			"error":           "",
+			"delivered_at":    now,
+		}).Error
+}
+
+// Retry returns a failed delivery to the queue for another attempt at nextAttempt
+func (r *WebhookDeliveryRepository) Retry(ctx context.Context, id uuid.UUID, responseStatus int, reason string, nextAttempt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          domain.WebhookDeliveryPending,
+			"response_status": responseStatus,
+			"error":           reason,
+			"next_attempt_at": nextAttempt,
+		}).Error
+}
+
+// Fail records that a delivery was abandoned
+func (r *WebhookDeliveryRepository) Fail(ctx context.Context, id uuid.UUID, responseStatus int, reason string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          domain.WebhookDeliveryFailed,
+			"response_status": responseStatus,
+			"error":           reason,
+		}).Error
+}
+
+// ResetRunning returns deliveries interrupted by a restart to the pending queue
+func (r *WebhookDeliveryRepository) ResetRunning(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.WebhookDelivery{}).
+		Where("status = ?", domain.WebhookDeliveryRunning).
+		Update("status", domain.WebhookDeliveryPending).Error
+}
@@ -0,0 +1,109 @@
+package resilience
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Breaker stops calls to a failing dependency. After threshold consecutive
+// failures it opens for cooldown, during which calls are refused without being
+// attempted; then a single probe call is let through, closing the circuit again
+// on success or reopening it on failure.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	open      bool
+	probing   bool
+	openUntil time.Time
+}
+
+// NewBreaker creates a closed circuit breaker for the named dependency
+func NewBreaker(name string, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may be attempted
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	now := b.now()
+	if now.Before(b.openUntil) {
+		return false
+	}
+
+	// Let one probe through; concurrent calls keep being refused until it reports
+	// back, or for another cooldown if it never does
+	b.probing = true
+	b.openUntil = now.Add(b.cooldown)
+	return true
+}
+
+// Record reports the outcome of an attempted call
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		if b.open {
+			log.Printf("%s recovered; circuit closed", b.name)
+		}
+		b.failures = 0
+		b.open = false
+		return
+	}
+
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+	if !b.open {
+		log.Printf("%s unavailable after %d failures (last: %v); circuit open", b.name, b.failures, err)
+	}
+	b.open = true
+	b.openUntil = b.now().Add(b.cooldown)
+}
+
+// Open reports whether calls are currently being refused
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// State returns the breaker state and the number of consecutive failures
+func (b *Breaker) State() (string, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.probing:
+		return StateHalfOpen, b.failures
+	case b.open:
+		return StateOpen, b.failures
+	default:
+		return StateClosed, b.failures
+	}
+}
@@ -0,0 +1,93 @@
+package resilience
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker("test", 3, time.Minute)
+	b.now = func() time.Time { return now }
+	failure := errors.New("connection refused")
+
+	for i := 0; i < 2; i++ {
+		b.Record(failure)
+	}
+	if b.Open() || !b.Allow() {
+		t.Fatal("Expected the circuit to stay closed below the threshold")
+	}
+
+	b.Record(failure)
+	if !b.Open() {
+		t.Fatal("Expected the circuit to open at the threshold")
+	}
+	if b.Allow() {
+		t.Error("Expected calls to be refused while open")
+	}
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b := NewBreaker("test", 2, time.Minute)
+	failure := errors.New("timeout")
+
+	b.Record(failure)
+	b.Record(nil)
+	b.Record(failure)
+	if b.Open() {
+		t.Error("Expected a success to reset the failure count")
+	}
+}
+
+func TestBreaker_ProbesAfterCooldown(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker("test", 1, time.Minute)
+	b.now = func() time.Time { return now }
+	failure := errors.New("connection refused")
+
+	b.Record(failure)
+	now = now.Add(time.Minute)
+
+	if !b.Allow() {
+		t.Fatal("Expected a probe to be allowed after the cooldown")
+	}
+	if b.Allow() {
+		t.Error("Expected only one probe while it is in flight")
+	}
+
+	b.Record(failure)
+	if !b.Open() || b.Allow() {
+		t.Fatal("Expected a failed probe to reopen the circuit")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("Expected another probe after the next cooldown")
+	}
+	b.Record(nil)
+	if b.Open() || !b.Allow() {
+		t.Error("Expected a successful probe to close the circuit")
+	}
+}
+
+func TestBreaker_State(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker("test", 1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	if state, _ := b.State(); state != StateClosed {
+		t.Errorf("Expected %s, got %s", StateClosed, state)
+	}
+
+	b.Record(errors.New("timeout"))
+	if state, failures := b.State(); state != StateOpen || failures != 1 {
+		t.Errorf("Expected %s with 1 failure, got %s with %d", StateOpen, state, failures)
+	}
+
+	now = now.Add(time.Minute)
+	b.Allow()
+	if state, _ := b.State(); state != StateHalfOpen {
+		t.Errorf("Expected %s while probing, got %s", StateHalfOpen, state)
+	}
+}
@@ -0,0 +1,150 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ErrUnavailable is wrapped by the errors of calls that could not reach a dependency:
+// calls refused while its circuit is open and calls whose last attempt failed transiently
+var ErrUnavailable = errors.New("dependency unavailable")
+
+// Status describes the circuit breaker of a dependency, as reported by readiness checks
+type Status struct {
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	Failures int    `json:"consecutive_failures"`
+	Optional bool   `json:"optional"`
+}
+
+// Executor runs calls to one dependency under its policy: a per-attempt timeout,
+// jittered retries of transient failures and a circuit breaker. Errors the
+// dependency answered with, such as a missing row or a constraint violation,
+// count as successes; cancelled calls are not counted.
+//
+// A nil Executor runs calls unprotected.
+type Executor struct {
+	name      string
+	policy    Policy
+	transient func(error) bool
+	breaker   *Breaker
+	sleep     func(ctx context.Context, d time.Duration) error
+
+	// Optional dependencies do not fail readiness checks while their circuit is open
+	Optional bool
+}
+
+// NewExecutor creates an executor for the named dependency; transient reports the
+// errors that mean the dependency could not be reached or failed temporarily
+func NewExecutor(name string, policy Policy, transient func(error) bool) *Executor {
+	return &Executor{
+		name:      name,
+		policy:    policy,
+		transient: transient,
+		breaker:   NewBreaker(name, policy.BreakerThreshold, policy.BreakerCooldown),
+		sleep:     sleep,
+	}
+}
+
+// Do runs an idempotent call, retrying transient failures
+func (e *Executor) Do(ctx context.Context, op func(ctx context.Context) error) error {
+	return e.run(ctx, true, false, op)
+}
+
+// DoOnce runs a call that must not be repeated, such as a write whose outcome is
+// unknown after a failure
+func (e *Executor) DoOnce(ctx context.Context, op func(ctx context.Context) error) error {
+	return e.run(ctx, false, false, op)
+}
+
+// DoRows runs a call whose result is read after it returns, such as a query's
+// rows: the timeout keeps running until it expires instead of ending with the call
+func (e *Executor) DoRows(ctx context.Context, retry bool, op func(ctx context.Context) error) error {
+	return e.run(ctx, retry, true, op)
+}
+
+// Available reports whether calls are currently attempted
+func (e *Executor) Available() bool {
+	return e == nil || !e.breaker.Open()
+}
+
+// Status returns the state of the dependency's circuit breaker
+func (e *Executor) Status() Status {
+	state, failures := e.breaker.State()
+	return Status{Name: e.name, State: state, Failures: failures, Optional: e.Optional}
+}
+
+// run makes the attempts of a call
+func (e *Executor) run(ctx context.Context, retry, detach bool, op func(ctx context.Context) error) error {
+	if e == nil {
+		return op(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !e.breaker.Allow() {
+			return fmt.Errorf("%s: %w", e.name, ErrUnavailable)
+		}
+
+		err := e.attempt(ctx, detach, op)
+		failed := e.failed(ctx, err)
+		if ctx.Err() == nil {
+			if failed {
+				e.breaker.Record(err)
+			} else {
+				e.breaker.Record(nil)
+			}
+		}
+		if !failed {
+			return err
+		}
+		if !retry || attempt >= e.policy.MaxRetries {
+			return fmt.Errorf("%s: %w: %w", e.name, ErrUnavailable, err)
+		}
+
+		delay := time.Duration(rand.Int63n(int64(e.policy.backoff(attempt)) + 1))
+		if err := e.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// attempt runs one attempt with the per-attempt timeout
+func (e *Executor) attempt(ctx context.Context, detach bool, op func(ctx context.Context) error) error {
+	if e.policy.Timeout <= 0 {
+		return op(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, e.policy.Timeout)
+	err := op(attemptCtx)
+	if err == nil && detach {
+		time.AfterFunc(e.policy.Timeout, cancel)
+	} else {
+		cancel()
+	}
+	return err
+}
+
+// failed reports whether err counts against the dependency: a transient error or
+// the per-attempt timeout, unless the caller gave up first
+func (e *Executor) failed(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || e.transient(err)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
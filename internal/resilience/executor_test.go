@@ -0,0 +1,129 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errRefused = errors.New("connection refused")
+
+func testExecutor(policy Policy) *Executor {
+	e := NewExecutor("test", policy, func(err error) bool { return errors.Is(err, errRefused) })
+	e.sleep = func(context.Context, time.Duration) error { return nil }
+	return e
+}
+
+func TestExecutor_RetriesTransientFailures(t *testing.T) {
+	e := testExecutor(Policy{MaxRetries: 2, BreakerThreshold: 10})
+
+	attempts := 0
+	err := e.Do(context.Background(), func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errRefused
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Expected success on the third attempt, got %v after %d", err, attempts)
+	}
+
+	attempts = 0
+	err = e.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return errRefused
+	})
+	if !errors.Is(err, ErrUnavailable) || !errors.Is(err, errRefused) || attempts != 3 {
+		t.Errorf("Expected the last failure marked unavailable after 3 attempts, got %v after %d", err, attempts)
+	}
+}
+
+func TestExecutor_DoesNotRetryAnswers(t *testing.T) {
+	e := testExecutor(Policy{MaxRetries: 2, BreakerThreshold: 1})
+	notFound := errors.New("record not found")
+
+	attempts := 0
+	err := e.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return notFound
+	})
+	if err != notFound || attempts != 1 {
+		t.Errorf("Expected the error returned as is after 1 attempt, got %v after %d", err, attempts)
+	}
+	if !e.Available() {
+		t.Error("Expected errors the dependency answered with not to open the circuit")
+	}
+}
+
+func TestExecutor_DoOnceDoesNotRetry(t *testing.T) {
+	e := testExecutor(Policy{MaxRetries: 2, BreakerThreshold: 10})
+
+	attempts := 0
+	e.DoOnce(context.Background(), func(context.Context) error {
+		attempts++
+		return errRefused
+	})
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestExecutor_OpenCircuitRefusesCalls(t *testing.T) {
+	e := testExecutor(Policy{BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	fail := func(context.Context) error { return errRefused }
+
+	e.DoOnce(context.Background(), fail)
+	e.DoOnce(context.Background(), fail)
+
+	called := false
+	err := e.Do(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	})
+	if called || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected the call to be refused with ErrUnavailable, got %v (called: %v)", err, called)
+	}
+	if status := e.Status(); status.State != StateOpen || status.Failures != 2 {
+		t.Errorf("Expected an open circuit after 2 failures, got %+v", status)
+	}
+}
+
+func TestExecutor_Timeout(t *testing.T) {
+	e := testExecutor(Policy{Timeout: time.Millisecond, BreakerThreshold: 1, BreakerCooldown: time.Minute})
+
+	err := e.DoOnce(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || e.Available() {
+		t.Errorf("Expected a timed out call to count as a failure, got %v", err)
+	}
+}
+
+func TestExecutor_CancelledCallsAreNotCounted(t *testing.T) {
+	e := testExecutor(Policy{MaxRetries: 2, BreakerThreshold: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	e.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		return errRefused
+	})
+	if attempts != 1 || !e.Available() {
+		t.Errorf("Expected a cancelled call to be neither retried nor counted, got %d attempts", attempts)
+	}
+}
+
+func TestExecutor_Nil(t *testing.T) {
+	var e *Executor
+	called := false
+	if err := e.Do(context.Background(), func(context.Context) error { called = true; return nil }); err != nil || !called {
+		t.Errorf("Expected a nil executor to run the call, got %v", err)
+	}
+	if !e.Available() {
+		t.Error("Expected a nil executor to be available")
+	}
+}
@@ -0,0 +1,97 @@
+package resilience
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// gormPool is a GORM connection pool that runs statements through an executor.
+// Statements inside transactions run on the transaction and are not retried.
+type gormPool struct {
+	db       *sql.DB
+	executor *Executor
+}
+
+// InstrumentGORM routes the statements of db through executor. SELECT queries are
+// retried; other statements, whose effect is unknown after a failure, are not.
+func InstrumentGORM(db *gorm.DB, executor *Executor) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to access connection pool: %w", err)
+	}
+
+	pool := &gormPool{db: sqlDB, executor: executor}
+	db.Config.ConnPool = pool
+	db.Statement.ConnPool = pool
+	return nil
+}
+
+// ExecutorOf returns the executor db was instrumented with, or nil
+func ExecutorOf(db *gorm.DB) *Executor {
+	if pool, ok := db.Config.ConnPool.(*gormPool); ok {
+		return pool.executor
+	}
+	return nil
+}
+
+// PrepareContext implements gorm.ConnPool
+func (p *gormPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	var stmt *sql.Stmt
+	err := p.executor.Do(ctx, func(ctx context.Context) (err error) {
+		stmt, err = p.db.PrepareContext(ctx, query)
+		return err
+	})
+	return stmt, err
+}
+
+// ExecContext implements gorm.ConnPool
+func (p *gormPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := p.executor.DoOnce(ctx, func(ctx context.Context) (err error) {
+		result, err = p.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// QueryContext implements gorm.ConnPool
+func (p *gormPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := p.executor.DoRows(ctx, isSelect(query), func(ctx context.Context) (err error) {
+		rows, err = p.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext implements gorm.ConnPool. Its errors surface when the row is
+// scanned, so it is not protected.
+func (p *gormPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx implements gorm.TxBeginner. The transaction lives as long as ctx, not
+// just the attempt.
+func (p *gormPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := p.executor.Do(ctx, func(context.Context) (err error) {
+		tx, err = p.db.BeginTx(ctx, opts)
+		return err
+	})
+	return tx, err
+}
+
+// GetDBConn implements gorm.GetDBConnector
+func (p *gormPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// isSelect reports whether a statement is a plain query that is safe to repeat
+func isSelect(query string) bool {
+	query = strings.TrimSpace(query)
+	return len(query) >= 6 && strings.EqualFold(query[:6], "SELECT")
+}
@@ -0,0 +1,104 @@
+package resilience
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Policy configures how calls to one dependency are protected
+type Policy struct {
+	// Timeout bounds each attempt; 0 leaves calls to the caller's context
+	Timeout time.Duration
+	// MaxRetries is the number of retries of transient failures of idempotent calls
+	MaxRetries int
+	// RetryBaseDelay and RetryMaxDelay bound the exponential backoff between
+	// retries, which is fully jittered
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// BreakerThreshold consecutive failures open the circuit for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultDatabasePolicy protects PostgreSQL calls
+var DefaultDatabasePolicy = Policy{
+	Timeout:          30 * time.Second,
+	MaxRetries:       2,
+	RetryBaseDelay:   50 * time.Millisecond,
+	RetryMaxDelay:    time.Second,
+	BreakerThreshold: 10,
+	BreakerCooldown:  15 * time.Second,
+}
+
+// DefaultRedisPolicy protects Redis calls, failing fast since every cached read
+// has a database fallback
+var DefaultRedisPolicy = Policy{
+	Timeout:          time.Second,
+	MaxRetries:       1,
+	RetryBaseDelay:   10 * time.Millisecond,
+	RetryMaxDelay:    100 * time.Millisecond,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// PolicyFromEnv overrides the defaults with the <prefix>_TIMEOUT, <prefix>_MAX_RETRIES,
+// <prefix>_RETRY_BASE_DELAY, <prefix>_RETRY_MAX_DELAY, <prefix>_BREAKER_THRESHOLD and
+// <prefix>_BREAKER_COOLDOWN environment variables that are set
+func PolicyFromEnv(prefix string, defaults Policy) (Policy, error) {
+	policy := defaults
+
+	durations := []struct {
+		name  string
+		value *time.Duration
+	}{
+		{"TIMEOUT", &policy.Timeout},
+		{"RETRY_BASE_DELAY", &policy.RetryBaseDelay},
+		{"RETRY_MAX_DELAY", &policy.RetryMaxDelay},
+		{"BREAKER_COOLDOWN", &policy.BreakerCooldown},
+	}
+	for _, d := range durations {
+		key := prefix + "_" + d.name
+		if value := os.Getenv(key); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed < 0 {
+				return policy, fmt.Errorf("invalid %s: %q", key, value)
+			}
+			*d.value = parsed
+		}
+	}
+
+	counts := []struct {
+		name  string
+		value *int
+		min   int
+	}{
+		{"MAX_RETRIES", &policy.MaxRetries, 0},
+		{"BREAKER_THRESHOLD", &policy.BreakerThreshold, 1},
+	}
+	for _, c := range counts {
+		key := prefix + "_" + c.name
+		if value := os.Getenv(key); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < c.min {
+				return policy, fmt.Errorf("invalid %s: %q", key, value)
+			}
+			*c.value = parsed
+		}
+	}
+
+	return policy, nil
+}
+
+// backoff returns the longest delay before the given retry, counting from 0
+func (p Policy) backoff(retry int) time.Duration {
+	delay := p.RetryBaseDelay
+	for i := 0; i < retry && delay < p.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if p.RetryMaxDelay > 0 && delay > p.RetryMaxDelay {
+		delay = p.RetryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,40 @@
+package resilience
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPolicyFromEnv(t *testing.T) {
+	t.Setenv("TEST_TIMEOUT", "2s")
+	t.Setenv("TEST_MAX_RETRIES", "0")
+	t.Setenv("TEST_BREAKER_THRESHOLD", "3")
+
+	policy, err := PolicyFromEnv("TEST", DefaultRedisPolicy)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if policy.Timeout != 2*time.Second || policy.MaxRetries != 0 || policy.BreakerThreshold != 3 {
+		t.Errorf("Expected the set variables to override the defaults, got %+v", policy)
+	}
+	if policy.BreakerCooldown != DefaultRedisPolicy.BreakerCooldown {
+		t.Errorf("Expected unset variables to keep the defaults, got %+v", policy)
+	}
+
+	t.Setenv("TEST_BREAKER_THRESHOLD", "0")
+	if _, err := PolicyFromEnv("TEST", DefaultRedisPolicy); err == nil || !strings.Contains(err.Error(), "TEST_BREAKER_THRESHOLD") {
+		t.Errorf("Expected an invalid threshold to be rejected, got %v", err)
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{RetryBaseDelay: 10 * time.Millisecond, RetryMaxDelay: 50 * time.Millisecond}
+
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	for retry, delay := range want {
+		if got := policy.backoff(retry); got != delay {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, delay)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/markdown"
+)
+
+var (
+	firstNames = []string{"Ana", "Bruno", "Camila", "Daniel", "Elena", "Felipe", "Gabriela", "Hugo", "Isabel", "João",
+		"Karina", "Lucas", "Marina", "Nicolas", "Olivia", "Pedro", "Rafaela", "Samuel", "Tatiana", "Vinicius"}
+	lastNames = []string{"Almeida", "Barbosa", "Carvalho", "Dias", "Ferreira", "Gomes", "Lima", "Martins", "Nunes",
+		"Oliveira", "Pereira", "Ribeiro", "Santos", "Souza", "Teixeira"}
+
+	adjectives = []string{"Classic", "Compact", "Deluxe", "Ergonomic", "Handmade", "Lightweight", "Modern", "Portable",
+		"Premium", "Rustic", "Sleek", "Vintage"}
+	colors    = []string{"black", "blue", "green", "grey", "red", "white", "yellow"}
+	materials = []string{"bamboo", "ceramic", "cotton", "glass", "leather", "oak", "steel", "wool"}
+
+	// categories maps product categories to the nouns of their products
+	categories = map[string][]string{
+		"furniture": {"Chair", "Desk", "Shelf", "Stool", "Table"},
+		"kitchen":   {"Bowl", "Kettle", "Mug", "Pan", "Teapot"},
+		"lighting":  {"Desk Lamp", "Floor Lamp", "Lantern", "Pendant Light"},
+		"office":    {"Notebook", "Organizer", "Pen Holder", "Planner"},
+		"outdoor":   {"Backpack", "Bench", "Hammock", "Planter"},
+	}
+	categoryNames = []string{"furniture", "kitchen", "lighting", "office", "outdoor"}
+)
+
+// Generator generates realistic users and products. Generators with the same seed
+// generate the same data apart from IDs.
+type Generator struct {
+	rand *rand.Rand
+	now  time.Time
+}
+
+// NewGenerator creates a generator seeded with seed, dating products up to a year
+// before now
+func NewGenerator(seed int64, now time.Time) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed)), now: now}
+}
+
+// User generates the nth user; tag keeps the emails of different runs apart
+func (g *Generator) User(n int, tag string) domain.User {
+	first := pick(g.rand, firstNames)
+	last := pick(g.rand, lastNames)
+	createdAt := g.pastTime()
+
+	return domain.User{
+		ID:        uuid.New(),
+		Email:     fmt.Sprintf("%s.%s.%s-%d@example.com", asciiLower(first), asciiLower(last), tag, n),
+		Name:      first + " " + last,
+		Role:      domain.RoleUser,
+		Plan:      domain.PlanFree,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+// Product generates a product of the user
+func (g *Generator) Product(userID uuid.UUID) domain.Product {
+	category := pick(g.rand, categoryNames)
+	noun := pick(g.rand, categories[category])
+	color := pick(g.rand, colors)
+	material := pick(g.rand, materials)
+
+	description := fmt.Sprintf("A %s %s %s made of %s.\n\n- Color: %s\n- Material: %s",
+		strings.ToLower(pick(g.rand, adjectives)), color, strings.ToLower(noun), material, color, material)
+	createdAt := g.pastTime()
+
+	return domain.Product{
+		ID:              uuid.New(),
+		Name:            pick(g.rand, adjectives) + " " + noun,
+		Description:     description,
+		DescriptionHTML: markdown.Render(description),
+		Price:           g.price(),
+		Stock:           g.stock(),
+		Attributes: domain.Attributes{
+			"category": category,
+			"color":    color,
+			"material": material,
+		},
+		Public:    g.rand.Float64() < 0.3,
+		Status:    domain.ProductStatusPublished,
+		UserID:    userID,
+		Version:   1,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+// price returns a log-normally distributed price, mostly between 5 and 250
+func (g *Generator) price() float64 {
+	price := math.Exp(g.rand.NormFloat64()*0.9 + 3.7)
+	return math.Max(1, math.Round(price*100)/100)
+}
+
+// stock returns a stock level, out of stock for about one in ten products
+func (g *Generator) stock() int {
+	if g.rand.Float64() < 0.1 {
+		return 0
+	}
+	return 1 + g.rand.Intn(200)
+}
+
+// pastTime returns a random time in the year before now
+func (g *Generator) pastTime() time.Time {
+	return g.now.Add(-time.Duration(g.rand.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+// pick returns a random element of values
+func pick(r *rand.Rand, values []string) string {
+	return values[r.Intn(len(values))]
+}
+
+// asciiLower lowercases name for use in an email address, dropping accents
+func asciiLower(name string) string {
+	return strings.NewReplacer("ã", "a", "á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,61 @@
+package seed
+
+import (
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerator_Reproducible(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	first, second := NewGenerator(42, now), NewGenerator(42, now)
+
+	for i := 0; i < 20; i++ {
+		a, b := first.Product(userID), second.Product(userID)
+		if a.Name != b.Name || a.Price != b.Price || a.Stock != b.Stock || !a.CreatedAt.Equal(b.CreatedAt) {
+			t.Fatalf("Expected the same products from the same seed, got %+v and %+v", a, b)
+		}
+	}
+}
+
+func TestGenerator_Product(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	generator := NewGenerator(1, now)
+	userID := uuid.New()
+
+	for i := 0; i < 500; i++ {
+		product := generator.Product(userID)
+		if product.Name == "" || product.Price <= 0 || product.Stock < 0 {
+			t.Fatalf("Unexpected product %+v", product)
+		}
+		if product.Attributes["category"] == nil || product.DescriptionHTML == "" {
+			t.Fatalf("Expected attributes and a rendered description, got %+v", product)
+		}
+		if product.CreatedAt.After(now) || product.CreatedAt.Before(now.AddDate(-1, 0, 0)) {
+			t.Fatalf("Expected a creation time in the past year, got %s", product.CreatedAt)
+		}
+		if product.UserID != userID {
+			t.Fatalf("Expected the product of user %s, got %s", userID, product.UserID)
+		}
+	}
+}
+
+func TestGenerator_UserEmails(t *testing.T) {
+	generator := NewGenerator(1, time.Now())
+	emails := map[string]bool{}
+	for i := 1; i <= 100; i++ {
+		user := generator.User(i, "abc123")
+		if emails[user.Email] {
+			t.Fatalf("Duplicate email %s", user.Email)
+		}
+		for _, r := range user.Email {
+			if r > unicode.MaxASCII {
+				t.Fatalf("Expected an ASCII email, got %s", user.Email)
+			}
+		}
+		emails[user.Email] = true
+	}
+}
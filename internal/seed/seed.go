@@ -0,0 +1,132 @@
+// Package seed fills the database with generated users and products, so that load
+// tests and demos have data to work with.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"products/internal/domain"
+)
+
+// Options configures Run
+type Options struct {
+	// Users is the number of users created
+	Users int
+
+	// ProductsPerUser is the number of products created for each user
+	ProductsPerUser int
+
+	// BatchSize is the number of rows inserted per statement
+	BatchSize int
+
+	// Workers is the number of users whose products are inserted concurrently
+	Workers int
+
+	// Seed makes the generated data reproducible
+	Seed int64
+
+	// Password is the password of every user created
+	Password string
+}
+
+// Result reports what Run created
+type Result struct {
+	Users    int
+	Products int64
+}
+
+// Run creates the users, then their products using Workers concurrent workers.
+// Emails carry a tag unique to the run, so running again adds more users.
+func Run(ctx context.Context, db *gorm.DB, options Options) (Result, error) {
+	if options.Users < 1 || options.ProductsPerUser < 0 || options.BatchSize < 1 || options.Workers < 1 {
+		return Result{}, fmt.Errorf("invalid seed options %+v", options)
+	}
+	if options.Password == "" {
+		return Result{}, errors.New("a password is required")
+	}
+	db = db.WithContext(ctx)
+
+	// users share the password, so it is hashed once
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(options.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	tag := uuid.NewString()[:6]
+	generator := NewGenerator(options.Seed, now)
+	users := make([]domain.User, options.Users)
+	for i := range users {
+		users[i] = generator.User(i+1, tag)
+		users[i].Password = string(hashedPassword)
+	}
+	if err := db.CreateInBatches(users, options.BatchSize).Error; err != nil {
+		return Result{}, fmt.Errorf("failed to create users: %w", err)
+	}
+	log.Printf("Created %d users", len(users))
+
+	result := Result{Users: len(users)}
+	var products atomic.Int64
+	indexes := make(chan int)
+	errs := make(chan error, options.Workers)
+	var workers sync.WaitGroup
+
+	for w := 0; w < options.Workers; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range indexes {
+				// each user's products come from their own generator, so the data
+				// does not depend on the order the workers run in
+				generator := NewGenerator(options.Seed+int64(i)+1, now)
+				batch := make([]domain.Product, options.ProductsPerUser)
+				for j := range batch {
+					batch[j] = generator.Product(users[i].ID)
+				}
+				if len(batch) == 0 {
+					continue
+				}
+				if err := db.Omit("User").CreateInBatches(batch, options.BatchSize).Error; err != nil {
+					errs <- fmt.Errorf("failed to create products of user %s: %w", users[i].ID, err)
+					return
+				}
+				if total := products.Add(int64(len(batch))); total%10000 < int64(len(batch)) {
+					log.Printf("Created %d products", total)
+				}
+			}
+		}()
+	}
+
+	var runErr error
+send:
+	for i := range users {
+		select {
+		case indexes <- i:
+		case runErr = <-errs:
+			break send
+		case <-ctx.Done():
+			runErr = ctx.Err()
+			break send
+		}
+	}
+	close(indexes)
+	workers.Wait()
+
+	if runErr == nil {
+		select {
+		case runErr = <-errs:
+		default:
+		}
+	}
+	result.Products = products.Load()
+	return result, runErr
+}
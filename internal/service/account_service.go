@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// DefaultDeletionGracePeriod is how long a deletion request can be cancelled
+const DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+// AccountService handles account-level data rights: deletion and export
+type AccountService struct {
+	userRepo       domain.UserRepository
+	productRepo    domain.ProductRepository
+	productService *ProductService
+	sessionService *SessionService
+	avatarService  *AvatarService
+	gracePeriod    time.Duration
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(userRepo domain.UserRepository, productRepo domain.ProductRepository, productService *ProductService, sessionService *SessionService, avatarService *AvatarService, gracePeriod time.Duration) *AccountService {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultDeletionGracePeriod
+	}
+
+	return &AccountService{
+		userRepo:       userRepo,
+		productRepo:    productRepo,
+		productService: productService,
+		sessionService: sessionService,
+		avatarService:  avatarService,
+		gracePeriod:    gracePeriod,
+	}
+}
+
+// ScheduleDeletion marks the account for deletion after the grace period
+func (s *AccountService) ScheduleDeletion(ctx context.Context, userID uuid.UUID) (*domain.AccountDeletionResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DeletionScheduledAt != nil {
+		return &domain.AccountDeletionResponse{
+			Message:      "Account deletion already scheduled",
+			ScheduledFor: *user.DeletionScheduledAt,
+		}, nil
+	}
+
+	scheduledFor := time.Now().Add(s.gracePeriod)
+	if err := s.userRepo.SetDeletionSchedule(ctx, userID, &scheduledFor); err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	return &domain.AccountDeletionResponse{
+		Message:      "Account deletion scheduled",
+		ScheduledFor: scheduledFor,
+	}, nil
+}
+
+// CancelDeletion cancels a pending account deletion
+func (s *AccountService) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.DeletionScheduledAt == nil {
+		return errors.New("no account deletion is scheduled")
+	}
+
+	return s.userRepo.SetDeletionSchedule(ctx, userID, nil)
+}
+
+// ExportData collects all personal data stored for a user
+func (s *AccountService) ExportData(ctx context.Context, userID uuid.UUID) (*domain.AccountExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.productRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load products: %w", err)
+	}
+
+	sessions, err := s.sessionService.GetUserSessions(ctx, userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	sessionInfos := make([]domain.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		sessionInfos = append(sessionInfos, session.Info())
+	}
+
+	if products == nil {
+		products = []domain.Product{}
+	}
+
+	export := &domain.AccountExport{
+		ExportedAt: time.Now().UTC(),
+		User:       domain.NewUserResponse(user),
+		Products:   products,
+		Sessions:   sessionInfos,
+	}
+	if err := s.userRepo.LoadAccountData(ctx, userID, export); err != nil {
+		return nil, fmt.Errorf("failed to load account data: %w", err)
+	}
+
+	return export, nil
+}
+
+// PurgeDueAccounts permanently deletes accounts whose grace period has elapsed
+func (s *AccountService) PurgeDueAccounts(ctx context.Context) (int, error) {
+	users, err := s.userRepo.GetScheduledForDeletion(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load accounts scheduled for deletion: %w", err)
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err := s.purgeAccount(ctx, user.ID); err != nil {
+			log.Printf("Failed to purge account %s: %v", user.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// StartDeletionWorker periodically purges due accounts until ctx is cancelled;
+// a purge in progress runs to completion
+func (s *AccountService) StartDeletionWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeDueAccounts(context.WithoutCancel(ctx))
+			if err != nil {
+				log.Printf("Account deletion worker: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Account deletion worker: purged %d accounts", purged)
+			}
+		}
+	}
+}
+
+// purgeAccount removes a user's sessions, avatar, caches, products and account
+func (s *AccountService) purgeAccount(ctx context.Context, userID uuid.UUID) error {
+	if err := s.sessionService.DeleteUserSessions(ctx, userID.String()); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+
+	// Before the account, so a failure leaves it due and the purge is retried
+	if err := s.avatarService.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.DeleteAccount(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete account data: %w", err)
+	}
+
+	s.productService.invalidateUserCache(ctx, userID)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// AttributeService manages user-defined product attribute schemas
+type AttributeService struct {
+	attributeRepo *repository.AttributeDefinitionRepository
+}
+
+// NewAttributeService creates a new attribute service
+func NewAttributeService(attributeRepo *repository.AttributeDefinitionRepository) *AttributeService {
+	return &AttributeService{
+		attributeRepo: attributeRepo,
+	}
+}
+
+// List returns the user's attribute definitions
+func (s *AttributeService) List(ctx context.Context, userID uuid.UUID) ([]domain.AttributeDefinition, error) {
+	definitions, err := s.attributeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if definitions == nil {
+		definitions = []domain.AttributeDefinition{}
+	}
+	return definitions, nil
+}
+
+// Define creates or replaces the definition of an attribute
+func (s *AttributeService) Define(ctx context.Context, userID uuid.UUID, key string, req domain.AttributeDefinitionRequest) (*domain.AttributeDefinition, error) {
+	now := time.Now()
+	definition := domain.AttributeDefinition{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Key:           key,
+		Type:          req.Type,
+		Required:      req.Required,
+		AllowedValues: req.AllowedValues,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := domain.ValidateAttributeDefinition(definition); err != nil {
+		return nil, err
+	}
+
+	if err := s.attributeRepo.Upsert(ctx, definition); err != nil {
+		return nil, err
+	}
+
+	return s.attributeRepo.GetByKey(ctx, userID, key)
+}
+
+// Delete removes an attribute definition; existing product values are kept
+func (s *AttributeService) Delete(ctx context.Context, userID uuid.UUID, key string) error {
+	return s.attributeRepo.Delete(ctx, userID, key)
+}
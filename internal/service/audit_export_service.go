@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// MaxAuditExportRange bounds the date range of a single audit export
+const MaxAuditExportRange = 366 * 24 * time.Hour
+
+// Audit export errors
+var (
+	ErrAuditExportNotFound = domain.NewError(domain.CodeNotFound, "audit export not found")
+	ErrAuditExportNotReady = domain.NewError(domain.CodeConflict, "audit export is not ready")
+)
+
+// auditExportColumns are the fields written for each exported event
+var auditExportColumns = []string{
+	"id", "created_at", "category", "method", "route", "path", "status",
+	"latency_ms", "client_ip", "request_body", "response_body", "sample_rate",
+}
+
+// AuditExportService generates audit trail exports asynchronously for compliance requests
+type AuditExportService struct {
+	exportRepo *repository.AuditExportRepository
+	auditRepo  *repository.AuditRepository
+	userRepo   domain.UserRepository
+	wake       chan struct{}
+}
+
+// NewAuditExportService creates a new audit export service
+func NewAuditExportService(exportRepo *repository.AuditExportRepository, auditRepo *repository.AuditRepository, userRepo domain.UserRepository) *AuditExportService {
+	return &AuditExportService{
+		exportRepo: exportRepo,
+		auditRepo:  auditRepo,
+		userRepo:   userRepo,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Request queues an export of a user's audit trail on behalf of an admin
+func (s *AuditExportService) Request(ctx context.Context, adminID uuid.UUID, req *domain.AuditExportRequest) (*domain.AuditExport, error) {
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, errors.New("invalid user_id")
+	}
+
+	if !req.From.Before(req.To) {
+		return nil, errors.New("from must be before to")
+	}
+	if req.To.Sub(req.From) > MaxAuditExportRange {
+		return nil, fmt.Errorf("date range must not exceed %d days", int(MaxAuditExportRange.Hours()/24))
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	export := &domain.AuditExport{
+		ID:           uuid.New(),
+		RequestedBy:  adminID,
+		UserID:       userID,
+		From:         req.From,
+		To:           req.To,
+		Format:       req.Format,
+		IncludeReads: req.IncludeReads,
+		Status:       domain.AuditExportPending,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("failed to queue audit export: %w", err)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return export, nil
+}
+
+// Get returns an export job; only the admin who requested it may see it
+func (s *AuditExportService) Get(ctx context.Context, adminID, id uuid.UUID) (*domain.AuditExport, error) {
+	export, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil || export.RequestedBy != adminID {
+		return nil, ErrAuditExportNotFound
+	}
+	return export, nil
+}
+
+// Download returns a completed export including its content
+func (s *AuditExportService) Download(ctx context.Context, adminID, id uuid.UUID) (*domain.AuditExport, error) {
+	export, err := s.exportRepo.GetContent(ctx, id)
+	if err != nil || export.RequestedBy != adminID {
+		return nil, ErrAuditExportNotFound
+	}
+	if export.Status != domain.AuditExportCompleted {
+		return nil, ErrAuditExportNotReady
+	}
+	return export, nil
+}
+
+// StartWorker generates queued exports when woken by a request or on every interval
+func (s *AuditExportService) StartWorker(ctx context.Context, interval time.Duration) {
+	if err := s.exportRepo.ResetRunning(ctx); err != nil {
+		log.Printf("Audit export worker: failed to requeue interrupted exports: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.processPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// processPending generates exports until the queue is empty or ctx is cancelled.
+// A claimed export is generated and stored even if ctx is cancelled meanwhile.
+func (s *AuditExportService) processPending(ctx context.Context) {
+	jobCtx := context.WithoutCancel(ctx)
+	for ctx.Err() == nil {
+		export, err := s.exportRepo.ClaimPending(jobCtx)
+		if err != nil {
+			log.Printf("Audit export worker: %v", err)
+			return
+		}
+		if export == nil {
+			return
+		}
+
+		content, rowCount, err := s.generate(jobCtx, export)
+		if err != nil {
+			log.Printf("Audit export worker: export %s failed: %v", export.ID, err)
+			if err := s.exportRepo.Fail(jobCtx, export.ID, err.Error()); err != nil {
+				log.Printf("Audit export worker: failed to record failure of %s: %v", export.ID, err)
+			}
+			continue
+		}
+
+		if err := s.exportRepo.Complete(jobCtx, export.ID, content, rowCount); err != nil {
+			log.Printf("Audit export worker: failed to store export %s: %v", export.ID, err)
+		}
+	}
+}
+
+// generate renders the user's audit trail in the requested format
+func (s *AuditExportService) generate(ctx context.Context, export *domain.AuditExport) ([]byte, int64, error) {
+	var buf bytes.Buffer
+	var rowCount int64
+
+	var csvWriter *csv.Writer
+	var jsonEncoder *json.Encoder
+
+	switch export.Format {
+	case domain.AuditExportFormatCSV:
+		csvWriter = csv.NewWriter(&buf)
+		if err := csvWriter.Write(auditExportColumns); err != nil {
+			return nil, 0, err
+		}
+	case domain.AuditExportFormatJSON:
+		header, err := json.Marshal(map[string]interface{}{
+			"user_id":      export.UserID,
+			"requested_by": export.RequestedBy,
+			"from":         export.From,
+			"to":           export.To,
+			"generated_at": time.Now(),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		buf.Write(header[:len(header)-1])
+		buf.WriteString(`,"events":[`)
+		jsonEncoder = json.NewEncoder(&buf)
+	default:
+		return nil, 0, fmt.Errorf("unsupported format %q", export.Format)
+	}
+
+	err := s.auditRepo.Each(ctx, export.UserID, export.From, export.To, func(entry domain.AuditLog) error {
+		category := domain.AuditCategory(entry)
+		if category == domain.AuditCategoryRead && !export.IncludeReads {
+			return nil
+		}
+
+		if csvWriter != nil {
+			rowCount++
+			return csvWriter.Write([]string{
+				entry.ID.String(),
+				entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+				category,
+				entry.Method,
+				entry.Route,
+				entry.Path,
+				strconv.Itoa(entry.Status),
+				strconv.FormatInt(entry.LatencyMs, 10),
+				entry.ClientIP,
+				entry.RequestBody,
+				entry.ResponseBody,
+				strconv.FormatFloat(entry.SampleRate, 'g', -1, 64),
+			})
+		}
+
+		if rowCount > 0 {
+			buf.WriteByte(',')
+		}
+		rowCount++
+		return jsonEncoder.Encode(map[string]interface{}{
+			"id":            entry.ID,
+			"created_at":    entry.CreatedAt,
+			"category":      category,
+			"method":        entry.Method,
+			"route":         entry.Route,
+			"path":          entry.Path,
+			"status":        entry.Status,
+			"latency_ms":    entry.LatencyMs,
+			"client_ip":     entry.ClientIP,
+			"request_body":  entry.RequestBody,
+			"response_body": entry.ResponseBody,
+			"sample_rate":   entry.SampleRate,
+		})
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read audit logs: %w", err)
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		buf.WriteString("]}")
+	}
+
+	return buf.Bytes(), rowCount, nil
+}
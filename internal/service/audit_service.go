@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/redact"
+	"products/internal/repository"
+)
+
+// AuditConfig holds audit logging configuration
+type AuditConfig struct {
+	Retention    time.Duration
+	MaxBodyBytes int
+	BufferSize   int
+	Sampling     domain.AuditSampling
+}
+
+// DefaultAuditConfig returns the default audit configuration
+func DefaultAuditConfig() AuditConfig {
+	return AuditConfig{
+		Retention:    90 * 24 * time.Hour,
+		MaxBodyBytes: 2048,
+		BufferSize:   1024,
+	}
+}
+
+// AuditService records API calls asynchronously and enforces retention
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+	config    AuditConfig
+	entries   chan domain.AuditLog
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepo *repository.AuditRepository, config AuditConfig) *AuditService {
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultAuditConfig().BufferSize
+	}
+
+	return &AuditService{
+		auditRepo: auditRepo,
+		config:    config,
+		entries:   make(chan domain.AuditLog, config.BufferSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// MaxBodyBytes returns the maximum number of body bytes recorded per call
+func (s *AuditService) MaxBodyBytes() int {
+	return s.config.MaxBodyBytes
+}
+
+// Record queues an audit log without blocking the request; entries are dropped when the buffer
+// is full or left out by the sampling rate of their category
+func (s *AuditService) Record(entry domain.AuditLog) {
+	entry.SampleRate = s.config.Sampling.Rate(domain.AuditCategory(entry), entry.UserID)
+	if entry.SampleRate < 1 && rand.Float64() >= entry.SampleRate {
+		return
+	}
+
+	entry.RequestBody = s.prepareBody(entry.RequestBody)
+	entry.ResponseBody = s.prepareBody(entry.ResponseBody)
+
+	select {
+	case s.entries <- entry:
+	default:
+		log.Printf("Audit buffer full, dropping entry for %s %s", entry.Method, entry.Path)
+	}
+}
+
+// Start persists queued entries in batches until Close is called
+func (s *AuditService) Start() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	defer close(s.done)
+
+	batch := make([]domain.AuditLog, 0, 100)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := s.auditRepo.CreateBatch(ctx, batch); err != nil {
+			log.Printf("Failed to persist %d audit logs: %v", len(batch), err)
+		}
+		cancel()
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry, ok := <-s.entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) == cap(batch) {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Close stops accepting entries and waits for queued entries to be persisted
+func (s *AuditService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.entries)
+	})
+	<-s.done
+}
+
+// Query retrieves recorded audit logs
+func (s *AuditService) Query(ctx context.Context, filter domain.AuditLogFilter, pagination domain.Pagination) (*domain.AuditLogListResponse, error) {
+	return s.auditRepo.Query(ctx, filter, pagination)
+}
+
+// StartRetentionWorker periodically deletes audit logs older than the retention
+// period until ctx is cancelled; a deletion in progress runs to completion
+func (s *AuditService) StartRetentionWorker(ctx context.Context, interval time.Duration) {
+	if s.config.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.auditRepo.DeleteOlderThan(context.WithoutCancel(ctx), time.Now().Add(-s.config.Retention))
+			if err != nil {
+				log.Printf("Audit retention worker: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Audit retention worker: deleted %d audit logs", deleted)
+			}
+		}
+	}
+}
+
+// prepareBody redacts sensitive JSON fields and truncates the body
+func (s *AuditService) prepareBody(body string) string {
+	body = redact.JSON(body, false)
+
+	if s.config.MaxBodyBytes > 0 && len(body) > s.config.MaxBodyBytes {
+		body = body[:s.config.MaxBodyBytes] + "...(truncated)"
+	}
+
+	return body
+}
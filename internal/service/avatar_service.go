@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/storage"
+)
+
+const (
+	// AvatarSize is the width and height, in pixels, avatars are stored at
+	AvatarSize = 256
+	// MaxAvatarBytes is the largest avatar upload accepted
+	MaxAvatarBytes = 5 << 20
+	// MaxAvatarDimension bounds the width and height of an upload, so a small file
+	// claiming huge dimensions is rejected before it is decoded
+	MaxAvatarDimension = 4096
+)
+
+// Avatar errors
+var (
+	ErrAvatarsDisabled = domain.NewError(domain.CodeUnavailable, "avatar storage is not configured")
+	ErrInvalidAvatar   = domain.NewError(domain.CodeValidationFailed, "avatar must be a JPEG, PNG or GIF image")
+	ErrAvatarTooLarge  = domain.NewError(domain.CodePayloadTooLarge, fmt.Sprintf("avatar must be at most %d bytes", MaxAvatarBytes))
+	ErrNoAvatar        = domain.NotFoundError(domain.CodeNotFound, "user has no avatar")
+)
+
+// AvatarService stores user avatars in object storage, cropped to a square and
+// scaled down to AvatarSize
+type AvatarService struct {
+	userRepo domain.UserRepository
+	store    storage.ObjectStore
+}
+
+// NewAvatarService creates a new avatar service. A nil store disables avatars.
+func NewAvatarService(userRepo domain.UserRepository, store storage.ObjectStore) *AvatarService {
+	return &AvatarService{
+		userRepo: userRepo,
+		store:    store,
+	}
+}
+
+// avatarKey returns the object key of a user's avatar; uploads replace it
+func avatarKey(userID uuid.UUID) string {
+	return fmt.Sprintf("avatars/%s.png", userID)
+}
+
+// Upload validates and resizes an uploaded image, stores it as the user's avatar
+// and returns the updated user
+func (s *AvatarService) Upload(ctx context.Context, userID uuid.UUID, data io.Reader) (*domain.User, error) {
+	if s.store == nil {
+		return nil, ErrAvatarsDisabled
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(data, MaxAvatarBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if len(raw) > MaxAvatarBytes {
+		return nil, ErrAvatarTooLarge
+	}
+
+	encoded, err := resizeAvatar(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Put(ctx, avatarKey(userID), encoded, "image/png"); err != nil {
+		return nil, fmt.Errorf("failed to upload avatar: %w", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+	if err := s.userRepo.SetAvatar(ctx, userID, hex.EncodeToString(sum[:8])); err != nil {
+		return nil, fmt.Errorf("failed to save avatar: %w", err)
+	}
+
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+// Open returns the stored avatar of a user as a PNG image
+func (s *AvatarService) Open(ctx context.Context, userID uuid.UUID) (io.ReadCloser, error) {
+	if s.store == nil {
+		return nil, ErrAvatarsDisabled
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.Avatar == "" {
+		return nil, ErrNoAvatar
+	}
+
+	reader, err := s.store.Get(ctx, avatarKey(userID))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, ErrNoAvatar
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download avatar: %w", err)
+	}
+
+	return reader, nil
+}
+
+// Delete removes the stored avatar of a user, if any; it does nothing when avatars
+// are disabled
+func (s *AvatarService) Delete(ctx context.Context, userID uuid.UUID) error {
+	if s.store == nil {
+		return nil
+	}
+
+	if err := s.store.Delete(ctx, avatarKey(userID)); err != nil {
+		return fmt.Errorf("failed to delete avatar: %w", err)
+	}
+	return nil
+}
+
+// resizeAvatar decodes an image, crops it to its centered square, scales it down
+// to AvatarSize if larger and encodes the result as PNG
+func resizeAvatar(raw []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrInvalidAvatar
+	}
+	if config.Width > MaxAvatarDimension || config.Height > MaxAvatarDimension {
+		return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("avatar must be at most %dx%d pixels", MaxAvatarDimension, MaxAvatarDimension))
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, ErrInvalidAvatar
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, cropScaled(src, AvatarSize)); err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// cropScaled crops an image to its centered square and shrinks it to size in one
+// pass, averaging the source pixels each target pixel covers, so the full-size
+// square is never allocated; smaller squares keep their size
+func cropScaled(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	size = min(side, size)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(origin.X+sx, origin.Y+sy).RGBA()
+					r += pr >> 8
+					g += pg >> 8
+					b += pb >> 8
+					a += pa >> 8
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository/memory"
+	"products/internal/storage"
+)
+
+// objectStore keeps objects in memory
+type objectStore map[string][]byte
+
+func (s objectStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	s[key] = body
+	return nil
+}
+
+func (s objectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, ok := s[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+func (s objectStore) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s objectStore) List(ctx context.Context, prefix string) ([]storage.ObjectInfo, error) {
+	return nil, nil
+}
+
+func TestAvatarService_Upload(t *testing.T) {
+	ctx := context.Background()
+	userRepo := memory.NewUserRepository(memory.NewStore())
+	userID := uuid.New()
+	if err := userRepo.Create(ctx, &domain.User{ID: userID, Email: "jane@example.com"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	svc := NewAvatarService(userRepo, objectStore{})
+
+	src := image.NewRGBA(image.Rect(0, 0, 1200, 800))
+	for i := range src.Pix {
+		src.Pix[i] = 200
+	}
+	var upload bytes.Buffer
+	if err := jpeg.Encode(&upload, src, nil); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+
+	user, err := svc.Upload(ctx, userID, &upload)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	url := domain.NewUserResponse(user).AvatarURL
+	if url == nil || !strings.HasPrefix(*url, "/api/v1/public/avatars/"+userID.String()+"?v=") {
+		t.Errorf("Expected a versioned avatar URL, got %v", url)
+	}
+
+	reader, err := svc.Open(ctx, userID)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer reader.Close()
+	stored, err := png.Decode(reader)
+	if err != nil {
+		t.Fatalf("Stored avatar is not a PNG: %v", err)
+	}
+	if bounds := stored.Bounds(); bounds.Dx() != AvatarSize || bounds.Dy() != AvatarSize {
+		t.Errorf("Expected a %dx%d avatar, got %dx%d", AvatarSize, AvatarSize, bounds.Dx(), bounds.Dy())
+	}
+	if r, _, _, _ := stored.At(AvatarSize/2, AvatarSize/2).RGBA(); r>>8 < 190 || r>>8 > 210 {
+		t.Errorf("Expected the source color to be kept, got red %d", r>>8)
+	}
+
+	if err := svc.Delete(ctx, userID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := svc.Open(ctx, userID); !errors.Is(err, ErrNoAvatar) {
+		t.Errorf("Expected ErrNoAvatar after Delete, got %v", err)
+	}
+}
+
+func TestAvatarService_KeepsSmallImages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	src.Set(0, 0, color.White)
+	var upload bytes.Buffer
+	if err := png.Encode(&upload, src); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+
+	encoded, err := resizeAvatar(upload.Bytes())
+	if err != nil {
+		t.Fatalf("resizeAvatar failed: %v", err)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("Result is not a PNG: %v", err)
+	}
+	if config.Width != 48 || config.Height != 48 {
+		t.Errorf("Expected the image cropped to 48x48, got %dx%d", config.Width, config.Height)
+	}
+}
+
+func TestAvatarService_Rejects(t *testing.T) {
+	ctx := context.Background()
+	svc := NewAvatarService(memory.NewUserRepository(memory.NewStore()), objectStore{})
+
+	if _, err := svc.Upload(ctx, uuid.New(), strings.NewReader("not an image")); !errors.Is(err, ErrInvalidAvatar) {
+		t.Errorf("Expected ErrInvalidAvatar, got %v", err)
+	}
+	if _, err := svc.Upload(ctx, uuid.New(), bytes.NewReader(make([]byte, MaxAvatarBytes+1))); !errors.Is(err, ErrAvatarTooLarge) {
+		t.Errorf("Expected ErrAvatarTooLarge, got %v", err)
+	}
+	var wide bytes.Buffer
+	if err := png.Encode(&wide, image.NewGray(image.Rect(0, 0, MaxAvatarDimension+1, 1))); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+	if _, err := svc.Upload(ctx, uuid.New(), &wide); domain.ErrorCode(err) != domain.CodeValidationFailed {
+		t.Errorf("Expected an image wider than %d pixels to be rejected, got %v", MaxAvatarDimension, err)
+	}
+	if _, err := svc.Open(ctx, uuid.New()); !errors.Is(err, ErrNoAvatar) {
+		t.Errorf("Expected ErrNoAvatar, got %v", err)
+	}
+
+	disabled := NewAvatarService(memory.NewUserRepository(memory.NewStore()), nil)
+	if _, err := disabled.Upload(ctx, uuid.New(), strings.NewReader("")); !errors.Is(err, ErrAvatarsDisabled) {
+		t.Errorf("Expected ErrAvatarsDisabled, got %v", err)
+	}
+}
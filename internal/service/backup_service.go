@@ -0,0 +1,306 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+	"products/internal/storage"
+)
+
+// maxBackupLineSize bounds a single NDJSON line when reading a backup
+const maxBackupLineSize = 1024 * 1024
+
+// BackupService handles backup and restore of user data to object storage
+type BackupService struct {
+	userRepo       domain.UserRepository
+	productRepo    domain.ProductRepository
+	attributeRepo  *repository.AttributeDefinitionRepository
+	productService *ProductService
+	store          storage.ObjectStore
+}
+
+// NewBackupService creates a new backup service. A nil store disables backups.
+func NewBackupService(userRepo domain.UserRepository, productRepo domain.ProductRepository, attributeRepo *repository.AttributeDefinitionRepository, productService *ProductService, store storage.ObjectStore) *BackupService {
+	return &BackupService{
+		userRepo:       userRepo,
+		productRepo:    productRepo,
+		attributeRepo:  attributeRepo,
+		productService: productService,
+		store:          store,
+	}
+}
+
+// CreateBackup writes a full NDJSON backup of the user's data and returns its location
+func (s *BackupService) CreateBackup(ctx context.Context, userID uuid.UUID) (*domain.BackupInfo, error) {
+	if s.store == nil {
+		return nil, errors.New("backup storage is not configured")
+	}
+
+	data, err := s.Export(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s%s.ndjson", backupPrefix(userID), now.Format("20060102T150405Z"))
+	if err := s.store.Put(ctx, key, data, "application/x-ndjson"); err != nil {
+		return nil, fmt.Errorf("failed to upload backup: %w", err)
+	}
+
+	return &domain.BackupInfo{
+		Key:       key,
+		Size:      int64(len(data)),
+		CreatedAt: now,
+	}, nil
+}
+
+// Export serializes the user's metadata and products as NDJSON
+func (s *BackupService) Export(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	products, err := s.productRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load products: %w", err)
+	}
+
+	definitions, err := s.attributeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+
+	if err := encoder.Encode(domain.BackupRecord{
+		Type: domain.BackupRecordMetadata,
+		Metadata: &domain.BackupMetadata{
+			FormatVersion: domain.BackupFormatVersion,
+			UserID:        user.ID,
+			Email:         user.Email,
+			Name:          user.Name,
+			ProductCount:  len(products),
+			CreatedAt:     time.Now().UTC(),
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to encode backup metadata: %w", err)
+	}
+
+	for i := range definitions {
+		if err := encoder.Encode(domain.BackupRecord{
+			Type:                domain.BackupRecordAttributeDefinition,
+			AttributeDefinition: &definitions[i],
+		}); err != nil {
+			return nil, fmt.Errorf("failed to encode attribute definition %s: %w", definitions[i].Key, err)
+		}
+	}
+
+	for i := range products {
+		if err := encoder.Encode(domain.BackupRecord{
+			Type:    domain.BackupRecordProduct,
+			Product: &products[i],
+		}); err != nil {
+			return nil, fmt.Errorf("failed to encode product %s: %w", products[i].ID, err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ListBackups returns the user's backups, newest first
+func (s *BackupService) ListBackups(ctx context.Context, userID uuid.UUID) ([]domain.BackupInfo, error) {
+	if s.store == nil {
+		return nil, errors.New("backup storage is not configured")
+	}
+
+	objects, err := s.store.List(ctx, backupPrefix(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups := make([]domain.BackupInfo, 0, len(objects))
+	for _, object := range objects {
+		backups = append(backups, domain.BackupInfo{
+			Key:       object.Key,
+			Size:      object.Size,
+			CreatedAt: object.LastModified,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// RestoreBackup validates a stored backup and re-imports its products for the user
+func (s *BackupService) RestoreBackup(ctx context.Context, userID uuid.UUID, key string) (*domain.RestoreBackupResponse, error) {
+	if s.store == nil {
+		return nil, errors.New("backup storage is not configured")
+	}
+
+	if !strings.HasPrefix(key, backupPrefix(userID)) {
+		return nil, errors.New("backup does not belong to user")
+	}
+
+	reader, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, errors.New("backup not found")
+		}
+		return nil, fmt.Errorf("failed to download backup: %w", err)
+	}
+	defer reader.Close()
+
+	var data bytes.Buffer
+	if _, err := data.ReadFrom(reader); err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	parsed, err := ParseBackup(data.Bytes(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attributeRepo.Upsert(ctx, parsed.AttributeDefinitions...); err != nil {
+		return nil, fmt.Errorf("failed to restore attribute definitions: %w", err)
+	}
+
+	if err := s.productRepo.UpsertForUser(ctx, userID, parsed.Products); err != nil {
+		return nil, fmt.Errorf("failed to restore products: %w", err)
+	}
+
+	s.productService.invalidateUserCache(ctx, userID)
+
+	return &domain.RestoreBackupResponse{
+		Key:                          key,
+		RestoredProducts:             len(parsed.Products),
+		RestoredAttributeDefinitions: len(parsed.AttributeDefinitions),
+	}, nil
+}
+
+// ParseBackup decodes and validates an NDJSON backup belonging to userID
+func ParseBackup(data []byte, userID uuid.UUID) (*domain.ParsedBackup, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBackupLineSize)
+
+	var metadata *domain.BackupMetadata
+	var products []domain.Product
+	var definitions []domain.AttributeDefinition
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var record domain.BackupRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("invalid backup line %d: %w", line, err)
+		}
+
+		switch record.Type {
+		case domain.BackupRecordMetadata:
+			if metadata != nil || record.Metadata == nil {
+				return nil, fmt.Errorf("invalid backup line %d: unexpected metadata record", line)
+			}
+			if record.Metadata.FormatVersion != domain.BackupFormatVersion {
+				return nil, fmt.Errorf("unsupported backup format version %d", record.Metadata.FormatVersion)
+			}
+			if record.Metadata.UserID != userID {
+				return nil, errors.New("backup does not belong to user")
+			}
+			metadata = record.Metadata
+		case domain.BackupRecordProduct:
+			if metadata == nil {
+				return nil, fmt.Errorf("invalid backup line %d: product before metadata", line)
+			}
+			if err := validateBackupProduct(record.Product); err != nil {
+				return nil, fmt.Errorf("invalid backup line %d: %w", line, err)
+			}
+			product := *record.Product
+			product.User = domain.User{}
+			products = append(products, product)
+		case domain.BackupRecordAttributeDefinition:
+			if metadata == nil {
+				return nil, fmt.Errorf("invalid backup line %d: attribute definition before metadata", line)
+			}
+			if record.AttributeDefinition == nil {
+				return nil, fmt.Errorf("invalid backup line %d: missing attribute definition", line)
+			}
+			definition := *record.AttributeDefinition
+			if err := domain.ValidateAttributeDefinition(definition); err != nil {
+				return nil, fmt.Errorf("invalid backup line %d: %w", line, err)
+			}
+			definition.ID = uuid.New()
+			definition.UserID = userID
+			definitions = append(definitions, definition)
+		default:
+			return nil, fmt.Errorf("invalid backup line %d: unknown record type %q", line, record.Type)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if metadata == nil {
+		return nil, errors.New("backup is missing metadata")
+	}
+
+	if metadata.ProductCount != len(products) {
+		return nil, fmt.Errorf("backup is incomplete: expected %d products, found %d", metadata.ProductCount, len(products))
+	}
+
+	for _, product := range products {
+		if err := domain.ValidateAttributes(product.Attributes, definitions); err != nil {
+			return nil, fmt.Errorf("invalid product %s: %w", product.ID, err)
+		}
+	}
+
+	return &domain.ParsedBackup{
+		Metadata:             *metadata,
+		Products:             products,
+		AttributeDefinitions: definitions,
+	}, nil
+}
+
+// validateBackupProduct checks that a product record can be safely re-imported
+func validateBackupProduct(product *domain.Product) error {
+	if product == nil {
+		return errors.New("missing product")
+	}
+	if product.ID == uuid.Nil {
+		return errors.New("product ID is required")
+	}
+	if strings.TrimSpace(product.Name) == "" {
+		return errors.New("product name is required")
+	}
+	if product.Price <= 0 {
+		return errors.New("product price must be greater than 0")
+	}
+	if product.Stock < 0 {
+		return errors.New("product stock cannot be negative")
+	}
+	return nil
+}
+
+// backupPrefix returns the object key prefix for a user's backups
+func backupPrefix(userID uuid.UUID) string {
+	return fmt.Sprintf("backups/%s/", userID)
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// Billing errors
+var (
+	ErrBillingDisabled   = domain.NewError(domain.CodeUnavailable, "billing is not configured")
+	ErrNoBillingCustomer = domain.NotFoundError(domain.CodeNotFound, "no billing account; subscribe to a plan first")
+	ErrAlreadySubscribed = domain.ConflictError(domain.CodeConflict, "already subscribed; change or cancel the plan in the billing portal")
+)
+
+// BillingConfig holds the Stripe configuration. Prices maps each paid plan to the
+// ID of its Stripe price.
+type BillingConfig struct {
+	SecretKey       string
+	WebhookSecret   string
+	Prices          map[string]string
+	SuccessURL      string
+	CancelURL       string
+	PortalReturnURL string
+}
+
+// Enabled reports whether billing is configured
+func (c BillingConfig) Enabled() bool {
+	return c.SecretKey != "" && c.WebhookSecret != ""
+}
+
+// BillingService takes payments for plans with Stripe Checkout, moves users
+// between plans as Stripe reports subscription changes and stores their invoices
+type BillingService struct {
+	billingRepo  *repository.BillingRepository
+	userRepo     domain.UserRepository
+	planService  *PlanService
+	quotaService *QuotaService
+	stripe       *StripeClient
+	config       BillingConfig
+}
+
+// NewBillingService creates a new billing service; without a configuration every
+// operation fails with ErrBillingDisabled
+func NewBillingService(billingRepo *repository.BillingRepository, userRepo domain.UserRepository, planService *PlanService, quotaService *QuotaService, config BillingConfig) *BillingService {
+	return &BillingService{
+		billingRepo:  billingRepo,
+		userRepo:     userRepo,
+		planService:  planService,
+		quotaService: quotaService,
+		stripe:       NewStripeClient(config.SecretKey),
+		config:       config,
+	}
+}
+
+// Checkout creates a Stripe Checkout Session subscribing the user to a paid plan
+// and returns the URL of its payment page
+func (s *BillingService) Checkout(ctx context.Context, userID uuid.UUID, plan string) (*domain.BillingLinkResponse, error) {
+	if !s.config.Enabled() {
+		return nil, ErrBillingDisabled
+	}
+	price, ok := s.config.Prices[plan]
+	if !ok {
+		return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("the %s plan cannot be bought", plan))
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.billingRepo.GetCustomerByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"mode":                                 {"subscription"},
+		"line_items[0][price]":                 {price},
+		"line_items[0][quantity]":              {"1"},
+		"client_reference_id":                  {userID.String()},
+		"metadata[user_id]":                    {userID.String()},
+		"subscription_data[metadata][user_id]": {userID.String()},
+		"success_url":                          {s.config.SuccessURL},
+		"cancel_url":                           {s.config.CancelURL},
+	}
+	if customer != nil {
+		if domain.SubscriptionActive(customer.SubscriptionStatus) {
+			return nil, ErrAlreadySubscribed
+		}
+		params.Set("customer", customer.StripeCustomerID)
+	} else {
+		params.Set("customer_email", user.Email)
+	}
+
+	checkoutURL, err := s.stripe.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BillingLinkResponse{URL: checkoutURL}, nil
+}
+
+// PortalLink creates a Stripe customer portal session in which the user manages
+// their subscription and payment methods, and returns its URL
+func (s *BillingService) PortalLink(ctx context.Context, userID uuid.UUID) (*domain.BillingLinkResponse, error) {
+	if !s.config.Enabled() {
+		return nil, ErrBillingDisabled
+	}
+
+	customer, err := s.billingRepo.GetCustomerByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrNoBillingCustomer
+	}
+
+	portalURL, err := s.stripe.CreatePortalSession(ctx, customer.StripeCustomerID, s.config.PortalReturnURL)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BillingLinkResponse{URL: portalURL}, nil
+}
+
+// Invoices returns the user's invoices, newest first
+func (s *BillingService) Invoices(ctx context.Context, userID uuid.UUID) ([]domain.Invoice, error) {
+	invoices, err := s.billingRepo.GetInvoicesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if invoices == nil {
+		invoices = []domain.Invoice{}
+	}
+	return invoices, nil
+}
+
+// stripeEvent is a Stripe webhook event
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// stripeCheckoutSession is the object of checkout.session events
+type stripeCheckoutSession struct {
+	Customer          string `json:"customer"`
+	Subscription      string `json:"subscription"`
+	ClientReferenceID string `json:"client_reference_id"`
+}
+
+// stripeSubscription is the object of customer.subscription events
+type stripeSubscription struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// stripeInvoice is the object of invoice events
+type stripeInvoice struct {
+	ID               string `json:"id"`
+	Customer         string `json:"customer"`
+	Number           string `json:"number"`
+	Status           string `json:"status"`
+	Currency         string `json:"currency"`
+	AmountDue        int64  `json:"amount_due"`
+	AmountPaid       int64  `json:"amount_paid"`
+	HostedInvoiceURL string `json:"hosted_invoice_url"`
+	InvoicePDF       string `json:"invoice_pdf"`
+	PeriodStart      int64  `json:"period_start"`
+	PeriodEnd        int64  `json:"period_end"`
+	Created          int64  `json:"created"`
+}
+
+// HandleWebhook verifies and applies a Stripe webhook event. Events are applied
+// idempotently, as Stripe may deliver them more than once and in any order.
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	if !s.config.Enabled() {
+		return ErrBillingDisabled
+	}
+	if err := VerifyStripeSignature(payload, signature, s.config.WebhookSecret, time.Now()); err != nil {
+		return err
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("invalid Stripe event: %w", err)
+	}
+
+	switch event.Type {
+	case "checkout.session.completed":
+		var session stripeCheckoutSession
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return fmt.Errorf("invalid checkout session: %w", err)
+		}
+		return s.linkCustomer(ctx, session)
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+		var subscription stripeSubscription
+		if err := json.Unmarshal(event.Data.Object, &subscription); err != nil {
+			return fmt.Errorf("invalid subscription: %w", err)
+		}
+		if event.Type == "customer.subscription.deleted" {
+			subscription.Status = "canceled"
+		}
+		return s.applySubscription(ctx, subscription)
+	case "invoice.finalized", "invoice.paid", "invoice.payment_failed", "invoice.voided", "invoice.marked_uncollectible":
+		var invoice stripeInvoice
+		if err := json.Unmarshal(event.Data.Object, &invoice); err != nil {
+			return fmt.Errorf("invalid invoice: %w", err)
+		}
+		return s.saveInvoice(ctx, invoice)
+	}
+	return nil
+}
+
+// linkCustomer records the Stripe customer a completed checkout created for the user
+func (s *BillingService) linkCustomer(ctx context.Context, session stripeCheckoutSession) error {
+	userID, err := uuid.Parse(session.ClientReferenceID)
+	if err != nil || session.Customer == "" {
+		return nil
+	}
+
+	customer, err := s.billingRepo.GetCustomerByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		customer = &domain.BillingCustomer{UserID: userID, Plan: domain.PlanFree, CreatedAt: time.Now()}
+	}
+	if customer.StripeCustomerID == session.Customer && customer.StripeSubscriptionID != "" {
+		// The subscription was already applied
+		return nil
+	}
+
+	customer.StripeCustomerID = session.Customer
+	customer.StripeSubscriptionID = session.Subscription
+	customer.UpdatedAt = time.Now()
+	return s.billingRepo.SaveCustomer(ctx, customer)
+}
+
+// applySubscription moves the subscriber to the plan of an active subscription,
+// or back to the free plan once it has ended
+func (s *BillingService) applySubscription(ctx context.Context, subscription stripeSubscription) error {
+	customer, err := s.billingRepo.GetCustomerByStripeID(ctx, subscription.Customer)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		// The subscription may be reported before its checkout completed
+		userID, err := uuid.Parse(subscription.Metadata["user_id"])
+		if err != nil {
+			log.Printf("Billing: ignoring subscription %s of unknown customer %s", subscription.ID, subscription.Customer)
+			return nil
+		}
+		customer = &domain.BillingCustomer{UserID: userID, StripeCustomerID: subscription.Customer, CreatedAt: time.Now()}
+	} else if customer.StripeSubscriptionID != "" && customer.StripeSubscriptionID != subscription.ID && !domain.SubscriptionActive(subscription.Status) {
+		// An older subscription ended; the current one decides the plan
+		return nil
+	}
+
+	plan := domain.PlanFree
+	if domain.SubscriptionActive(subscription.Status) {
+		plan = s.planForSubscription(subscription)
+	}
+
+	customer.StripeSubscriptionID = subscription.ID
+	customer.SubscriptionStatus = subscription.Status
+	customer.Plan = plan
+	customer.UpdatedAt = time.Now()
+	if subscription.CurrentPeriodEnd > 0 {
+		periodEnd := time.Unix(subscription.CurrentPeriodEnd, 0).UTC()
+		customer.CurrentPeriodEnd = &periodEnd
+	}
+	if err := s.billingRepo.SaveCustomer(ctx, customer); err != nil {
+		return fmt.Errorf("failed to save billing customer: %w", err)
+	}
+
+	if _, err := s.planService.SetUserPlan(ctx, customer.UserID, plan); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Billing: subscription %s belongs to deleted user %s", subscription.ID, customer.UserID)
+			return nil
+		}
+		return err
+	}
+	s.quotaService.Refresh(ctx, customer.UserID)
+	return nil
+}
+
+// planForSubscription returns the plan whose price the subscription is for
+func (s *BillingService) planForSubscription(subscription stripeSubscription) string {
+	for _, item := range subscription.Items.Data {
+		for plan, price := range s.config.Prices {
+			if item.Price.ID == price {
+				return plan
+			}
+		}
+	}
+	log.Printf("Billing: subscription %s is for no configured price", subscription.ID)
+	return domain.PlanFree
+}
+
+// saveInvoice stores an invoice of a known customer
+func (s *BillingService) saveInvoice(ctx context.Context, stripe stripeInvoice) error {
+	customer, err := s.billingRepo.GetCustomerByStripeID(ctx, stripe.Customer)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		// Stripe redelivers the event until the customer is known
+		return fmt.Errorf("invoice %s of unknown customer %s", stripe.ID, stripe.Customer)
+	}
+
+	invoice := &domain.Invoice{
+		ID:               uuid.New(),
+		UserID:           customer.UserID,
+		StripeInvoiceID:  stripe.ID,
+		Number:           stripe.Number,
+		Status:           stripe.Status,
+		Currency:         stripe.Currency,
+		AmountDue:        stripe.AmountDue,
+		AmountPaid:       stripe.AmountPaid,
+		HostedInvoiceURL: stripe.HostedInvoiceURL,
+		InvoicePDF:       stripe.InvoicePDF,
+		PeriodStart:      unixTime(stripe.PeriodStart),
+		PeriodEnd:        unixTime(stripe.PeriodEnd),
+		CreatedAt:        time.Unix(stripe.Created, 0).UTC(),
+		UpdatedAt:        time.Now(),
+	}
+	return s.billingRepo.SaveInvoice(ctx, invoice)
+}
+
+// unixTime converts a Unix timestamp to a time, or nil if it is unset
+func unixTime(seconds int64) *time.Time {
+	if seconds <= 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
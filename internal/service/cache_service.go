@@ -3,22 +3,65 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"products/internal/domain"
+	"products/internal/resilience"
 )
 
-// CacheService handles Redis caching operations
+// ErrCacheUnavailable is returned while Redis is unreachable or caching is disabled.
+// Cached reads treat it as a miss and fall through to the database.
+var ErrCacheUnavailable = errors.New("cache unavailable")
+
+// CacheService handles Redis caching operations. Calls run through a resilience
+// executor so that an unreachable Redis fails fast instead of delaying every request.
 type CacheService struct {
-	Client *redis.Client
+	Client   *redis.Client
+	executor *resilience.Executor
 }
 
-// NewCacheService creates a new cache service
-func NewCacheService(client *redis.Client) *CacheService {
+var _ domain.Cache = (*CacheService)(nil)
+
+// NewCacheService creates a new cache service whose calls run through executor. A
+// nil client disables caching, with every operation failing with ErrCacheUnavailable.
+func NewCacheService(client *redis.Client, executor *resilience.Executor) *CacheService {
 	return &CacheService{
-		Client: client,
+		Client:   client,
+		executor: executor,
+	}
+}
+
+// Available reports whether cache operations are currently attempted
+func (s *CacheService) Available() bool {
+	return s.Client != nil && s.executor.Available()
+}
+
+// call runs an idempotent Redis operation, retrying transient failures
+func (s *CacheService) call(ctx context.Context, operation func(ctx context.Context) error) error {
+	if s.Client == nil {
+		return ErrCacheUnavailable
 	}
+	return unavailable(s.executor.Do(ctx, operation))
+}
+
+// callOnce runs a Redis operation that must not be repeated
+func (s *CacheService) callOnce(ctx context.Context, operation func(ctx context.Context) error) error {
+	if s.Client == nil {
+		return ErrCacheUnavailable
+	}
+	return unavailable(s.executor.DoOnce(ctx, operation))
+}
+
+// unavailable marks the errors of calls that could not reach Redis with ErrCacheUnavailable
+func unavailable(err error) error {
+	if errors.Is(err, resilience.ErrUnavailable) {
+		return fmt.Errorf("%w: %v", ErrCacheUnavailable, err)
+	}
+	return err
 }
 
 // Set stores a key-value pair in Redis with expiration
@@ -28,36 +71,68 @@ func (s *CacheService) Set(ctx context.Context, key string, value interface{}, e
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
 
-	return s.Client.Set(ctx, key, jsonValue, expiration).Err()
+	return s.SetRaw(ctx, key, jsonValue, expiration)
 }
 
 // Get retrieves a value from Redis by key
 func (s *CacheService) Get(ctx context.Context, key string, dest interface{}) error {
-	value, err := s.Client.Get(ctx, key).Result()
+	value, err := s.GetRaw(ctx, key)
 	if err != nil {
-		if err == redis.Nil {
-			return fmt.Errorf("failed to get value: %w", err)
-		}
-		return fmt.Errorf("failed to get value: %w", err)
+		return err
+	}
+
+	return json.Unmarshal(value, dest)
+}
+
+// GetRaw retrieves the raw JSON stored under key
+func (s *CacheService) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.call(ctx, func(ctx context.Context) (err error) {
+		value, err = s.Client.Get(ctx, key).Bytes()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get value: %w", err)
 	}
 
-	return json.Unmarshal([]byte(value), dest)
+	return value, nil
+}
+
+// SetRaw stores already-encoded JSON under key with expiration
+func (s *CacheService) SetRaw(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	return s.call(ctx, func(ctx context.Context) error {
+		return s.Client.Set(ctx, key, value, expiration).Err()
+	})
 }
 
 // Delete removes a key from Redis
 func (s *CacheService) Delete(ctx context.Context, key string) error {
-	return s.Client.Del(ctx, key).Err()
+	return s.call(ctx, func(ctx context.Context) error {
+		return s.Client.Del(ctx, key).Err()
+	})
+}
+
+// Keys returns the keys matching a pattern
+func (s *CacheService) Keys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	err := s.call(ctx, func(ctx context.Context) (err error) {
+		keys, err = s.Client.Keys(ctx, pattern).Result()
+		return err
+	})
+	return keys, err
 }
 
 // DeletePattern removes keys matching a pattern
 func (s *CacheService) DeletePattern(ctx context.Context, pattern string) error {
-	keys, err := s.Client.Keys(ctx, pattern).Result()
+	keys, err := s.Keys(ctx, pattern)
 	if err != nil {
 		return fmt.Errorf("failed to get keys: %w", err)
 	}
-	
+
 	if len(keys) > 0 {
-		return s.Client.Del(ctx, keys...).Err()
+		return s.call(ctx, func(ctx context.Context) error {
+			return s.Client.Del(ctx, keys...).Err()
+		})
 	}
 
 	return nil
@@ -65,7 +140,11 @@ func (s *CacheService) DeletePattern(ctx context.Context, pattern string) error
 
 // Exists checks if a key exists in Redis
 func (s *CacheService) Exists(ctx context.Context, key string) (bool, error) {
-	result, err := s.Client.Exists(ctx, key).Result()
+	var result int64
+	err := s.call(ctx, func(ctx context.Context) (err error) {
+		result, err = s.Client.Exists(ctx, key).Result()
+		return err
+	})
 	if err != nil {
 		return false, fmt.Errorf("failed to check key existence: %w", err)
 	}
@@ -80,15 +159,174 @@ func (s *CacheService) SetNX(ctx context.Context, key string, value interface{},
 		return false, fmt.Errorf("failed to marshal value: %w", err)
 	}
 
-	return s.Client.SetNX(ctx, key, jsonValue, expiration).Result()
+	var acquired bool
+	err = s.callOnce(ctx, func(ctx context.Context) (err error) {
+		acquired, err = s.Client.SetNX(ctx, key, jsonValue, expiration).Result()
+		return err
+	})
+	return acquired, err
 }
 
 // Incr increments a counter in Redis
 func (s *CacheService) Incr(ctx context.Context, key string) (int64, error) {
-	return s.Client.Incr(ctx, key).Result()
+	var count int64
+	err := s.callOnce(ctx, func(ctx context.Context) (err error) {
+		count, err = s.Client.Incr(ctx, key).Result()
+		return err
+	})
+	return count, err
 }
 
 // Expire sets expiration for a key
 func (s *CacheService) Expire(ctx context.Context, key string, expiration time.Duration) error {
-	return s.Client.Expire(ctx, key, expiration).Err()
+	return s.call(ctx, func(ctx context.Context) error {
+		return s.Client.Expire(ctx, key, expiration).Err()
+	})
+}
+
+// IncrHash adds to counters held in the fields of a hash and adds member to the
+// set under setKey, setting the expiration of both in one round trip
+func (s *CacheService) IncrHash(ctx context.Context, key string, increments map[string]int64, setKey, member string, expiration time.Duration) error {
+	return s.callOnce(ctx, func(ctx context.Context) error {
+		_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for field, increment := range increments {
+				pipe.HIncrBy(ctx, key, field, increment)
+			}
+			pipe.Expire(ctx, key, expiration)
+			pipe.SAdd(ctx, setKey, member)
+			pipe.Expire(ctx, setKey, expiration)
+			return nil
+		})
+		return err
+	})
+}
+
+// GetHashInts retrieves the integer fields of a hash; a missing hash has none
+func (s *CacheService) GetHashInts(ctx context.Context, key string) (map[string]int64, error) {
+	var values map[string]string
+	err := s.call(ctx, func(ctx context.Context) (err error) {
+		values, err = s.Client.HGetAll(ctx, key).Result()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash: %w", err)
+	}
+
+	ints := make(map[string]int64, len(values))
+	for field, value := range values {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s field %s: %w", key, field, err)
+		}
+		ints[field] = parsed
+	}
+	return ints, nil
+}
+
+// SetMembers retrieves the members of a set
+func (s *CacheService) SetMembers(ctx context.Context, key string) ([]string, error) {
+	var members []string
+	err := s.call(ctx, func(ctx context.Context) (err error) {
+		members, err = s.Client.SMembers(ctx, key).Result()
+		return err
+	})
+	return members, err
+}
+
+// SetScheduled stores a value under key with expiration and adds member to the
+// sorted set under setKey, scored by the time it is due at, in one round trip
+func (s *CacheService) SetScheduled(ctx context.Context, key string, value interface{}, expiration time.Duration, setKey, member string, at time.Time) error {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	return s.call(ctx, func(ctx context.Context) error {
+		_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, jsonValue, expiration)
+			pipe.ZAdd(ctx, setKey, redis.Z{Score: float64(at.Unix()), Member: member})
+			return nil
+		})
+		return err
+	})
+}
+
+// Schedule adds member to the sorted set under setKey, due at the given time
+func (s *CacheService) Schedule(ctx context.Context, setKey, member string, at time.Time) error {
+	return s.call(ctx, func(ctx context.Context) error {
+		return s.Client.ZAdd(ctx, setKey, redis.Z{Score: float64(at.Unix()), Member: member}).Err()
+	})
+}
+
+// Unschedule removes member from the sorted set under setKey and reports whether it
+// was there, so that concurrent callers can agree on which of them removed it
+func (s *CacheService) Unschedule(ctx context.Context, setKey, member string) (bool, error) {
+	var removed int64
+	err := s.callOnce(ctx, func(ctx context.Context) (err error) {
+		removed, err = s.Client.ZRem(ctx, setKey, member).Result()
+		return err
+	})
+	return removed > 0, err
+}
+
+// DueMembers retrieves up to limit members of the sorted set under setKey that are
+// due at or before now, earliest first
+func (s *CacheService) DueMembers(ctx context.Context, setKey string, now time.Time, limit int64) ([]string, error) {
+	var members []string
+	err := s.call(ctx, func(ctx context.Context) (err error) {
+		members, err = s.Client.ZRangeByScore(ctx, setKey, &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   strconv.FormatInt(now.Unix(), 10),
+			Count: limit,
+		}).Result()
+		return err
+	})
+	return members, err
+}
+
+var _ domain.Broker = (*CacheService)(nil)
+
+// Publish sends message to the subscribers of channel on every instance
+func (s *CacheService) Publish(ctx context.Context, channel string, message []byte) error {
+	return s.callOnce(ctx, func(ctx context.Context) error {
+		return s.Client.Publish(ctx, channel, message).Err()
+	})
+}
+
+// Subscribe returns the messages published on channel until ctx is done. Redis
+// reconnects the subscription by itself, dropping the messages published meanwhile.
+func (s *CacheService) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	if !s.Available() {
+		return nil, ErrCacheUnavailable
+	}
+
+	pubsub := s.Client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("%w: %v", ErrCacheUnavailable, err)
+	}
+
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		received := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-received:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- []byte(message.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
 }
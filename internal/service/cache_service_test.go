@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCacheService_DisabledWithoutClient(t *testing.T) {
+	cache := NewCacheService(nil, nil)
+
+	if cache.Available() {
+		t.Error("Expected a cache without client to be unavailable")
+	}
+	var value string
+	if err := cache.Get(context.Background(), "key", &value); !errors.Is(err, ErrCacheUnavailable) {
+		t.Errorf("Expected ErrCacheUnavailable, got %v", err)
+	}
+}
+
+func TestSessionService_StatelessFallback(t *testing.T) {
+	sessions := NewSessionService(NewCacheSessionStore(NewCacheService(nil, nil)))
+
+	if valid, _ := sessions.IsSessionValid(context.Background(), "session"); valid {
+		t.Error("Expected sessions to be invalid without the cache by default")
+	}
+
+	sessions.SetStatelessFallback(true)
+	if valid, err := sessions.IsSessionValid(context.Background(), "session"); !valid || err != nil {
+		t.Errorf("Expected stateless fallback to accept the session, got %v, %v", valid, err)
+	}
+}
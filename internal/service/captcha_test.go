@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSiteverifyCaptcha_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("secret") != "topsecret" || r.FormValue("remoteip") != "203.0.113.7" {
+			t.Errorf("Unexpected verification form %v", r.Form)
+		}
+		if r.FormValue("response") == "solved" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+	}))
+	defer server.Close()
+
+	verifier, err := NewCaptchaVerifier(CaptchaProviderHCaptcha, "topsecret")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	verifier.verifyURL = server.URL
+
+	if valid, err := verifier.Verify(context.Background(), "solved", "203.0.113.7"); !valid || err != nil {
+		t.Errorf("Expected solved token to verify, got %v, %v", valid, err)
+	}
+	if valid, err := verifier.Verify(context.Background(), "guessed", "203.0.113.7"); valid || err != nil {
+		t.Errorf("Expected guessed token to be rejected, got %v, %v", valid, err)
+	}
+}
+
+func TestNewCaptchaVerifier_Invalid(t *testing.T) {
+	if _, err := NewCaptchaVerifier("turnstile", "secret"); err == nil {
+		t.Error("Expected unsupported provider to be rejected")
+	}
+	if _, err := NewCaptchaVerifier(CaptchaProviderReCaptcha, ""); err == nil {
+		t.Error("Expected provider without secret to be rejected")
+	}
+}
+
+type stubCaptcha struct {
+	calls int
+}
+
+func (s *stubCaptcha) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	s.calls++
+	return true, nil
+}
+
+func TestLoginGuard_WithoutCache(t *testing.T) {
+	verifier := &stubCaptcha{}
+	guard := NewLoginGuard(NewCacheService(nil, nil), verifier, 1, time.Minute)
+
+	guard.RecordFailure(context.Background(), "203.0.113.7")
+	if err := guard.Check(context.Background(), "203.0.113.7", ""); err != nil {
+		t.Errorf("Expected no CAPTCHA while failures are unknown, got %v", err)
+	}
+	if verifier.calls != 0 {
+		t.Errorf("Expected no verification, got %d", verifier.calls)
+	}
+
+	var disabled *LoginGuard
+	disabled.RecordFailure(context.Background(), "203.0.113.7")
+	if err := disabled.Check(context.Background(), "203.0.113.7", ""); err != nil {
+		t.Errorf("Expected a nil guard to allow logins, got %v", err)
+	}
+}
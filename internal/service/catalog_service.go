@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// publicCacheTTL bounds how stale public catalog responses may be
+const publicCacheTTL = 5 * time.Minute
+
+// slugRegex matches public catalog slugs
+var slugRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,38}[a-z0-9]$`)
+
+// CatalogService serves opt-in public products to anonymous visitors
+type CatalogService struct {
+	productRepo  domain.ProductRepository
+	userRepo     domain.UserRepository
+	cacheService domain.Cache
+	authorizer   *ProductAuthorizer
+}
+
+// NewCatalogService creates a new catalog service
+func NewCatalogService(productRepo domain.ProductRepository, userRepo domain.UserRepository, cacheService domain.Cache, authorizer *ProductAuthorizer) *CatalogService {
+	return &CatalogService{
+		productRepo:  productRepo,
+		userRepo:     userRepo,
+		cacheService: cacheService,
+		authorizer:   authorizer,
+	}
+}
+
+// SetPublic publishes or unpublishes a product the user may publish
+func (s *CatalogService) SetPublic(ctx context.Context, id, userID uuid.UUID, public bool) error {
+	product, err := s.productRepo.GetByID(repository.WithPrimary(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, product, ProductActionPublish); err != nil {
+		return err
+	}
+
+	if err := s.productRepo.SetPublic(ctx, id, public); err != nil {
+		return err
+	}
+
+	s.cacheService.DeletePattern(ctx, fmt.Sprintf("product:*:%s", id))
+	invalidatePublicCache(ctx, s.cacheService, product.UserID, id)
+
+	return nil
+}
+
+// SetSlug sets the slug under which the user's public catalog is listed
+func (s *CatalogService) SetSlug(ctx context.Context, userID uuid.UUID, slug string) error {
+	if !slugRegex.MatchString(slug) {
+		return errors.New("slug must be 3-40 lowercase letters, digits or hyphens")
+	}
+
+	if existing, err := s.userRepo.GetBySlug(ctx, slug); err == nil && existing.ID != userID {
+		return errors.New("slug is already taken")
+	}
+
+	if err := s.userRepo.SetSlug(ctx, userID, slug); err != nil {
+		return err
+	}
+
+	s.cacheService.DeletePattern(ctx, fmt.Sprintf("public:user_products:%s:*", userID))
+
+	return nil
+}
+
+// GetProduct returns a public product
+func (s *CatalogService) GetProduct(ctx context.Context, id uuid.UUID) (*domain.PublicProductResponse, error) {
+	cacheKey := publicProductCacheKey(id)
+	var cachedProduct domain.PublicProductResponse
+	if err := s.cacheService.Get(ctx, cacheKey, &cachedProduct); err == nil {
+		return &cachedProduct, nil
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil || !product.IsListedPublicly() {
+		return nil, domain.ErrProductNotFound
+	}
+
+	response := toPublicProduct(product, product.User.Slug)
+	s.cacheService.Set(ctx, cacheKey, response, publicCacheTTL)
+
+	return response, nil
+}
+
+// ListBySlug returns a page of the public products of the user with the given slug
+func (s *CatalogService) ListBySlug(ctx context.Context, slug string, pagination domain.Pagination) (*domain.PublicProductListResponse, error) {
+	user, err := s.userRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("public:user_products:%s:%d:%d", user.ID, pagination.Page, pagination.PageSize)
+	var cachedList domain.PublicProductListResponse
+	if err := s.cacheService.Get(ctx, cacheKey, &cachedList); err == nil {
+		return &cachedList, nil
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	products, total, err := s.productRepo.GetPublicByUserID(ctx, user.ID, offset, pagination.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.PublicProductListResponse{
+		Seller:     slug,
+		Products:   make([]domain.PublicProductResponse, 0, len(products)),
+		Total:      total,
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		TotalPages: int((total + int64(pagination.PageSize) - 1) / int64(pagination.PageSize)),
+	}
+	for i := range products {
+		response.Products = append(response.Products, *toPublicProduct(&products[i], user.Slug))
+	}
+
+	s.cacheService.Set(ctx, cacheKey, response, publicCacheTTL)
+
+	return response, nil
+}
+
+// toPublicProduct strips owner details from a product
+func toPublicProduct(product *domain.Product, slug *string) *domain.PublicProductResponse {
+	response := &domain.PublicProductResponse{
+		ID:              product.ID,
+		Name:            product.Name,
+		Description:     product.Description,
+		DescriptionHTML: product.DescriptionHTML,
+		Price:           product.Price,
+		Stock:           product.Stock,
+		Attributes:      product.Attributes,
+		RatingAverage:   product.RatingAverage,
+		RatingCount:     product.RatingCount,
+		CreatedAt:       product.CreatedAt,
+		UpdatedAt:       product.UpdatedAt,
+	}
+	if slug != nil {
+		response.Seller = *slug
+	}
+	return response
+}
+
+// publicProductCacheKey returns the public cache key of a product
+func publicProductCacheKey(id uuid.UUID) string {
+	return fmt.Sprintf("public:product:%s", id)
+}
+
+// invalidatePublicCache drops cached public responses for a product and its owner's listing
+func invalidatePublicCache(ctx context.Context, cacheService domain.Cache, userID, productID uuid.UUID) {
+	cacheService.Delete(ctx, publicProductCacheKey(productID))
+	cacheService.DeletePattern(ctx, fmt.Sprintf("public:user_products:%s:*", userID))
+}
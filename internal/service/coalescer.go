@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// coalescedCall is an in-flight or completed coalesced call
+type coalescedCall struct {
+	done chan struct{}
+	val  []byte
+	err  error
+}
+
+// coalescer ensures only one execution of a function is in flight for a given key;
+// concurrent callers with the same key wait for and share its result.
+type coalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+// newCoalescer creates a new coalescer
+func newCoalescer() *coalescer {
+	return &coalescer{calls: make(map[string]*coalescedCall)}
+}
+
+// Do executes fn once for all concurrent callers using the same key.
+// shared reports whether the result was produced by another caller.
+// A caller whose ctx is done stops waiting and gets its error, while fn keeps
+// running for the others, so fn must not depend on the cancellation of ctx.
+func (g *coalescer) Do(ctx context.Context, key string, fn func() ([]byte, error)) (val []byte, err error, shared bool) {
+	g.mu.Lock()
+	call, shared := g.calls[key]
+	if !shared {
+		call = &coalescedCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.run(key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err, shared
+	case <-ctx.Done():
+		return nil, ctx.Err(), shared
+	}
+}
+
+// run executes fn for the call and releases its waiters. fn runs on its own
+// goroutine, so a panic is returned as an error instead of crashing the process.
+func (g *coalescer) run(key string, call *coalescedCall, fn func() ([]byte, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("coalesced call panicked: %v", r)
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.val, call.err = fn()
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescer_SharesConcurrentCalls(t *testing.T) {
+	g := newCoalescer()
+	var executions int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			val, err, _ := g.Do(context.Background(), "key", func() ([]byte, error) {
+				atomic.AddInt32(&executions, 1)
+				<-release
+				return []byte("result"), nil
+			})
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			results[i] = string(val)
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if executions != 1 {
+		t.Errorf("Expected 1 execution, got %d", executions)
+	}
+	for i, result := range results {
+		if result != "result" {
+			t.Errorf("Caller %d: expected 'result', got '%s'", i, result)
+		}
+	}
+}
+
+func TestCoalescer_RunsAgainAfterCompletion(t *testing.T) {
+	g := newCoalescer()
+	var executions int32
+
+	for i := 0; i < 2; i++ {
+		g.Do(context.Background(), "key", func() ([]byte, error) {
+			atomic.AddInt32(&executions, 1)
+			return nil, nil
+		})
+	}
+
+	if executions != 2 {
+		t.Errorf("Expected 2 executions, got %d", executions)
+	}
+}
+
+func TestCoalescer_StopsWaitingWhenCancelled(t *testing.T) {
+	g := newCoalescer()
+	release := make(chan struct{})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err, _ := g.Do(ctx, "key", func() ([]byte, error) {
+		<-release
+		return []byte("result"), nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected the deadline to end the wait, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// ErrEmailTemplateNotFound is returned for unknown email template keys
+var ErrEmailTemplateNotFound = domain.NotFoundError(domain.CodeNotFound, "email template not found")
+
+// EmailTemplateService manages transactional email templates and renders emails from them
+type EmailTemplateService struct {
+	templateRepo *repository.EmailTemplateRepository
+}
+
+// NewEmailTemplateService creates a new email template service
+func NewEmailTemplateService(templateRepo *repository.EmailTemplateRepository) *EmailTemplateService {
+	return &EmailTemplateService{
+		templateRepo: templateRepo,
+	}
+}
+
+// List returns every email template, customized or default
+func (s *EmailTemplateService) List(ctx context.Context) ([]domain.EmailTemplateResponse, error) {
+	customized, err := s.templateRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*domain.EmailTemplate, len(customized))
+	for i := range customized {
+		byKey[customized[i].Key] = &customized[i]
+	}
+
+	keys := make([]string, 0, len(domain.EmailTemplateSpecs))
+	for key := range domain.EmailTemplateSpecs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	responses := make([]domain.EmailTemplateResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, toEmailTemplateResponse(key, byKey[key]))
+	}
+	return responses, nil
+}
+
+// Get returns a single email template
+func (s *EmailTemplateService) Get(ctx context.Context, key string) (*domain.EmailTemplateResponse, error) {
+	if _, ok := domain.EmailTemplateSpecs[key]; !ok {
+		return nil, ErrEmailTemplateNotFound
+	}
+
+	tmpl, err := s.templateRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toEmailTemplateResponse(key, tmpl)
+	return &response, nil
+}
+
+// Update validates and stores a customized email template
+func (s *EmailTemplateService) Update(ctx context.Context, key string, adminID uuid.UUID, req domain.EmailTemplateRequest) (*domain.EmailTemplateResponse, error) {
+	if _, ok := domain.EmailTemplateSpecs[key]; !ok {
+		return nil, ErrEmailTemplateNotFound
+	}
+
+	if err := domain.ValidateEmailTemplate(key, req.Subject, req.Body); err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	now := time.Now()
+	tmpl := &domain.EmailTemplate{
+		Key:       key,
+		Subject:   req.Subject,
+		Body:      req.Body,
+		UpdatedBy: &adminID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.templateRepo.Upsert(ctx, tmpl); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, key)
+}
+
+// Reset discards a customized email template so the default is used again
+func (s *EmailTemplateService) Reset(ctx context.Context, key string) error {
+	if _, ok := domain.EmailTemplateSpecs[key]; !ok {
+		return ErrEmailTemplateNotFound
+	}
+	return s.templateRepo.Delete(ctx, key)
+}
+
+// Preview renders a template, or unsaved changes to it, with sample variables
+func (s *EmailTemplateService) Preview(ctx context.Context, key string, req domain.EmailTemplatePreviewRequest) (*domain.RenderedEmail, error) {
+	current, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := domain.EmailTemplate{Key: key, Subject: current.Subject, Body: current.Body}
+	if req.Subject != "" {
+		tmpl.Subject = req.Subject
+	}
+	if req.Body != "" {
+		tmpl.Body = req.Body
+	}
+
+	if err := domain.ValidateEmailTemplate(key, tmpl.Subject, tmpl.Body); err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	variables := make(map[string]string)
+	for name, value := range domain.EmailTemplateSpecs[key].Sample {
+		variables[name] = value
+	}
+	for name, value := range req.Variables {
+		variables[name] = value
+	}
+
+	rendered, err := domain.RenderEmailTemplate(tmpl, variables)
+	if err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+	return rendered, nil
+}
+
+// Render renders the current version of a template for sending
+func (s *EmailTemplateService) Render(ctx context.Context, key string, variables map[string]string) (*domain.RenderedEmail, error) {
+	current, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.RenderEmailTemplate(domain.EmailTemplate{
+		Key:     key,
+		Subject: current.Subject,
+		Body:    current.Body,
+	}, variables)
+}
+
+// toEmailTemplateResponse describes a template, falling back to its default when not customized
+func toEmailTemplateResponse(key string, tmpl *domain.EmailTemplate) domain.EmailTemplateResponse {
+	spec := domain.EmailTemplateSpecs[key]
+	response := domain.EmailTemplateResponse{
+		Key:       key,
+		Subject:   spec.Default.Subject,
+		Body:      spec.Default.Body,
+		Variables: spec.Variables,
+		Required:  spec.Required,
+	}
+
+	if tmpl != nil {
+		response.Subject = tmpl.Subject
+		response.Body = tmpl.Body
+		response.Customized = true
+		response.UpdatedBy = tmpl.UpdatedBy
+		response.UpdatedAt = &tmpl.UpdatedAt
+	}
+
+	return response
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrorReporter sends errors, with their stack and the request they failed, to an
+// error tracker
+type ErrorReporter interface {
+	// Report queues a report without blocking
+	Report(ctx context.Context, report ErrorReport)
+
+	// Flush waits until the queued reports are sent or ctx is done
+	Flush(ctx context.Context) error
+}
+
+// ErrorReport is an error reported to an ErrorReporter
+type ErrorReport struct {
+	Err error
+
+	// Panic is set when Err was recovered from a panic
+	Panic bool
+
+	// Stack holds the program counters of the stack the error was reported from,
+	// see CaptureStack
+	Stack []uintptr
+
+	// Request describes the request the error failed, if any
+	Request *ReportedRequest
+
+	UserID *uuid.UUID
+	Tags   map[string]string
+}
+
+// ReportedRequest describes a failed request. It must not carry credentials; the
+// reporting middleware redacts the query and leaves out sensitive headers.
+type ReportedRequest struct {
+	Method   string
+	URL      string
+	Route    string
+	ClientIP string
+	Headers  map[string]string
+}
+
+// CaptureStack returns the program counters of the calling goroutine's stack,
+// skipping skip frames above the caller of CaptureStack
+func CaptureStack(skip int) []uintptr {
+	pcs := make([]uintptr, 64)
+	return pcs[:runtime.Callers(skip+2, pcs)]
+}
+
+// NopErrorReporter discards reports, for when no error tracker is configured
+type NopErrorReporter struct{}
+
+// Report discards the report
+func (NopErrorReporter) Report(ctx context.Context, report ErrorReport) {}
+
+// Flush returns immediately
+func (NopErrorReporter) Flush(ctx context.Context) error { return nil }
+
+// User ID handling of SentryConfig.UserIDs
+const (
+	UserIDsKeep = "keep"
+	UserIDsHash = "hash"
+	UserIDsDrop = "drop"
+)
+
+// SentryConfig configures a SentryReporter
+type SentryConfig struct {
+	// DSN is the project's client key URL, e.g. https://<key>@o1.ingest.sentry.io/42
+	DSN string
+
+	// Environment and Release tag every event
+	Environment string
+	Release     string
+
+	// UserIDs is UserIDsKeep, UserIDsHash to send a hash that still groups a
+	// user's events, or UserIDsDrop
+	UserIDs string
+
+	// BufferSize is the number of reports queued before new ones are dropped
+	BufferSize int
+
+	// Timeout bounds each request to Sentry
+	Timeout time.Duration
+}
+
+// DefaultSentryConfig returns the default Sentry configuration, without a DSN
+func DefaultSentryConfig() SentryConfig {
+	return SentryConfig{
+		Environment: "production",
+		UserIDs:     UserIDsKeep,
+		BufferSize:  256,
+		Timeout:     5 * time.Second,
+	}
+}
+
+// SentryReporter sends reports to Sentry's envelope endpoint in the background
+type SentryReporter struct {
+	config   SentryConfig
+	endpoint string
+	auth     string
+	client   *http.Client
+	events   chan sentryEvent
+
+	// pending counts the events queued or being sent
+	pending atomic.Int64
+}
+
+// NewSentryReporter creates a reporter for the DSN of config and starts sending
+func NewSentryReporter(config SentryConfig) (*SentryReporter, error) {
+	switch config.UserIDs {
+	case "":
+		config.UserIDs = UserIDsKeep
+	case UserIDsKeep, UserIDsHash, UserIDsDrop:
+	default:
+		return nil, fmt.Errorf("user IDs must be %s, %s or %s", UserIDsKeep, UserIDsHash, UserIDsDrop)
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultSentryConfig().BufferSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultSentryConfig().Timeout
+	}
+
+	dsn, err := url.Parse(config.DSN)
+	if err != nil || dsn.User == nil || dsn.User.Username() == "" || dsn.Host == "" {
+		return nil, errors.New("invalid Sentry DSN")
+	}
+	path, projectID := "", strings.Trim(dsn.Path, "/")
+	if i := strings.LastIndex(projectID, "/"); i >= 0 {
+		path, projectID = "/"+projectID[:i], projectID[i+1:]
+	}
+	if projectID == "" {
+		return nil, errors.New("invalid Sentry DSN: missing project ID")
+	}
+
+	reporter := &SentryReporter{
+		config:   config,
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/envelope/", dsn.Scheme, dsn.Host, path, projectID),
+		auth:     fmt.Sprintf("Sentry sentry_version=7, sentry_client=products/1.0, sentry_key=%s", dsn.User.Username()),
+		client:   &http.Client{Timeout: config.Timeout},
+		events:   make(chan sentryEvent, config.BufferSize),
+	}
+	go reporter.run()
+	return reporter, nil
+}
+
+// Report converts the report to a Sentry event and queues it, dropping it when
+// the queue is full
+func (r *SentryReporter) Report(ctx context.Context, report ErrorReport) {
+	if report.Err == nil {
+		return
+	}
+
+	r.pending.Add(1)
+	select {
+	case r.events <- r.event(report):
+	default:
+		r.pending.Add(-1)
+		log.Printf("Error report dropped, queue full: %v", report.Err)
+	}
+}
+
+// Flush waits until the queued events are sent or ctx is done
+func (r *SentryReporter) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for r.pending.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to flush error reports: %w", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// run sends queued events one at a time
+func (r *SentryReporter) run() {
+	for event := range r.events {
+		if err := r.send(event); err != nil {
+			log.Printf("Failed to send error report %s: %v", event.EventID, err)
+		}
+		r.pending.Add(-1)
+	}
+}
+
+// send posts an event to the envelope endpoint
+func (r *SentryReporter) send(event sentryEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var envelope bytes.Buffer
+	header, _ := json.Marshal(map[string]string{"event_id": event.EventID, "sent_at": time.Now().UTC().Format(time.RFC3339)})
+	item, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(payload)})
+	envelope.Write(header)
+	envelope.WriteByte('\n')
+	envelope.Write(item)
+	envelope.WriteByte('\n')
+	envelope.Write(payload)
+	envelope.WriteByte('\n')
+
+	request, err := http.NewRequest(http.MethodPost, r.endpoint, &envelope)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-sentry-envelope")
+	request.Header.Set("X-Sentry-Auth", r.auth)
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("sentry answered %d", response.StatusCode)
+	}
+	return nil
+}
+
+// sentryEvent is the subset of Sentry's event payload the reporter sends
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Transaction string            `json:"transaction,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	User        *sentryUser       `json:"user,omitempty"`
+	Request     *sentryRequest    `json:"request,omitempty"`
+	Exception   struct {
+		Values []sentryException `json:"values"`
+	} `json:"exception"`
+}
+
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+type sentryRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Mechanism  map[string]any    `json:"mechanism,omitempty"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// event converts a report to a Sentry event
+func (r *SentryReporter) event(report ErrorReport) sentryEvent {
+	event := sentryEvent{
+		EventID:     strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Level:       "error",
+		Platform:    "go",
+		Environment: r.config.Environment,
+		Release:     r.config.Release,
+		Tags:        report.Tags,
+	}
+	if report.Panic {
+		event.Level = "fatal"
+	}
+
+	exception := sentryException{Type: reflect.TypeOf(report.Err).String(), Value: report.Err.Error()}
+	if report.Panic {
+		exception.Type = "panic"
+		exception.Mechanism = map[string]any{"type": "recovery", "handled": true}
+	}
+	if frames := stackFrames(report.Stack); len(frames) > 0 {
+		exception.Stacktrace = &sentryStacktrace{Frames: frames}
+	}
+	event.Exception.Values = []sentryException{exception}
+
+	if report.Request != nil {
+		event.Transaction = report.Request.Method + " " + report.Request.Route
+		event.Request = &sentryRequest{Method: report.Request.Method, URL: report.Request.URL, Headers: report.Request.Headers}
+	}
+	if report.UserID != nil {
+		switch r.config.UserIDs {
+		case UserIDsKeep:
+			event.User = &sentryUser{ID: report.UserID.String()}
+		case UserIDsHash:
+			sum := sha256.Sum256([]byte(report.UserID.String()))
+			event.User = &sentryUser{ID: hex.EncodeToString(sum[:8])}
+		}
+	}
+	return event
+}
+
+// stackFrames converts program counters to Sentry frames, outermost call first
+func stackFrames(stack []uintptr) []sentryFrame {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	var frames []sentryFrame
+	callers := runtime.CallersFrames(stack)
+	for {
+		frame, more := callers.Next()
+		module, function := splitFunctionName(frame.Function)
+		frames = append(frames, sentryFrame{
+			Function: function,
+			Module:   module,
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    strings.HasPrefix(module, "products/") || module == "main",
+		})
+		if !more {
+			break
+		}
+	}
+
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+// splitFunctionName splits a qualified function name such as
+// "products/internal/service.(*ProductService).GetByID" into its package and name
+func splitFunctionName(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+2+dot:]
+}
+
+// NewErrorReporter returns a SentryReporter for config, or a NopErrorReporter when
+// no DSN is configured
+func NewErrorReporter(config SentryConfig) (ErrorReporter, error) {
+	if config.DSN == "" {
+		return NopErrorReporter{}, nil
+	}
+	return NewSentryReporter(config)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSentryReporter(t *testing.T) {
+	var mu sync.Mutex
+	var paths, auths []string
+	var events []sentryEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// an envelope is a header, an item header and the event, one per line
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 1<<20), 1<<20)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		var event sentryEvent
+		if len(lines) == 3 {
+			json.Unmarshal([]byte(lines[2]), &event)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, r.URL.Path)
+		auths = append(auths, r.Header.Get("X-Sentry-Auth"))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	config := DefaultSentryConfig()
+	config.DSN = strings.Replace(server.URL, "http://", "http://public-key@", 1) + "/42"
+	config.Environment = "staging"
+	config.Release = "v1.2.3"
+	config.UserIDs = UserIDsHash
+	reporter, err := NewSentryReporter(config)
+	if err != nil {
+		t.Fatalf("NewSentryReporter: %v", err)
+	}
+
+	userID := uuid.New()
+	reporter.Report(context.Background(), ErrorReport{
+		Err:     errors.New("query failed"),
+		Stack:   CaptureStack(0),
+		Request: &ReportedRequest{Method: http.MethodGet, URL: "/api/v1/products", Route: "/api/v1/products/"},
+		UserID:  &userID,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := reporter.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 {
+		t.Fatalf("Expected one event, got %d", len(events))
+	}
+	if paths[0] != "/api/42/envelope/" || !strings.Contains(auths[0], "sentry_key=public-key") {
+		t.Errorf("Unexpected request to %s with auth %q", paths[0], auths[0])
+	}
+
+	event := events[0]
+	if event.Environment != "staging" || event.Release != "v1.2.3" || event.Transaction != "GET /api/v1/products/" {
+		t.Errorf("Unexpected event tags %+v", event)
+	}
+	if len(event.Exception.Values) != 1 || event.Exception.Values[0].Value != "query failed" {
+		t.Fatalf("Unexpected exception %+v", event.Exception)
+	}
+	frames := event.Exception.Values[0].Stacktrace.Frames
+	if last := frames[len(frames)-1]; last.Function != "TestSentryReporter" || !last.InApp {
+		t.Errorf("Expected the innermost frame last, got %+v", last)
+	}
+	if event.User == nil || event.User.ID == "" || event.User.ID == userID.String() {
+		t.Errorf("Expected a hashed user ID, got %+v", event.User)
+	}
+}
+
+func TestNewErrorReporter(t *testing.T) {
+	reporter, err := NewErrorReporter(DefaultSentryConfig())
+	if err != nil {
+		t.Fatalf("NewErrorReporter: %v", err)
+	}
+	if _, ok := reporter.(NopErrorReporter); !ok {
+		t.Errorf("Expected a NopErrorReporter without DSN, got %T", reporter)
+	}
+
+	for _, dsn := range []string{"not a url", "https://sentry.io/42", "https://key@sentry.io/"} {
+		config := DefaultSentryConfig()
+		config.DSN = dsn
+		if _, err := NewErrorReporter(config); err == nil {
+			t.Errorf("Expected DSN %q to be rejected", dsn)
+		}
+	}
+
+	config := DefaultSentryConfig()
+	config.DSN = "https://key@sentry.io/42"
+	config.UserIDs = "encrypt"
+	if _, err := NewErrorReporter(config); err == nil {
+		t.Error("Expected unknown user ID handling to be rejected")
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// DefaultEventRetention is how long domain events are kept by default
+const DefaultEventRetention = 90 * 24 * time.Hour
+
+// EventService records domain events in the append-only event log, the source
+// webhook deliveries are published from
+type EventService struct {
+	eventRepo *repository.EventRepository
+	webhooks  *WebhookService
+	stream    *StreamService
+	retention time.Duration
+}
+
+// NewEventService creates a new event service; events older than retention are
+// deleted, unless it is 0
+func NewEventService(eventRepo *repository.EventRepository, webhooks *WebhookService, retention time.Duration) *EventService {
+	return &EventService{
+		eventRepo: eventRepo,
+		webhooks:  webhooks,
+		retention: retention,
+	}
+}
+
+// SetStream sends recorded events to the user's open event streams
+func (s *EventService) SetStream(stream *StreamService) {
+	s.stream = stream
+}
+
+// Record appends an event to the log and publishes it to the user's event streams
+// and webhook subscribers. An event that could not be recorded is not published.
+func (s *EventService) Record(ctx context.Context, event *domain.Event) error {
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", event.Type, err)
+	}
+
+	if s.stream != nil {
+		if err := s.stream.PublishEvent(ctx, event); err != nil {
+			log.Printf("Failed to stream %s event: %v", event.Type, err)
+		}
+	}
+
+	if s.webhooks == nil {
+		return nil
+	}
+	webhookEvent, published, err := event.WebhookEvent()
+	if err != nil || !published {
+		return err
+	}
+	return s.webhooks.Publish(ctx, webhookEvent)
+}
+
+// Query returns a page of the user's events matching the filter
+func (s *EventService) Query(ctx context.Context, userID uuid.UUID, filter domain.EventFilter) (*domain.EventListResponse, error) {
+	filter.UserID = &userID
+	return s.QueryAll(ctx, filter)
+}
+
+// QueryAll returns a page of the events of every user matching the filter
+func (s *EventService) QueryAll(ctx context.Context, filter domain.EventFilter) (*domain.EventListResponse, error) {
+	if err := domain.ValidateEventTypes(filter.Types); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+
+	events, err := s.eventRepo.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	return domain.NewEventListResponse(filter.After, filter.Limit, events), nil
+}
+
+// StartRetentionWorker periodically deletes events older than the retention
+// period until ctx is cancelled; a deletion in progress runs to completion
+func (s *EventService) StartRetentionWorker(ctx context.Context, interval time.Duration) {
+	if s.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.eventRepo.DeleteOlderThan(context.WithoutCancel(ctx), time.Now().Add(-s.retention))
+			if err != nil {
+				log.Printf("Event retention worker: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Event retention worker: deleted %d events", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/netguard"
+	"products/internal/repository"
+	"products/internal/storage"
+)
+
+// Export statuses
+const (
+	ExportStatusSucceeded = "succeeded"
+	ExportStatusFailed    = "failed"
+)
+
+// exportInterval is the minimum time between two exports to the same destination
+const exportInterval = 24 * time.Hour
+
+// exportTimeout bounds an upload to a destination bucket
+const exportTimeout = 60 * time.Second
+
+// ExportService pushes scheduled product exports to user-configured buckets
+type ExportService struct {
+	destinationRepo     *repository.ExportDestinationRepository
+	backupService       *BackupService
+	notificationService *NotificationService
+	gcm                 cipher.AEAD
+	client              *http.Client
+	validateURL         func(ctx context.Context, rawURL string) error
+}
+
+// NewExportService creates a new export service. encryptionKey protects stored credentials.
+func NewExportService(destinationRepo *repository.ExportDestinationRepository, backupService *BackupService, notificationService *NotificationService, encryptionKey string) (*ExportService, error) {
+	key := sha256.Sum256([]byte(encryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &ExportService{
+		destinationRepo:     destinationRepo,
+		backupService:       backupService,
+		notificationService: notificationService,
+		gcm:                 gcm,
+		client:              netguard.NewClient(exportTimeout),
+		validateURL:         netguard.ValidateURL,
+	}, nil
+}
+
+// ConfigureDestination creates or replaces the user's export destination
+func (s *ExportService) ConfigureDestination(ctx context.Context, userID uuid.UUID, req domain.ExportDestinationRequest) (*domain.ExportDestination, error) {
+	if err := s.validateURL(ctx, req.Endpoint); err != nil {
+		return nil, errors.New("endpoint " + netguard.ErrInvalidURL.Error())
+	}
+
+	encryptedSecret, err := s.encrypt(req.SecretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := s.destinationRepo.GetByUserID(ctx, userID)
+	isNew := err != nil
+	if isNew {
+		destination = &domain.ExportDestination{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	destination.Endpoint = strings.TrimRight(req.Endpoint, "/")
+	destination.Region = req.Region
+	destination.Bucket = req.Bucket
+	destination.Prefix = strings.Trim(req.Prefix, "/")
+	destination.AccessKeyID = req.AccessKeyID
+	destination.SecretAccessKey = encryptedSecret
+	destination.UsePathStyle = req.UsePathStyle
+	destination.UpdatedAt = time.Now()
+
+	if isNew {
+		err = s.destinationRepo.Create(ctx, destination)
+	} else {
+		err = s.destinationRepo.Update(ctx, destination)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save export destination: %w", err)
+	}
+
+	return destination, nil
+}
+
+// GetDestination returns the user's export destination
+func (s *ExportService) GetDestination(ctx context.Context, userID uuid.UUID) (*domain.ExportDestination, error) {
+	return s.destinationRepo.GetByUserID(ctx, userID)
+}
+
+// RemoveDestination stops scheduled exports for the user
+func (s *ExportService) RemoveDestination(ctx context.Context, userID uuid.UUID) error {
+	return s.destinationRepo.DeleteByUserID(ctx, userID)
+}
+
+// RunDueExports exports to every destination whose last export is older than the export interval
+func (s *ExportService) RunDueExports(ctx context.Context) (int, error) {
+	destinations, err := s.destinationRepo.GetDue(ctx, time.Now().Add(-exportInterval))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due export destinations: %w", err)
+	}
+
+	for i := range destinations {
+		s.export(ctx, &destinations[i])
+	}
+
+	return len(destinations), nil
+}
+
+// StartScheduler periodically runs due exports until ctx is cancelled; exports
+// in progress run to completion
+func (s *ExportService) StartScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunDueExports(context.WithoutCancel(ctx)); err != nil {
+				log.Printf("Export scheduler: %v", err)
+			}
+		}
+	}
+}
+
+// export pushes a single export and records its delivery status
+func (s *ExportService) export(ctx context.Context, destination *domain.ExportDestination) {
+	now := time.Now().UTC()
+	key := fmt.Sprintf("products-%s.ndjson", now.Format("20060102T150405Z"))
+	if destination.Prefix != "" {
+		key = destination.Prefix + "/" + key
+	}
+
+	exportErr := s.push(ctx, destination, key)
+
+	status, errMessage := ExportStatusSucceeded, ""
+	if exportErr != nil {
+		log.Printf("Export for user %s failed: %v", destination.UserID, exportErr)
+		status, errMessage = ExportStatusFailed, exportErrorMessage(exportErr)
+	}
+
+	if err := s.destinationRepo.UpdateStatus(ctx, destination.ID, now, status, errMessage); err != nil {
+		log.Printf("Failed to record export status for user %s: %v", destination.UserID, err)
+	}
+
+	var notifyErr error
+	if exportErr != nil {
+		notifyErr = s.notificationService.Notify(ctx, destination.UserID, NotificationExportFailed,
+			"Product export failed",
+			fmt.Sprintf("The scheduled export to bucket %s failed: %s", destination.Bucket, errMessage))
+	} else {
+		notifyErr = s.notificationService.Notify(ctx, destination.UserID, NotificationExportSucceeded,
+			"Product export delivered",
+			fmt.Sprintf("Your products were exported to s3://%s/%s", destination.Bucket, key))
+	}
+	if notifyErr != nil {
+		log.Printf("Failed to notify user %s about export: %v", destination.UserID, notifyErr)
+	}
+}
+
+// push builds the export and uploads it to the destination bucket
+func (s *ExportService) push(ctx context.Context, destination *domain.ExportDestination, key string) error {
+	secret, err := s.decrypt(destination.SecretAccessKey)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.NewS3Store(&storage.S3Config{
+		Endpoint:        destination.Endpoint,
+		Region:          destination.Region,
+		Bucket:          destination.Bucket,
+		AccessKeyID:     destination.AccessKeyID,
+		SecretAccessKey: secret,
+		UsePathStyle:    destination.UsePathStyle,
+		HTTPClient:      s.client,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := s.backupService.Export(ctx, destination.UserID)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, key, data, "application/x-ndjson")
+}
+
+// exportErrorMessage describes a failed export to its user without the details of
+// network or storage errors, which would reveal what the server can reach
+func exportErrorMessage(err error) string {
+	if errors.Is(err, netguard.ErrBlockedAddress) {
+		return "the bucket endpoint address is not publicly routable"
+	}
+	return "the upload to the bucket did not succeed"
+}
+
+// encrypt seals a credential for storage
+func (s *ExportService) encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, s.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := s.gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens a stored credential
+func (s *ExportService) decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(data) < s.gcm.NonceSize() {
+		return "", errors.New("stored credentials are corrupted")
+	}
+
+	nonce, sealed := data[:s.gcm.NonceSize()], data[s.gcm.NonceSize():]
+	plaintext, err := s.gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errors.New("stored credentials could not be decrypted")
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/netguard"
+)
+
+func TestExportService_ConfigureDestinationRefusesInternalEndpoints(t *testing.T) {
+	service, err := NewExportService(nil, nil, nil, "test-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, endpoint := range []string{"http://93.184.216.34", "https://127.0.0.1:9000", "https://10.0.0.5", "https://169.254.169.254"} {
+		_, err := service.ConfigureDestination(context.Background(), uuid.New(), domain.ExportDestinationRequest{Endpoint: endpoint})
+		if err == nil {
+			t.Errorf("Expected endpoint %s to be rejected", endpoint)
+		}
+	}
+}
+
+func TestExportErrorMessage(t *testing.T) {
+	blocked := fmt.Errorf("dial tcp 10.0.0.5:443: %w", netguard.ErrBlockedAddress)
+	if message := exportErrorMessage(blocked); message != "the bucket endpoint address is not publicly routable" {
+		t.Errorf("Expected the blocked address message, got %q", message)
+	}
+
+	upload := fmt.Errorf("S3 PUT failed with status 403: <Error><Code>AccessDenied</Code></Error>")
+	if message := exportErrorMessage(upload); message != "the upload to the bucket did not succeed" {
+		t.Errorf("Expected a generic message, got %q", message)
+	}
+}
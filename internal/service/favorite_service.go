@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// FavoriteService manages users' favorite products
+type FavoriteService struct {
+	favoriteRepo *repository.FavoriteRepository
+	productRepo  domain.ProductRepository
+	authorizer   *ProductAuthorizer
+}
+
+// NewFavoriteService creates a new favorite service
+func NewFavoriteService(favoriteRepo *repository.FavoriteRepository, productRepo domain.ProductRepository, authorizer *ProductAuthorizer) *FavoriteService {
+	return &FavoriteService{
+		favoriteRepo: favoriteRepo,
+		productRepo:  productRepo,
+		authorizer:   authorizer,
+	}
+}
+
+// Add favorites a product the user can access
+func (s *FavoriteService) Add(ctx context.Context, userID, productID uuid.UUID) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, product, ProductActionFavorite); err != nil {
+		return err
+	}
+
+	favorite := &domain.Favorite{
+		UserID:    userID,
+		ProductID: productID,
+		CreatedAt: time.Now(),
+	}
+
+	return s.favoriteRepo.Add(ctx, favorite)
+}
+
+// Remove unfavorites a product
+func (s *FavoriteService) Remove(ctx context.Context, userID, productID uuid.UUID) error {
+	return s.favoriteRepo.Remove(ctx, userID, productID)
+}
+
+// List returns the user's favorite products. Favorites are not cached: other
+// users' products are checked to still be listed publicly on every read, and
+// only their public details are returned.
+func (s *FavoriteService) List(ctx context.Context, userID uuid.UUID) ([]domain.FavoriteProduct, error) {
+	products, err := s.favoriteRepo.GetProductsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites := make([]domain.FavoriteProduct, 0, len(products))
+	for i := range products {
+		product := &products[i]
+		switch {
+		case product.UserID == userID:
+			favorites = append(favorites, domain.FavoriteProduct{Product: product})
+		case product.IsListedPublicly():
+			favorites = append(favorites, domain.FavoriteProduct{Public: toPublicProduct(product, product.User.Slug)})
+		}
+	}
+
+	return favorites, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"products/internal/domain"
+)
+
+// geoLookupTimeout bounds a lookup of an IP address's location
+const geoLookupTimeout = 3 * time.Second
+
+// GeoLocator resolves the location of an IP address. The zero location means the
+// address could not be located.
+type GeoLocator interface {
+	Locate(ctx context.Context, ipAddress string) (domain.GeoLocation, error)
+}
+
+// HTTPGeoLocator locates IP addresses with a JSON lookup service, such as
+// ipapi.co, ip-api.com or ipinfo.io
+type HTTPGeoLocator struct {
+	urlTemplate string
+	client      *http.Client
+}
+
+// NewHTTPGeoLocator creates a locator that requests urlTemplate with {ip}
+// replaced by the address
+func NewHTTPGeoLocator(urlTemplate string) (*HTTPGeoLocator, error) {
+	if !strings.Contains(urlTemplate, "{ip}") {
+		return nil, fmt.Errorf("lookup URL %q has no {ip} placeholder", urlTemplate)
+	}
+	if parsed, err := url.Parse(strings.ReplaceAll(urlTemplate, "{ip}", "192.0.2.1")); err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("lookup URL %q is not an absolute HTTP URL", urlTemplate)
+	}
+	return &HTTPGeoLocator{
+		urlTemplate: urlTemplate,
+		client:      &http.Client{Timeout: geoLookupTimeout},
+	}, nil
+}
+
+// Locate looks up the country and city of the address. Private, loopback and
+// other non-public addresses are not looked up.
+func (l *HTTPGeoLocator) Locate(ctx context.Context, ipAddress string) (domain.GeoLocation, error) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return domain.GeoLocation{}, nil
+	}
+
+	lookupURL := strings.ReplaceAll(l.urlTemplate, "{ip}", url.PathEscape(ip.String()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
+	if err != nil {
+		return domain.GeoLocation{}, fmt.Errorf("failed to create location lookup: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return domain.GeoLocation{}, fmt.Errorf("failed to look up location: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.GeoLocation{}, fmt.Errorf("location lookup returned status %d", resp.StatusCode)
+	}
+
+	// The services name the country code differently
+	var result struct {
+		CountryCode      string `json:"country_code"`
+		CountryCodeCamel string `json:"countryCode"`
+		Country          string `json:"country"`
+		City             string `json:"city"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return domain.GeoLocation{}, fmt.Errorf("invalid location lookup response: %w", err)
+	}
+
+	for _, code := range []string{result.CountryCode, result.CountryCodeCamel, result.Country} {
+		if country := normalizeCountry(code); country != "" {
+			return domain.GeoLocation{Country: country, City: strings.TrimSpace(result.City)}, nil
+		}
+	}
+	return domain.GeoLocation{}, nil
+}
+
+// normalizeCountry returns an ISO 3166-1 alpha-2 country code in upper case, or
+// "" if code is not one
+func normalizeCountry(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 {
+		return ""
+	}
+	return code
+}
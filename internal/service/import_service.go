@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// ErrImportProfileNotFound is returned for import profiles that do not exist or belong to another user
+var ErrImportProfileNotFound = domain.NotFoundError(domain.CodeNotFound, "import profile not found")
+
+// ImportService imports products from CSV files using saved column mapping profiles
+type ImportService struct {
+	profileRepo    *repository.ImportProfileRepository
+	attributeRepo  *repository.AttributeDefinitionRepository
+	productService *ProductService
+}
+
+// NewImportService creates a new import service
+func NewImportService(profileRepo *repository.ImportProfileRepository, attributeRepo *repository.AttributeDefinitionRepository, productService *ProductService) *ImportService {
+	return &ImportService{
+		profileRepo:    profileRepo,
+		attributeRepo:  attributeRepo,
+		productService: productService,
+	}
+}
+
+// ListProfiles returns the user's import profiles
+func (s *ImportService) ListProfiles(ctx context.Context, userID uuid.UUID) ([]domain.ImportProfile, error) {
+	profiles, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profiles == nil {
+		profiles = []domain.ImportProfile{}
+	}
+	return profiles, nil
+}
+
+// CreateProfile saves a new import profile
+func (s *ImportService) CreateProfile(ctx context.Context, userID uuid.UUID, req domain.ImportProfileRequest) (*domain.ImportProfile, error) {
+	if err := s.checkProfile(ctx, userID, uuid.Nil, req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	profile := &domain.ImportProfile{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Mappings:  req.Mappings,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.profileRepo.Create(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// UpdateProfile replaces the name and mappings of one of the user's import profiles
+func (s *ImportService) UpdateProfile(ctx context.Context, userID, id uuid.UUID, req domain.ImportProfileRequest) (*domain.ImportProfile, error) {
+	profile, err := s.getProfile(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkProfile(ctx, userID, id, req); err != nil {
+		return nil, err
+	}
+
+	profile.Name = req.Name
+	profile.Mappings = req.Mappings
+	profile.UpdatedAt = time.Now()
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// DeleteProfile removes one of the user's import profiles
+func (s *ImportService) DeleteProfile(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getProfile(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.profileRepo.Delete(ctx, id)
+}
+
+// ImportRowValidator sanitizes and validates the product of an imported row as a
+// create request, returning the failure of the row
+type ImportRowValidator func(req *domain.CreateProductRequest) error
+
+// Import creates products from a CSV file. Columns are mapped by the given profile,
+// or by header names matching product fields when profileID is nil. Each row is
+// sanitized and validated by validateRow like a create request; rows that fail
+// are reported by line number and skipped, and the remaining rows are created together.
+func (s *ImportService) Import(ctx context.Context, userID uuid.UUID, profileID *uuid.UUID, data io.Reader, validateRow ImportRowValidator) (*domain.BulkResult, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, domain.NewError(domain.CodeValidationFailed, "CSV file is empty")
+		}
+		return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("invalid CSV header: %v", err))
+	}
+
+	// Spreadsheet exports often start with a UTF-8 byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	mappings, err := s.resolveMappings(ctx, userID, profileID, header)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[column] = i
+	}
+	for _, mapping := range mappings {
+		if _, ok := columns[mapping.Column]; !ok {
+			return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("CSV is missing mapped column %q", mapping.Column))
+		}
+	}
+
+	definitions, err := s.attributeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+
+	result := domain.NewBulkResult()
+	var products []domain.Product
+	var rows []int
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if row-1 > domain.MaxImportRows {
+			return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("CSV has more than %d rows", domain.MaxImportRows))
+		}
+		if err != nil {
+			result.Fail(row, nil, domain.NewError(domain.CodeValidationFailed, err.Error()))
+			continue
+		}
+
+		product, err := toImportedProduct(columns, record, mappings, definitions, validateRow)
+		if err != nil {
+			result.Fail(row, nil, err)
+			continue
+		}
+		products = append(products, *product)
+		rows = append(rows, row)
+	}
+
+	if err := s.productService.CreateBatch(ctx, products, userID); err != nil {
+		return nil, err
+	}
+
+	for i := range products {
+		result.Succeed(rows[i], products[i].ID)
+	}
+	result.Sort()
+	return result, nil
+}
+
+// resolveMappings returns the mappings of the requested profile or those derived from the header
+func (s *ImportService) resolveMappings(ctx context.Context, userID uuid.UUID, profileID *uuid.UUID, header []string) ([]domain.ColumnMapping, error) {
+	if profileID != nil {
+		profile, err := s.getProfile(ctx, userID, *profileID)
+		if err != nil {
+			return nil, err
+		}
+		return profile.Mappings, nil
+	}
+
+	mappings := domain.DefaultColumnMappings(header)
+	if err := domain.ValidateColumnMappings(mappings); err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("CSV header cannot be mapped without a profile: %v", err))
+	}
+	return mappings, nil
+}
+
+// getProfile loads an import profile owned by the user
+func (s *ImportService) getProfile(ctx context.Context, userID, id uuid.UUID) (*domain.ImportProfile, error) {
+	profile, err := s.profileRepo.GetByID(ctx, id)
+	if err != nil || profile.UserID != userID {
+		return nil, ErrImportProfileNotFound
+	}
+	return profile, nil
+}
+
+// checkProfile validates a profile request and that its name is not used by another profile
+func (s *ImportService) checkProfile(ctx context.Context, userID, id uuid.UUID, req domain.ImportProfileRequest) error {
+	if err := domain.ValidateColumnMappings(req.Mappings); err != nil {
+		return domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	existing, err := s.profileRepo.GetByName(ctx, userID, req.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return domain.ConflictError(domain.CodeConflict, "an import profile with this name already exists")
+	}
+	return nil
+}
+
+// toImportedProduct maps a CSV record to a product, sanitized and validated by
+// validateRow, and validates its attributes against the user's definitions
+func toImportedProduct(columns map[string]int, record []string, mappings []domain.ColumnMapping, definitions []domain.AttributeDefinition, validateRow ImportRowValidator) (*domain.Product, error) {
+	row, err := domain.MapImportRow(columns, record, mappings)
+	if err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	attributes, err := domain.CoerceAttributeFilter(row.Attributes, definitions)
+	if err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	req := &domain.CreateProductRequest{
+		Name:        row.Name,
+		Description: row.Description,
+		Price:       row.Price,
+		Stock:       row.Stock,
+		Attributes:  attributes,
+	}
+	if err := validateRow(req); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateAttributes(req.Attributes, definitions); err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	return &domain.Product{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Stock:       req.Stock,
+		Attributes:  req.Attributes,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"products/internal/domain"
+)
+
+func TestToImportedProduct_ValidatesRow(t *testing.T) {
+	columns := map[string]int{"name": 0, "price": 1, "stock": 2}
+	mappings := domain.DefaultColumnMappings([]string{"name", "price", "stock"})
+	rejected := &domain.ValidationError{Message: "invalid", Fields: []domain.FieldError{{Field: "Name"}}}
+
+	validateRow := func(req *domain.CreateProductRequest) error {
+		if len(req.Name) > 10 {
+			return rejected
+		}
+		req.Name = strings.TrimSuffix(req.Name, "!")
+		return nil
+	}
+
+	product, err := toImportedProduct(columns, []string{"Lamp!", "19.99", "3"}, mappings, nil, validateRow)
+	if err != nil {
+		t.Fatalf("Expected the row to be imported, got %v", err)
+	}
+	if product.Name != "Lamp" || product.Price != 19.99 || product.Stock != 3 {
+		t.Errorf("Expected the validated row, got %+v", product)
+	}
+
+	if _, err := toImportedProduct(columns, []string{"Very long lamp", "19.99", "3"}, mappings, nil, validateRow); !errors.Is(err, rejected) {
+		t.Errorf("Expected the validator's error, got %v", err)
+	}
+
+	if _, err := toImportedProduct(columns, []string{"Lamp", "free", "3"}, mappings, nil, validateRow); domain.ErrorCode(err) != domain.CodeValidationFailed {
+		t.Errorf("Expected a validation error for an unparsable price, got %v", err)
+	}
+}
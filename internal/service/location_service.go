@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// LocationService manages a user's locations and the stock of products kept at them.
+// A product's stock stays the total across locations: changing the stock at a
+// location changes the total in the same transaction.
+type LocationService struct {
+	locationRepo   *repository.LocationRepository
+	productService *ProductService
+}
+
+// NewLocationService creates a new location service
+func NewLocationService(locationRepo *repository.LocationRepository, productService *ProductService) *LocationService {
+	return &LocationService{
+		locationRepo:   locationRepo,
+		productService: productService,
+	}
+}
+
+// List returns the user's locations by name
+func (s *LocationService) List(ctx context.Context, userID uuid.UUID) ([]domain.Location, error) {
+	locations, err := s.locationRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if locations == nil {
+		locations = []domain.Location{}
+	}
+	return locations, nil
+}
+
+// Create adds a location for the user
+func (s *LocationService) Create(ctx context.Context, userID uuid.UUID, req domain.LocationRequest) (*domain.Location, error) {
+	location := &domain.Location{
+		UserID:  userID,
+		Name:    req.Name,
+		Address: req.Address,
+	}
+	if err := s.locationRepo.Create(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// Get returns one of the user's locations
+func (s *LocationService) Get(ctx context.Context, userID, id uuid.UUID) (*domain.Location, error) {
+	location, err := s.locationRepo.GetByID(ctx, id)
+	if err != nil || location.UserID != userID {
+		return nil, domain.ErrLocationNotFound
+	}
+	return location, nil
+}
+
+// Update replaces the details of one of the user's locations
+func (s *LocationService) Update(ctx context.Context, userID, id uuid.UUID, req domain.LocationRequest) (*domain.Location, error) {
+	location, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	location.Name = req.Name
+	location.Address = req.Address
+	location.UpdatedAt = time.Now()
+	if err := s.locationRepo.Update(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// Delete removes one of the user's locations unless it still holds stock
+func (s *LocationService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.locationRepo.DeleteUnlessStocked(ctx, id)
+}
+
+// Stats returns statistics of the stock kept at one of the user's locations
+func (s *LocationService) Stats(ctx context.Context, userID, id uuid.UUID) (map[string]interface{}, error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.locationRepo.GetStats(ctx, userID, id)
+}
+
+// ProductLocations returns where the stock of a product the user may view is kept
+func (s *LocationService) ProductLocations(ctx context.Context, productID, userID uuid.UUID) (*domain.ProductLocationsResponse, error) {
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := s.locationRepo.GetProductLevels(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewProductLocationsResponse(product, levels), nil
+}
+
+// AdjustStock adds delta to the stock at one of the owner's locations of a product
+// the user may adjust, and to the product's stock
+func (s *LocationService) AdjustStock(ctx context.Context, productID, userID, locationID uuid.UUID, delta int) (*domain.LocationStockResponse, error) {
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionAdjustStock)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, locationStock, err := s.locationRepo.AdjustStock(ctx, productID, locationID, delta)
+	if err != nil {
+		return nil, err
+	}
+
+	s.productService.StockChanged(ctx, product, updated.Stock)
+
+	return &domain.LocationStockResponse{
+		ProductID:      productID,
+		LocationID:     locationID,
+		LocationStock:  locationStock,
+		Stock:          updated.Stock,
+		ReservedStock:  updated.ReservedStock,
+		AvailableStock: updated.AvailableStock(),
+	}, nil
+}
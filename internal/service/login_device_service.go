@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// deviceCodeTTL is how long a new device verification code is valid
+const deviceCodeTTL = 15 * time.Minute
+
+// Login device errors
+var (
+	ErrLoginDeviceNotFound        = domain.NotFoundError(domain.CodeNotFound, "device not found")
+	ErrDeviceVerificationRequired = domain.NewError(domain.CodeDeviceVerification, "logging in from a new device requires the code emailed to you; send it as device_code")
+	ErrInvalidDeviceCode          = domain.NewError(domain.CodeDeviceVerification, "the device code is invalid or expired; log in again without it to receive a new code")
+)
+
+// LoginDeviceService recognizes the devices users log in from and lets users
+// name, trust and forget them
+type LoginDeviceService struct {
+	deviceRepo     *repository.LoginDeviceRepository
+	sessionService *SessionService
+	cacheService   domain.Cache
+	templates      *EmailTemplateService
+	mailer         Mailer
+
+	// requireVerification makes logins from untrusted devices confirm a code
+	// emailed to the user
+	requireVerification bool
+}
+
+// NewLoginDeviceService creates a new login device service
+func NewLoginDeviceService(deviceRepo *repository.LoginDeviceRepository, sessionService *SessionService, cacheService domain.Cache, templates *EmailTemplateService, mailer Mailer, requireVerification bool) *LoginDeviceService {
+	return &LoginDeviceService{
+		deviceRepo:          deviceRepo,
+		sessionService:      sessionService,
+		cacheService:        cacheService,
+		templates:           templates,
+		mailer:              mailer,
+		requireVerification: requireVerification,
+	}
+}
+
+// Authorize records the device a user whose password was verified logs in from.
+// A user's first device is trusted; later new devices are announced by email or,
+// when verification is required, must confirm the code emailed to the user until
+// they are trusted. Confirming the code trusts the device.
+func (s *LoginDeviceService) Authorize(ctx context.Context, user *domain.User, deviceID, code, ipAddress, userAgent string) (*domain.LoginDevice, error) {
+	fingerprint := domain.DeviceFingerprint(deviceID, userAgent)
+	device, err := s.deviceRepo.GetByFingerprint(ctx, user.ID, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device: %w", err)
+	}
+
+	now := time.Now()
+	if device == nil {
+		device, err = s.register(ctx, user, fingerprint, ipAddress, userAgent, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.requireVerification && !device.Trusted {
+		if code == "" {
+			if err := s.sendCode(ctx, user, device, ipAddress); err != nil {
+				return nil, err
+			}
+			return nil, ErrDeviceVerificationRequired
+		}
+		if !s.checkCode(ctx, device, code) {
+			return nil, ErrInvalidDeviceCode
+		}
+		device.Trusted = true
+	}
+
+	device.UserAgent = userAgent
+	device.LastIPAddress = ipAddress
+	device.LastSeenAt = now
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		return nil, fmt.Errorf("failed to update device: %w", err)
+	}
+	return device, nil
+}
+
+// register records a device the user never logged in from before
+func (s *LoginDeviceService) register(ctx context.Context, user *domain.User, fingerprint, ipAddress, userAgent string, now time.Time) (*domain.LoginDevice, error) {
+	known, err := s.deviceRepo.CountByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count devices: %w", err)
+	}
+
+	device := &domain.LoginDevice{
+		ID:            uuid.New(),
+		UserID:        user.ID,
+		Fingerprint:   fingerprint,
+		Name:          domain.DefaultDeviceName(userAgent),
+		Trusted:       known == 0,
+		UserAgent:     userAgent,
+		LastIPAddress: ipAddress,
+		LastSeenAt:    now,
+		CreatedAt:     now,
+	}
+	created, err := s.deviceRepo.CreateIfNotExists(ctx, device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record device: %w", err)
+	}
+	if !created {
+		// The same device logged in concurrently
+		return s.deviceRepo.GetByFingerprint(ctx, user.ID, fingerprint)
+	}
+
+	// With verification required, the code email announces the device instead
+	if known > 0 && !s.requireVerification {
+		s.notify(ctx, user, device)
+	}
+	return device, nil
+}
+
+// notify emails the user about a login from a new device
+func (s *LoginDeviceService) notify(ctx context.Context, user *domain.User, device *domain.LoginDevice) {
+	email, err := s.templates.Render(ctx, domain.EmailTemplateNewDevice, map[string]string{
+		"name":       user.Name,
+		"email":      user.Email,
+		"device":     device.Name,
+		"ip_address": device.LastIPAddress,
+		"time":       device.CreatedAt.UTC().Format("2006-01-02 15:04 MST"),
+	})
+	if err == nil {
+		err = s.mailer.Send(ctx, user.Email, email.Subject, email.Body)
+	}
+	if err != nil {
+		log.Printf("Failed to notify user %s of new device %s: %v", user.ID, device.ID, err)
+	}
+}
+
+// sendCode emails the user a single-use code confirming a login from the device
+func (s *LoginDeviceService) sendCode(ctx context.Context, user *domain.User, device *domain.LoginDevice, ipAddress string) error {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	if err := s.cacheService.Set(ctx, deviceCodeCacheKey(device.ID), hashDeviceCode(code), deviceCodeTTL); err != nil {
+		return fmt.Errorf("failed to store code: %w", err)
+	}
+
+	email, err := s.templates.Render(ctx, domain.EmailTemplateDeviceCode, map[string]string{
+		"name":       user.Name,
+		"email":      user.Email,
+		"device":     device.Name,
+		"ip_address": ipAddress,
+		"code":       code,
+		"expires_in": "15 minutes",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+	return s.mailer.Send(ctx, user.Email, email.Subject, email.Body)
+}
+
+// checkCode reports whether code is the one emailed for the device. A code can
+// be tried once.
+func (s *LoginDeviceService) checkCode(ctx context.Context, device *domain.LoginDevice, code string) bool {
+	key := deviceCodeCacheKey(device.ID)
+	var expected string
+	if err := s.cacheService.Get(ctx, key, &expected); err != nil {
+		return false
+	}
+	s.cacheService.Delete(ctx, key)
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(hashDeviceCode(code))) == 1
+}
+
+// List returns the user's devices, marking the one the session logged in from
+func (s *LoginDeviceService) List(ctx context.Context, userID uuid.UUID, sessionID string) ([]domain.LoginDeviceResponse, error) {
+	devices, err := s.deviceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var currentDeviceID string
+	if session, err := s.sessionService.GetSession(ctx, sessionID); err == nil {
+		currentDeviceID = session.DeviceID
+	}
+
+	response := make([]domain.LoginDeviceResponse, 0, len(devices))
+	for _, device := range devices {
+		response = append(response, domain.LoginDeviceResponse{
+			LoginDevice: device,
+			Current:     device.ID.String() == currentDeviceID,
+		})
+	}
+	return response, nil
+}
+
+// Update renames one of the user's devices or changes whether it is trusted
+func (s *LoginDeviceService) Update(ctx context.Context, userID, id uuid.UUID, req domain.LoginDeviceRequest) (*domain.LoginDevice, error) {
+	device, err := s.getDevice(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		device.Name = *req.Name
+	}
+	if req.Trusted != nil {
+		device.Trusted = *req.Trusted
+	}
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// Delete forgets one of the user's devices and logs out its sessions; the next
+// login from it counts as a new device
+func (s *LoginDeviceService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getDevice(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.deviceRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.sessionService.DeleteDeviceSessions(ctx, userID.String(), id.String())
+}
+
+// getDevice loads a login device owned by the user
+func (s *LoginDeviceService) getDevice(ctx context.Context, userID, id uuid.UUID) (*domain.LoginDevice, error) {
+	device, err := s.deviceRepo.GetByID(ctx, id)
+	if err != nil || device.UserID != userID {
+		return nil, ErrLoginDeviceNotFound
+	}
+	return device, nil
+}
+
+// deviceCodeCacheKey returns the cache key of a device's verification code
+func deviceCodeCacheKey(deviceID uuid.UUID) string {
+	return fmt.Sprintf("device_code:%s", deviceID)
+}
+
+// hashDeviceCode hashes a verification code so that cache contents cannot be replayed
+func hashDeviceCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
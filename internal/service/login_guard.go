@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"products/internal/domain"
+)
+
+// Login guard defaults
+const (
+	DefaultCaptchaThreshold = 5
+	DefaultCaptchaWindow    = 15 * time.Minute
+)
+
+// CAPTCHA errors of the login flow
+var (
+	ErrCaptchaRequired = domain.NewError(domain.CodeCaptchaRequired, "too many failed logins from this address; solve a CAPTCHA and send its token as captcha_token")
+	ErrCaptchaInvalid  = domain.NewError(domain.CodeCaptchaRequired, "the CAPTCHA token could not be verified")
+)
+
+// LoginGuard requires a solved CAPTCHA for logins from an IP address once its
+// failed attempts within the window reach the threshold
+type LoginGuard struct {
+	cacheService domain.Cache
+	verifier     CaptchaVerifier
+	threshold    int64
+	window       time.Duration
+}
+
+// NewLoginGuard creates a login guard verifying CAPTCHAs with verifier
+func NewLoginGuard(cacheService domain.Cache, verifier CaptchaVerifier, threshold int64, window time.Duration) *LoginGuard {
+	return &LoginGuard{
+		cacheService: cacheService,
+		verifier:     verifier,
+		threshold:    threshold,
+		window:       window,
+	}
+}
+
+// Check returns ErrCaptchaRequired when a CAPTCHA is due for the address and no
+// token was given, and ErrCaptchaInvalid when the token does not verify. While
+// the cache is unavailable failures are not known and no CAPTCHA is required.
+func (g *LoginGuard) Check(ctx context.Context, ipAddress, captchaToken string) error {
+	if g == nil {
+		return nil
+	}
+
+	var failures int64
+	if err := g.cacheService.Get(ctx, g.key(ipAddress), &failures); err != nil || failures < g.threshold {
+		return nil
+	}
+	if captchaToken == "" {
+		return ErrCaptchaRequired
+	}
+
+	valid, err := g.verifier.Verify(ctx, captchaToken, ipAddress)
+	if err != nil {
+		return fmt.Errorf("failed to verify CAPTCHA: %w", err)
+	}
+	if !valid {
+		return ErrCaptchaInvalid
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login from the address. Failures expire with the
+// window that started at the first one.
+func (g *LoginGuard) RecordFailure(ctx context.Context, ipAddress string) {
+	if g == nil {
+		return
+	}
+
+	key := g.key(ipAddress)
+	count, err := g.cacheService.Incr(ctx, key)
+	if err == nil && count == 1 {
+		g.cacheService.Expire(ctx, key, g.window)
+	}
+}
+
+// key returns the cache key counting failed logins from an address
+func (g *LoginGuard) key(ipAddress string) string {
+	return fmt.Sprintf("login_failures:%s", ipAddress)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// DefaultLoginHistoryBufferSize is how many logins may wait to be recorded
+const DefaultLoginHistoryBufferSize = 256
+
+// LoginAttempt is a successful login queued for the login history
+type LoginAttempt struct {
+	User      domain.User
+	DeviceID  *uuid.UUID
+	IPAddress string
+	UserAgent string
+	Time      time.Time
+}
+
+// LoginHistoryService records users' logins asynchronously, locating each login's
+// IP address and alerting users by email and webhook to logins from countries
+// they never logged in from before
+type LoginHistoryService struct {
+	loginRepo *repository.LoginEventRepository
+	locator   GeoLocator
+	events    *EventService
+	templates *EmailTemplateService
+	mailer    Mailer
+	attempts  chan LoginAttempt
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewLoginHistoryService creates a new login history service; without a locator
+// logins are recorded without their location and never flagged
+func NewLoginHistoryService(loginRepo *repository.LoginEventRepository, locator GeoLocator, events *EventService, templates *EmailTemplateService, mailer Mailer, bufferSize int) *LoginHistoryService {
+	if bufferSize <= 0 {
+		bufferSize = DefaultLoginHistoryBufferSize
+	}
+
+	return &LoginHistoryService{
+		loginRepo: loginRepo,
+		locator:   locator,
+		events:    events,
+		templates: templates,
+		mailer:    mailer,
+		attempts:  make(chan LoginAttempt, bufferSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// Record queues a login without blocking the request; logins are dropped when the
+// buffer is full
+func (s *LoginHistoryService) Record(attempt LoginAttempt) {
+	if s == nil {
+		return
+	}
+
+	select {
+	case s.attempts <- attempt:
+	default:
+		log.Printf("Login history buffer full, dropping login of user %s", attempt.User.ID)
+	}
+}
+
+// Start records queued logins until Close is called
+func (s *LoginHistoryService) Start() {
+	defer close(s.done)
+
+	for attempt := range s.attempts {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := s.process(ctx, attempt); err != nil {
+			log.Printf("Failed to record login of user %s: %v", attempt.User.ID, err)
+		}
+		cancel()
+	}
+}
+
+// Close stops accepting logins and waits for queued logins to be recorded
+func (s *LoginHistoryService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.attempts)
+	})
+	<-s.done
+}
+
+// process locates a login, records it and alerts the user when it comes from a
+// new country
+func (s *LoginHistoryService) process(ctx context.Context, attempt LoginAttempt) error {
+	login := &domain.LoginEvent{
+		ID:        uuid.New(),
+		UserID:    attempt.User.ID,
+		DeviceID:  attempt.DeviceID,
+		IPAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		CreatedAt: attempt.Time,
+	}
+
+	if s.locator != nil {
+		location, err := s.locator.Locate(ctx, attempt.IPAddress)
+		if err != nil {
+			// The login is still recorded, without its location
+			log.Printf("Failed to locate login of user %s: %v", attempt.User.ID, err)
+		}
+		login.Country = location.Country
+		login.City = location.City
+	}
+
+	if login.Country != "" {
+		countries, err := s.loginRepo.GetCountries(ctx, login.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to load login countries: %w", err)
+		}
+		login.NewCountry = isNewCountry(countries, login.Country)
+	}
+
+	if err := s.loginRepo.Create(ctx, login); err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+
+	if login.NewCountry {
+		s.alert(ctx, &attempt.User, login)
+	}
+	return nil
+}
+
+// isNewCountry reports whether a login from country is the first from it after
+// logins from other countries. The first located login sets the baseline.
+func isNewCountry(known []string, country string) bool {
+	return len(known) > 0 && !slices.Contains(known, country)
+}
+
+// alert notifies the user of a login from a new country by email and publishes
+// it to the user's webhooks
+func (s *LoginHistoryService) alert(ctx context.Context, user *domain.User, login *domain.LoginEvent) {
+	email, err := s.templates.Render(ctx, domain.EmailTemplateNewLocation, map[string]string{
+		"name":       user.Name,
+		"email":      user.Email,
+		"country":    login.Country,
+		"city":       login.City,
+		"ip_address": login.IPAddress,
+		"user_agent": login.UserAgent,
+		"time":       login.CreatedAt.UTC().Format("2006-01-02 15:04 MST"),
+	})
+	if err == nil {
+		err = s.mailer.Send(ctx, user.Email, email.Subject, email.Body)
+	}
+	if err != nil {
+		log.Printf("Failed to notify user %s of login from %s: %v", user.ID, login.Country, err)
+	}
+
+	if s.events == nil {
+		return
+	}
+	event, err := domain.NewLoginAnomalyEvent(login)
+	if err == nil {
+		err = s.events.Record(ctx, event)
+	}
+	if err != nil {
+		log.Printf("Failed to record login anomaly of user %s: %v", user.ID, err)
+	}
+}
+
+// History returns a page of the user's logins, most recent first
+func (s *LoginHistoryService) History(ctx context.Context, userID uuid.UUID, pagination domain.Pagination) (*domain.LoginHistoryResponse, error) {
+	return s.loginRepo.GetByUserID(ctx, userID, pagination)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPGeoLocator_Locate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/203.0.113.7/json":
+			w.Write([]byte(`{"ip": "203.0.113.7", "country_code": "de", "city": "Berlin"}`))
+		case "/198.51.100.4/json":
+			w.Write([]byte(`{"query": "198.51.100.4", "country": "France", "countryCode": "FR", "city": "Paris"}`))
+		default:
+			t.Errorf("Unexpected lookup of %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	locator, err := NewHTTPGeoLocator(server.URL + "/{ip}/json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	location, err := locator.Locate(context.Background(), "203.0.113.7")
+	if err != nil || location.Country != "DE" || location.City != "Berlin" {
+		t.Errorf("Expected DE/Berlin, got %+v, %v", location, err)
+	}
+	location, err = locator.Locate(context.Background(), "198.51.100.4")
+	if err != nil || location.Country != "FR" || location.City != "Paris" {
+		t.Errorf("Expected FR/Paris, got %+v, %v", location, err)
+	}
+
+	for _, address := range []string{"127.0.0.1", "10.0.0.8", "::1", "not an address"} {
+		if location, err := locator.Locate(context.Background(), address); err != nil || location.Country != "" {
+			t.Errorf("Expected %q not to be looked up, got %+v, %v", address, location, err)
+		}
+	}
+}
+
+func TestNewHTTPGeoLocator_Invalid(t *testing.T) {
+	for _, urlTemplate := range []string{"https://ipapi.co/json/", "ipapi.co/{ip}/json/", "ftp://ipapi.co/{ip}"} {
+		if _, err := NewHTTPGeoLocator(urlTemplate); err == nil {
+			t.Errorf("Expected %q to be rejected", urlTemplate)
+		}
+	}
+}
+
+func TestIsNewCountry(t *testing.T) {
+	tests := []struct {
+		name    string
+		known   []string
+		country string
+		want    bool
+	}{
+		{"first located login", nil, "DE", false},
+		{"known country", []string{"DE", "FR"}, "FR", false},
+		{"new country", []string{"DE"}, "US", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNewCountry(tt.known, tt.country); got != tt.want {
+				t.Errorf("isNewCountry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"log"
+)
+
+// Mailer sends transactional emails
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogMailer writes emails to the application log instead of delivering them
+type LogMailer struct{}
+
+// NewLogMailer creates a mailer suitable for development
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the email
+func (m *LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("Email to %s: %s\n%s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,46 @@
+package memorycache
+
+import "context"
+
+// subscriberBuffer is the number of messages a slow subscriber may fall behind by
+// before further messages to it are dropped
+const subscriberBuffer = 64
+
+// Publish sends message to the subscribers of channel without waiting for them
+func (c *Cache) Publish(ctx context.Context, channel string, message []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for subscriber := range c.subscribers[channel] {
+		select {
+		case subscriber <- message:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the messages published on channel until ctx is done
+func (c *Cache) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	messages := make(chan []byte, subscriberBuffer)
+
+	c.mu.Lock()
+	if c.subscribers[channel] == nil {
+		c.subscribers[channel] = make(map[chan []byte]struct{})
+	}
+	c.subscribers[channel][messages] = struct{}{}
+	c.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		c.mu.Lock()
+		delete(c.subscribers[channel], messages)
+		if len(c.subscribers[channel]) == 0 {
+			delete(c.subscribers, channel)
+		}
+		c.mu.Unlock()
+		close(messages)
+	}()
+	return messages, nil
+}
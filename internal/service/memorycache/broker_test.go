@@ -0,0 +1,38 @@
+package memorycache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCache_PublishSubscribe(t *testing.T) {
+	cache := New()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	messages, err := cache.Subscribe(ctx, "updates")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	cache.Publish(ctx, "other", []byte("ignored"))
+	cache.Publish(ctx, "updates", []byte("hello"))
+
+	select {
+	case message := <-messages:
+		if string(message) != "hello" {
+			t.Errorf("Expected hello, got %q", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the published message")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-messages:
+		if ok {
+			t.Error("Expected no further messages")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the channel closed once the context is done")
+	}
+}
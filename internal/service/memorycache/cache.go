@@ -0,0 +1,385 @@
+// Package memorycache implements domain.Cache in process memory with the semantics
+// of the Redis commands CacheService uses, for tests and demos that run without
+// Redis. Entries are not shared between processes.
+package memorycache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"products/internal/domain"
+)
+
+// ErrMiss is returned when reading a key that is not set
+var ErrMiss = errors.New("cache miss")
+
+// entry is a cached value: a string, hash, set or sorted set
+type entry struct {
+	value   []byte
+	hash    map[string]int64
+	set     map[string]bool
+	scores  map[string]float64
+	expires time.Time
+}
+
+// Cache is an in-memory domain.Cache, and a domain.Broker within the process
+type Cache struct {
+	mu          sync.Mutex
+	entries     map[string]*entry
+	subscribers map[string]map[chan []byte]struct{}
+	now         func() time.Time
+}
+
+var (
+	_ domain.Cache  = (*Cache)(nil)
+	_ domain.Broker = (*Cache)(nil)
+)
+
+// New creates an empty cache
+func New() *Cache {
+	return &Cache{entries: make(map[string]*entry), subscribers: make(map[string]map[chan []byte]struct{}), now: time.Now}
+}
+
+// Available reports whether cache operations are attempted, which they always are
+func (c *Cache) Available() bool {
+	return true
+}
+
+// Get decodes the JSON value stored under key into dest
+func (c *Cache) Get(ctx context.Context, key string, dest interface{}) error {
+	value, err := c.GetRaw(ctx, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value, dest)
+}
+
+// Set stores value as JSON under key with expiration
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	return c.SetRaw(ctx, key, data, expiration)
+}
+
+// SetNX stores value as JSON under key unless it is set
+func (c *Cache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lookup(key) != nil {
+		return false, nil
+	}
+	c.entries[key] = &entry{value: data, expires: c.expiry(expiration)}
+	return true, nil
+}
+
+// GetRaw retrieves the raw JSON stored under key
+func (c *Cache) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.lookup(key)
+	if e == nil || e.value == nil {
+		return nil, fmt.Errorf("failed to get value: %w", ErrMiss)
+	}
+	return append([]byte(nil), e.value...), nil
+}
+
+// SetRaw stores already-encoded JSON under key with expiration
+func (c *Cache) SetRaw(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = &entry{value: append([]byte(nil), value...), expires: c.expiry(expiration)}
+	return nil
+}
+
+// Exists checks if a key is set
+func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lookup(key) != nil, nil
+}
+
+// Expire sets the expiration of a key
+func (c *Cache) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e := c.lookup(key); e != nil {
+		e.expires = c.expiry(expiration)
+	}
+	return nil
+}
+
+// Delete removes a key
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	return nil
+}
+
+// Keys returns the keys matching a Redis glob pattern
+func (c *Cache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	matcher, err := compilePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := []string{}
+	for key := range c.entries {
+		if c.lookup(key) != nil && matcher.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// DeletePattern removes the keys matching a Redis glob pattern
+func (c *Cache) DeletePattern(ctx context.Context, pattern string) error {
+	keys, err := c.Keys(ctx, pattern)
+	if err != nil {
+		return fmt.Errorf("failed to get keys: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// Incr increments the counter under key, starting from zero
+func (c *Cache) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.lookup(key)
+	if e == nil {
+		e = &entry{value: []byte("0")}
+		c.entries[key] = e
+	}
+	if e.value == nil {
+		return 0, fmt.Errorf("%s does not hold a counter", key)
+	}
+	count, err := strconv.ParseInt(string(e.value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s does not hold a counter", key)
+	}
+	count++
+	e.value = []byte(strconv.FormatInt(count, 10))
+	return count, nil
+}
+
+// IncrHash adds to counters held in the fields of a hash and adds member to the
+// set under setKey, setting the expiration of both
+func (c *Cache) IncrHash(ctx context.Context, key string, increments map[string]int64, setKey, member string, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hash := c.lookup(key)
+	if hash == nil {
+		hash = &entry{hash: make(map[string]int64)}
+		c.entries[key] = hash
+	}
+	if hash.hash == nil {
+		return fmt.Errorf("%s does not hold a hash", key)
+	}
+	for field, increment := range increments {
+		hash.hash[field] += increment
+	}
+	hash.expires = c.expiry(expiration)
+
+	set := c.lookup(setKey)
+	if set == nil {
+		set = &entry{set: make(map[string]bool)}
+		c.entries[setKey] = set
+	}
+	if set.set == nil {
+		return fmt.Errorf("%s does not hold a set", setKey)
+	}
+	set.set[member] = true
+	set.expires = c.expiry(expiration)
+	return nil
+}
+
+// GetHashInts retrieves the integer fields of a hash; a missing hash has none
+func (c *Cache) GetHashInts(ctx context.Context, key string) (map[string]int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ints := make(map[string]int64)
+	if e := c.lookup(key); e != nil {
+		for field, value := range e.hash {
+			ints[field] = value
+		}
+	}
+	return ints, nil
+}
+
+// SetMembers retrieves the members of a set
+func (c *Cache) SetMembers(ctx context.Context, key string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	members := []string{}
+	if e := c.lookup(key); e != nil {
+		for member := range e.set {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// SetScheduled stores value as JSON under key with expiration and schedules member
+// in the sorted set under setKey at the given time
+func (c *Cache) SetScheduled(ctx context.Context, key string, value interface{}, expiration time.Duration, setKey, member string, at time.Time) error {
+	if err := c.Set(ctx, key, value, expiration); err != nil {
+		return err
+	}
+	return c.Schedule(ctx, setKey, member, at)
+}
+
+// Schedule adds member to the sorted set under setKey, due at the given time
+func (c *Cache) Schedule(ctx context.Context, setKey, member string, at time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.lookup(setKey)
+	if e == nil {
+		e = &entry{scores: make(map[string]float64)}
+		c.entries[setKey] = e
+	}
+	if e.scores == nil {
+		return fmt.Errorf("%s does not hold a sorted set", setKey)
+	}
+	e.scores[member] = float64(at.Unix())
+	return nil
+}
+
+// Unschedule removes member from the sorted set under setKey and reports whether it
+// was there
+func (c *Cache) Unschedule(ctx context.Context, setKey, member string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.lookup(setKey)
+	if e == nil {
+		return false, nil
+	}
+	_, ok := e.scores[member]
+	delete(e.scores, member)
+	return ok, nil
+}
+
+// DueMembers retrieves up to limit members of the sorted set under setKey that are
+// due at or before now, earliest first
+func (c *Cache) DueMembers(ctx context.Context, setKey string, now time.Time, limit int64) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	members := []string{}
+	e := c.lookup(setKey)
+	if e == nil {
+		return members, nil
+	}
+	for member, score := range e.scores {
+		if score <= float64(now.Unix()) {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if e.scores[members[i]] != e.scores[members[j]] {
+			return e.scores[members[i]] < e.scores[members[j]]
+		}
+		return members[i] < members[j]
+	})
+	if limit > 0 && int64(len(members)) > limit {
+		members = members[:limit]
+	}
+	return members, nil
+}
+
+// lookup returns the entry under key, dropping it once expired; c.mu must be held
+func (c *Cache) lookup(key string) *entry {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return e
+}
+
+// expiry returns when an entry set now with expiration expires; zero never expires
+func (c *Cache) expiry(expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return c.now().Add(expiration)
+}
+
+// compilePattern compiles a Redis glob pattern, supporting *, ?, [...] and \ escapes
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	var expression strings.Builder
+	expression.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch char := pattern[i]; char {
+		case '*':
+			expression.WriteString(".*")
+		case '?':
+			expression.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				expression.WriteString(regexp.QuoteMeta(pattern[i:]))
+				i = len(pattern)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + regexp.QuoteMeta(class[1:])
+			} else {
+				class = regexp.QuoteMeta(class)
+			}
+			expression.WriteString("[" + class + "]")
+			i += end
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			expression.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	expression.WriteString("$")
+	return regexp.Compile(expression.String())
+}
@@ -0,0 +1,136 @@
+package memorycache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_GetSet(t *testing.T) {
+	cache := New()
+	ctx := context.Background()
+
+	var value map[string]int
+	if err := cache.Get(ctx, "missing", &value); !errors.Is(err, ErrMiss) {
+		t.Fatalf("Expected ErrMiss, got %v", err)
+	}
+
+	if err := cache.Set(ctx, "key", map[string]int{"a": 1}, 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := cache.Get(ctx, "key", &value); err != nil || value["a"] != 1 {
+		t.Errorf("Expected the stored value, got %v, %v", value, err)
+	}
+
+	if acquired, _ := cache.SetNX(ctx, "key", "other", 0); acquired {
+		t.Error("Expected SetNX to keep an existing key")
+	}
+}
+
+func TestCache_Expiration(t *testing.T) {
+	cache := New()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	cache.Set(ctx, "key", "value", time.Minute)
+	if exists, _ := cache.Exists(ctx, "key"); !exists {
+		t.Fatal("Expected the key before it expires")
+	}
+
+	now = now.Add(time.Minute)
+	if exists, _ := cache.Exists(ctx, "key"); exists {
+		t.Error("Expected the key to expire")
+	}
+}
+
+func TestCache_DeletePattern(t *testing.T) {
+	cache := New()
+	ctx := context.Background()
+	for _, key := range []string{"user_stats:1", "user_stats_series:1:count", "user_stats_series:2:count", "product:1:a"} {
+		cache.Set(ctx, key, 1, 0)
+	}
+
+	if err := cache.DeletePattern(ctx, "user_stats_series:1:*"); err != nil {
+		t.Fatalf("DeletePattern: %v", err)
+	}
+
+	keys, _ := cache.Keys(ctx, "*")
+	expected := []string{"product:1:a", "user_stats:1", "user_stats_series:2:count"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected %v left, got %v", expected, keys)
+	}
+}
+
+func TestCache_Patterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		match   bool
+	}{
+		{"product:*:abc", "product:u1:abc", true},
+		{"product:*:abc", "product:u1:abcd", false},
+		{"h?llo", "hallo", true},
+		{"h[ae]llo", "hello", true},
+		{"h[^e]llo", "hello", false},
+		{`a\*b`, "a*b", true},
+		{`a\*b`, "axb", false},
+		{"a.b", "axb", false},
+	}
+
+	for _, tt := range tests {
+		matcher, err := compilePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("compilePattern(%q): %v", tt.pattern, err)
+		}
+		if got := matcher.MatchString(tt.key); got != tt.match {
+			t.Errorf("Pattern %q on %q: expected %v, got %v", tt.pattern, tt.key, tt.match, got)
+		}
+	}
+}
+
+func TestCache_Counters(t *testing.T) {
+	cache := New()
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		if count, err := cache.Incr(ctx, "counter"); err != nil || count != i {
+			t.Fatalf("Expected count %d, got %d, %v", i, count, err)
+		}
+	}
+
+	cache.IncrHash(ctx, "usage:1", map[string]int64{"requests": 2}, "usage_keys", "usage:1", time.Hour)
+	cache.IncrHash(ctx, "usage:1", map[string]int64{"requests": 3, "errors": 1}, "usage_keys", "usage:1", time.Hour)
+
+	hash, _ := cache.GetHashInts(ctx, "usage:1")
+	if hash["requests"] != 5 || hash["errors"] != 1 {
+		t.Errorf("Expected requests 5 and errors 1, got %v", hash)
+	}
+	if members, _ := cache.SetMembers(ctx, "usage_keys"); !reflect.DeepEqual(members, []string{"usage:1"}) {
+		t.Errorf("Expected one member, got %v", members)
+	}
+}
+
+func TestCache_Schedule(t *testing.T) {
+	cache := New()
+	ctx := context.Background()
+	now := time.Now()
+
+	cache.Schedule(ctx, "due", "later", now.Add(time.Hour))
+	cache.Schedule(ctx, "due", "second", now.Add(-time.Minute))
+	cache.Schedule(ctx, "due", "first", now.Add(-time.Hour))
+
+	members, _ := cache.DueMembers(ctx, "due", now, 10)
+	if !reflect.DeepEqual(members, []string{"first", "second"}) {
+		t.Errorf("Expected the due members earliest first, got %v", members)
+	}
+
+	if removed, _ := cache.Unschedule(ctx, "due", "first"); !removed {
+		t.Error("Expected first to be removed")
+	}
+	if removed, _ := cache.Unschedule(ctx, "due", "first"); removed {
+		t.Error("Expected first to be removed only once")
+	}
+}
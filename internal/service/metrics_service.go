@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// DefaultDailySnapshotRetention is how long daily snapshots are kept before downsampling
+const DefaultDailySnapshotRetention = 90 * 24 * time.Hour
+
+// maxStatsHistoryRange bounds the period returned by a single history request
+const maxStatsHistoryRange = 5 * 365 * 24 * time.Hour
+
+// MetricsService archives daily product statistics and serves their history
+type MetricsService struct {
+	snapshotRepo   *repository.StatsSnapshotRepository
+	dailyRetention time.Duration
+}
+
+// NewMetricsService creates a new metrics service
+func NewMetricsService(snapshotRepo *repository.StatsSnapshotRepository, dailyRetention time.Duration) *MetricsService {
+	if dailyRetention <= 0 {
+		dailyRetention = DefaultDailySnapshotRetention
+	}
+
+	return &MetricsService{
+		snapshotRepo:   snapshotRepo,
+		dailyRetention: dailyRetention,
+	}
+}
+
+// CaptureSnapshots records today's statistics for every user and downsamples old snapshots
+func (s *MetricsService) CaptureSnapshots(ctx context.Context) error {
+	today := truncateToDay(time.Now())
+
+	captured, err := s.snapshotRepo.CaptureDaily(ctx, today)
+	if err != nil {
+		return err
+	}
+
+	downsampled, err := s.snapshotRepo.Downsample(ctx, today.Add(-s.dailyRetention))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Metrics snapshots: captured %d, downsampled %d daily snapshots", captured, downsampled)
+	return nil
+}
+
+// StartSnapshotWorker captures snapshots immediately and then on every interval until ctx is cancelled;
+// a capture in progress runs to completion
+func (s *MetricsService) StartSnapshotWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.CaptureSnapshots(context.WithoutCancel(ctx)); err != nil {
+			log.Printf("Metrics snapshot worker: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetHistory returns the user's archived statistics between from and to
+func (s *MetricsService) GetHistory(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.StatsHistoryResponse, error) {
+	from, to = truncateToDay(from), truncateToDay(to)
+	if to.Before(from) {
+		return nil, errors.New("'from' must not be after 'to'")
+	}
+	if to.Sub(from) > maxStatsHistoryRange {
+		return nil, errors.New("requested range is too large")
+	}
+
+	snapshots, err := s.snapshotRepo.GetRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshots == nil {
+		snapshots = []domain.StatsSnapshot{}
+	}
+
+	return &domain.StatsHistoryResponse{
+		From:   from,
+		To:     to,
+		Points: snapshots,
+	}, nil
+}
+
+// truncateToDay returns midnight UTC of the given time's date
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
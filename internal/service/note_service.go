@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// NoteService manages the internal notes thread of products. Notes are seen by
+// the users a product policy allows to take notes on the product, and users
+// mentioned by email who may see the thread are notified.
+type NoteService struct {
+	noteRepo            *repository.NoteRepository
+	userRepo            domain.UserRepository
+	productService      *ProductService
+	notificationService *NotificationService
+}
+
+// NewNoteService creates a new note service
+func NewNoteService(noteRepo *repository.NoteRepository, userRepo domain.UserRepository, productService *ProductService, notificationService *NotificationService) *NoteService {
+	return &NoteService{
+		noteRepo:            noteRepo,
+		userRepo:            userRepo,
+		productService:      productService,
+		notificationService: notificationService,
+	}
+}
+
+// List returns a page of the notes of a product the user may take notes on
+func (s *NoteService) List(ctx context.Context, productID, userID uuid.UUID, pagination domain.Pagination) (*domain.NoteListResponse, error) {
+	if _, err := s.productService.Authorize(ctx, productID, userID, ProductActionNote); err != nil {
+		return nil, err
+	}
+	return s.noteRepo.GetByProductID(ctx, productID, pagination)
+}
+
+// Create adds a note by the user to a product's thread
+func (s *NoteService) Create(ctx context.Context, productID, userID uuid.UUID, req domain.NoteRequest) (*domain.ProductNote, error) {
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionNote)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &domain.ProductNote{
+		ID:        uuid.New(),
+		ProductID: productID,
+		UserID:    userID,
+		Body:      req.Body,
+		Mentions:  domain.ParseMentions(req.Body),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(ctx, product, note, note.Mentions)
+	return s.noteRepo.GetForProduct(ctx, productID, note.ID)
+}
+
+// Update edits the body of a note the user wrote, notifying only users newly mentioned
+func (s *NoteService) Update(ctx context.Context, productID, noteID, userID uuid.UUID, req domain.NoteRequest) (*domain.ProductNote, error) {
+	product, note, err := s.authorNote(ctx, productID, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[string]bool{}
+	for _, email := range note.Mentions {
+		previous[email] = true
+	}
+
+	now := time.Now()
+	note.Body = req.Body
+	note.Mentions = domain.ParseMentions(req.Body)
+	note.EditedAt = &now
+	note.UpdatedAt = now
+	if err := s.noteRepo.UpdateBody(ctx, note); err != nil {
+		return nil, err
+	}
+
+	var added domain.StringList
+	for _, email := range note.Mentions {
+		if !previous[email] {
+			added = append(added, email)
+		}
+	}
+	s.notifyMentions(ctx, product, note, added)
+	return note, nil
+}
+
+// Delete removes a note the user wrote
+func (s *NoteService) Delete(ctx context.Context, productID, noteID, userID uuid.UUID) error {
+	if _, _, err := s.authorNote(ctx, productID, noteID, userID); err != nil {
+		return err
+	}
+	return s.noteRepo.Delete(ctx, noteID)
+}
+
+// authorNote returns a note of a product the user may take notes on, provided the
+// user wrote it
+func (s *NoteService) authorNote(ctx context.Context, productID, noteID, userID uuid.UUID) (*domain.Product, *domain.ProductNote, error) {
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionNote)
+	if err != nil {
+		return nil, nil, err
+	}
+	note, err := s.noteRepo.GetForProduct(ctx, productID, noteID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if note.UserID != userID {
+		return nil, nil, domain.ErrNoteNotAuthor
+	}
+	return product, note, nil
+}
+
+// notifyMentions notifies the mentioned users, other than the note's author, who
+// may see the product's notes; failures are logged so they never fail the note
+func (s *NoteService) notifyMentions(ctx context.Context, product *domain.Product, note *domain.ProductNote, mentions domain.StringList) {
+	author := "Someone"
+	if note.Author != nil {
+		author = note.Author.Name
+	} else if user, err := s.userRepo.GetByID(ctx, note.UserID); err == nil {
+		author = user.Name
+	}
+
+	for _, email := range mentions {
+		user, err := s.userRepo.GetByEmail(ctx, email)
+		if err != nil || user.ID == note.UserID {
+			continue
+		}
+		if err := s.productService.authorizer.Authorize(ctx, user.ID, product, ProductActionNote); err != nil {
+			continue
+		}
+
+		message := fmt.Sprintf("%s mentioned you in a note on %s", author, product.Name)
+		if err := s.notificationService.Notify(ctx, user.ID, NotificationNoteMention, "You were mentioned in a note", message); err != nil {
+			log.Printf("Failed to notify %s of note %s: %v", user.ID, note.ID, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// Notification types
+const (
+	NotificationExportSucceeded  = "export_succeeded"
+	NotificationExportFailed     = "export_failed"
+	NotificationNoteMention      = "note_mention"
+	NotificationSavedSearchMatch = "saved_search_match"
+)
+
+// NotificationTypes lists every notification type users can turn off
+var NotificationTypes = []string{NotificationExportSucceeded, NotificationExportFailed, NotificationNoteMention, NotificationSavedSearchMatch}
+
+// maxNotifications is the number of notifications returned to a user
+const maxNotifications = 50
+
+// NotificationService manages user notifications
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	userRepo         domain.UserRepository
+	stream           *StreamService
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo *repository.NotificationRepository, userRepo domain.UserRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// SetStream sends new notifications to the user's open event streams
+func (s *NotificationService) SetStream(stream *StreamService) {
+	s.stream = stream
+}
+
+// Notify stores a new notification for a user, unless the user turned off
+// notifications of its type
+func (s *NotificationService) Notify(ctx context.Context, userID uuid.UUID, notificationType, title, message string) error {
+	if user, err := s.userRepo.GetByID(ctx, userID); err == nil && !user.Preferences.NotificationEnabled(notificationType) {
+		return nil
+	}
+
+	notification := &domain.Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      notificationType,
+		Title:     title,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to store notification: %w", err)
+	}
+
+	if s.stream != nil {
+		if err := s.stream.PublishNotification(ctx, notification); err != nil {
+			log.Printf("Failed to stream notification: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// List returns the user's most recent notifications and unread count
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID) (*domain.NotificationListResponse, error) {
+	notifications, err := s.notificationRepo.GetByUserID(ctx, userID, maxNotifications)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+
+	return &domain.NotificationListResponse{
+		Notifications: notifications,
+		Unread:        unread,
+	}, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	return s.notificationRepo.MarkRead(ctx, id, userID)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// emailVerificationTTL is how long an email verification token stays valid
+const emailVerificationTTL = 24 * time.Hour
+
+// ErrInvalidVerificationToken is returned for unknown or expired email verification tokens
+var ErrInvalidVerificationToken = domain.NewError(domain.CodeInvalidToken, "invalid or expired verification token")
+
+// OnboardingService drives the guided setup of new users
+type OnboardingService struct {
+	userRepo     domain.UserRepository
+	productRepo  domain.ProductRepository
+	cacheService domain.Cache
+	templates    *EmailTemplateService
+	mailer       Mailer
+}
+
+// NewOnboardingService creates a new onboarding service
+func NewOnboardingService(userRepo domain.UserRepository, productRepo domain.ProductRepository, cacheService domain.Cache, templates *EmailTemplateService, mailer Mailer) *OnboardingService {
+	return &OnboardingService{
+		userRepo:     userRepo,
+		productRepo:  productRepo,
+		cacheService: cacheService,
+		templates:    templates,
+		mailer:       mailer,
+	}
+}
+
+// Get returns the user's onboarding state with the next step to complete
+func (s *OnboardingService) Get(ctx context.Context, userID uuid.UUID) (*domain.OnboardingResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	firstProductAt, err := s.productRepo.FirstCreatedAt(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load products: %w", err)
+	}
+
+	response := domain.BuildOnboarding(domain.OnboardingProgress{
+		EmailVerifiedAt:  user.EmailVerifiedAt,
+		FirstProductAt:   firstProductAt,
+		PreferencesSetAt: user.PreferencesSetAt,
+	})
+
+	return &response, nil
+}
+
+// RequestEmailVerification emails the user a single-use verification token
+func (s *OnboardingService) RequestEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email is already verified")
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	if err := s.cacheService.Set(ctx, emailVerificationCacheKey(token), user.ID.String(), emailVerificationTTL); err != nil {
+		return fmt.Errorf("failed to store token: %w", err)
+	}
+
+	email, err := s.templates.Render(ctx, domain.EmailTemplateVerification, map[string]string{
+		"name":       user.Name,
+		"email":      user.Email,
+		"token":      token,
+		"expires_in": "24 hours",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	return s.mailer.Send(ctx, user.Email, email.Subject, email.Body)
+}
+
+// VerifyEmail confirms the email address belonging to a verification token
+func (s *OnboardingService) VerifyEmail(ctx context.Context, token string) error {
+	cacheKey := emailVerificationCacheKey(token)
+
+	var userIDStr string
+	if err := s.cacheService.Get(ctx, cacheKey, &userIDStr); err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, userID); err != nil {
+		return err
+	}
+
+	s.cacheService.Delete(ctx, cacheKey)
+
+	return nil
+}
+
+// GetPreferences returns the user's preferences
+func (s *OnboardingService) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.UserPreferences, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &user.Preferences, nil
+}
+
+// UpdatePreferences stores the user's preferences, keeping their list and
+// notification settings
+func (s *OnboardingService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *domain.PreferencesRequest) (*domain.UserPreferences, error) {
+	current, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := domain.UserPreferences{
+		Currency:          req.Currency,
+		Locale:            req.Locale,
+		LowStockThreshold: req.LowStockThreshold,
+		ProductCodes:      req.ProductCodes,
+		DefaultPageSize:   current.DefaultPageSize,
+		Notifications:     current.Notifications,
+	}
+
+	if err := s.savePreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// UpdateSettings replaces all of the user's settings, such as with settings
+// exported from another account
+func (s *OnboardingService) UpdateSettings(ctx context.Context, userID uuid.UUID, req *domain.UserSettingsRequest) (*domain.UserPreferences, error) {
+	for notificationType := range req.Notifications {
+		if !slices.Contains(NotificationTypes, notificationType) {
+			return nil, &domain.ValidationError{
+				Message: "invalid settings",
+				Fields:  []domain.FieldError{{Field: "notifications", Message: fmt.Sprintf("unknown notification type %q", notificationType)}},
+			}
+		}
+	}
+
+	preferences := domain.NewUserSettings(req)
+	if err := s.savePreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// DefaultPageSize returns the page size of the user's lists requested without
+// page_size; it falls back to domain.DefaultListPageSize when the user cannot be loaded
+func (s *OnboardingService) DefaultPageSize(ctx context.Context, userID uuid.UUID) int {
+	return s.CachedPreferences(ctx, userID).PageSize()
+}
+
+// CachedPreferences returns the user's preferences, cached for the requests that
+// read them on every call; the defaults are returned when they cannot be loaded
+func (s *OnboardingService) CachedPreferences(ctx context.Context, userID uuid.UUID) domain.UserPreferences {
+	cacheKey := preferencesCacheKey(userID)
+	var preferences domain.UserPreferences
+	if err := s.cacheService.Get(ctx, cacheKey, &preferences); err == nil {
+		return preferences
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.UserPreferences{}
+	}
+	s.cacheService.Set(ctx, cacheKey, user.Preferences, 10*time.Minute)
+	return user.Preferences
+}
+
+// savePreferences stores the user's preferences, giving their products codes
+// when product codes are turned on
+func (s *OnboardingService) savePreferences(ctx context.Context, userID uuid.UUID, preferences domain.UserPreferences) error {
+	if err := s.userRepo.SetPreferences(ctx, userID, preferences); err != nil {
+		return err
+	}
+	s.cacheService.Delete(ctx, preferencesCacheKey(userID))
+
+	if preferences.ProductCodes {
+		assigned, err := s.productRepo.AssignMissingCodes(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to assign product codes: %w", err)
+		}
+		if assigned > 0 {
+			// Cached products and listings predate their codes
+			s.cacheService.DeletePattern(ctx, fmt.Sprintf("product:%s:*", userID))
+			s.cacheService.Delete(ctx, fmt.Sprintf("user_products:%s", userID))
+			s.cacheService.DeletePattern(ctx, fmt.Sprintf("user_products_filtered:%s:*", userID))
+			s.cacheService.DeletePattern(ctx, fmt.Sprintf("user_products_cursor:%s:*", userID))
+		}
+	}
+
+	return nil
+}
+
+// preferencesCacheKey returns the cache key of a user's preferences
+func preferencesCacheKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_preferences:%s", userID)
+}
+
+// emailVerificationCacheKey returns the cache key of an email verification token.
+// Tokens are hashed so that cache contents cannot be replayed.
+func emailVerificationCacheKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("email_verification:%s", hex.EncodeToString(hash[:]))
+}
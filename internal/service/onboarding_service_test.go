@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/mocks"
+)
+
+func TestOnboardingService_DefaultPageSize(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	users := &mocks.UserRepository{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+			return &domain.User{ID: id, Preferences: domain.UserPreferences{DefaultPageSize: 50}}, nil
+		},
+	}
+	cache, _, _ := newCacheMock()
+	onboarding := NewOnboardingService(users, nil, cache, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		if size := onboarding.DefaultPageSize(ctx, userID); size != 50 {
+			t.Errorf("Expected page size 50, got %d", size)
+		}
+	}
+	if calls := users.CallCount("GetByID"); calls != 1 {
+		t.Errorf("Expected cached preferences on the second call, repository was called %d times", calls)
+	}
+}
+
+func TestOnboardingService_DefaultPageSizeWithoutUser(t *testing.T) {
+	users := &mocks.UserRepository{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	cache, _, _ := newCacheMock()
+	onboarding := NewOnboardingService(users, nil, cache, nil, nil)
+
+	if size := onboarding.DefaultPageSize(context.Background(), uuid.New()); size != domain.DefaultListPageSize {
+		t.Errorf("Expected the default page size %d, got %d", domain.DefaultListPageSize, size)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// OrderService places and cancels orders for the user's products, keeping their
+// stock in step
+type OrderService struct {
+	orderRepo      *repository.OrderRepository
+	productService *ProductService
+}
+
+// NewOrderService creates a new order service
+func NewOrderService(orderRepo *repository.OrderRepository, productService *ProductService) *OrderService {
+	return &OrderService{
+		orderRepo:      orderRepo,
+		productService: productService,
+	}
+}
+
+// Create places an order, failing as a whole when a product is missing or short of stock
+func (s *OrderService) Create(ctx context.Context, userID uuid.UUID, req domain.CreateOrderRequest) (*domain.Order, error) {
+	if err := domain.ValidateOrderItems(req.Items); err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	order, products, err := s.orderRepo.Create(ctx, userID, req.Items, s.authorizeOrder(ctx, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	// The products line up with the order's items
+	for i := range products {
+		s.productService.StockChanged(ctx, &products[i], products[i].Stock-order.Items[i].Quantity)
+	}
+	return order, nil
+}
+
+// authorizeOrder returns the check of the products a user's orders change the stock
+// of, run by the repository while they are locked
+func (s *OrderService) authorizeOrder(ctx context.Context, userID uuid.UUID) func(*domain.Product) error {
+	return func(product *domain.Product) error {
+		return s.productService.authorizer.Authorize(ctx, userID, product, ProductActionOrder)
+	}
+}
+
+// Get returns one of the user's orders
+func (s *OrderService) Get(ctx context.Context, id, userID uuid.UUID) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// List returns a page of the user's orders, newest first
+func (s *OrderService) List(ctx context.Context, userID uuid.UUID, query domain.OrderQuery) (*domain.OrderListResponse, error) {
+	return s.orderRepo.GetByUserID(ctx, userID, query)
+}
+
+// Cancel cancels one of the user's orders, putting its quantities back in stock
+func (s *OrderService) Cancel(ctx context.Context, id, userID uuid.UUID) (*domain.Order, error) {
+	order, products, err := s.orderRepo.Cancel(ctx, id, userID, s.authorizeOrder(ctx, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[uuid.UUID]int, len(order.Items))
+	for _, item := range order.Items {
+		quantities[item.ProductID] = item.Quantity
+	}
+	for i := range products {
+		s.productService.StockChanged(ctx, &products[i], products[i].Stock+quantities[products[i].ID])
+	}
+	return order, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// NotificationPlanChanged is the notification type of plan changes
+const NotificationPlanChanged = "plan_changed"
+
+// planCacheTTL is how long a user's plan is cached; plan changes drop it at once
+const planCacheTTL = 5 * time.Minute
+
+// ErrUnknownPlan is returned for a plan that does not exist
+var ErrUnknownPlan = domain.NewError(domain.CodeValidationFailed, "unknown plan")
+
+// PlanService resolves the subscription plan of users and the limits it grants
+type PlanService struct {
+	userRepo            domain.UserRepository
+	cacheService        domain.Cache
+	notificationService *NotificationService
+	plans               []domain.Plan
+}
+
+// NewPlanService creates a new plan service offering plans
+func NewPlanService(userRepo domain.UserRepository, cacheService domain.Cache, notificationService *NotificationService, plans []domain.Plan) *PlanService {
+	return &PlanService{
+		userRepo:            userRepo,
+		cacheService:        cacheService,
+		notificationService: notificationService,
+		plans:               plans,
+	}
+}
+
+// List returns every plan
+func (s *PlanService) List() []domain.Plan {
+	return s.plans
+}
+
+// Get returns the plan with the key. Users on a plan that is no longer offered
+// get the free plan.
+func (s *PlanService) Get(key string) domain.Plan {
+	if plan, ok := s.find(key); ok {
+		return plan
+	}
+	plan, _ := s.find(domain.PlanFree)
+	return plan
+}
+
+// ForUser returns the plan of the user
+func (s *PlanService) ForUser(ctx context.Context, userID uuid.UUID) (domain.Plan, error) {
+	cacheKey := planCacheKey(userID)
+	var key string
+	if err := s.cacheService.Get(ctx, cacheKey, &key); err == nil {
+		return s.Get(key), nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	s.cacheService.Set(ctx, cacheKey, user.Plan, planCacheTTL)
+
+	return s.Get(user.Plan), nil
+}
+
+// SetUserPlan moves a user to another plan, notifying the user
+func (s *PlanService) SetUserPlan(ctx context.Context, userID uuid.UUID, key string) (*domain.User, error) {
+	plan, ok := s.find(key)
+	if !ok {
+		return nil, ErrUnknownPlan
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.NotFoundError(domain.CodeNotFound, "user not found")
+	}
+	if user.Plan == plan.Key {
+		return user, nil
+	}
+
+	if err := s.userRepo.SetPlan(ctx, userID, plan.Key); err != nil {
+		return nil, fmt.Errorf("failed to change plan: %w", err)
+	}
+	user.Plan = plan.Key
+	s.cacheService.Delete(ctx, planCacheKey(userID))
+	s.cacheService.Delete(ctx, quotaCacheKey(userID))
+
+	if err := s.notificationService.Notify(ctx, userID, NotificationPlanChanged, "Plan changed",
+		fmt.Sprintf("Your account is now on the %s plan.", plan.Name)); err != nil {
+		log.Printf("Failed to notify user %s about plan change: %v", userID, err)
+	}
+	return user, nil
+}
+
+// find looks up a plan by key
+func (s *PlanService) find(key string) (domain.Plan, bool) {
+	for _, plan := range s.plans {
+		if plan.Key == key {
+			return plan, true
+		}
+	}
+	return domain.Plan{}, false
+}
+
+// planCacheKey returns the cache key of a user's plan
+func planCacheKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_plan:%s", userID)
+}
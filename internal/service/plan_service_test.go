@@ -0,0 +1,18 @@
+package service
+
+import (
+	"testing"
+
+	"products/internal/domain"
+)
+
+func TestPlanService_Get(t *testing.T) {
+	service := NewPlanService(nil, nil, nil, domain.DefaultPlans())
+
+	if plan := service.Get(domain.PlanPro); plan.Key != domain.PlanPro {
+		t.Errorf("Expected the pro plan, got %+v", plan)
+	}
+	if plan := service.Get("legacy"); plan.Key != domain.PlanFree {
+		t.Errorf("Expected users on a retired plan to get the free plan, got %+v", plan)
+	}
+}
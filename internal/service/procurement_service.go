@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// ProcurementService manages a user's suppliers and the purchase orders that
+// restock products from them
+type ProcurementService struct {
+	supplierRepo      *repository.SupplierRepository
+	purchaseOrderRepo *repository.PurchaseOrderRepository
+	productService    *ProductService
+}
+
+// NewProcurementService creates a new procurement service
+func NewProcurementService(supplierRepo *repository.SupplierRepository, purchaseOrderRepo *repository.PurchaseOrderRepository, productService *ProductService) *ProcurementService {
+	return &ProcurementService{
+		supplierRepo:      supplierRepo,
+		purchaseOrderRepo: purchaseOrderRepo,
+		productService:    productService,
+	}
+}
+
+// ListSuppliers returns the user's suppliers by name
+func (s *ProcurementService) ListSuppliers(ctx context.Context, userID uuid.UUID) ([]domain.Supplier, error) {
+	suppliers, err := s.supplierRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if suppliers == nil {
+		suppliers = []domain.Supplier{}
+	}
+	return suppliers, nil
+}
+
+// CreateSupplier adds a supplier for the user
+func (s *ProcurementService) CreateSupplier(ctx context.Context, userID uuid.UUID, req domain.SupplierRequest) (*domain.Supplier, error) {
+	supplier := &domain.Supplier{
+		UserID: userID,
+		Name:   req.Name,
+		Email:  req.Email,
+		Phone:  req.Phone,
+		Notes:  req.Notes,
+	}
+	if err := s.supplierRepo.Create(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// GetSupplier returns one of the user's suppliers
+func (s *ProcurementService) GetSupplier(ctx context.Context, userID, id uuid.UUID) (*domain.Supplier, error) {
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil || supplier.UserID != userID {
+		return nil, domain.ErrSupplierNotFound
+	}
+	return supplier, nil
+}
+
+// UpdateSupplier replaces the details of one of the user's suppliers
+func (s *ProcurementService) UpdateSupplier(ctx context.Context, userID, id uuid.UUID, req domain.SupplierRequest) (*domain.Supplier, error) {
+	supplier, err := s.GetSupplier(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier.Name = req.Name
+	supplier.Email = req.Email
+	supplier.Phone = req.Phone
+	supplier.Notes = req.Notes
+	supplier.UpdatedAt = time.Now()
+	if err := s.supplierRepo.Update(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// DeleteSupplier removes one of the user's suppliers unless it has open purchase orders
+func (s *ProcurementService) DeleteSupplier(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.GetSupplier(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.supplierRepo.DeleteUnlessOpen(ctx, id)
+}
+
+// CreatePurchaseOrder opens a purchase order of the user's products from one of
+// their suppliers
+func (s *ProcurementService) CreatePurchaseOrder(ctx context.Context, userID uuid.UUID, req domain.PurchaseOrderRequest) (*domain.PurchaseOrder, error) {
+	if err := domain.ValidatePurchaseOrderItems(req.Items); err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	order := domain.NewPurchaseOrder(userID, req)
+	if err := s.purchaseOrderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetPurchaseOrder returns one of the user's purchase orders
+func (s *ProcurementService) GetPurchaseOrder(ctx context.Context, userID, id uuid.UUID) (*domain.PurchaseOrder, error) {
+	order, err := s.purchaseOrderRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, domain.ErrPurchaseOrderNotFound
+	}
+	return order, nil
+}
+
+// ListPurchaseOrders returns a page of the user's purchase orders, newest first
+func (s *ProcurementService) ListPurchaseOrders(ctx context.Context, userID uuid.UUID, query domain.PurchaseOrderQuery) (*domain.PurchaseOrderListResponse, error) {
+	return s.purchaseOrderRepo.GetByUserID(ctx, userID, query)
+}
+
+// ReceivePurchaseOrder marks an open purchase order received, adding its quantities
+// to stock. Each product's stock change is recorded as a stock adjustment event.
+func (s *ProcurementService) ReceivePurchaseOrder(ctx context.Context, userID, id uuid.UUID) (*domain.PurchaseOrder, error) {
+	order, products, err := s.purchaseOrderRepo.Receive(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[uuid.UUID]int, len(order.Items))
+	for _, item := range order.Items {
+		quantities[item.ProductID] = item.Quantity
+	}
+	for i := range products {
+		s.productService.StockChanged(ctx, &products[i], products[i].Stock+quantities[products[i].ID])
+	}
+	return order, nil
+}
+
+// CancelPurchaseOrder cancels an open purchase order
+func (s *ProcurementService) CancelPurchaseOrder(ctx context.Context, userID, id uuid.UUID) (*domain.PurchaseOrder, error) {
+	return s.purchaseOrderRepo.Cancel(ctx, id, userID)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// ProductAction is an operation guarded by product policies
+type ProductAction string
+
+// Product actions
+const (
+	ProductActionView        ProductAction = "view"
+	ProductActionUpdate      ProductAction = "update"
+	ProductActionDelete      ProductAction = "delete"
+	ProductActionPublish     ProductAction = "publish"
+	ProductActionAdjustStock ProductAction = "adjust_stock"
+	ProductActionFavorite    ProductAction = "favorite"
+	ProductActionNote        ProductAction = "note"
+	ProductActionOrder       ProductAction = "order"
+)
+
+// ProductPolicy decides whether a user may perform an action on a product.
+// New sharing models (organization members, explicit shares) plug in by
+// implementing this interface and registering with the ProductAuthorizer.
+type ProductPolicy interface {
+	Allows(ctx context.Context, userID uuid.UUID, product *domain.Product, action ProductAction) (bool, error)
+}
+
+// OwnerPolicy grants every action to the user who owns the product
+type OwnerPolicy struct{}
+
+// Allows implements ProductPolicy
+func (OwnerPolicy) Allows(_ context.Context, userID uuid.UUID, product *domain.Product, _ ProductAction) (bool, error) {
+	return product.UserID == userID, nil
+}
+
+// PublicPolicy grants the given actions to any user on products listed in their
+// owner's public catalog
+type PublicPolicy struct {
+	Actions []ProductAction
+}
+
+// Allows implements ProductPolicy
+func (p PublicPolicy) Allows(_ context.Context, _ uuid.UUID, product *domain.Product, action ProductAction) (bool, error) {
+	if !product.IsListedPublicly() {
+		return false, nil
+	}
+	return slices.Contains(p.Actions, action), nil
+}
+
+// OrganizationMembership reports whether two users belong to the same organization
+type OrganizationMembership interface {
+	SameOrganization(ctx context.Context, userID, ownerID uuid.UUID) (bool, error)
+}
+
+// OrgMemberPolicy grants the given actions to the members of the organization of
+// the product's owner
+type OrgMemberPolicy struct {
+	Memberships OrganizationMembership
+	Actions     []ProductAction
+}
+
+// Allows implements ProductPolicy
+func (p OrgMemberPolicy) Allows(ctx context.Context, userID uuid.UUID, product *domain.Product, action ProductAction) (bool, error) {
+	if !slices.Contains(p.Actions, action) {
+		return false, nil
+	}
+	return p.Memberships.SameOrganization(ctx, userID, product.UserID)
+}
+
+// ProductShares reports the actions a product's owner shared with a user
+type ProductShares interface {
+	SharedActions(ctx context.Context, productID, userID uuid.UUID) ([]ProductAction, error)
+}
+
+// SharedWithPolicy grants the actions a product's owner explicitly shared with a user
+type SharedWithPolicy struct {
+	Shares ProductShares
+}
+
+// Allows implements ProductPolicy
+func (p SharedWithPolicy) Allows(ctx context.Context, userID uuid.UUID, product *domain.Product, action ProductAction) (bool, error) {
+	actions, err := p.Shares.SharedActions(ctx, product.ID, userID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(actions, action), nil
+}
+
+// ProductAuthorizer evaluates product policies in one place
+type ProductAuthorizer struct {
+	policies []ProductPolicy
+}
+
+// NewProductAuthorizer creates an authorizer that grants an action when any policy allows it
+func NewProductAuthorizer(policies ...ProductPolicy) *ProductAuthorizer {
+	return &ProductAuthorizer{policies: policies}
+}
+
+// DefaultProductAuthorizer lets owners do everything and anyone favorite public
+// products. OrgMemberPolicy and SharedWithPolicy are not registered until
+// organizations and shares are stored.
+func DefaultProductAuthorizer() *ProductAuthorizer {
+	return NewProductAuthorizer(
+		OwnerPolicy{},
+		PublicPolicy{Actions: []ProductAction{ProductActionFavorite}},
+	)
+}
+
+// Authorize returns domain.ErrProductForbidden unless a policy allows the action
+func (a *ProductAuthorizer) Authorize(ctx context.Context, userID uuid.UUID, product *domain.Product, action ProductAction) error {
+	for _, policy := range a.policies {
+		allowed, err := policy.Allows(ctx, userID, product, action)
+		if err != nil {
+			return err
+		}
+		if allowed {
+			return nil
+		}
+	}
+	return domain.ErrProductForbidden
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// productShares keeps the actions shared with each user
+type productShares map[uuid.UUID][]ProductAction
+
+func (s productShares) SharedActions(_ context.Context, _, userID uuid.UUID) ([]ProductAction, error) {
+	return s[userID], nil
+}
+
+// organizations maps users to their organization
+type organizations map[uuid.UUID]string
+
+func (o organizations) SameOrganization(_ context.Context, userID, ownerID uuid.UUID) (bool, error) {
+	return o[userID] != "" && o[userID] == o[ownerID], nil
+}
+
+// failingPolicy always returns an error
+type failingPolicy struct{}
+
+func (failingPolicy) Allows(context.Context, uuid.UUID, *domain.Product, ProductAction) (bool, error) {
+	return false, errors.New("membership lookup failed")
+}
+
+func TestDefaultProductAuthorizer(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	other := uuid.New()
+	product := &domain.Product{ID: uuid.New(), UserID: owner}
+	publicProduct := &domain.Product{ID: uuid.New(), UserID: owner, Public: true, Status: domain.ProductStatusPublished}
+	publicDraft := &domain.Product{ID: uuid.New(), UserID: owner, Public: true, Status: domain.ProductStatusDraft}
+	archivedAt := time.Now()
+	publicArchived := &domain.Product{ID: uuid.New(), UserID: owner, Public: true, Status: domain.ProductStatusPublished, ArchivedAt: &archivedAt}
+	authorizer := DefaultProductAuthorizer()
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		product *domain.Product
+		action  ProductAction
+		allowed bool
+	}{
+		{"owner updates", owner, product, ProductActionUpdate, true},
+		{"owner deletes", owner, product, ProductActionDelete, true},
+		{"other views private", other, product, ProductActionView, false},
+		{"other favorites private", other, product, ProductActionFavorite, false},
+		{"other favorites public", other, publicProduct, ProductActionFavorite, true},
+		{"other favorites public draft", other, publicDraft, ProductActionFavorite, false},
+		{"other favorites public archived", other, publicArchived, ProductActionFavorite, false},
+		{"owner favorites draft", owner, publicDraft, ProductActionFavorite, true},
+		{"other updates public", other, publicProduct, ProductActionUpdate, false},
+		{"owner orders", owner, product, ProductActionOrder, true},
+		{"other orders public", other, publicProduct, ProductActionOrder, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(ctx, tt.userID, tt.product, tt.action)
+			if tt.allowed && err != nil {
+				t.Errorf("Expected access, got %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, domain.ErrForbidden) {
+				t.Errorf("Expected forbidden, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProductAuthorizer_CustomPolicy(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	viewer := uuid.New()
+	product := &domain.Product{ID: uuid.New(), UserID: owner}
+	member := uuid.New()
+	outsider := uuid.New()
+	authorizer := NewProductAuthorizer(
+		OwnerPolicy{},
+		SharedWithPolicy{Shares: productShares{viewer: {ProductActionView}}},
+		OrgMemberPolicy{Memberships: organizations{owner: "acme", member: "acme", outsider: "other"}, Actions: []ProductAction{ProductActionView, ProductActionOrder}},
+	)
+
+	if err := authorizer.Authorize(ctx, viewer, product, ProductActionView); err != nil {
+		t.Errorf("Expected shared user to view product, got %v", err)
+	}
+	if err := authorizer.Authorize(ctx, viewer, product, ProductActionDelete); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected shared user not to delete product, got %v", err)
+	}
+	if err := authorizer.Authorize(ctx, member, product, ProductActionOrder); err != nil {
+		t.Errorf("Expected organization member to order product, got %v", err)
+	}
+	if err := authorizer.Authorize(ctx, member, product, ProductActionUpdate); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected organization member not to update product, got %v", err)
+	}
+	if err := authorizer.Authorize(ctx, outsider, product, ProductActionView); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected user of another organization not to view product, got %v", err)
+	}
+}
+
+func TestProductAuthorizer_PolicyError(t *testing.T) {
+	authorizer := NewProductAuthorizer(failingPolicy{}, OwnerPolicy{})
+	product := &domain.Product{ID: uuid.New(), UserID: uuid.New()}
+
+	err := authorizer.Authorize(context.Background(), product.UserID, product, ProductActionView)
+	if err == nil || errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected policy error to be returned, got %v", err)
+	}
+}
@@ -5,44 +5,139 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"products/internal/domain"
+	"products/internal/markdown"
 	"products/internal/repository"
 )
 
+// ErrInvalidFilter is returned when a query filter cannot be applied
+var ErrInvalidFilter = domain.NewError(domain.CodeInvalidFilter, "invalid filter")
+
 // ProductService implements the product service interface
 type ProductService struct {
-	productRepo  *repository.ProductRepository
-	cacheService *CacheService
+	productRepo   domain.ProductRepository
+	attributeRepo *repository.AttributeDefinitionRepository
+	cacheService  domain.Cache
+	quotaService  *QuotaService
+	authorizer    *ProductAuthorizer
+	events        *EventService
+	listCalls     *coalescer
 }
 
 // NewProductService creates a new product service
-func NewProductService(productRepo *repository.ProductRepository, cacheService *CacheService) *ProductService {
+// A nil quota service disables quota enforcement and a nil webhook service disables webhook events
+func NewProductService(productRepo domain.ProductRepository, attributeRepo *repository.AttributeDefinitionRepository, cacheService domain.Cache, quotaService *QuotaService, authorizer *ProductAuthorizer, events *EventService) *ProductService {
 	return &ProductService{
-		productRepo:  productRepo,
-		cacheService: cacheService,
+		productRepo:   productRepo,
+		attributeRepo: attributeRepo,
+		cacheService:  cacheService,
+		quotaService:  quotaService,
+		authorizer:    authorizer,
+		events:        events,
+		listCalls:     newCoalescer(),
 	}
 }
 
-// Create creates a new product for a specific user
+// Create creates a new product for a specific user, published unless its status is draft
 func (s *ProductService) Create(ctx context.Context, product *domain.Product, userID uuid.UUID) error {
+	if product.Attributes == nil {
+		product.Attributes = domain.Attributes{}
+	}
+	if product.Status == "" {
+		product.Status = domain.ProductStatusPublished
+	}
+	if err := s.validateAttributes(ctx, userID, product.Attributes, product.Status == domain.ProductStatusDraft); err != nil {
+		return err
+	}
+
+	if s.quotaService != nil {
+		if err := s.quotaService.CheckCreate(ctx, userID, 1); err != nil {
+			return err
+		}
+	}
+
 	product.ID = uuid.New()
 	product.UserID = userID
+	product.DescriptionHTML = markdown.Render(product.Description)
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
 
+	first, err := s.productRepo.ReserveProductCodes(ctx, userID, 1)
+	if err != nil {
+		return fmt.Errorf("failed to reserve product code: %w", err)
+	}
+	if first > 0 {
+		code := domain.EncodeProductCode(first)
+		product.Code = &code
+	}
+
 	if err := s.productRepo.Create(ctx, product); err != nil {
 		return err
 	}
 
 	s.invalidateUserCache(ctx, userID)
+	if product.Public {
+		invalidatePublicCache(ctx, s.cacheService, userID, product.ID)
+	}
+	s.publish(ctx, domain.EventProductCreated, product, nil)
 
 	return nil
 }
 
-// GetByID retrieves a product by ID, ensuring the user owns it
+// CreateBatch creates several products for a user at once, enforcing the quota for all of them
+func (s *ProductService) CreateBatch(ctx context.Context, products []domain.Product, userID uuid.UUID) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	if s.quotaService != nil {
+		if err := s.quotaService.CheckCreate(ctx, userID, int64(len(products))); err != nil {
+			return err
+		}
+	}
+
+	first, err := s.productRepo.ReserveProductCodes(ctx, userID, len(products))
+	if err != nil {
+		return fmt.Errorf("failed to reserve product codes: %w", err)
+	}
+
+	now := time.Now()
+	for i := range products {
+		if products[i].Attributes == nil {
+			products[i].Attributes = domain.Attributes{}
+		}
+		products[i].ID = uuid.New()
+		products[i].DescriptionHTML = markdown.Render(products[i].Description)
+		products[i].CreatedAt = now
+		products[i].UpdatedAt = now
+		if first > 0 {
+			code := domain.EncodeProductCode(first + int64(i))
+			products[i].Code = &code
+		}
+	}
+
+	if err := s.productRepo.UpsertForUser(ctx, userID, products); err != nil {
+		return err
+	}
+
+	s.invalidateUserCache(ctx, userID)
+	for i := range products {
+		if products[i].Public {
+			invalidatePublicCache(ctx, s.cacheService, userID, products[i].ID)
+		}
+		s.publish(ctx, domain.EventProductCreated, &products[i], nil)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a product by ID, ensuring the user may view it
 func (s *ProductService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Product, error) {
 	cacheKey := fmt.Sprintf("product:%s:%s", userID, id)
 	var cachedProduct domain.Product
@@ -50,15 +145,11 @@ func (s *ProductService) GetByID(ctx context.Context, id, userID uuid.UUID) (*do
 		return &cachedProduct, nil
 	}
 
-	product, err := s.productRepo.GetByID(ctx, id)
+	product, err := s.Authorize(ctx, id, userID, ProductActionView)
 	if err != nil {
 		return nil, err
 	}
 
-	if product.UserID != userID {
-		return nil, errors.New("unauthorized access to product")
-	}
-
 	s.cacheService.Set(ctx, cacheKey, product, 30*time.Minute)
 
 	return product, nil
@@ -72,7 +163,7 @@ func (s *ProductService) GetAllByUser(ctx context.Context, userID uuid.UUID) ([]
 		return cachedProducts, nil
 	}
 
-	products, err := s.productRepo.GetByUserID(ctx, userID)
+	products, err := s.productRepo.GetActiveByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -84,103 +175,407 @@ func (s *ProductService) GetAllByUser(ctx context.Context, userID uuid.UUID) ([]
 
 // GetProductsWithFilters retrieves products with advanced filtering, sorting, and pagination
 func (s *ProductService) GetProductsWithFilters(ctx context.Context, userID uuid.UUID, query domain.ProductQuery) (*domain.ProductListResponse, error) {
-	cacheKey := s.generateQueryCacheKey(userID, query)
+	data, err := s.GetProductsWithFiltersJSON(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
 
-	var cachedResponse domain.ProductListResponse
-	if err := s.cacheService.Get(ctx, cacheKey, &cachedResponse); err == nil {
-		return &cachedResponse, nil
+	var response domain.ProductListResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
 	}
 
-	response, err := s.productRepo.GetProductsWithFilters(ctx, userID, query)
+	return &response, nil
+}
+
+// StreamProducts calls yield with each of the user's products matching the
+// query's filters as they are read, bypassing pagination and the list cache
+func (s *ProductService) StreamProducts(ctx context.Context, userID uuid.UUID, query domain.ProductQuery, yield func(*domain.Product) error) error {
+	if err := s.normalizeAttributeFilter(ctx, userID, &query.Filter); err != nil {
+		return err
+	}
+	return s.productRepo.StreamProductsWithFilters(ctx, userID, query, yield)
+}
+
+// GetByIDs fetches the products with the given IDs in one query, in the order
+// requested. Duplicate IDs are fetched once; products that do not exist or that
+// the user may not view are reported as missing.
+func (s *ProductService) GetByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*domain.BatchGetProductResponse, error) {
+	if len(ids) > domain.MaxBatchGetProducts {
+		return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("at most %d products can be fetched at once", domain.MaxBatchGetProducts))
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, ids)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
+	}
+	byID := make(map[uuid.UUID]*domain.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+
+	response := &domain.BatchGetProductResponse{Products: []domain.Product{}, Missing: []uuid.UUID{}}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		product, ok := byID[id]
+		if !ok {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+		if err := s.authorizer.Authorize(ctx, userID, product, ProductActionView); err != nil {
+			if errors.Is(err, domain.ErrProductForbidden) {
+				response.Missing = append(response.Missing, id)
+				continue
+			}
+			return nil, err
+		}
+		response.Products = append(response.Products, *product)
+	}
+
+	return response, nil
+}
+
+// SuggestNames returns up to MaxSuggestions names of the user's products completing
+// a search-as-you-type query; a blank query has none
+func (s *ProductService) SuggestNames(ctx context.Context, userID uuid.UUID, query string) (*domain.ProductSuggestionsResponse, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) > domain.MaxSuggestionQuery {
+		return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("q must be at most %d characters", domain.MaxSuggestionQuery))
 	}
 
-	s.cacheService.Set(ctx, cacheKey, response, 5*time.Minute)
+	response := &domain.ProductSuggestionsResponse{Suggestions: []string{}}
+	if query == "" {
+		return response, nil
+	}
 
+	names, err := s.productRepo.SuggestNames(ctx, userID, query, domain.MaxSuggestions)
+	if err != nil {
+		return nil, err
+	}
+	response.Suggestions = append(response.Suggestions, names...)
 	return response, nil
 }
 
+// GetProductsWithFiltersJSON returns the JSON-encoded filtered product list.
+// Identical concurrent requests from the same user share a single query and encoding.
+func (s *ProductService) GetProductsWithFiltersJSON(ctx context.Context, userID uuid.UUID, query domain.ProductQuery) ([]byte, error) {
+	if err := s.normalizeAttributeFilter(ctx, userID, &query.Filter); err != nil {
+		return nil, err
+	}
+
+	cacheKey := s.generateQueryCacheKey(userID, query)
+
+	data, err, _ := s.listCalls.Do(ctx, cacheKey, func() ([]byte, error) {
+		return s.loadJSON(ctx, cacheKey, 5*time.Minute, func(ctx context.Context) (interface{}, error) {
+			return s.productRepo.GetProductsWithFilters(ctx, userID, query)
+		})
+	})
+
+	return data, err
+}
+
 // GetProductsWithCursor retrieves products with cursor-based pagination
 func (s *ProductService) GetProductsWithCursor(ctx context.Context, userID uuid.UUID, query domain.ProductQueryCursor) (*domain.ProductListCursorResponse, error) {
+	data, err := s.GetProductsWithCursorJSON(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response domain.ProductListCursorResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetProductsWithCursorJSON returns the JSON-encoded cursor-paginated product list.
+// Identical concurrent requests from the same user share a single query and encoding.
+func (s *ProductService) GetProductsWithCursorJSON(ctx context.Context, userID uuid.UUID, query domain.ProductQueryCursor) ([]byte, error) {
+	if err := s.normalizeAttributeFilter(ctx, userID, &query.Filter); err != nil {
+		return nil, err
+	}
+
 	cacheKey := s.generateCursorQueryCacheKey(userID, query)
 
-	var cachedResponse domain.ProductListCursorResponse
-	if err := s.cacheService.Get(ctx, cacheKey, &cachedResponse); err == nil {
-		return &cachedResponse, nil
+	data, err, _ := s.listCalls.Do(ctx, cacheKey, func() ([]byte, error) {
+		return s.loadJSON(ctx, cacheKey, 5*time.Minute, func(ctx context.Context) (interface{}, error) {
+			return s.productRepo.GetProductsWithCursor(ctx, userID, query)
+		})
+	})
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+
+	return data, err
+}
+
+// loadJSON returns the cached JSON for cacheKey, or runs load, encodes its
+// result once and caches it. load runs detached from the caller's cancellation
+// because its result may be shared with other waiting requests.
+func (s *ProductService) loadJSON(ctx context.Context, cacheKey string, ttl time.Duration, load func(ctx context.Context) (interface{}, error)) ([]byte, error) {
+	if data, err := s.cacheService.GetRaw(ctx, cacheKey); err == nil {
+		return data, nil
 	}
 
-	response, err := s.productRepo.GetProductsWithCursor(ctx, userID, query)
+	loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	result, err := load(loadCtx)
 	if err != nil {
 		return nil, err
 	}
 
-	s.cacheService.Set(ctx, cacheKey, response, 5*time.Minute)
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode products: %w", err)
+	}
 
-	return response, nil
+	s.cacheService.SetRaw(loadCtx, cacheKey, data, ttl)
+
+	return data, nil
 }
 
-// Update updates a product, ensuring the user owns it
-func (s *ProductService) Update(ctx context.Context, product *domain.Product, userID uuid.UUID) error {
-	existingProduct, err := s.productRepo.GetByID(ctx, product.ID)
+// Update changes the fields of a product set in req, ensuring the user may change
+// it. Only those columns are written, so concurrent stock changes such as orders
+// and decrements are kept unless req sets the stock, which may not fall below the
+// reserved stock.
+func (s *ProductService) Update(ctx context.Context, id uuid.UUID, req *domain.UpdateProductRequest, userID uuid.UUID) error {
+	existingProduct, err := s.Authorize(ctx, id, userID, ProductActionUpdate)
 	if err != nil {
 		return err
 	}
 
-	if existingProduct.UserID != userID {
-		return errors.New("unauthorized access to product")
-	}
+	previous := *existingProduct
+	columns := []string{"updated_at"}
 
-	if product.Name != "" {
-		existingProduct.Name = product.Name
+	if req.Name != nil {
+		existingProduct.Name = *req.Name
+		columns = append(columns, "name")
 	}
-	if product.Description != "" {
-		existingProduct.Description = product.Description
+	if req.Description != nil {
+		existingProduct.Description = *req.Description
+		existingProduct.DescriptionHTML = markdown.Render(*req.Description)
+		columns = append(columns, "description", "description_html")
 	}
-	if product.Price > 0 {
-		existingProduct.Price = product.Price
+	if req.Price != nil {
+		existingProduct.Price = *req.Price
+		columns = append(columns, "price")
 	}
-	if product.Stock >= 0 {
-		existingProduct.Stock = product.Stock
+	if req.Stock != nil {
+		existingProduct.Stock = *req.Stock
+		columns = append(columns, "stock")
+	}
+	if req.Attributes != nil {
+		if err := s.validateAttributes(ctx, userID, req.Attributes, existingProduct.Status == domain.ProductStatusDraft); err != nil {
+			return err
+		}
+		existingProduct.Attributes = req.Attributes
+		columns = append(columns, "attributes")
+	}
+	if req.LowStockThreshold != nil {
+		existingProduct.LowStockThreshold = req.LowStockThreshold
+		columns = append(columns, "low_stock_threshold")
+	}
+	if req.ReorderQuantity != nil {
+		existingProduct.ReorderQuantity = req.ReorderQuantity
+		columns = append(columns, "reorder_quantity")
 	}
 
 	existingProduct.UpdatedAt = time.Now()
+	// The database bumps the version too; this keeps the returned product in step
+	existingProduct.Version++
 
-	if err := s.productRepo.Update(ctx, existingProduct); err != nil {
+	if err := s.productRepo.UpdateColumns(ctx, existingProduct, columns); err != nil {
 		return err
 	}
 
-	s.invalidateUserCache(ctx, userID)
+	s.invalidateProductCache(ctx, existingProduct)
+
+	s.publish(ctx, domain.EventProductUpdated, existingProduct, &previous)
+	if existingProduct.Price != previous.Price {
+		s.publish(ctx, domain.EventProductPriceChanged, existingProduct, &previous)
+	}
+	if existingProduct.Stock != previous.Stock {
+		s.publish(ctx, domain.EventProductStockChanged, existingProduct, &previous)
+	}
 
 	return nil
 }
 
-// Delete deletes a product, ensuring the user owns it
+// Delete deletes a product, ensuring the user may delete it
 func (s *ProductService) Delete(ctx context.Context, id, userID uuid.UUID) error {
-	existingProduct, err := s.productRepo.GetByID(ctx, id)
+	existingProduct, err := s.Authorize(ctx, id, userID, ProductActionDelete)
 	if err != nil {
 		return err
 	}
 
-	if existingProduct.UserID != userID {
-		return errors.New("unauthorized access to product")
-	}
-
 	if err := s.productRepo.Delete(ctx, id); err != nil {
 		return err
 	}
 
-	s.invalidateUserCache(ctx, userID)
+	s.invalidateProductCache(ctx, existingProduct)
+	if s.quotaService != nil {
+		s.quotaService.Refresh(ctx, existingProduct.UserID)
+	}
+	s.publish(ctx, domain.EventProductDeleted, existingProduct, nil)
 
 	return nil
 }
 
+// Publish publishes a draft product the user may update once it meets the rules
+// that apply on publishing, failing with a *domain.ValidationError listing the
+// rules it does not meet
+func (s *ProductService) Publish(ctx context.Context, id, userID uuid.UUID) (*domain.Product, error) {
+	product, err := s.Authorize(ctx, id, userID, ProductActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == domain.ProductStatusPublished {
+		return product, nil
+	}
+
+	definitions, err := s.attributeRepo.GetByUserID(ctx, product.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+	if err := domain.ValidatePublish(product, definitions); err != nil {
+		return nil, err
+	}
+
+	return s.setStatus(ctx, product, domain.ProductStatusPublished)
+}
+
+// Unpublish turns a product the user may update back into a draft, taking it out
+// of the public catalog
+func (s *ProductService) Unpublish(ctx context.Context, id, userID uuid.UUID) (*domain.Product, error) {
+	product, err := s.Authorize(ctx, id, userID, ProductActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == domain.ProductStatusDraft {
+		return product, nil
+	}
+
+	return s.setStatus(ctx, product, domain.ProductStatusDraft)
+}
+
+// setStatus changes the status of a product and publishes the update
+func (s *ProductService) setStatus(ctx context.Context, product *domain.Product, status string) (*domain.Product, error) {
+	previous := *product
+	if err := s.productRepo.SetStatus(ctx, product.ID, status); err != nil {
+		return nil, err
+	}
+	product.Status = status
+	product.UpdatedAt = time.Now()
+	product.Version++
+
+	s.invalidateProductCache(ctx, product)
+	s.publish(ctx, domain.EventProductUpdated, product, &previous)
+
+	return product, nil
+}
+
+// Archive archives a product the user may update, leaving it out of lists, statistics
+// and low-stock alerts until it is unarchived. Archiving an archived product keeps
+// its original archive time.
+func (s *ProductService) Archive(ctx context.Context, id, userID uuid.UUID) (*domain.Product, error) {
+	now := time.Now()
+	return s.setArchived(ctx, id, userID, &now)
+}
+
+// Unarchive returns an archived product the user may update to lists and statistics
+func (s *ProductService) Unarchive(ctx context.Context, id, userID uuid.UUID) (*domain.Product, error) {
+	return s.setArchived(ctx, id, userID, nil)
+}
+
+// setArchived archives a product at archivedAt or unarchives it when archivedAt is nil
+func (s *ProductService) setArchived(ctx context.Context, id, userID uuid.UUID, archivedAt *time.Time) (*domain.Product, error) {
+	product, err := s.Authorize(ctx, id, userID, ProductActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if (product.ArchivedAt != nil) == (archivedAt != nil) {
+		return product, nil
+	}
+
+	previous := *product
+	if err := s.productRepo.SetArchived(ctx, id, archivedAt); err != nil {
+		return nil, err
+	}
+	product.ArchivedAt = archivedAt
+	product.UpdatedAt = time.Now()
+	product.Version++
+
+	s.invalidateProductCache(ctx, product)
+	s.publish(ctx, domain.EventProductUpdated, product, &previous)
+
+	return product, nil
+}
+
+// AdjustStock adds delta to the stock of a product the user may adjust and returns the new stock
+func (s *ProductService) AdjustStock(ctx context.Context, id, userID uuid.UUID, delta int) (int, error) {
+	product, err := s.Authorize(ctx, id, userID, ProductActionAdjustStock)
+	if err != nil {
+		return 0, err
+	}
+
+	stock, err := s.productRepo.AdjustStock(ctx, id, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	s.StockChanged(ctx, product, stock)
+
+	return stock, nil
+}
+
+// DecrementStock takes quantity units out of the available stock of a product the
+// user may adjust, failing with an out-of-stock error when fewer are available
+func (s *ProductService) DecrementStock(ctx context.Context, id, userID uuid.UUID, quantity int) (*domain.StockResponse, error) {
+	product, err := s.Authorize(ctx, id, userID, ProductActionAdjustStock)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.productRepo.DecrementStock(ctx, id, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	s.StockChanged(ctx, product, updated.Stock)
+
+	return &domain.StockResponse{
+		ProductID:      id,
+		Stock:          updated.Stock,
+		ReservedStock:  updated.ReservedStock,
+		AvailableStock: updated.AvailableStock(),
+	}, nil
+}
+
+// StockChanged drops the cached copies of a product whose stock changed to stock and
+// publishes the adjustment; previous is the product before the change
+func (s *ProductService) StockChanged(ctx context.Context, previous *domain.Product, stock int) {
+	s.invalidateProductCache(ctx, previous)
+
+	if stock != previous.Stock {
+		adjusted := *previous
+		adjusted.Stock = stock
+		s.publish(ctx, domain.EventStockAdjusted, &adjusted, previous)
+	}
+}
+
 // GetProductStats retrieves product statistics for a user
-func (s *ProductService) GetProductStats(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+func (s *ProductService) GetProductStats(ctx context.Context, userID uuid.UUID) (*domain.ProductStats, error) {
 	cacheKey := fmt.Sprintf("user_stats:%s", userID)
-	var cachedStats map[string]interface{}
+	var cachedStats domain.ProductStats
 	if err := s.cacheService.Get(ctx, cacheKey, &cachedStats); err == nil {
-		return cachedStats, nil
+		return &cachedStats, nil
 	}
 
 	stats, err := s.productRepo.GetProductStats(ctx, userID)
@@ -193,6 +588,144 @@ func (s *ProductService) GetProductStats(ctx context.Context, userID uuid.UUID)
 	return stats, nil
 }
 
+// GetStatsSeries returns a metric of the user's products per bucket of the given
+// interval they were created in, covering from to to. Every bucket is cached on
+// its own, so only buckets missing from the cache are queried; buckets are
+// dropped whenever the user's products change.
+func (s *ProductService) GetStatsSeries(ctx context.Context, userID uuid.UUID, metric, interval string, from, to time.Time) (*domain.StatsSeriesResponse, error) {
+	buckets, err := domain.StatsBuckets(metric, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]domain.StatsSeriesPoint, len(buckets))
+	missing := -1
+	for i, bucket := range buckets {
+		points[i].Bucket = bucket
+		if err := s.cacheService.Get(ctx, statsSeriesCacheKey(userID, metric, interval, bucket), &points[i].Value); err != nil && missing < 0 {
+			missing = i
+		}
+	}
+
+	if missing >= 0 {
+		end := nextStatsBucket(buckets[len(buckets)-1], interval)
+		series, err := s.productRepo.GetStatsSeries(ctx, userID, metric, interval, buckets[missing], end)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		for i := missing; i < len(buckets); i++ {
+			points[i].Value = series[buckets[i]]
+			// The current bucket still fills up as products are created
+			ttl := 24 * time.Hour
+			if nextStatsBucket(buckets[i], interval).After(now) {
+				ttl = 5 * time.Minute
+			}
+			s.cacheService.Set(ctx, statsSeriesCacheKey(userID, metric, interval, buckets[i]), points[i].Value, ttl)
+		}
+	}
+
+	return &domain.StatsSeriesResponse{
+		Metric:   metric,
+		Interval: interval,
+		From:     buckets[0],
+		To:       nextStatsBucket(buckets[len(buckets)-1], interval),
+		Points:   points,
+	}, nil
+}
+
+// nextStatsBucket returns the start of the bucket following the one starting at bucket
+func nextStatsBucket(bucket time.Time, interval string) time.Time {
+	if interval == domain.StatsIntervalWeek {
+		return bucket.AddDate(0, 0, 7)
+	}
+	return bucket.AddDate(0, 0, 1)
+}
+
+// statsSeriesCacheKey returns the cache key of a bucket of a user's stats series
+func statsSeriesCacheKey(userID uuid.UUID, metric, interval string, bucket time.Time) string {
+	return fmt.Sprintf("user_stats_series:%s:%s:%s:%d", userID, metric, interval, bucket.Unix())
+}
+
+// ResolveCode returns the ID of the user's product with the given short code
+func (s *ProductService) ResolveCode(ctx context.Context, userID uuid.UUID, code string) (uuid.UUID, error) {
+	normalized, err := domain.NormalizeProductCode(code)
+	if err != nil {
+		return uuid.Nil, domain.ErrProductNotFound
+	}
+
+	id, err := s.productRepo.GetIDByCode(ctx, userID, normalized)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if id == nil {
+		return uuid.Nil, domain.ErrProductNotFound
+	}
+	return *id, nil
+}
+
+// Authorize loads a product and checks that the user may perform the action on it.
+// Products loaded for a change are read from the primary, never a lagging replica.
+func (s *ProductService) Authorize(ctx context.Context, id, userID uuid.UUID, action ProductAction) (*domain.Product, error) {
+	if action != ProductActionView {
+		ctx = repository.WithPrimary(ctx)
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizer.Authorize(ctx, userID, product, action); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// validateAttributes checks product attributes against the user's attribute definitions
+func (s *ProductService) validateAttributes(ctx context.Context, userID uuid.UUID, attributes domain.Attributes, draft bool) error {
+	definitions, err := s.attributeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+
+	validate := domain.ValidateAttributes
+	if draft {
+		validate = domain.ValidateDraftAttributes
+	}
+	if err := validate(attributes, definitions); err != nil {
+		return domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+	return nil
+}
+
+// normalizeAttributeFilter converts raw attribute filter values to their declared types
+func (s *ProductService) normalizeAttributeFilter(ctx context.Context, userID uuid.UUID, filter *domain.ProductFilter) error {
+	if len(filter.Attributes) == 0 {
+		return nil
+	}
+
+	definitions, err := s.attributeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+
+	raw := make(map[string]string, len(filter.Attributes))
+	for key, value := range filter.Attributes {
+		raw[key] = fmt.Sprint(value)
+	}
+
+	attributes, err := domain.CoerceAttributeFilter(raw, definitions)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+
+	filter.Attributes = attributes
+	return nil
+}
+
 // generateQueryCacheKey generates a cache key for filtered queries
 func (s *ProductService) generateQueryCacheKey(userID uuid.UUID, query domain.ProductQuery) string {
 	queryBytes, _ := json.Marshal(query)
@@ -205,15 +738,41 @@ func (s *ProductService) generateCursorQueryCacheKey(userID uuid.UUID, query dom
 	return fmt.Sprintf("user_products_cursor:%s:%s", userID, string(queryBytes))
 }
 
+// invalidateProductCache drops every cached copy of a product and its owner's aggregates
+func (s *ProductService) invalidateProductCache(ctx context.Context, product *domain.Product) {
+	s.cacheService.DeletePattern(ctx, fmt.Sprintf("product:*:%s", product.ID))
+	s.invalidateUserCache(ctx, product.UserID)
+	invalidatePublicCache(ctx, s.cacheService, product.UserID, product.ID)
+}
+
 // invalidateUserCache invalidates all cache entries for a specific user
 func (s *ProductService) invalidateUserCache(ctx context.Context, userID uuid.UUID) {
 	s.cacheService.Delete(ctx, fmt.Sprintf("user_products:%s", userID))
 
 	s.cacheService.Delete(ctx, fmt.Sprintf("user_stats:%s", userID))
 
+	s.cacheService.DeletePattern(ctx, fmt.Sprintf("user_stats_series:%s:*", userID))
+
+	s.cacheService.Delete(ctx, quotaCacheKey(userID))
+
 	pattern := fmt.Sprintf("user_products_filtered:%s:*", userID)
 	s.cacheService.DeletePattern(ctx, pattern)
 
 	pattern = fmt.Sprintf("user_products_cursor:%s:*", userID)
 	s.cacheService.DeletePattern(ctx, pattern)
 }
+
+// publish records an event of the product change and publishes it to webhooks;
+// failures are logged so they never fail the change itself
+func (s *ProductService) publish(ctx context.Context, eventType string, product, previous *domain.Product) {
+	if s.events == nil {
+		return
+	}
+	event, err := domain.NewProductEvent(eventType, product, previous)
+	if err == nil {
+		err = s.events.Record(ctx, event)
+	}
+	if err != nil {
+		log.Printf("Failed to publish %s for product %s: %v", eventType, product.ID, err)
+	}
+}
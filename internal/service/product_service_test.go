@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/mocks"
+	"products/internal/repository/memory"
+	"products/internal/service/memorycache"
+)
+
+// newCacheMock returns a cache mock keeping JSON values in a map, with the keys
+// deleted by Delete and DeletePattern
+func newCacheMock() (*mocks.Cache, *sync.Map, *[]string) {
+	var values sync.Map
+	var mu sync.Mutex
+	deleted := []string{}
+
+	cache := &mocks.Cache{
+		GetFunc: func(ctx context.Context, key string, dest interface{}) error {
+			value, ok := values.Load(key)
+			if !ok {
+				return errors.New("cache miss")
+			}
+			return json.Unmarshal(value.([]byte), dest)
+		},
+		SetFunc: func(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			values.Store(key, data)
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			values.Delete(key)
+			mu.Lock()
+			defer mu.Unlock()
+			deleted = append(deleted, key)
+			return nil
+		},
+		DeletePatternFunc: func(ctx context.Context, pattern string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			deleted = append(deleted, pattern)
+			return nil
+		},
+	}
+	return cache, &values, &deleted
+}
+
+func TestProductService_GetByIDCachesProduct(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	product := &domain.Product{ID: uuid.New(), Name: "Lamp", UserID: userID}
+
+	repo := &mocks.ProductRepository{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+			copied := *product
+			return &copied, nil
+		},
+	}
+	cache, _, _ := newCacheMock()
+	products := NewProductService(repo, nil, cache, nil, DefaultProductAuthorizer(), nil)
+
+	for i := 0; i < 2; i++ {
+		got, err := products.GetByID(ctx, product.ID, userID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.ID != product.ID || got.Name != product.Name {
+			t.Errorf("Expected product %s, got %+v", product.ID, got)
+		}
+	}
+
+	if calls := repo.CallCount("GetByID"); calls != 1 {
+		t.Errorf("Expected the second read to be served from the cache, repository was called %d times", calls)
+	}
+}
+
+func TestProductService_GetByIDForbidsOtherUsers(t *testing.T) {
+	ctx := context.Background()
+	product := &domain.Product{ID: uuid.New(), UserID: uuid.New()}
+
+	repo := &mocks.ProductRepository{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+	cache, values, _ := newCacheMock()
+	products := NewProductService(repo, nil, cache, nil, DefaultProductAuthorizer(), nil)
+
+	if _, err := products.GetByID(ctx, product.ID, uuid.New()); !errors.Is(err, domain.ErrProductForbidden) {
+		t.Fatalf("Expected ErrProductForbidden, got %v", err)
+	}
+
+	values.Range(func(key, value interface{}) bool {
+		t.Errorf("Expected nothing cached, found %s", key)
+		return true
+	})
+}
+
+func TestProductService_GetByIDs(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	first := domain.Product{ID: uuid.New(), UserID: userID, Name: "first"}
+	second := domain.Product{ID: uuid.New(), UserID: userID, Name: "second"}
+	other := domain.Product{ID: uuid.New(), UserID: uuid.New(), Name: "other"}
+	unknown := uuid.New()
+
+	var queries int
+	repo := &mocks.ProductRepository{
+		GetByIDsFunc: func(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+			queries++
+			return []domain.Product{first, other, second}, nil
+		},
+	}
+	cache, _, _ := newCacheMock()
+	products := NewProductService(repo, nil, cache, nil, DefaultProductAuthorizer(), nil)
+
+	response, err := products.GetByIDs(ctx, userID, []uuid.UUID{second.ID, other.ID, first.ID, unknown, second.ID})
+	if err != nil {
+		t.Fatalf("GetByIDs: %v", err)
+	}
+	if queries != 1 {
+		t.Errorf("Expected one query, got %d", queries)
+	}
+	if len(response.Products) != 2 || response.Products[0].ID != second.ID || response.Products[1].ID != first.ID {
+		t.Errorf("Expected the user's products in the order requested, got %+v", response.Products)
+	}
+	if len(response.Missing) != 2 || response.Missing[0] != other.ID || response.Missing[1] != unknown {
+		t.Errorf("Expected other users' and unknown products missing, got %v", response.Missing)
+	}
+
+	if _, err := products.GetByIDs(ctx, userID, make([]uuid.UUID, domain.MaxBatchGetProducts+1)); err == nil {
+		t.Error("Expected too many IDs to be rejected")
+	}
+}
+
+func TestProductService_DeleteInvalidatesOwnerCache(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	product := &domain.Product{ID: uuid.New(), UserID: userID}
+
+	repo := &mocks.ProductRepository{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+			return product, nil
+		},
+		DeleteFunc: func(ctx context.Context, id uuid.UUID) error {
+			if id != product.ID {
+				t.Errorf("Expected product %s deleted, got %s", product.ID, id)
+			}
+			return nil
+		},
+	}
+	cache, _, deleted := newCacheMock()
+	products := NewProductService(repo, nil, cache, nil, DefaultProductAuthorizer(), nil)
+
+	if err := products.Delete(ctx, product.ID, userID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	keys := strings.Join(*deleted, " ")
+	for _, key := range []string{"product:*:" + product.ID.String(), "user_products:" + userID.String(), "user_stats:" + userID.String()} {
+		if !strings.Contains(keys, key) {
+			t.Errorf("Expected %s invalidated, invalidated %s", key, keys)
+		}
+	}
+}
+
+func TestProductService_DeleteKeepsOtherUsersProducts(t *testing.T) {
+	ctx := context.Background()
+	product := &domain.Product{ID: uuid.New(), UserID: uuid.New()}
+
+	repo := &mocks.ProductRepository{
+		GetByIDFunc: func(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+	cache, _, _ := newCacheMock()
+	products := NewProductService(repo, nil, cache, nil, DefaultProductAuthorizer(), nil)
+
+	if err := products.Delete(ctx, product.ID, uuid.New()); !errors.Is(err, domain.ErrProductForbidden) {
+		t.Fatalf("Expected ErrProductForbidden, got %v", err)
+	}
+	if calls := repo.CallCount("Delete"); calls != 0 {
+		t.Errorf("Expected no deletion, repository Delete was called %d times", calls)
+	}
+}
+
+func TestProductService_GetProductStatsCachesStats(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	stats := &domain.ProductStats{
+		TotalProducts:         3,
+		MedianPrice:           12.5,
+		StockValuePercentiles: domain.StockValuePercentiles{P90: 400},
+	}
+
+	repo := &mocks.ProductRepository{
+		GetProductStatsFunc: func(ctx context.Context, id uuid.UUID) (*domain.ProductStats, error) {
+			return stats, nil
+		},
+	}
+	cache, _, _ := newCacheMock()
+	products := NewProductService(repo, nil, cache, nil, DefaultProductAuthorizer(), nil)
+
+	for i := 0; i < 2; i++ {
+		got, err := products.GetProductStats(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetProductStats: %v", err)
+		}
+		if *got != *stats {
+			t.Errorf("Expected %+v, got %+v", stats, got)
+		}
+	}
+
+	if calls := repo.CallCount("GetProductStats"); calls != 1 {
+		t.Errorf("Expected cached stats on the second call, repository was called %d times", calls)
+	}
+}
+
+func TestProductService_MemoryStores(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	if err := memory.NewUserRepository(store).Create(ctx, &domain.User{ID: userID, Email: "owner@example.com"}); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+	repo := memory.NewProductRepository(store)
+	product := &domain.Product{Name: "Lamp", Price: 20, Stock: 3, UserID: userID}
+	if err := repo.Create(ctx, product); err != nil {
+		t.Fatalf("Create product: %v", err)
+	}
+	products := NewProductService(repo, nil, memorycache.New(), nil, DefaultProductAuthorizer(), nil)
+
+	got, err := products.GetByID(ctx, product.ID, userID)
+	if err != nil || got.Name != "Lamp" {
+		t.Fatalf("Expected the lamp, got %+v, %v", got, err)
+	}
+	stats, err := products.GetProductStats(ctx, userID)
+	if err != nil || stats.TotalProducts != 1 || stats.TotalValue != 60 {
+		t.Fatalf("Expected one product worth 60, got %+v, %v", stats, err)
+	}
+
+	if err := products.Delete(ctx, product.ID, userID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := products.GetByID(ctx, product.ID, userID); err == nil {
+		t.Error("Expected the deleted product to be evicted from the cache")
+	}
+}
+
+func TestProductService_UpdateWritesOnlyChangedFields(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := memory.NewProductRepository(memory.NewStore())
+	product := &domain.Product{Name: "Lamp", Price: 20, Stock: 10, UserID: userID}
+	if err := repo.Create(ctx, product); err != nil {
+		t.Fatalf("Create product: %v", err)
+	}
+	products := NewProductService(repo, nil, memorycache.New(), nil, DefaultProductAuthorizer(), nil)
+
+	// Cache the product, then change its stock behind the service's back like an order would
+	if _, err := products.GetByID(ctx, product.ID, userID); err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if _, err := repo.DecrementStock(ctx, product.ID, 4); err != nil {
+		t.Fatalf("DecrementStock: %v", err)
+	}
+	if err := repo.Reserve(ctx, product.ID, 2); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+
+	name := "Desk lamp"
+	if err := products.Update(ctx, product.ID, &domain.UpdateProductRequest{Name: &name}, userID); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	stored, err := repo.GetByID(ctx, product.ID)
+	if err != nil || stored.Name != name || stored.Stock != 6 || stored.Price != 20 {
+		t.Fatalf("Expected only the name to change and the decremented stock to be kept, got %+v, %v", stored, err)
+	}
+
+	stock := 1
+	if err := products.Update(ctx, product.ID, &domain.UpdateProductRequest{Stock: &stock}, userID); domain.ErrorCode(err) != domain.CodeOutOfStock {
+		t.Errorf("Expected stock below the reserved stock to be refused, got %v", err)
+	}
+	stock = 2
+	if err := products.Update(ctx, product.ID, &domain.UpdateProductRequest{Stock: &stock}, userID); err != nil {
+		t.Errorf("Expected stock equal to the reserved stock to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// Notification types
+const (
+	NotificationQuotaWarning  = "quota_warning"
+	NotificationQuotaExceeded = "quota_exceeded"
+)
+
+// QuotaConfig holds product quota configuration; the product limit is set by the
+// user's plan
+type QuotaConfig struct {
+	WarningThreshold float64
+	GracePeriod      time.Duration
+}
+
+// DefaultQuotaConfig returns the default quota configuration
+func DefaultQuotaConfig() QuotaConfig {
+	return QuotaConfig{
+		WarningThreshold: 0.9,
+		GracePeriod:      7 * 24 * time.Hour,
+	}
+}
+
+// QuotaService enforces product quotas with soft warnings and a grace window
+type QuotaService struct {
+	userRepo            domain.UserRepository
+	productRepo         domain.ProductRepository
+	notificationService *NotificationService
+	cacheService        domain.Cache
+	planService         *PlanService
+	config              QuotaConfig
+}
+
+// NewQuotaService creates a new quota service
+func NewQuotaService(userRepo domain.UserRepository, productRepo domain.ProductRepository, notificationService *NotificationService, cacheService domain.Cache, planService *PlanService, config QuotaConfig) *QuotaService {
+	return &QuotaService{
+		userRepo:            userRepo,
+		productRepo:         productRepo,
+		notificationService: notificationService,
+		cacheService:        cacheService,
+		planService:         planService,
+		config:              config,
+	}
+}
+
+// Status returns the user's current quota usage
+func (s *QuotaService) Status(ctx context.Context, userID uuid.UUID) (*domain.QuotaStatus, error) {
+	cacheKey := quotaCacheKey(userID)
+	var cachedStatus domain.QuotaStatus
+	if err := s.cacheService.Get(ctx, cacheKey, &cachedStatus); err == nil {
+		return &cachedStatus, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.productRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count products: %w", err)
+	}
+
+	status := s.buildStatus(user, used)
+	s.cacheService.Set(ctx, cacheKey, status, time.Minute)
+
+	return status, nil
+}
+
+// CheckCreate verifies the user may add count products, starting the grace window
+// or emitting a warning notification when thresholds are crossed
+func (s *QuotaService) CheckCreate(ctx context.Context, userID uuid.UUID, count int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	limit := s.planService.Get(user.Plan).ProductLimit
+	if limit <= 0 {
+		return nil
+	}
+
+	used, err := s.productRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count products: %w", err)
+	}
+
+	after := used + count
+	now := time.Now()
+
+	if after > limit {
+		if user.QuotaExceededAt == nil {
+			user.QuotaExceededAt = &now
+			s.saveState(ctx, user)
+			s.notify(ctx, userID, NotificationQuotaExceeded, "Product quota exceeded",
+				fmt.Sprintf("You have exceeded your quota of %d products. New products will be rejected after %s.",
+					limit, now.Add(s.config.GracePeriod).Format(time.RFC1123)))
+		} else if graceEndsAt := user.QuotaExceededAt.Add(s.config.GracePeriod); now.After(graceEndsAt) {
+			return quotaExceededError(used, limit, graceEndsAt)
+		}
+		return nil
+	}
+
+	if float64(after) >= float64(limit)*s.config.WarningThreshold && user.QuotaWarnedAt == nil {
+		user.QuotaWarnedAt = &now
+		s.saveState(ctx, user)
+		s.notify(ctx, userID, NotificationQuotaWarning, "Approaching product quota",
+			fmt.Sprintf("You are using %d of %d products.", after, limit))
+	}
+
+	return nil
+}
+
+// Refresh clears warning and grace state once usage drops back below the thresholds
+func (s *QuotaService) Refresh(ctx context.Context, userID uuid.UUID) {
+	s.cacheService.Delete(ctx, quotaCacheKey(userID))
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || (user.QuotaWarnedAt == nil && user.QuotaExceededAt == nil) {
+		return
+	}
+
+	used, err := s.productRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	limit := s.planService.Get(user.Plan).ProductLimit
+	changed := false
+	if user.QuotaExceededAt != nil && (limit <= 0 || used <= limit) {
+		user.QuotaExceededAt = nil
+		changed = true
+	}
+	if user.QuotaWarnedAt != nil && (limit <= 0 || float64(used) < float64(limit)*s.config.WarningThreshold) {
+		user.QuotaWarnedAt = nil
+		changed = true
+	}
+
+	if changed {
+		s.saveState(ctx, user)
+	}
+}
+
+// buildStatus computes quota usage for a user
+func (s *QuotaService) buildStatus(user *domain.User, used int64) *domain.QuotaStatus {
+	limit := s.planService.Get(user.Plan).ProductLimit
+	status := &domain.QuotaStatus{
+		Plan:  user.Plan,
+		Limit: limit,
+		Used:  used,
+	}
+
+	if limit <= 0 {
+		return status
+	}
+
+	status.Percent = float64(used) / float64(limit) * 100
+	status.Warning = float64(used) >= float64(limit)*s.config.WarningThreshold
+	status.Exceeded = used > limit
+
+	if status.Exceeded && user.QuotaExceededAt != nil {
+		graceEndsAt := user.QuotaExceededAt.Add(s.config.GracePeriod)
+		status.GraceEndsAt = &graceEndsAt
+	}
+
+	return status
+}
+
+// saveState persists the user's quota state and drops the cached status
+func (s *QuotaService) saveState(ctx context.Context, user *domain.User) {
+	if err := s.userRepo.SetQuotaState(ctx, user.ID, user.QuotaWarnedAt, user.QuotaExceededAt); err != nil {
+		log.Printf("Failed to save quota state for user %s: %v", user.ID, err)
+	}
+	s.cacheService.Delete(ctx, quotaCacheKey(user.ID))
+}
+
+// notify sends a quota notification, logging failures
+func (s *QuotaService) notify(ctx context.Context, userID uuid.UUID, notificationType, title, message string) {
+	if err := s.notificationService.Notify(ctx, userID, notificationType, title, message); err != nil {
+		log.Printf("Failed to notify user %s about quota: %v", userID, err)
+	}
+}
+
+// quotaExceededError describes a rejected creation, returned when a user is over quota
+// and the grace window has elapsed
+func quotaExceededError(used, limit int64, graceEndsAt time.Time) error {
+	return domain.NewError(domain.CodeQuotaExceeded, fmt.Sprintf(
+		"product quota exceeded: %d of %d products used and the grace period ended %s; delete products or upgrade your plan to create new ones",
+		used, limit, graceEndsAt.UTC().Format(time.RFC3339)))
+}
+
+// quotaCacheKey returns the cache key of a user's quota status
+func quotaCacheKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_quota:%s", userID)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"products/internal/domain"
+)
+
+// RateLimiter enforces a fixed-window request limit per key using Redis counters
+type RateLimiter struct {
+	cacheService domain.Cache
+	namespace    string
+	limit        int64
+	window       time.Duration
+}
+
+// NewRateLimiter creates a new rate limiter allowing limit requests per window
+func NewRateLimiter(cacheService domain.Cache, namespace string, limit int64, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		cacheService: cacheService,
+		namespace:    namespace,
+		limit:        limit,
+		window:       window,
+	}
+}
+
+// Limit returns the number of requests allowed per window
+func (l *RateLimiter) Limit() int64 {
+	return l.limit
+}
+
+// Allow counts a request for key and reports whether it is within the limit,
+// how many requests remain and when the current window resets
+func (l *RateLimiter) Allow(ctx context.Context, key string) (bool, int64, time.Duration, error) {
+	return l.AllowLimit(ctx, key, l.limit)
+}
+
+// AllowLimit is Allow with a limit of its own for key, such as one set by the
+// user's plan
+func (l *RateLimiter) AllowLimit(ctx context.Context, key string, limit int64) (bool, int64, time.Duration, error) {
+	windowStart := time.Now().Truncate(l.window)
+	reset := time.Until(windowStart.Add(l.window))
+	cacheKey := fmt.Sprintf("ratelimit:%s:%s:%d", l.namespace, key, windowStart.Unix())
+
+	count, err := l.cacheService.Incr(ctx, cacheKey)
+	if err != nil {
+		return false, 0, reset, err
+	}
+	if count == 1 {
+		l.cacheService.Expire(ctx, cacheKey, l.window)
+	}
+
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return count <= limit, remaining, reset, nil
+}
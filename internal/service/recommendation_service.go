@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// relatedCacheTTL bounds how stale related products may be after other products change
+const relatedCacheTTL = 10 * time.Minute
+
+// Recommender ranks the products related to a product, most relevant first.
+// Other backends, such as a trained model, plug in by implementing this interface.
+type Recommender interface {
+	Related(ctx context.Context, product *domain.Product, limit int) ([]domain.RelatedProduct, error)
+}
+
+// AttributeRecommender relates the owner's products that share attribute values
+// with a product and are close to it in price, ranked by a repository query
+type AttributeRecommender struct {
+	productRepo domain.ProductRepository
+}
+
+// NewAttributeRecommender creates a new attribute recommender
+func NewAttributeRecommender(productRepo domain.ProductRepository) *AttributeRecommender {
+	return &AttributeRecommender{productRepo: productRepo}
+}
+
+// Related implements Recommender
+func (r *AttributeRecommender) Related(ctx context.Context, product *domain.Product, limit int) ([]domain.RelatedProduct, error) {
+	return r.productRepo.GetRelated(ctx, product, limit)
+}
+
+// RecommendationService serves the products related to a product
+type RecommendationService struct {
+	productService *ProductService
+	recommender    Recommender
+	cacheService   domain.Cache
+}
+
+// NewRecommendationService creates a new recommendation service
+func NewRecommendationService(productService *ProductService, recommender Recommender, cacheService domain.Cache) *RecommendationService {
+	return &RecommendationService{
+		productService: productService,
+		recommender:    recommender,
+		cacheService:   cacheService,
+	}
+}
+
+// Related returns up to limit products related to a product the user may view.
+// Results are cached per product and dropped whenever the product changes.
+func (s *RecommendationService) Related(ctx context.Context, productID, userID uuid.UUID, limit int) (*domain.RelatedProductsResponse, error) {
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key ends with the product ID so invalidateProductCache drops it
+	cacheKey := fmt.Sprintf("product:related:%d:%s", limit, productID)
+	var cached domain.RelatedProductsResponse
+	if err := s.cacheService.Get(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	related, err := s.recommender.Related(ctx, product, limit)
+	if err != nil {
+		return nil, err
+	}
+	if related == nil {
+		related = []domain.RelatedProduct{}
+	}
+
+	response := &domain.RelatedProductsResponse{ProductID: productID, Related: related}
+	s.cacheService.Set(ctx, cacheKey, response, relatedCacheTTL)
+	return response, nil
+}
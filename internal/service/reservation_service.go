@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// reservationHoldsKey is the sorted set of the holds of all reservations, scored
+// by when they expire
+const reservationHoldsKey = "reservation_holds"
+
+// reservationExpiryBatch is the number of expired holds released per batch
+const reservationExpiryBatch = 100
+
+// ErrReservationsUnavailable is returned while Redis, which tracks reservations, is unavailable
+var ErrReservationsUnavailable = domain.NewError(domain.CodeUnavailable, "reservations are unavailable")
+
+// ReservationService places time-limited holds on product stock. A reservation's
+// details live in Redis for its lifetime, and its hold in a sorted set by expiry;
+// whoever removes the hold from the set - confirming, releasing or the expiry
+// worker - is the one to adjust the product's reserved stock, exactly once.
+type ReservationService struct {
+	productRepo    domain.ProductRepository
+	productService *ProductService
+	cacheService   domain.Cache
+}
+
+// NewReservationService creates a new reservation service
+func NewReservationService(productRepo domain.ProductRepository, productService *ProductService, cacheService domain.Cache) *ReservationService {
+	return &ReservationService{
+		productRepo:    productRepo,
+		productService: productService,
+		cacheService:   cacheService,
+	}
+}
+
+// Reserve holds units of a product's available stock for the requested time
+func (s *ReservationService) Reserve(ctx context.Context, productID, userID uuid.UUID, req domain.StockReservationRequest) (*domain.StockReservation, error) {
+	if !s.cacheService.Available() {
+		return nil, ErrReservationsUnavailable
+	}
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionAdjustStock)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	reservation := &domain.StockReservation{
+		ID:        uuid.New(),
+		ProductID: productID,
+		UserID:    userID,
+		Quantity:  req.Quantity,
+		ExpiresAt: now.Add(req.TTL()),
+		CreatedAt: now,
+	}
+
+	if err := s.productRepo.Reserve(ctx, productID, req.Quantity); err != nil {
+		return nil, err
+	}
+	if err := s.cacheService.SetScheduled(ctx, reservationKey(reservation.ID), reservation, req.TTL(), reservationHoldsKey, reservation.Hold(), reservation.ExpiresAt); err != nil {
+		// A hold missing from Redis would never be released
+		if releaseErr := s.productRepo.ReleaseReserved(ctx, productID, req.Quantity); releaseErr != nil {
+			log.Printf("Failed to release reservation of product %s: %v", productID, releaseErr)
+		}
+		if errors.Is(err, ErrCacheUnavailable) {
+			return nil, ErrReservationsUnavailable
+		}
+		return nil, fmt.Errorf("failed to store reservation: %w", err)
+	}
+
+	s.productService.StockChanged(ctx, product, product.Stock)
+	return reservation, nil
+}
+
+// Get returns a live reservation of the user's product
+func (s *ReservationService) Get(ctx context.Context, productID, reservationID, userID uuid.UUID) (*domain.StockReservation, error) {
+	var reservation domain.StockReservation
+	if err := s.cacheService.Get(ctx, reservationKey(reservationID), &reservation); err != nil {
+		if errors.Is(err, ErrCacheUnavailable) {
+			return nil, ErrReservationsUnavailable
+		}
+		return nil, domain.ErrReservationNotFound
+	}
+	if reservation.ProductID != productID || reservation.UserID != userID {
+		return nil, domain.ErrReservationNotFound
+	}
+	return &reservation, nil
+}
+
+// Confirm ends a reservation by taking its units out of the product's stock, and
+// returns the updated product
+func (s *ReservationService) Confirm(ctx context.Context, productID, reservationID, userID uuid.UUID) (*domain.Product, error) {
+	reservation, product, err := s.end(ctx, productID, reservationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := s.productRepo.ConsumeReserved(ctx, productID, reservation.Quantity)
+	if err != nil {
+		s.release(ctx, *reservation)
+		return nil, err
+	}
+
+	s.productService.StockChanged(ctx, product, stock)
+	return s.productService.GetByID(ctx, productID, userID)
+}
+
+// Release ends a reservation, making its units available again
+func (s *ReservationService) Release(ctx context.Context, productID, reservationID, userID uuid.UUID) error {
+	reservation, product, err := s.end(ctx, productID, reservationID, userID)
+	if err != nil {
+		return err
+	}
+
+	s.release(ctx, *reservation)
+	s.productService.StockChanged(ctx, product, product.Stock)
+	return nil
+}
+
+// end takes a live reservation of the user's product out of Redis, failing with
+// ErrReservationNotFound when it has already ended
+func (s *ReservationService) end(ctx context.Context, productID, reservationID, userID uuid.UUID) (*domain.StockReservation, *domain.Product, error) {
+	reservation, err := s.Get(ctx, productID, reservationID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionAdjustStock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	removed, err := s.cacheService.Unschedule(ctx, reservationHoldsKey, reservation.Hold())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to end reservation: %w", err)
+	}
+	if !removed {
+		return nil, nil, domain.ErrReservationNotFound
+	}
+	s.cacheService.Delete(ctx, reservationKey(reservationID))
+
+	return reservation, product, nil
+}
+
+// release gives the units of an ended reservation back to the product's available
+// stock. If that fails, the hold is put back to be released by the expiry worker.
+func (s *ReservationService) release(ctx context.Context, reservation domain.StockReservation) {
+	err := s.productRepo.ReleaseReserved(ctx, reservation.ProductID, reservation.Quantity)
+	if err == nil {
+		return
+	}
+
+	log.Printf("Failed to release reservation %s: %v", reservation.ID, err)
+	if err := s.cacheService.Schedule(ctx, reservationHoldsKey, reservation.Hold(), time.Now()); err != nil {
+		log.Printf("Failed to reschedule reservation %s; %d units of product %s stay reserved: %v", reservation.ID, reservation.Quantity, reservation.ProductID, err)
+	}
+}
+
+// ReleaseExpired releases the holds of expired reservations and returns how many
+// were released
+func (s *ReservationService) ReleaseExpired(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		holds, err := s.cacheService.DueMembers(ctx, reservationHoldsKey, time.Now(), reservationExpiryBatch)
+		if err != nil {
+			return released, err
+		}
+
+		for _, hold := range holds {
+			removed, err := s.cacheService.Unschedule(ctx, reservationHoldsKey, hold)
+			if err != nil {
+				return released, err
+			}
+			if !removed {
+				continue
+			}
+
+			reservation, err := domain.ParseReservationHold(hold)
+			if err != nil {
+				log.Printf("Dropping reservation hold: %v", err)
+				continue
+			}
+			if err := s.productRepo.ReleaseReserved(ctx, reservation.ProductID, reservation.Quantity); err != nil {
+				s.cacheService.Schedule(ctx, reservationHoldsKey, hold, time.Now().Add(time.Minute))
+				return released, fmt.Errorf("failed to release reservation %s: %w", reservation.ID, err)
+			}
+			if product, err := s.productRepo.GetByID(ctx, reservation.ProductID); err == nil {
+				s.productService.StockChanged(ctx, product, product.Stock)
+			}
+			released++
+		}
+
+		if len(holds) < reservationExpiryBatch {
+			return released, nil
+		}
+	}
+}
+
+// StartExpiryWorker releases expired reservations every interval until ctx is cancelled
+func (s *ReservationService) StartExpiryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.cacheService.Available() {
+				continue
+			}
+			if _, err := s.ReleaseExpired(context.WithoutCancel(ctx)); err != nil {
+				log.Printf("Reservation expiry worker: %v", err)
+			}
+		}
+	}
+}
+
+// reservationKey returns the cache key of a reservation's details
+func reservationKey(id uuid.UUID) string {
+	return fmt.Sprintf("reservation:%s", id)
+}
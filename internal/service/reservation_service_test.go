@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+func TestReservationService_UnavailableWithoutCache(t *testing.T) {
+	reservations := NewReservationService(nil, nil, NewCacheService(nil, nil))
+	ctx := context.Background()
+
+	_, err := reservations.Reserve(ctx, uuid.New(), uuid.New(), domain.StockReservationRequest{Quantity: 1})
+	if !errors.Is(err, ErrReservationsUnavailable) {
+		t.Errorf("Expected ErrReservationsUnavailable when reserving, got %v", err)
+	}
+	if _, err := reservations.Get(ctx, uuid.New(), uuid.New(), uuid.New()); !errors.Is(err, ErrReservationsUnavailable) {
+		t.Errorf("Expected ErrReservationsUnavailable when reading, got %v", err)
+	}
+}
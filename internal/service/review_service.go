@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// ReviewService manages reviews of public products. Any user may review another
+// user's public product once; the product's owner lists and moderates its reviews.
+type ReviewService struct {
+	reviewRepo     *repository.ReviewRepository
+	productRepo    domain.ProductRepository
+	productService *ProductService
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(reviewRepo *repository.ReviewRepository, productRepo domain.ProductRepository, productService *ProductService) *ReviewService {
+	return &ReviewService{
+		reviewRepo:     reviewRepo,
+		productRepo:    productRepo,
+		productService: productService,
+	}
+}
+
+// Create reviews a public product of another user
+func (s *ReviewService) Create(ctx context.Context, productID, userID uuid.UUID, req domain.ReviewRequest) (*domain.Review, error) {
+	product, err := s.publicProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.UserID == userID {
+		return nil, domain.ErrOwnProductReview
+	}
+
+	review := &domain.Review{
+		ID:        uuid.New(),
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    req.Rating,
+		Comment:   req.Comment,
+		Status:    domain.ReviewStatusPublished,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+
+	s.productService.invalidateProductCache(ctx, product)
+	return review, nil
+}
+
+// ListPublic returns a page of the published reviews of a public product
+func (s *ReviewService) ListPublic(ctx context.Context, productID uuid.UUID, pagination domain.Pagination) (*domain.PublicReviewListResponse, error) {
+	product, err := s.publicProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.reviewRepo.GetByProductID(ctx, productID, domain.ReviewQuery{Pagination: pagination, Status: domain.ReviewStatusPublished})
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPublicReviewListResponse(product, page), nil
+}
+
+// List returns a page of every review of a product the user may view
+func (s *ReviewService) List(ctx context.Context, productID, userID uuid.UUID, query domain.ReviewQuery) (*domain.ReviewListResponse, error) {
+	if _, err := s.productService.Authorize(ctx, productID, userID, ProductActionView); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.GetByProductID(ctx, productID, query)
+}
+
+// Moderate publishes or hides a review of a product the user may update
+func (s *ReviewService) Moderate(ctx context.Context, productID, reviewID, userID uuid.UUID, status string) (*domain.Review, error) {
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.reviewRepo.SetStatus(ctx, productID, reviewID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	s.productService.invalidateProductCache(ctx, product)
+	return review, nil
+}
+
+// publicProduct returns a product listed in the public catalog
+func (s *ReviewService) publicProduct(ctx context.Context, productID uuid.UUID) (*domain.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || !product.Public || product.Status != domain.ProductStatusPublished || product.ArchivedAt != nil {
+		return nil, domain.ErrProductNotFound
+	}
+	return product, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// savedSearchAlertBatch is the number of subscribed searches checked per run
+const savedSearchAlertBatch = 100
+
+// SavedSearchService manages saved product searches and notifies subscribers of
+// new products matching them
+type SavedSearchService struct {
+	savedSearchRepo     *repository.SavedSearchRepository
+	productRepo         domain.ProductRepository
+	productService      *ProductService
+	notificationService *NotificationService
+}
+
+// NewSavedSearchService creates a new saved search service
+func NewSavedSearchService(savedSearchRepo *repository.SavedSearchRepository, productRepo domain.ProductRepository, productService *ProductService, notificationService *NotificationService) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchRepo:     savedSearchRepo,
+		productRepo:         productRepo,
+		productService:      productService,
+		notificationService: notificationService,
+	}
+}
+
+// List returns the user's saved searches by name
+func (s *SavedSearchService) List(ctx context.Context, userID uuid.UUID) ([]domain.SavedSearch, error) {
+	searches, err := s.savedSearchRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if searches == nil {
+		searches = []domain.SavedSearch{}
+	}
+	return searches, nil
+}
+
+// Create saves a search for the user
+func (s *SavedSearchService) Create(ctx context.Context, userID uuid.UUID, req domain.SavedSearchRequest) (*domain.SavedSearch, error) {
+	if err := domain.NormalizeSavedQuery(&req.Query); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	search := &domain.SavedSearch{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       req.Name,
+		Query:      req.Query,
+		Subscribed: req.Subscribed,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if req.Subscribed {
+		search.CheckedAt = &now
+	}
+	if err := s.savedSearchRepo.Create(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Get returns one of the user's saved searches
+func (s *SavedSearchService) Get(ctx context.Context, userID, id uuid.UUID) (*domain.SavedSearch, error) {
+	search, err := s.savedSearchRepo.GetByID(ctx, id)
+	if err != nil || search.UserID != userID {
+		return nil, domain.ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+// Update replaces the name, query and subscription of one of the user's saved searches
+func (s *SavedSearchService) Update(ctx context.Context, userID, id uuid.UUID, req domain.SavedSearchRequest) (*domain.SavedSearch, error) {
+	if err := domain.NormalizeSavedQuery(&req.Query); err != nil {
+		return nil, err
+	}
+	search, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if req.Subscribed && !search.Subscribed {
+		search.CheckedAt = &now
+	}
+	search.Name = req.Name
+	search.Query = req.Query
+	search.Subscribed = req.Subscribed
+	search.UpdatedAt = now
+	if err := s.savedSearchRepo.Update(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Delete removes one of the user's saved searches
+func (s *SavedSearchService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.savedSearchRepo.Delete(ctx, id)
+}
+
+// SetSubscribed subscribes the user to, or unsubscribes them from, one of their
+// saved searches. Subscribers are notified of products created after subscribing.
+func (s *SavedSearchService) SetSubscribed(ctx context.Context, userID, id uuid.UUID, subscribed bool) (*domain.SavedSearch, error) {
+	search, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if search.Subscribed == subscribed {
+		return search, nil
+	}
+
+	now := time.Now()
+	if err := s.savedSearchRepo.SetSubscribed(ctx, id, subscribed, now); err != nil {
+		return nil, err
+	}
+	search.Subscribed = subscribed
+	search.CheckedAt = &now
+	search.UpdatedAt = now
+	return search, nil
+}
+
+// CheckSubscriptions notifies the users of subscribed searches of products
+// created since the searches were last checked that match them, and returns the
+// number of notifications sent
+func (s *SavedSearchService) CheckSubscriptions(ctx context.Context) (int, error) {
+	searches, err := s.savedSearchRepo.GetSubscribed(ctx, savedSearchAlertBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load subscribed searches: %w", err)
+	}
+
+	notified := 0
+	for _, search := range searches {
+		checkedAt := time.Now()
+		matches, err := s.countNewMatches(ctx, &search)
+		if err != nil {
+			log.Printf("Failed to check saved search %s: %v", search.ID, err)
+			continue
+		}
+
+		if matches > 0 {
+			message := fmt.Sprintf("%d new products match your saved search %q", matches, search.Name)
+			if matches == 1 {
+				message = fmt.Sprintf("A new product matches your saved search %q", search.Name)
+			}
+			if err := s.notificationService.Notify(ctx, search.UserID, NotificationSavedSearchMatch, "New products match a saved search", message); err != nil {
+				log.Printf("Failed to notify %s of saved search %s: %v", search.UserID, search.ID, err)
+				continue
+			}
+			notified++
+		}
+
+		if err := s.savedSearchRepo.SetCheckedAt(ctx, search.ID, checkedAt); err != nil {
+			log.Printf("Failed to record check of saved search %s: %v", search.ID, err)
+		}
+	}
+	return notified, nil
+}
+
+// countNewMatches counts the products matching a saved search that were created
+// since it was last checked
+func (s *SavedSearchService) countNewMatches(ctx context.Context, search *domain.SavedSearch) (int64, error) {
+	since := search.CreatedAt
+	if search.CheckedAt != nil {
+		since = *search.CheckedAt
+	}
+
+	query := search.Query
+	if query.Filter.CreatedFrom == nil || query.Filter.CreatedFrom.Before(since) {
+		query.Filter.CreatedFrom = &since
+	}
+	query.Sort = []domain.SortField{}
+	query.Pagination = domain.Pagination{Page: 1, PageSize: 1}
+	query.Total = domain.TotalExact
+
+	if err := s.productService.normalizeAttributeFilter(ctx, search.UserID, &query.Filter); err != nil {
+		return 0, err
+	}
+	response, err := s.productRepo.GetProductsWithFilters(ctx, search.UserID, query)
+	if err != nil {
+		return 0, err
+	}
+	if response.Total == nil {
+		return 0, nil
+	}
+	return *response.Total, nil
+}
+
+// StartAlertWorker checks subscribed searches for new matches every interval
+// until ctx is cancelled
+func (s *SavedSearchService) StartAlertWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CheckSubscriptions(context.WithoutCancel(ctx)); err != nil {
+				log.Printf("Saved search alert worker: %v", err)
+			}
+		}
+	}
+}
@@ -2,73 +2,179 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"products/internal/domain"
 )
 
-// Session represents a user session
-type Session struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	ExpiresAt time.Time `json:"expires_at"`
-	IPAddress string    `json:"ip_address"`
-	UserAgent string    `json:"user_agent"`
-	IsActive  bool      `json:"is_active"`
-}
+// Default lifetimes of sessions and their refresh tokens after login or a token
+// refresh, for standard and remember-me logins
+const (
+	DefaultSessionDuration    = 24 * time.Hour
+	DefaultRememberMeDuration = 30 * 24 * time.Hour
+)
+
+// sessionActivityInterval is the minimum time between two recorded activities of a session
+const sessionActivityInterval = time.Minute
+
+// Policies applied when a login would exceed the concurrent session limit
+const (
+	SessionLimitEvictOldest = "evict_oldest"
+	SessionLimitReject      = "reject"
+)
+
+// ErrSessionLimitReached rejects a login of a user with the maximum number of sessions
+var ErrSessionLimitReached = domain.NewError(domain.CodeSessionLimit, "the maximum number of active sessions has been reached; log out on another device first")
 
 // SessionService manages user sessions
 type SessionService struct {
-	cacheService *CacheService
+	store domain.SessionStore
+
+	// statelessFallback accepts any validly signed token while the cache is
+	// unavailable, at the cost of logouts not taking effect until it recovers
+	statelessFallback bool
+
+	// durations of standard and remember-me sessions
+	duration           time.Duration
+	rememberMeDuration time.Duration
+
+	// sliding extends sessions to their TTL after their last activity, up to
+	// maxLifetime after they were created
+	sliding     bool
+	maxLifetime time.Duration
+
+	// sessionLimit caps the active sessions of a user, applying limitPolicy to
+	// logins beyond it; 0 allows any number
+	sessionLimit int
+	limitPolicy  string
 }
 
-// NewSessionService creates a new session service
-func NewSessionService(cacheService *CacheService) *SessionService {
+// NewSessionService creates a new session service keeping sessions in store
+func NewSessionService(store domain.SessionStore) *SessionService {
 	return &SessionService{
-		cacheService: cacheService,
+		store:              store,
+		duration:           DefaultSessionDuration,
+		rememberMeDuration: DefaultRememberMeDuration,
 	}
 }
 
-// CreateSession creates a new user session
-func (s *SessionService) CreateSession(ctx context.Context, userID, email, ipAddress, userAgent string, duration time.Duration) (*Session, error) {
-	sessionID := uuid.New().String()
-	now := time.Now()
+// SetDurations sets the lifetimes of standard and remember-me sessions
+func (s *SessionService) SetDurations(standard, rememberMe time.Duration) {
+	s.duration = standard
+	s.rememberMeDuration = rememberMe
+}
 
-	session := &Session{
-		ID:        sessionID,
-		UserID:    userID,
-		Email:     email,
-		CreatedAt: now,
-		ExpiresAt: now.Add(duration),
-		IPAddress: ipAddress,
-		UserAgent: userAgent,
-		IsActive:  true,
+// Duration returns the lifetime of new sessions
+func (s *SessionService) Duration(rememberMe bool) time.Duration {
+	if rememberMe {
+		return s.rememberMeDuration
 	}
+	return s.duration
+}
+
+// SetStatelessFallback enables or disables falling back to validating only the
+// token signature and expiry while the session cache is unavailable
+func (s *SessionService) SetStatelessFallback(enabled bool) {
+	s.statelessFallback = enabled
+}
+
+// SetSlidingExpiration makes activity keep sessions alive for their TTL after
+// each authenticated request, but no longer than maxLifetime (0 for no limit)
+// after login
+func (s *SessionService) SetSlidingExpiration(enabled bool, maxLifetime time.Duration) {
+	s.sliding = enabled
+	s.maxLifetime = maxLifetime
+}
+
+// SetSessionLimit caps the active sessions of a user at limit (0 for no limit).
+// Logins beyond it evict the oldest sessions or are rejected, according to policy.
+func (s *SessionService) SetSessionLimit(limit int, policy string) {
+	s.sessionLimit = limit
+	s.limitPolicy = policy
+}
 
-	key := fmt.Sprintf("session:%s", sessionID)
-	err := s.cacheService.Set(ctx, key, session, duration)
+// EnforceSessionLimit makes room for a new session of the user, returning the
+// sessions it revoked, or ErrSessionLimitReached when the policy rejects new
+// sessions. Logins racing on several instances may briefly exceed the limit.
+func (s *SessionService) EnforceSessionLimit(ctx context.Context, userID string) ([]domain.Session, error) {
+	if s.sessionLimit <= 0 {
+		return nil, nil
+	}
+
+	sessions, err := s.GetUserSessions(ctx, userID)
+	if s.stateless(err) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to store session: %w", err)
+		return nil, err
+	}
+	excess := len(sessions) - s.sessionLimit + 1
+	if excess <= 0 {
+		return nil, nil
 	}
+	if s.limitPolicy == SessionLimitReject {
+		return nil, ErrSessionLimitReached
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	evicted := sessions[:excess]
+	for _, session := range evicted {
+		if err := s.store.Delete(ctx, session.ID); err != nil {
+			return nil, fmt.Errorf("failed to revoke session %s: %w", session.ID, err)
+		}
+	}
+	return evicted, nil
+}
 
-	userSessionsKey := fmt.Sprintf("user_sessions:%s", userID)
-	err = s.cacheService.Set(ctx, userSessionsKey, sessionID, duration)
+// stateless reports whether err means the session cache is unavailable and the
+// stateless fallback applies; it never applies to sessions kept in PostgreSQL
+func (s *SessionService) stateless(err error) bool {
+	return s.statelessFallback && errors.Is(err, ErrCacheUnavailable)
+}
+
+// CreateSession creates a new user session, lasting longer for remember-me logins
+func (s *SessionService) CreateSession(ctx context.Context, userID, email, ipAddress, userAgent, deviceID string, rememberMe bool) (*domain.Session, error) {
+	sessionID := uuid.New().String()
+	now := time.Now()
+	duration := s.Duration(rememberMe)
+
+	session := &domain.Session{
+		ID:           sessionID,
+		UserID:       userID,
+		Email:        email,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(duration),
+		IPAddress:    ipAddress,
+		UserAgent:    userAgent,
+		DeviceID:     deviceID,
+		IsActive:     true,
+		TTL:          duration,
+		RememberMe:   rememberMe,
+		LastActiveAt: now,
+	}
+
+	err := s.store.Save(ctx, session)
+	if s.stateless(err) {
+		log.Printf("Session cache unavailable; issuing stateless session %s", sessionID)
+		return session, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to store user session index: %w", err)
+		return nil, fmt.Errorf("failed to store session: %w", err)
 	}
 
 	return session, nil
 }
 
 // GetSession retrieves a session by ID
-func (s *SessionService) GetSession(ctx context.Context, sessionID string) (*Session, error) {
-	key := fmt.Sprintf("session:%s", sessionID)
-	var session Session
-
-	err := s.cacheService.Get(ctx, key, &session)
+func (s *SessionService) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	session, err := s.store.Get(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("session not found: %w", err)
 	}
@@ -78,60 +184,82 @@ func (s *SessionService) GetSession(ctx context.Context, sessionID string) (*Ses
 		return nil, fmt.Errorf("session expired")
 	}
 
-	return &session, nil
+	return session, nil
 }
 
 // DeleteSession removes a session
 func (s *SessionService) DeleteSession(ctx context.Context, sessionID string) error {
-	key := fmt.Sprintf("session:%s", sessionID)
-
-	var session Session
-	err := s.cacheService.Get(ctx, key, &session)
-	if err == nil {
-		userSessionsKey := fmt.Sprintf("user_sessions:%s", session.UserID)
-		s.cacheService.Delete(ctx, userSessionsKey)
-	}
-
-	return s.cacheService.Delete(ctx, key)
+	return s.store.Delete(ctx, sessionID)
 }
 
 // DeleteUserSessions removes all sessions for a specific user
 func (s *SessionService) DeleteUserSessions(ctx context.Context, userID string) error {
-	pattern := fmt.Sprintf("session:*")
-	keys, err := s.cacheService.Client.Keys(ctx, pattern).Result()
+	return s.store.DeleteByUser(ctx, userID)
+}
+
+// DeleteDeviceSessions removes a user's sessions logged in from a device
+func (s *SessionService) DeleteDeviceSessions(ctx context.Context, userID, deviceID string) error {
+	sessions, err := s.store.ListByUser(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get session keys: %w", err)
+		return fmt.Errorf("failed to get sessions: %w", err)
 	}
 
-	for _, key := range keys {
-		var session Session
-		if err := s.cacheService.Get(ctx, key, &session); err == nil {
-			if session.UserID == userID {
-				s.cacheService.Delete(ctx, key)
-			}
+	for _, session := range sessions {
+		if session.DeviceID == deviceID {
+			s.store.Delete(ctx, session.ID)
 		}
 	}
-
-	userSessionsKey := fmt.Sprintf("user_sessions:%s", userID)
-	return s.cacheService.Delete(ctx, userSessionsKey)
+	return nil
 }
 
-// RefreshSession extends a session's expiration time
-func (s *SessionService) RefreshSession(ctx context.Context, sessionID string, duration time.Duration) error {
+// RefreshSession extends a session to its TTL from now and returns it. With
+// sliding expiration the session cannot outlive its maximum lifetime. While the
+// cache is unavailable and the stateless fallback applies, nil is returned.
+func (s *SessionService) RefreshSession(ctx context.Context, sessionID string) (*domain.Session, error) {
 	session, err := s.GetSession(ctx, sessionID)
+	if s.stateless(err) {
+		return nil, nil
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	session.ExpiresAt = time.Now().Add(duration)
+	now := time.Now()
+	if session.TTL <= 0 {
+		// sessions created before TTLs were stored
+		session.TTL = s.duration
+	}
+	if s.sliding {
+		session.ExpiresAt = domain.SessionActivity{At: now, Slide: true, MaxLifetime: s.maxLifetime}.ExpiresAt(session)
+	} else {
+		session.ExpiresAt = now.Add(session.TTL)
+	}
+	return session, s.store.Save(ctx, session)
+}
 
-	key := fmt.Sprintf("session:%s", sessionID)
-	return s.cacheService.Set(ctx, key, session, duration)
+// RecordActivity stores the current time as the session's last activity and, with
+// sliding expiration, extends the session. Writes are throttled to one per
+// sessionActivityInterval across all API instances.
+func (s *SessionService) RecordActivity(ctx context.Context, sessionID string) error {
+	err := s.store.TouchActivity(ctx, sessionID, domain.SessionActivity{
+		At:          time.Now(),
+		Interval:    sessionActivityInterval,
+		Slide:       s.sliding,
+		MaxLifetime: s.maxLifetime,
+	})
+	if errors.Is(err, ErrCacheUnavailable) {
+		// Nothing can be recorded until the cache recovers
+		return nil
+	}
+	return err
 }
 
 // IsSessionValid checks if a session is valid and active
 func (s *SessionService) IsSessionValid(ctx context.Context, sessionID string) (bool, error) {
 	session, err := s.GetSession(ctx, sessionID)
+	if s.stateless(err) {
+		return true, nil
+	}
 	if err != nil {
 		return false, nil
 	}
@@ -141,42 +269,78 @@ func (s *SessionService) IsSessionValid(ctx context.Context, sessionID string) (
 
 // GetActiveSessionsCount returns the number of active sessions for a user
 func (s *SessionService) GetActiveSessionsCount(ctx context.Context, userID string) (int64, error) {
-	pattern := fmt.Sprintf("session:*")
-	keys, err := s.cacheService.Client.Keys(ctx, pattern).Result()
+	sessions, err := s.GetUserSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(sessions)), nil
+}
+
+// GetUserSessions returns all active sessions for a user
+func (s *SessionService) GetUserSessions(ctx context.Context, userID string) ([]domain.Session, error) {
+	stored, err := s.store.ListByUser(ctx, userID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get session keys: %w", err)
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
 	}
 
-	count := int64(0)
-	for _, key := range keys {
-		var session Session
-		if err := s.cacheService.Get(ctx, key, &session); err == nil {
-			if session.UserID == userID && session.IsActive && time.Now().Before(session.ExpiresAt) {
-				count++
-			}
+	var sessions []domain.Session
+	for _, session := range stored {
+		if session.IsActive && time.Now().Before(session.ExpiresAt) {
+			sessions = append(sessions, session)
 		}
 	}
 
-	return count, nil
+	return sessions, nil
 }
 
-// GetUserSessions returns all active sessions for a user
-func (s *SessionService) GetUserSessions(ctx context.Context, userID string) ([]Session, error) {
-	pattern := fmt.Sprintf("session:*")
-	keys, err := s.cacheService.Client.Keys(ctx, pattern).Result()
+// Revoke invalidates key, a token or session identifier, for ttl
+func (s *SessionService) Revoke(ctx context.Context, key string, ttl time.Duration) error {
+	return s.store.Revoke(ctx, key, ttl)
+}
+
+// IsRevoked reports whether key has been revoked. While the cache is unavailable
+// and the stateless fallback applies, nothing is revoked.
+func (s *SessionService) IsRevoked(ctx context.Context, key string) (bool, error) {
+	revoked, err := s.store.IsRevoked(ctx, key)
+	if s.stateless(err) {
+		return false, nil
+	}
+	return revoked, err
+}
+
+// PurgeExpired deletes expired sessions and revocations from stores that do not
+// expire them on their own
+func (s *SessionService) PurgeExpired(ctx context.Context) error {
+	purger, ok := s.store.(interface {
+		PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+	})
+	if !ok {
+		return nil
+	}
+	purged, err := purger.PurgeExpired(ctx, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get session keys: %w", err)
+		return err
+	}
+	if purged > 0 {
+		log.Printf("Sessions: purged %d expired sessions and revocations", purged)
 	}
+	return nil
+}
+
+// StartCleanupWorker periodically purges expired sessions until ctx is cancelled
+func (s *SessionService) StartCleanupWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	var sessions []Session
-	for _, key := range keys {
-		var session Session
-		if err := s.cacheService.Get(ctx, key, &session); err == nil {
-			if session.UserID == userID && session.IsActive && time.Now().Before(session.ExpiresAt) {
-				sessions = append(sessions, session)
-			}
+	for {
+		if err := s.PurgeExpired(context.WithoutCancel(ctx)); err != nil {
+			log.Printf("Session cleanup worker: %v", err)
 		}
-	}
 
-	return sessions, nil
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
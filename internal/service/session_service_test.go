@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"products/internal/service/memorycache"
+)
+
+func TestSessionService_CacheStore(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewSessionService(NewCacheSessionStore(memorycache.New()))
+
+	laptop, err := sessions.CreateSession(ctx, "user-1", "jane@example.com", "10.0.0.1", "Firefox", "laptop", false)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	phone, _ := sessions.CreateSession(ctx, "user-1", "jane@example.com", "10.0.0.2", "Safari", "phone", false)
+	other, _ := sessions.CreateSession(ctx, "user-2", "john@example.com", "10.0.0.3", "Chrome", "", false)
+
+	if valid, _ := sessions.IsSessionValid(ctx, laptop.ID); !valid {
+		t.Error("Expected the new session to be valid")
+	}
+	if count, _ := sessions.GetActiveSessionsCount(ctx, "user-1"); count != 2 {
+		t.Errorf("Expected 2 sessions of user-1, got %d", count)
+	}
+
+	// Activity is recorded at most once per interval
+	if err := sessions.RecordActivity(ctx, laptop.ID); err != nil {
+		t.Fatalf("Failed to record activity: %v", err)
+	}
+	recorded, _ := sessions.GetSession(ctx, laptop.ID)
+	if err := sessions.RecordActivity(ctx, laptop.ID); err != nil {
+		t.Fatalf("Failed to record activity: %v", err)
+	}
+	if again, _ := sessions.GetSession(ctx, laptop.ID); !again.LastActiveAt.Equal(recorded.LastActiveAt) {
+		t.Error("Expected the second activity within the interval to be skipped")
+	}
+
+	if err := sessions.Revoke(ctx, "blacklist:token", time.Hour); err != nil {
+		t.Fatalf("Failed to revoke: %v", err)
+	}
+	if revoked, _ := sessions.IsRevoked(ctx, "blacklist:token"); !revoked {
+		t.Error("Expected the key to be revoked")
+	}
+	if revoked, _ := sessions.IsRevoked(ctx, "blacklist:other"); revoked {
+		t.Error("Expected another key not to be revoked")
+	}
+
+	if err := sessions.DeleteDeviceSessions(ctx, "user-1", "phone"); err != nil {
+		t.Fatalf("Failed to delete device sessions: %v", err)
+	}
+	if valid, _ := sessions.IsSessionValid(ctx, phone.ID); valid {
+		t.Error("Expected the phone session to be deleted")
+	}
+	if valid, _ := sessions.IsSessionValid(ctx, laptop.ID); !valid {
+		t.Error("Expected the laptop session to remain")
+	}
+
+	if err := sessions.DeleteUserSessions(ctx, "user-1"); err != nil {
+		t.Fatalf("Failed to delete user sessions: %v", err)
+	}
+	if count, _ := sessions.GetActiveSessionsCount(ctx, "user-1"); count != 0 {
+		t.Errorf("Expected no sessions of user-1, got %d", count)
+	}
+	if valid, _ := sessions.IsSessionValid(ctx, other.ID); !valid {
+		t.Error("Expected the sessions of other users to remain")
+	}
+}
+
+func TestSessionService_SlidingExpiration(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewSessionService(NewCacheSessionStore(memorycache.New()))
+	sessions.SetDurations(time.Hour, 2*time.Hour)
+	sessions.SetSlidingExpiration(true, 90*time.Minute)
+
+	session, err := sessions.CreateSession(ctx, "user-1", "jane@example.com", "10.0.0.1", "Firefox", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	// Pretend the session was created 50 minutes ago
+	session.CreatedAt = session.CreatedAt.Add(-50 * time.Minute)
+	session.ExpiresAt = session.ExpiresAt.Add(-50 * time.Minute)
+	if err := sessions.store.Save(ctx, session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	if err := sessions.RecordActivity(ctx, session.ID); err != nil {
+		t.Fatalf("Failed to record activity: %v", err)
+	}
+
+	extended, err := sessions.GetSession(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if limit := session.CreatedAt.Add(90 * time.Minute); !extended.ExpiresAt.Equal(limit) {
+		t.Errorf("Expected activity to extend the session up to %s, got %s", limit, extended.ExpiresAt)
+	}
+
+	// Refreshing cannot extend the session past its maximum lifetime either
+	refreshed, err := sessions.RefreshSession(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to refresh session: %v", err)
+	}
+	if !refreshed.ExpiresAt.Equal(extended.ExpiresAt) {
+		t.Errorf("Expected the session capped at %s, got %s", extended.ExpiresAt, refreshed.ExpiresAt)
+	}
+}
+
+func TestSessionService_RememberMe(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewSessionService(NewCacheSessionStore(memorycache.New()))
+
+	standard, _ := sessions.CreateSession(ctx, "user-1", "jane@example.com", "", "", "", false)
+	remembered, _ := sessions.CreateSession(ctx, "user-1", "jane@example.com", "", "", "", true)
+
+	if standard.TTL != DefaultSessionDuration || remembered.TTL != DefaultRememberMeDuration || !remembered.RememberMe {
+		t.Fatalf("Unexpected session lifetimes %s and %s", standard.TTL, remembered.TTL)
+	}
+	if until := time.Until(remembered.ExpiresAt); until < DefaultRememberMeDuration-time.Minute {
+		t.Errorf("Expected the remember-me session to last 30 days, expires in %s", until)
+	}
+
+	refreshed, err := sessions.RefreshSession(ctx, remembered.ID)
+	if err != nil {
+		t.Fatalf("Failed to refresh session: %v", err)
+	}
+	if until := time.Until(refreshed.ExpiresAt); until < DefaultRememberMeDuration-time.Minute {
+		t.Errorf("Expected the refresh to keep the remember-me lifetime, expires in %s", until)
+	}
+}
+
+func TestSessionService_EnforceSessionLimit(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewSessionService(NewCacheSessionStore(memorycache.New()))
+	sessions.SetSessionLimit(2, SessionLimitEvictOldest)
+
+	first, _ := sessions.CreateSession(ctx, "user-1", "jane@example.com", "", "", "", false)
+	time.Sleep(time.Millisecond)
+	second, _ := sessions.CreateSession(ctx, "user-1", "jane@example.com", "", "", "", false)
+
+	evicted, err := sessions.EnforceSessionLimit(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Failed to enforce the limit: %v", err)
+	}
+	if len(evicted) != 1 || evicted[0].ID != first.ID {
+		t.Fatalf("Expected the oldest session evicted, got %+v", evicted)
+	}
+	if valid, _ := sessions.IsSessionValid(ctx, first.ID); valid {
+		t.Error("Expected the evicted session to be invalid")
+	}
+	if valid, _ := sessions.IsSessionValid(ctx, second.ID); !valid {
+		t.Error("Expected the newer session to remain")
+	}
+	if evicted, _ := sessions.EnforceSessionLimit(ctx, "user-2"); len(evicted) != 0 {
+		t.Errorf("Expected no eviction below the limit, got %+v", evicted)
+	}
+
+	sessions.SetSessionLimit(1, SessionLimitReject)
+	if _, err := sessions.EnforceSessionLimit(ctx, "user-1"); !errors.Is(err, ErrSessionLimitReached) {
+		t.Errorf("Expected ErrSessionLimitReached, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"products/internal/domain"
+)
+
+// Session store backends
+const (
+	SessionStoreRedis    = "redis"
+	SessionStorePostgres = "postgres"
+)
+
+// CacheSessionStore keeps sessions in the cache, as session:<id> keys expiring
+// with the session
+type CacheSessionStore struct {
+	cacheService domain.Cache
+}
+
+// NewCacheSessionStore creates a session store on the cache
+func NewCacheSessionStore(cacheService domain.Cache) *CacheSessionStore {
+	return &CacheSessionStore{cacheService: cacheService}
+}
+
+func sessionKey(id string) string {
+	return fmt.Sprintf("session:%s", id)
+}
+
+// Save stores the session until it expires
+func (s *CacheSessionStore) Save(ctx context.Context, session *domain.Session) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return s.Delete(ctx, session.ID)
+	}
+	return s.cacheService.Set(ctx, sessionKey(session.ID), session, ttl)
+}
+
+// Get retrieves a session by ID
+func (s *CacheSessionStore) Get(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	if err := s.cacheService.Get(ctx, sessionKey(id), &session); err != nil {
+		if errors.Is(err, ErrCacheUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrSessionNotFound, err)
+	}
+	return &session, nil
+}
+
+// Delete removes a session
+func (s *CacheSessionStore) Delete(ctx context.Context, id string) error {
+	return s.cacheService.Delete(ctx, sessionKey(id))
+}
+
+// ListByUser scans the stored sessions for the user's
+func (s *CacheSessionStore) ListByUser(ctx context.Context, userID string) ([]domain.Session, error) {
+	keys, err := s.cacheService.Keys(ctx, "session:*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session keys: %w", err)
+	}
+
+	var sessions []domain.Session
+	for _, key := range keys {
+		var session domain.Session
+		if err := s.cacheService.Get(ctx, key, &session); err == nil && session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+// DeleteByUser removes all sessions of a user
+func (s *CacheSessionStore) DeleteByUser(ctx context.Context, userID string) error {
+	sessions, err := s.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		s.cacheService.Delete(ctx, sessionKey(session.ID))
+	}
+	return nil
+}
+
+// TouchActivity records the activity of a session, extending the expiry of its
+// key along with the session. A session_activity:<id> key throttles writes across
+// all API instances.
+func (s *CacheSessionStore) TouchActivity(ctx context.Context, id string, activity domain.SessionActivity) error {
+	acquired, err := s.cacheService.SetNX(ctx, fmt.Sprintf("session_activity:%s", id), true, activity.Interval)
+	if err != nil || !acquired {
+		return err
+	}
+
+	session, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	session.LastActiveAt = activity.At
+	session.ExpiresAt = activity.ExpiresAt(session)
+	return s.Save(ctx, session)
+}
+
+// Revoke stores key for ttl
+func (s *CacheSessionStore) Revoke(ctx context.Context, key string, ttl time.Duration) error {
+	return s.cacheService.Set(ctx, key, true, ttl)
+}
+
+// IsRevoked reports whether key is stored
+func (s *CacheSessionStore) IsRevoked(ctx context.Context, key string) (bool, error) {
+	return s.cacheService.Exists(ctx, key)
+}
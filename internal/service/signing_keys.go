@@ -0,0 +1,202 @@
+package service
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"products/internal/domain"
+)
+
+// LegacyKeyID identifies the key that verifies tokens issued without a kid,
+// before signing keys could be rotated
+const LegacyKeyID = "default"
+
+// Supported token signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// SigningKeyConfig configures one token signing key. HS256 keys take a secret;
+// RS256 keys take a PEM private key, or only a PEM public key for retired keys
+// that still verify tokens but no longer sign them.
+type SigningKeyConfig struct {
+	ID         string `json:"kid"`
+	Algorithm  string `json:"alg"`
+	Secret     string `json:"secret,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	PublicKey  string `json:"public_key,omitempty"`
+}
+
+// SigningKeysConfig configures the token signing keys. Current is the kid of
+// the key new tokens are signed with; it defaults to the first key.
+type SigningKeysConfig struct {
+	Current string             `json:"current"`
+	Keys    []SigningKeyConfig `json:"keys"`
+}
+
+// signingKey is a parsed token signing key
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// SigningKeys signs tokens with the current key and verifies them with any
+// configured key, by the kid in the token header
+type SigningKeys struct {
+	current *signingKey
+	keys    map[string]*signingKey
+	order   []string
+}
+
+// NewSigningKeys parses the configured signing keys
+func NewSigningKeys(config SigningKeysConfig) (*SigningKeys, error) {
+	if len(config.Keys) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+
+	keys := &SigningKeys{keys: make(map[string]*signingKey, len(config.Keys))}
+	for _, keyConfig := range config.Keys {
+		if keyConfig.ID == "" {
+			return nil, errors.New("signing key without kid")
+		}
+		if _, exists := keys.keys[keyConfig.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key %q", keyConfig.ID)
+		}
+
+		key, err := parseSigningKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key %q: %w", keyConfig.ID, err)
+		}
+		keys.keys[key.id] = key
+		keys.order = append(keys.order, key.id)
+	}
+
+	current := config.Current
+	if current == "" {
+		current = config.Keys[0].ID
+	}
+	key, ok := keys.keys[current]
+	if !ok {
+		return nil, fmt.Errorf("current signing key %q is not configured", current)
+	}
+	if key.signKey == nil {
+		return nil, fmt.Errorf("current signing key %q has no private key", current)
+	}
+	keys.current = key
+
+	return keys, nil
+}
+
+// LoadSigningKeysConfig reads a JSON signing keys config from a file, such as
+// one mounted by a secret manager
+func LoadSigningKeysConfig(path string) (SigningKeysConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SigningKeysConfig{}, fmt.Errorf("failed to read signing keys: %w", err)
+	}
+	return ParseSigningKeysConfig(data)
+}
+
+// ParseSigningKeysConfig decodes a JSON signing keys config
+func ParseSigningKeysConfig(data []byte) (SigningKeysConfig, error) {
+	var config SigningKeysConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return SigningKeysConfig{}, fmt.Errorf("invalid signing keys: %w", err)
+	}
+	return config, nil
+}
+
+// parseSigningKey parses the key material of one configured key
+func parseSigningKey(config SigningKeyConfig) (*signingKey, error) {
+	key := &signingKey{id: config.ID}
+	switch config.Algorithm {
+	case AlgorithmHS256, "":
+		if config.Secret == "" {
+			return nil, errors.New("HS256 keys require a secret")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = []byte(config.Secret)
+		key.verifyKey = []byte(config.Secret)
+	case AlgorithmRS256:
+		key.method = jwt.SigningMethodRS256
+		switch {
+		case config.PrivateKey != "":
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(config.PrivateKey))
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		case config.PublicKey != "":
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(config.PublicKey))
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey = publicKey
+		default:
+			return nil, errors.New("RS256 keys require a private or public key")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", config.Algorithm)
+	}
+	return key, nil
+}
+
+// Sign signs claims with the current key, naming it in the kid header
+func (k *SigningKeys) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.current.method, claims)
+	token.Header["kid"] = k.current.id
+	return token.SignedString(k.current.signKey)
+}
+
+// Parse verifies a token with the key named by its kid header. Tokens without
+// a kid are verified with the legacy key, if it is still configured.
+func (k *SigningKeys) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		id := LegacyKeyID
+		if kid, ok := token.Header["kid"]; ok {
+			if id, ok = kid.(string); !ok {
+				return nil, errors.New("invalid key ID")
+			}
+		}
+
+		key, ok := k.keys[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", id)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verifyKey, nil
+	})
+}
+
+// JWKS returns the public keys of the RS256 keys, in configuration order.
+// HS256 secrets are never published.
+func (k *SigningKeys) JWKS() domain.JSONWebKeySet {
+	set := domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+	for _, id := range k.order {
+		publicKey, ok := k.keys[id].verifyKey.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		set.Keys = append(set.Keys, domain.JSONWebKey{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: AlgorithmRS256,
+			KeyID:     id,
+			Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		})
+	}
+	return set
+}
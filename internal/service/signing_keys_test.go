@@ -0,0 +1,159 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func generateRSAKeyPEM(t *testing.T) (string, string) {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+}
+
+func TestSigningKeys_Rotation(t *testing.T) {
+	before, err := NewSigningKeys(SigningKeysConfig{Keys: []SigningKeyConfig{{ID: "2026-04", Algorithm: AlgorithmHS256, Secret: "old"}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	oldToken, err := before.Sign(jwt.MapClaims{"user_id": "1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	after, err := NewSigningKeys(SigningKeysConfig{
+		Current: "2026-10",
+		Keys: []SigningKeyConfig{
+			{ID: "2026-04", Algorithm: AlgorithmHS256, Secret: "old"},
+			{ID: "2026-10", Algorithm: AlgorithmHS256, Secret: "new"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	newToken, err := after.Sign(jwt.MapClaims{"user_id": "1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for name, token := range map[string]string{"old": oldToken, "new": newToken} {
+		if _, err := after.Parse(token); err != nil {
+			t.Errorf("Expected %s token to verify, got %v", name, err)
+		}
+	}
+	parsed, _ := after.Parse(newToken)
+	if kid := parsed.Header["kid"]; kid != "2026-10" {
+		t.Errorf("Expected token signed with the current key, got kid %v", kid)
+	}
+
+	// Removing the old key ends the validity of its tokens
+	if _, err := before.Parse(newToken); err == nil {
+		t.Error("Expected token of an unknown key to be rejected")
+	}
+}
+
+func TestSigningKeys_LegacyTokens(t *testing.T) {
+	keys, err := NewSigningKeys(SigningKeysConfig{
+		Keys: []SigningKeyConfig{
+			{ID: "2026-10", Algorithm: AlgorithmHS256, Secret: "new"},
+			{ID: LegacyKeyID, Algorithm: AlgorithmHS256, Secret: "legacy"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	legacy, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "1"}).SignedString([]byte("legacy"))
+	if _, err := keys.Parse(legacy); err != nil {
+		t.Errorf("Expected token without kid to verify with the legacy key, got %v", err)
+	}
+
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "1"}).SignedString([]byte("new"))
+	if _, err := keys.Parse(forged); err == nil {
+		t.Error("Expected token without kid signed by another key to be rejected")
+	}
+}
+
+func TestSigningKeys_RS256(t *testing.T) {
+	privateKey, publicKey := generateRSAKeyPEM(t)
+	_, retiredKey := generateRSAKeyPEM(t)
+
+	keys, err := NewSigningKeys(SigningKeysConfig{
+		Keys: []SigningKeyConfig{
+			{ID: "rsa-2", Algorithm: AlgorithmRS256, PrivateKey: privateKey},
+			{ID: "rsa-1", Algorithm: AlgorithmRS256, PublicKey: retiredKey},
+			{ID: LegacyKeyID, Algorithm: AlgorithmHS256, Secret: "legacy"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	token, err := keys.Sign(jwt.MapClaims{"user_id": "1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	parsed, err := keys.Parse(token)
+	if err != nil {
+		t.Fatalf("Expected token to verify, got %v", err)
+	}
+	if parsed.Method.Alg() != AlgorithmRS256 {
+		t.Errorf("Expected RS256 token, got %s", parsed.Method.Alg())
+	}
+
+	// A token claiming an RSA key but signed with HMAC is rejected
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "1"})
+	confused.Header["kid"] = "rsa-2"
+	confusedToken, _ := confused.SignedString([]byte(publicKey))
+	if _, err := keys.Parse(confusedToken); err == nil {
+		t.Error("Expected token with mismatched algorithm to be rejected")
+	}
+
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Expected the 2 RSA keys to be published, got %d", len(jwks.Keys))
+	}
+	if jwks.Keys[0].KeyID != "rsa-2" || jwks.Keys[1].KeyID != "rsa-1" {
+		t.Errorf("Expected keys in configuration order, got %s, %s", jwks.Keys[0].KeyID, jwks.Keys[1].KeyID)
+	}
+	if jwks.Keys[0].KeyType != "RSA" || jwks.Keys[0].Exponent != "AQAB" {
+		t.Errorf("Unexpected key %+v", jwks.Keys[0])
+	}
+}
+
+func TestNewSigningKeys_Invalid(t *testing.T) {
+	_, publicKey := generateRSAKeyPEM(t)
+
+	tests := []struct {
+		name   string
+		config SigningKeysConfig
+	}{
+		{"no keys", SigningKeysConfig{}},
+		{"missing kid", SigningKeysConfig{Keys: []SigningKeyConfig{{Secret: "s"}}}},
+		{"duplicate kid", SigningKeysConfig{Keys: []SigningKeyConfig{{ID: "a", Secret: "s"}, {ID: "a", Secret: "t"}}}},
+		{"missing secret", SigningKeysConfig{Keys: []SigningKeyConfig{{ID: "a", Algorithm: AlgorithmHS256}}}},
+		{"unsupported algorithm", SigningKeysConfig{Keys: []SigningKeyConfig{{ID: "a", Algorithm: "none", Secret: "s"}}}},
+		{"unknown current", SigningKeysConfig{Current: "b", Keys: []SigningKeyConfig{{ID: "a", Secret: "s"}}}},
+		{"current without private key", SigningKeysConfig{Keys: []SigningKeyConfig{{ID: "a", Algorithm: AlgorithmRS256, PublicKey: publicKey}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigningKeys(tt.config); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
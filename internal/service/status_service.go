@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/resilience"
+)
+
+// Windows and caching of the public status page
+const (
+	StatusUptimeWindow  = 24 * time.Hour
+	StatusLatencyWindow = time.Hour
+	StatusCacheTTL      = 30 * time.Second
+)
+
+// statusBuckets is the number of minutes kept, covering the longest window
+const statusBuckets = int(StatusUptimeWindow / time.Minute)
+
+// statusBucket holds the metrics of one minute
+type statusBucket struct {
+	minute       int64
+	requests     int64
+	serverErrors int64
+	latency      time.Duration
+	sampled      bool
+	down         bool
+}
+
+// StatusService computes the public status page from metrics kept in memory: the
+// latency and status of requests, and samples of the circuit breakers of the
+// API's dependencies, in per-minute buckets over StatusUptimeWindow
+type StatusService struct {
+	dependencies []*resilience.Executor
+	now          func() time.Time
+
+	mu       sync.Mutex
+	buckets  [statusBuckets]statusBucket
+	cached   *domain.StatusResponse
+	cachedAt time.Time
+}
+
+// NewStatusService creates a new status service reporting on dependencies
+func NewStatusService(dependencies []*resilience.Executor) *StatusService {
+	return &StatusService{
+		dependencies: dependencies,
+		now:          time.Now,
+	}
+}
+
+// bucket returns the bucket of the minute of t, resetting it when it last held
+// an older minute; s.mu must be held
+func (s *StatusService) bucket(t time.Time) *statusBucket {
+	minute := t.Unix() / 60
+	b := &s.buckets[minute%int64(statusBuckets)]
+	if b.minute != minute {
+		*b = statusBucket{minute: minute}
+	}
+	return b
+}
+
+// Record counts a finished request, its latency and response status
+func (s *StatusService) Record(latency time.Duration, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.bucket(s.now())
+	b.requests++
+	b.latency += latency
+	if status >= 500 {
+		b.serverErrors++
+	}
+}
+
+// Sample records whether every required dependency is up in the current minute;
+// a minute is down when any sample of it was
+func (s *StatusService) Sample() {
+	_, down := s.dependencyHealth()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.bucket(s.now())
+	b.sampled = true
+	b.down = b.down || down
+}
+
+// StartSampler samples the dependencies every interval until ctx is done
+func (s *StatusService) StartSampler(ctx context.Context, interval time.Duration) {
+	s.Sample()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sample()
+		}
+	}
+}
+
+// dependencyHealth reports the health of each dependency and whether a required
+// one is down
+func (s *StatusService) dependencyHealth() ([]domain.DependencyHealth, bool) {
+	health := make([]domain.DependencyHealth, 0, len(s.dependencies))
+	down := false
+	for _, dependency := range s.dependencies {
+		status := dependency.Status()
+		dependencyHealth := domain.DependencyHealth{Name: status.Name, Status: domain.DependencyUp, Optional: status.Optional}
+		switch status.State {
+		case resilience.StateOpen:
+			dependencyHealth.Status = domain.DependencyDown
+			down = down || !status.Optional
+		case resilience.StateHalfOpen:
+			dependencyHealth.Status = domain.DependencyDegraded
+		}
+		health = append(health, dependencyHealth)
+	}
+	return health, down
+}
+
+// Status returns the data of the public status page, computed at most once per
+// StatusCacheTTL
+func (s *StatusService) Status() *domain.StatusResponse {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && now.Sub(s.cachedAt) < StatusCacheTTL {
+		return s.cached
+	}
+
+	dependencies, down := s.dependencyHealth()
+	response := &domain.StatusResponse{
+		Status:        domain.ServiceOperational,
+		UptimePercent: 100,
+		UptimeWindow:  "24h",
+		LatencyWindow: "1h",
+		Dependencies:  dependencies,
+		UpdatedAt:     now.UTC(),
+	}
+	for _, dependency := range dependencies {
+		if dependency.Status != domain.DependencyUp {
+			response.Status = domain.ServiceDegraded
+		}
+	}
+	if down {
+		response.Status = domain.ServiceOutage
+	}
+
+	currentMinute := now.Unix() / 60
+	var sampled, upMinutes, serverErrors int64
+	var latency time.Duration
+	for _, b := range s.buckets {
+		age := currentMinute - b.minute
+		if b.minute == 0 || age < 0 || age >= int64(statusBuckets) {
+			continue
+		}
+		if b.sampled {
+			sampled++
+			if !b.down {
+				upMinutes++
+			}
+		}
+		if age < int64(StatusLatencyWindow/time.Minute) {
+			response.Requests += b.requests
+			serverErrors += b.serverErrors
+			latency += b.latency
+		}
+	}
+	if sampled > 0 {
+		response.UptimePercent = round2(float64(upMinutes) / float64(sampled) * 100)
+	}
+	if response.Requests > 0 {
+		response.AverageLatencyMs = round2(float64(latency.Microseconds()) / 1000 / float64(response.Requests))
+		response.ErrorRate = math.Round(float64(serverErrors)/float64(response.Requests)*10000) / 10000
+	}
+
+	s.cached, s.cachedAt = response, now
+	return response
+}
+
+// round2 rounds to two decimal places
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
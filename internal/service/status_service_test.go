@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"products/internal/domain"
+	"products/internal/resilience"
+)
+
+func TestStatusService_Status(t *testing.T) {
+	database := resilience.NewExecutor("database", resilience.Policy{BreakerThreshold: 1, BreakerCooldown: time.Hour}, func(error) bool { return true })
+	redis := resilience.NewExecutor("redis", resilience.Policy{BreakerThreshold: 1, BreakerCooldown: time.Hour}, func(error) bool { return true })
+	redis.Optional = true
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	service := NewStatusService([]*resilience.Executor{database, redis})
+	service.now = func() time.Time { return now }
+
+	// three healthy minutes an hour apart, with requests older than the latency window
+	for i := 0; i < 3; i++ {
+		service.Sample()
+		service.Record(100*time.Millisecond, http.StatusOK)
+		now = now.Add(time.Hour)
+	}
+	service.Record(40*time.Millisecond, http.StatusOK)
+	service.Record(20*time.Millisecond, http.StatusInternalServerError)
+
+	// the database goes down for a minute
+	database.DoOnce(context.Background(), func(context.Context) error { return errors.New("connection refused") })
+	service.Sample()
+
+	status := service.Status()
+	if status.Status != domain.ServiceOutage || status.Dependencies[0].Status != domain.DependencyDown {
+		t.Errorf("Expected an outage while the database is down, got %+v", status)
+	}
+	if status.UptimePercent != 75 {
+		t.Errorf("Expected 3 of 4 minutes up, got %v", status.UptimePercent)
+	}
+	if status.Requests != 2 || status.AverageLatencyMs != 30 || status.ErrorRate != 0.5 {
+		t.Errorf("Expected the last hour's 2 requests, got %+v", status)
+	}
+
+	// cached for 30 seconds
+	service.Record(time.Second, http.StatusOK)
+	now = now.Add(10 * time.Second)
+	if cached := service.Status(); cached.Requests != 2 {
+		t.Errorf("Expected the cached status, got %d requests", cached.Requests)
+	}
+	now = now.Add(30 * time.Second)
+	if fresh := service.Status(); fresh.Requests != 3 {
+		t.Errorf("Expected a fresh status after 30 seconds, got %d requests", fresh.Requests)
+	}
+}
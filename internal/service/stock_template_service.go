@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// maxLowStockProducts limits the number of products listed as low on stock
+const maxLowStockProducts = 500
+
+// ErrStockTemplateNotFound is returned for categories without a stock template
+var ErrStockTemplateNotFound = domain.NotFoundError(domain.CodeNotFound, "stock template not found")
+
+// StockTemplateService manages per-category stock templates and resolves the stock
+// levels products inherit from them
+type StockTemplateService struct {
+	templateRepo   *repository.StockTemplateRepository
+	productRepo    domain.ProductRepository
+	productService *ProductService
+}
+
+// NewStockTemplateService creates a new stock template service
+func NewStockTemplateService(templateRepo *repository.StockTemplateRepository, productRepo domain.ProductRepository, productService *ProductService) *StockTemplateService {
+	return &StockTemplateService{
+		templateRepo:   templateRepo,
+		productRepo:    productRepo,
+		productService: productService,
+	}
+}
+
+// List returns the user's stock templates
+func (s *StockTemplateService) List(ctx context.Context, userID uuid.UUID) ([]domain.StockTemplate, error) {
+	templates, err := s.templateRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []domain.StockTemplate{}
+	}
+	return templates, nil
+}
+
+// Define creates or replaces the stock template of a category
+func (s *StockTemplateService) Define(ctx context.Context, userID uuid.UUID, category string, req domain.StockTemplateRequest) (*domain.StockTemplate, error) {
+	if err := domain.ValidateStockCategory(category); err != nil {
+		return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+
+	now := time.Now()
+	template := &domain.StockTemplate{
+		ID:                uuid.New(),
+		UserID:            userID,
+		Category:          category,
+		LowStockThreshold: req.LowStockThreshold,
+		ReorderQuantity:   req.ReorderQuantity,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.templateRepo.Upsert(ctx, template); err != nil {
+		return nil, err
+	}
+
+	// Low-stock counts in the cached stats depend on the template
+	s.productService.invalidateUserCache(ctx, userID)
+
+	return s.templateRepo.GetByCategory(ctx, userID, category)
+}
+
+// Delete removes the stock template of a category; its products fall back to the defaults
+func (s *StockTemplateService) Delete(ctx context.Context, userID uuid.UUID, category string) error {
+	deleted, err := s.templateRepo.Delete(ctx, userID, category)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrStockTemplateNotFound
+	}
+
+	s.productService.invalidateUserCache(ctx, userID)
+	return nil
+}
+
+// Levels returns the stock levels that apply to a product the user may view
+func (s *StockTemplateService) Levels(ctx context.Context, userID, productID uuid.UUID) (*domain.StockLevels, error) {
+	product, err := s.productService.Authorize(ctx, productID, userID, ProductActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	// Templates and preferences belong to the product's owner
+	var template *domain.StockTemplate
+	if category := domain.ProductCategory(product); category != "" {
+		template, err = s.templateRepo.GetByCategory(ctx, product.UserID, category)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	levels := domain.ResolveStockLevels(product, template, product.User.Preferences.LowStockThreshold)
+	return &levels, nil
+}
+
+// LowStock returns the user's products below their low-stock threshold with the
+// quantity to reorder, lowest stock first. Given a location, it returns the products
+// low on stock at that location.
+func (s *StockTemplateService) LowStock(ctx context.Context, userID uuid.UUID, locationID *uuid.UUID) ([]domain.LowStockProduct, error) {
+	products, err := s.productRepo.GetLowStock(ctx, userID, locationID, maxLowStockProducts)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := s.templateRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byCategory := make(map[string]*domain.StockTemplate, len(templates))
+	for i := range templates {
+		byCategory[templates[i].Category] = &templates[i]
+	}
+
+	result := make([]domain.LowStockProduct, 0, len(products))
+	for i := range products {
+		product := &products[i]
+		result = append(result, domain.LowStockProduct{
+			ID:     product.ID,
+			Name:   product.Name,
+			Stock:  product.Stock,
+			Levels: domain.ResolveStockLevels(product, byCategory[domain.ProductCategory(product)], product.User.Preferences.LowStockThreshold),
+		})
+	}
+	return result, nil
+}
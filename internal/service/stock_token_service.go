@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// Stock token lifetimes
+const (
+	DefaultStockTokenTTL = 12 * time.Hour
+	MaxStockTokenTTL     = 7 * 24 * time.Hour
+)
+
+// stockTokenType marks tokens that may only adjust the stock of one product
+const stockTokenType = "stock"
+
+// stockTokenRevocationTTL is how long the revocation time of a product's stock
+// tokens, stored with the product, is cached
+const stockTokenRevocationTTL = 10 * time.Minute
+
+// ErrInvalidStockToken is returned for malformed, expired or revoked stock tokens
+var ErrInvalidStockToken = domain.NewError(domain.CodeInvalidToken, "invalid or expired stock token")
+
+// stockTokenClaims are the verified contents of a stock token
+type stockTokenClaims struct {
+	productID uuid.UUID
+	userID    uuid.UUID
+}
+
+// StockTokenService issues signed per-product tokens that allow adjusting stock without logging in
+type StockTokenService struct {
+	productService  *ProductService
+	productRepo     domain.ProductRepository
+	locationService *LocationService
+	cacheService    domain.Cache
+	signingKey      []byte
+	defaultTTL      time.Duration
+}
+
+// NewStockTokenService creates a new stock token service.
+// The signing key is derived from the JWT secret so stock tokens are never valid access tokens.
+func NewStockTokenService(productService *ProductService, productRepo domain.ProductRepository, locationService *LocationService, cacheService domain.Cache, jwtSecret string, defaultTTL time.Duration) *StockTokenService {
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte("stock-token"))
+
+	return &StockTokenService{
+		productService:  productService,
+		productRepo:     productRepo,
+		locationService: locationService,
+		cacheService:    cacheService,
+		signingKey:      mac.Sum(nil),
+		defaultTTL:      defaultTTL,
+	}
+}
+
+// Issue creates a stock token for a product whose stock the user may adjust
+func (s *StockTokenService) Issue(ctx context.Context, productID, userID uuid.UUID, ttl time.Duration) (*domain.StockTokenResponse, error) {
+	if ttl <= 0 {
+		ttl = s.defaultTTL
+	}
+	if ttl > MaxStockTokenTTL {
+		return nil, domain.NewError(domain.CodeValidationFailed, fmt.Sprintf("ttl must not exceed %s", MaxStockTokenTTL))
+	}
+
+	if _, err := s.productService.Authorize(ctx, productID, userID, ProductActionAdjustStock); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"product_id": productID.String(),
+		"user_id":    userID.String(),
+		"iat":        now.Unix(),
+		"exp":        expiresAt.Unix(),
+		"type":       stockTokenType,
+	})
+
+	signed, err := token.SignedString(s.signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return &domain.StockTokenResponse{
+		Token:     signed,
+		ProductID: productID,
+		ExpiresAt: expiresAt,
+		URL:       "/api/v1/quick/stock?token=" + signed,
+	}, nil
+}
+
+// RevokeAll invalidates every stock token issued so far for a product whose stock
+// the user may adjust. The revocation is stored with the product; the cached copy
+// is replaced, or dropped when that fails.
+func (s *StockTokenService) RevokeAll(ctx context.Context, productID, userID uuid.UUID) error {
+	if _, err := s.productService.Authorize(ctx, productID, userID, ProductActionAdjustStock); err != nil {
+		return err
+	}
+
+	revokedAt := time.Now()
+	if err := s.productRepo.RevokeStockTokens(ctx, productID, revokedAt); err != nil {
+		return fmt.Errorf("failed to revoke stock tokens: %w", err)
+	}
+
+	key := stockTokenRevocationKey(productID)
+	if err := s.cacheService.Set(ctx, key, revokedAt.Unix(), stockTokenRevocationTTL); err != nil {
+		s.cacheService.Delete(ctx, key)
+	}
+	return nil
+}
+
+// revokedAt returns the Unix time up to which the stock tokens of a product are
+// revoked, 0 when they never were. It reads the cache, falling back to the product
+// on a miss or while the cache is unavailable.
+func (s *StockTokenService) revokedAt(ctx context.Context, productID uuid.UUID) (int64, error) {
+	key := stockTokenRevocationKey(productID)
+	var revokedAt int64
+	if err := s.cacheService.Get(ctx, key, &revokedAt); err == nil {
+		return revokedAt, nil
+	}
+
+	stored, err := s.productRepo.GetStockTokensRevokedAt(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	if stored != nil {
+		revokedAt = stored.Unix()
+	}
+	s.cacheService.Set(ctx, key, revokedAt, stockTokenRevocationTTL)
+	return revokedAt, nil
+}
+
+// Get returns the product a stock token grants access to
+func (s *StockTokenService) Get(ctx context.Context, token string) (*domain.QuickStockResponse, error) {
+	claims, err := s.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productService.GetByID(ctx, claims.productID, claims.userID)
+	if err != nil {
+		return nil, ErrInvalidStockToken
+	}
+
+	return &domain.QuickStockResponse{
+		ProductID: product.ID,
+		Name:      product.Name,
+		Stock:     product.Stock,
+	}, nil
+}
+
+// Adjust applies a stock delta authorized by a stock token, to the stock at a location
+// when one is given, and returns the owner it acted for
+func (s *StockTokenService) Adjust(ctx context.Context, token string, delta int, locationID *uuid.UUID) (*domain.QuickStockResponse, uuid.UUID, error) {
+	claims, err := s.verify(ctx, token)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	var stock int
+	if locationID != nil {
+		var adjusted *domain.LocationStockResponse
+		adjusted, err = s.locationService.AdjustStock(ctx, claims.productID, claims.userID, *locationID, delta)
+		if adjusted != nil {
+			stock = adjusted.Stock
+		}
+	} else {
+		stock, err = s.productService.AdjustStock(ctx, claims.productID, claims.userID, delta)
+	}
+	if err != nil {
+		return nil, claims.userID, err
+	}
+
+	product, err := s.productService.GetByID(ctx, claims.productID, claims.userID)
+	if err != nil {
+		return nil, claims.userID, err
+	}
+
+	return &domain.QuickStockResponse{
+		ProductID: product.ID,
+		Name:      product.Name,
+		Stock:     stock,
+	}, claims.userID, nil
+}
+
+// verify checks a stock token's signature, type, expiry and revocation
+func (s *StockTokenService) verify(ctx context.Context, tokenString string) (*stockTokenClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.signingKey, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidStockToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["type"] != stockTokenType {
+		return nil, ErrInvalidStockToken
+	}
+
+	productIDStr, _ := claims["product_id"].(string)
+	userIDStr, _ := claims["user_id"].(string)
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return nil, ErrInvalidStockToken
+	}
+
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		return nil, ErrInvalidStockToken
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, ErrInvalidStockToken
+	}
+
+	// Tokens whose revocation cannot be checked are refused
+	revokedAt, err := s.revokedAt(ctx, productID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return nil, ErrInvalidStockToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stock token revocation: %w", err)
+	}
+	if issuedAt.Unix() <= revokedAt {
+		return nil, ErrInvalidStockToken
+	}
+
+	return &stockTokenClaims{
+		productID: productID,
+		userID:    userID,
+	}, nil
+}
+
+// stockTokenRevocationKey returns the cache key holding when a product's stock tokens were revoked
+func stockTokenRevocationKey(productID uuid.UUID) string {
+	return fmt.Sprintf("stock_token_revoked:%s", productID)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository/memory"
+	"products/internal/service/memorycache"
+)
+
+// signStockToken signs a stock token issued at issuedAt
+func signStockToken(t *testing.T, service *StockTokenService, productID, userID uuid.UUID, issuedAt time.Time) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"product_id": productID.String(),
+		"user_id":    userID.String(),
+		"iat":        issuedAt.Unix(),
+		"exp":        time.Now().Add(time.Hour).Unix(),
+		"type":       stockTokenType,
+	}).SignedString(service.signingKey)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestStockTokenService_RevocationOutlivesCache(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	repo := memory.NewProductRepository(memory.NewStore())
+	product := &domain.Product{ID: uuid.New(), UserID: userID, Name: "Lamp"}
+	if err := repo.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		cache domain.Cache
+	}{
+		{"cache evicted", memorycache.New()},
+		{"cache unavailable", NewCacheService(nil, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewStockTokenService(nil, repo, nil, tt.cache, "secret", time.Hour)
+			issuedAt := time.Now().Add(-time.Hour)
+			token := signStockToken(t, service, product.ID, userID, issuedAt)
+
+			if err := repo.RevokeStockTokens(ctx, product.ID, issuedAt.Add(-time.Minute)); err != nil {
+				t.Fatalf("Failed to revoke: %v", err)
+			}
+			if _, err := service.verify(ctx, token); err != nil {
+				t.Fatalf("Expected a token issued after the revocation to be valid, got %v", err)
+			}
+
+			if err := repo.RevokeStockTokens(ctx, product.ID, issuedAt.Add(time.Minute)); err != nil {
+				t.Fatalf("Failed to revoke: %v", err)
+			}
+			tt.cache.Delete(ctx, stockTokenRevocationKey(product.ID))
+			if _, err := service.verify(ctx, token); !errors.Is(err, ErrInvalidStockToken) {
+				t.Errorf("Expected the revoked token to be refused, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStockTokenService_UnknownProduct(t *testing.T) {
+	service := NewStockTokenService(nil, memory.NewProductRepository(memory.NewStore()), nil, memorycache.New(), "secret", time.Hour)
+	token := signStockToken(t, service, uuid.New(), uuid.New(), time.Now())
+
+	if _, err := service.verify(context.Background(), token); !errors.Is(err, ErrInvalidStockToken) {
+		t.Errorf("Expected a token of an unknown product to be refused, got %v", err)
+	}
+}
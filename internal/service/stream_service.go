@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+// ErrStreamUnavailable is returned when real-time updates cannot be subscribed to
+var ErrStreamUnavailable = domain.NewError(domain.CodeUnavailable, "real-time updates are unavailable; please retry later")
+
+// StreamService delivers events and notifications to the open event streams of
+// their user, on whichever API instance the streams are connected to
+type StreamService struct {
+	broker       domain.Broker
+	eventService *EventService
+	closed       context.Context
+	close        context.CancelFunc
+}
+
+// NewStreamService creates a new stream service replaying missed events from
+// eventService. A nil broker disables streams.
+func NewStreamService(broker domain.Broker, eventService *EventService) *StreamService {
+	closed, close := context.WithCancel(context.Background())
+	return &StreamService{
+		broker:       broker,
+		eventService: eventService,
+		closed:       closed,
+		close:        close,
+	}
+}
+
+// streamChannel returns the broker channel carrying a user's stream events
+func streamChannel(userID uuid.UUID) string {
+	return "stream:" + userID.String()
+}
+
+// PublishEvent sends a recorded event to its user's streams
+func (s *StreamService) PublishEvent(ctx context.Context, event *domain.Event) error {
+	streamEvent, err := domain.NewEventStreamEvent(*event)
+	if err != nil {
+		return err
+	}
+	return s.publish(ctx, event.UserID, streamEvent)
+}
+
+// PublishNotification sends a stored notification to its user's streams
+func (s *StreamService) PublishNotification(ctx context.Context, notification *domain.Notification) error {
+	streamEvent, err := domain.NewNotificationStreamEvent(notification)
+	if err != nil {
+		return err
+	}
+	return s.publish(ctx, notification.UserID, streamEvent)
+}
+
+func (s *StreamService) publish(ctx context.Context, userID uuid.UUID, event domain.StreamEvent) error {
+	if s.broker == nil {
+		return nil
+	}
+
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := s.broker.Publish(ctx, streamChannel(userID), message); err != nil {
+		return fmt.Errorf("failed to publish stream event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe returns the events published for the user until ctx is done or the
+// service is closed, when the returned channel is closed
+func (s *StreamService) Subscribe(ctx context.Context, userID uuid.UUID) (<-chan domain.StreamEvent, error) {
+	if s.broker == nil {
+		return nil, ErrStreamUnavailable
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(s.closed, cancel)
+
+	messages, err := s.broker.Subscribe(ctx, streamChannel(userID))
+	if err != nil {
+		stop()
+		cancel()
+		return nil, ErrStreamUnavailable
+	}
+
+	events := make(chan domain.StreamEvent)
+	go func() {
+		defer close(events)
+		defer stop()
+		defer cancel()
+
+		for message := range messages {
+			var event domain.StreamEvent
+			if err := json.Unmarshal(message, &event); err != nil {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// Replay returns a page of the user's logged events after the given sequence,
+// reporting whether more remain
+func (s *StreamService) Replay(ctx context.Context, userID uuid.UUID, after int64) ([]domain.StreamEvent, bool, error) {
+	page, err := s.eventService.Query(ctx, userID, domain.EventFilter{After: after, Limit: domain.MaxEventPageSize})
+	if err != nil {
+		return nil, false, err
+	}
+
+	events := make([]domain.StreamEvent, 0, len(page.Events))
+	for _, event := range page.Events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, false, err
+		}
+		events = append(events, domain.StreamEvent{Sequence: event.Sequence, Type: event.Type, Data: data})
+	}
+	return events, page.HasMore, nil
+}
+
+// Close ends every open subscription, so streams let the server shut down
+func (s *StreamService) Close() {
+	s.close()
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stripeAPIURL is the base URL of the Stripe API
+const stripeAPIURL = "https://api.stripe.com/v1"
+
+// stripeTimeout bounds a request to the Stripe API
+const stripeTimeout = 10 * time.Second
+
+// stripeSignatureTolerance is how old a signed Stripe webhook may be
+const stripeSignatureTolerance = 5 * time.Minute
+
+// ErrInvalidStripeSignature is returned for webhooks not signed with the webhook secret
+var ErrInvalidStripeSignature = errors.New("invalid Stripe signature")
+
+// StripeClient calls the Stripe API with a secret key
+type StripeClient struct {
+	apiURL    string
+	secretKey string
+	client    *http.Client
+}
+
+// NewStripeClient creates a new Stripe client
+func NewStripeClient(secretKey string) *StripeClient {
+	return &StripeClient{
+		apiURL:    stripeAPIURL,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: stripeTimeout},
+	}
+}
+
+// CreateCheckoutSession creates a Checkout Session and returns the URL of its payment page
+func (c *StripeClient) CreateCheckoutSession(ctx context.Context, params url.Values) (string, error) {
+	var session struct {
+		URL string `json:"url"`
+	}
+	if err := c.post(ctx, "/checkout/sessions", params, &session); err != nil {
+		return "", fmt.Errorf("failed to create checkout session: %w", err)
+	}
+	return session.URL, nil
+}
+
+// CreatePortalSession creates a customer portal session for the customer and
+// returns its URL
+func (c *StripeClient) CreatePortalSession(ctx context.Context, customerID, returnURL string) (string, error) {
+	params := url.Values{"customer": {customerID}}
+	if returnURL != "" {
+		params.Set("return_url", returnURL)
+	}
+
+	var session struct {
+		URL string `json:"url"`
+	}
+	if err := c.post(ctx, "/billing_portal/sessions", params, &session); err != nil {
+		return "", fmt.Errorf("failed to create portal session: %w", err)
+	}
+	return session.URL, nil
+}
+
+// post sends a form-encoded request to the Stripe API and decodes the response into dest
+func (c *StripeClient) post(ctx context.Context, path string, params url.Values, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+path, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		return fmt.Errorf("Stripe returned status %d: %s", resp.StatusCode, failure.Error.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+// VerifyStripeSignature checks the Stripe-Signature header of a webhook: one of
+// its v1 signatures must be the HMAC-SHA256 of "<t>.<payload>" with the webhook
+// secret, and its timestamp t must be recent
+func VerifyStripeSignature(payload []byte, header, secret string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidStripeSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return fmt.Errorf("%w: timestamp outside the tolerance", ErrInvalidStripeSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidStripeSignature
+}
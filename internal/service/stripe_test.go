@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// signStripePayload builds a Stripe-Signature header for the payload
+func signStripePayload(payload []byte, secret string, at time.Time) string {
+	timestamp := fmt.Sprintf("%d", at.Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"invoice.paid"}`)
+	now := time.Now()
+	header := signStripePayload(payload, "whsec_test", now)
+
+	if err := VerifyStripeSignature(payload, header, "whsec_test", now); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+	if err := VerifyStripeSignature(payload, "v1=deadbeef,"+header, "whsec_test", now); err != nil {
+		t.Errorf("Expected any matching v1 signature to be accepted, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload []byte
+		header  string
+		secret  string
+		now     time.Time
+	}{
+		{"tampered payload", []byte(`{"id":"evt_2"}`), header, "whsec_test", now},
+		{"wrong secret", payload, header, "whsec_other", now},
+		{"stale timestamp", payload, header, "whsec_test", now.Add(10 * time.Minute)},
+		{"missing signature", payload, fmt.Sprintf("t=%d", now.Unix()), "whsec_test", now},
+		{"empty header", payload, "", "whsec_test", now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyStripeSignature(tt.payload, tt.header, tt.secret, tt.now)
+			if !errors.Is(err, ErrInvalidStripeSignature) {
+				t.Errorf("Expected ErrInvalidStripeSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStripeClient_CreateCheckoutSession(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "sk_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/checkout/sessions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		r.ParseForm()
+		received = r.PostForm
+		w.Write([]byte(`{"id":"cs_1","url":"https://checkout.stripe.com/c/cs_1"}`))
+	}))
+	defer server.Close()
+
+	client := NewStripeClient("sk_test")
+	client.apiURL = server.URL
+
+	checkoutURL, err := client.CreateCheckoutSession(context.Background(), url.Values{"mode": {"subscription"}})
+	if err != nil {
+		t.Fatalf("Expected a checkout session, got %v", err)
+	}
+	if checkoutURL != "https://checkout.stripe.com/c/cs_1" {
+		t.Errorf("Expected the session URL, got %q", checkoutURL)
+	}
+	if received.Get("mode") != "subscription" {
+		t.Errorf("Expected the parameters to be form encoded, got %v", received)
+	}
+
+	client.secretKey = "sk_wrong"
+	if _, err := client.CreateCheckoutSession(context.Background(), url.Values{}); err == nil {
+		t.Error("Expected an error for a rejected request")
+	}
+}
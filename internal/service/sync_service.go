@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// ErrSyncDeviceNotFound is returned for sync devices that do not exist or belong to another user
+var ErrSyncDeviceNotFound = domain.NotFoundError(domain.CodeNotFound, "sync device not found")
+
+// SyncService manages the devices registered for offline sync and the product
+// changes they pull
+type SyncService struct {
+	deviceRepo *repository.SyncDeviceRepository
+	syncRepo   *repository.ProductSyncRepository
+}
+
+// NewSyncService creates a new sync service
+func NewSyncService(deviceRepo *repository.SyncDeviceRepository, syncRepo *repository.ProductSyncRepository) *SyncService {
+	return &SyncService{
+		deviceRepo: deviceRepo,
+		syncRepo:   syncRepo,
+	}
+}
+
+// ListDevices returns the user's sync devices
+func (s *SyncService) ListDevices(ctx context.Context, userID uuid.UUID) ([]domain.SyncDevice, error) {
+	devices, err := s.deviceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if devices == nil {
+		devices = []domain.SyncDevice{}
+	}
+	return devices, nil
+}
+
+// RegisterDevice registers a new device, which starts with a full sync from token 0
+func (s *SyncService) RegisterDevice(ctx context.Context, userID uuid.UUID, req domain.SyncDeviceRequest) (*domain.SyncDevice, error) {
+	count, err := s.deviceRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxSyncDevices {
+		return nil, domain.ConflictError(domain.CodeConflict, fmt.Sprintf("at most %d sync devices are allowed", domain.MaxSyncDevices))
+	}
+
+	now := time.Now()
+	device := &domain.SyncDevice{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Platform:  req.Platform,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.deviceRepo.Create(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// DeleteDevice removes one of the user's sync devices
+func (s *SyncService) DeleteDevice(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getDevice(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.deviceRepo.Delete(ctx, id)
+}
+
+// Changes returns up to limit of the user's product changes after since, or after
+// the device's last acknowledged token when since is nil. Requesting the changes
+// after a token acknowledges that the device has applied everything up to it.
+func (s *SyncService) Changes(ctx context.Context, userID, deviceID uuid.UUID, since *int64, limit int) (*domain.SyncChangesResponse, error) {
+	device, err := s.getDevice(ctx, userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	token := device.Token
+	if since != nil {
+		if err := domain.ValidateSyncToken(*since); err != nil {
+			return nil, domain.NewError(domain.CodeValidationFailed, err.Error())
+		}
+		token = *since
+	}
+
+	syncs, products, err := s.syncRepo.ChangesSince(ctx, userID, token, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load changes: %w", err)
+	}
+
+	if err := s.deviceRepo.Acknowledge(ctx, device.ID, token, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to record sync: %w", err)
+	}
+
+	return domain.NewSyncChangesResponse(token, limit, syncs, products), nil
+}
+
+// getDevice loads a sync device owned by the user
+func (s *SyncService) getDevice(ctx context.Context, userID, id uuid.UUID) (*domain.SyncDevice, error) {
+	device, err := s.deviceRepo.GetByID(ctx, id)
+	if err != nil || device.UserID != userID {
+		return nil, ErrSyncDeviceNotFound
+	}
+	return device, nil
+}
+
+// ProductChanges returns the IDs of the user's products created, updated and
+// deleted after since
+func (s *SyncService) ProductChanges(ctx context.Context, userID uuid.UUID, since time.Time) (*domain.ProductChangesResponse, error) {
+	until := time.Now().Add(-domain.ProductChangesSettle)
+	if until.Before(since) {
+		until = since
+	}
+
+	changes, err := s.syncRepo.ChangedAfter(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load changes: %w", err)
+	}
+
+	return domain.NewProductChangesResponse(since, until.UTC(), changes), nil
+}
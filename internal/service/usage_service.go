@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/repository"
+)
+
+// usageTTL is how long a day's usage counters are kept in Redis; they are rolled
+// up to Postgres well before they expire
+const usageTTL = 72 * time.Hour
+
+// maxUsageRange bounds the period returned by a single usage request
+const maxUsageRange = 366 * 24 * time.Hour
+
+// Usage counter fields
+const (
+	usageFieldRequests = "requests"
+	usageFieldBytesIn  = "bytes_in"
+	usageFieldBytesOut = "bytes_out"
+)
+
+// UsageService counts each user's API requests and bandwidth. Counts are
+// accumulated in memory, flushed to per-day counters in Redis and rolled up to
+// Postgres, where they are kept for billing and abuse investigations.
+type UsageService struct {
+	usageRepo    *repository.UsageRepository
+	cacheService domain.Cache
+
+	mu      sync.Mutex
+	pending map[usageKey]domain.UsageTotals
+}
+
+// usageKey identifies a user's usage on a day
+type usageKey struct {
+	userID uuid.UUID
+	date   time.Time
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(usageRepo *repository.UsageRepository, cacheService domain.Cache) *UsageService {
+	return &UsageService{
+		usageRepo:    usageRepo,
+		cacheService: cacheService,
+		pending:      make(map[usageKey]domain.UsageTotals),
+	}
+}
+
+// Record counts a request of the user without blocking on Redis
+func (s *UsageService) Record(userID uuid.UUID, bytesIn, bytesOut int64) {
+	key := usageKey{userID: userID, date: truncateToDay(time.Now())}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	totals := s.pending[key]
+	totals.Requests++
+	totals.BytesIn += max(bytesIn, 0)
+	totals.BytesOut += max(bytesOut, 0)
+	s.pending[key] = totals
+}
+
+// Flush adds the counts recorded since the last flush to the counters in Redis.
+// Counts that cannot be flushed are dropped.
+func (s *UsageService) Flush(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageKey]domain.UsageTotals)
+	s.mu.Unlock()
+
+	var failed int
+	for key, totals := range pending {
+		err := s.cacheService.IncrHash(ctx, usageCacheKey(key.userID, key.date), map[string]int64{
+			usageFieldRequests: totals.Requests,
+			usageFieldBytesIn:  totals.BytesIn,
+			usageFieldBytesOut: totals.BytesOut,
+		}, usageUsersCacheKey(key.date), key.userID.String(), usageTTL)
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Printf("Usage: failed to flush the usage of %d users", failed)
+	}
+}
+
+// RollUp copies yesterday's and today's counters from Redis to Postgres
+func (s *UsageService) RollUp(ctx context.Context) error {
+	today := truncateToDay(time.Now())
+	for _, date := range []time.Time{today.AddDate(0, 0, -1), today} {
+		userIDs, err := s.cacheService.SetMembers(ctx, usageUsersCacheKey(date))
+		if err != nil {
+			return fmt.Errorf("failed to list users with usage: %w", err)
+		}
+
+		usage := make([]domain.APIUsage, 0, len(userIDs))
+		for _, value := range userIDs {
+			userID, err := uuid.Parse(value)
+			if err != nil {
+				continue
+			}
+			counters, err := s.cacheService.GetHashInts(ctx, usageCacheKey(userID, date))
+			if err != nil {
+				return err
+			}
+			usage = append(usage, newAPIUsage(userID, date, counters))
+		}
+
+		if err := s.usageRepo.Upsert(ctx, usage); err != nil {
+			return fmt.Errorf("failed to store usage: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartRollupWorker flushes recorded counts every interval and rolls them up to
+// Postgres every hour until ctx is cancelled, flushing once more on the way out
+func (s *UsageService) StartRollupWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastRollUp time.Time
+	for {
+		jobCtx := context.WithoutCancel(ctx)
+		s.Flush(jobCtx)
+		if time.Since(lastRollUp) >= time.Hour {
+			if err := s.RollUp(jobCtx); err != nil {
+				log.Printf("Usage rollup worker: %v", err)
+			}
+			lastRollUp = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			s.Flush(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetUsage returns the user's daily usage between from and to. Days still
+// counted in Redis are read from it, so today's usage is current.
+func (s *UsageService) GetUsage(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.UsageResponse, error) {
+	from, to = truncateToDay(from), truncateToDay(to)
+	if to.Before(from) {
+		return nil, errors.New("'from' must not be after 'to'")
+	}
+	if to.Sub(from) > maxUsageRange {
+		return nil, errors.New("requested range is too large")
+	}
+
+	stored, err := s.usageRepo.GetRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make(map[time.Time]domain.APIUsage, len(stored))
+	for _, day := range stored {
+		days[truncateToDay(day.Date)] = day
+	}
+
+	today := truncateToDay(time.Now())
+	for _, date := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if date.Before(from) || date.After(to) {
+			continue
+		}
+		counters, err := s.cacheService.GetHashInts(ctx, usageCacheKey(userID, date))
+		if err != nil || len(counters) == 0 {
+			continue
+		}
+		days[date] = newAPIUsage(userID, date, counters)
+	}
+
+	usage := make([]domain.APIUsage, 0, len(days))
+	for _, day := range days {
+		usage = append(usage, day)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Date.Before(usage[j].Date) })
+
+	return domain.NewUsageResponse(userID, from, to, usage), nil
+}
+
+// newAPIUsage builds a day's usage from its Redis counters
+func newAPIUsage(userID uuid.UUID, date time.Time, counters map[string]int64) domain.APIUsage {
+	return domain.APIUsage{
+		UserID:    userID,
+		Date:      date,
+		Requests:  counters[usageFieldRequests],
+		BytesIn:   counters[usageFieldBytesIn],
+		BytesOut:  counters[usageFieldBytesOut],
+		UpdatedAt: time.Now(),
+	}
+}
+
+// usageCacheKey returns the cache key of a user's usage counters on a day
+func usageCacheKey(userID uuid.UUID, date time.Time) string {
+	return fmt.Sprintf("usage:%s:%s", date.Format(time.DateOnly), userID)
+}
+
+// usageUsersCacheKey returns the cache key of the set of users with usage on a day
+func usageUsersCacheKey(date time.Time) string {
+	return fmt.Sprintf("usage_users:%s", date.Format(time.DateOnly))
+}
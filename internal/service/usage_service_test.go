@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+func TestUsageService_Record(t *testing.T) {
+	service := NewUsageService(nil, NewCacheService(nil, nil))
+	userID := uuid.New()
+
+	service.Record(userID, 120, 2048)
+	service.Record(userID, -1, 512)
+	service.Record(uuid.New(), 0, -1)
+
+	key := usageKey{userID: userID, date: truncateToDay(time.Now())}
+	want := domain.UsageTotals{Requests: 2, BytesIn: 120, BytesOut: 2560}
+	if got := service.pending[key]; got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if len(service.pending) != 2 {
+		t.Errorf("Expected the usage of 2 users, got %d", len(service.pending))
+	}
+
+	// Counts that cannot be flushed without Redis are dropped
+	service.Flush(context.Background())
+	if len(service.pending) != 0 {
+		t.Errorf("Expected flushed counts to be cleared, got %+v", service.pending)
+	}
+}
+
+func TestUsageCacheKeys(t *testing.T) {
+	userID := uuid.MustParse("3f0c2b1a-9d4e-4c6b-8a7f-1e2d3c4b5a69")
+	date := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	if key := usageCacheKey(userID, date); key != "usage:2026-10-17:3f0c2b1a-9d4e-4c6b-8a7f-1e2d3c4b5a69" {
+		t.Errorf("Unexpected usage key %q", key)
+	}
+	if key := usageUsersCacheKey(date); key != "usage_users:2026-10-17" {
+		t.Errorf("Unexpected usage users key %q", key)
+	}
+}
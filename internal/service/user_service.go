@@ -6,36 +6,45 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"products/internal/domain"
-	"products/internal/repository"
 )
 
 // UserService implements the user service interface
 type UserService struct {
-	userRepo       *repository.UserRepository
+	userRepo       domain.UserRepository
 	sessionService *SessionService
-	jwtSecret      string
+	events         *EventService
+	signingKeys    *SigningKeys
+	loginGuard     *LoginGuard
+	devices        *LoginDeviceService
+	loginHistory   *LoginHistoryService
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo *repository.UserRepository, sessionService *SessionService, jwtSecret string) *UserService {
+func NewUserService(userRepo domain.UserRepository, sessionService *SessionService, events *EventService, signingKeys *SigningKeys, loginGuard *LoginGuard, devices *LoginDeviceService, loginHistory *LoginHistoryService) *UserService {
 	return &UserService{
 		userRepo:       userRepo,
 		sessionService: sessionService,
-		jwtSecret:      jwtSecret,
+		events:         events,
+		signingKeys:    signingKeys,
+		loginGuard:     loginGuard,
+		devices:        devices,
+		loginHistory:   loginHistory,
 	}
 }
 
 // Register creates a new user account
 func (s *UserService) Register(ctx context.Context, user *domain.User) error {
+	user.Email = domain.NormalizeEmail(user.Email)
 	existingUser, err := s.userRepo.GetByEmail(ctx, user.Email)
 	if err == nil && existingUser != nil {
-		return errors.New("user already exists")
+		return domain.ErrUserExists
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
@@ -45,25 +54,62 @@ func (s *UserService) Register(ctx context.Context, user *domain.User) error {
 
 	user.ID = uuid.New()
 	user.Password = string(hashedPassword)
+	user.Role = domain.RoleUser
+	user.Plan = domain.PlanFree
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
-	return s.userRepo.Create(ctx, user)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	if s.events != nil {
+		event, err := domain.NewUserEvent(domain.EventUserRegistered, user)
+		if err == nil {
+			err = s.events.Record(ctx, event)
+		}
+		if err != nil {
+			log.Printf("Failed to record %s for user %s: %v", domain.EventUserRegistered, user.ID, err)
+		}
+	}
+	return nil
 }
 
-// Login authenticates a user and returns access and refresh tokens
-func (s *UserService) Login(ctx context.Context, email, password, ipAddress, userAgent string) (*domain.LoginResponse, error) {
-	user, err := s.userRepo.GetByEmail(ctx, email)
+// Login authenticates a user and returns access and refresh tokens. After
+// repeated failed logins from the address, a verified CAPTCHA token is required,
+// and logins from new devices may have to confirm a code emailed to the user.
+// Successful logins are recorded in the user's login history.
+func (s *UserService) Login(ctx context.Context, req *domain.LoginRequest, ipAddress, userAgent string) (*domain.LoginResponse, error) {
+	if err := s.loginGuard.Check(ctx, ipAddress, req.CaptchaToken); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, domain.NormalizeEmail(req.Email))
 	if err != nil {
+		s.loginGuard.RecordFailure(ctx, ipAddress)
 		return nil, errors.New("invalid credentials")
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
+		s.loginGuard.RecordFailure(ctx, ipAddress)
 		return nil, errors.New("invalid credentials")
 	}
 
-	session, err := s.sessionService.CreateSession(ctx, user.ID.String(), user.Email, ipAddress, userAgent, 24*time.Hour)
+	device, err := s.devices.Authorize(ctx, user, req.DeviceID, req.DeviceCode, ipAddress, userAgent)
+	if err != nil {
+		if errors.Is(err, ErrInvalidDeviceCode) {
+			s.loginGuard.RecordFailure(ctx, ipAddress)
+		}
+		return nil, err
+	}
+
+	revoked, err := s.sessionService.EnforceSessionLimit(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionService.CreateSession(ctx, user.ID.String(), user.Email, ipAddress, userAgent, device.ID.String(), req.RememberMe)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
@@ -73,31 +119,35 @@ func (s *UserService) Login(ctx context.Context, email, password, ipAddress, use
 		return nil, err
 	}
 
-	refreshToken, err := s.generateRefreshToken(user, session.ID)
+	refreshToken, err := s.generateRefreshToken(user, session.ID, session.TTL)
 	if err != nil {
 		return nil, err
 	}
 
-	user.Password = ""
+	s.loginHistory.Record(LoginAttempt{
+		User:      *user,
+		DeviceID:  &device.ID,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Time:      time.Now(),
+	})
 
 	response := &domain.LoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		User:         *user,
+		User:         domain.NewUserResponse(user),
 		ExpiresIn:    3600, // 1 hour
 	}
+	for _, session := range revoked {
+		response.RevokedSessions = append(response.RevokedSessions, session.Info())
+	}
 
 	return response, nil
 }
 
 // RefreshToken generates new access and refresh tokens
 func (s *UserService) RefreshToken(ctx context.Context, refreshToken string) (*domain.RefreshTokenResponse, error) {
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(s.jwtSecret), nil
-	})
+	token, err := s.signingKeys.Parse(refreshToken)
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid refresh token")
@@ -138,14 +188,18 @@ func (s *UserService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, err
 	}
 
-	newRefreshToken, err := s.generateRefreshToken(user, sessionID)
+	session, err := s.sessionService.RefreshSession(ctx, sessionID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to refresh session: %w", err)
+	}
+	refreshTTL := s.sessionService.Duration(false)
+	if session != nil {
+		refreshTTL = session.TTL
 	}
 
-	err = s.sessionService.RefreshSession(ctx, sessionID, 24*time.Hour)
+	newRefreshToken, err := s.generateRefreshToken(user, sessionID, refreshTTL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to refresh session: %w", err)
+		return nil, err
 	}
 
 	return &domain.RefreshTokenResponse{
@@ -180,7 +234,7 @@ func (s *UserService) BlacklistAllUserSessions(ctx context.Context, userID uuid.
 	for _, session := range sessions {
 		userBlacklistKey := fmt.Sprintf("user_blacklist:%s:%s", userID.String(), session.ID)
 
-		if err := s.sessionService.cacheService.Set(ctx, userBlacklistKey, true, 24*time.Hour); err != nil {
+		if err := s.sessionService.Revoke(ctx, userBlacklistKey, 24*time.Hour); err != nil {
 			return fmt.Errorf("failed to blacklist session %s: %w", session.ID, err)
 		}
 	}
@@ -198,7 +252,7 @@ func (s *UserService) IsTokenBlacklisted(ctx context.Context, token string) (boo
 	tokenHash := s.hashToken(token)
 	blacklistKey := fmt.Sprintf("blacklist:%s", tokenHash)
 
-	exists, err := s.sessionService.cacheService.Exists(ctx, blacklistKey)
+	exists, err := s.sessionService.IsRevoked(ctx, blacklistKey)
 	if err != nil {
 		return false, fmt.Errorf("failed to check token blacklist: %w", err)
 	}
@@ -208,7 +262,7 @@ func (s *UserService) IsTokenBlacklisted(ctx context.Context, token string) (boo
 // IsUserSessionBlacklisted checks if a user's session has been blacklisted by logout all
 func (s *UserService) IsUserSessionBlacklisted(ctx context.Context, userID uuid.UUID, sessionID string) (bool, error) {
 	userBlacklistKey := fmt.Sprintf("user_blacklist:%s:%s", userID.String(), sessionID)
-	exists, err := s.sessionService.cacheService.Exists(ctx, userBlacklistKey)
+	exists, err := s.sessionService.IsRevoked(ctx, userBlacklistKey)
 	if err != nil {
 		return false, fmt.Errorf("failed to check user session blacklist: %w", err)
 	}
@@ -220,7 +274,7 @@ func (s *UserService) BlacklistToken(ctx context.Context, token string) error {
 	tokenHash := s.hashToken(token)
 	blacklistKey := fmt.Sprintf("blacklist:%s", tokenHash)
 
-	return s.sessionService.cacheService.Set(ctx, blacklistKey, true, 24*time.Hour)
+	return s.sessionService.Revoke(ctx, blacklistKey, 24*time.Hour)
 }
 
 // hashToken creates a proper cryptographic hash of the token for blacklisting
@@ -231,18 +285,29 @@ func (s *UserService) hashToken(token string) string {
 
 // GetUserSessions returns all active sessions for a user
 func (s *UserService) GetUserSessions(ctx context.Context, userID uuid.UUID) (*domain.UserSessionsResponse, error) {
-
-	count, err := s.sessionService.GetActiveSessionsCount(ctx, userID.String())
+	sessions, err := s.sessionService.GetUserSessions(ctx, userID.String())
 	if err != nil {
 		return nil, err
 	}
 
+	sessionInfos := make([]domain.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		sessionInfos = append(sessionInfos, session.Info())
+	}
+
 	return &domain.UserSessionsResponse{
-		ActiveSessions: []domain.SessionInfo{}, // Would need to implement this
-		TotalSessions:  count,
+		ActiveSessions: sessionInfos,
+		TotalSessions:  int64(len(sessionInfos)),
 	}, nil
 }
 
+// RecordSessionActivity updates the last activity of a session, logging failures
+func (s *UserService) RecordSessionActivity(ctx context.Context, sessionID string) {
+	if err := s.sessionService.RecordActivity(ctx, sessionID); err != nil {
+		log.Printf("Failed to record activity of session %s: %v", sessionID, err)
+	}
+}
+
 // GetByID retrieves a user by ID
 func (s *UserService) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	return s.userRepo.GetByID(ctx, id)
@@ -250,26 +315,22 @@ func (s *UserService) GetByID(ctx context.Context, id uuid.UUID) (*domain.User,
 
 // generateAccessToken generates a short-lived access token
 func (s *UserService) generateAccessToken(user *domain.User, sessionID string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return s.signingKeys.Sign(jwt.MapClaims{
 		"user_id":    user.ID.String(),
 		"email":      user.Email,
 		"session_id": sessionID,
 		"exp":        time.Now().Add(time.Hour).Unix(), // 1 hour
 		"type":       "access",
 	})
-
-	return token.SignedString([]byte(s.jwtSecret))
 }
 
-// generateRefreshToken generates a long-lived refresh token
-func (s *UserService) generateRefreshToken(user *domain.User, sessionID string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// generateRefreshToken generates a refresh token lasting as long as its session
+func (s *UserService) generateRefreshToken(user *domain.User, sessionID string, ttl time.Duration) (string, error) {
+	return s.signingKeys.Sign(jwt.MapClaims{
 		"user_id":    user.ID.String(),
 		"email":      user.Email,
 		"session_id": sessionID,
-		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(), // 7 days
+		"exp":        time.Now().Add(ttl).Unix(),
 		"type":       "refresh",
 	})
-
-	return token.SignedString([]byte(s.jwtSecret))
 }
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+	"products/internal/netguard"
+	"products/internal/repository"
+)
+
+// Webhook delivery settings
+const (
+	maxWebhookAttempts   = 6
+	webhookRetryBase     = 30 * time.Second
+	webhookTimeout       = 10 * time.Second
+	maxWebhookDeliveries = 50
+)
+
+// ErrWebhookNotFound is returned for subscriptions that do not exist or belong to another user
+var ErrWebhookNotFound = domain.NotFoundError(domain.CodeNotFound, "webhook subscription not found")
+
+// WebhookService manages webhook subscriptions and delivers the product events they select
+type WebhookService struct {
+	webhookRepo  *repository.WebhookRepository
+	deliveryRepo *repository.WebhookDeliveryRepository
+	planService  *PlanService
+	client       *http.Client
+	validateURL  func(ctx context.Context, rawURL string) error
+	wake         chan struct{}
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo *repository.WebhookRepository, deliveryRepo *repository.WebhookDeliveryRepository, planService *PlanService) *WebhookService {
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		planService:  planService,
+		client:       netguard.NewClient(webhookTimeout),
+		validateURL:  netguard.ValidateURL,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// List returns the user's webhook subscriptions
+func (s *WebhookService) List(ctx context.Context, userID uuid.UUID) ([]domain.WebhookSubscription, error) {
+	subscriptions, err := s.webhookRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptions == nil {
+		subscriptions = []domain.WebhookSubscription{}
+	}
+	return subscriptions, nil
+}
+
+// Create registers a new subscription and returns it with its signing secret
+func (s *WebhookService) Create(ctx context.Context, userID uuid.UUID, req domain.WebhookSubscriptionRequest) (*domain.WebhookSubscriptionCreatedResponse, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planService.ForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.webhookRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.WebhookLimit > 0 && count >= plan.WebhookLimit {
+		return nil, domain.ConflictError(domain.CodeConflict, fmt.Sprintf("the %s plan allows at most %d webhook subscriptions", plan.Name, plan.WebhookLimit))
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := time.Now()
+	subscription := &domain.WebhookSubscription{
+		ID:        uuid.New(),
+		UserID:    userID,
+		URL:       req.URL,
+		Secret:    hex.EncodeToString(secretBytes),
+		Events:    req.Events,
+		Filter:    req.Filter,
+		Active:    req.Active == nil || *req.Active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.webhookRepo.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return &domain.WebhookSubscriptionCreatedResponse{
+		WebhookSubscription: subscription,
+		Secret:              subscription.Secret,
+	}, nil
+}
+
+// Update replaces the URL, events and filter of one of the user's subscriptions
+func (s *WebhookService) Update(ctx context.Context, userID, id uuid.UUID, req domain.WebhookSubscriptionRequest) (*domain.WebhookSubscription, error) {
+	subscription, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	subscription.URL = req.URL
+	subscription.Events = req.Events
+	subscription.Filter = req.Filter
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+	subscription.UpdatedAt = time.Now()
+	if err := s.webhookRepo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// validate checks the events and filter of a subscription request, and that its URL
+// is an https URL of a public host, so that deliveries cannot reach internal services
+func (s *WebhookService) validate(ctx context.Context, req domain.WebhookSubscriptionRequest) error {
+	if err := s.validateURL(ctx, req.URL); err != nil {
+		return domain.NewError(domain.CodeValidationFailed, "url "+netguard.ErrInvalidURL.Error())
+	}
+	if err := domain.ValidateWebhookSubscription(req.Events, req.Filter); err != nil {
+		return domain.NewError(domain.CodeValidationFailed, err.Error())
+	}
+	return nil
+}
+
+// Delete removes one of the user's subscriptions and its delivery history
+func (s *WebhookService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.webhookRepo.DeleteWithDeliveries(ctx, id)
+}
+
+// Deliveries returns the most recent deliveries of one of the user's subscriptions
+func (s *WebhookService) Deliveries(ctx context.Context, userID, id uuid.UUID) ([]domain.WebhookDelivery, error) {
+	if _, err := s.get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.deliveryRepo.GetBySubscriptionID(ctx, id, maxWebhookDeliveries)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []domain.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+// Publish queues an event for every subscription of the product owner whose
+// events and filter accept it
+func (s *WebhookService) Publish(ctx context.Context, event domain.WebhookEvent) error {
+	subscriptions, err := s.webhookRepo.GetActiveByUserID(ctx, event.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+
+	var accepted []uuid.UUID
+	for i := range subscriptions {
+		if subscriptions[i].Accepts(event) {
+			accepted = append(accepted, subscriptions[i].ID)
+		}
+	}
+	if len(accepted) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(accepted))
+	for _, subscriptionID := range accepted {
+		deliveries = append(deliveries, newWebhookDelivery(subscriptionID, event, payload))
+	}
+
+	if err := s.deliveryRepo.CreateBatch(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// StartWorker delivers queued events when woken by Publish or on every interval
+func (s *WebhookService) StartWorker(ctx context.Context, interval time.Duration) {
+	if err := s.deliveryRepo.ResetRunning(ctx); err != nil {
+		log.Printf("Webhook worker: failed to requeue interrupted deliveries: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// processDue attempts deliveries until none are due or ctx is cancelled. A claimed
+// delivery is attempted and recorded even if ctx is cancelled meanwhile.
+func (s *WebhookService) processDue(ctx context.Context) {
+	jobCtx := context.WithoutCancel(ctx)
+	for ctx.Err() == nil {
+		delivery, err := s.deliveryRepo.ClaimDue(jobCtx)
+		if err != nil {
+			log.Printf("Webhook worker: %v", err)
+			return
+		}
+		if delivery == nil {
+			return
+		}
+
+		s.deliver(jobCtx, delivery)
+	}
+}
+
+// deliver sends one delivery and records the outcome, scheduling a retry with
+// exponential backoff until the attempts are exhausted
+func (s *WebhookService) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	subscription, err := s.webhookRepo.GetByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		if err := s.deliveryRepo.Fail(ctx, delivery.ID, 0, "subscription no longer exists"); err != nil {
+			log.Printf("Webhook worker: failed to record delivery %s: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	status, err := s.send(ctx, subscription, delivery)
+	if err == nil {
+		err = s.deliveryRepo.Complete(ctx, delivery.ID, status)
+	} else if delivery.Attempts >= maxWebhookAttempts {
+		err = s.deliveryRepo.Fail(ctx, delivery.ID, status, webhookErrorMessage(status, err))
+	} else {
+		backoff := webhookRetryBase << (delivery.Attempts - 1)
+		err = s.deliveryRepo.Retry(ctx, delivery.ID, status, webhookErrorMessage(status, err), time.Now().Add(backoff))
+	}
+	if err != nil {
+		log.Printf("Webhook worker: failed to record delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send posts the payload to the subscription URL, signed with its secret
+func (s *WebhookService) send(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookErrorMessage describes a failed delivery to the subscriber without the
+// details of network errors, which would reveal what the server can reach
+func webhookErrorMessage(status int, err error) string {
+	switch {
+	case status != 0:
+		return fmt.Sprintf("endpoint responded with status %d", status)
+	case errors.Is(err, netguard.ErrBlockedAddress):
+		return "endpoint address is not publicly routable"
+	default:
+		log.Printf("Webhook worker: delivery failed: %v", err)
+		return "endpoint could not be reached"
+	}
+}
+
+// get loads a subscription owned by the user
+func (s *WebhookService) get(ctx context.Context, userID, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil || subscription.UserID != userID {
+		return nil, ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+// SignWebhookPayload computes the hex HMAC-SHA256 of "<timestamp>.<payload>" that
+// subscribers compare against the X-Webhook-Signature header
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookDelivery builds a pending delivery of an event to a subscription
+func newWebhookDelivery(subscriptionID uuid.UUID, event domain.WebhookEvent, payload []byte) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  event.OccurredAt,
+		CreatedAt:      event.OccurredAt,
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"products/internal/domain"
+)
+
+func TestWebhookService_SendSignsPayload(t *testing.T) {
+	payload := []byte(`{"type":"product.price_changed"}`)
+	subscription := &domain.WebhookSubscription{Secret: "topsecret"}
+	delivery := &domain.WebhookDelivery{ID: uuid.New(), EventType: domain.WebhookProductPriceChanged, Payload: payload}
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	subscription.URL = server.URL
+
+	service := NewWebhookService(nil, nil, nil)
+	service.client = server.Client()
+	status, err := service.send(context.Background(), subscription, delivery)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", status)
+	}
+
+	if got := received.Header.Get("X-Webhook-Event"); got != domain.WebhookProductPriceChanged {
+		t.Errorf("Expected event header, got %q", got)
+	}
+	want := "sha256=" + SignWebhookPayload("topsecret", received.Header.Get("X-Webhook-Timestamp"), body)
+	if got := received.Header.Get("X-Webhook-Signature"); got != want {
+		t.Errorf("Expected signature %q, got %q", want, got)
+	}
+}
+
+func TestWebhookService_SendRejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	service := NewWebhookService(nil, nil, nil)
+	service.client = server.Client()
+	subscription := &domain.WebhookSubscription{URL: server.URL, Secret: "topsecret"}
+	status, err := service.send(context.Background(), subscription, &domain.WebhookDelivery{ID: uuid.New(), Payload: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("Expected an error for a 502 response")
+	}
+	if status != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", status)
+	}
+}
+
+func TestWebhookService_RefusesInternalEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to reach the loopback endpoint")
+	}))
+	defer server.Close()
+
+	service := NewWebhookService(nil, nil, nil)
+	subscription := &domain.WebhookSubscription{URL: server.URL, Secret: "topsecret"}
+	status, err := service.send(context.Background(), subscription, &domain.WebhookDelivery{ID: uuid.New(), Payload: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("Expected delivery to a loopback address to fail")
+	}
+	if message := webhookErrorMessage(status, err); message != "endpoint address is not publicly routable" {
+		t.Errorf("Expected a generic error message, got %q", message)
+	}
+
+	for _, url := range []string{server.URL, "http://93.184.216.34/hook", "https://169.254.169.254/latest/meta-data/"} {
+		_, err := service.Create(context.Background(), uuid.New(), domain.WebhookSubscriptionRequest{URL: url, Events: []string{domain.WebhookProductPriceChanged}})
+		if domain.ErrorCode(err) != domain.CodeValidationFailed {
+			t.Errorf("Expected %s to be rejected, got %v", url, err)
+		}
+	}
+}
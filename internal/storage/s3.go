@@ -0,0 +1,337 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config holds S3-compatible storage configuration
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UsePathStyle    bool
+	// HTTPClient sends the requests; nil uses a client with a 60 second timeout
+	HTTPClient *http.Client
+}
+
+// NewS3Config creates a new S3 configuration from environment variables
+func NewS3Config() *S3Config {
+	region := getEnv("S3_REGION", "us-east-1")
+	return &S3Config{
+		Endpoint:        getEnv("S3_ENDPOINT", fmt.Sprintf("https://s3.%s.amazonaws.com", region)),
+		Region:          region,
+		Bucket:          getEnv("S3_BUCKET", ""),
+		AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		UsePathStyle:    getEnv("S3_USE_PATH_STYLE", "false") == "true",
+	}
+}
+
+// S3Store implements ObjectStore against any S3-compatible API
+type S3Store struct {
+	config *S3Config
+	client *http.Client
+}
+
+// NewS3Store creates a new S3 store
+func NewS3Store(config *S3Config) (*S3Store, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("S3 credentials are required")
+	}
+	if _, err := url.Parse(config.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+
+	return &S3Store{
+		config: config,
+		client: client,
+	}, nil
+}
+
+// Put uploads an object
+func (s *S3Store) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, nil, body, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError(resp)
+	}
+
+	return nil
+}
+
+// Get downloads an object; the caller must close the returned reader
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrObjectNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, s.responseError(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// Delete removes an object; deleting a missing object succeeds
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s.responseError(resp)
+	}
+
+	return nil
+}
+
+// List returns all objects whose key starts with prefix
+func (s *S3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	continuationToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := s.responseError(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode list response: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          content.Key,
+				Size:         content.Size,
+				LastModified: content.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+
+	return objects, nil
+}
+
+// do builds, signs and sends a request for the given object key
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, body []byte, contentType string) (*http.Response, error) {
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+
+	host := endpoint.Host
+	path := "/" + uriEncode(key, false)
+	if s.config.UsePathStyle {
+		path = "/" + uriEncode(s.config.Bucket, true) + path
+	} else {
+		host = s.config.Bucket + "." + host
+	}
+
+	rawQuery := canonicalQuery(query)
+	target := fmt.Sprintf("%s://%s%s", endpoint.Scheme, host, path)
+	if rawQuery != "" {
+		target += "?" + rawQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build S3 request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(req, host, path, rawQuery, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 request failed: %w", err)
+	}
+
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers to the request
+func (s *S3Store) sign(req *http.Request, host, path, rawQuery string, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		rawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", shortDate, s.config.Region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), shortDate)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// responseError converts a failed S3 response into an error
+func (s *S3Store) responseError(resp *http.Response) error {
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := xml.Unmarshal(body, &s3Err); err == nil && s3Err.Code != "" {
+		return fmt.Errorf("S3 error %d %s: %s", resp.StatusCode, s3Err.Code, s3Err.Message)
+	}
+	return fmt.Errorf("S3 error: unexpected status %d", resp.StatusCode)
+}
+
+// canonicalQuery encodes query parameters in the order required by SigV4
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except unreserved characters
+func uriEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9'),
+			b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
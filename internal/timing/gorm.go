@@ -0,0 +1,50 @@
+package timing
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// gormStartKey is the statement setting holding the start time of a GORM operation
+const gormStartKey = "timing:start"
+
+// RegisterGORM records the duration of every GORM operation on db, including
+// those inside transactions and the scanning of their rows, as the Database metric
+func RegisterGORM(db *gorm.DB) error {
+	before := func(db *gorm.DB) {
+		db.InstanceSet(gormStartKey, time.Now())
+	}
+	after := func(db *gorm.DB) {
+		recorder := FromContext(db.Statement.Context)
+		if recorder == nil {
+			return
+		}
+		if start, ok := db.InstanceGet(gormStartKey); ok {
+			recorder.Add(Database, time.Since(start.(time.Time)))
+		}
+	}
+
+	callbacks := db.Callback()
+	registrations := []struct {
+		operation     string
+		before, after func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("*").Register, callbacks.Create().After("*").Register},
+		{"query", callbacks.Query().Before("*").Register, callbacks.Query().After("*").Register},
+		{"update", callbacks.Update().Before("*").Register, callbacks.Update().After("*").Register},
+		{"delete", callbacks.Delete().Before("*").Register, callbacks.Delete().After("*").Register},
+		{"row", callbacks.Row().Before("*").Register, callbacks.Row().After("*").Register},
+		{"raw", callbacks.Raw().Before("*").Register, callbacks.Raw().After("*").Register},
+	}
+	for _, r := range registrations {
+		if err := r.before("timing:before_"+r.operation, before); err != nil {
+			return fmt.Errorf("failed to register %s timing: %w", r.operation, err)
+		}
+		if err := r.after("timing:after_"+r.operation, after); err != nil {
+			return fmt.Errorf("failed to register %s timing: %w", r.operation, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package timing
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHook records the duration of Redis commands and pipelines as the Cache metric
+type RedisHook struct{}
+
+// DialHook implements redis.Hook
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		defer Start(ctx, Cache)()
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		defer Start(ctx, Cache)()
+		return next(ctx, cmds)
+	}
+}
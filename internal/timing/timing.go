@@ -0,0 +1,126 @@
+// Package timing gathers the time each request spends in database and cache calls
+// for Server-Timing headers
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics reported in Server-Timing headers
+const (
+	Database = "db"
+	Cache    = "cache"
+	App      = "app"
+	Total    = "total"
+	Budget   = "budget"
+)
+
+// units name the calls of each metric, singular and plural
+var units = map[string][2]string{
+	Database: {"query", "queries"},
+	Cache:    {"command", "commands"},
+}
+
+// Recorder accumulates the time one request spends in calls to each dependency.
+// It is safe for concurrent use, so calls made on other goroutines with the
+// request's context are recorded too.
+type Recorder struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+// metric is the accumulated duration and number of calls of one metric
+type metric struct {
+	name     string
+	duration time.Duration
+	calls    int
+}
+
+type contextKey struct{}
+
+// NewContext returns a context that records call durations in a new recorder
+func NewContext(ctx context.Context) (context.Context, *Recorder) {
+	recorder := &Recorder{}
+	return context.WithValue(ctx, contextKey{}, recorder), recorder
+}
+
+// FromContext returns the recorder of ctx, or nil
+func FromContext(ctx context.Context) *Recorder {
+	recorder, _ := ctx.Value(contextKey{}).(*Recorder)
+	return recorder
+}
+
+// Start starts timing a call of the metric, recording it in the recorder of ctx
+// when the returned function is called. Without a recorder it does nothing.
+func Start(ctx context.Context, name string) func() {
+	recorder := FromContext(ctx)
+	if recorder == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		recorder.Add(name, time.Since(start))
+	}
+}
+
+// Add records a call of the metric that took d
+func (r *Recorder) Add(name string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.metrics {
+		if r.metrics[i].name == name {
+			r.metrics[i].duration += d
+			r.metrics[i].calls++
+			return
+		}
+	}
+	r.metrics = append(r.metrics, metric{name: name, duration: d, calls: 1})
+}
+
+// Header formats the Server-Timing header of a request that has taken total so
+// far: the recorded metrics, the remaining app time, the total and, when the
+// request has a deadline, its latency budget
+func (r *Recorder) Header(total, budget time.Duration) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]string, 0, len(r.metrics)+3)
+	app := total
+	for _, m := range r.metrics {
+		app -= m.duration
+		unit, ok := units[m.name]
+		if !ok {
+			unit = [2]string{"call", "calls"}
+		}
+		desc := fmt.Sprintf("%d %s", m.calls, unit[1])
+		if m.calls == 1 {
+			desc = "1 " + unit[0]
+		}
+		entries = append(entries, entry(m.name, desc, m.duration))
+	}
+
+	// Concurrent calls can add up to more than the request took
+	if app < 0 {
+		app = 0
+	}
+	entries = append(entries, entry(App, "", app), entry(Total, "", total))
+	if budget > 0 {
+		entries = append(entries, entry(Budget, "", budget))
+	}
+	return strings.Join(entries, ", ")
+}
+
+// entry formats one Server-Timing metric with its duration in milliseconds
+func entry(name, desc string, d time.Duration) string {
+	ms := float64(d.Microseconds()) / 1000
+	if desc == "" {
+		return fmt.Sprintf("%s;dur=%.1f", name, ms)
+	}
+	return fmt.Sprintf("%s;desc=%q;dur=%.1f", name, desc, ms)
+}
@@ -0,0 +1,41 @@
+package timing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder_Header(t *testing.T) {
+	_, recorder := NewContext(context.Background())
+	recorder.Add(Database, 12*time.Millisecond)
+	recorder.Add(Cache, 500*time.Microsecond)
+	recorder.Add(Database, 3*time.Millisecond)
+
+	want := `db;desc="2 queries";dur=15.0, cache;desc="1 command";dur=0.5, app;dur=24.5, total;dur=40.0, budget;dur=30000.0`
+	if header := recorder.Header(40*time.Millisecond, 30*time.Second); header != want {
+		t.Errorf("Expected %s, got %s", want, header)
+	}
+}
+
+func TestRecorder_HeaderConcurrentCalls(t *testing.T) {
+	_, recorder := NewContext(context.Background())
+	recorder.Add(Database, 30*time.Millisecond)
+	recorder.Add(Database, 30*time.Millisecond)
+
+	if header := recorder.Header(40*time.Millisecond, 0); !strings.Contains(header, "app;dur=0.0") {
+		t.Errorf("Expected app time not to go below zero, got %s", header)
+	}
+}
+
+func TestStart(t *testing.T) {
+	// Calls made outside a request are not recorded
+	Start(context.Background(), Database)()
+
+	ctx, recorder := NewContext(context.Background())
+	Start(ctx, Cache)()
+	if header := recorder.Header(time.Millisecond, 0); !strings.HasPrefix(header, `cache;desc="1 command"`) {
+		t.Errorf("Expected the call to be recorded, got %s", header)
+	}
+}
@@ -0,0 +1,152 @@
+// Package client is a Go client of the products API for services that consume
+// it. It logs in, keeps the access token fresh, pages through lists and returns
+// API errors as *Error.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenRefreshMargin is how long before it expires the access token is refreshed;
+// the refresh endpoint itself requires an access token that is still valid
+const tokenRefreshMargin = 30 * time.Second
+
+// Client calls the products API as one user. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+}
+
+// New creates a client of the API at baseURL, e.g. "https://api.example.com",
+// sending requests with httpClient, or http.DefaultClient when nil
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// tokens is the response of the login and refresh endpoints
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	User         *User  `json:"user,omitempty"`
+}
+
+// Login authenticates the client as a user and returns the user
+func (c *Client) Login(ctx context.Context, email, password string) (*User, error) {
+	var response tokens
+	body := map[string]string{"email": email, "password": password}
+	if err := c.send(ctx, http.MethodPost, "/api/v1/auth/login", "", body, &response); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.setTokens(response)
+	c.mu.Unlock()
+	return response.User, nil
+}
+
+// SetTokens authenticates the client with tokens obtained elsewhere. An empty
+// refreshToken or a zero expiresIn disables refreshing.
+func (c *Client) SetTokens(accessToken, refreshToken string, expiresIn time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setTokens(tokens{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: int64(expiresIn / time.Second)})
+}
+
+func (c *Client) setTokens(response tokens) {
+	c.accessToken = response.AccessToken
+	c.refreshToken = response.RefreshToken
+	c.expiresAt = time.Time{}
+	if response.ExpiresIn > 0 {
+		c.expiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+}
+
+// token returns the access token, refreshing it first when it is about to expire
+func (c *Client) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.refreshToken == "" || c.expiresAt.IsZero() || time.Until(c.expiresAt) > tokenRefreshMargin {
+		return c.accessToken, nil
+	}
+
+	var response tokens
+	body := map[string]string{"refresh_token": c.refreshToken}
+	if err := c.send(ctx, http.MethodPost, "/api/v1/auth/refresh", c.accessToken, body, &response); err != nil {
+		return "", fmt.Errorf("failed to refresh access token: %w", err)
+	}
+	c.setTokens(response)
+	return c.accessToken, nil
+}
+
+// do sends an authenticated request with a JSON body, decoding a JSON response
+// into out when it is not nil
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, method, path, token, body, out)
+}
+
+// send sends a request with a JSON body and the given access token, if any
+func (c *Client) send(ctx context.Context, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return newError(response)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
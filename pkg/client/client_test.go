@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestClient_RefreshesExpiringToken(t *testing.T) {
+	var refreshed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/refresh":
+			if r.Header.Get("Authorization") != "Bearer old" {
+				t.Errorf("Expected the refresh authenticated by the old token, got %q", r.Header.Get("Authorization"))
+			}
+			refreshed = true
+			json.NewEncoder(w).Encode(tokens{AccessToken: "new", RefreshToken: "refresh-2", ExpiresIn: 3600})
+		case "/api/v1/products/" + uuid.Nil.String():
+			if r.Header.Get("Authorization") != "Bearer new" {
+				t.Errorf("Expected the refreshed token, got %q", r.Header.Get("Authorization"))
+			}
+			json.NewEncoder(w).Encode(Product{Name: "Widget"})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, nil)
+	c.SetTokens("old", "refresh-1", 10*time.Second)
+
+	product, err := c.GetProduct(context.Background(), uuid.Nil)
+	if err != nil {
+		t.Fatalf("GetProduct failed: %v", err)
+	}
+	if !refreshed || product.Name != "Widget" {
+		t.Errorf("Expected the token refreshed before the request, got refreshed=%v product=%+v", refreshed, product)
+	}
+}
+
+func TestClient_Products(t *testing.T) {
+	pages := map[string]ProductPage{
+		"":   {Products: []Product{{Name: "a"}, {Name: "b"}}, NextCursor: ptr("c1"), HasNext: true},
+		"c1": {Products: []Product{{Name: "c"}}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page_size") != "2" {
+			t.Errorf("Expected page_size 2, got %q", r.URL.Query().Get("page_size"))
+		}
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	}))
+	defer server.Close()
+
+	var names []string
+	for product, err := range New(server.URL, nil).Products(context.Background(), ListOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatalf("Products failed: %v", err)
+		}
+		names = append(names, product.Name)
+	}
+	if len(names) != 3 || names[0] != "a" || names[2] != "c" {
+		t.Errorf("Expected products a, b and c across pages, got %v", names)
+	}
+}
+
+func TestClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"title":"Not Found","status":404,"code":"NOT_FOUND","detail":"product not found","request_id":"req-1"}`))
+	}))
+	defer server.Close()
+
+	err := New(server.URL, nil).DeleteProduct(context.Background(), uuid.New())
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *Error, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Detail != "product not found" || apiErr.RequestID != "req-1" {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+	if !IsNotFound(err) || !HasCode(err, CodeNotFound) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
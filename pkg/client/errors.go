@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody bounds how much of an error response is read
+const maxErrorBody = 64 << 10
+
+// Error codes returned by the API that callers commonly handle
+const (
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeRateLimited      = "RATE_LIMITED"
+	CodeQuotaExceeded    = "QUOTA_EXCEEDED"
+)
+
+// FieldError is a validation error of one request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error response of the API, decoded from its problem details
+type Error struct {
+	StatusCode int          `json:"status"`
+	Code       string       `json:"code"`
+	Title      string       `json:"title"`
+	Detail     string       `json:"detail,omitempty"`
+	Errors     []FieldError `json:"errors,omitempty"`
+	RequestID  string       `json:"request_id,omitempty"`
+}
+
+func (e *Error) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("products api: %d %s", e.StatusCode, message)
+	}
+	return fmt.Sprintf("products api: %d %s: %s", e.StatusCode, e.Code, message)
+}
+
+// newError reads an error response; responses without problem details keep
+// their status and status text
+func newError(response *http.Response) *Error {
+	apiErr := &Error{}
+	data, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+	if err := json.Unmarshal(data, apiErr); err != nil {
+		apiErr = &Error{}
+	}
+	apiErr.StatusCode = response.StatusCode
+	if apiErr.Title == "" {
+		apiErr.Title = http.StatusText(response.StatusCode)
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = response.Header.Get("X-Request-ID")
+	}
+	return apiErr
+}
+
+// HasCode reports whether err is an API error with the given code
+func HasCode(err error, code string) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// IsNotFound reports whether err is an API error for a missing resource
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxPageSize is the largest page of products the API returns
+const MaxPageSize = 100
+
+// User is a user of the API
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	AvatarURL *string   `json:"avatar_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Product is a product of the authenticated user
+type Product struct {
+	ID                uuid.UUID              `json:"id"`
+	Name              string                 `json:"name"`
+	Description       string                 `json:"description"`
+	Price             float64                `json:"price"`
+	Stock             int                    `json:"stock"`
+	ReservedStock     int                    `json:"reserved_stock"`
+	Code              *string                `json:"code,omitempty"`
+	LowStockThreshold *int                   `json:"low_stock_threshold,omitempty"`
+	ReorderQuantity   *int                   `json:"reorder_quantity,omitempty"`
+	Attributes        map[string]interface{} `json:"attributes"`
+	Public            bool                   `json:"public"`
+	Status            string                 `json:"status"`
+	ArchivedAt        *time.Time             `json:"archived_at,omitempty"`
+	UserID            uuid.UUID              `json:"user_id"`
+	Version           int64                  `json:"version"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+// CreateProductRequest is the body of a product creation
+type CreateProductRequest struct {
+	Name              string                 `json:"name"`
+	Description       string                 `json:"description,omitempty"`
+	Price             float64                `json:"price"`
+	Stock             int                    `json:"stock"`
+	Attributes        map[string]interface{} `json:"attributes,omitempty"`
+	Public            bool                   `json:"public,omitempty"`
+	LowStockThreshold *int                   `json:"low_stock_threshold,omitempty"`
+	ReorderQuantity   *int                   `json:"reorder_quantity,omitempty"`
+}
+
+// UpdateProductRequest is the body of a product update; nil fields are kept
+type UpdateProductRequest struct {
+	Name              *string                `json:"name,omitempty"`
+	Description       *string                `json:"description,omitempty"`
+	Price             *float64               `json:"price,omitempty"`
+	Stock             *int                   `json:"stock,omitempty"`
+	Attributes        map[string]interface{} `json:"attributes,omitempty"`
+	LowStockThreshold *int                   `json:"low_stock_threshold,omitempty"`
+	ReorderQuantity   *int                   `json:"reorder_quantity,omitempty"`
+}
+
+// ProductPage is a page of products listed by cursor
+type ProductPage struct {
+	Products   []Product `json:"products"`
+	NextCursor *string   `json:"next_cursor,omitempty"`
+	HasNext    bool      `json:"has_next"`
+}
+
+// ListOptions filters and pages product lists. Zero values are left to the API.
+type ListOptions struct {
+	PageSize int
+	Name     string
+	MinPrice *float64
+	MaxPrice *float64
+}
+
+func (o ListOptions) values() url.Values {
+	values := url.Values{}
+	if o.PageSize > 0 {
+		values.Set("page_size", strconv.Itoa(min(o.PageSize, MaxPageSize)))
+	}
+	if o.Name != "" {
+		values.Set("name", o.Name)
+	}
+	if o.MinPrice != nil {
+		values.Set("min_price", strconv.FormatFloat(*o.MinPrice, 'f', -1, 64))
+	}
+	if o.MaxPrice != nil {
+		values.Set("max_price", strconv.FormatFloat(*o.MaxPrice, 'f', -1, 64))
+	}
+	return values
+}
+
+// GetProduct returns one of the user's products
+func (c *Client) GetProduct(ctx context.Context, id uuid.UUID) (*Product, error) {
+	var product Product
+	if err := c.do(ctx, http.MethodGet, "/api/v1/products/"+id.String(), nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// CreateProduct creates a product and returns it
+func (c *Client) CreateProduct(ctx context.Context, req CreateProductRequest) (*Product, error) {
+	var product Product
+	if err := c.do(ctx, http.MethodPost, "/api/v1/products/", req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// UpdateProduct changes the fields set in req and returns the updated product
+func (c *Client) UpdateProduct(ctx context.Context, id uuid.UUID, req UpdateProductRequest) (*Product, error) {
+	var product Product
+	if err := c.do(ctx, http.MethodPut, "/api/v1/products/"+id.String(), req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// DeleteProduct deletes one of the user's products
+func (c *Client) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/products/"+id.String(), nil, nil)
+}
+
+// ListProducts returns the page of products after cursor, or the first page when
+// cursor is empty
+func (c *Client) ListProducts(ctx context.Context, opts ListOptions, cursor string) (*ProductPage, error) {
+	values := opts.values()
+	if cursor != "" {
+		values.Set("cursor", cursor)
+	}
+
+	var page ProductPage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/products/cursor?"+values.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Products iterates over every product matching opts, fetching a page at a time.
+// Iteration stops after the first error, which is yielded with a zero Product.
+func (c *Client) Products(ctx context.Context, opts ListOptions) iter.Seq2[Product, error] {
+	return func(yield func(Product, error) bool) {
+		cursor := ""
+		for {
+			page, err := c.ListProducts(ctx, opts, cursor)
+			if err != nil {
+				yield(Product{}, err)
+				return
+			}
+			for _, product := range page.Products {
+				if !yield(product, nil) {
+					return
+				}
+			}
+			if !page.HasNext || page.NextCursor == nil || *page.NextCursor == "" {
+				return
+			}
+			cursor = *page.NextCursor
+		}
+	}
+}